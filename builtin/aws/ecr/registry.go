@@ -3,11 +3,11 @@ package ecr
 import (
 	"context"
 	"encoding/base64"
-	"encoding/json"
 	"fmt"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"os"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ecr"
@@ -133,6 +133,11 @@ func (r *Registry) Push(
 	var repo *ecr.Repository
 
 	if repOut == nil || len(repOut.Repositories) == 0 {
+		if r.config.CreateRepository != nil && !*r.config.CreateRepository {
+			return nil, status.Errorf(codes.FailedPrecondition,
+				"ECR repository %q doesn't exist and create_repository is false", repoName)
+		}
+
 		log.Info("no ECR repository detected, creating", "name", repoName)
 
 		step = sg.Add("Creating new repository: %s", repoName)
@@ -157,9 +162,26 @@ func (r *Registry) Push(
 		repo = repOut.Repositories[0]
 	}
 
+	if r.config.LifecyclePolicy != "" {
+		step = sg.Add("Applying lifecycle policy to repository: %s", repoName)
+
+		// Putting the policy replaces any existing one, so this also updates
+		// the policy when it changes between pushes.
+		_, err = svc.PutLifecyclePolicy(&ecr.PutLifecyclePolicyInput{
+			RegistryId:          repo.RegistryId,
+			RepositoryName:      repo.RepositoryName,
+			LifecyclePolicyText: aws.String(r.config.LifecyclePolicy),
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "unable to apply lifecycle policy: %s", err)
+		}
+
+		step.Done()
+	}
+
 	uri := repo.RepositoryUri
 
-	target := &docker.Image{Image: *uri, Tag: r.config.Tag}
+	target := &docker.Image{Image: *uri, Tag: r.config.Tag, Platforms: img.Platforms}
 
 	step = sg.Add("Tagging Docker image: %s => %s", img.Name(), target.Name())
 
@@ -168,43 +190,85 @@ func (r *Registry) Push(
 		return nil, err
 	}
 
-	ref, err := reference.ParseNormalizedNamed(target.Name())
-	if err != nil {
-		return nil, err
+	for _, platform := range img.Platforms {
+		err = cli.ImageTag(ctx, img.PlatformName(platform), target.PlatformName(platform))
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	step.Done()
 
 	uptoken := *gat.AuthorizationData[0].AuthorizationToken
 
+	// The token is the base64 encoded "user:password" to log in with.
 	data, err := base64.StdEncoding.DecodeString(uptoken)
 	if err != nil {
 		return nil, err
 	}
-
-	authInfo := map[string]string{
-		"username": "AWS",
-		"password": string(data[4:]),
+	parts := strings.SplitN(string(data), ":", 2)
+	if len(parts) != 2 {
+		return nil, status.Errorf(codes.Internal, "unexpected ECR authorization token format")
 	}
 
-	authData, err := json.Marshal(authInfo)
+	encodedAuth, err := docker.EncodeRegistryAuth(parts[0], parts[1])
 	if err != nil {
 		return nil, err
 	}
 
-	encodedAuth := base64.StdEncoding.EncodeToString(authData)
+	// An image built for multiple platforms is pushed as the image of each
+	// platform, and then a manifest list of them under the tag itself.
+	if len(target.Platforms) > 0 {
+		for _, platform := range target.Platforms {
+			step = sg.Add("Pushing image for %s...", platform)
+			if err := pushImage(ctx, cli, target.PlatformName(platform), encodedAuth, step); err != nil {
+				return nil, err
+			}
 
-	options := types.ImagePushOptions{
-		RegistryAuth: encodedAuth,
+			step.Done()
+		}
+
+		step = sg.Add("Pushing manifest list...")
+		if _, err := docker.PushManifestList(ctx, target, encodedAuth); err != nil {
+			return nil, err
+		}
+
+		step.Done()
+	} else {
+		step = sg.Add("Pushing image...")
+		if err := pushImage(ctx, cli, target.Name(), encodedAuth, step); err != nil {
+			return nil, err
+		}
+
+		step.Done()
 	}
 
-	step.Done()
+	ui.Output("Docker image pushed: %s", target.Name())
 
-	step = sg.Add("Pushing image...")
+	return target, nil
+}
+
+// pushImage pushes the image with the given name, streaming the push
+// progress to the step.
+func pushImage(
+	ctx context.Context,
+	cli *client.Client,
+	name string,
+	encodedAuth string,
+	step terminal.Step,
+) error {
+	ref, err := reference.ParseNormalizedNamed(name)
+	if err != nil {
+		return err
+	}
+
+	options := types.ImagePushOptions{
+		RegistryAuth: encodedAuth,
+	}
 
 	responseBody, err := cli.ImagePush(ctx, reference.FamiliarString(ref), options)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	defer responseBody.Close()
@@ -221,16 +285,7 @@ func (r *Registry) Push(
 		isTerm = true
 	}
 
-	err = jsonmessage.DisplayJSONMessagesStream(responseBody, stdout, termFd, isTerm, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	step.Done()
-
-	ui.Output("Docker image pushed: %s", target.Name())
-
-	return target, nil
+	return jsonmessage.DisplayJSONMessagesStream(responseBody, stdout, termFd, isTerm, nil)
 }
 
 // Config is the configuration structure for the registry.
@@ -243,6 +298,13 @@ type Config struct {
 
 	// Tag is the tag to apply to the image.
 	Tag string `hcl:"tag,attr"`
+
+	// CreateRepository, if set to false, will fail the push rather than
+	// create the repository when it doesn't exist. This defaults to true.
+	CreateRepository *bool `hcl:"create_repository,optional"`
+
+	// LifecyclePolicy is the JSON lifecycle policy to apply to the repository.
+	LifecyclePolicy string `hcl:"lifecycle_policy,optional"`
 }
 
 func (r *Registry) Documentation() (*docs.Documentation, error) {
@@ -258,7 +320,8 @@ func (r *Registry) Documentation() (*docs.Documentation, error) {
 registry {
     use "aws-ecr" {
       region = "us-east-1"
-      tag = "latest"
+      tag = "build-${build.sequence}"
+      lifecycle_policy = file("ecr-lifecycle-policy.json")
     }
 }
 `)
@@ -279,12 +342,37 @@ registry {
 		"the ECR repository to store the image into",
 		docs.Summary(
 			"This defaults to waypoint- then the application name. The repository will be automatically created if needed",
+			"unless create_repository is false",
 		),
 	)
 
 	doc.SetField(
 		"tag",
 		"the docker tag to assign to the new image",
+		docs.Summary(
+			"use the build variable to tag each build with its sequence number,",
+			"for example: build-${build.sequence}",
+		),
+	)
+
+	doc.SetField(
+		"create_repository",
+		"if set to false, the repository must already exist",
+		docs.Summary(
+			"by default the repository is created if it doesn't exist",
+		),
+		docs.Default("true"),
+	)
+
+	doc.SetField(
+		"lifecycle_policy",
+		"the lifecycle policy to apply to the repository, in JSON",
+		docs.Summary(
+			"the policy replaces any lifecycle policy the repository already has,",
+			"and is applied on every push so that changes to it take effect. Use",
+			"a function like `file()` to read the policy from a file. See the",
+			"ECR documentation for the policy format",
+		),
 	)
 
 	return doc, nil
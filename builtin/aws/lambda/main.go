@@ -0,0 +1,13 @@
+// Package lambda contains components for deploying to AWS Lambda.
+package lambda
+
+import (
+	"github.com/hashicorp/waypoint-plugin-sdk"
+)
+
+//go:generate protoc -I ../../../.. --go_opt=plugins=grpc --go_out=../../../.. waypoint/builtin/aws/lambda/plugin.proto
+
+// Options are the SDK options to use for instantiation.
+var Options = []sdk.Option{
+	sdk.WithComponents(&Platform{}, &Releaser{}),
+}
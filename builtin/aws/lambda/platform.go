@@ -0,0 +1,467 @@
+package lambda
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/dustin/go-humanize"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/waypoint-plugin-sdk/component"
+	"github.com/hashicorp/waypoint-plugin-sdk/docs"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/builtin/aws/utils"
+	"github.com/hashicorp/waypoint/builtin/files"
+)
+
+const (
+	// DefaultMemory is the memory in MB of functions if none is configured.
+	DefaultMemory = 256
+
+	// DefaultTimeout is the timeout in seconds of functions if none is
+	// configured.
+	DefaultTimeout = 60
+)
+
+const rolePolicy = `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Sid": "",
+      "Effect": "Allow",
+      "Principal": {
+        "Service": "lambda.amazonaws.com"
+      },
+      "Action": "sts:AssumeRole"
+    }
+  ]
+}`
+
+// Platform is the Platform implementation for AWS Lambda.
+type Platform struct {
+	config Config
+}
+
+// Config implements Configurable
+func (p *Platform) Config() (interface{}, error) {
+	return &p.config, nil
+}
+
+// DeployFunc implements component.Platform
+func (p *Platform) DeployFunc() interface{} {
+	return p.Deploy
+}
+
+// DestroyFunc implements component.Destroyer
+func (p *Platform) DestroyFunc() interface{} {
+	return p.Destroy
+}
+
+// DefaultReleaserFunc implements component.PlatformReleaser
+func (p *Platform) DefaultReleaserFunc() interface{} {
+	return func() *Releaser { return &Releaser{} }
+}
+
+// Deploy uploads the files of the artifact as the code of the function
+// named after the application, creating the function if it doesn't exist,
+// and publishes a new version of it.
+func (p *Platform) Deploy(
+	ctx context.Context,
+	log hclog.Logger,
+	src *component.Source,
+	artifact *files.Files,
+	ui terminal.UI,
+) (*Deployment, error) {
+	sg := ui.StepGroup()
+	defer sg.Wait()
+
+	step := sg.Add("Packaging %s...", artifact.Path)
+	defer func() { step.Abort() }()
+
+	code, err := zipDir(artifact.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	step.Update("Packaged %s (%s)", artifact.Path, humanize.Bytes(uint64(len(code))))
+	step.Done()
+
+	sess, err := utils.GetSession(&utils.SessionConfig{
+		Region: p.config.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	step = sg.Add("Setting up IAM role...")
+	roleArn, err := p.setupRole(ctx, log, sess, src)
+	if err != nil {
+		return nil, err
+	}
+	step.Update("Using IAM role: %s", roleArn)
+	step.Done()
+
+	name := src.App
+	svc := lambda.New(sess)
+
+	step = sg.Add("Deploying function %s...", name)
+	_, err = svc.GetFunctionWithContext(ctx, &lambda.GetFunctionInput{
+		FunctionName: aws.String(name),
+	})
+
+	var fn *lambda.FunctionConfiguration
+	switch {
+	case isNotFound(err):
+		fn, err = p.createFunction(ctx, log, svc, name, roleArn, code)
+
+	case err == nil:
+		fn, err = p.updateFunction(ctx, log, svc, name, roleArn, code)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	version := aws.StringValue(fn.Version)
+	step.Update("Deployed function %s version %s", name, version)
+	step.Done()
+
+	return &Deployment{
+		Region: p.config.Region,
+
+		// The ARN of a published version includes the version, but the
+		// version is stored separately.
+		FunctionArn: strings.TrimSuffix(aws.StringValue(fn.FunctionArn), ":"+version),
+		Version:     version,
+	}, nil
+}
+
+// setupRole returns the ARN of the execution role of the function. If no
+// role is configured, a role that allows the function to write logs is
+// created for the application.
+func (p *Platform) setupRole(
+	ctx context.Context,
+	log hclog.Logger,
+	sess *session.Session,
+	src *component.Source,
+) (string, error) {
+	svc := iam.New(sess)
+
+	roleName := p.config.RoleName
+	if roleName == "" {
+		roleName = "waypoint-lambda-" + src.App
+	}
+
+	log.Debug("attempting to retrieve existing role", "role-name", roleName)
+	getOut, err := svc.GetRoleWithContext(ctx, &iam.GetRoleInput{
+		RoleName: aws.String(roleName),
+	})
+	if err == nil {
+		return aws.StringValue(getOut.Role.Arn), nil
+	}
+	if p.config.RoleName != "" {
+		// Roles that are configured are never created.
+		return "", err
+	}
+
+	log.Debug("creating new role", "role-name", roleName)
+	result, err := svc.CreateRoleWithContext(ctx, &iam.CreateRoleInput{
+		AssumeRolePolicyDocument: aws.String(rolePolicy),
+		Path:                     aws.String("/"),
+		RoleName:                 aws.String(roleName),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	_, err = svc.AttachRolePolicyWithContext(ctx, &iam.AttachRolePolicyInput{
+		RoleName:  aws.String(roleName),
+		PolicyArn: aws.String("arn:aws:iam::aws:policy/service-role/AWSLambdaBasicExecutionRole"),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	roleArn := aws.StringValue(result.Role.Arn)
+	log.Debug("created new role", "arn", roleArn)
+	return roleArn, nil
+}
+
+// createFunction creates the function and publishes its first version.
+func (p *Platform) createFunction(
+	ctx context.Context,
+	log hclog.Logger,
+	svc *lambda.Lambda,
+	name, roleArn string,
+	code []byte,
+) (*lambda.FunctionConfiguration, error) {
+	input := &lambda.CreateFunctionInput{
+		FunctionName: aws.String(name),
+		Role:         aws.String(roleArn),
+		Runtime:      aws.String(p.config.Runtime),
+		Handler:      aws.String(p.config.Handler),
+		MemorySize:   aws.Int64(int64(p.memory())),
+		Timeout:      aws.Int64(int64(p.timeout())),
+		Environment:  p.environment(),
+		Code:         &lambda.FunctionCode{ZipFile: code},
+		Publish:      aws.Bool(true),
+	}
+
+	// A role that was just created can't be assumed by Lambda until it
+	// has propagated, which takes a few seconds.
+	deadline := time.Now().Add(time.Minute)
+	for {
+		fn, err := svc.CreateFunctionWithContext(ctx, input)
+		if err == nil {
+			return fn, nil
+		}
+
+		aerr, ok := err.(awserr.Error)
+		if !ok ||
+			aerr.Code() != lambda.ErrCodeInvalidParameterValueException ||
+			!strings.Contains(aerr.Message(), "cannot be assumed") ||
+			time.Now().After(deadline) {
+			return nil, err
+		}
+
+		log.Debug("role can't be assumed yet, retrying", "role", roleArn)
+		select {
+		case <-time.After(5 * time.Second):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// updateFunction updates the configuration and code of the function and
+// publishes a new version of it.
+func (p *Platform) updateFunction(
+	ctx context.Context,
+	log hclog.Logger,
+	svc *lambda.Lambda,
+	name, roleArn string,
+	code []byte,
+) (*lambda.FunctionConfiguration, error) {
+	_, err := svc.UpdateFunctionConfigurationWithContext(ctx, &lambda.UpdateFunctionConfigurationInput{
+		FunctionName: aws.String(name),
+		Role:         aws.String(roleArn),
+		Runtime:      aws.String(p.config.Runtime),
+		Handler:      aws.String(p.config.Handler),
+		MemorySize:   aws.Int64(int64(p.memory())),
+		Timeout:      aws.Int64(int64(p.timeout())),
+		Environment:  p.environment(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The code can't be updated while the configuration is being updated.
+	log.Debug("waiting for configuration update", "function", name)
+	err = svc.WaitUntilFunctionUpdatedWithContext(ctx, &lambda.GetFunctionConfigurationInput{
+		FunctionName: aws.String(name),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.UpdateFunctionCodeWithContext(ctx, &lambda.UpdateFunctionCodeInput{
+		FunctionName: aws.String(name),
+		ZipFile:      code,
+		Publish:      aws.Bool(true),
+	})
+}
+
+func (p *Platform) memory() int {
+	if p.config.Memory > 0 {
+		return p.config.Memory
+	}
+
+	return DefaultMemory
+}
+
+func (p *Platform) timeout() int {
+	if p.config.Timeout > 0 {
+		return p.config.Timeout
+	}
+
+	return DefaultTimeout
+}
+
+func (p *Platform) environment() *lambda.Environment {
+	env := &lambda.Environment{Variables: map[string]*string{}}
+	for k, v := range p.config.Environment {
+		env.Variables[k] = aws.String(v)
+	}
+
+	return env
+}
+
+// Destroy deletes the version of the function of the deployment. When no
+// other versions are left, the function is deleted.
+func (p *Platform) Destroy(
+	ctx context.Context,
+	log hclog.Logger,
+	deployment *Deployment,
+	ui terminal.UI,
+) error {
+	sess, err := utils.GetSession(&utils.SessionConfig{
+		Region: deployment.Region,
+	})
+	if err != nil {
+		return err
+	}
+	svc := lambda.New(sess)
+
+	log.Debug("deleting function version",
+		"function", deployment.FunctionArn, "version", deployment.Version)
+	_, err = svc.DeleteFunctionWithContext(ctx, &lambda.DeleteFunctionInput{
+		FunctionName: aws.String(deployment.FunctionArn),
+		Qualifier:    aws.String(deployment.Version),
+	})
+	if isNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// $LATEST is always listed, so the function has no published versions
+	// left if it is the only one.
+	var versions int
+	err = svc.ListVersionsByFunctionPagesWithContext(ctx, &lambda.ListVersionsByFunctionInput{
+		FunctionName: aws.String(deployment.FunctionArn),
+	}, func(page *lambda.ListVersionsByFunctionOutput, lastPage bool) bool {
+		versions += len(page.Versions)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if versions > 1 {
+		return nil
+	}
+
+	log.Debug("deleting function", "function", deployment.FunctionArn)
+	_, err = svc.DeleteFunctionWithContext(ctx, &lambda.DeleteFunctionInput{
+		FunctionName: aws.String(deployment.FunctionArn),
+	})
+	if isNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+// isNotFound returns true if err is returned by the Lambda API for a
+// function, version or alias that doesn't exist.
+func isNotFound(err error) bool {
+	aerr, ok := err.(awserr.Error)
+	return ok && aerr.Code() == lambda.ErrCodeResourceNotFoundException
+}
+
+// Config is the configuration structure for the Platform.
+type Config struct {
+	// AWS Region to deploy into
+	Region string `hcl:"region"`
+
+	// The runtime of the function, such as "nodejs12.x" or "go1.x"
+	Runtime string `hcl:"runtime"`
+
+	// The handler of the function in the code, such as "index.handler"
+	Handler string `hcl:"handler"`
+
+	// Name of the execution IAM role of the function
+	RoleName string `hcl:"role_name,optional"`
+
+	// How much memory in MB to assign to the function
+	Memory int `hcl:"memory,optional"`
+
+	// How many seconds the function may run for
+	Timeout int `hcl:"timeout,optional"`
+
+	// The environment variables to pass to the function
+	Environment map[string]string `hcl:"static_environment,optional"`
+}
+
+func (p *Platform) Documentation() (*docs.Documentation, error) {
+	doc, err := docs.New(docs.FromConfig(&Config{}), docs.FromFunc(p.DeployFunc()))
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Description("Deploy the application as an AWS Lambda function")
+
+	doc.Example(
+		`
+build {
+  use "files" {}
+}
+
+deploy {
+  use "aws-lambda" {
+    region  = "us-east-1"
+    runtime = "nodejs12.x"
+    handler = "index.handler"
+  }
+}
+`)
+
+	doc.Input("files.Files")
+	doc.Output("lambda.Deployment")
+
+	doc.SetField(
+		"region",
+		"the AWS region to deploy the function into",
+	)
+
+	doc.SetField(
+		"runtime",
+		"the Lambda runtime of the function, such as nodejs12.x, python3.8 or go1.x",
+	)
+
+	doc.SetField(
+		"handler",
+		"the function in the code that handles invocations, such as index.handler",
+	)
+
+	doc.SetField(
+		"role_name",
+		"the name of the execution IAM role of the function",
+		docs.Default("create a new IAM role based on the application name"),
+		docs.Summary(
+			"the created role only allows the function to write its logs to",
+			"CloudWatch Logs. A role that is configured must already exist.",
+		),
+	)
+
+	doc.SetField(
+		"memory",
+		"how much memory in MB to assign to the function",
+		docs.Default("256"),
+	)
+
+	doc.SetField(
+		"timeout",
+		"how many seconds an invocation of the function may run for",
+		docs.Default("60"),
+	)
+
+	doc.SetField(
+		"static_environment",
+		"static environment variables to make available",
+	)
+
+	return doc, nil
+}
+
+var (
+	_ component.Platform         = (*Platform)(nil)
+	_ component.Destroyer        = (*Platform)(nil)
+	_ component.PlatformReleaser = (*Platform)(nil)
+	_ component.Configurable     = (*Platform)(nil)
+	_ component.Documented       = (*Platform)(nil)
+)
@@ -0,0 +1,9 @@
+package lambda
+
+import "github.com/hashicorp/waypoint-plugin-sdk/component"
+
+// URL implements component.Release. Lambda functions aren't reachable
+// over HTTP without a separate trigger, so releases have no URL.
+func (r *Release) URL() string { return "" }
+
+var _ component.Release = (*Release)(nil)
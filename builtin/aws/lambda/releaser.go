@@ -0,0 +1,198 @@
+package lambda
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/waypoint-plugin-sdk/component"
+	"github.com/hashicorp/waypoint-plugin-sdk/docs"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/builtin/aws/utils"
+)
+
+// DefaultAlias is the name of the alias that releases point to the
+// released version if none is configured.
+const DefaultAlias = "live"
+
+// Releaser is the ReleaseManager implementation for AWS Lambda. Releases
+// point an alias of the function to the version of the deployment, so
+// triggers that invoke the alias invoke the released version.
+type Releaser struct {
+	config ReleaserConfig
+}
+
+// Config implements Configurable
+func (r *Releaser) Config() (interface{}, error) {
+	return &r.config, nil
+}
+
+// ReleaseFunc implements component.ReleaseManager
+func (r *Releaser) ReleaseFunc() interface{} {
+	return r.Release
+}
+
+// DestroyFunc implements component.Destroyer
+func (r *Releaser) DestroyFunc() interface{} {
+	return r.Destroy
+}
+
+// Release points the alias to the version of the deployment, creating the
+// alias if it doesn't exist.
+func (r *Releaser) Release(
+	ctx context.Context,
+	log hclog.Logger,
+	src *component.Source,
+	ui terminal.UI,
+	target *Deployment,
+) (*Release, error) {
+	alias := r.config.Alias
+	if alias == "" {
+		alias = DefaultAlias
+	}
+
+	sg := ui.StepGroup()
+	defer sg.Wait()
+
+	step := sg.Add("Pointing alias %s to version %s...", alias, target.Version)
+	defer step.Abort()
+
+	sess, err := utils.GetSession(&utils.SessionConfig{
+		Region: target.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+	svc := lambda.New(sess)
+
+	_, err = svc.GetAliasWithContext(ctx, &lambda.GetAliasInput{
+		FunctionName: aws.String(target.FunctionArn),
+		Name:         aws.String(alias),
+	})
+
+	var out *lambda.AliasConfiguration
+	switch {
+	case isNotFound(err):
+		log.Debug("creating alias", "alias", alias, "version", target.Version)
+		out, err = svc.CreateAliasWithContext(ctx, &lambda.CreateAliasInput{
+			FunctionName:    aws.String(target.FunctionArn),
+			FunctionVersion: aws.String(target.Version),
+			Name:            aws.String(alias),
+		})
+
+	case err == nil:
+		log.Debug("updating alias", "alias", alias, "version", target.Version)
+		out, err = svc.UpdateAliasWithContext(ctx, &lambda.UpdateAliasInput{
+			FunctionName:    aws.String(target.FunctionArn),
+			FunctionVersion: aws.String(target.Version),
+			Name:            aws.String(alias),
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	step.Update("Alias %s points to version %s", alias, target.Version)
+	step.Done()
+
+	return &Release{
+		Region:      target.Region,
+		FunctionArn: target.FunctionArn,
+		AliasArn:    aws.StringValue(out.AliasArn),
+		Version:     target.Version,
+	}, nil
+}
+
+// Destroy deletes the alias of the release, unless it has since been
+// pointed to another version by a newer release.
+func (r *Releaser) Destroy(
+	ctx context.Context,
+	log hclog.Logger,
+	release *Release,
+	ui terminal.UI,
+) error {
+	sess, err := utils.GetSession(&utils.SessionConfig{
+		Region: release.Region,
+	})
+	if err != nil {
+		return err
+	}
+	svc := lambda.New(sess)
+
+	// The name of the alias is the last part of its ARN.
+	alias := release.AliasArn[strings.LastIndex(release.AliasArn, ":")+1:]
+
+	out, err := svc.GetAliasWithContext(ctx, &lambda.GetAliasInput{
+		FunctionName: aws.String(release.FunctionArn),
+		Name:         aws.String(alias),
+	})
+	if isNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if v := aws.StringValue(out.FunctionVersion); v != release.Version {
+		log.Debug("alias points to another version, not deleting",
+			"alias", alias, "version", v)
+		return nil
+	}
+
+	log.Debug("deleting alias", "alias", alias)
+	_, err = svc.DeleteAliasWithContext(ctx, &lambda.DeleteAliasInput{
+		FunctionName: aws.String(release.FunctionArn),
+		Name:         aws.String(alias),
+	})
+	if isNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+// ReleaserConfig is the configuration structure for the Releaser.
+type ReleaserConfig struct {
+	// Name of the alias to point to released versions
+	Alias string `hcl:"alias,optional"`
+}
+
+func (r *Releaser) Documentation() (*docs.Documentation, error) {
+	doc, err := docs.New(docs.FromConfig(&ReleaserConfig{}), docs.FromFunc(r.ReleaseFunc()))
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Description("Points an alias of the Lambda function to the deployed version")
+
+	doc.Example(`
+release {
+  use "aws-lambda" {
+    alias = "live"
+  }
+}
+`)
+
+	doc.Input("lambda.Deployment")
+	doc.Output("lambda.Release")
+
+	doc.SetField(
+		"alias",
+		"the name of the alias to point to the released version",
+		docs.Default(DefaultAlias),
+		docs.Summary(
+			"triggers such as API Gateway or event source mappings should invoke",
+			"this alias, so that they invoke the released version.",
+		),
+	)
+
+	return doc, nil
+}
+
+var (
+	_ component.ReleaseManager = (*Releaser)(nil)
+	_ component.Destroyer      = (*Releaser)(nil)
+	_ component.Configurable   = (*Releaser)(nil)
+	_ component.Documented     = (*Releaser)(nil)
+)
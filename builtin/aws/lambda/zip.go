@@ -0,0 +1,95 @@
+package lambda
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// maxZipSize is the largest deployment package that can be uploaded to
+// Lambda directly. Larger packages must be uploaded to S3 first.
+const maxZipSize = 50 << 20
+
+// zipSkip are the names of files and directories that are never part of
+// a deployment package. The data.db files hold the local state when
+// Waypoint is running without a server.
+var zipSkip = map[string]bool{
+	".git":         true,
+	"data.db":      true,
+	"data.db.lock": true,
+}
+
+// zipDir returns a zip archive of the files in dir to use as the code of
+// a Lambda function. Paths in the archive are relative to dir and file
+// modes are kept so that executables, such as the handlers of Go
+// functions, stay executable.
+func zipDir(dir string) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+
+		if zipSkip[info.Name()] {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		// Only regular files are included. Directories are implied by the
+		// paths of the files in them and symlinks aren't followed.
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		hdr, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		hdr.Method = zip.Deflate
+
+		w, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(w, f)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	if buf.Len() > maxZipSize {
+		return nil, fmt.Errorf(
+			"deployment package of %s is %d bytes, larger than the %d bytes Lambda allows",
+			dir, buf.Len(), maxZipSize)
+	}
+
+	return buf.Bytes(), nil
+}
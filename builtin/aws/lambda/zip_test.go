@@ -0,0 +1,56 @@
+package lambda
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestZipDir(t *testing.T) {
+	require := require.New(t)
+
+	td, err := ioutil.TempDir("", "waypoint")
+	require.NoError(err)
+	defer os.RemoveAll(td)
+
+	require.NoError(os.MkdirAll(filepath.Join(td, "lib"), 0755))
+	require.NoError(os.MkdirAll(filepath.Join(td, ".git"), 0755))
+	require.NoError(ioutil.WriteFile(filepath.Join(td, "main"), []byte("main"), 0755))
+	require.NoError(ioutil.WriteFile(filepath.Join(td, "lib", "util.js"), []byte("util"), 0644))
+	require.NoError(ioutil.WriteFile(filepath.Join(td, ".git", "HEAD"), []byte("head"), 0644))
+	require.NoError(ioutil.WriteFile(filepath.Join(td, "data.db"), []byte("db"), 0600))
+
+	data, err := zipDir(td)
+	require.NoError(err)
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(err)
+
+	var names []string
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+		files[f.Name] = f
+	}
+	sort.Strings(names)
+	require.Equal([]string{"lib/util.js", "main"}, names)
+
+	// Executables stay executable
+	if runtime.GOOS != "windows" {
+		require.Equal(os.FileMode(0755), files["main"].Mode().Perm())
+	}
+
+	rc, err := files["lib/util.js"].Open()
+	require.NoError(err)
+	defer rc.Close()
+	contents, err := ioutil.ReadAll(rc)
+	require.NoError(err)
+	require.Equal("util", string(contents))
+}
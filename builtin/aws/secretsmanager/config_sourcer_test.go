@@ -0,0 +1,95 @@
+package secretsmanager
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretValue(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Resp     *secretsmanager.GetSecretValueOutput
+		Key      string
+		Expected string
+		Error    bool
+	}{
+		{
+			"string",
+			&secretsmanager.GetSecretValueOutput{SecretString: aws.String("hello")},
+			"",
+			"hello",
+			false,
+		},
+
+		{
+			"binary",
+			&secretsmanager.GetSecretValueOutput{SecretBinary: []byte("hello")},
+			"",
+			"hello",
+			false,
+		},
+
+		{
+			"no value",
+			&secretsmanager.GetSecretValueOutput{},
+			"",
+			"",
+			true,
+		},
+
+		{
+			"JSON key",
+			&secretsmanager.GetSecretValueOutput{
+				SecretString: aws.String(`{"username":"admin","password":"hunter2"}`),
+			},
+			"password",
+			"hunter2",
+			false,
+		},
+
+		{
+			"JSON key that isn't a string",
+			&secretsmanager.GetSecretValueOutput{
+				SecretString: aws.String(`{"port":5432}`),
+			},
+			"port",
+			"5432",
+			false,
+		},
+
+		{
+			"JSON key not found",
+			&secretsmanager.GetSecretValueOutput{
+				SecretString: aws.String(`{"username":"admin"}`),
+			},
+			"password",
+			"",
+			true,
+		},
+
+		{
+			"key of secret that isn't JSON",
+			&secretsmanager.GetSecretValueOutput{SecretString: aws.String("hello")},
+			"password",
+			"",
+			true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			require := require.New(t)
+
+			value, err := secretValue(tt.Resp, tt.Key)
+			if tt.Error {
+				require.Error(err)
+				return
+			}
+			require.NoError(err)
+			require.Equal(tt.Expected, value)
+		})
+	}
+}
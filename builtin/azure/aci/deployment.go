@@ -12,6 +12,12 @@ import (
 	"github.com/hashicorp/waypoint-plugin-sdk/component"
 )
 
+const (
+	// Azure tag names can't contain some characters, such as "/".
+	tagId    = "_waypoint_hashicorp_com_id"
+	tagNonce = "_waypoint_hashicorp_com_nonce"
+)
+
 var _ component.Deployment = (*Deployment)(nil)
 var locations []string // accessible locations for the current account
 
@@ -109,3 +115,17 @@ func (d *Deployment) createOrUpdate(ctx context.Context, auth autorest.Authorize
 
 	return response.Result(*c)
 }
+
+func (d *Deployment) delete(ctx context.Context, auth autorest.Authorizer) error {
+	c, err := d.containerInstanceGroupsClient(auth)
+	if err != nil {
+		return fmt.Errorf("Unable to create Container Groups client: %s", err)
+	}
+
+	result, err := c.Delete(ctx, d.ContainerGroup.ResourceGroup, d.ContainerGroup.Name)
+	if err != nil && result.StatusCode != 404 {
+		return fmt.Errorf("Unable to delete container group: %s", err)
+	}
+
+	return nil
+}
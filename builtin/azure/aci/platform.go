@@ -37,6 +37,11 @@ func (p *Platform) DeployFunc() interface{} {
 	return p.Deploy
 }
 
+// DestroyFunc implements component.Destroyer
+func (p *Platform) DestroyFunc() interface{} {
+	return p.Destroy
+}
+
 // ConfigSet is called after a configuration has been decoded
 // we can use this to validate the config
 func (p *Platform) ConfigSet(config interface{}) error {
@@ -181,9 +186,18 @@ To update the location you will need to manually destroy and recreate the resour
 		OsType: containerinstance.Linux,
 	}
 
+	// The container group is replaced by every deployment, so the ID of
+	// the deployment is tagged to know which deployment it runs.
+	id, err := component.Id()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Unable to generate ID for the deployment: %s", err)
+	}
+	deployment.Id = id
+
 	// Add the tags
 	var tags = map[string]*string{
-		"_waypoint_hashicorp_com_nonce": to.StringPtr(time.Now().UTC().Format(time.RFC3339Nano)),
+		tagId:    to.StringPtr(id),
+		tagNonce: to.StringPtr(time.Now().UTC().Format(time.RFC3339Nano)),
 	}
 	containerGroup.Tags = tags
 
@@ -224,7 +238,7 @@ To update the location you will need to manually destroy and recreate the resour
 	container := containerinstance.Container{
 		Name: &deployment.ContainerGroup.Name,
 		ContainerProperties: &containerinstance.ContainerProperties{
-			Image:                &img.Image,
+			Image:                to.StringPtr(img.Name()),
 			EnvironmentVariables: &env,
 			Resources:            &containerinstance.ResourceRequirements{},
 		},
@@ -338,14 +352,7 @@ To update the location you will need to manually destroy and recreate the resour
 		return nil, status.Errorf(codes.Internal, "Unable to create or update container instance: %s", err)
 	}
 
-	// The container group is ready, we will set the id and URL
-	id, err := component.Id()
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "Unable to generate ID for the deployment: %s", err)
-	}
-
-	deployment.Id = id
-
+	// The container group is ready, we will set the URL
 	ports := *containerGroupResult.IPAddress.Ports
 	if len(ports) > 0 {
 		// Only set the URL if there is a port
@@ -371,6 +378,45 @@ To update the location you will need to manually destroy and recreate the resour
 	return deployment, nil
 }
 
+// Destroy deletes the container group of the deployment. The container
+// group is shared by the deployments of the application, so it is only
+// deleted if it still runs this deployment.
+func (p *Platform) Destroy(
+	ctx context.Context,
+	log hclog.Logger,
+	deployment *Deployment,
+	ui terminal.UI,
+) error {
+	// We'll update the user in real time
+	st := ui.Status()
+	defer st.Close()
+
+	auth, err := deployment.authenticate(ctx)
+	if err != nil {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	st.Update("Checking container group")
+	containerGroup, err := deployment.getContainerGroup(ctx, auth)
+	if err != nil {
+		if containerGroup.StatusCode == 404 {
+			return nil
+		}
+
+		return status.Errorf(codes.Internal, "Unable to get container group: %s", err)
+	}
+
+	if v := containerGroup.Tags[tagId]; v == nil || *v != deployment.Id {
+		log.Info("Container group runs another deployment, not deleting",
+			"containergroup", deployment.ContainerGroup.Name)
+		return nil
+	}
+
+	st.Update("Deleting container group")
+	log.Info("Deleting container group", "containergroup", deployment.ContainerGroup.Name)
+	return deployment.delete(ctx, auth)
+}
+
 // Config is the configuration structure for the Platform.
 // In addition to HCL defined configuration the following environment variables
 // are also valid
@@ -591,5 +637,6 @@ deploy {
 var (
 	_ component.Platform     = (*Platform)(nil)
 	_ component.Configurable = (*Platform)(nil)
+	_ component.Destroyer    = (*Platform)(nil)
 	_ component.Documented   = (*Platform)(nil)
 )
@@ -0,0 +1,85 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/registry"
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RegistryAuth returns the credentials for the registry of the image ref,
+// encoded for the RegistryAuth option of image pushes and pulls. The
+// credentials are read from the Docker config file, including any
+// credential helpers it configures. If there are none, the encoded empty
+// credentials are returned so that the registry is accessed anonymously.
+//
+// Plugins that push or pull images should use this rather than reading
+// the Docker config file themselves, so that auth behaves the same way
+// across plugins.
+func RegistryAuth(
+	ctx context.Context,
+	log hclog.Logger,
+	cli *client.Client,
+	ref reference.Named,
+) (string, error) {
+	// Resolve the Repository name from fqn to RepositoryInfo
+	repoInfo, err := registry.ParseRepositoryInfo(ref)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "unable to parse repository info from image name: %s", err)
+	}
+
+	var server string
+	if repoInfo.Index.Official {
+		info, err := cli.Info(ctx)
+		if err != nil || info.IndexServerAddress == "" {
+			server = registry.IndexServer
+		} else {
+			server = info.IndexServerAddress
+		}
+	} else {
+		server = repoInfo.Index.Name
+	}
+
+	var errBuf bytes.Buffer
+	cf := config.LoadDefaultConfigFile(&errBuf)
+	if errBuf.Len() > 0 {
+		// The config file is optional, so we continue without its
+		// credentials.
+		log.Warn("error loading Docker config file", "err", errBuf.String())
+	}
+
+	// Errors are ignored since they only mean that we have no credentials
+	// for this registry, which is the same as using empty credentials.
+	authConfig, _ := cf.GetAuthConfig(server)
+	return encodeRegistryAuth(authConfig)
+}
+
+// EncodeRegistryAuth encodes the username and password for the
+// RegistryAuth option of image pushes and pulls. This is for plugins that
+// get credentials from somewhere other than the Docker config file, such
+// as a registry token exchange.
+func EncodeRegistryAuth(username, password string) (string, error) {
+	return encodeRegistryAuth(types.AuthConfig{
+		Username: username,
+		Password: password,
+	})
+}
+
+func encodeRegistryAuth(authConfig interface{}) (string, error) {
+	buf, err := json.Marshal(authConfig)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "unable to generate authentication info for registry: %s", err)
+	}
+
+	// The Docker API decodes this as URL safe base64.
+	return base64.URLEncoding.EncodeToString(buf), nil
+}
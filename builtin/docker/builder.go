@@ -47,6 +47,12 @@ type BuilderConfig struct {
 
 	// The name/path to the Dockerfile if it is not the root of the project
 	Dockerfile string `hcl:"dockerfile,optional"`
+
+	// Controls whether or not to build with Podman instead of Docker
+	Podman bool `hcl:"podman,optional"`
+
+	// The platforms to build the image for, in the form os/arch[/variant]
+	Platforms []string `hcl:"platforms,optional"`
 }
 
 func (b *Builder) Documentation() (*docs.Documentation, error) {
@@ -94,6 +100,31 @@ build {
 		),
 	)
 
+	doc.SetField(
+		"podman",
+		"if set, build with Podman through its Docker compatible API socket",
+		docs.Summary(
+			"The socket of the current user's rootless Podman is used if it",
+			"exists, and the socket of the system Podman otherwise. Podman is",
+			"also used without this when DOCKER_HOST isn't set and there is no",
+			"Docker daemon socket. BuildKit isn't supported with Podman",
+		),
+	)
+
+	doc.SetField(
+		"platforms",
+		"the platforms to build the image for, such as linux/amd64 and linux/arm64",
+		docs.Summary(
+			"By default the image is built for the platform of the Docker daemon.",
+			"If set, an image is built for each platform and the docker and aws-ecr",
+			"registries push them together as a manifest list, so that the same image",
+			"name runs on each platform. Building for a platform other than that of the",
+			"daemon requires QEMU emulation to be set up on the host, as with",
+			"docker buildx. The entrypoint binary is only injected into linux/amd64",
+			"images",
+		),
+	)
+
 	return doc, nil
 }
 
@@ -117,12 +148,27 @@ func (b *Builder) Build(
 	step := sg.Add("Initializing Docker client...")
 	defer step.Abort()
 
+	for _, platform := range b.config.Platforms {
+		if _, err := platformParts(platform); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%s", err)
+		}
+	}
+
 	result := &Image{
 		Image: fmt.Sprintf("waypoint.local/%s", src.App),
 		Tag:   "latest",
 	}
 
-	cli, err := wpdockerclient.NewClientWithOpts(client.FromEnv)
+	opts := []client.Opt{client.FromEnv}
+	if b.config.Podman {
+		if b.config.UseBuildKit {
+			return nil, status.Errorf(codes.InvalidArgument, "buildkit can't be used with podman")
+		}
+
+		opts = append(opts, wpdockerclient.WithPodman)
+	}
+
+	cli, err := wpdockerclient.NewClientWithOpts(opts...)
 	if err != nil {
 		return nil, status.Errorf(codes.FailedPrecondition, "unable to create Docker client: %s", err)
 	}
@@ -173,79 +219,135 @@ func (b *Builder) Build(
 	relDockerfile = archive.CanonicalTarNameForPath(relDockerfile)
 
 	excludes = build.TrimBuildFilesFromExcludes(excludes, relDockerfile, false)
-	buildCtx, err := archive.TarWithOptions(contextDir, &archive.TarOptions{
-		ExcludePatterns: excludes,
-		ChownOpts:       &idtools.Identity{UID: 0, GID: 0},
-	})
-
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "unable to compress context: %s", err)
-	}
 
 	ver := types.BuilderV1
 	if b.config.UseBuildKit {
 		ver = types.BuilderBuildKit
 	}
 
-	step.Done()
-	step = sg.Add("Building image...")
-
-	resp, err := cli.ImageBuild(ctx, buildCtx, types.ImageBuildOptions{
-		Version:    ver,
-		Dockerfile: relDockerfile,
-		Tags:       []string{result.Name()},
-		Remove:     true,
-	})
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "error building image: %s", err)
-	}
-	defer resp.Body.Close()
-
 	var termFd uintptr
 	if f, ok := stdout.(*os.File); ok {
 		termFd = f.Fd()
 	}
 
-	err = jsonmessage.DisplayJSONMessagesStream(resp.Body, step.TermOutput(), termFd, true, nil)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "unable to stream build logs to the terminal: %s", err)
-	}
-
 	step.Done()
 
-	if !b.config.DisableCEB {
-		step = sg.Add("Injecting Waypoint Entrypoint...")
+	// The Docker daemon stores a single platform's image under a name, so
+	// for multiple platforms each one is built separately under its own tag.
+	type imageBuild struct {
+		platform string
+		name     string
+	}
 
-		asset, err := assets.Asset("ceb/ceb")
-		if err != nil {
-			return nil, status.Errorf(codes.Internal, "unable to restore custom entry point binary: %s", err)
+	builds := []imageBuild{{name: result.Name()}}
+	if len(b.config.Platforms) > 0 {
+		result.Platforms = b.config.Platforms
+
+		builds = nil
+		for _, platform := range result.Platforms {
+			builds = append(builds, imageBuild{
+				platform: platform,
+				name:     result.PlatformName(platform),
+			})
 		}
+	}
+
+	for _, ib := range builds {
+		buildCtx, err := archive.TarWithOptions(contextDir, &archive.TarOptions{
+			ExcludePatterns: excludes,
+			ChownOpts:       &idtools.Identity{UID: 0, GID: 0},
+		})
 
-		assetInfo, err := assets.AssetInfo("ceb/ceb")
 		if err != nil {
-			return nil, status.Errorf(codes.Internal, "unable to restore custom entry point binary: %s", err)
+			return nil, status.Errorf(codes.Internal, "unable to compress context: %s", err)
 		}
 
-		_, err = epinject.AlterEntrypoint(ctx, result.Name(), func(cur []string) (*epinject.NewEntrypoint, error) {
-			ep := &epinject.NewEntrypoint{
-				Entrypoint: append([]string{"/waypoint-entrypoint"}, cur...),
-				InjectFiles: map[string]epinject.InjectFile{
-					"/waypoint-entrypoint": {
-						Reader: bytes.NewReader(asset),
-						Info:   assetInfo,
-					},
-				},
-			}
+		if ib.platform == "" {
+			step = sg.Add("Building image...")
+		} else {
+			step = sg.Add("Building image for %s...", ib.platform)
+		}
 
-			return ep, nil
+		resp, err := cli.ImageBuild(ctx, buildCtx, types.ImageBuildOptions{
+			Version:    ver,
+			Dockerfile: relDockerfile,
+			Tags:       []string{ib.name},
+			Remove:     true,
+			Platform:   ib.platform,
 		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "error building image: %s", err)
+		}
 
+		err = jsonmessage.DisplayJSONMessagesStream(resp.Body, step.TermOutput(), termFd, true, nil)
+		resp.Body.Close()
 		if err != nil {
-			return nil, status.Errorf(codes.Internal, "unable to set modify Docker entrypoint: %s", err)
+			return nil, status.Errorf(codes.Internal, "unable to stream build logs to the terminal: %s", err)
+		}
+
+		step.Done()
+
+		if b.config.DisableCEB {
+			continue
+		}
+
+		// The entrypoint binary is only built for linux/amd64.
+		if ib.platform != "" && ib.platform != "linux/amd64" {
+			step = sg.Add("Not injecting Waypoint Entrypoint into the %s image, it's only available for linux/amd64", ib.platform)
+			step.Status(terminal.StatusWarn)
+			step.Done()
+			continue
+		}
+
+		step = sg.Add("Injecting Waypoint Entrypoint...")
+		if err := injectEntrypoint(ctx, ib.name); err != nil {
+			return nil, err
 		}
 
 		step.Done()
 	}
 
+	if len(result.Platforms) > 0 {
+		// The image for the first platform also gets the plain tag so that
+		// it can be used locally like an image built for a single platform.
+		if err := cli.ImageTag(ctx, result.PlatformName(result.Platforms[0]), result.Name()); err != nil {
+			return nil, status.Errorf(codes.Internal, "unable to tag image: %s", err)
+		}
+	}
+
 	return result, nil
 }
+
+// injectEntrypoint injects the Waypoint entrypoint binary into the image
+// with the given name, wrapping the image's entrypoint.
+func injectEntrypoint(ctx context.Context, name string) error {
+	asset, err := assets.Asset("ceb/ceb")
+	if err != nil {
+		return status.Errorf(codes.Internal, "unable to restore custom entry point binary: %s", err)
+	}
+
+	assetInfo, err := assets.AssetInfo("ceb/ceb")
+	if err != nil {
+		return status.Errorf(codes.Internal, "unable to restore custom entry point binary: %s", err)
+	}
+
+	_, err = epinject.AlterEntrypoint(ctx, name, func(cur []string) (*epinject.NewEntrypoint, error) {
+		ep := &epinject.NewEntrypoint{
+			Entrypoint: append([]string{"/waypoint-entrypoint"}, cur...),
+			InjectFiles: map[string]epinject.InjectFile{
+				"/waypoint-entrypoint": {
+					Reader: bytes.NewReader(asset),
+					Info:   assetInfo,
+				},
+			},
+		}
+
+		return ep, nil
+	})
+
+	if err != nil {
+		return status.Errorf(codes.Internal, "unable to set modify Docker entrypoint: %s", err)
+	}
+
+	return nil
+}
@@ -1,18 +1,84 @@
 package client
 
 import (
+	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
 
 	"github.com/docker/cli/cli/connhelper"
 	"github.com/docker/docker/client"
 )
 
-// NewClientWithOpts wraps Docker's NewClientWithOpts with withConnectionHelper
+// defaultDockerSocket is the socket of the Docker daemon when no host is
+// configured.
+const defaultDockerSocket = "/var/run/docker.sock"
+
+// NewClientWithOpts wraps Docker's NewClientWithOpts with withPodmanFallback
+// and withConnectionHelper
 func NewClientWithOpts(ops ...client.Opt) (*client.Client, error) {
-	ops = append(ops, withConnectionHelper)
+	ops = append(ops, withPodmanFallback, withConnectionHelper)
 	return client.NewClientWithOpts(ops...)
 }
 
+// PodmanHost returns the host of the Podman API socket, or "" if there is
+// none. The socket of the current user's rootless Podman is preferred over
+// the socket of the system Podman. Podman serves a Docker compatible API on
+// these sockets once "podman system service" runs.
+func PodmanHost() string {
+	var paths []string
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		paths = append(paths, filepath.Join(dir, "podman", "podman.sock"))
+	}
+	paths = append(paths,
+		fmt.Sprintf("/run/user/%d/podman/podman.sock", os.Getuid()),
+		"/run/podman/podman.sock",
+	)
+
+	for _, path := range paths {
+		fi, err := os.Stat(path)
+		if err == nil && fi.Mode()&os.ModeSocket != 0 {
+			return "unix://" + path
+		}
+	}
+
+	return ""
+}
+
+// WithPodman connects the client to the Podman API socket instead of the
+// Docker daemon.
+func WithPodman(c *client.Client) error {
+	host := PodmanHost()
+	if host == "" {
+		return fmt.Errorf("no Podman API socket found, " +
+			"start one with \"podman system service\"")
+	}
+
+	return client.WithHost(host)(c)
+}
+
+// withPodmanFallback connects the client to the Podman API socket if no
+// host is configured and there is no Docker daemon socket, so that hosts
+// that only have Podman work without configuration.
+func withPodmanFallback(c *client.Client) error {
+	if runtime.GOOS == "windows" ||
+		os.Getenv("DOCKER_HOST") != "" ||
+		c.DaemonHost() != client.DefaultDockerHost {
+		return nil
+	}
+
+	if _, err := os.Stat(defaultDockerSocket); err == nil {
+		return nil
+	}
+
+	if host := PodmanHost(); host != "" {
+		return client.WithHost(host)(c)
+	}
+
+	return nil
+}
+
 // withConnectionHelper applies a Docker-specific connection helper (concept from the
 // Docker CLI) for a given daemon host. As an example, a connection helper makes it
 // possible to use the client given a DOCKER_HOST with an ssh scheme.
@@ -0,0 +1,34 @@
+package client
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPodmanHost(t *testing.T) {
+	require := require.New(t)
+
+	td, err := ioutil.TempDir("", "waypoint")
+	require.NoError(err)
+	defer os.RemoveAll(td)
+
+	defer os.Setenv("XDG_RUNTIME_DIR", os.Getenv("XDG_RUNTIME_DIR"))
+	require.NoError(os.Setenv("XDG_RUNTIME_DIR", td))
+
+	// A regular file isn't a socket
+	path := filepath.Join(td, "podman", "podman.sock")
+	require.NoError(os.MkdirAll(filepath.Dir(path), 0700))
+	require.NoError(ioutil.WriteFile(path, nil, 0600))
+	require.NotEqual("unix://"+path, PodmanHost())
+	require.NoError(os.Remove(path))
+
+	ln, err := net.Listen("unix", path)
+	require.NoError(err)
+	defer ln.Close()
+	require.Equal("unix://"+path, PodmanHost())
+}
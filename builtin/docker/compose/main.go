@@ -0,0 +1,14 @@
+// Package dockercompose contains a platform that deploys with Docker
+// Compose on the local Docker daemon.
+package dockercompose
+
+import (
+	"github.com/hashicorp/waypoint-plugin-sdk"
+)
+
+//go:generate protoc -I ../../../.. --go_opt=plugins=grpc --go_out=../../../.. waypoint/builtin/docker/compose/plugin.proto
+
+// Options are the SDK options to use for instantiation.
+var Options = []sdk.Option{
+	sdk.WithComponents(&Platform{}),
+}
@@ -0,0 +1,60 @@
+package dockercompose
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"gopkg.in/yaml.v2"
+)
+
+// composeVersion returns the version of the compose file at path, or ""
+// if the file doesn't declare one.
+func composeVersion(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	// The version may be written as a number, such as 3.8.
+	var file struct {
+		Version interface{} `yaml:"version"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return "", fmt.Errorf("error parsing %s: %s", path, err)
+	}
+	if file.Version == nil {
+		return "", nil
+	}
+
+	return fmt.Sprint(file.Version), nil
+}
+
+// overrideFile returns a compose file that runs service with image, with
+// the id of the deployment as a label and env added to its environment.
+// Compose requires the files it merges to have the same version.
+func overrideFile(
+	version, service, image, id string,
+	env map[string]string,
+) ([]byte, error) {
+	type serviceConfig struct {
+		Image       string            `yaml:"image"`
+		Labels      map[string]string `yaml:"labels"`
+		Environment map[string]string `yaml:"environment,omitempty"`
+	}
+
+	file := struct {
+		Version  string                   `yaml:"version,omitempty"`
+		Services map[string]serviceConfig `yaml:"services"`
+	}{
+		Version: version,
+		Services: map[string]serviceConfig{
+			service: {
+				Image:       image,
+				Labels:      map[string]string{labelId: id},
+				Environment: env,
+			},
+		},
+	}
+
+	return yaml.Marshal(&file)
+}
@@ -0,0 +1,59 @@
+package dockercompose
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestComposeVersion(t *testing.T) {
+	td, err := ioutil.TempDir("", "waypoint")
+	require.NoError(t, err)
+	defer os.RemoveAll(td)
+
+	cases := []struct {
+		Name     string
+		File     string
+		Expected string
+	}{
+		{"string", "version: \"3.8\"\nservices: {}\n", "3.8"},
+		{"number", "version: 3.8\nservices: {}\n", "3.8"},
+		{"integer", "version: 2\nservices: {}\n", "2"},
+		{"none", "services: {}\n", ""},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			require := require.New(t)
+
+			path := filepath.Join(td, tt.Name+".yml")
+			require.NoError(ioutil.WriteFile(path, []byte(tt.File), 0644))
+
+			version, err := composeVersion(path)
+			require.NoError(err)
+			require.Equal(tt.Expected, version)
+		})
+	}
+}
+
+func TestOverrideFile(t *testing.T) {
+	require := require.New(t)
+
+	data, err := overrideFile("3.8", "web", "example:latest", "ID",
+		map[string]string{"WAYPOINT_URL": "localhost:9701"})
+	require.NoError(err)
+
+	var file map[string]interface{}
+	require.NoError(yaml.Unmarshal(data, &file))
+	require.Equal("3.8", file["version"])
+
+	services := file["services"].(map[interface{}]interface{})
+	web := services["web"].(map[interface{}]interface{})
+	require.Equal("example:latest", web["image"])
+	require.Equal(map[interface{}]interface{}{labelId: "ID"}, web["labels"])
+	require.Equal(map[interface{}]interface{}{"WAYPOINT_URL": "localhost:9701"}, web["environment"])
+}
@@ -0,0 +1,307 @@
+package dockercompose
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/component"
+	"github.com/hashicorp/waypoint-plugin-sdk/docs"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	wpdocker "github.com/hashicorp/waypoint/builtin/docker"
+	wpdockerclient "github.com/hashicorp/waypoint/builtin/docker/client"
+)
+
+const (
+	labelId      = "waypoint.hashicorp.com/id"
+	labelProject = "com.docker.compose.project"
+)
+
+// Platform is the Platform implementation for Docker Compose.
+type Platform struct {
+	config PlatformConfig
+}
+
+// Config implements Configurable
+func (p *Platform) Config() (interface{}, error) {
+	return &p.config, nil
+}
+
+// DeployFunc implements component.Platform
+func (p *Platform) DeployFunc() interface{} {
+	return p.Deploy
+}
+
+// DestroyFunc implements component.Destroyer
+func (p *Platform) DestroyFunc() interface{} {
+	return p.Destroy
+}
+
+// Deploy runs the compose project with the service of the application
+// using the built image. Services that are already running are only
+// recreated if their configuration changed, so the services the
+// application depends on keep running across deployments.
+func (p *Platform) Deploy(
+	ctx context.Context,
+	log hclog.Logger,
+	src *component.Source,
+	img *wpdocker.Image,
+	deployConfig *component.DeploymentConfig,
+	ui terminal.UI,
+) (*Deployment, error) {
+	sg := ui.StepGroup()
+	defer sg.Wait()
+
+	id, err := component.Id()
+	if err != nil {
+		return nil, err
+	}
+	result := &Deployment{
+		Id:      id,
+		Project: p.project(src),
+		Service: p.config.Service,
+	}
+	if result.Service == "" {
+		result.Service = src.App
+	}
+
+	var files []string
+	for _, f := range p.config.ComposeFiles {
+		if !filepath.IsAbs(f) {
+			f = filepath.Join(src.Path, f)
+		}
+		files = append(files, f)
+	}
+	if len(files) == 0 {
+		files = []string{filepath.Join(src.Path, "docker-compose.yml")}
+	}
+
+	s := sg.Add("Preparing compose files...")
+	defer func() { s.Abort() }()
+
+	version, err := composeVersion(files[0])
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"unable to read compose file: %s", err)
+	}
+
+	override, err := overrideFile(version, result.Service, img.Name(), id, deployConfig.Env())
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := ioutil.TempFile("", "waypoint-compose-*.yml")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(override); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	s.Done()
+
+	// The project directory is the directory of the first file, so paths
+	// in the compose files are relative to them and not to the override.
+	args := []string{"--project-name", result.Project}
+	for _, f := range files {
+		args = append(args, "--file", f)
+	}
+	args = append(args, "--file", f.Name(), "up", "--detach", "--no-build")
+
+	s = sg.Add("Starting compose project %s...", result.Project)
+	log.Debug("running docker-compose", "args", args)
+	cmd := exec.CommandContext(ctx, "docker-compose", args...)
+	cmd.Dir = src.Path
+	cmd.Stdout = s.TermOutput()
+	cmd.Stderr = s.TermOutput()
+	if err := cmd.Run(); err != nil {
+		return nil, status.Errorf(codes.Internal,
+			"docker-compose up failed: %s", err)
+	}
+
+	s.Update("Compose project %s is running service %s", result.Project, result.Service)
+	s.Done()
+
+	return result, nil
+}
+
+// Destroy removes the containers and networks of the compose project. A
+// project only runs its latest deployment, so nothing is removed for
+// deployments that have been replaced.
+func (p *Platform) Destroy(
+	ctx context.Context,
+	log hclog.Logger,
+	deployment *Deployment,
+	ui terminal.UI,
+) error {
+	cli, err := wpdockerclient.NewClientWithOpts(client.FromEnv)
+	if err != nil {
+		return status.Errorf(codes.FailedPrecondition, "unable to create Docker client: %s", err)
+	}
+
+	cli.NegotiateAPIVersion(ctx)
+
+	// We'll update the user in real time
+	st := ui.Status()
+	defer st.Close()
+	st.Update("Checking compose project...")
+
+	projectFilter := filters.Arg("label", labelProject+"="+deployment.Project)
+	current, err := cli.ContainerList(ctx, types.ContainerListOptions{
+		All: true,
+		Filters: filters.NewArgs(
+			projectFilter,
+			filters.Arg("label", labelId+"="+deployment.Id),
+		),
+	})
+	if err != nil {
+		return err
+	}
+	if len(current) == 0 {
+		log.Debug("deployment isn't running, nothing to destroy", "id", deployment.Id)
+		return nil
+	}
+
+	st.Update("Deleting compose project containers...")
+	containers, err := cli.ContainerList(ctx, types.ContainerListOptions{
+		All:     true,
+		Filters: filters.NewArgs(projectFilter),
+	})
+	if err != nil {
+		return err
+	}
+	for _, c := range containers {
+		log.Debug("removing container", "id", c.ID, "names", c.Names)
+		err := cli.ContainerRemove(ctx, c.ID, types.ContainerRemoveOptions{
+			Force: true,
+		})
+		if err != nil && !client.IsErrNotFound(err) {
+			return err
+		}
+	}
+
+	st.Update("Deleting compose project networks...")
+	networks, err := cli.NetworkList(ctx, types.NetworkListOptions{
+		Filters: filters.NewArgs(projectFilter),
+	})
+	if err != nil {
+		return err
+	}
+	for _, n := range networks {
+		log.Debug("removing network", "id", n.ID, "name", n.Name)
+		if err := cli.NetworkRemove(ctx, n.ID); err != nil && !client.IsErrNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// project returns the name of the compose project. Compose only allows
+// lowercase letters, digits, dashes and underscores in project names.
+func (p *Platform) project(src *component.Source) string {
+	if p.config.Project != "" {
+		return p.config.Project
+	}
+
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		default:
+			return -1
+		}
+	}, src.App)
+}
+
+// PlatformConfig is the configuration structure for the Platform.
+type PlatformConfig struct {
+	// ComposeFiles are the paths to the compose files, relative to the
+	// application. Defaults to docker-compose.yml.
+	ComposeFiles []string `hcl:"compose_files,optional"`
+
+	// Project is the name of the compose project. Defaults to the app name.
+	Project string `hcl:"project,optional"`
+
+	// Service is the name of the service in the compose files that runs
+	// the built image. Defaults to the app name.
+	Service string `hcl:"service,optional"`
+}
+
+func (p *Platform) Documentation() (*docs.Documentation, error) {
+	doc, err := docs.New(docs.FromConfig(&PlatformConfig{}), docs.FromFunc(p.DeployFunc()))
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Description(`
+Deploy the application with Docker Compose on the local Docker daemon
+
+The service of the application in the compose files is run with the built
+image, along with the other services in the files, such as databases that
+the application depends on. Services are only recreated if their
+configuration changed, so redeploying replaces just the application.
+`)
+
+	doc.Example(`
+deploy {
+  use "docker-compose" {
+    service = "web"
+  }
+}
+`)
+
+	doc.Input("docker.Image")
+	doc.Output("dockercompose.Deployment")
+
+	doc.SetField(
+		"compose_files",
+		"paths to the compose files, relative to the application",
+		docs.Default("docker-compose.yml"),
+		docs.Summary(
+			"paths in the compose files are relative to the directory of the",
+			"first file, as with docker-compose",
+		),
+	)
+
+	doc.SetField(
+		"project",
+		"the name of the compose project",
+		docs.Default("the application name"),
+	)
+
+	doc.SetField(
+		"service",
+		"the service in the compose files that runs the built image",
+		docs.Default("the application name"),
+		docs.Summary(
+			"the image of the service is replaced by the built image, so the",
+			"service may declare a placeholder image or a build section",
+		),
+	)
+
+	return doc, nil
+}
+
+var (
+	_ component.Platform     = (*Platform)(nil)
+	_ component.Configurable = (*Platform)(nil)
+	_ component.Destroyer    = (*Platform)(nil)
+	_ component.Documented   = (*Platform)(nil)
+)
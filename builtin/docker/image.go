@@ -1,6 +1,20 @@
 package docker
 
+import "strings"
+
 // Name is the full name including the tag.
 func (i *Image) Name() string {
 	return i.Image + ":" + i.Tag
 }
+
+// PlatformTag is the tag of the image for one of the platforms of an
+// image built for multiple platforms.
+func (i *Image) PlatformTag(platform string) string {
+	return i.Tag + "-" + strings.Replace(platform, "/", "-", -1)
+}
+
+// PlatformName is the full name of the image for one of the platforms of
+// an image built for multiple platforms.
+func (i *Image) PlatformName(platform string) string {
+	return i.Image + ":" + i.PlatformTag(platform)
+}
@@ -0,0 +1,193 @@
+package docker
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+	// schema2 registers its media type, so that the images of the
+	// platforms are fetched in that format rather than converted to the
+	// legacy format that manifest lists can't reference.
+	_ "github.com/docker/distribution/manifest/schema2"
+	"github.com/docker/distribution/reference"
+	"github.com/docker/distribution/registry/client"
+	"github.com/docker/distribution/registry/client/auth"
+	"github.com/docker/distribution/registry/client/auth/challenge"
+	"github.com/docker/distribution/registry/client/transport"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/registry"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// platformParts splits a platform in the form os/arch[/variant], such as
+// linux/amd64 or linux/arm64/v8, into its parts.
+func platformParts(platform string) ([]string, error) {
+	parts := strings.Split(platform, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, fmt.Errorf("platform %q must be in the form os/arch[/variant]", platform)
+	}
+
+	for _, part := range parts {
+		if part == "" {
+			return nil, fmt.Errorf("platform %q must be in the form os/arch[/variant]", platform)
+		}
+	}
+
+	return parts, nil
+}
+
+// PushManifestList pushes a manifest list that references the already
+// pushed image of each of the platforms of target, tagged with the tag of
+// target. This lets the same image name be pulled on each of the
+// platforms. The digest of the manifest list is returned.
+//
+// The Docker daemon can't store manifest lists, so this talks to the
+// registry directly with the credentials that the images were pushed with.
+func PushManifestList(ctx context.Context, target *Image, encodedAuth string) (string, error) {
+	ref, err := reference.ParseNormalizedNamed(target.Image)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "unable to parse image name: %s", err)
+	}
+
+	repoInfo, err := registry.ParseRepositoryInfo(ref)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "unable to parse repository info from image name: %s", err)
+	}
+
+	endpoint := "https://" + repoInfo.Index.Name
+	if repoInfo.Index.Official {
+		endpoint = registry.DefaultV2Registry.String()
+	} else if !repoInfo.Index.Secure {
+		endpoint = "http://" + repoInfo.Index.Name
+	}
+
+	var authConfig types.AuthConfig
+	if encodedAuth != "" {
+		buf, err := base64.URLEncoding.DecodeString(encodedAuth)
+		if err == nil {
+			err = json.Unmarshal(buf, &authConfig)
+		}
+		if err != nil {
+			return "", status.Errorf(codes.InvalidArgument, "unable to decode registry authentication: %s", err)
+		}
+	}
+
+	tr, err := registryTransport(ctx, endpoint, reference.Path(ref), authConfig)
+	if err != nil {
+		return "", status.Errorf(codes.Unavailable, "unable to connect to registry: %s", err)
+	}
+
+	// The registry API addresses repositories by their path only.
+	name, err := reference.WithName(reference.Path(ref))
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "unable to parse image name: %s", err)
+	}
+
+	repo, err := client.NewRepository(name, endpoint, tr)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "unable to create registry client: %s", err)
+	}
+
+	var descs []manifestlist.ManifestDescriptor
+	for _, platform := range target.Platforms {
+		parts, err := platformParts(platform)
+		if err != nil {
+			return "", status.Errorf(codes.InvalidArgument, "%s", err)
+		}
+
+		desc, err := repo.Tags(ctx).Get(ctx, target.PlatformTag(platform))
+		if err != nil {
+			return "", status.Errorf(codes.Internal,
+				"unable to get the pushed image for platform %q: %s", platform, err)
+		}
+
+		spec := manifestlist.PlatformSpec{OS: parts[0], Architecture: parts[1]}
+		if len(parts) == 3 {
+			spec.Variant = parts[2]
+		}
+
+		descs = append(descs, manifestlist.ManifestDescriptor{
+			Descriptor: desc,
+			Platform:   spec,
+		})
+	}
+
+	list, err := manifestlist.FromDescriptors(descs)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "unable to create manifest list: %s", err)
+	}
+
+	ms, err := repo.Manifests(ctx)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "unable to create registry client: %s", err)
+	}
+
+	dgst, err := ms.Put(ctx, list, distribution.WithTag(target.Tag))
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "unable to push manifest list: %s", err)
+	}
+
+	return dgst.String(), nil
+}
+
+// registryTransport returns a transport for the registry API at endpoint
+// that authenticates for pushes to the repository at the given path.
+func registryTransport(
+	ctx context.Context,
+	endpoint string,
+	path string,
+	authConfig types.AuthConfig,
+) (http.RoundTripper, error) {
+	base := http.DefaultTransport
+
+	// The registry's response to the version check tells us how it wants
+	// us to authenticate.
+	req, err := http.NewRequest("GET", endpoint+"/v2/", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	cm := challenge.NewSimpleManager()
+	if err := cm.AddResponse(resp); err != nil {
+		return nil, err
+	}
+
+	creds := registryCredentials(authConfig)
+	return transport.NewTransport(base, auth.NewAuthorizer(cm,
+		auth.NewTokenHandlerWithOptions(auth.TokenHandlerOptions{
+			Transport:   base,
+			Credentials: creds,
+			Scopes: []auth.Scope{
+				auth.RepositoryScope{Repository: path, Actions: []string{"pull", "push"}},
+			},
+		}),
+		auth.NewBasicHandler(creds),
+	)), nil
+}
+
+// registryCredentials implements auth.CredentialStore for the credentials
+// that Docker uses for the registry.
+type registryCredentials types.AuthConfig
+
+func (c registryCredentials) Basic(*url.URL) (string, string) {
+	return c.Username, c.Password
+}
+
+func (c registryCredentials) RefreshToken(*url.URL, string) string {
+	return c.IdentityToken
+}
+
+func (c registryCredentials) SetRefreshToken(*url.URL, string, string) {}
@@ -0,0 +1,43 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlatformParts(t *testing.T) {
+	cases := []struct {
+		Platform string
+		Parts    []string
+		Err      bool
+	}{
+		{"linux/amd64", []string{"linux", "amd64"}, false},
+		{"linux/arm64/v8", []string{"linux", "arm64", "v8"}, false},
+		{"linux", nil, true},
+		{"linux/", nil, true},
+		{"linux/arm/v7/extra", nil, true},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Platform, func(t *testing.T) {
+			require := require.New(t)
+
+			parts, err := platformParts(tt.Platform)
+			if tt.Err {
+				require.Error(err)
+				return
+			}
+
+			require.NoError(err)
+			require.Equal(tt.Parts, parts)
+		})
+	}
+}
+
+func TestImagePlatformName(t *testing.T) {
+	require := require.New(t)
+
+	img := &Image{Image: "waypoint.local/app", Tag: "latest"}
+	require.Equal("waypoint.local/app:latest-linux-arm64-v8", img.PlatformName("linux/arm64/v8"))
+}
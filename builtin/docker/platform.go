@@ -248,15 +248,28 @@ func (p *Platform) Destroy(
 
 func (p *Platform) getDockerClient() (*client.Client, error) {
 	if p.config.ClientConfig == nil {
-		return wpdockerclient.NewClientWithOpts(client.FromEnv)
+		opts := []client.Opt{client.FromEnv}
+		if p.config.Podman {
+			opts = append(opts, wpdockerclient.WithPodman)
+		}
+
+		return wpdockerclient.NewClientWithOpts(opts...)
 	}
 
 	opts := []client.Opt{}
 
 	if host := p.config.ClientConfig.Host; host != "" {
+		if p.config.Podman {
+			return nil, fmt.Errorf("client_config.host can't be set with podman")
+		}
+
 		opts = append(opts, client.WithHost(host))
 	}
 
+	if p.config.Podman {
+		opts = append(opts, wpdockerclient.WithPodman)
+	}
+
 	if path := p.config.ClientConfig.CertPath; path != "" {
 		opts = append(opts, client.WithTLSClientConfig(
 			filepath.Join(path, "ca.pem"),
@@ -381,6 +394,9 @@ type PlatformConfig struct {
 	// DOCKER_CERT_PATH to load the TLS certificates from.
 	// DOCKER_TLS_VERIFY to enable or disable TLS verification, off by default.
 	ClientConfig *ClientConfig `hcl:"client_config,block"`
+
+	// Podman runs the container with Podman instead of Docker.
+	Podman bool `hcl:"podman,optional"`
 }
 
 type ClientConfig struct {
@@ -471,6 +487,18 @@ deploy {
 		),
 	)
 
+	doc.SetField(
+		"podman",
+		"run the container with Podman through its Docker compatible API socket",
+		docs.Summary(
+			"the socket of the current user's rootless Podman is used if it",
+			"exists, and the socket of the system Podman otherwise. Podman is",
+			"also used without this when DOCKER_HOST isn't set and there is no",
+			"Docker daemon socket.",
+		),
+		docs.Default("false"),
+	)
+
 	return doc, nil
 }
 
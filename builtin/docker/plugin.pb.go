@@ -1,189 +1,316 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.23.0
+// 	protoc        v3.11.2
 // source: waypoint/builtin/docker/plugin.proto
 
 package docker
 
 import (
-	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
-	math "math"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
 )
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ = proto.Marshal
-var _ = fmt.Errorf
-var _ = math.Inf
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the proto package it is being compiled against.
-// A compilation error at this line likely means your copy of the
-// proto package needs to be updated.
-const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
 
 // Image is the artifact type for the registry.
 type Image struct {
-	Image                string   `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
-	Tag                  string   `protobuf:"bytes,2,opt,name=tag,proto3" json:"tag,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func (m *Image) Reset()         { *m = Image{} }
-func (m *Image) String() string { return proto.CompactTextString(m) }
-func (*Image) ProtoMessage()    {}
-func (*Image) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2440d536b1a2bd07, []int{0}
+	Image string `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+	Tag   string `protobuf:"bytes,2,opt,name=tag,proto3" json:"tag,omitempty"`
+	// platforms is set when the image is built for multiple platforms, and
+	// lists the platforms in the form os/arch[/variant]. Each platform's
+	// image is tagged with the tag suffixed by the platform, and the tag
+	// itself is the image for the first platform locally and a manifest
+	// list for all platforms once pushed.
+	Platforms []string `protobuf:"bytes,3,rep,name=platforms,proto3" json:"platforms,omitempty"`
 }
 
-func (m *Image) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Image.Unmarshal(m, b)
-}
-func (m *Image) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Image.Marshal(b, m, deterministic)
-}
-func (m *Image) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Image.Merge(m, src)
+func (x *Image) Reset() {
+	*x = Image{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_waypoint_builtin_docker_plugin_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *Image) XXX_Size() int {
-	return xxx_messageInfo_Image.Size(m)
+
+func (x *Image) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *Image) XXX_DiscardUnknown() {
-	xxx_messageInfo_Image.DiscardUnknown(m)
+
+func (*Image) ProtoMessage() {}
+
+func (x *Image) ProtoReflect() protoreflect.Message {
+	mi := &file_waypoint_builtin_docker_plugin_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_Image proto.InternalMessageInfo
+// Deprecated: Use Image.ProtoReflect.Descriptor instead.
+func (*Image) Descriptor() ([]byte, []int) {
+	return file_waypoint_builtin_docker_plugin_proto_rawDescGZIP(), []int{0}
+}
 
-func (m *Image) GetImage() string {
-	if m != nil {
-		return m.Image
+func (x *Image) GetImage() string {
+	if x != nil {
+		return x.Image
 	}
 	return ""
 }
 
-func (m *Image) GetTag() string {
-	if m != nil {
-		return m.Tag
+func (x *Image) GetTag() string {
+	if x != nil {
+		return x.Tag
 	}
 	return ""
 }
 
-type Deployment struct {
-	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Container            string   `protobuf:"bytes,3,opt,name=container,proto3" json:"container,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (x *Image) GetPlatforms() []string {
+	if x != nil {
+		return x.Platforms
+	}
+	return nil
 }
 
-func (m *Deployment) Reset()         { *m = Deployment{} }
-func (m *Deployment) String() string { return proto.CompactTextString(m) }
-func (*Deployment) ProtoMessage()    {}
-func (*Deployment) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2440d536b1a2bd07, []int{1}
-}
+type Deployment struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func (m *Deployment) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Deployment.Unmarshal(m, b)
-}
-func (m *Deployment) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Deployment.Marshal(b, m, deterministic)
+	Id        string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name      string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Container string `protobuf:"bytes,3,opt,name=container,proto3" json:"container,omitempty"`
 }
-func (m *Deployment) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Deployment.Merge(m, src)
+
+func (x *Deployment) Reset() {
+	*x = Deployment{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_waypoint_builtin_docker_plugin_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *Deployment) XXX_Size() int {
-	return xxx_messageInfo_Deployment.Size(m)
+
+func (x *Deployment) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *Deployment) XXX_DiscardUnknown() {
-	xxx_messageInfo_Deployment.DiscardUnknown(m)
+
+func (*Deployment) ProtoMessage() {}
+
+func (x *Deployment) ProtoReflect() protoreflect.Message {
+	mi := &file_waypoint_builtin_docker_plugin_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_Deployment proto.InternalMessageInfo
+// Deprecated: Use Deployment.ProtoReflect.Descriptor instead.
+func (*Deployment) Descriptor() ([]byte, []int) {
+	return file_waypoint_builtin_docker_plugin_proto_rawDescGZIP(), []int{1}
+}
 
-func (m *Deployment) GetId() string {
-	if m != nil {
-		return m.Id
+func (x *Deployment) GetId() string {
+	if x != nil {
+		return x.Id
 	}
 	return ""
 }
 
-func (m *Deployment) GetName() string {
-	if m != nil {
-		return m.Name
+func (x *Deployment) GetName() string {
+	if x != nil {
+		return x.Name
 	}
 	return ""
 }
 
-func (m *Deployment) GetContainer() string {
-	if m != nil {
-		return m.Container
+func (x *Deployment) GetContainer() string {
+	if x != nil {
+		return x.Container
 	}
 	return ""
 }
 
 type Release struct {
-	Url                  string   `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func (m *Release) Reset()         { *m = Release{} }
-func (m *Release) String() string { return proto.CompactTextString(m) }
-func (*Release) ProtoMessage()    {}
-func (*Release) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2440d536b1a2bd07, []int{2}
+	Url string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
 }
 
-func (m *Release) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Release.Unmarshal(m, b)
-}
-func (m *Release) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Release.Marshal(b, m, deterministic)
-}
-func (m *Release) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Release.Merge(m, src)
+func (x *Release) Reset() {
+	*x = Release{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_waypoint_builtin_docker_plugin_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *Release) XXX_Size() int {
-	return xxx_messageInfo_Release.Size(m)
+
+func (x *Release) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *Release) XXX_DiscardUnknown() {
-	xxx_messageInfo_Release.DiscardUnknown(m)
+
+func (*Release) ProtoMessage() {}
+
+func (x *Release) ProtoReflect() protoreflect.Message {
+	mi := &file_waypoint_builtin_docker_plugin_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_Release proto.InternalMessageInfo
+// Deprecated: Use Release.ProtoReflect.Descriptor instead.
+func (*Release) Descriptor() ([]byte, []int) {
+	return file_waypoint_builtin_docker_plugin_proto_rawDescGZIP(), []int{2}
+}
 
-func (m *Release) GetUrl() string {
-	if m != nil {
-		return m.Url
+func (x *Release) GetUrl() string {
+	if x != nil {
+		return x.Url
 	}
 	return ""
 }
 
-func init() {
-	proto.RegisterType((*Image)(nil), "docker.Image")
-	proto.RegisterType((*Deployment)(nil), "docker.Deployment")
-	proto.RegisterType((*Release)(nil), "docker.Release")
+var File_waypoint_builtin_docker_plugin_proto protoreflect.FileDescriptor
+
+var file_waypoint_builtin_docker_plugin_proto_rawDesc = []byte{
+	0x0a, 0x24, 0x77, 0x61, 0x79, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x74,
+	0x69, 0x6e, 0x2f, 0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x2f, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06, 0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x22, 0x4d,
+	0x0a, 0x05, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x10, 0x0a,
+	0x03, 0x74, 0x61, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x74, 0x61, 0x67, 0x12,
+	0x1c, 0x0a, 0x09, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x09, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x73, 0x22, 0x4e, 0x0a,
+	0x0a, 0x44, 0x65, 0x70, 0x6c, 0x6f, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x1c, 0x0a, 0x09, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x22, 0x1b, 0x0a,
+	0x07, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x42, 0x19, 0x5a, 0x17, 0x77, 0x61,
+	0x79, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x74, 0x69, 0x6e, 0x2f, 0x64,
+	0x6f, 0x63, 0x6b, 0x65, 0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
-func init() {
-	proto.RegisterFile("waypoint/builtin/docker/plugin.proto", fileDescriptor_2440d536b1a2bd07)
+var (
+	file_waypoint_builtin_docker_plugin_proto_rawDescOnce sync.Once
+	file_waypoint_builtin_docker_plugin_proto_rawDescData = file_waypoint_builtin_docker_plugin_proto_rawDesc
+)
+
+func file_waypoint_builtin_docker_plugin_proto_rawDescGZIP() []byte {
+	file_waypoint_builtin_docker_plugin_proto_rawDescOnce.Do(func() {
+		file_waypoint_builtin_docker_plugin_proto_rawDescData = protoimpl.X.CompressGZIP(file_waypoint_builtin_docker_plugin_proto_rawDescData)
+	})
+	return file_waypoint_builtin_docker_plugin_proto_rawDescData
 }
 
-var fileDescriptor_2440d536b1a2bd07 = []byte{
-	// 189 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x52, 0x29, 0x4f, 0xac, 0x2c,
-	0xc8, 0xcf, 0xcc, 0x2b, 0xd1, 0x4f, 0x2a, 0xcd, 0xcc, 0x29, 0xc9, 0xcc, 0xd3, 0x4f, 0xc9, 0x4f,
-	0xce, 0x4e, 0x2d, 0xd2, 0x2f, 0xc8, 0x29, 0x4d, 0xcf, 0xcc, 0xd3, 0x2b, 0x28, 0xca, 0x2f, 0xc9,
-	0x17, 0x62, 0x83, 0x08, 0x2a, 0xe9, 0x73, 0xb1, 0x7a, 0xe6, 0x26, 0xa6, 0xa7, 0x0a, 0x89, 0x70,
-	0xb1, 0x66, 0x82, 0x18, 0x12, 0x8c, 0x0a, 0x8c, 0x1a, 0x9c, 0x41, 0x10, 0x8e, 0x90, 0x00, 0x17,
-	0x73, 0x49, 0x62, 0xba, 0x04, 0x13, 0x58, 0x0c, 0xc4, 0x54, 0xf2, 0xe3, 0xe2, 0x72, 0x49, 0x2d,
-	0xc8, 0xc9, 0xaf, 0xcc, 0x4d, 0xcd, 0x2b, 0x11, 0xe2, 0xe3, 0x62, 0xca, 0x4c, 0x81, 0x6a, 0x61,
-	0xca, 0x4c, 0x11, 0x12, 0xe2, 0x62, 0xc9, 0x4b, 0xcc, 0x4d, 0x85, 0x6a, 0x00, 0xb3, 0x85, 0x64,
-	0xb8, 0x38, 0x93, 0xf3, 0xf3, 0x4a, 0x12, 0x33, 0xf3, 0x52, 0x8b, 0x24, 0x98, 0xc1, 0x12, 0x08,
-	0x01, 0x25, 0x69, 0x2e, 0xf6, 0xa0, 0xd4, 0x9c, 0xd4, 0xc4, 0x62, 0xb0, 0x65, 0xa5, 0x45, 0x39,
-	0x50, 0xd3, 0x40, 0x4c, 0x27, 0xc9, 0x28, 0x71, 0x1c, 0xbe, 0x49, 0x62, 0x03, 0xfb, 0xc3, 0x18,
-	0x10, 0x00, 0x00, 0xff, 0xff, 0xe4, 0x69, 0xcd, 0x4f, 0xef, 0x00, 0x00, 0x00,
+var file_waypoint_builtin_docker_plugin_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_waypoint_builtin_docker_plugin_proto_goTypes = []interface{}{
+	(*Image)(nil),      // 0: docker.Image
+	(*Deployment)(nil), // 1: docker.Deployment
+	(*Release)(nil),    // 2: docker.Release
+}
+var file_waypoint_builtin_docker_plugin_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_waypoint_builtin_docker_plugin_proto_init() }
+func file_waypoint_builtin_docker_plugin_proto_init() {
+	if File_waypoint_builtin_docker_plugin_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_waypoint_builtin_docker_plugin_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Image); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_waypoint_builtin_docker_plugin_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Deployment); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_waypoint_builtin_docker_plugin_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Release); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_waypoint_builtin_docker_plugin_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_waypoint_builtin_docker_plugin_proto_goTypes,
+		DependencyIndexes: file_waypoint_builtin_docker_plugin_proto_depIdxs,
+		MessageInfos:      file_waypoint_builtin_docker_plugin_proto_msgTypes,
+	}.Build()
+	File_waypoint_builtin_docker_plugin_proto = out.File
+	file_waypoint_builtin_docker_plugin_proto_rawDesc = nil
+	file_waypoint_builtin_docker_plugin_proto_goTypes = nil
+	file_waypoint_builtin_docker_plugin_proto_depIdxs = nil
 }
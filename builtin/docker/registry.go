@@ -1,18 +1,13 @@
 package docker
 
 import (
-	"bytes"
 	"context"
-	"encoding/base64"
-	"encoding/json"
 	"os"
 
-	"github.com/docker/cli/cli/config"
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/jsonmessage"
-	"github.com/docker/docker/registry"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/waypoint-plugin-sdk/docs"
 	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
@@ -60,12 +55,19 @@ func (r *Registry) Push(
 
 	step.Update("Tagging Docker image: %s => %s:%s", img.Name(), r.config.Image, r.config.Tag)
 
-	target := &Image{Image: r.config.Image, Tag: r.config.Tag}
+	target := &Image{Image: r.config.Image, Tag: r.config.Tag, Platforms: img.Platforms}
 	err = cli.ImageTag(ctx, img.Name(), target.Name())
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "unable to tag image:%s", err)
 	}
 
+	for _, platform := range img.Platforms {
+		err = cli.ImageTag(ctx, img.PlatformName(platform), target.PlatformName(platform))
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "unable to tag image:%s", err)
+		}
+	}
+
 	step.Done()
 
 	if r.config.Local {
@@ -79,69 +81,86 @@ func (r *Registry) Push(
 
 	encodedAuth := r.config.EncodedAuth
 	if encodedAuth == "" {
-		// Resolve the Repository name from fqn to RepositoryInfo
-		repoInfo, err := registry.ParseRepositoryInfo(ref)
+		encodedAuth, err = RegistryAuth(ctx, log, cli, ref)
 		if err != nil {
-			return nil, status.Errorf(codes.Internal, "unable to parse repository info from image name: %s", err)
+			return nil, err
 		}
+	}
 
-		var server string
+	var termFd uintptr
+	if f, ok := stdout.(*os.File); ok {
+		termFd = f.Fd()
+	}
 
-		if repoInfo.Index.Official {
-			info, err := cli.Info(ctx)
-			if err != nil || info.IndexServerAddress == "" {
-				server = registry.IndexServer
-			} else {
-				server = info.IndexServerAddress
+	// An image built for multiple platforms is pushed as the image of each
+	// platform, and then a manifest list of them under the tag itself.
+	if len(target.Platforms) > 0 {
+		for _, platform := range target.Platforms {
+			ref, err := reference.ParseNormalizedNamed(target.PlatformName(platform))
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "unable to parse image name: %s", err)
 			}
-		} else {
-			server = repoInfo.Index.Name
-		}
 
-		var errBuf bytes.Buffer
-		cf := config.LoadDefaultConfigFile(&errBuf)
-		if errBuf.Len() > 0 {
-			// NOTE(mitchellh): I don't know why we ignore this, but we always have.
-			log.Warn("error loading Docker config file", "err", err)
+			step = sg.Add("Pushing Docker image for %s...", platform)
+			if err := pushImage(ctx, cli, ref, encodedAuth, step, termFd); err != nil {
+				return nil, err
+			}
+
+			step.Done()
 		}
 
-		authConfig, _ := cf.GetAuthConfig(server)
-		buf, err := json.Marshal(authConfig)
+		step = sg.Add("Pushing manifest list...")
+		dgst, err := PushManifestList(ctx, target, encodedAuth)
 		if err != nil {
-			return nil, status.Errorf(codes.Internal, "unable to generate authentication info for registry: %s", err)
+			return nil, err
 		}
-		encodedAuth = base64.URLEncoding.EncodeToString(buf)
+
+		step.Update("Manifest list pushed: %s:%s@%s", r.config.Image, r.config.Tag, dgst)
+		step.Done()
+
+		return target, nil
 	}
 
 	step = sg.Add("Pushing Docker image...")
+	if err := pushImage(ctx, cli, ref, encodedAuth, step, termFd); err != nil {
+		return nil, err
+	}
+
+	step.Done()
 
+	step = sg.Add("Docker image pushed: %s:%s", r.config.Image, r.config.Tag)
+	step.Done()
+
+	return target, nil
+}
+
+// pushImage pushes the image with the given name, streaming the push
+// progress to the step.
+func pushImage(
+	ctx context.Context,
+	cli *client.Client,
+	ref reference.Named,
+	encodedAuth string,
+	step terminal.Step,
+	termFd uintptr,
+) error {
 	options := types.ImagePushOptions{
 		RegistryAuth: encodedAuth,
 	}
 
 	responseBody, err := cli.ImagePush(ctx, reference.FamiliarString(ref), options)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "unable to push image to registry: %s", err)
+		return status.Errorf(codes.Internal, "unable to push image to registry: %s", err)
 	}
 
 	defer responseBody.Close()
 
-	var termFd uintptr
-	if f, ok := stdout.(*os.File); ok {
-		termFd = f.Fd()
-	}
-
 	err = jsonmessage.DisplayJSONMessagesStream(responseBody, step.TermOutput(), termFd, true, nil)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "unable to stream Docker logs to terminal: %s", err)
+		return status.Errorf(codes.Internal, "unable to stream Docker logs to terminal: %s", err)
 	}
 
-	step.Done()
-
-	step = sg.Add("Docker image pushed: %s:%s", r.config.Image, r.config.Tag)
-	step.Done()
-
-	return target, nil
+	return nil
 }
 
 // Config is the configuration structure for the registry.
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 
 	"github.com/hashicorp/go-hclog"
 	run "google.golang.org/api/run/v1"
@@ -55,14 +56,24 @@ func (r *Releaser) Release(
 		return nil, status.Errorf(codes.Aborted, "Unable to fetch service information from Google Cloud: %s", err.Error())
 	}
 
-	// Update the service with the traffic info. This code is laid out this
-	// way to make it more trivial in the future to add traffic splitting.
-	service.Spec.Traffic = nil
-	log.Debug("Setting traffic target", "revision", target.RevisionId, "percent", 100)
-	service.Spec.Traffic = append(service.Spec.Traffic, &run.TrafficTarget{
-		RevisionName: target.RevisionId,
-		Percent:      100,
-	})
+	// Update the service with the traffic info. The status has the
+	// revisions that currently receive traffic resolved by name.
+	percent := int64(r.config.Percent)
+	if percent == 0 {
+		percent = 100
+	}
+	var current []*run.TrafficTarget
+	if service.Status != nil {
+		current = service.Status.Traffic
+	}
+	traffic, err := trafficTargets(current, target.RevisionId, percent)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, err.Error())
+	}
+	for _, t := range traffic {
+		log.Debug("Setting traffic target", "revision", t.RevisionName, "percent", t.Percent)
+	}
+	service.Spec.Traffic = traffic
 
 	// Replace the service
 	st.Update("Deploying routing changes")
@@ -98,6 +109,79 @@ func (r *Releaser) Release(
 	}, nil
 }
 
+// trafficTargets returns the traffic targets that send percent of the
+// traffic to revision. The rest of the traffic is split between the
+// revisions that currently receive traffic, in proportion to what they
+// receive now.
+func trafficTargets(
+	current []*run.TrafficTarget,
+	revision string,
+	percent int64,
+) ([]*run.TrafficTarget, error) {
+	if percent < 1 || percent > 100 {
+		return nil, fmt.Errorf("percent must be between 1 and 100, got %d", percent)
+	}
+
+	result := []*run.TrafficTarget{
+		{
+			RevisionName: revision,
+			Percent:      percent,
+		},
+	}
+	remaining := 100 - percent
+	if remaining == 0 {
+		return result, nil
+	}
+
+	// The revisions that currently receive traffic, besides the one being
+	// released, in the order that they are listed.
+	var others []*run.TrafficTarget
+	idx := map[string]*run.TrafficTarget{}
+	var total int64
+	for _, t := range current {
+		if t.RevisionName == "" || t.RevisionName == revision || t.Percent <= 0 {
+			continue
+		}
+
+		total += t.Percent
+		if o, ok := idx[t.RevisionName]; ok {
+			o.Percent += t.Percent
+			continue
+		}
+
+		o := &run.TrafficTarget{
+			RevisionName: t.RevisionName,
+			Percent:      t.Percent,
+		}
+		idx[t.RevisionName] = o
+		others = append(others, o)
+	}
+	if total == 0 {
+		return nil, fmt.Errorf(
+			"no other revision receives traffic, so %s must receive 100 percent", revision)
+	}
+
+	// Percentages must be whole numbers that add up to 100. What is lost by
+	// rounding down goes to the revisions in order.
+	var assigned int64
+	for _, o := range others {
+		o.Percent = o.Percent * remaining / total
+		assigned += o.Percent
+	}
+	for i := 0; assigned < remaining; i++ {
+		others[i%len(others)].Percent++
+		assigned++
+	}
+
+	for _, o := range others {
+		if o.Percent > 0 {
+			result = append(result, o)
+		}
+	}
+
+	return result, nil
+}
+
 // setNoAuthPolicy sets the IAM policy on the deployment so that anyone
 // can access it (no auth required).
 func (r *Releaser) setNoAuthPolicy(
@@ -124,7 +208,10 @@ func (r *Releaser) setNoAuthPolicy(
 }
 
 // ReleaserConfig is the configuration structure for the Releaser.
-type ReleaserConfig struct{}
+type ReleaserConfig struct {
+	// Percent of the traffic to send to the released revision. Default 100.
+	Percent int `hcl:"percent,optional"`
+}
 
 func (r *Releaser) Documentation() (*docs.Documentation, error) {
 	doc, err := docs.New(docs.FromConfig(&ReleaserConfig{}))
@@ -134,6 +221,26 @@ func (r *Releaser) Documentation() (*docs.Documentation, error) {
 
 	doc.Description("Manipulates the Cloud Run APIs to make deployments active")
 
+	doc.Example(`
+release {
+  use "google-cloud-run" {
+    percent = 10
+  }
+}
+`)
+
+	doc.SetField(
+		"percent",
+		"the percent of the traffic to send to the released revision",
+		docs.Default("100"),
+		docs.Summary(
+			"the rest of the traffic is split between the revisions that receive",
+			"traffic before the release, in proportion to what they receive.",
+			"releasing the same deployment again with a higher percent shifts",
+			"more traffic to it.",
+		),
+	)
+
 	return doc, nil
 }
 
@@ -0,0 +1,102 @@
+package cloudrun
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	run "google.golang.org/api/run/v1"
+)
+
+func TestTrafficTargets(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Current  []*run.TrafficTarget
+		Percent  int64
+		Expected map[string]int64
+		Err      bool
+	}{
+		{
+			"all traffic",
+			[]*run.TrafficTarget{{RevisionName: "a", Percent: 100}},
+			100,
+			map[string]int64{"new": 100},
+			false,
+		},
+
+		{
+			"split with one revision",
+			[]*run.TrafficTarget{{RevisionName: "a", Percent: 100}},
+			10,
+			map[string]int64{"new": 10, "a": 90},
+			false,
+		},
+
+		{
+			"split in proportion",
+			[]*run.TrafficTarget{
+				{RevisionName: "a", Percent: 75},
+				{RevisionName: "b", Percent: 25},
+			},
+			20,
+			map[string]int64{"new": 20, "a": 60, "b": 20},
+			false,
+		},
+
+		{
+			"rounding",
+			[]*run.TrafficTarget{
+				{RevisionName: "a", Percent: 50},
+				{RevisionName: "b", Percent: 50},
+			},
+			25,
+			map[string]int64{"new": 25, "a": 38, "b": 37},
+			false,
+		},
+
+		{
+			"shift more to the same revision",
+			[]*run.TrafficTarget{
+				{RevisionName: "new", Percent: 10},
+				{RevisionName: "a", Percent: 90},
+			},
+			50,
+			map[string]int64{"new": 50, "a": 50},
+			false,
+		},
+
+		{
+			"no other revision",
+			[]*run.TrafficTarget{{RevisionName: "new", Percent: 100}},
+			50,
+			nil,
+			true,
+		},
+
+		{
+			"invalid percent",
+			nil,
+			101,
+			nil,
+			true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			require := require.New(t)
+
+			result, err := trafficTargets(tt.Current, "new", tt.Percent)
+			if tt.Err {
+				require.Error(err)
+				return
+			}
+			require.NoError(err)
+
+			actual := map[string]int64{}
+			for _, t := range result {
+				actual[t.RevisionName] = t.Percent
+			}
+			require.Equal(tt.Expected, actual)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package helm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// helmRevision is a revision of a release in the output of "helm history".
+type helmRevision struct {
+	Revision    int32  `json:"revision"`
+	Status      string `json:"status"`
+	Description string `json:"description"`
+}
+
+// helm runs the helm CLI with args in namespace and returns its output.
+func (p *Platform) helm(
+	ctx context.Context,
+	log hclog.Logger,
+	namespace string,
+	args ...string,
+) ([]byte, error) {
+	if namespace != "" {
+		args = append(args, "--namespace", namespace)
+	}
+	if p.config.KubeconfigPath != "" {
+		args = append(args, "--kubeconfig", p.config.KubeconfigPath)
+	}
+	if p.config.Context != "" {
+		args = append(args, "--kube-context", p.config.Context)
+	}
+
+	// Only the command is logged since values may be sensitive.
+	log.Debug("running helm", "command", args[0])
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "helm", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, &helmError{
+			Command: args[0],
+			Err:     err,
+			Stderr:  strings.TrimSpace(stderr.String()),
+		}
+	}
+
+	return out, nil
+}
+
+// history returns the revisions of release, or nil if it isn't installed.
+func (p *Platform) history(
+	ctx context.Context,
+	log hclog.Logger,
+	release, namespace string,
+) ([]helmRevision, error) {
+	out, err := p.helm(ctx, log, namespace, "history", release, "--output", "json")
+	if herr, ok := err.(*helmError); ok && strings.Contains(herr.Stderr, "not found") {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var result []helmRevision
+	if err := json.Unmarshal(out, &result); err != nil {
+		return nil, fmt.Errorf("error parsing helm history: %s", err)
+	}
+
+	return result, nil
+}
+
+// deployedRevision returns the revision of a release that is deployed, or
+// 0 if none is.
+func deployedRevision(history []helmRevision) int32 {
+	var result int32
+	for _, r := range history {
+		if r.Status == "deployed" && r.Revision > result {
+			result = r.Revision
+		}
+	}
+
+	return result
+}
+
+// effectiveRevision returns the revision that revision is a copy of. Helm
+// rolls back by creating a new revision that copies an older one, so the
+// deployed revision of a rolled back release is a copy of a revision that
+// Waypoint created.
+func effectiveRevision(history []helmRevision, revision int32) int32 {
+	descs := map[int32]string{}
+	for _, r := range history {
+		descs[r.Revision] = r.Description
+	}
+
+	seen := map[int32]bool{}
+	for !seen[revision] {
+		seen[revision] = true
+
+		var target int32
+		if _, err := fmt.Sscanf(descs[revision], "Rollback to %d", &target); err != nil {
+			break
+		}
+		revision = target
+	}
+
+	return revision
+}
+
+// helmError is returned when the helm CLI fails.
+type helmError struct {
+	Command string
+	Err     error
+	Stderr  string
+}
+
+func (e *helmError) Error() string {
+	if e.Stderr == "" {
+		return fmt.Sprintf("helm %s failed: %s", e.Command, e.Err)
+	}
+
+	return fmt.Sprintf("helm %s failed: %s", e.Command, e.Stderr)
+}
@@ -0,0 +1,43 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeployedRevision(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal(int32(0), deployedRevision(nil))
+	require.Equal(int32(0), deployedRevision([]helmRevision{
+		{Revision: 1, Status: "failed"},
+	}))
+	require.Equal(int32(2), deployedRevision([]helmRevision{
+		{Revision: 1, Status: "superseded"},
+		{Revision: 2, Status: "deployed"},
+		{Revision: 3, Status: "failed"},
+	}))
+}
+
+func TestEffectiveRevision(t *testing.T) {
+	require := require.New(t)
+
+	history := []helmRevision{
+		{Revision: 1, Description: "Install complete"},
+		{Revision: 2, Description: "Upgrade complete"},
+		{Revision: 3, Description: "Rollback to 1"},
+		{Revision: 4, Description: "Rollback to 3"},
+	}
+
+	require.Equal(int32(2), effectiveRevision(history, 2))
+	require.Equal(int32(1), effectiveRevision(history, 3))
+	require.Equal(int32(1), effectiveRevision(history, 4))
+	require.Equal(int32(5), effectiveRevision(history, 5))
+
+	// Loops can't happen, but shouldn't hang
+	require.Equal(int32(6), effectiveRevision([]helmRevision{
+		{Revision: 6, Description: "Rollback to 7"},
+		{Revision: 7, Description: "Rollback to 6"},
+	}, 6))
+}
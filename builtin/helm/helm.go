@@ -0,0 +1,13 @@
+// Package helm contains a platform that deploys to Kubernetes by
+// installing or upgrading a Helm chart.
+package helm
+
+import "github.com/hashicorp/waypoint-plugin-sdk"
+
+//go:generate protoc -I ../../.. --go_opt=plugins=grpc --go_out=../../.. waypoint/builtin/helm/plugin.proto
+
+// Options are the SDK options to use for instantiation for
+// the Helm plugin.
+var Options = []sdk.Option{
+	sdk.WithComponents(&Platform{}),
+}
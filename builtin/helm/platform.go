@@ -0,0 +1,350 @@
+package helm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/waypoint-plugin-sdk/component"
+	"github.com/hashicorp/waypoint-plugin-sdk/docs"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/builtin/docker"
+)
+
+const (
+	// DefaultImageValue is the chart value that the image repository is set
+	// to if none is configured.
+	DefaultImageValue = "image.repository"
+
+	// DefaultTagValue is the chart value that the image tag is set to if
+	// none is configured.
+	DefaultTagValue = "image.tag"
+)
+
+// Platform is the Platform implementation for Helm.
+type Platform struct {
+	config Config
+}
+
+// Config implements Configurable
+func (p *Platform) Config() (interface{}, error) {
+	return &p.config, nil
+}
+
+// DeployFunc implements component.Platform
+func (p *Platform) DeployFunc() interface{} {
+	return p.Deploy
+}
+
+// DestroyFunc implements component.Destroyer
+func (p *Platform) DestroyFunc() interface{} {
+	return p.Destroy
+}
+
+// Deploy installs the chart, or upgrades the release if it is already
+// installed, with the image set in the chart values. Each deployment is a
+// revision of the release.
+func (p *Platform) Deploy(
+	ctx context.Context,
+	log hclog.Logger,
+	src *component.Source,
+	img *docker.Image,
+	ui terminal.UI,
+) (*Deployment, error) {
+	release := p.config.Release
+	if release == "" {
+		release = src.App
+	}
+	imageValue := p.config.ImageValue
+	if imageValue == "" {
+		imageValue = DefaultImageValue
+	}
+	tagValue := p.config.TagValue
+	if tagValue == "" {
+		tagValue = DefaultTagValue
+	}
+
+	sg := ui.StepGroup()
+	defer sg.Wait()
+
+	step := sg.Add("Checking release %s...", release)
+	defer func() { step.Abort() }()
+
+	history, err := p.history(ctx, log, release, p.config.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	previous := deployedRevision(history)
+	step.Done()
+
+	// Local charts are relative to the application, but other charts can
+	// look like paths, such as "bitnami/nginx".
+	chart := p.config.Chart
+	if p.config.Repository == "" && !filepath.IsAbs(chart) {
+		if _, err := os.Stat(filepath.Join(src.Path, chart)); err == nil {
+			chart = filepath.Join(src.Path, chart)
+		}
+	}
+
+	// A failed upgrade is rolled back by helm, so a failed deployment
+	// leaves the release as it was.
+	args := []string{
+		"upgrade", release, chart,
+		"--install",
+		"--atomic",
+		"--output", "json",
+	}
+	if p.config.Repository != "" {
+		args = append(args, "--repo", p.config.Repository)
+	}
+	if p.config.Version != "" {
+		args = append(args, "--version", p.config.Version)
+	}
+	if p.config.Timeout != "" {
+		args = append(args, "--timeout", p.config.Timeout)
+	}
+	for _, v := range p.config.Values {
+		if !filepath.IsAbs(v) {
+			v = filepath.Join(src.Path, v)
+		}
+		args = append(args, "--values", v)
+	}
+
+	keys := make([]string, 0, len(p.config.Set))
+	for k := range p.config.Set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		args = append(args, "--set", k+"="+p.config.Set[k])
+	}
+
+	// The image is set last so that it can't be overridden by values.
+	args = append(args,
+		"--set-string", imageValue+"="+img.Image,
+		"--set-string", tagValue+"="+img.Tag,
+	)
+
+	step = sg.Add("Installing chart %s as release %s...", p.config.Chart, release)
+	out, err := p.helm(ctx, log, p.config.Namespace, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Version int32 `json:"version"`
+	}
+	if err := json.Unmarshal(out, &result); err != nil {
+		return nil, fmt.Errorf("error parsing helm output: %s", err)
+	}
+
+	step.Update("Release %s is at revision %d", release, result.Version)
+	step.Done()
+
+	return &Deployment{
+		Release:          release,
+		Namespace:        p.config.Namespace,
+		Revision:         result.Version,
+		PreviousRevision: previous,
+	}, nil
+}
+
+// Destroy rolls the release back to the revision that was deployed before
+// the deployment, or uninstalls it if there was none. If another revision
+// has been deployed since, the release is left as it is.
+func (p *Platform) Destroy(
+	ctx context.Context,
+	log hclog.Logger,
+	deployment *Deployment,
+	ui terminal.UI,
+) error {
+	sg := ui.StepGroup()
+	defer sg.Wait()
+
+	step := sg.Add("Checking release %s...", deployment.Release)
+	defer func() { step.Abort() }()
+
+	history, err := p.history(ctx, log, deployment.Release, deployment.Namespace)
+	if err != nil {
+		return err
+	}
+
+	current := effectiveRevision(history, deployedRevision(history))
+	if current != deployment.Revision {
+		step.Update("Release %s no longer runs revision %d, nothing to destroy",
+			deployment.Release, deployment.Revision)
+		step.Done()
+		return nil
+	}
+
+	if deployment.PreviousRevision == 0 {
+		step.Update("Uninstalling release %s...", deployment.Release)
+		_, err = p.helm(ctx, log, deployment.Namespace,
+			"uninstall", deployment.Release)
+	} else {
+		previous := effectiveRevision(history, deployment.PreviousRevision)
+		step.Update("Rolling back release %s to revision %d...", deployment.Release, previous)
+		_, err = p.helm(ctx, log, deployment.Namespace,
+			"rollback", deployment.Release, strconv.Itoa(int(previous)), "--wait")
+	}
+	if err != nil {
+		return err
+	}
+
+	step.Done()
+	return nil
+}
+
+// Config is the configuration structure for the Platform.
+type Config struct {
+	// Chart is the chart to install. This is a path to a chart directory
+	// or archive, a chart in a repository added with "helm repo add" such
+	// as "bitnami/nginx", or a chart name if Repository is set.
+	Chart string `hcl:"chart"`
+
+	// Repository is the URL of the chart repository to find the chart in.
+	Repository string `hcl:"repository,optional"`
+
+	// Version is the version of the chart. Defaults to the latest.
+	Version string `hcl:"version,optional"`
+
+	// Release is the name of the release. Defaults to the app name.
+	Release string `hcl:"release,optional"`
+
+	// Namespace is the Kubernetes namespace to install the release in.
+	Namespace string `hcl:"namespace,optional"`
+
+	// Values are paths to values files, relative to the app.
+	Values []string `hcl:"values,optional"`
+
+	// Set are chart values to set, such as "replicaCount" = "3".
+	Set map[string]string `hcl:"set,optional"`
+
+	// ImageValue and TagValue are the chart values that the repository
+	// and tag of the image are set to.
+	ImageValue string `hcl:"image_value,optional"`
+	TagValue   string `hcl:"tag_value,optional"`
+
+	// Timeout is how long to wait for the resources of the release to be
+	// ready, such as "10m". Defaults to the helm default.
+	Timeout string `hcl:"timeout,optional"`
+
+	// KubeconfigPath is the path to the kubeconfig file. If this is
+	// blank then we default to the home directory.
+	KubeconfigPath string `hcl:"kubeconfig,optional"`
+
+	// Context specifies the kube context to use.
+	Context string `hcl:"context,optional"`
+}
+
+func (p *Platform) Documentation() (*docs.Documentation, error) {
+	doc, err := docs.New(docs.FromConfig(&Config{}), docs.FromFunc(p.DeployFunc()))
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Description("Deploy to Kubernetes by installing or upgrading a Helm chart")
+
+	doc.Example(
+		`
+deploy {
+  use "helm" {
+    chart     = "./chart"
+    namespace = "production"
+
+    set = {
+      "replicaCount" = "3"
+    }
+  }
+}
+`)
+
+	doc.Input("docker.Image")
+	doc.Output("helm.Deployment")
+
+	doc.SetField(
+		"chart",
+		"the chart to install",
+		docs.Summary(
+			"this is a path to a chart directory or archive, relative to the",
+			"application, a chart in a repository added with \"helm repo add\"",
+			"such as \"bitnami/nginx\", or a chart name if repository is set",
+		),
+	)
+
+	doc.SetField(
+		"repository",
+		"the URL of the chart repository to find the chart in",
+	)
+
+	doc.SetField(
+		"version",
+		"the version of the chart to install",
+		docs.Default("the latest version"),
+	)
+
+	doc.SetField(
+		"release",
+		"the name of the Helm release",
+		docs.Default("the application name"),
+	)
+
+	doc.SetField(
+		"namespace",
+		"the Kubernetes namespace to install the release in",
+		docs.Default("the namespace of the kube context"),
+	)
+
+	doc.SetField(
+		"values",
+		"paths to values files to use, relative to the application",
+	)
+
+	doc.SetField(
+		"set",
+		"chart values to set, as with --set",
+	)
+
+	doc.SetField(
+		"image_value",
+		"the chart value that the repository of the built image is set to",
+		docs.Default(DefaultImageValue),
+	)
+
+	doc.SetField(
+		"tag_value",
+		"the chart value that the tag of the built image is set to",
+		docs.Default(DefaultTagValue),
+	)
+
+	doc.SetField(
+		"timeout",
+		"how long to wait for the resources of the release to be ready, such as 10m",
+		docs.Default("the helm default of 5m"),
+	)
+
+	doc.SetField(
+		"kubeconfig",
+		"path to the kubeconfig file to use",
+		docs.Summary("by default uses from current user's home directory"),
+	)
+
+	doc.SetField(
+		"context",
+		"the kubectl context to use, as defined in the kubeconfig file",
+	)
+
+	return doc, nil
+}
+
+var (
+	_ component.Platform     = (*Platform)(nil)
+	_ component.Configurable = (*Platform)(nil)
+	_ component.Destroyer    = (*Platform)(nil)
+	_ component.Documented   = (*Platform)(nil)
+)
@@ -11,6 +11,13 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 )
 
+// Clientset returns a K8S clientset, the configured namespace, and the
+// client configuration for other plugins that run in Kubernetes. An empty
+// kubeconfig path and context use the defaults.
+func Clientset(kubeconfig, context string) (*kubernetes.Clientset, string, *rest.Config, error) {
+	return clientset(kubeconfig, context)
+}
+
 // clientset returns a K8S clientset and configured namespace.
 func clientset(kubeconfig, context string) (*kubernetes.Clientset, string, *rest.Config, error) {
 	loader := clientcmd.NewDefaultClientConfigLoadingRules()
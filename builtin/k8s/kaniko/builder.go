@@ -0,0 +1,429 @@
+package kaniko
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/component"
+	"github.com/hashicorp/waypoint-plugin-sdk/docs"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/builtin/docker"
+	"github.com/hashicorp/waypoint/builtin/k8s"
+)
+
+const (
+	// DefaultImage is the Kaniko executor image to build with if none is
+	// configured.
+	DefaultImage = "gcr.io/kaniko-project/executor:v1.3.0"
+
+	// contextKey is the key of the build context in the ConfigMap that it
+	// is uploaded as, and its file name in the pod.
+	contextKey = "context.tar.gz"
+
+	// buildTimeout is how long a build may take.
+	buildTimeout = 30 * time.Minute
+)
+
+// Builder builds Docker images in a Kubernetes cluster with Kaniko.
+type Builder struct {
+	config BuilderConfig
+}
+
+// Config implements Configurable
+func (b *Builder) Config() (interface{}, error) {
+	return &b.config, nil
+}
+
+// BuildFunc implements component.Builder
+func (b *Builder) BuildFunc() interface{} {
+	return b.Build
+}
+
+// BuilderConfig is the configuration structure for the builder.
+type BuilderConfig struct {
+	// Image is the repository to push the built image to.
+	Image string `hcl:"image"`
+
+	// Tag is the tag of the built image. Defaults to "latest".
+	Tag string `hcl:"tag,optional"`
+
+	// Dockerfile is the path to the Dockerfile, relative to the
+	// application. Defaults to "Dockerfile".
+	Dockerfile string `hcl:"dockerfile,optional"`
+
+	// BuildContext is a remote Kaniko build context, such as a git
+	// repository or a tarball in a bucket. If this is not set, the
+	// application directory is uploaded.
+	BuildContext string `hcl:"build_context,optional"`
+
+	// BuildArgs are the build args to build with.
+	BuildArgs map[string]string `hcl:"build_args,optional"`
+
+	// DockerConfigSecret is the name of a Secret of type
+	// kubernetes.io/dockerconfigjson with the registry credentials.
+	DockerConfigSecret string `hcl:"docker_config_secret,optional"`
+
+	// KanikoImage is the Kaniko executor image to build with.
+	KanikoImage string `hcl:"kaniko_image,optional"`
+
+	// Namespace is the Kubernetes namespace to build in.
+	Namespace string `hcl:"namespace,optional"`
+
+	// KubeconfigPath is the path to the kubeconfig file. If this is
+	// blank then we default to the home directory.
+	KubeconfigPath string `hcl:"kubeconfig,optional"`
+
+	// Context specifies the kube context to use.
+	Context string `hcl:"context,optional"`
+}
+
+// Build runs Kaniko in a pod to build the image and push it to the
+// registry. The pod, and the ConfigMap that the build context is uploaded
+// as, are deleted once the build is done.
+func (b *Builder) Build(
+	ctx context.Context,
+	log hclog.Logger,
+	ui terminal.UI,
+	src *component.Source,
+) (*docker.Image, error) {
+	result := &docker.Image{
+		Image: b.config.Image,
+		Tag:   b.config.Tag,
+	}
+	if result.Tag == "" {
+		result.Tag = "latest"
+	}
+
+	sg := ui.StepGroup()
+	defer sg.Wait()
+
+	step := sg.Add("Initializing Kubernetes client...")
+	defer func() { step.Abort() }()
+
+	clientset, ns, config, err := k8s.Clientset(b.config.KubeconfigPath, b.config.Context)
+	if err != nil {
+		return nil, err
+	}
+	if b.config.Namespace != "" {
+		ns = b.config.Namespace
+	}
+	step.Update("Kubernetes client connected to %s with namespace %s", config.Host, ns)
+	step.Done()
+
+	id, err := component.Id()
+	if err != nil {
+		return nil, err
+	}
+	name := "waypoint-kaniko-" + strings.ToLower(id)
+
+	dockerfile := b.config.Dockerfile
+	if dockerfile == "" {
+		dockerfile = "Dockerfile"
+	}
+
+	contextURL := b.config.BuildContext
+	if contextURL == "" {
+		step = sg.Add("Uploading build context...")
+		if !filepath.IsAbs(dockerfile) {
+			dockerfile = filepath.Join(src.Path, dockerfile)
+		}
+
+		data, relDockerfile, err := buildContext(src.Path, dockerfile)
+		if err != nil {
+			return nil, status.Errorf(codes.FailedPrecondition, err.Error())
+		}
+		dockerfile = relDockerfile
+
+		cms := clientset.CoreV1().ConfigMaps(ns)
+		_, err = cms.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			BinaryData: map[string][]byte{contextKey: data},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "unable to upload build context: %s", err)
+		}
+		defer func() {
+			if err := cms.Delete(context.Background(), name, metav1.DeleteOptions{}); err != nil {
+				log.Warn("error deleting build context", "name", name, "err", err)
+			}
+		}()
+
+		contextURL = "tar:///workspace/" + contextKey
+		step.Update("Uploaded build context (%d bytes)", len(data))
+		step.Done()
+	}
+
+	step = sg.Add("Starting Kaniko pod %s...", name)
+	pods := clientset.CoreV1().Pods(ns)
+	_, err = pods.Create(ctx, b.pod(name, contextURL, dockerfile, result), metav1.CreateOptions{})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to create Kaniko pod: %s", err)
+	}
+	defer func() {
+		if err := pods.Delete(context.Background(), name, metav1.DeleteOptions{}); err != nil {
+			log.Warn("error deleting Kaniko pod", "name", name, "err", err)
+		}
+	}()
+	step.Done()
+
+	step = sg.Add("Building image %s...", result.Name())
+	if err := waitPod(ctx, clientset, ns, name, func(p *corev1.Pod) bool {
+		return p.Status.Phase != corev1.PodPending
+	}); err != nil {
+		return nil, err
+	}
+
+	// Follow the logs until the build finishes.
+	logs, err := pods.GetLogs(name, &corev1.PodLogOptions{Follow: true}).Stream(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to read Kaniko logs: %s", err)
+	}
+	_, err = io.Copy(step.TermOutput(), logs)
+	logs.Close()
+	if err != nil {
+		log.Warn("error reading Kaniko logs", "err", err)
+	}
+
+	var pod *corev1.Pod
+	if err := waitPod(ctx, clientset, ns, name, func(p *corev1.Pod) bool {
+		pod = p
+		return p.Status.Phase == corev1.PodSucceeded || p.Status.Phase == corev1.PodFailed
+	}); err != nil {
+		return nil, err
+	}
+	if pod.Status.Phase == corev1.PodFailed {
+		msg := pod.Status.Message
+		for _, cs := range pod.Status.ContainerStatuses {
+			if t := cs.State.Terminated; t != nil {
+				msg = fmt.Sprintf("exit code %d: %s", t.ExitCode, t.Reason)
+			}
+		}
+
+		return nil, status.Errorf(codes.Internal, "Kaniko build failed: %s", msg)
+	}
+
+	step.Update("Built and pushed image %s", result.Name())
+	step.Done()
+
+	return result, nil
+}
+
+// pod returns the pod that runs Kaniko to build the image.
+func (b *Builder) pod(name, contextURL, dockerfile string, img *docker.Image) *corev1.Pod {
+	kanikoImage := b.config.KanikoImage
+	if kanikoImage == "" {
+		kanikoImage = DefaultImage
+	}
+
+	container := corev1.Container{
+		Name:  "kaniko",
+		Image: kanikoImage,
+		Args:  kanikoArgs(contextURL, dockerfile, img.Name(), b.config.BuildArgs),
+	}
+
+	var volumes []corev1.Volume
+	if strings.HasPrefix(contextURL, "tar:///workspace/") {
+		volumes = append(volumes, corev1.Volume{
+			Name: "context",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: name},
+				},
+			},
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      "context",
+			MountPath: "/workspace",
+		})
+	}
+
+	if b.config.DockerConfigSecret != "" {
+		volumes = append(volumes, corev1.Volume{
+			Name: "docker-config",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: b.config.DockerConfigSecret,
+					Items: []corev1.KeyToPath{
+						{Key: corev1.DockerConfigJsonKey, Path: "config.json"},
+					},
+				},
+			},
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      "docker-config",
+			MountPath: "/kaniko/.docker",
+		})
+	}
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "waypoint",
+			},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers:    []corev1.Container{container},
+			Volumes:       volumes,
+		},
+	}
+}
+
+// kanikoArgs returns the arguments of the Kaniko executor.
+func kanikoArgs(contextURL, dockerfile, destination string, buildArgs map[string]string) []string {
+	args := []string{
+		"--context=" + contextURL,
+		"--dockerfile=" + dockerfile,
+		"--destination=" + destination,
+	}
+
+	keys := make([]string, 0, len(buildArgs))
+	for k := range buildArgs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		args = append(args, "--build-arg="+k+"="+buildArgs[k])
+	}
+
+	return args
+}
+
+// waitPod waits until done returns true for the pod named name.
+func waitPod(
+	ctx context.Context,
+	clientset *kubernetes.Clientset,
+	ns, name string,
+	done func(*corev1.Pod) bool,
+) error {
+	err := wait.PollImmediate(2*time.Second, buildTimeout, func() (bool, error) {
+		pod, err := clientset.CoreV1().Pods(ns).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		return done(pod), nil
+	})
+	if err == wait.ErrWaitTimeout {
+		err = fmt.Errorf("Kaniko pod %s didn't finish after %s", name, buildTimeout)
+	}
+
+	return err
+}
+
+func (b *Builder) Documentation() (*docs.Documentation, error) {
+	doc, err := docs.New(docs.FromConfig(&BuilderConfig{}), docs.FromFunc(b.BuildFunc()))
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Description(`
+Build a Docker image inside a Kubernetes cluster with Kaniko
+
+The image is built in a pod and pushed to the registry by Kaniko, so no
+Docker daemon or privileged access is needed. The image is already pushed
+once it is built, so no registry needs to be configured. The Waypoint
+entrypoint isn't injected into the image.
+`)
+
+	doc.Example(`
+build {
+  use "kaniko" {
+    image                = "registry.example.com/my-app"
+    tag                  = "1.0.0"
+    docker_config_secret = "registry-credentials"
+  }
+}
+`)
+
+	doc.Input("component.Source")
+	doc.Output("docker.Image")
+
+	doc.SetField(
+		"image",
+		"the repository to push the built image to",
+	)
+
+	doc.SetField(
+		"tag",
+		"the tag of the built image",
+		docs.Default("latest"),
+	)
+
+	doc.SetField(
+		"dockerfile",
+		"the path to the Dockerfile",
+		docs.Default("Dockerfile"),
+		docs.Summary(
+			"this is relative to the application, or to build_context if it is set",
+		),
+	)
+
+	doc.SetField(
+		"build_context",
+		"a remote Kaniko build context, such as a git repository or a tarball in a bucket",
+		docs.Summary(
+			"for example git://github.com/org/repo.git#refs/heads/main or",
+			"s3://bucket/context.tar.gz. If this is not set, the application",
+			"directory is uploaded, which is limited to 1MB compressed.",
+		),
+	)
+
+	doc.SetField(
+		"build_args",
+		"build args to pass to the Dockerfile",
+	)
+
+	doc.SetField(
+		"docker_config_secret",
+		"the name of a Secret of type kubernetes.io/dockerconfigjson with the registry credentials",
+		docs.Summary(
+			"kubectl create secret docker-registry creates such a Secret",
+		),
+	)
+
+	doc.SetField(
+		"kaniko_image",
+		"the Kaniko executor image to build with",
+		docs.Default(DefaultImage),
+	)
+
+	doc.SetField(
+		"namespace",
+		"the Kubernetes namespace to build in",
+		docs.Default("the namespace of the kube context"),
+	)
+
+	doc.SetField(
+		"kubeconfig",
+		"path to the kubeconfig file to use",
+		docs.Summary("by default uses from current user's home directory"),
+	)
+
+	doc.SetField(
+		"context",
+		"the kubectl context to use, as defined in the kubeconfig file",
+	)
+
+	return doc, nil
+}
+
+var (
+	_ component.Builder      = (*Builder)(nil)
+	_ component.Configurable = (*Builder)(nil)
+	_ component.Documented   = (*Builder)(nil)
+)
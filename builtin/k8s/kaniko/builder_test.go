@@ -0,0 +1,25 @@
+package kaniko
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKanikoArgs(t *testing.T) {
+	require := require.New(t)
+
+	args := kanikoArgs(
+		"tar:///workspace/context.tar.gz",
+		"Dockerfile",
+		"registry.example.com/app:latest",
+		map[string]string{"VERSION": "1", "ENV": "prod"},
+	)
+	require.Equal([]string{
+		"--context=tar:///workspace/context.tar.gz",
+		"--dockerfile=Dockerfile",
+		"--destination=registry.example.com/app:latest",
+		"--build-arg=ENV=prod",
+		"--build-arg=VERSION=1",
+	}, args)
+}
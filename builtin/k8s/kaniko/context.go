@@ -0,0 +1,59 @@
+package kaniko
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/cli/cli/command/image/build"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/idtools"
+)
+
+// maxContextSize is the largest compressed build context that can be
+// uploaded. Contexts are uploaded as ConfigMaps, which are limited to 1MiB
+// including their metadata.
+const maxContextSize = 1000 * 1024
+
+// buildContext returns a gzipped tarball of dir for Kaniko to build with,
+// excluding the files in .dockerignore, and the path of the Dockerfile in
+// it.
+func buildContext(dir, dockerfile string) ([]byte, string, error) {
+	relDockerfile, err := filepath.Rel(dir, dockerfile)
+	if err != nil || strings.HasPrefix(relDockerfile, "..") {
+		return nil, "", fmt.Errorf(
+			"dockerfile %s must be in the application directory %s", dockerfile, dir)
+	}
+
+	excludes, err := build.ReadDockerignore(dir)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to read .dockerignore: %s", err)
+	}
+
+	relDockerfile = archive.CanonicalTarNameForPath(relDockerfile)
+	excludes = build.TrimBuildFilesFromExcludes(excludes, relDockerfile, false)
+	tar, err := archive.TarWithOptions(dir, &archive.TarOptions{
+		Compression:     archive.Gzip,
+		ExcludePatterns: excludes,
+		ChownOpts:       &idtools.Identity{UID: 0, GID: 0},
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to compress context: %s", err)
+	}
+	defer tar.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(tar, maxContextSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to compress context: %s", err)
+	}
+	if len(data) > maxContextSize {
+		return nil, "", fmt.Errorf(
+			"the compressed build context is larger than %d bytes, the most "+
+				"that can be uploaded to Kubernetes. Exclude files with "+
+				".dockerignore or set build_context to a remote context", maxContextSize)
+	}
+
+	return data, relDockerfile, nil
+}
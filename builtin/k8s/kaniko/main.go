@@ -0,0 +1,12 @@
+// Package kaniko contains a builder that builds Docker images inside a
+// Kubernetes cluster with Kaniko, without a Docker daemon.
+package kaniko
+
+import (
+	"github.com/hashicorp/waypoint-plugin-sdk"
+)
+
+// Options are the SDK options to use for instantiation.
+var Options = []sdk.Option{
+	sdk.WithComponents(&Builder{}),
+}
@@ -266,3 +266,53 @@ func formatAllocMetrics(metrics *api.AllocationMetric, scores bool, prefix strin
 	out = strings.TrimSuffix(out, "\n")
 	return out
 }
+
+// monitorDeployment is used to monitor the Nomad deployment that the last
+// monitored evaluation created, until its allocations are healthy. If the
+// evaluation didn't create a deployment, there is nothing to wait for.
+func (m *monitor) monitorDeployment() error {
+	m.Lock()
+	deployID := m.state.deployment
+	m.Unlock()
+	if deployID == "" {
+		return nil
+	}
+
+	var last string
+	for {
+		deploy, _, err := m.client.Deployments().Info(deployID, nil)
+		if err != nil {
+			return fmt.Errorf("Error reading deployment %q: %s", deployID, err)
+		}
+
+		var desired, healthy, unhealthy int
+		for _, state := range deploy.TaskGroups {
+			desired += state.DesiredTotal
+			healthy += state.HealthyAllocs
+			unhealthy += state.UnhealthyAllocs
+		}
+
+		msg := fmt.Sprintf("Waiting for allocations to be healthy: %d/%d healthy, %d unhealthy",
+			healthy, desired, unhealthy)
+		if msg != last {
+			m.ui.Update(msg)
+			last = msg
+		}
+
+		switch deploy.Status {
+		case "successful":
+			m.ui.Step(terminal.StatusOK, fmt.Sprintf(
+				"Deployment %q successful: %d/%d allocations healthy",
+				deploy.ID, healthy, desired))
+			return nil
+
+		case "failed", "cancelled":
+			m.ui.Step(terminal.StatusError, fmt.Sprintf(
+				"Deployment %q %s: %s", deploy.ID, deploy.Status, deploy.StatusDescription))
+			return fmt.Errorf("Deployment %q %s: %s",
+				deploy.ID, deploy.Status, deploy.StatusDescription)
+		}
+
+		time.Sleep(updateWait)
+	}
+}
@@ -96,10 +96,16 @@ func (p *Platform) Deploy(
 
 	// Determine if we have a job that we manage already
 	job, _, err := jobclient.Info(result.Name, &api.QueryOptions{})
-	if strings.Contains(err.Error(), "job not found") {
+	if err != nil && strings.Contains(err.Error(), "job not found") {
 		job = api.NewServiceJob(result.Name, result.Name, p.config.Region, 10)
 		job.Datacenters = []string{p.config.Datacenter}
 		tg := api.NewTaskGroup(result.Name, 1)
+
+		// Allocations are healthy once their tasks are running, since the
+		// job has no service checks. This also makes Nomad create a
+		// deployment that tracks the health of the allocations.
+		healthCheck := "task_states"
+		tg.Update = &api.UpdateStrategy{HealthCheck: &healthCheck}
 		tg.Networks = []*api.NetworkResource{
 			{
 				Mode: "host",
@@ -174,7 +180,13 @@ func (p *Platform) Deploy(
 	// Wait on the allocation
 	st.Update(fmt.Sprintf("Monitoring evaluation %q", evalID))
 
-	if err := newMonitor(st, client).monitor(evalID); err != nil {
+	mon := newMonitor(st, client)
+	if err := mon.monitor(evalID); err != nil {
+		return nil, err
+	}
+
+	// Wait on the allocations to be healthy
+	if err := mon.monitorDeployment(); err != nil {
 		return nil, err
 	}
 	st.Step(terminal.StatusOK, "Deployment successfully rolled out!")
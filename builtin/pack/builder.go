@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/buildpacks/pack"
@@ -188,7 +189,7 @@ func (b *Builder) Build(
 			return nil, status.Errorf(codes.Internal, "unable to restore custom entry point binary: %s", err)
 		}
 
-		imageId, err := epinject.AlterEntrypoint(ctx, src.App+":latest", func(cur []string) (*epinject.NewEntrypoint, error) {
+		_, err = epinject.AlterEntrypoint(ctx, src.App+":latest", func(cur []string) (*epinject.NewEntrypoint, error) {
 			ep := &epinject.NewEntrypoint{
 				Entrypoint: append([]string{"/waypoint-entrypoint"}, cur...),
 				InjectFiles: map[string]epinject.InjectFile{
@@ -206,18 +207,24 @@ func (b *Builder) Build(
 			return nil, err
 		}
 
-		labels["common/image-id"] = imageId
-
 		inject.Done()
 	}
 
+	// Record the image that we built. If we injected the entrypoint, this
+	// is the image with the entrypoint.
+	imageInfo, _, err := dockerClient.ImageInspectWithRaw(ctx, src.App+":latest")
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to inspect built image: %s", err)
+	}
+
+	labels["common/image-id"] = imageInfo.ID
+	labels["common/image-size"] = strconv.FormatInt(imageInfo.Size, 10)
+
 	sg.Wait()
 
 	ui.Output("")
 	ui.Output("Generated new Docker image: %s:latest", src.App)
 
-	// We don't even need to inspect Docker to verify we have the image.
-	// If `pack` succeeded we can assume that it created an image for us.
 	return &DockerImage{
 		Image:       src.App,
 		Tag:         "latest", // It always tags latest
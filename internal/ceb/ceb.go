@@ -20,6 +20,7 @@ import (
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
 	"github.com/hashicorp/waypoint/internal/version"
 
+	pluginAWSSecretsManager "github.com/hashicorp/waypoint/builtin/aws/secretsmanager"
 	pluginAWSSSM "github.com/hashicorp/waypoint/builtin/aws/ssm"
 	pluginK8s "github.com/hashicorp/waypoint/builtin/k8s"
 	pluginVault "github.com/hashicorp/waypoint/builtin/vault"
@@ -30,6 +31,9 @@ const (
 	envServerAddr          = "WAYPOINT_SERVER_ADDR"
 	envServerTls           = "WAYPOINT_SERVER_TLS"
 	envServerTlsSkipVerify = "WAYPOINT_SERVER_TLS_SKIP_VERIFY"
+	envServerCACert        = "WAYPOINT_SERVER_CA_CERT"
+	envServerClientCert    = "WAYPOINT_SERVER_CLIENT_CERT"
+	envServerClientKey     = "WAYPOINT_SERVER_CLIENT_KEY"
 	envCEBDisable          = "WAYPOINT_CEB_DISABLE"
 	envCEBServerRequired   = "WAYPOINT_CEB_SERVER_REQUIRED"
 	envCEBToken            = "WAYPOINT_CEB_INVITE_TOKEN"
@@ -132,6 +136,9 @@ func Run(ctx context.Context, os ...Option) error {
 	// NOTE(mitchellh): In the future, we will dynamically load these via
 	// a plugin system, Initially, we hardcode what we support.
 	ceb.configPlugins = map[string]*plugin.Instance{
+		"aws-secretsmanager": {
+			Component: &pluginAWSSecretsManager.ConfigSourcer{},
+		},
 		"aws-ssm": {
 			Component: &pluginAWSSSM.ConfigSourcer{},
 		},
@@ -278,6 +285,19 @@ type config struct {
 	ServerTlsSkipVerify bool
 	InviteToken         string
 
+	// ServerCACert is the PEM encoded certificate of a CA that the server
+	// certificate is verified with, in addition to the system CAs. This
+	// lets servers with certificates signed by an internal CA be used
+	// without skipping verification.
+	ServerCACert string
+
+	// ServerClientCert and ServerClientKey are the PEM encoded certificate
+	// and private key that the entrypoint presents to the server when
+	// using TLS. This lets the server, or a proxy in front of it, require
+	// mutual TLS for entrypoint connections.
+	ServerClientCert string
+	ServerClientKey  string
+
 	URLServicePort int
 }
 
@@ -307,6 +327,9 @@ func WithEnvDefaults() Option {
 		cfg.ServerRequired = os.Getenv(envCEBServerRequired) != ""
 		cfg.ServerTls = os.Getenv(envServerTls) != ""
 		cfg.ServerTlsSkipVerify = os.Getenv(envServerTlsSkipVerify) != ""
+		cfg.ServerCACert = os.Getenv(envServerCACert)
+		cfg.ServerClientCert = os.Getenv(envServerClientCert)
+		cfg.ServerClientKey = os.Getenv(envServerClientKey)
 		cfg.InviteToken = os.Getenv(envCEBToken)
 		cfg.disable = os.Getenv(envCEBDisable) != ""
 
@@ -3,6 +3,8 @@ package ceb
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"fmt"
 	"time"
 
 	"github.com/golang/protobuf/ptypes/empty"
@@ -84,11 +86,14 @@ func (ceb *CEB) dialServer(ctx context.Context, cfg *config, isRetry bool) error
 	if !cfg.ServerTls {
 		grpcOpts = append(grpcOpts, grpc.WithInsecure())
 	} else {
-		if cfg.ServerTlsSkipVerify {
-			grpcOpts = append(grpcOpts, grpc.WithTransportCredentials(
-				credentials.NewTLS(&tls.Config{InsecureSkipVerify: true}),
-			))
+		tlsConfig, err := serverTLSConfig(cfg)
+		if err != nil {
+			return err
 		}
+
+		grpcOpts = append(grpcOpts, grpc.WithTransportCredentials(
+			credentials.NewTLS(tlsConfig),
+		))
 	}
 
 	// Connect to this server
@@ -96,6 +101,8 @@ func (ceb *CEB) dialServer(ctx context.Context, cfg *config, isRetry bool) error
 		"addr", cfg.ServerAddr,
 		"tls", cfg.ServerTls,
 		"tls_skip_verify", cfg.ServerTlsSkipVerify,
+		"ca_cert", cfg.ServerCACert != "",
+		"client_cert", cfg.ServerClientCert != "",
 	)
 	conn, err := grpc.DialContext(ctx, cfg.ServerAddr, grpcOpts...)
 	if err != nil {
@@ -212,3 +219,38 @@ func (t staticToken) GetRequestMetadata(ctx context.Context, uri ...string) (map
 func (t staticToken) RequireTransportSecurity() bool {
 	return false
 }
+
+// serverTLSConfig returns the TLS configuration for connecting to the
+// server. The server certificate is verified with the system CAs and the
+// CA in ServerCACert, if it is set, unless verification is skipped. If a
+// client certificate is set, it is presented to the server.
+func serverTLSConfig(cfg *config) (*tls.Config, error) {
+	var tlsConfig tls.Config
+	if cfg.ServerTlsSkipVerify {
+		tlsConfig.InsecureSkipVerify = true
+	} else if cfg.ServerCACert != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			// The system CAs can't be loaded on some platforms, such as
+			// Windows, so only the given CA is trusted there.
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(cfg.ServerCACert)) {
+			return nil, fmt.Errorf("%s doesn't contain a PEM encoded certificate", envServerCACert)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.ServerClientCert != "" || cfg.ServerClientKey != "" {
+		cert, err := tls.X509KeyPair([]byte(cfg.ServerClientCert), []byte(cfg.ServerClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("error loading client certificate from %s and %s: %s",
+				envServerClientCert, envServerClientKey, err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return &tlsConfig, nil
+}
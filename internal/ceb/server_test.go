@@ -0,0 +1,137 @@
+package ceb
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerTLSConfig(t *testing.T) {
+	t.Run("skip verify", func(t *testing.T) {
+		require := require.New(t)
+
+		tlsConfig, err := serverTLSConfig(&config{
+			ServerTls:           true,
+			ServerTlsSkipVerify: true,
+		})
+		require.NoError(err)
+		require.True(tlsConfig.InsecureSkipVerify)
+	})
+
+	t.Run("system CAs", func(t *testing.T) {
+		require := require.New(t)
+
+		tlsConfig, err := serverTLSConfig(&config{ServerTls: true})
+		require.NoError(err)
+		require.False(tlsConfig.InsecureSkipVerify)
+		require.Nil(tlsConfig.RootCAs)
+	})
+
+	t.Run("CA cert", func(t *testing.T) {
+		require := require.New(t)
+
+		srv := httptest.NewTLSServer(http.NotFoundHandler())
+		defer srv.Close()
+
+		tlsConfig, err := serverTLSConfig(&config{
+			ServerTls: true,
+			ServerCACert: string(pem.EncodeToMemory(&pem.Block{
+				Type:  "CERTIFICATE",
+				Bytes: srv.Certificate().Raw,
+			})),
+		})
+		require.NoError(err)
+		require.False(tlsConfig.InsecureSkipVerify)
+		require.NotNil(tlsConfig.RootCAs)
+
+		// The server's certificate is signed by the CA, so it verifies
+		client := srv.Client()
+		client.Transport.(*http.Transport).TLSClientConfig = tlsConfig
+		resp, err := client.Get(srv.URL)
+		require.NoError(err)
+		resp.Body.Close()
+	})
+
+	t.Run("invalid CA cert", func(t *testing.T) {
+		require := require.New(t)
+
+		_, err := serverTLSConfig(&config{
+			ServerTls:    true,
+			ServerCACert: "nope",
+		})
+		require.Error(err)
+	})
+
+	t.Run("client cert", func(t *testing.T) {
+		require := require.New(t)
+
+		srv := httptest.NewUnstartedServer(http.NotFoundHandler())
+		srv.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+		srv.StartTLS()
+		defer srv.Close()
+
+		certPEM, keyPEM := testClientCert(t)
+		tlsConfig, err := serverTLSConfig(&config{
+			ServerTls:           true,
+			ServerTlsSkipVerify: true,
+			ServerClientCert:    certPEM,
+			ServerClientKey:     keyPEM,
+		})
+		require.NoError(err)
+		require.Len(tlsConfig.Certificates, 1)
+
+		client := srv.Client()
+		client.Transport.(*http.Transport).TLSClientConfig = tlsConfig
+		resp, err := client.Get(srv.URL)
+		require.NoError(err)
+		resp.Body.Close()
+	})
+
+	t.Run("client cert without key", func(t *testing.T) {
+		require := require.New(t)
+
+		certPEM, _ := testClientCert(t)
+		_, err := serverTLSConfig(&config{
+			ServerTls:        true,
+			ServerClientCert: certPEM,
+		})
+		require.Error(err)
+	})
+}
+
+// testClientCert returns a PEM encoded self-signed client certificate and
+// its private key.
+func testClientCert(t *testing.T) (string, string) {
+	require := require.New(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "waypoint-entrypoint"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(err)
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(err)
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+}
@@ -59,7 +59,7 @@ func (c *ArtifactBuildCommand) Flags() *flag.Sets {
 }
 
 func (c *ArtifactBuildCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictNothing
+	return c.predictAppTarget()
 }
 
 func (c *ArtifactBuildCommand) AutocompleteFlags() complete.Flags {
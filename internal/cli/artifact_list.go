@@ -2,9 +2,9 @@ package cli
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -53,16 +53,22 @@ func (c *ArtifactListCommand) Run(args []string) int {
 		resp, err := client.ListPushedArtifacts(c.Ctx, &pb.ListPushedArtifactsRequest{
 			Application:  app.Ref(),
 			Workspace:    wsRef,
+			Status:       c.filterFlags.statusFilters(),
 			Order:        c.filterFlags.orderOp(),
+			Labels:       c.filterFlags.labels(),
 			IncludeBuild: true,
 		})
 		if err != nil {
 			c.project.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
 			return ErrSentinel
 		}
-		sort.Sort(serversort.ArtifactStartDesc(resp.Artifacts))
+		sort.Sort(c.filterFlags.sortBy(
+			serversort.ArtifactStartDesc(resp.Artifacts),
+			serversort.ArtifactStartDesc(resp.Artifacts),
+			serversort.ArtifactCompleteDesc(resp.Artifacts),
+		))
 
-		if c.flagJson {
+		if c.flagJson || c.jsonOutput() {
 			return c.displayJson(resp.Artifacts)
 		}
 
@@ -107,6 +113,16 @@ func (c *ArtifactListCommand) Run(args []string) int {
 
 			details = append(details, fmt.Sprintf("build:%s", c.flagId.FormatId(b.Build.Sequence, b.Build.Id)))
 
+			if img, ok := b.Build.Labels["common/image-id"]; ok {
+				details = append(details, "image:"+shortImg(img))
+			}
+
+			if size, ok := b.Build.Labels["common/image-size"]; ok {
+				if n, err := strconv.ParseUint(size, 10, 64); err == nil {
+					details = append(details, "size:"+humanize.Bytes(n))
+				}
+			}
+
 			if c.flagVerbose {
 				for k, val := range b.Labels {
 					if strings.HasPrefix(k, "waypoint/") {
@@ -191,14 +207,7 @@ func (c *ArtifactListCommand) displayJson(artifacts []*pb.PushedArtifact) error
 		output = append(output, i)
 	}
 
-	data, err := json.MarshalIndent(output, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	c.ui.Output(string(data))
-
-	return nil
+	return c.writeJSON(output)
 }
 
 func (c *ArtifactListCommand) statusJson(status *pb.Status) interface{} {
@@ -223,7 +232,7 @@ func (c *ArtifactListCommand) buildJson(b *pb.Build) interface{} {
 }
 
 func (c *ArtifactListCommand) Flags() *flag.Sets {
-	return c.flagSet(0, func(set *flag.Sets) {
+	return c.flagSet(flagSetCache|flagSetOutput, func(set *flag.Sets) {
 		f := set.NewSet("Command Options")
 		f.BoolVar(&flag.BoolVar{
 			Name:   "workspace-all",
@@ -241,16 +250,17 @@ func (c *ArtifactListCommand) Flags() *flag.Sets {
 		f.BoolVar(&flag.BoolVar{
 			Name:   "json",
 			Target: &c.flagJson,
-			Usage:  "Output the deployment information as JSON.",
+			Usage:  "Output the deployment information as JSON. This is the same as -output=json.",
 		})
 
 		initIdFormat(f, &c.flagId)
-		initFilterFlags(set, &c.filterFlags, filterOptionOrder)
+		initFilterFlags(set, &c.filterFlags,
+			filterOptionState|filterOptionOrder|filterOptionLabel)
 	})
 }
 
 func (c *ArtifactListCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictNothing
+	return c.predictAppTarget()
 }
 
 func (c *ArtifactListCommand) AutocompleteFlags() complete.Flags {
@@ -62,7 +62,7 @@ func (c *ArtifactPushCommand) Flags() *flag.Sets {
 }
 
 func (c *ArtifactPushCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictNothing
+	return c.predictAppTarget()
 }
 
 func (c *ArtifactPushCommand) AutocompleteFlags() complete.Flags {
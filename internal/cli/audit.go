@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type AuditCommand struct {
+	*baseCommand
+
+	flagOperation string
+	flagLimit     int
+}
+
+func (c *AuditCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	if c.flagLimit < 0 {
+		c.ui.Output("-limit must not be negative", terminal.WithErrorStyle())
+		return 1
+	}
+
+	resp, err := c.project.Client().ListAuditEvents(c.Ctx, &pb.ListAuditEventsRequest{
+		Operation: c.flagOperation,
+		Limit:     uint32(c.flagLimit),
+	})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if c.jsonOutput() {
+		output := []map[string]interface{}{}
+		for _, e := range resp.Events {
+			output = append(output, map[string]interface{}{
+				"id":        e.Id,
+				"time":      e.Time.AsTime().Format(time.RFC3339Nano),
+				"user":      e.User,
+				"token_id":  e.TokenId,
+				"operation": e.Operation,
+				"target":    e.Target,
+				"detail":    e.Detail,
+			})
+		}
+
+		if err := c.writeJSON(output); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
+	if len(resp.Events) == 0 {
+		c.ui.Output("No audit events found.")
+		return 0
+	}
+
+	table := terminal.NewTable("Time", "Operation", "Target", "User", "Token", "Detail")
+	for _, e := range resp.Events {
+		var t string
+		if v, err := ptypes.Timestamp(e.Time); err == nil {
+			t = v.Local().Format(time.RFC3339)
+		}
+
+		table.Rich([]string{
+			t,
+			e.Operation,
+			e.Target,
+			e.User,
+			e.TokenId,
+			e.Detail,
+		}, nil)
+	}
+
+	c.ui.Table(table)
+	return 0
+}
+
+func (c *AuditCommand) Flags() *flag.Sets {
+	return c.flagSet(flagSetOutput, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:   "operation",
+			Target: &c.flagOperation,
+			Usage: "Only show events for this operation, such as \"deploy\", " +
+				"\"destroy\", \"restore_snapshot\", or \"token_create\".",
+		})
+
+		f.IntVar(&flag.IntVar{
+			Name:    "limit",
+			Target:  &c.flagLimit,
+			Default: 50,
+			Usage:   "Maximum number of events to show, newest first. Set to 0 to show all events.",
+		})
+	})
+}
+
+func (c *AuditCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *AuditCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *AuditCommand) Synopsis() string {
+	return "Show the audit log of operations performed on the server"
+}
+
+func (c *AuditCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint audit [options]
+
+  Show the audit log of operations performed on the server, newest first.
+
+  The server records who performed operations that change what is running
+  or who has access, such as deploying, destroying, restoring a snapshot,
+  and creating tokens. Events are identified by the ID of the token used,
+  never the token itself.
+
+  The audit log is kept when a snapshot is restored. The server can also
+  send events to a file or syslog, see the "audit" block in the server
+  configuration.
+
+` + c.Flags().Help())
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/hashicorp/waypoint/internal/config"
 	"github.com/hashicorp/waypoint/internal/pkg/flag"
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	"github.com/hashicorp/waypoint/internal/serverclient"
 )
 
 // baseCommand is embedded in all commands to provide common logic and data.
@@ -59,6 +60,9 @@ type baseCommand struct {
 	// contextStorage is for CLI contexts.
 	contextStorage *clicontext.Storage
 
+	// cacheDir is the directory for cached server responses.
+	cacheDir string
+
 	// refProject and refWorkspace the references for this CLI invocation.
 	refProject   *pb.Ref_Project
 	refApp       *pb.Ref_Application
@@ -70,6 +74,14 @@ type baseCommand struct {
 	// flagPlain is whether the output should be in plain mode.
 	flagPlain bool
 
+	// flagOutput is the output format set via -output. Use jsonOutput
+	// rather than checking this directly.
+	flagOutput string
+
+	// flagNonInteractive is set via -non-interactive. Use nonInteractive
+	// rather than checking this directly.
+	flagNonInteractive bool
+
 	// flagLabels are set via -label if flagSetOperation is set.
 	flagLabels map[string]string
 
@@ -89,6 +101,14 @@ type baseCommand struct {
 	// flagConnection contains manual flag-based connection info.
 	flagConnection clicontext.Config
 
+	// flagCached is set via -cached if flagSetCache is set.
+	flagCached bool
+
+	// exitCode and exitErr are the exit code and error for the failure
+	// of the command, if known. See setExitError.
+	exitCode int
+	exitErr  error
+
 	// args that were present after parsing flags
 	args []string
 
@@ -145,36 +165,53 @@ func (c *baseCommand) Init(opts ...Option) error {
 
 	// Parse flags
 	if err := baseCfg.Flags.Parse(baseCfg.Args); err != nil {
+		c.setExitError(exitCodeConfig, err)
 		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
 		return err
 	}
 	c.args = baseCfg.Flags.Args()
 
-	// Reset the UI to plain if that was set
-	if c.flagPlain {
+	// Reset the UI to plain if that was set. JSON output is always plain
+	// so that nothing but the JSON is written.
+	if c.flagPlain || c.jsonOutput() {
 		c.ui = terminal.NonInteractiveUI(c.Ctx)
 	}
 
+	// In non-interactive mode we always use the plain UI so that the output
+	// doesn't depend on whether we're in a terminal, and we fail prompts.
+	if c.nonInteractive() {
+		c.ui = &strictUI{UI: terminal.NonInteractiveUI(c.Ctx)}
+	}
+
 	// With the flags we now know what workspace we're targeting
 	c.refWorkspace = &pb.Ref_Workspace{Workspace: c.flagWorkspace}
 
-	// Setup our base config path
+	// Setup our base config path. This creates the directory, which fails
+	// in read-only environments such as containers. If the connection is
+	// configured by the environment we don't need it, so we continue.
 	homeConfigPath, err := xdg.ConfigFile("waypoint/.ignore")
 	if err != nil {
-		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
-		return err
+		if !serverclient.EnvSet() {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return err
+		}
+
+		c.Log.Debug("error creating home configuration directory, using env",
+			"err", err)
+		homeConfigPath = filepath.Join(xdg.ConfigHome, "waypoint", ".ignore")
 	}
 	homeConfigPath = filepath.Dir(homeConfigPath)
 	c.Log.Debug("home configuration directory", "path", homeConfigPath)
 
 	// Setup our base directory for context management
-	contextStorage, err := clicontext.NewStorage(
-		clicontext.WithDir(filepath.Join(homeConfigPath, "context")))
+	contextDir := filepath.Join(homeConfigPath, "context")
+	contextStorage, err := clicontext.NewStorage(clicontext.WithDir(contextDir))
 	if err != nil {
 		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
 		return err
 	}
 	c.contextStorage = contextStorage
+	c.cacheDir = responseCacheDir(contextDir)
 
 	// Parse the configuration
 	c.cfg = &config.Config{}
@@ -211,6 +248,7 @@ func (c *baseCommand) Init(opts ...Option) error {
 	if baseCfg.Config {
 		cfg, err := c.initConfig(baseCfg.ConfigOptional)
 		if err != nil {
+			c.setExitError(exitCodeConfig, err)
 			c.logError(c.Log, "failed to load config", err)
 			return err
 		}
@@ -252,6 +290,7 @@ func (c *baseCommand) Init(opts ...Option) error {
 					"Remote operations must be manually enabled by using setting the 'runner.enabled'\n" +
 					"setting in your Waypoint configuration file. Please see the documentation\n" +
 					"on this setting for more information.")
+			c.setExitError(exitCodeConfig, err)
 			c.logError(c.Log, "", err)
 			return err
 		}
@@ -262,6 +301,7 @@ func (c *baseCommand) Init(opts ...Option) error {
 	if baseCfg.AppTargetRequired {
 		if c.refApp == nil {
 			if len(c.cfg.Apps()) != 1 {
+				c.setExitError(exitCodeConfig, errors.New(errAppModeSingle))
 				c.ui.Output(errAppModeSingle, terminal.WithErrorStyle())
 				return ErrSentinel
 			}
@@ -305,6 +345,7 @@ func (c *baseCommand) DoApp(ctx context.Context, f func(context.Context, *client
 	// Just a serialize loop for now, one day we'll parallelize.
 	var finalErr error
 	var didErrSentinel bool
+	var succeeded int
 	for _, app := range apps {
 		// Support cancellation
 		if err := ctx.Err(); err != nil {
@@ -317,12 +358,21 @@ func (c *baseCommand) DoApp(ctx context.Context, f func(context.Context, *client
 			} else {
 				didErrSentinel = true
 			}
+		} else {
+			succeeded++
 		}
 	}
 	if finalErr == nil && didErrSentinel {
 		finalErr = ErrSentinel
 	}
 
+	// If only some of the apps failed, we exit with a code that says so.
+	if succeeded > 0 && succeeded < len(apps) {
+		c.setExitError(exitCodePartial, finalErr)
+	} else if finalErr != nil {
+		c.setExitError(exitCodeForError(finalErr), finalErr)
+	}
+
 	return finalErr
 }
 
@@ -333,6 +383,7 @@ func (c *baseCommand) logError(log hclog.Logger, prefix string, err error) {
 	}
 
 	log.Error(prefix, "error", err)
+	c.setExitError(exitCodeForError(err), err)
 
 	if prefix != "" {
 		prefix += ": "
@@ -354,6 +405,30 @@ func (c *baseCommand) flagSet(bit flagSetBit, f func(*flag.Sets)) *flag.Sets {
 			Usage:   "Plain output: no colors, no animation.",
 		})
 
+		// Only commands that can write JSON have -output, so that
+		// WAYPOINT_OUTPUT is ignored by the others.
+		if bit&flagSetOutput != 0 {
+			f.EnumSingleVar(&flag.EnumSingleVar{
+				Name:    "output",
+				Target:  &c.flagOutput,
+				Values:  []string{outputHuman, outputJSON},
+				Default: outputHuman,
+				EnvVar:  "WAYPOINT_OUTPUT",
+				Usage: "Output format. With json, the command outputs a single " +
+					"JSON document instead of human-readable text.",
+			})
+		}
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "non-interactive",
+			Target:  &c.flagNonInteractive,
+			Default: false,
+			EnvVar:  "WAYPOINT_NON_INTERACTIVE",
+			Usage: "Never prompt for input or change behavior based on whether " +
+				"a terminal is attached. Commands that need input fail with an " +
+				"error instead. Use this in CI.",
+		})
+
 		f.StringVar(&flag.StringVar{
 			Name:    "app",
 			Target:  &c.flagApp,
@@ -361,6 +436,7 @@ func (c *baseCommand) flagSet(bit flagSetBit, f func(*flag.Sets)) *flag.Sets {
 			Usage: "App to target. Certain commands require a single app target for " +
 				"Waypoint configurations with multiple apps. If you have a single app, " +
 				"then this can be ignored.",
+			Completion: c.predictApp(),
 		})
 
 		f.StringVar(&flag.StringVar{
@@ -419,6 +495,17 @@ func (c *baseCommand) flagSet(bit flagSetBit, f func(*flag.Sets)) *flag.Sets {
 		})
 	}
 
+	if bit&flagSetCache != 0 {
+		f := set.NewSet("Cache Options")
+		f.BoolVar(&flag.BoolVar{
+			Name:   "cached",
+			Target: &c.flagCached,
+			Usage: "Use the responses cached by previous commands instead of " +
+				"connecting to the server. This works when the server is " +
+				"unreachable, but the results may be out of date.",
+		})
+	}
+
 	if f != nil {
 		// Configure our values
 		f(set)
@@ -434,6 +521,8 @@ const (
 	flagSetNone       flagSetBit = 1 << iota
 	flagSetOperation             // shared flags for operations (build, deploy, etc)
 	flagSetConnection            // shared flags for server connections
+	flagSetCache                 // use cached responses for read-only commands
+	flagSetOutput                // -output for commands that can write JSON
 )
 
 var (
@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"path/filepath"
 
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clicache"
 	"github.com/hashicorp/waypoint/internal/clicontext"
 	clientpkg "github.com/hashicorp/waypoint/internal/client"
 	configpkg "github.com/hashicorp/waypoint/internal/config"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
 	"github.com/hashicorp/waypoint/internal/serverclient"
 )
 
@@ -72,17 +75,46 @@ func (c *baseCommand) initClient() (*clientpkg.Project, error) {
 	// Get the context we'll use. The ordering here is purposeful and creates
 	// the following precedence: (1) context (2) env (3) flags where the
 	// later values override the former.
+	//
+	// If the connection is configured entirely by the environment, we don't
+	// load the stored context at all so that no config directory is needed.
 	var err error
-	connectOpts := []serverclient.ConnectOption{
-		serverclient.FromContext(c.contextStorage, ""),
+	var connectOpts []serverclient.ConnectOption
+	if !serverclient.EnvSet() {
+		connectOpts = append(connectOpts, serverclient.FromContext(c.contextStorage, ""))
+	}
+	connectOpts = append(connectOpts,
 		serverclient.FromEnv(),
 		serverclient.FromContextConfig(flagConnection),
+	)
+
+	// A client certificate can be specified by flags without an address,
+	// in which case it is used for whichever server we connect to.
+	if v := c.flagConnection.Server; flagConnection == nil &&
+		(v.TlsClientCertFile != "" || v.TlsClientKeyFile != "") {
+		connectOpts = append(connectOpts, serverclient.WithClientCertificate(
+			v.TlsClientCertFile, v.TlsClientKeyFile))
 	}
+
 	c.clientContext, err = serverclient.ContextConfig(connectOpts...)
 	if err != nil {
 		return nil, err
 	}
 
+	// Responses from a remote server are cached so that they can be used
+	// with -cached when the server is unreachable. There is nothing to
+	// cache for the in-memory server.
+	var cache *clicache.Cache
+	if addr := c.clientContext.Server.Address; addr != "" {
+		cache = clicache.New(c.cacheDir, addr, c.Log)
+		connectOpts = append(connectOpts,
+			serverclient.WithUnaryInterceptor(cache.UnaryClientInterceptor()))
+	}
+
+	// Record server errors so that we exit with a code for them.
+	connectOpts = append(connectOpts,
+		serverclient.WithUnaryInterceptor(c.exitErrorInterceptor()))
+
 	// Start building our client options
 	opts := []clientpkg.Option{
 		clientpkg.WithLogger(c.Log),
@@ -96,6 +128,22 @@ func (c *baseCommand) initClient() (*clientpkg.Project, error) {
 		opts = append(opts, clientpkg.WithLocal())
 	}
 
+	// With -cached we never connect and only use the cached responses.
+	if c.flagCached {
+		if cache == nil {
+			return nil, errors.New(
+				"-cached requires a server to be configured with a context " +
+					"or environment variables")
+		}
+
+		if c.ui != nil && !c.jsonOutput() {
+			c.ui.Output("Using cached responses from %s. These may be out of date.",
+				c.clientContext.Server.Address, terminal.WithWarningStyle())
+		}
+
+		opts = append(opts, clientpkg.WithClient(pb.NewWaypointClient(cache.Conn(0, nil))))
+	}
+
 	if c.ui != nil {
 		opts = append(opts, clientpkg.WithUI(c.ui))
 	}
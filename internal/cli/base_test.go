@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseCommandFlagSet_output(t *testing.T) {
+	cases := []struct {
+		Name string
+		Bit  flagSetBit
+		Args []string
+		Env  string
+		JSON bool
+		Err  bool
+	}{
+		{
+			"flag",
+			flagSetOutput,
+			[]string{"-output=json"},
+			"",
+			true,
+			false,
+		},
+
+		{
+			"env",
+			flagSetOutput,
+			nil,
+			"json",
+			true,
+			false,
+		},
+
+		{
+			"env ignored without JSON output",
+			0,
+			nil,
+			"json",
+			false,
+			false,
+		},
+
+		{
+			"flag not defined without JSON output",
+			0,
+			[]string{"-output=json"},
+			"",
+			false,
+			true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			require := require.New(t)
+
+			if tt.Env != "" {
+				require.NoError(os.Setenv("WAYPOINT_OUTPUT", tt.Env))
+				t.Cleanup(func() { os.Unsetenv("WAYPOINT_OUTPUT") })
+			}
+
+			// The environment is read when the flags are created.
+			c := &baseCommand{}
+			err := c.flagSet(tt.Bit, nil).Parse(tt.Args)
+			if tt.Err {
+				require.Error(err)
+				return
+			}
+
+			require.NoError(err)
+			require.Equal(tt.JSON, c.jsonOutput())
+		})
+	}
+}
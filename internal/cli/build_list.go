@@ -3,6 +3,7 @@ package cli
 import (
 	"context"
 	"sort"
+	"time"
 
 	"github.com/dustin/go-humanize"
 	"github.com/golang/protobuf/ptypes"
@@ -21,6 +22,7 @@ type BuildListCommand struct {
 
 	flagWorkspaceAll bool
 	flagId           idFormat
+	filterFlags      filterFlags
 }
 
 func (c *BuildListCommand) Run(args []string) int {
@@ -46,12 +48,23 @@ func (c *BuildListCommand) Run(args []string) int {
 		resp, err := client.ListBuilds(c.Ctx, &pb.ListBuildsRequest{
 			Application: app.Ref(),
 			Workspace:   wsRef,
+			Status:      c.filterFlags.statusFilters(),
+			Order:       c.filterFlags.orderOp(),
+			Labels:      c.filterFlags.labels(),
 		})
 		if err != nil {
 			c.project.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
 			return ErrSentinel
 		}
-		sort.Sort(serversort.BuildStartDesc(resp.Builds))
+		sort.Sort(c.filterFlags.sortBy(
+			serversort.BuildStartDesc(resp.Builds),
+			serversort.BuildStartDesc(resp.Builds),
+			serversort.BuildCompleteDesc(resp.Builds),
+		))
+
+		if c.jsonOutput() {
+			return c.displayJson(resp.Builds)
+		}
 
 		const bullet = "●"
 
@@ -106,8 +119,38 @@ func (c *BuildListCommand) Run(args []string) int {
 	return 0
 }
 
+func (c *BuildListCommand) displayJson(builds []*pb.Build) error {
+	output := []map[string]interface{}{}
+
+	for _, b := range builds {
+		i := map[string]interface{}{}
+
+		i["id"] = b.Id
+		i["sequence"] = b.Sequence
+		i["application"] = b.Application
+		i["labels"] = b.Labels
+		i["component"] = b.Component.Name
+		i["status"] = c.statusJson(b.Status)
+		i["workspace"] = b.Workspace.Workspace
+
+		output = append(output, i)
+	}
+
+	return c.writeJSON(output)
+}
+
+func (c *BuildListCommand) statusJson(status *pb.Status) interface{} {
+	i := map[string]interface{}{}
+
+	i["state"] = status.State.String()
+	i["complete_time"] = status.CompleteTime.AsTime().Format(time.RFC3339Nano)
+	i["start_time"] = status.StartTime.AsTime().Format(time.RFC3339Nano)
+
+	return i
+}
+
 func (c *BuildListCommand) Flags() *flag.Sets {
-	return c.flagSet(0, func(set *flag.Sets) {
+	return c.flagSet(flagSetCache|flagSetOutput, func(set *flag.Sets) {
 		f := set.NewSet("Command Options")
 		f.BoolVar(&flag.BoolVar{
 			Name:   "workspace-all",
@@ -116,11 +159,13 @@ func (c *BuildListCommand) Flags() *flag.Sets {
 		})
 
 		initIdFormat(f, &c.flagId)
+		initFilterFlags(set, &c.filterFlags,
+			filterOptionState|filterOptionOrder|filterOptionLabel)
 	})
 }
 
 func (c *BuildListCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictNothing
+	return c.predictAppTarget()
 }
 
 func (c *BuildListCommand) AutocompleteFlags() complete.Flags {
@@ -0,0 +1,12 @@
+package cli
+
+import (
+	"path/filepath"
+)
+
+// responseCacheDir returns the directory for cached server responses in
+// the given context directory. The name is prefixed with "_" so that it
+// isn't listed as a context.
+func responseCacheDir(contextDir string) string {
+	return filepath.Join(contextDir, "_cache")
+}
@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+)
+
+// The completion scripts for each shell. Each is formatted with the quoted
+// path to the waypoint binary and the name of the command. Rather than
+// listing the commands and flags in the script, the scripts ask the binary
+// itself by setting COMP_LINE and COMP_POINT, which the CLI handles before
+// running any command. This means the completions never go out of date and
+// can include values fetched from the server.
+var completionScripts = map[string]string{
+	"bash": `# waypoint completion for bash. Add this to ~/.bashrc:
+#
+#   source <(waypoint completion bash)
+#
+complete -o nospace -C %[1]s %[2]s
+`,
+
+	"zsh": `# waypoint completion for zsh. Add this to ~/.zshrc:
+#
+#   source <(waypoint completion zsh)
+#
+autoload -U +X bashcompinit && bashcompinit
+complete -o nospace -C %[1]s %[2]s
+`,
+
+	"fish": `# waypoint completion for fish. Save this to
+# ~/.config/fish/completions/waypoint.fish:
+#
+#   waypoint completion fish > ~/.config/fish/completions/waypoint.fish
+#
+function __complete_%[2]s
+    set -lx COMP_LINE (commandline -cp)
+    set -lx COMP_POINT (string length -- $COMP_LINE)
+    test -z (commandline -ct)
+    and set COMP_LINE "$COMP_LINE "
+    and set COMP_POINT (math $COMP_POINT + 1)
+    %[1]s
+end
+complete -f -c %[2]s -a "(__complete_%[2]s)"
+`,
+
+	"powershell": `# waypoint completion for PowerShell. Add this to your profile:
+#
+#   waypoint completion powershell | Out-String | Invoke-Expression
+#
+Register-ArgumentCompleter -Native -CommandName %[2]s -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+
+    $line = $commandAst.ToString()
+    if ($cursorPosition -gt $line.Length) {
+        $line = $line.PadRight($cursorPosition)
+    }
+
+    $env:COMP_LINE = $line.Substring(0, $cursorPosition)
+    $env:COMP_POINT = $cursorPosition
+    & %[1]s | ForEach-Object {
+        [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
+    }
+    Remove-Item Env:COMP_LINE, Env:COMP_POINT
+}
+`,
+}
+
+type CompletionCommand struct {
+	*baseCommand
+}
+
+func (c *CompletionCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+		WithClient(false),
+	); err != nil {
+		return 1
+	}
+
+	if len(c.args) != 1 {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	script, ok := completionScripts[c.args[0]]
+	if !ok {
+		c.ui.Output("Unsupported shell %q. Supported shells: %s.",
+			c.args[0], strings.Join(completionShells(), ", "),
+			terminal.WithErrorStyle())
+		return 1
+	}
+
+	// Use the absolute path to this binary so that completion works even
+	// if it isn't on the PATH, falling back to the name it was run as.
+	path, err := os.Executable()
+	if err != nil {
+		path = os.Args[0]
+	}
+
+	quote := shellQuote
+	if c.args[0] == "powershell" {
+		quote = powershellQuote
+	}
+
+	fmt.Fprintf(os.Stdout, script, quote(path), cliName)
+	return 0
+}
+
+// completionShells returns the shells we can generate completion for.
+func completionShells() []string {
+	return []string{"bash", "zsh", "fish", "powershell"}
+}
+
+// shellQuote quotes s for POSIX shells and fish.
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// powershellQuote quotes s as a PowerShell string literal.
+func powershellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
+
+func (c *CompletionCommand) Flags() *flag.Sets {
+	return c.flagSet(0, nil)
+}
+
+func (c *CompletionCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictSet(completionShells()...)
+}
+
+func (c *CompletionCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *CompletionCommand) Synopsis() string {
+	return "Output shell completion code"
+}
+
+func (c *CompletionCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint completion SHELL
+
+  Output shell completion code for the given shell, which is one of bash,
+  zsh, fish, or powershell.
+
+  The completion covers commands, flags, and values such as project and
+  app names, which are fetched from the server in the current context. If
+  the server can't be reached within a couple of seconds, only commands and
+  flags are completed.
+
+  To enable completion, load the output in your shell's startup file. For
+  example, for bash add the following to ~/.bashrc:
+
+      source <(waypoint completion bash)
+
+  Each script includes instructions for its shell. Unlike
+  -autocomplete-install, this supports PowerShell and doesn't modify your
+  shell's startup files.
+
+` + c.Flags().Help())
+}
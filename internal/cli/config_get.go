@@ -3,7 +3,6 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 
 	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
 	"github.com/hashicorp/waypoint/internal/clierrors"
@@ -39,7 +38,7 @@ func (c *ConfigGetCommand) Run(args []string) int {
 	case 1:
 		prefix = c.args[0]
 	default:
-		fmt.Fprintf(os.Stderr, "config-get requires 1 arguments: a variable name prefix")
+		c.ui.Output(c.Flags().Help(), terminal.WithErrorStyle())
 		return 1
 	}
 
@@ -107,12 +106,14 @@ func (c *ConfigGetCommand) Run(args []string) int {
 		}
 
 		if len(resp.Variables) == 0 {
-			fmt.Fprintf(os.Stderr, "named variable '%s' was not found in config", prefix)
+			c.ui.Output("Named variable %q was not found in config.", prefix,
+				terminal.WithErrorStyle())
 			return 1
 		}
 
 		if resp.Variables[0].Name != prefix {
-			fmt.Fprintf(os.Stderr, "name '%s' doesn't match prefix: %s", resp.Variables[0].Name, prefix)
+			c.ui.Output("Name %q doesn't match prefix %q.", resp.Variables[0].Name, prefix,
+				terminal.WithErrorStyle())
 			return 1
 		}
 
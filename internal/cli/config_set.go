@@ -2,7 +2,6 @@ package cli
 
 import (
 	"bufio"
-	"fmt"
 	"os"
 	"strings"
 
@@ -31,13 +30,14 @@ func (c *ConfigSetCommand) Run(args []string) int {
 	if len(c.args) == 0 {
 		info, err := os.Stdin.Stat()
 		if err != nil {
-			_, _ = fmt.Fprintf(os.Stderr, "failed to get console mode for stdin")
+			c.outputError("Failed to get console mode for stdin", err)
 			return 1
 		}
 
 		// If there's no pipe, there are no arguments. Fail.
 		if info.Mode()&os.ModeNamedPipe == 0 {
-			_, _ = fmt.Fprintf(os.Stderr, "config set requires at least one key=value entry")
+			c.ui.Output("At least one key=value entry is required.",
+				terminal.WithErrorStyle())
 			return 1
 		}
 
@@ -55,7 +55,8 @@ func (c *ConfigSetCommand) Run(args []string) int {
 	for _, arg := range c.args {
 		idx := strings.IndexByte(arg, '=')
 		if idx == -1 || idx == 0 {
-			fmt.Fprintf(os.Stderr, "variables must be in the form key=value")
+			c.ui.Output("Variables must be in the form key=value.",
+				terminal.WithErrorStyle())
 			return 1
 		}
 
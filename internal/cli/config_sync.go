@@ -55,7 +55,7 @@ func (c *ConfigSyncCommand) Flags() *flag.Sets {
 }
 
 func (c *ConfigSyncCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictNothing
+	return c.predictAppTarget()
 }
 
 func (c *ConfigSyncCommand) AutocompleteFlags() complete.Flags {
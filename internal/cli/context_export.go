@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+)
+
+type ContextExportCommand struct {
+	*baseCommand
+}
+
+func (c *ContextExportCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+		WithClient(false),
+	); err != nil {
+		return 1
+	}
+	args = c.args
+
+	if len(args) > 1 {
+		c.ui.Output(c.Flags().Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	var name string
+	if len(args) == 1 {
+		name = args[0]
+	}
+	if name == "" {
+		def, err := c.contextStorage.Default()
+		if err != nil {
+			c.ui.Output(
+				"Error getting default context: %s",
+				clierrors.Humanize(err),
+				terminal.WithErrorStyle(),
+			)
+			return 1
+		}
+		if def == "" {
+			c.ui.Output("No default context is set. Specify the context to export.",
+				terminal.WithErrorStyle())
+			return 1
+		}
+
+		name = def
+	}
+
+	config, err := c.contextStorage.Load(name)
+	if err != nil {
+		c.ui.Output(
+			"Error loading the context %q: %s",
+			name,
+			clierrors.Humanize(err),
+			terminal.WithErrorStyle(),
+		)
+		return 1
+	}
+
+	data, err := config.Export()
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	// We write directly to stdout so that the output can be redirected to
+	// a file or captured into a variable without any UI formatting.
+	fmt.Println(string(data))
+	return 0
+}
+
+func (c *ContextExportCommand) Flags() *flag.Sets {
+	return c.flagSet(0, nil)
+}
+
+func (c *ContextExportCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ContextExportCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ContextExportCommand) Synopsis() string {
+	return "Export a context as JSON."
+}
+
+func (c *ContextExportCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint context export [options] [NAME]
+
+  Export a context as a single JSON document.
+
+  If NAME isn't specified, the default context is exported. The result
+  can be loaded on another machine with "waypoint context import", or
+  set as the WAYPOINT_CONTEXT_CONFIG environment variable to connect
+  without storing a context at all, which is useful in CI and containers.
+
+  The export contains the authentication token for the server in plain
+  text so it should be stored as a secret. Paths to client certificates
+  are exported as-is and the files must exist where the context is used.
+
+` + c.Flags().Help())
+}
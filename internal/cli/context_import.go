@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clicontext"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+)
+
+type ContextImportCommand struct {
+	*baseCommand
+
+	flagSetDefault bool
+}
+
+func (c *ContextImportCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+		WithClient(false),
+	); err != nil {
+		return 1
+	}
+	args = c.args
+
+	// Require the name and optionally a file
+	if len(args) < 1 || len(args) > 2 {
+		c.ui.Output(c.Flags().Help(), terminal.WithErrorStyle())
+		return 1
+	}
+	name := args[0]
+
+	// Read the export from the file or stdin.
+	var data []byte
+	var err error
+	if len(args) == 2 && args[1] != "-" {
+		data, err = ioutil.ReadFile(args[1])
+	} else {
+		data, err = ioutil.ReadAll(os.Stdin)
+	}
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	config, err := clicontext.Import(bytes.TrimSpace(data))
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if err := c.contextStorage.Set(name, config); err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if c.flagSetDefault {
+		if err := c.contextStorage.SetDefault(name); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+	}
+
+	c.ui.Output("Context %q imported.", name, terminal.WithSuccessStyle())
+	return 0
+}
+
+func (c *ContextImportCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.BoolVar(&flag.BoolVar{
+			Name:   "set-default",
+			Target: &c.flagSetDefault,
+			Usage:  "Set this context as the new default for the CLI.",
+		})
+	})
+}
+
+func (c *ContextImportCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFiles("*")
+}
+
+func (c *ContextImportCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ContextImportCommand) Synopsis() string {
+	return "Import a context exported with \"context export\"."
+}
+
+func (c *ContextImportCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint context import [options] NAME [FILE]
+
+  Import a context exported with "waypoint context export".
+
+  The context is read from FILE, or from stdin if FILE isn't specified
+  or is "-". An existing context with the same name is overwritten.
+
+` + c.Flags().Help())
+}
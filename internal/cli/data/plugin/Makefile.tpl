@@ -0,0 +1,26 @@
+PLUGIN_NAME={{.Name}}
+
+# The plugin must be named waypoint-plugin-<name> for Waypoint to find it.
+BINARY=waypoint-plugin-$(PLUGIN_NAME)
+
+.PHONY: all
+all: protos build test
+
+# Generates the Go code for the messages in plugin.proto.
+.PHONY: protos
+protos:
+	go generate ./{{.Package}}
+
+.PHONY: build
+build:
+	go build -o ./bin/$(BINARY) .
+
+.PHONY: test
+test:
+	go test ./...
+
+# Installs the plugin where Waypoint looks for plugins.
+.PHONY: install
+install: build
+	mkdir -p $(HOME)/.config/waypoint/plugins
+	cp ./bin/$(BINARY) $(HOME)/.config/waypoint/plugins/
@@ -0,0 +1,40 @@
+# Waypoint Plugin: {{.Name}}
+
+This is a Waypoint plugin that provides the "{{.Name}}" {{.TypeName}}.
+
+## Getting Started
+
+Building the plugin requires Go, `protoc`, and `protoc-gen-go`. To
+generate the protobuf code, build the plugin, and run the tests:
+
+```shell
+go mod tidy
+make
+```
+
+To make the plugin available to Waypoint, install it with `make install`
+or copy `bin/waypoint-plugin-{{.Name}}` into the `.waypoint/plugins`
+directory of your project. Then use it in `waypoint.hcl`:
+
+```hcl
+{{.Stanza}} {
+  use "{{.Name}}" {}
+}
+```
+
+## Layout
+
+  * `main.go` - The entrypoint of the plugin binary.
+
+  * `{{.Package}}/main.go` - The components and mappers the plugin serves.
+
+  * `{{.Package}}/{{.Type}}.go` - The {{.TypeName}}. This is where the
+    work of the plugin happens.
+
+  * `{{.Package}}/plugin.proto` - The messages the plugin passes to and
+    from Waypoint. Run `make protos` after changing this file.
+
+  * `{{.Package}}/mapper.go` - Mappers that convert between types.
+
+  * `{{.Package}}/*_test.go` - Tests. The acceptance tests launch the
+    plugin the same way Waypoint does and talk to it over gRPC.
@@ -0,0 +1,105 @@
+package {{.Package}}
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/component"
+	"github.com/hashicorp/waypoint-plugin-sdk/docs"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+)
+
+// BuilderConfig is the configuration structure for the builder. It is
+// decoded from the "use" block of the build stanza in waypoint.hcl.
+type BuilderConfig struct {
+	// Directory is the path of the build output, relative to the
+	// application.
+	Directory string `hcl:"directory,optional"`
+}
+
+// Builder builds an application into an Artifact.
+type Builder struct {
+	config BuilderConfig
+}
+
+// Config implements component.Configurable
+func (b *Builder) Config() (interface{}, error) {
+	return &b.config, nil
+}
+
+// ConfigSet is called after the configuration is decoded, so that it can
+// be validated.
+func (b *Builder) ConfigSet(config interface{}) error {
+	c, ok := config.(*BuilderConfig)
+	if !ok {
+		return fmt.Errorf("expected *BuilderConfig, got %T", config)
+	}
+
+	if filepath.IsAbs(c.Directory) {
+		return fmt.Errorf("directory must be relative to the application")
+	}
+
+	return nil
+}
+
+// BuildFunc implements component.Builder
+func (b *Builder) BuildFunc() interface{} {
+	// Waypoint calls the returned function with the arguments it asks
+	// for, so add any arguments Build needs to its signature.
+	return b.Build
+}
+
+// Build builds the application.
+func (b *Builder) Build(
+	ctx context.Context,
+	ui terminal.UI,
+	src *component.Source,
+) (*Artifact, error) {
+	sg := ui.StepGroup()
+	defer sg.Wait()
+
+	step := sg.Add("Building application...")
+	defer step.Abort()
+
+	// TODO: build the application.
+	location := filepath.Join(src.Path, b.config.Directory)
+
+	step.Update("Application built to %s", location)
+	step.Done()
+
+	return &Artifact{
+		Location: location,
+	}, nil
+}
+
+// Documentation is shown by "waypoint docs".
+func (b *Builder) Documentation() (*docs.Documentation, error) {
+	doc, err := docs.New(docs.FromConfig(&BuilderConfig{}), docs.FromFunc(b.BuildFunc()))
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Description("Builds the application")
+	doc.Output("{{.Package}}.Artifact")
+
+	doc.Example(`
+build {
+  use "{{.Name}}" {
+    directory = "dist"
+  }
+}
+`)
+
+	doc.SetField(
+		"directory",
+		"the path of the build output, relative to the application",
+	)
+
+	return doc, nil
+}
+
+var (
+	_ component.Builder      = (*Builder)(nil)
+	_ component.Configurable = (*Builder)(nil)
+)
@@ -0,0 +1,56 @@
+package {{.Package}}
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/component"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+)
+
+func TestBuilder(t *testing.T) {
+	ctx := context.Background()
+
+	var b Builder
+	if diags := component.Configure(&b, testConfig(t, `directory = "dist"`), nil); diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	src := &component.Source{App: "test", Path: t.TempDir()}
+	result, err := b.Build(ctx, terminal.NonInteractiveUI(ctx), src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := filepath.Join(src.Path, "dist"); result.Location != expected {
+		t.Fatalf("expected location %q, got %q", expected, result.Location)
+	}
+}
+
+// TestBuilder_acceptance tests the builder through the plugin process.
+func TestBuilder_acceptance(t *testing.T) {
+	raw := testPlugin(t, component.BuilderType)
+	if _, ok := raw.(component.Builder); !ok {
+		t.Fatalf("expected a component.Builder, got %T", raw)
+	}
+
+	// Valid configuration is accepted
+	if diags := component.Configure(raw, testConfig(t, `directory = "dist"`), nil); diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	// Invalid configuration is rejected by ConfigSet
+	if diags := component.Configure(raw, testConfig(t, `directory = "/dist"`), nil); !diags.HasErrors() {
+		t.Fatal("expected an absolute directory to be rejected")
+	}
+
+	// Documentation is served
+	doc, err := component.Documentation(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Details().Description == "" {
+		t.Fatal("expected a description")
+	}
+}
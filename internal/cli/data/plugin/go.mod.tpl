@@ -0,0 +1,12 @@
+module {{.Module}}
+
+go 1.15
+
+require (
+	github.com/golang/protobuf v1.4.2
+	github.com/hashicorp/go-hclog v0.14.1
+	github.com/hashicorp/go-plugin v1.3.0
+	github.com/hashicorp/hcl/v2 v2.7.1-0.20201023000745-3de61ecba298
+	github.com/hashicorp/waypoint-plugin-sdk v0.0.0-20201202203308-140d0145b90e
+	google.golang.org/protobuf v1.25.0
+)
@@ -0,0 +1,13 @@
+package main
+
+import (
+	sdk "github.com/hashicorp/waypoint-plugin-sdk"
+
+	"{{.Module}}/{{.Package}}"
+)
+
+func main() {
+	// sdk.Main serves the components of the plugin over gRPC so that
+	// Waypoint can launch and talk to the plugin.
+	sdk.Main({{.Package}}.Options...)
+}
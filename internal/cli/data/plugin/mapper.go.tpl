@@ -0,0 +1,13 @@
+package {{.Package}}
+
+// ReleaseMapper maps a Deployment to a Release.
+//
+// Mappers convert a value into another type when a component asks for a
+// type that no earlier step produced. This one lets a deployment be used
+// directly as a release when the application has no release manager.
+// Mappers are registered with sdk.WithMappers in Options.
+func ReleaseMapper(src *Deployment) *Release {
+	return &Release{
+		Url: src.Url,
+	}
+}
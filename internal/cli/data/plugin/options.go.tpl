@@ -0,0 +1,13 @@
+package {{.Package}}
+
+import (
+	sdk "github.com/hashicorp/waypoint-plugin-sdk"
+)
+
+//go:generate protoc -I . --go_out=. --go_opt=paths=source_relative plugin.proto
+
+// Options are the SDK options to use for instantiation.
+var Options = []sdk.Option{
+	sdk.WithComponents(&{{.Component}}{}),
+	sdk.WithMappers(ReleaseMapper),
+}
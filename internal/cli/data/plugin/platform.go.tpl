@@ -0,0 +1,149 @@
+package {{.Package}}
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/waypoint-plugin-sdk/component"
+	"github.com/hashicorp/waypoint-plugin-sdk/docs"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+)
+
+// PlatformConfig is the configuration structure for the platform. It is
+// decoded from the "use" block of the deploy stanza in waypoint.hcl.
+type PlatformConfig struct {
+	// Domain is the domain that deployments are reachable under.
+	Domain string `hcl:"domain,optional"`
+}
+
+// Platform deploys an Artifact.
+type Platform struct {
+	config PlatformConfig
+}
+
+// Config implements component.Configurable
+func (p *Platform) Config() (interface{}, error) {
+	return &p.config, nil
+}
+
+// ConfigSet is called after the configuration is decoded, so that it can
+// be validated.
+func (p *Platform) ConfigSet(config interface{}) error {
+	c, ok := config.(*PlatformConfig)
+	if !ok {
+		return fmt.Errorf("expected *PlatformConfig, got %T", config)
+	}
+
+	if strings.Contains(c.Domain, "/") {
+		return fmt.Errorf("domain must be a domain name, not a URL")
+	}
+
+	return nil
+}
+
+// DeployFunc implements component.Platform
+func (p *Platform) DeployFunc() interface{} {
+	// Waypoint calls the returned function with the arguments it asks
+	// for, so add any arguments Deploy needs to its signature.
+	return p.Deploy
+}
+
+// DestroyFunc implements component.Destroyer
+func (p *Platform) DestroyFunc() interface{} {
+	return p.Destroy
+}
+
+// Deploy deploys an artifact.
+func (p *Platform) Deploy(
+	ctx context.Context,
+	log hclog.Logger,
+	src *component.Source,
+	artifact *Artifact,
+	deployConfig *component.DeploymentConfig,
+	ui terminal.UI,
+) (*Deployment, error) {
+	id, err := component.Id()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Deployment{
+		Id:   id,
+		Name: strings.ToLower(fmt.Sprintf("%s-%s", src.App, id)),
+	}
+	if p.config.Domain != "" {
+		result.Url = fmt.Sprintf("https://%s.%s", result.Name, p.config.Domain)
+	}
+
+	sg := ui.StepGroup()
+	defer sg.Wait()
+
+	step := sg.Add("Deploying %s...", artifact.Location)
+	defer step.Abort()
+
+	// TODO: deploy the artifact. The deployment should set the variables in
+	// deployConfig.Env so that the Waypoint entrypoint can connect to the
+	// server.
+	log.Debug("deploying", "name", result.Name, "env", len(deployConfig.Env))
+
+	step.Update("Deployed %s", result.Name)
+	step.Done()
+
+	return result, nil
+}
+
+// Destroy destroys a deployment.
+func (p *Platform) Destroy(
+	ctx context.Context,
+	log hclog.Logger,
+	deployment *Deployment,
+	ui terminal.UI,
+) error {
+	sg := ui.StepGroup()
+	defer sg.Wait()
+
+	step := sg.Add("Destroying %s...", deployment.Name)
+	defer step.Abort()
+
+	// TODO: destroy the deployment.
+
+	step.Update("Destroyed %s", deployment.Name)
+	step.Done()
+
+	return nil
+}
+
+// Documentation is shown by "waypoint docs".
+func (p *Platform) Documentation() (*docs.Documentation, error) {
+	doc, err := docs.New(docs.FromConfig(&PlatformConfig{}), docs.FromFunc(p.DeployFunc()))
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Description("Deploys the application")
+	doc.Input("{{.Package}}.Artifact")
+	doc.Output("{{.Package}}.Deployment")
+
+	doc.Example(`
+deploy {
+  use "{{.Name}}" {
+    domain = "example.com"
+  }
+}
+`)
+
+	doc.SetField(
+		"domain",
+		"the domain that deployments are reachable under",
+	)
+
+	return doc, nil
+}
+
+var (
+	_ component.Platform     = (*Platform)(nil)
+	_ component.Configurable = (*Platform)(nil)
+	_ component.Destroyer    = (*Platform)(nil)
+)
@@ -0,0 +1,71 @@
+package {{.Package}}
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/waypoint-plugin-sdk/component"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+)
+
+func TestPlatform(t *testing.T) {
+	ctx := context.Background()
+	ui := terminal.NonInteractiveUI(ctx)
+
+	var p Platform
+	if diags := component.Configure(&p, testConfig(t, `domain = "example.com"`), nil); diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	result, err := p.Deploy(
+		ctx,
+		hclog.L(),
+		&component.Source{App: "test", Path: t.TempDir()},
+		&Artifact{Location: "dist"},
+		&component.DeploymentConfig{},
+		ui,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(result.Name, "test-") {
+		t.Fatalf("bad name: %q", result.Name)
+	}
+	if !strings.HasSuffix(result.Url, ".example.com") {
+		t.Fatalf("bad url: %q", result.Url)
+	}
+
+	if err := p.Destroy(ctx, hclog.L(), result, ui); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestPlatform_acceptance tests the platform through the plugin process.
+func TestPlatform_acceptance(t *testing.T) {
+	raw := testPlugin(t, component.PlatformType)
+	if _, ok := raw.(component.Platform); !ok {
+		t.Fatalf("expected a component.Platform, got %T", raw)
+	}
+
+	// Valid configuration is accepted
+	if diags := component.Configure(raw, testConfig(t, `domain = "example.com"`), nil); diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	// Invalid configuration is rejected by ConfigSet
+	if diags := component.Configure(raw, testConfig(t, `domain = "https://example.com"`), nil); !diags.HasErrors() {
+		t.Fatal("expected a URL to be rejected")
+	}
+
+	// Documentation is served
+	doc, err := component.Documentation(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Details().Description == "" {
+		t.Fatal("expected a description")
+	}
+}
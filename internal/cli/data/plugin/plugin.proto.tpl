@@ -0,0 +1,30 @@
+syntax = "proto3";
+
+package {{.Package}};
+
+option go_package = "{{.Module}}/{{.Package}}";
+
+// The messages below are the values that the plugin passes to and from
+// Waypoint. Every value a component returns must be a protobuf message so
+// that it can be sent over gRPC and stored by the server. After changing
+// this file, run "make protos" to regenerate plugin.pb.go.
+
+// Artifact is the result of a build.
+message Artifact {
+  // location is the path of the build output.
+  string location = 1;
+}
+
+// Deployment is a deployed artifact.
+message Deployment {
+  string id = 1;
+  string name = 2;
+
+  // url is where the deployment can be reached.
+  string url = 3;
+}
+
+// Release is a deployment that is made available to users.
+message Release {
+  string url = 1;
+}
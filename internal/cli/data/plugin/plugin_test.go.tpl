@@ -0,0 +1,82 @@
+package {{.Package}}
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	sdk "github.com/hashicorp/waypoint-plugin-sdk"
+	"github.com/hashicorp/waypoint-plugin-sdk/component"
+	"github.com/hashicorp/waypoint-plugin-sdk/internal-shared/pluginclient"
+)
+
+// testServeEnv is set when testPlugin launches the test binary as the
+// plugin.
+const testServeEnv = "WAYPOINT_PLUGIN_TEST_SERVE"
+
+func TestMain(m *testing.M) {
+	// When launched by testPlugin, serve the plugin just like the plugin
+	// binary does rather than run the tests.
+	if os.Getenv(testServeEnv) != "" {
+		sdk.Main(Options...)
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+// testPlugin launches the plugin the same way that Waypoint does and
+// returns the client for the component of the given type. Calls to the
+// client are sent over gRPC to the plugin process, so this tests the
+// plugin as Waypoint sees it.
+func testPlugin(t *testing.T, typ component.Type) interface{} {
+	t.Helper()
+
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = append(os.Environ(), testServeEnv+"=1")
+
+	config := pluginclient.ClientConfig(hclog.New(&hclog.LoggerOptions{
+		Name:  "plugin",
+		Level: hclog.Debug,
+	}))
+	config.Cmd = cmd
+
+	client := plugin.NewClient(config)
+	t.Cleanup(client.Kill)
+
+	rpcClient, err := client.Client()
+	if err != nil {
+		t.Fatalf("error launching plugin: %s", err)
+	}
+
+	raw, err := rpcClient.Dispense(strings.ToLower(typ.String()))
+	if err != nil {
+		t.Fatalf("error requesting %s: %s", typ, err)
+	}
+
+	return raw
+}
+
+// testConfig parses src as the body of a "use" block.
+func testConfig(t *testing.T, src string) hcl.Body {
+	t.Helper()
+
+	f, diags := hclsyntax.ParseConfig([]byte(src), "waypoint.hcl", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	return f.Body
+}
+
+func TestReleaseMapper(t *testing.T) {
+	result := ReleaseMapper(&Deployment{Url: "https://example.com"})
+	if result.Url != "https://example.com" {
+		t.Fatalf("bad url: %q", result.Url)
+	}
+}
@@ -0,0 +1,120 @@
+package {{.Package}}
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/waypoint-plugin-sdk/component"
+	"github.com/hashicorp/waypoint-plugin-sdk/docs"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+)
+
+// ReleaseManagerConfig is the configuration structure for the release
+// manager. It is decoded from the "use" block of the release stanza in
+// waypoint.hcl.
+type ReleaseManagerConfig struct {
+	// URL is the address that releases are reachable at.
+	URL string `hcl:"url,optional"`
+}
+
+// ReleaseManager releases a Deployment.
+type ReleaseManager struct {
+	config ReleaseManagerConfig
+}
+
+// Config implements component.Configurable
+func (r *ReleaseManager) Config() (interface{}, error) {
+	return &r.config, nil
+}
+
+// ConfigSet is called after the configuration is decoded, so that it can
+// be validated.
+func (r *ReleaseManager) ConfigSet(config interface{}) error {
+	c, ok := config.(*ReleaseManagerConfig)
+	if !ok {
+		return fmt.Errorf("expected *ReleaseManagerConfig, got %T", config)
+	}
+
+	if c.URL != "" {
+		if _, err := url.ParseRequestURI(c.URL); err != nil {
+			return fmt.Errorf("url is invalid: %s", err)
+		}
+	}
+
+	return nil
+}
+
+// ReleaseFunc implements component.ReleaseManager
+func (r *ReleaseManager) ReleaseFunc() interface{} {
+	// Waypoint calls the returned function with the arguments it asks
+	// for, so add any arguments Release needs to its signature.
+	return r.Release
+}
+
+// Release releases a deployment.
+func (r *ReleaseManager) Release(
+	ctx context.Context,
+	log hclog.Logger,
+	src *component.Source,
+	ui terminal.UI,
+	target *Deployment,
+) (*Release, error) {
+	sg := ui.StepGroup()
+	defer sg.Wait()
+
+	step := sg.Add("Releasing %s...", target.Name)
+	defer step.Abort()
+
+	// TODO: release the deployment.
+	result := &Release{Url: r.config.URL}
+	if result.Url == "" {
+		result.Url = target.Url
+	}
+	log.Debug("releasing", "deployment", target.Id, "url", result.Url)
+
+	step.Update("Released %s", target.Name)
+	step.Done()
+
+	return result, nil
+}
+
+// Documentation is shown by "waypoint docs".
+func (r *ReleaseManager) Documentation() (*docs.Documentation, error) {
+	doc, err := docs.New(docs.FromConfig(&ReleaseManagerConfig{}), docs.FromFunc(r.ReleaseFunc()))
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Description("Releases a deployment of the application")
+	doc.Input("{{.Package}}.Deployment")
+	doc.Output("{{.Package}}.Release")
+
+	doc.Example(`
+release {
+  use "{{.Name}}" {
+    url = "https://example.com"
+  }
+}
+`)
+
+	doc.SetField(
+		"url",
+		"the address that releases are reachable at",
+		docs.Summary(
+			"This defaults to the URL of the deployment.",
+		),
+	)
+
+	return doc, nil
+}
+
+// URL implements component.Release
+func (r *Release) URL() string { return r.Url }
+
+var (
+	_ component.ReleaseManager = (*ReleaseManager)(nil)
+	_ component.Configurable   = (*ReleaseManager)(nil)
+	_ component.Release        = (*Release)(nil)
+)
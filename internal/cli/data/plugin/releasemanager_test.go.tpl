@@ -0,0 +1,63 @@
+package {{.Package}}
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/waypoint-plugin-sdk/component"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+)
+
+func TestReleaseManager(t *testing.T) {
+	ctx := context.Background()
+
+	var r ReleaseManager
+	if diags := component.Configure(&r, testConfig(t, ``), nil); diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	result, err := r.Release(
+		ctx,
+		hclog.L(),
+		&component.Source{App: "test", Path: t.TempDir()},
+		terminal.NonInteractiveUI(ctx),
+		&Deployment{Id: "1", Name: "test-1", Url: "https://test-1.example.com"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a URL configured, the release uses the deployment URL
+	if result.URL() != "https://test-1.example.com" {
+		t.Fatalf("bad url: %q", result.URL())
+	}
+}
+
+// TestReleaseManager_acceptance tests the release manager through the
+// plugin process.
+func TestReleaseManager_acceptance(t *testing.T) {
+	raw := testPlugin(t, component.ReleaseManagerType)
+	if _, ok := raw.(component.ReleaseManager); !ok {
+		t.Fatalf("expected a component.ReleaseManager, got %T", raw)
+	}
+
+	// Valid configuration is accepted
+	if diags := component.Configure(raw, testConfig(t, `url = "https://example.com"`), nil); diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	// Invalid configuration is rejected by ConfigSet
+	if diags := component.Configure(raw, testConfig(t, `url = "example"`), nil); !diags.HasErrors() {
+		t.Fatal("expected an invalid URL to be rejected")
+	}
+
+	// Documentation is served
+	doc, err := component.Documentation(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Details().Description == "" {
+		t.Fatal("expected a description")
+	}
+}
@@ -1,6 +1,21 @@
 // Code generated by go-bindata.
 // sources:
 // data/init.tpl.hcl
+// data/plugin/Makefile.tpl
+// data/plugin/README.md.tpl
+// data/plugin/builder.go.tpl
+// data/plugin/builder_test.go.tpl
+// data/plugin/gitignore.tpl
+// data/plugin/go.mod.tpl
+// data/plugin/main.go.tpl
+// data/plugin/mapper.go.tpl
+// data/plugin/options.go.tpl
+// data/plugin/platform.go.tpl
+// data/plugin/platform_test.go.tpl
+// data/plugin/plugin.proto.tpl
+// data/plugin/plugin_test.go.tpl
+// data/plugin/releasemanager.go.tpl
+// data/plugin/releasemanager_test.go.tpl
 // DO NOT EDIT!
 
 package datagen
@@ -88,6 +103,306 @@ func initTplHcl() (*asset, error) {
 	return a, nil
 }
 
+var _pluginMakefileTpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x85\x50\xcd\x6a\x84\x30\x18\x3c\x6f\x9e\xe2\x83\x7a\x70\x0f\x26\x77\xe9\x16\xb6\x50\x5c\xa1\x75\xa5\xb4\x94\x3d\x95\xa8\xd1\x0d\x6a\x22\x26\xb2\x94\x65\xdf\xbd\xf9\xd1\x0a\xed\xa1\x87\x24\x26\xe3\xcc\x37\x33\xf9\xf3\x7b\x92\x66\x9f\xd9\xfe\xe5\x69\x77\xbd\xe2\x8c\xf6\xec\x76\x43\xe8\x0e\xde\xce\x0c\x86\x6e\x6a\xb8\x80\x7e\x52\x1a\x0a\x06\xc2\x80\x15\x5c\xe8\xd7\x20\xb9\xd0\x91\x47\xa3\x7b\xfb\xfc\x00\xb5\x1c\xe1\x63\x86\x40\x4b\xa8\xb9\xa8\x80\x6b\x8c\x1e\xd3\x6c\xff\x7a\xda\xfd\xa6\x05\x61\xbe\x8e\xde\x22\x84\xf3\xc3\x31\x3b\xc5\x40\xbb\x0e\x99\x15\xc3\x30\x4a\x2d\x15\x14\x13\xef\x2a\xd0\x4c\x69\xeb\x2a\x61\x82\x8d\xd4\xdc\x40\x1b\x7f\x89\x84\x52\x56\xcc\xcd\xb6\xf7\x9e\x29\x45\x1b\x03\x1a\xd3\x7e\x0c\x76\x2a\xf8\x47\xdd\x8b\x22\x7f\xc4\x68\xd3\x48\x68\x66\x49\xc0\xc4\x14\x90\xd3\xb2\x35\x12\xb6\x83\x85\xe3\x1c\x20\xb7\x7b\x86\xb7\x14\x49\xc3\x28\xb8\x20\x41\xe8\x23\x6e\x01\xaf\x24\x67\xd8\x6e\x9e\x62\xbf\xcc\xef\x18\x63\x9b\x22\x15\x4a\x9b\x8c\x3e\xc4\x5c\xf2\xe5\xcc\x46\xb6\x36\xd8\x49\xd9\x2a\x17\xcc\xe3\x6a\xcd\xc0\x3d\x1b\xcd\xe7\x62\x70\xd3\xb7\x15\x1f\x21\x1a\x20\x08\x0f\x47\xd3\x29\xc1\xa5\x14\x35\x6f\xc8\x52\x3d\x99\xa5\xd0\xa6\x1c\xfe\x78\xff\x8f\x44\xd0\x37\xf2\xff\x18\x82\x2b\x02\x00\x00"
+
+func pluginMakefileTplBytes() ([]byte, error) {
+	return bindataRead(
+		_pluginMakefileTpl,
+		"plugin/Makefile.tpl",
+	)
+}
+
+func pluginMakefileTpl() (*asset, error) {
+	bytes, err := pluginMakefileTplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "plugin/Makefile.tpl", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _pluginReadmeMdTpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x75\x54\xb1\x8e\xdb\x30\x0c\xdd\xf5\x15\xc4\x65\x3b\x24\xce\x7e\x63\x3b\xdc\xd2\x16\xc1\x35\x40\xc7\x8a\x91\x19\x5b\x8d\x2c\xb9\x92\x9c\xc0\x0d\xfc\xef\xa5\x2c\xdb\x49\x0e\x77\x80\x07\x89\x22\x1f\xc9\xc7\x47\xaf\xe0\x17\xf6\xad\xd3\x36\xc2\xce\x74\x95\xb6\x2f\x70\xbd\x16\x3f\xb0\xa1\x61\x10\x62\x5f\xeb\x00\xfc\xe1\xcd\xab\x1d\xbd\x20\xd6\xc8\x67\xef\xce\xba\xa4\xc0\x37\x82\xa7\x25\xee\x29\x41\xec\xfb\x96\xf2\xb5\x10\x62\xb5\x82\x57\x8a\x51\xdb\x0a\x7e\x46\xf4\x91\x4a\x21\xbe\x74\xda\x94\xc9\x92\x82\x27\x54\x4f\x7f\x3b\xed\x19\xf0\xd5\xad\x41\x32\x7c\x74\x4a\xae\x01\x6d\x39\xdf\x36\x15\xd9\x4d\xe5\x64\x01\x7b\x27\xf8\x4c\x1e\x23\x65\x88\xf4\x7e\xe8\x8e\xa0\x5c\x49\x6b\x38\x24\xf8\x3b\xec\x8c\xe2\x3b\x3b\xda\x22\x85\x18\x5e\x84\x90\x52\x86\x9a\x8c\x11\x95\x83\xc6\xb1\xbf\x2e\x7b\xd1\xe0\x89\xd2\x0b\xf7\xcf\x56\xbe\xdc\x97\x88\x67\xd4\x06\x0f\x86\x8d\x6e\x61\x65\x0d\xda\x86\x88\xc6\x80\x8e\x70\xd1\xb1\x06\x39\xc6\x4d\x56\x29\x9c\xe7\xb2\xda\x1e\xe4\x41\xdb\xed\x65\x8a\xda\x64\xc8\xcd\x42\x9c\xe4\x00\x46\x4d\xd9\x64\x31\x7b\x6d\xb3\x57\x90\xa2\x64\x6a\x54\x74\xbe\x07\x77\x84\xde\x75\x3e\xf5\xfc\x87\x4d\x4c\x46\x4d\x16\xba\x40\x29\x3f\x17\x29\xe7\xe0\xa2\x56\x46\xe6\x3e\xf9\x24\x38\x13\xf3\x6f\xff\xe1\x30\xc0\x55\xc0\x18\xf1\x30\xb7\x41\x0c\xb9\x73\x9e\xd8\x37\xe4\x1c\x51\xb0\xdb\x73\x6a\x47\xdb\x82\x79\x87\x4d\xca\x05\x64\xa3\x9f\x04\xc1\xb5\xdc\xd1\xc3\xfd\xa1\xef\x8b\x29\x8a\xa1\x77\xa8\x4e\x58\x31\xfa\xf6\x1d\x84\x72\x4d\xeb\x2c\x03\x85\x71\x32\x0d\xb6\x2d\xf9\x70\x8f\x15\xc8\x9f\x29\x7c\x88\x35\x09\x8c\xc5\x75\x03\x7c\x14\x1d\xcc\xda\xbd\xd4\xe4\xc7\x09\x32\x0c\xc0\xc5\xf9\xd3\xbb\x92\xeb\x94\xd9\x7e\x9c\x27\xbb\x14\xa3\xb6\xe6\x3c\x0d\x85\xc0\xaf\x0f\xa5\xb6\x18\x42\xb2\xb8\xd4\xcb\x98\xe8\xe8\x5d\xb3\xe8\xa3\x80\x37\x16\x5e\xd6\xc4\x88\x15\x24\xe0\x31\x12\xab\xa2\x46\x5b\xe5\x2d\xe0\x5a\x8f\xda\xd0\x27\xdc\x25\x76\xa6\x66\xbf\x2f\x54\xf1\x0e\x2a\x67\xcf\xe4\x23\x1c\x28\x5e\x88\x45\x10\x99\x82\x8f\x7b\x79\xfe\x9d\x54\x3f\x13\x96\x16\x60\xd4\x0d\xa0\x52\xd4\xb2\x2a\xd4\xb4\x16\x60\xb0\xb3\xaa\x5e\x28\x5b\x36\x9e\x20\x30\xb7\xc0\xda\xba\xfd\x0e\x4a\x47\x79\x7e\xac\xf3\x53\xea\x9f\x05\xe8\xb8\x20\xa8\xde\x76\x5f\x0b\xf1\x1f\x0f\x01\xe4\x3b\x5f\x04\x00\x00"
+
+func pluginReadmeMdTplBytes() ([]byte, error) {
+	return bindataRead(
+		_pluginReadmeMdTpl,
+		"plugin/README.md.tpl",
+	)
+}
+
+func pluginReadmeMdTpl() (*asset, error) {
+	bytes, err := pluginReadmeMdTplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "plugin/README.md.tpl", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _pluginBuilderGoTpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x95\x56\x5d\x6f\xd3\x3c\x14\xbe\x6e\x7e\xc5\x21\x12\x28\xa9\x82\x7b\x3f\x69\x17\x85\x32\x34\xf4\x8a\x21\x0d\xc4\x25\x73\x1c\x27\xb5\x9a\xda\x91\xed\x6c\xeb\x5b\xf5\xbf\x73\xec\x38\xa9\x33\x3a\x09\x76\xb3\xc4\x39\xcf\xf9\x7e\x1e\xb7\xa3\x6c\x47\x1b\x0e\xc7\x23\xf9\x36\x3c\x9e\x4e\x49\x22\xf6\x9d\xd2\x16\xb2\x64\x91\x32\x25\x2d\x7f\xb6\x29\x3e\xd6\x7b\xff\xaf\xa3\x76\xbb\xaa\x45\xcb\xdd\x43\x9a\xe0\x49\x23\xec\xb6\x2f\x09\x53\xfb\xd5\x96\x9a\xad\x60\x4a\x77\xab\x27\x7a\xe8\x94\x90\xf6\x7d\xd7\xf6\x8d\x90\xef\x4d\xb5\x5b\xa1\x45\xa7\x24\x97\xde\xcf\x5f\xa3\x2a\xc5\xcc\x3f\x01\x2c\xd7\x7b\x21\x69\x9b\x26\x79\x92\xac\x56\xf0\xa1\x17\x6d\xc5\xf5\x47\x25\x6b\xd1\x80\x30\x60\xb7\x1c\x98\x7f\xeb\x35\xb5\x42\x49\x30\x56\xf7\xcc\xf6\x9a\x43\xad\xb4\xff\x5e\x0e\x20\x02\xb7\x16\x21\xce\x4d\xc5\x99\xaa\x78\x05\xb5\x56\x7b\x6f\x92\xf6\x86\xa7\x50\xb6\x8a\xed\x40\xd5\x67\x14\x7a\xa3\xf2\x7f\x0a\x42\xc2\x98\x1e\xd9\xb2\x96\x24\xf6\xd0\xf1\x17\xd9\x0c\x81\xe1\x98\x2c\x30\xc2\x46\x68\xce\xac\xd2\x87\x31\x49\xd7\xe2\xb9\x6b\xd5\xdb\xae\xb7\x05\x68\xde\x62\xe6\x8f\x1c\xac\x72\x5f\x3d\x9c\x76\x5d\x2b\x98\x2f\x88\x24\x8b\xb3\x33\x8c\x21\x64\x03\x0f\x98\xc3\x55\x5a\x8d\xc7\x85\xea\x9c\x25\xb6\xe9\x21\x39\xc5\x7d\x1a\x22\x19\xa0\x32\xf6\x88\xd5\x60\x28\x3c\x5b\x6b\x2b\x6a\xca\xec\xbc\x9c\xa8\x90\xa1\xb3\xf3\x3a\x43\x84\x71\x04\xfb\xae\xe5\x7b\xdc\x03\x03\xd3\x4a\x90\x8f\xe3\x40\xca\x96\x27\x75\x2f\x19\x64\x25\x2c\x83\x97\x3c\x40\xb3\x1c\x32\x4c\x84\x6b\xcc\x80\x1f\x4f\x05\x70\xad\x15\x7e\xc5\xb0\x9a\xe3\xfc\x24\xbc\x73\x1b\xe2\x4c\x0b\x90\xa2\x9d\xc5\xbd\xe7\x6e\x94\xc0\x68\xdb\xe2\x18\x69\x8d\x6e\x2e\xac\x02\x5a\x84\x49\x17\x60\x5c\x73\x29\xa2\x2c\xa2\xa4\xf3\x54\x72\x78\xa4\xad\xa8\xa8\xe5\x15\x79\x35\x4d\x8c\x94\x85\x36\x44\xd9\xe6\x43\xb6\xbe\x47\x05\xa8\x1d\x5c\x5d\x87\xd8\x24\x5b\xce\xda\x95\x27\x0b\x51\xc3\x1b\x34\x41\xdb\xb1\x32\xe4\x1f\xf9\xe4\x1c\xd4\x59\xca\x9f\x3b\x9c\x22\x56\x31\xc7\x15\xd0\x28\x0b\x6f\xbf\xa7\x45\x70\x8c\x8e\xb0\x03\xce\xd9\xc8\x58\x72\x6b\xd6\xa5\xc9\x18\x99\xf6\x23\x7f\x2d\xc8\xb4\x2a\xb0\xef\x8d\x75\xa5\xbf\xd8\xba\x78\x3f\xd2\x10\x2a\xf8\x39\xf7\xde\x27\x78\xe3\x1a\x75\x71\xec\x21\xff\x0b\xad\x9c\x80\x38\xf4\xa8\x8b\x81\x2b\x3f\x03\xb3\xfc\x38\x07\xb6\x0c\xa1\x1d\x43\x11\xe4\x67\xf9\x84\x92\x31\x24\xaa\x9b\x7e\x88\x8c\xa3\xa4\x66\x67\xbc\x0f\xe4\xba\x9f\x31\xad\x70\x1d\xe4\x21\xb2\xf2\xb1\x41\x72\x8e\x3c\xc0\x5a\x05\x1e\x19\xd1\x48\xea\x24\x82\x4c\x45\x96\x43\xf6\x71\xa1\x23\x79\x5e\x74\x87\xbc\x56\x1f\x6a\x2c\xb3\xcf\x10\x64\xd6\x91\xc0\xfd\x2f\x92\x45\x2f\x60\xd4\x31\xf2\xe3\x16\x0f\x8c\x66\xb0\x3c\xf7\xed\x5e\xf5\x9a\xf1\x22\x41\x42\x2c\x47\x4a\xc6\x74\x30\x8d\x5b\xaf\x5e\x90\x7b\xcb\xbb\xcf\x5a\xf5\x5d\x86\x13\xaa\x78\xed\xb8\xda\x90\x9f\x54\x58\x3c\x40\x3b\xfc\xec\x2c\xf1\x6c\x5d\x55\x59\xea\xb3\x72\x72\x11\x67\x4f\x48\x7a\x06\x23\x80\xac\x4b\xbc\x20\x3c\x1e\x0b\xff\x7e\xb7\xb9\xbb\x0a\xfa\xf4\x47\xdd\x0b\x94\xc7\x81\x58\x18\x64\x5a\xc2\x2f\x38\xba\x0c\x2b\xc2\x5b\xc7\x6e\x0b\x18\x39\x1b\x2d\x65\x48\x8d\xfc\xe8\x1c\xd7\xb2\x74\x1d\x49\x91\x0b\x65\xdd\x5c\xde\x1a\xdc\xf4\x31\x40\x1e\x10\x1b\x6c\x90\x4f\x6d\x54\x84\xb1\x3d\x6e\xcd\xff\x0b\xc6\x57\x13\x0c\x5b\x7b\x8a\xb5\x62\xa3\x98\x5f\x82\x49\x0d\xcc\x56\x3d\x61\xcc\x03\xa4\xa3\x9a\x83\xbf\x92\x2e\xcd\x74\x06\x76\x62\xb5\x74\xa6\x64\x76\x1c\x4f\x09\xbf\xfa\x57\xd7\x1c\x6f\xf9\x95\x3f\x65\xfe\xe1\x06\x6f\x99\xa0\x79\xef\x66\x1c\x47\x1d\x29\x60\x32\xf1\xfc\x08\x7b\x38\x70\x25\x1f\xc4\xc3\x39\x7d\x73\xed\xea\x8a\xe9\x8d\xaf\x3e\xde\xc0\x55\xf4\x42\x36\xdc\x30\x2d\xfc\x55\x10\x86\x6f\x2e\x51\xdb\x99\xde\xf9\xbb\x27\x4b\xe3\x1f\x0b\x64\x6c\x6e\x9a\x07\x87\x9f\x9e\xa9\xe3\x79\xf6\x90\x0c\x1b\x71\x4c\x00\xf0\xa2\x04\x07\xfb\x4a\xf7\x88\x49\xfd\x19\xc0\x59\x5f\xae\x01\xc5\xc6\xe0\xcf\x02\x80\x13\x8e\xe1\x61\xf4\x85\x3a\x7a\x23\xb8\x67\xc9\xe2\x2c\x47\x69\xe1\x5e\xff\xe9\x7e\x9c\x95\x83\xf0\x68\x3d\xfc\x08\xc2\xf8\x1f\xa9\x76\xbf\x7a\x7e\xfd\xa9\x4f\xe0\xff\xae\x61\x12\xea\x3c\x43\x4c\x3e\xb7\x8d\xaf\xb0\x0b\xb6\x79\xf2\x1b\xde\x8d\x5f\x3f\x6f\x09\x00\x00"
+
+func pluginBuilderGoTplBytes() ([]byte, error) {
+	return bindataRead(
+		_pluginBuilderGoTpl,
+		"plugin/builder.go.tpl",
+	)
+}
+
+func pluginBuilderGoTpl() (*asset, error) {
+	bytes, err := pluginBuilderGoTplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "plugin/builder.go.tpl", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _pluginBuilder_testGoTpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb5\x53\x4d\x4f\xdc\x30\x10\x3d\x27\xbf\x62\x88\x54\x94\x54\xc1\xb9\x53\xed\xa1\x94\x56\xa5\xaa\x2a\x24\x96\x5e\xc1\x71\x66\xb3\x2e\x59\xdb\xb5\x9d\x85\xd5\x8a\xff\xde\x71\x9c\xfd\x80\x2d\xa5\x87\xf6\x94\xd1\x7c\xbc\x79\xef\x79\x62\xb8\xb8\xe3\x2d\xc2\x7a\xcd\x2e\x63\xf8\xf8\x98\xa6\x72\x61\xb4\xf5\x90\xa7\x49\x26\xb4\xf2\xf8\xe0\x33\x0a\x0d\xf7\xf3\x6a\x26\x3b\x0c\x41\x48\x78\x74\x5e\xaa\x36\x4b\x29\x6e\xa5\x9f\xf7\x35\x13\x7a\x51\xcd\xb9\x9b\x4b\xa1\xad\xa9\xee\xf9\xca\x68\xa9\xfc\x89\xe9\xfa\x56\xaa\x13\xd7\xdc\x55\xd4\x61\xb4\x42\x35\x40\xfe\xf5\x94\x47\xbb\x90\x8a\x77\x59\x5a\xa4\xe9\xac\x57\x02\xa6\xb4\xfc\xac\x97\x5d\x83\x36\xf7\xf0\x76\xe4\xc2\xa6\x05\xac\xd3\x44\xf8\x07\x38\x9d\xc0\x48\x9e\x9d\x91\xb4\xd6\xea\x5e\x35\x39\x8d\x27\x4b\x6e\xa1\x86\x71\x38\x4d\xe4\x0c\x1a\xc9\x5b\x17\x27\x46\x76\xec\x83\x56\x33\xd9\xf6\x16\xf3\xe3\xba\x84\x00\x1f\x33\xb9\x2f\xe1\xb6\x91\x16\x85\xd7\x76\x05\x13\xc8\x1a\xe9\x7c\x76\x5b\x94\xa0\x64\x57\xbc\x8b\x58\xec\x33\x77\x1f\xad\xd5\xd6\xe5\x03\xa1\xc4\xb3\x4f\xdc\xf3\x2e\x8f\xd5\xa1\x94\x17\x45\x9a\x90\xdb\x89\xb3\x22\xec\x3e\xde\x2d\xbf\xd2\xbd\x15\xb8\x7e\x6f\xcc\x29\x0c\x36\x67\x25\x5c\x92\xeb\xa7\xe0\xd9\x14\x17\xe6\x5c\xd2\xf4\x63\x9a\x58\x74\x7d\x47\x84\xd0\xda\x80\x50\xb3\x41\x54\x4e\xf2\x03\xe5\x68\x19\xfb\xa6\xd5\x05\xf9\x60\xb9\xf0\x72\x89\xd7\x17\xa1\x4c\x6c\x69\x6b\x31\x88\x0f\xc3\x47\x93\xc0\xfe\x09\x53\x4a\x8f\xfc\x42\xcf\x83\x21\xc1\xd8\x84\x2d\x9b\x13\x60\x5f\xe8\x95\x72\x82\x61\x81\x5a\x39\x1a\x41\x0e\x44\x56\xec\xab\x16\xdc\x4b\xad\x02\xf8\x76\x7e\x6f\xc3\x2c\xcf\xb6\xe9\x6e\xd3\xfb\xe6\x67\x09\xad\xf6\xf4\x25\xc9\x9b\x72\xf9\x1c\x72\x20\x46\xd4\xaa\x6a\xff\x0e\x6e\xb8\x10\x68\x3c\x57\x02\x87\x07\x73\xe0\xe7\x08\x75\x2c\x52\x4c\x17\xd0\xce\x87\x5c\xbc\x2c\x30\x56\x0b\x74\x8e\x1d\x5c\xd4\x1e\xd2\xe1\x71\x59\x7e\x1f\x6c\x08\xc9\xcb\x01\x27\x9c\xc4\xee\xed\x46\x88\xe9\xca\x60\xf4\xf7\xa6\x04\x7d\x17\x26\x68\x90\xe5\x07\x8d\x64\xd8\x11\xd5\x5f\x30\x86\x1f\x22\x8f\x06\x4d\xc9\x20\x42\x1c\xdf\x88\x9c\xf8\xce\x3b\xd9\x84\x9b\x1f\xee\x36\xda\x29\x1d\x44\x29\xd8\xbc\x7e\xe8\x84\xf6\x9f\x2f\x9d\x58\x5e\xa8\xe5\xef\x79\x5a\xfc\x11\x25\xd7\x2b\x88\x04\xae\xd0\xff\x03\xd2\xd5\x33\xd6\x47\x7f\xa6\xbd\x67\xbd\x02\x5e\x3b\xdd\xf5\x1e\x61\x87\xe8\x35\xd4\xb8\x25\x9b\xed\x84\x9d\x6b\xd1\x2f\x88\xdb\x56\x90\x43\xbb\x0c\xb6\x37\x5a\x6c\xff\xd0\x9d\x82\x27\xed\x79\x7c\xc8\xd7\x7f\xc6\xc1\x0e\x2d\xd8\x39\x7a\x2e\x3b\xe2\x4e\x91\x13\x56\x9a\x61\xe9\x84\xe4\x66\x2f\xa9\x81\x66\xd7\x99\x8d\x3f\xd0\x2f\x7d\x0b\xfa\xc8\xff\x05\x00\x00"
+
+func pluginBuilder_testGoTplBytes() ([]byte, error) {
+	return bindataRead(
+		_pluginBuilder_testGoTpl,
+		"plugin/builder_test.go.tpl",
+	)
+}
+
+func pluginBuilder_testGoTpl() (*asset, error) {
+	bytes, err := pluginBuilder_testGoTplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "plugin/builder_test.go.tpl", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _pluginGitignoreTpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x4b\xca\xcc\xd3\xe7\x02\x00\xe0\x5c\x7e\xd3\x05\x00\x00\x00"
+
+func pluginGitignoreTplBytes() ([]byte, error) {
+	return bindataRead(
+		_pluginGitignoreTpl,
+		"plugin/gitignore.tpl",
+	)
+}
+
+func pluginGitignoreTpl() (*asset, error) {
+	bytes, err := pluginGitignoreTplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "plugin/gitignore.tpl", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _pluginGoModTpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x75\x8f\x49\x6e\xc3\x30\x0c\x45\xd7\xd1\x29\xb4\x6c\x17\xa2\x49\x49\xce\x70\x88\x1e\xc2\x83\x2a\x0b\x55\x4c\x47\xb1\x1c\x04\x41\xee\x5e\x65\xd8\x64\x61\x10\x20\xb8\x78\x78\xfc\xff\xc8\x7d\x8e\x4e\xde\x6e\xf0\xf3\xbc\xee\x77\x21\x3c\x4b\x02\xaa\x85\x48\xee\x94\x43\x72\xf2\x4b\x6c\x7c\x98\x87\xdc\x42\xc7\xc7\xca\x73\x6c\x46\x5f\x4d\x89\x67\x6e\xf3\xaf\x5c\x08\x2c\xe8\x0f\x64\x68\xce\x43\xe8\x38\x4d\x05\x56\x43\x17\xd9\xcb\x05\x81\x2c\xd0\x3a\x36\xc5\xec\xc3\xf8\xb0\x19\xc0\x15\xac\xa8\xaa\x45\xcb\x45\xc3\x0e\x48\x21\x68\xd4\x48\xa8\x0d\x22\xee\x6c\xad\x4c\xef\xb6\xe4\xba\xb6\xd1\x87\xfd\x8a\xe1\xd2\x5c\x27\x0e\xe3\xfc\x7e\xa7\xce\xfd\xdf\x23\x5a\x19\xf5\x94\x95\xa5\xd1\x18\xdc\x2b\xb2\xd8\x23\xd9\xba\x3d\xa0\x2b\x32\x66\x1f\x1d\xbc\xba\x03\xa7\xcf\xfe\xba\x2e\x91\xbf\xc5\x3f\xd6\x31\x8d\xcf\x4d\x01\x00\x00"
+
+func pluginGoModTplBytes() ([]byte, error) {
+	return bindataRead(
+		_pluginGoModTpl,
+		"plugin/go.mod.tpl",
+	)
+}
+
+func pluginGoModTpl() (*asset, error) {
+	bytes, err := pluginGoModTplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "plugin/go.mod.tpl", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _pluginMainGoTpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x4d\x8f\x31\x6e\xc3\x30\x0c\x45\x67\xf3\x14\x84\x27\x7b\x88\x74\x88\xce\x41\x83\x2e\x9d\x19\x59\xb1\x04\xdb\xa4\x60\xd1\x29\x0a\x43\x77\xaf\xa0\x74\xc8\x46\x3c\x90\xff\x7d\x26\x72\x0b\xcd\x1e\x37\x8a\x0c\x10\xb7\x24\xbb\xe2\x00\x5d\x9e\x16\xec\xe7\xa8\xe1\xb8\x1b\x27\x9b\x0d\x94\x43\x74\xb2\x27\xfb\x43\xbf\x49\x22\xeb\x25\xad\xc7\x1c\xf9\x52\x37\x7b\x80\xae\x3f\x4f\x73\x95\xe9\x58\x7d\x29\xb6\xce\xb7\x57\x70\x29\x3d\x8c\x00\x8f\x83\x5d\x73\x0c\x23\x9e\xd0\x59\x8b\xf5\xcc\x5c\x2b\xc0\xec\xf7\xa7\xcf\xa8\xc1\x63\x15\x25\x61\xcf\x9a\x51\x1e\x8d\xbc\x1c\x28\x4f\xbf\xe3\xfc\x75\xfb\xc0\x2c\x95\x93\xb6\x88\xef\xff\x26\xe8\x88\x71\xa5\xaa\x08\x48\x3c\xa1\xd2\xba\xa0\xca\x5b\x80\x69\x0f\x35\xdf\xf0\xde\xcd\x7c\x26\x8d\xc2\xd9\x18\x33\x42\x81\x3f\x1c\x41\x9d\xa7\x0d\x01\x00\x00"
+
+func pluginMainGoTplBytes() ([]byte, error) {
+	return bindataRead(
+		_pluginMainGoTpl,
+		"plugin/main.go.tpl",
+	)
+}
+
+func pluginMainGoTpl() (*asset, error) {
+	bytes, err := pluginMainGoTplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "plugin/main.go.tpl", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _pluginMapperGoTpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x55\x90\xc1\x4e\x03\x31\x0c\x44\xcf\xcd\x57\xcc\x09\x41\x85\xd2\x3b\x67\xae\x08\x84\x40\x9c\x4d\xe2\x76\xa3\x66\x93\xc8\xc9\xb6\xaa\xaa\xfd\x77\x1c\xb6\x55\xe1\xe6\xd8\x6f\xc6\x13\x17\x72\x7b\xda\x31\xce\x67\xfb\xb6\x94\xf3\x6c\xcc\x66\x83\x77\x8e\x4c\x95\x5f\xa8\x14\x16\x8c\x54\x2a\x08\xcf\x5c\x62\x3e\x8d\x9c\x1a\x5a\xd6\xf7\x05\xb2\x2a\xe8\x9a\x05\xae\x70\x39\x1d\x58\x9a\x02\x07\x8a\x13\x23\xa4\x4e\xa7\xdc\x06\xb5\x6a\xa7\xc2\x38\x0e\x9c\x74\xec\xf2\x58\x72\xea\x76\x54\xf7\x15\xdb\x2c\xa0\x6e\xf4\xcb\xb4\x81\x1a\x52\x06\x93\xc4\xa0\xc2\xda\xb8\xa0\x48\xf6\x93\x63\x6f\xf1\x31\x84\x0a\x15\x23\x72\xeb\xd9\xfc\x2d\xdb\x37\x63\xaa\xec\xbb\x93\x0f\xc2\xae\xc5\x93\x2e\x50\x46\x96\xbc\xcb\x7a\x4d\x03\x0d\x1c\x83\xa3\x16\x72\xc2\xa0\x88\xae\xbb\x32\x23\x25\xbd\x86\xd8\xbf\x1f\x23\x61\x9d\xef\x82\x46\x11\xf6\x38\x86\x36\xa0\xfa\xbd\xfd\xd2\xe2\xca\x84\x84\xd7\xd2\x0d\xab\x35\xdb\x29\xb9\xff\x97\xbc\xaf\xe2\xb0\xbe\xdd\xf1\x01\xeb\xcb\x1c\x67\xb3\x12\x6e\x93\x24\xdc\x5d\x5a\xda\x59\x7d\x4a\x7c\x82\x8a\xac\x16\x8f\x66\x35\x9b\xd9\xfc\x00\x06\xa9\x68\xe3\xb4\x01\x00\x00"
+
+func pluginMapperGoTplBytes() ([]byte, error) {
+	return bindataRead(
+		_pluginMapperGoTpl,
+		"plugin/mapper.go.tpl",
+	)
+}
+
+func pluginMapperGoTpl() (*asset, error) {
+	bytes, err := pluginMapperGoTplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "plugin/mapper.go.tpl", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _pluginOptionsGoTpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x45\x50\xc1\x4e\xc3\x30\x0c\x3d\x93\xaf\xb0\x76\x40\x9d\x44\xd3\x3b\x52\x4f\x70\x41\x08\x6d\x82\x03\x07\x84\x26\x53\xbc\x34\x5a\x17\x5b\x89\x3b\x84\xaa\xfc\x3b\x59\x0b\xe3\xf6\x9e\xfd\xde\x93\x9f\x05\xbb\x03\x3a\x82\x69\xb2\xdb\x05\xe6\x6c\x8c\x3f\x0a\x47\x85\xca\x5c\xa5\xcf\x03\xac\x9c\xd7\x7e\xfc\xb0\x1d\x1f\x9b\x1e\x53\xef\x3b\x8e\xd2\x7c\xe1\xb7\xb0\x0f\x5a\xcb\x30\x3a\x1f\xea\xa2\x5c\x99\xb5\x31\x4d\xe3\xf8\xd6\x51\xa0\x88\x4a\x20\x91\x95\x3b\xa8\x1f\xc0\x42\x5d\x3b\xde\xf1\xa8\xed\x1f\x14\x6d\x05\xb5\x4f\x6d\xe2\x31\x76\xb4\x8b\x34\xa0\xfa\x53\x71\xcd\x91\x76\x36\x9f\x13\x61\x23\xea\x39\x24\xc0\x48\xa0\x3d\xc1\xcb\xfd\x23\xf0\xef\x4c\x19\xc6\x44\xb0\xe7\x08\x3e\x24\xc5\xa0\x1e\xcf\x1b\x6b\x4e\x18\x2f\xce\x16\xde\xde\xcb\x89\x76\xe1\xd3\xdc\xcc\xbe\x96\x62\x77\x5c\xca\x06\x0a\x9a\xaa\xeb\xf2\x85\x0b\xcd\x79\xca\xeb\x9b\x7f\xdd\x13\x8a\x50\x4c\xd5\x33\x0d\x84\x89\x16\x5a\x04\xd9\xfc\x00\xeb\x2e\xc6\xe1\x44\x01\x00\x00"
+
+func pluginOptionsGoTplBytes() ([]byte, error) {
+	return bindataRead(
+		_pluginOptionsGoTpl,
+		"plugin/options.go.tpl",
+	)
+}
+
+func pluginOptionsGoTpl() (*asset, error) {
+	bytes, err := pluginOptionsGoTplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "plugin/options.go.tpl", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _pluginPlatformGoTpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9d\x57\x4b\x6f\xdb\x38\x10\x3e\x5b\xbf\x62\x4a\x20\x85\x64\x28\xf4\x3d\x40\x0f\x41\xd3\x2e\x02\x04\x6d\xb1\x49\xd0\x63\x43\x4b\xb4\x4c\x44\x26\x09\x92\xca\x63\x03\xff\xf7\x1d\xbe\x64\xd9\x71\xda\xcd\x9e\x2c\x51\xf3\xfc\x66\xe6\xe3\x58\xb3\xe6\x9e\x75\x1c\x5e\x5e\xe8\x8f\xf8\xb8\xdd\x16\x85\xd8\x68\x65\x1c\x94\xc5\x8c\x34\x4a\x3a\xfe\xe4\x08\x3e\xae\x36\xe1\xc7\x3a\x23\x64\x67\x49\x81\xcf\x9d\x70\xeb\x61\x49\x1b\xb5\x59\xac\x99\x5d\x8b\x46\x19\xbd\xe8\xd4\xe9\xba\xe9\x55\x47\xde\x12\x78\x64\xcf\x5a\x09\xe9\x4e\x75\x3f\x74\x42\x9e\xda\xf6\x7e\x81\x12\x5a\x49\x2e\xdd\xbb\xb4\x5a\xd5\xd8\x77\x29\x38\x6e\x36\x42\xb2\x9e\x14\x55\x51\x2c\x16\xf0\xa3\x67\x6e\xa5\xcc\xe6\xb3\x92\x2b\xd1\x81\xb0\xe0\xd6\x1c\x9a\xf0\x36\x18\xe6\x84\x92\x80\x09\x0f\x8d\x1b\x0c\x07\x94\x0c\xdf\x75\xd2\xa2\x70\xe9\x50\xc7\x1b\x6a\x79\xa3\x5a\xde\xc2\xca\xa8\x4d\x90\x21\x83\xe5\x04\x96\xbd\x6a\xee\x41\xad\xc2\x51\xcb\x75\xaf\x9e\xd1\x1e\x93\xff\x30\x10\x12\x72\x84\x14\xf1\xa2\x85\x7b\xd6\xfc\x30\xa0\xe8\x1b\x5e\x8a\x19\xfa\xb8\x50\x1b\x86\x5a\x29\xc8\x36\xbe\xb9\x35\x73\xc9\xf2\x06\xe1\xb3\xc0\x30\x50\xc3\x59\xb3\x66\xcb\x9e\xc3\x20\x5b\x6e\x68\x31\x4b\xba\xb1\x78\x70\x87\x0e\xcf\x48\xb4\x50\x2b\xed\xd3\x44\x4c\xee\x8a\xed\x1e\x28\xc9\x2c\x9a\x94\x70\x6e\x9c\x58\xb1\xc6\x1d\x84\x39\x09\x30\x82\x76\x90\x40\xb2\x98\xe1\xdd\xe8\x9e\xc7\x28\xc7\x7a\xd3\xcf\x19\x6c\x0c\xb7\x58\x0d\xb2\x81\x52\xc3\x3c\x9b\xa9\x92\x6e\x59\x41\x89\x48\x71\x83\x41\xf0\x97\x6d\x0d\xdc\x18\x65\x2a\xef\xd8\x70\x2c\x8e\x84\x8f\x9a\xc6\x10\x6a\x90\xa2\xdf\x73\x7c\xcd\x7d\x99\xa0\x61\x7d\x8f\x25\x62\x2b\x34\x73\xa4\xce\x28\x91\xaa\x58\x83\x55\x11\x58\xe1\x50\x4b\x7a\x4b\x4b\x0e\x0f\xac\x17\x2d\x73\xbc\xa5\x6f\xc7\x89\xae\xca\x84\xc4\x24\xdc\x2a\x86\x1b\x60\xaa\x41\xdd\xc3\xd9\xa7\xe4\x9c\x96\xf3\x7d\xc4\xaa\x62\x26\x56\xf0\x01\x65\x50\x38\xe7\x86\xa3\x47\xbf\x78\x0b\xab\x92\xf0\x27\xcd\x1b\x0c\x02\x0e\x14\x6b\xe8\x94\x83\x93\x1b\x52\x27\xd3\x68\x09\x41\xf0\xd6\xd2\xc8\x7a\xa8\x1d\x56\xdc\x96\x0d\x8d\xfd\x50\x03\x59\x90\xea\x2d\x4f\xa9\xc3\x36\x83\x75\x3e\x7d\x96\x5b\x4e\xb2\x0d\x47\x90\xd1\x1b\x83\xdb\xbf\xaf\x48\x72\x94\x2c\xec\xc0\xbf\x08\xed\xf3\xd5\x43\x75\xb4\xf2\x39\xfe\x63\x68\xee\x74\xb1\xf2\x13\x24\xd3\x28\xfc\x4c\x93\x13\x6a\x1a\xe7\x21\xba\xf7\x23\x88\x4a\xa1\xa0\x8f\xc8\x0a\xe1\x13\x33\xdd\x10\x9d\x63\x3d\x99\xbd\xb7\xc1\x06\x3a\x0a\x85\x66\x2d\xf6\x84\x7c\x9e\x48\x45\xe7\x20\x39\x6f\xd1\xb6\x42\x2d\x0b\x56\x74\x92\x79\x16\xa0\x63\xa6\x9a\x46\xc1\x31\x5d\xc4\xf9\x77\xf9\x26\x01\x6e\x8e\x27\x3c\x6a\xbf\xce\x78\xe2\x30\x48\xed\x01\x3c\x1d\x53\x36\x8e\xe9\x9b\x98\x22\xab\x37\xee\x09\x12\xb1\x87\x9e\xc0\xdf\xba\x98\x21\x65\x43\x20\x6e\x7a\xa5\xba\x8e\x1b\x3c\xb2\xa6\x81\xf9\x2e\x81\x6b\x35\x98\x86\xe3\x79\x76\x03\xf3\xcc\x0b\x78\x18\xc3\x48\xa3\x3e\x9f\xa6\x9d\xc9\x29\x35\x6a\x31\x1b\x04\x64\x26\xa6\xb7\x97\x75\x81\xc3\x3d\xdf\x89\x4d\x87\x5b\xb4\xe1\x2d\x0e\x4c\xb6\x78\xd9\x96\x71\x4c\xfc\x97\x0f\x9f\x7c\xcb\x4d\x7b\x18\x5f\x83\x52\x6e\x4b\x3b\xf4\xce\x1b\xf8\xb8\x73\xe1\xa5\x2f\xdb\x33\x00\x40\x07\xf8\xfc\x0d\x5b\xfa\x6c\x9c\x93\x1b\x75\xa5\x1e\xb9\x29\xfd\x30\x5c\x6b\x3c\x73\x38\x0d\x27\xf6\xf4\xc4\xe2\x70\x21\x26\xf4\x5c\xeb\x1a\x35\xab\xaa\xf6\x3e\x7c\x24\x99\x7c\xd2\x60\xf9\xa8\x08\x49\x41\x79\xff\xf4\xd6\xf4\xf0\x09\xf6\x2c\xae\x9d\xd3\xf6\x6c\xb1\x38\xb1\x34\x58\x4e\x92\xdf\xc2\x78\x1d\x18\x4c\x33\x66\x3b\x9f\xc8\x20\xe8\xb5\xe3\xfa\x2f\xa3\x06\xed\x91\x68\xf9\x0a\x19\xcd\x76\xf4\x27\x13\x0e\x0f\x50\x0e\x3f\x7b\x49\x3c\x3b\x6f\xdb\x92\xc4\xcc\x3d\xf5\xa3\x33\x4a\xd1\xd9\xd8\x29\x57\xaa\x09\xfc\xb7\xb3\x83\xba\xf4\x7c\x89\x1b\x40\x30\x85\x7d\x76\xf3\xfd\xe2\xfb\x59\xbe\xbe\xe2\x40\x25\x65\xb8\x19\xef\x35\x0f\x2b\xd8\xb5\x1a\xfa\x16\x2c\x12\xae\x97\x7b\x60\x46\x78\x5e\xc7\xc1\x93\xc1\xd2\xb4\x47\xe8\x17\xf9\x30\x12\xad\x97\x1e\x87\x1a\x2d\x99\x71\xbe\xa5\x6f\x55\x89\x94\xe7\x27\x11\xc5\x82\x1d\xcb\xcd\x43\xb8\xd7\x7c\xbb\x5e\xf0\xe5\xd0\x21\x5d\xe5\x1c\x31\x3b\xe2\x39\xea\x10\x52\xc2\xe5\x03\x9e\xf5\x5c\x96\x87\x71\x54\x19\x34\x7a\xab\x3d\xc5\x67\xc4\x90\x4e\x0e\x4b\x53\x25\xc1\x0b\xec\xc5\x00\x50\xea\xba\x28\x52\xef\xf1\x5f\x18\x56\x4c\x3a\xfc\x5a\xcf\xa0\x23\x54\xf4\x37\x24\xf0\x9e\x11\x9d\x80\x3f\x1d\xa1\x63\x33\x36\xde\x41\xff\xbf\x8b\x42\x78\xd3\x36\x9a\x24\x94\xc0\xf9\x53\x13\x45\x4c\xdc\x5e\xdf\xd0\xd7\xe8\x47\xaa\x4c\xf0\xbf\xf6\x72\xb4\x04\x13\xe8\x55\x13\xb8\x7c\xbc\xd9\xb1\x31\x1f\x25\x2c\x9f\x81\xe4\xa5\x0b\xc2\xf2\x78\xbc\x0c\x53\x6d\xbf\x79\xcc\xbd\x2c\xdd\x3b\x9e\x92\x14\x7e\x1d\x59\x2a\x48\x7e\xe3\x8f\x65\x78\xf8\x8a\xeb\x60\x5a\x60\x3e\xee\x5f\xd7\xb8\x14\xd4\x30\xca\x04\xda\xcf\xf7\x49\xbc\x03\xaa\xff\x4e\x71\x68\xc6\xdf\x0c\x8d\x11\x61\x95\xcb\xcd\x1b\xef\x45\xa6\x75\x2f\xe2\x88\xfb\x9b\xda\xcb\x5e\x4a\x3d\xb8\x92\x4c\x97\x7e\x9a\x89\x3c\xcb\x7c\x1f\xdc\x6b\xa1\x5d\x83\x91\x2a\xf9\xfd\xf2\xc4\xfc\x5d\x57\xde\x15\x89\x1f\x5e\x0a\x00\xdc\x7d\xc1\x6b\xfa\x62\x6d\xb7\x24\x9c\x41\x5e\x1f\x90\x19\x79\x54\xf2\x0b\x3b\xc1\x4f\x5b\xac\xda\x5d\x36\x88\x1b\xd4\x57\xc1\xfb\x16\x87\x60\x96\x56\x10\xe2\x29\x9a\xbc\x73\xe9\xf5\x4a\x93\xde\x08\x35\x4a\x0d\x82\xb4\xe4\xff\xdc\xfc\x3a\xb2\x8b\xf8\x38\x31\xc2\xdd\x56\x56\x95\xa8\x54\xed\x0b\x4f\x57\xd6\x3f\x0a\x8f\xb7\xfe\x1b\x96\xab\xe2\x5f\xb1\xfc\xf1\x25\x85\x0d\x00\x00"
+
+func pluginPlatformGoTplBytes() ([]byte, error) {
+	return bindataRead(
+		_pluginPlatformGoTpl,
+		"plugin/platform.go.tpl",
+	)
+}
+
+func pluginPlatformGoTpl() (*asset, error) {
+	bytes, err := pluginPlatformGoTplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "plugin/platform.go.tpl", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _pluginPlatform_testGoTpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xbd\x54\x51\x6f\xda\x30\x10\x7e\x4e\x7e\xc5\x11\xa9\x55\x32\x85\xf0\xce\xc4\x43\xb7\x6e\x5a\xa5\xaa\x42\x2b\xdd\x6b\x6b\x1c\x27\xf1\x9a\xd8\x9e\xed\x50\x10\xe2\xbf\xef\x1c\x87\x12\x5a\xba\x6a\x9a\xb4\x17\x70\xec\xbb\xef\xbe\xef\x3e\x9f\x15\xa1\x8f\xa4\x64\xb0\xdd\x66\x73\xbf\xdc\xed\xc2\x90\x37\x4a\x6a\x0b\x71\x18\x44\x54\x0a\xcb\xd6\x36\xc2\xa5\xb1\x9a\x8b\xd2\xb8\xa5\x65\xc6\xe2\x3a\x0a\x71\x5d\x72\x5b\xb5\xcb\x8c\xca\x66\x52\x11\x53\x71\x2a\xb5\x9a\x94\x72\x5c\xd1\x5a\x96\xd1\x5b\x01\x4f\x64\xa3\x24\x17\x76\xac\xea\xb6\xe4\x62\x6c\xf2\xc7\x09\x46\x28\x29\x98\xb0\x7f\x95\x65\x99\x6e\xb8\x20\x75\x14\x26\x61\x58\xb4\x82\xc2\x02\xd9\xcd\x6b\x62\x0b\xa9\x9b\xd8\xc2\x87\x9e\x6d\xb6\x48\x60\x1b\x06\xd4\xae\x61\x3a\x83\x5e\x58\xf6\x09\x65\x97\x5a\xb6\x22\x8f\x93\x30\x68\xb9\x3b\xdb\x43\x66\x37\x52\x5c\x61\x98\x26\xd4\xf2\x15\xbb\xbb\x8a\x31\x19\xab\x04\x2b\xa2\x41\xc1\xbe\x46\x18\xf0\x02\x72\x4e\x4a\xe3\x81\x7b\x15\xd9\x67\x29\x0a\x5e\xb6\x9a\xc5\xe7\x2a\x05\xc7\xc2\xef\xc4\x36\x85\x87\x5c\x36\x84\x0b\x98\x41\xc4\xd6\xa4\x51\x35\x73\x52\xa3\x87\x24\x05\xc1\xeb\xe4\xa3\xc7\xcb\xbe\x11\xf3\x45\x6b\xa9\x4d\xdc\x71\x0f\x6c\xf6\x95\x58\x52\xc7\xfe\xb4\x3b\x8a\x13\xe4\x8d\xa6\x05\x9a\x99\xb6\x46\x68\xa6\xb5\xe3\xa1\xb2\x4b\xa6\x6a\xb9\x41\x17\x9d\xe6\x14\xff\x3a\x4b\xb2\xeb\x38\x71\x1f\xe7\x07\xa2\xb7\xb2\xd5\x94\x6d\x2f\x94\x9a\x42\xe7\x6d\x94\xc2\x9c\xd8\x6a\x0a\x36\x5b\xb0\x46\x5d\x72\xac\xb2\xeb\x92\x2e\xb4\xe5\x05\xb6\x63\x7b\x2d\x29\xb1\x5c\x0a\xcc\xc8\x39\x66\xec\x5e\x60\xfa\xe2\x0d\x2e\xbd\xe8\x6d\x17\xd0\x72\xfc\x4d\xba\x86\x39\x9a\xa3\x99\x53\x7b\xa4\x0c\xb7\x7b\x3d\x18\x33\xea\xef\x9c\xeb\xc3\x5c\xb3\x82\xaf\x63\xaf\x32\xbb\x21\x0d\x4b\x3d\xd9\x71\x74\xd4\x9b\x22\x8e\x96\x24\x07\x81\x01\x53\x38\xfb\x85\x52\x06\x29\x1d\xf4\x4b\xe4\xdb\xb6\x18\x20\xdf\xe9\x1a\x81\xb3\xa1\x2b\xa7\xf0\x5b\x5d\x1f\xc3\x63\xde\x81\xf8\xc0\x03\x2c\x84\x26\x38\x07\xe0\xd0\x7f\xd8\x9b\xd5\x72\xf4\xfa\x9d\x56\x20\xe6\x64\x72\x74\xab\xef\x09\xa5\x4c\x59\x22\x28\xeb\xee\x95\x01\x5b\x31\x50\xfd\x29\x7e\xe0\x85\x2e\xab\x7e\xd3\x4d\x0a\x28\x2d\x29\x33\x26\x7b\x3d\x21\x03\xac\xd7\xc3\xa2\xc9\x93\x1f\x08\x17\xef\x80\xdc\xdd\x3d\x98\xbc\xc7\x58\x6c\x14\xf3\xae\xde\xa7\x20\x1f\x5d\x0a\x66\x66\xf1\xeb\x48\x54\x3b\xc2\x80\xe3\x7e\xb2\xb5\x62\xd4\xb2\x1c\xc8\x09\xec\x14\x4a\x69\xe1\x6c\xe1\x3a\x4d\x9e\xfa\x16\x63\x3f\x7e\x90\x9a\xe7\x6e\x8c\xbb\x19\xeb\xee\x22\x70\x03\x5e\x0d\xcb\xdf\x1f\x4a\x44\xfb\x0f\x53\x89\x4c\xaf\xc4\xea\x34\x57\xcd\x7e\x7a\xdd\xcb\x0d\x78\x12\xb7\xcc\xfe\x2b\xf1\xca\x5a\x65\xa6\x93\xc9\x69\x01\xa3\x3f\x2b\x18\x5a\x71\xf7\xfd\x1a\xac\x84\x25\x7b\xe6\x19\x1d\x34\x5d\x4a\xda\xba\xe1\x7e\xd6\x62\x98\x5e\xb9\xae\xe7\x92\x3e\x3f\x41\x83\xd7\x60\x18\x1e\x7b\x1f\xdf\x7f\x03\xba\x4e\x48\x8a\x43\x64\x09\xaf\x91\xab\x1b\x27\xaa\xb9\xea\x8a\xce\x50\x6c\xf4\x26\xfb\xfc\x10\x19\xf5\x53\xf4\x1b\x8b\xde\x83\x23\xed\x06\x00\x00"
+
+func pluginPlatform_testGoTplBytes() ([]byte, error) {
+	return bindataRead(
+		_pluginPlatform_testGoTpl,
+		"plugin/platform_test.go.tpl",
+	)
+}
+
+func pluginPlatform_testGoTpl() (*asset, error) {
+	bytes, err := pluginPlatform_testGoTplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "plugin/platform_test.go.tpl", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _pluginPluginProtoTpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x5d\x92\x41\x6b\xe4\x30\x0c\x85\xef\xf9\x15\x62\xce\x25\x43\xdb\x63\x99\x43\xd9\xed\xb1\x50\x4a\xa1\xc7\x45\x49\x94\xc4\xd4\xb1\x8c\x65\x4f\x37\x84\xf9\xef\x2b\x3b\x93\x66\xd8\x5b\x2c\xbf\xf7\xf4\xc9\x8a\xcc\x2e\xe2\x5f\x38\xc1\xc1\x07\x8e\xfc\x78\x78\xaa\x2a\x8f\xed\x17\x0e\x04\xcb\x52\xbf\xad\x9f\x97\x8b\x96\xd9\x47\xc3\x0e\x06\xfe\xb3\x09\xd4\xa5\x9a\x57\xee\x92\x55\xc9\xf1\x56\x9f\x73\x8e\x47\xf8\x18\x09\x26\x12\xd1\x9a\x40\x43\x96\xbf\x01\x03\x41\xd4\xf2\x19\x6d\xd2\x62\x1c\x31\x96\xb3\xb7\x69\x30\x0e\x3c\x8a\xe4\x32\x03\xba\x0e\xfa\xc0\x53\xce\xf9\xc4\xd9\xb3\x71\xb1\x86\x97\x33\x85\x79\x35\x03\x42\xcb\x93\x67\x47\x2e\x42\xa0\x98\x82\x13\x98\x92\x44\xed\xa4\x77\x65\xa0\x26\xf5\x1b\x00\x08\xe7\xa8\xd2\xd0\x44\x68\xd1\x65\x9d\x64\x33\x6b\x28\x0c\xef\x6f\xbf\x4a\x53\x89\x1c\xa8\x83\x66\x2e\x5c\x42\x41\x6f\x6b\x78\xee\xa3\x8a\xda\x11\x9d\x62\x0e\x6b\x92\x11\xe8\x8d\xa5\x3b\x08\xc9\xc1\x61\xc2\x2f\x5a\xbb\xca\x21\x0f\x10\x68\x20\x47\x01\xe3\x36\x5c\xed\x9b\x7a\xe0\xba\xbc\xcc\x73\x88\xa6\xc7\x56\x51\xa4\xb4\x09\x24\xc9\x2a\x49\xaf\xe4\x4d\x32\xb6\xab\xab\x8d\xfb\x47\xba\x54\x00\x6a\xb5\xdc\x62\x59\xc5\xd5\xea\x31\x8e\xd9\x98\xbf\x8b\x15\x38\x45\x9f\x62\xad\x72\x89\x41\x69\x77\xcb\x09\xee\x9f\xaa\x4b\x21\xf8\x4d\xde\xf2\x3c\xe5\xf9\x35\x08\xa1\x2b\x67\x1d\x1c\xaf\xfd\x76\x82\x1b\xe9\xb2\x87\x9a\x6e\x8d\xfb\x29\x38\x9c\xf2\x4f\xf1\xa0\xbb\x2f\xa0\x29\xd8\x1c\xfd\x3d\xd2\x75\xe7\xdd\x9e\x73\x7d\xfe\x40\xd8\x8e\xd4\xdd\xa0\x66\xd3\x09\x1e\x37\xca\x77\xb2\x84\x42\xb7\x88\xc5\xbf\xae\x51\xf7\x8d\x9d\x2e\xfb\x8c\xc6\x62\x63\x29\x3f\x7b\xd2\x8d\xc9\xce\xbe\x05\x2c\xff\xb7\x28\x0f\xf1\x0f\x78\x2a\x31\x9d\x00\x03\x00\x00"
+
+func pluginPluginProtoTplBytes() ([]byte, error) {
+	return bindataRead(
+		_pluginPluginProtoTpl,
+		"plugin/plugin.proto.tpl",
+	)
+}
+
+func pluginPluginProtoTpl() (*asset, error) {
+	bytes, err := pluginPluginProtoTplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "plugin/plugin.proto.tpl", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _pluginPlugin_testGoTpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x95\x55\x51\x6f\xdb\x36\x10\x7e\x8e\x7e\xc5\x4d\x40\x0b\x69\x73\xe8\x6e\x8f\x06\xf2\xd0\x3a\x5e\x1b\x2c\x49\x8d\xc4\x5d\x30\x14\x45\x40\xcb\x67\x99\x0b\x4d\xaa\x24\x65\xc7\x30\xfc\xdf\x77\x47\x2a\x8e\x5d\xa4\xdb\xfa\x62\x53\xbc\xbb\x8f\xdf\x7d\xf7\x89\x6a\x64\xf5\x20\x6b\x84\xed\x56\x8c\xd3\x72\xb7\xcb\x32\xb5\x6c\xac\x0b\x50\x64\x27\xb9\xf5\x79\xfc\xed\xe3\x23\x56\xbc\xf4\xc1\x29\x53\xc7\xdd\x80\x3e\xd0\x3a\xcf\x68\x5d\xab\xb0\x68\xa7\xa2\xb2\xcb\xfe\x42\xfa\x85\xaa\xac\x6b\xfa\xb5\x3d\x5d\x54\xda\xd6\xf9\xbf\x24\x34\xba\xad\x95\xf9\x6e\x06\xd5\xf7\x57\xbf\xfd\x47\x98\xff\xfc\xc6\x04\xf9\x48\x89\x7e\xf6\x00\x2f\x27\xaf\xe5\xa6\xb1\xca\x84\xee\xcc\x53\xca\xfc\x2e\xf0\x0b\xb9\x7d\xca\x68\xac\x41\x13\x7e\xa8\x8a\x1e\xd1\x19\xa9\x4f\xfd\x42\x3a\x9c\xf5\x53\xa8\xd2\x2a\x02\x95\x59\xd6\xef\x03\x2b\x79\x8b\x6e\x85\x23\xb3\x02\xe5\xc1\x63\x80\xf5\x02\x4d\x0c\x8c\x63\x01\x68\xd9\x9a\x6a\x81\x1e\xc2\x02\xe3\x3e\x4c\x95\x91\x6e\x03\x32\x6e\x31\x4c\x82\x16\x59\x65\x0d\x85\x8f\x40\xcf\x20\xbf\x7b\xfb\xd7\xf8\xe3\xc5\xf5\xe4\x7e\x7c\xf9\xe9\xfd\xc5\xf5\xfd\x64\x74\x3b\xb9\xbf\x1d\xdd\xfc\x39\xa2\x09\xce\x09\x1c\x26\x54\x71\x25\x95\x29\x96\xf0\x73\x37\x5c\x71\x55\xc2\x36\x3b\x21\xf0\x3b\xe6\xd3\x91\x98\xc1\x74\x73\xc0\xad\x47\x84\xe9\x9c\xc8\x2c\x71\x80\xbf\x5b\x62\xa0\xd5\xc3\xe1\x66\x84\xe9\x48\xcf\x2c\x75\xe2\x24\x05\x1d\x65\x48\x03\xae\x35\xfb\xce\xbc\xc8\x4e\xd4\x1c\xac\x17\xef\x31\xa0\x59\x15\x87\xad\x94\xf0\x13\x35\x93\x33\x2b\x9e\xb5\x88\x84\x3f\x36\x41\x51\xd3\x42\x88\x92\xb6\xa9\x70\xf4\xa8\x42\xf1\x86\x1e\xc8\xce\xfb\xe7\xa5\xb8\x69\x4d\x51\x96\xd9\x6e\xaf\xfa\x4b\xe2\x76\x2d\xf0\xd2\xcb\x25\x02\x4d\x95\x39\x06\xb8\xeb\xc6\x9b\xd8\x4b\x33\x63\x14\x87\xa1\x75\x26\x55\xa6\xa9\xc2\xdc\xba\xf4\xf8\x64\x18\xb0\xf3\xb8\x51\xab\x15\x0f\x75\xd3\xa0\x80\xa1\xd4\x9a\xaa\xec\xd3\xf0\xba\x5a\xf2\x08\xa9\xc9\x25\x2b\x92\xa6\xbe\x19\x0f\xbb\x9c\x27\x5a\x8d\xb3\x15\x7a\x4f\x9a\xf3\x36\x99\x25\x2a\x76\x6c\x01\xf6\xc4\x9e\xac\x47\x22\xab\x82\x48\x33\x7e\x6e\xba\x08\xcf\x53\x9e\xf4\x98\xd5\x33\x61\x31\x21\x8e\x25\x44\xef\xce\x65\x85\xdb\x1d\xeb\x1d\xc4\x07\xd4\x0d\xba\x82\x5c\x7b\x52\x2d\x67\x30\x38\x03\xbe\x17\xc4\xd0\x2e\x97\x24\x47\x41\x42\xbf\x75\xb5\xff\xfc\xe6\x4b\x19\x13\x44\xb2\x9e\x6c\x1a\x4c\x51\x7a\x56\xce\xd2\x0c\x7a\x47\xf6\xfc\x25\x3f\xfb\x35\x8f\xa0\xd6\xcc\x55\xcd\xb8\x87\xaf\x89\x18\xc6\xbf\x61\x0c\x16\xf1\x4a\x11\xd7\xb8\x2e\x5e\xa7\xe5\xa5\xad\x6b\x74\x9d\x05\xd8\x16\xd7\x34\xb5\x01\x40\xde\xdd\x2d\x3d\xda\xba\xc4\x15\xea\x01\xa4\x82\x73\x9c\xb6\x35\xed\xee\xca\xf2\xe9\x48\x31\xa4\x76\xce\x80\x38\x33\x8b\x34\x8a\x3d\x0b\x3e\x2c\x51\x28\x52\x76\xc9\x5a\x0c\x35\x4a\xd3\x36\x45\xc7\xf1\x0f\xa5\x35\xb7\xe0\x9a\x2a\xe5\xf6\x00\x9d\x63\x90\xa3\x26\x48\x3b\xf6\x36\x87\xc8\xc7\x46\xe9\x68\xe4\x20\x7e\x97\x41\xea\x79\x91\x53\x80\xdc\x93\xfc\x48\x93\xe9\x18\x0c\xe0\x95\xcf\x23\x60\x67\x69\x27\xd7\x7b\xfc\xfd\x89\xe2\x5c\x79\x52\xda\x63\xd1\xdd\xd2\x62\x62\x2f\xed\x9a\x06\x46\xc3\x15\xb7\x71\x8f\xec\xff\xbf\x18\x38\xfc\xda\x26\x73\xd0\xd1\xdd\xf1\x84\x72\xc4\x21\x3a\x9f\xde\xe2\xf5\xc1\x0b\x95\x86\x04\x8d\x74\x9e\x6c\xe7\x5d\xd5\xdd\x4f\x30\xb5\xb3\x0d\xbf\x07\x12\xf2\xd6\x63\x0e\x53\x6d\xab\x87\x03\x53\x76\xd3\x3d\x36\x25\xd7\xa7\x5e\x4a\x9e\x9d\x78\xc7\x20\xdf\x3a\x71\xde\x83\x99\x92\xb5\x67\x2d\xf6\x5f\x02\xfa\x9e\x11\x83\x0e\xf4\xf3\x97\xe9\x26\x90\x2a\xae\x22\xe3\xe5\x4f\xb7\xb4\xa0\x64\xea\x8a\x71\x2f\x8c\x0a\x4a\xea\xb1\xf5\x49\x9c\x88\x27\x3e\x48\x3f\x62\x31\x7c\x51\x1e\x6a\x54\xa4\x68\x0c\xf1\x6d\x72\x28\xc6\x3c\x52\x64\x3d\xf6\x37\xea\x0d\x92\x51\x3c\x5e\xf1\x5b\xe0\x8e\xfa\x8b\xa8\x0e\x7d\xab\xa3\xd9\x8e\x13\x5f\x9f\x63\xa3\xed\x66\x49\x73\xdd\x7e\x72\xe4\xdd\x7c\x11\x42\xe3\x07\x7d\xfa\x14\xcb\x65\xa3\x91\xbf\x3d\xf9\x2e\xf1\x4d\x20\x82\xf2\xe2\xed\xf8\x52\xe6\x37\x53\x9e\xca\x19\xb4\x0c\xfb\xea\x2b\x69\xf0\x5c\x1f\xdb\xd9\x65\xff\x00\x6a\x43\x76\x98\x19\x08\x00\x00"
+
+func pluginPlugin_testGoTplBytes() ([]byte, error) {
+	return bindataRead(
+		_pluginPlugin_testGoTpl,
+		"plugin/plugin_test.go.tpl",
+	)
+}
+
+func pluginPlugin_testGoTpl() (*asset, error) {
+	bytes, err := pluginPlugin_testGoTplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "plugin/plugin_test.go.tpl", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _pluginReleasemanagerGoTpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x95\x56\x4d\x6f\xdb\x38\x10\x3d\x5b\xbf\x62\x4a\x20\x85\x14\x38\xf4\x3d\x8b\x1c\x8a\xa6\x59\x04\xe8\x36\x8b\x7c\xa0\xc7\x86\x96\x28\x99\x88\x4c\x6a\x49\xaa\x89\xd7\xf0\x7f\xef\x23\x25\x59\x72\xeb\xf4\xc3\x07\x5b\xa6\xe6\xcd\x0c\xdf\xcc\x3c\xb2\x11\xf9\x93\xa8\x24\x6d\xb7\xfc\xdf\xee\x71\xb7\x4b\x12\xb5\x6e\x8c\xf5\x94\x26\x33\x96\x1b\xed\xe5\x8b\x67\x78\x2c\xd7\xf1\x47\x4b\xbf\x68\x6d\xcd\x12\x3c\x57\xca\xaf\xda\x25\xcf\xcd\x7a\xb1\x12\x6e\xa5\x72\x63\x9b\x45\x65\xce\x56\x79\x6d\x2a\xf6\x9a\xc1\xb3\xd8\x34\x46\x69\x7f\xd6\xd4\x6d\xa5\xf4\x99\x2b\x9e\x16\xb0\x68\x8c\x96\xda\xff\x11\xaa\x30\xb9\xfb\x23\x80\x97\x76\xad\xb4\x40\xf6\x59\x92\x2c\x16\x74\x2b\x6b\x29\x9c\xfc\x47\x68\x6c\xdd\xbe\x37\xba\x54\x15\x29\x47\x7e\x25\x29\x8f\xff\x5a\x2b\xbc\x32\x9a\x9c\xb7\x6d\xee\x5b\x2b\xa9\x34\x36\xbe\xb7\x1d\x36\xb8\x59\x77\x78\x4e\xd7\x3e\xa0\x0b\x99\x9b\x42\x16\x54\x5a\xb3\x8e\xa6\xac\x75\x92\xd1\xb2\x36\xf9\x13\x99\x72\x8a\x86\x5f\xa1\xff\x17\xa4\x74\xf0\x33\xa4\xcc\x41\x20\x4f\xfc\xa6\x91\xc7\x33\xec\x92\xa1\x6d\x32\x03\xe8\xe1\xf6\xe3\x90\xb2\x28\x0a\x2b\x5d\x78\x16\x7e\x88\xe0\x48\xd8\x10\x4e\xe4\x2b\xb1\xac\x61\xe3\x79\x32\x0b\x18\x38\x51\xba\xa2\x47\xc4\x3a\x67\xa8\xe8\xdc\x34\x61\xa7\x20\xe7\x31\xd9\x1d\x61\x67\xe2\x90\x2e\x65\x53\x9b\xcd\x1a\xf5\x3a\x9a\xe6\x24\xc1\x8e\xc5\xa3\xdb\xe8\xa3\x0c\xac\xaf\x9b\x5a\x06\x8f\x8e\xf6\xcd\xc0\xdf\x0f\x35\x40\xe6\x49\xd9\xea\x9c\x52\x4b\xa7\x87\xce\xb2\xde\x43\x9a\x51\x0a\xee\xa4\x2d\x45\x2e\xb7\xbb\x39\x49\x6b\x0d\xde\x22\x09\x2b\x51\x39\x4d\x6f\x2d\xef\xd2\x99\x93\x56\xf5\x41\xf8\x3b\x19\x2b\x97\x8b\xba\x46\xe1\x44\x09\x37\x47\x9a\x60\xac\xed\x9c\x9c\xe9\x68\x56\x1e\xa8\x58\xbd\xa5\xa4\xaf\xa2\x56\x85\xf0\xb2\xe0\xbf\xca\x16\x01\xd3\x9e\x9b\x49\xd2\x59\x97\x74\x24\x6e\x4e\xe6\x89\xce\x2f\xfa\x14\x78\x7a\x7a\x8c\xc3\x2c\x99\xa9\x92\xde\xc0\x12\x90\x61\x9f\x98\x54\xfe\x21\xf8\x29\x53\x26\x5f\x1a\x99\x23\x21\x3a\x0a\x9f\x53\x65\x3c\x9d\xdc\xb3\x79\x1f\x06\xfe\x40\x4b\xf0\x99\xf3\xd0\x24\x6f\x2e\x88\xb1\xe8\x1b\x4b\x5f\x22\xa7\x21\x27\xb4\x0b\x04\xc3\x3a\x79\x2b\xff\x6b\xa5\xf3\x0f\xb7\xd7\x69\x04\x64\x7f\x45\x13\xc0\xc0\x70\xc4\x1d\x4b\x0a\xf0\xc0\xa5\xd2\x91\xaf\x73\x3a\x71\x2c\xba\x46\x74\x84\x8f\x19\xf4\xa8\xb1\x4e\x7d\xfa\x57\x81\xd6\xa3\xbd\x72\xb8\xbf\xd7\xf9\x9f\x38\x42\xcb\x4c\xc8\xef\xe7\xe9\x73\x3f\x84\xb1\x19\x5c\x3f\xac\x21\x99\x30\xd1\x00\xc5\x4e\x78\x86\xe2\x74\x23\x67\xab\xb6\xcb\x04\x8d\x20\xdc\x93\x8b\x3e\xa0\x11\xb1\x43\x30\x90\x24\xf4\x66\x62\xd5\x47\x27\x2d\x65\x01\xe7\x06\x30\x47\x4e\x55\x5a\x04\x71\xe1\xfb\x8d\xdb\x61\x3f\x87\xdb\x9f\x0e\x61\x31\x19\xc2\x5f\xed\x16\x5a\x9e\xfb\x17\xea\xe5\x3c\xcc\x55\xf8\x9d\x27\x33\x08\x35\x45\xb9\xe6\x1f\x4d\x05\x04\x96\x9c\xcd\xe9\x74\xe4\xf5\xce\xb4\x36\x97\x58\x6f\x15\x0d\xfa\xc9\x1f\xae\xb1\xe0\xb1\x2d\xcc\xcd\xe9\xa8\x06\xf3\x04\x43\x38\xe4\x30\x9d\x40\x57\xc5\xb6\x51\xfc\xce\xcb\xe6\x6f\x6b\xda\x26\x45\xb1\x0b\x59\x06\xb1\xa8\xf8\x67\xa1\x3c\x16\x60\x87\xd7\xc1\x12\x6b\xef\x8a\x22\x65\x9d\xab\xa0\x53\x27\x8e\x73\x8e\x36\xe9\x82\xf2\x4f\x62\x2d\x47\x0f\x40\xf1\x77\x4b\x1c\x5a\xd1\x09\xe8\xba\xbf\xb9\xbc\x39\xdf\x8b\x6c\x28\xd4\x94\x2d\x90\xec\xda\xda\x87\x40\x6f\xfb\x64\xb7\x0f\xb6\x06\xa0\x17\x88\xd0\xca\xbb\x38\x06\x9d\x25\xc7\x5b\xba\xd8\xcf\xc2\x74\x71\x48\x08\x7f\x42\xe7\x06\x42\xf9\xa5\x5c\xb6\x55\xca\xec\x90\x3c\xd2\x66\x63\xfc\x71\x13\xd7\xd0\x91\x30\x0c\x58\x19\x5d\x0e\x34\xf0\x87\x26\x48\xc9\xc0\x01\xba\x2f\x8e\xc9\xe1\xfe\xa3\xe1\x25\x0a\x15\x37\x3e\xf4\x4e\xf4\x35\x15\xb9\x4b\x93\xc7\xfe\xdb\xcb\x98\x5b\x99\x67\x4d\xcb\x0d\xb1\xe1\xcc\xa1\x78\x98\xfe\xa4\x93\x0e\x7c\x04\xb1\x3d\x0d\x08\x7e\xb0\x3c\x2d\x39\xde\xee\xf5\x22\x5a\x7e\x92\xcf\x69\x7c\xb8\xc2\xb9\xd8\x6b\xf6\xdb\x63\xaa\x04\x1d\x9c\xd3\xde\x32\x0e\xea\x7e\x1c\xba\xb1\xcd\x3a\xdd\xfb\x4e\x6a\x46\xcd\x88\x81\x3b\x21\x81\x1f\xd4\xc3\xe5\x56\xc5\xe3\x6d\x4f\xe7\xe1\x04\x0d\xc7\xb2\x68\x9a\x5a\xe5\x71\x2f\x2c\xeb\xc0\xd7\xba\x69\x7d\xca\xa6\x57\x24\x3e\x76\xfc\x60\x75\xd3\xfa\x1f\xcd\xfa\x50\x2c\xeb\xf3\xf8\xf0\x22\x82\x72\xa5\x8f\xc9\xd0\x99\xdb\x84\x08\x97\x03\x0a\xb8\x50\xd2\xdd\x8e\xc5\x35\x0a\x12\x8b\xe6\x62\x2b\xef\x1b\x77\xbe\x58\xc8\x0e\x1a\xee\x38\x0c\xef\x77\x28\xec\xe3\xe0\x16\x67\xc9\x95\x92\x75\x81\x21\x9f\x75\xed\x14\x1e\x7e\xff\x42\x10\xed\x23\xdf\x77\xed\x7a\x2d\xec\x26\x38\x9a\xb1\xfb\x55\x3c\xef\x4a\x81\x66\x8a\x52\x15\x3c\x86\x43\xa1\xe7\x6a\x32\x51\xd1\x43\x86\xaf\x49\x17\xc6\xfa\x8f\x2d\x18\xaf\x29\x3f\xd1\xed\x1f\x1a\x2f\x0b\x10\xf4\x59\x7f\x4f\xd9\xd2\x5e\x19\xc3\xdc\xc1\xe9\x57\x61\xc3\x1d\xf5\xcb\xab\x67\x00\xf8\xfb\xfe\xd4\xcc\x52\x64\x94\x1d\x82\xa6\x97\x0c\x10\xff\x7b\xa0\x41\x8f\xfb\xcf\x04\xd4\x5b\x67\xc9\x37\x67\xf0\x54\x01\x5d\x0b\x00\x00"
+
+func pluginReleasemanagerGoTplBytes() ([]byte, error) {
+	return bindataRead(
+		_pluginReleasemanagerGoTpl,
+		"plugin/releasemanager.go.tpl",
+	)
+}
+
+func pluginReleasemanagerGoTpl() (*asset, error) {
+	bytes, err := pluginReleasemanagerGoTplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "plugin/releasemanager.go.tpl", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _pluginReleasemanager_testGoTpl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa5\x54\x4d\x6f\xdb\x30\x0c\x3d\xc7\xbf\x82\x35\xd0\xc2\x1e\x12\x05\xbb\x66\xe8\x61\x5b\x36\x2c\x40\x57\x14\x6d\xba\x1d\x5b\x45\x66\x6c\xad\x8e\xa4\x49\x72\x92\x22\xe8\x7f\x1f\x65\x79\xf9\x40\x97\x16\x43\x4f\x91\x68\xea\xf1\xf1\x3d\x32\x86\x8b\x07\x5e\x22\x6c\x36\xec\x2a\x1e\x9f\x9e\x92\x44\x2e\x8c\xb6\x1e\xb2\xa4\x97\x0a\xad\x3c\xae\x7d\x4a\x47\x8f\xce\x4b\x55\xa6\x09\x9d\x4b\xe9\xab\x66\xc6\x84\x5e\x0c\x2b\xee\x2a\x29\xb4\x35\xc3\x52\x0f\x2a\x51\xeb\x32\x3d\x96\xb0\xe2\x8f\x46\x4b\xe5\x07\xa6\x6e\x4a\xa9\x06\xae\x78\x18\x52\x86\xd1\x0a\x95\xff\xaf\x57\x1e\xed\x42\x2a\x5e\xa7\x49\x9e\x24\xf3\x46\x09\x98\x12\xbb\x6b\xac\x91\x3b\xfc\xce\x15\x35\x62\x33\x0f\xef\x3a\xce\x6c\x9a\xc3\x26\xe9\x09\xbf\x86\xd1\x39\x74\x3d\xb1\x4f\xd4\x71\x69\x75\xa3\x8a\x8c\x50\x7a\x4b\x6e\xc1\xc2\x21\x46\xd2\x93\x73\x28\x24\x2f\x5d\x7c\xd8\x71\x65\x9f\xb5\x9a\xcb\xb2\xb1\x98\x9d\xd9\x3e\x84\x2a\x31\x92\xf9\x3e\xdc\xdf\xe7\x7d\x50\xb2\xce\x3f\xc4\x97\xec\x1b\x77\x5f\xac\xd5\xd6\x65\x2d\x8b\x9e\x67\x5f\xb9\xe7\x75\x16\xbf\xb6\x9f\xb2\x3c\x4f\x7a\xa4\x7c\xcf\xa2\x6b\x6a\x02\x41\x6b\x43\x45\xcb\x3a\x3e\xe4\x45\xa0\xdf\xa7\x9f\x56\x63\x76\x91\xe5\xe1\x72\xb6\xe3\x74\xa3\x1b\x2b\x70\xf3\xd1\x98\x11\xb4\x66\xa5\x7d\xb8\xe2\xbe\x1a\x81\x67\x53\x5c\x98\xb1\xa4\x32\x4f\xe1\xd1\x5f\xf5\xd8\xa5\x56\x13\xd2\xc2\x72\xe1\xe5\x12\x6f\x27\x19\x95\x88\xb0\x63\x34\xb5\x7e\x5c\x10\xee\x66\x52\x10\xde\x7b\x02\xbb\xe4\x0b\xec\xa0\x07\xe1\x7e\x6b\x6b\xba\x56\xde\x1b\x37\x1a\x0e\x63\x98\xe1\x9a\x2f\x4c\x8d\xc1\xc3\x34\x14\xcb\x5b\x09\x43\x3b\x27\xe7\x41\x95\x03\x05\x28\xdc\xf5\x3d\x1c\xc2\x4f\x32\x5f\x37\x1e\x38\xdc\x5e\x5f\x04\x93\xa2\xc2\x05\xe9\x5b\x21\xd8\xa8\x03\x34\x0e\x5d\x1b\x28\xb6\x0c\x43\x7e\x5b\x25\x8a\xc7\xe8\x4a\x4a\x53\xb9\x97\xb8\xed\xf3\x98\x67\xe9\x8c\x17\xd0\x84\x7e\x4e\x7f\x53\x67\xfb\x40\x2d\x41\xa2\x48\x0c\x9f\xcf\xd8\x1d\x17\x02\x8d\xe7\x4a\x60\x3b\x05\xee\x80\xeb\x22\x26\x51\x8c\xa6\xac\xac\xc2\xb7\x00\x13\xe7\x18\x8c\xd5\x02\x9d\x63\xc7\xe6\x77\x0f\xfb\xf9\x28\x5b\xbe\x0a\xf3\x11\x82\x57\x2d\x5c\x98\xbc\xdd\x2c\x1c\x22\x4d\x1f\x0d\x46\x1f\xee\xfa\xa0\x1f\xda\xc1\xe2\x2b\x96\x1d\xcb\xa7\xc9\x3d\xa1\xb4\x43\x85\x70\x6d\x50\x78\x2c\xc8\x9f\x63\xef\xfa\x50\x6a\x0f\xa7\xd3\xa0\x20\x5f\xed\x9c\xfd\xc1\x6b\x59\x6c\x1d\xe5\x5e\x6a\x05\xd2\x41\xec\x0f\x8b\xd7\x97\x8c\xd0\x9e\x6d\x19\xb9\x05\x7b\x16\xef\x7b\xfb\x96\x05\x24\xba\x13\xb5\xfc\x37\x61\x8b\xbf\xa2\x04\xb3\x47\x88\x4c\x6e\xd0\xbf\x89\x7d\xc7\x7a\x8f\xf1\xc9\xcb\x94\xf7\x6c\x20\x4a\x1d\xd1\xb0\x2f\x5e\xc3\x0c\xb7\x0c\xd3\x5d\x37\x63\x2d\x9a\xb0\x25\xdb\x2e\x1c\xda\x65\x10\xbd\xd0\x62\xfb\x3f\xb3\xa3\x7d\x90\x9e\x45\x1b\x5f\x5f\xe0\x56\x03\x2d\xd8\x18\x3d\x97\x35\x91\xa6\x93\x13\x56\x9a\xb6\xe8\x39\x75\x9a\x1e\x6b\x83\x16\x79\x9b\x99\x76\xcb\xf6\x07\xde\x5b\x63\x77\x97\x06\x00\x00"
+
+func pluginReleasemanager_testGoTplBytes() ([]byte, error) {
+	return bindataRead(
+		_pluginReleasemanager_testGoTpl,
+		"plugin/releasemanager_test.go.tpl",
+	)
+}
+
+func pluginReleasemanager_testGoTpl() (*asset, error) {
+	bytes, err := pluginReleasemanager_testGoTplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "plugin/releasemanager_test.go.tpl", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -141,6 +456,21 @@ func AssetNames() []string {
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
 	"init.tpl.hcl": initTplHcl,
+	"plugin/Makefile.tpl": pluginMakefileTpl,
+	"plugin/README.md.tpl": pluginReadmeMdTpl,
+	"plugin/builder.go.tpl": pluginBuilderGoTpl,
+	"plugin/builder_test.go.tpl": pluginBuilder_testGoTpl,
+	"plugin/gitignore.tpl": pluginGitignoreTpl,
+	"plugin/go.mod.tpl": pluginGoModTpl,
+	"plugin/main.go.tpl": pluginMainGoTpl,
+	"plugin/mapper.go.tpl": pluginMapperGoTpl,
+	"plugin/options.go.tpl": pluginOptionsGoTpl,
+	"plugin/platform.go.tpl": pluginPlatformGoTpl,
+	"plugin/platform_test.go.tpl": pluginPlatform_testGoTpl,
+	"plugin/plugin.proto.tpl": pluginPluginProtoTpl,
+	"plugin/plugin_test.go.tpl": pluginPlugin_testGoTpl,
+	"plugin/releasemanager.go.tpl": pluginReleasemanagerGoTpl,
+	"plugin/releasemanager_test.go.tpl": pluginReleasemanager_testGoTpl,
 }
 
 // AssetDir returns the file names below a certain
@@ -185,6 +515,23 @@ type bintree struct {
 
 var _bintree = &bintree{nil, map[string]*bintree{
 	"init.tpl.hcl": {initTplHcl, map[string]*bintree{}},
+	"plugin": {nil, map[string]*bintree{
+		"Makefile.tpl": {pluginMakefileTpl, map[string]*bintree{}},
+		"README.md.tpl": {pluginReadmeMdTpl, map[string]*bintree{}},
+		"builder.go.tpl": {pluginBuilderGoTpl, map[string]*bintree{}},
+		"builder_test.go.tpl": {pluginBuilder_testGoTpl, map[string]*bintree{}},
+		"gitignore.tpl": {pluginGitignoreTpl, map[string]*bintree{}},
+		"go.mod.tpl": {pluginGoModTpl, map[string]*bintree{}},
+		"main.go.tpl": {pluginMainGoTpl, map[string]*bintree{}},
+		"mapper.go.tpl": {pluginMapperGoTpl, map[string]*bintree{}},
+		"options.go.tpl": {pluginOptionsGoTpl, map[string]*bintree{}},
+		"platform.go.tpl": {pluginPlatformGoTpl, map[string]*bintree{}},
+		"platform_test.go.tpl": {pluginPlatform_testGoTpl, map[string]*bintree{}},
+		"plugin.proto.tpl": {pluginPluginProtoTpl, map[string]*bintree{}},
+		"plugin_test.go.tpl": {pluginPlugin_testGoTpl, map[string]*bintree{}},
+		"releasemanager.go.tpl": {pluginReleasemanagerGoTpl, map[string]*bintree{}},
+		"releasemanager_test.go.tpl": {pluginReleasemanager_testGoTpl, map[string]*bintree{}},
+	}},
 }}
 
 // RestoreAsset restores an asset under the given directory
@@ -126,7 +126,7 @@ func (c *DeploymentCreateCommand) Flags() *flag.Sets {
 }
 
 func (c *DeploymentCreateCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictNothing
+	return c.predictAppTarget()
 }
 
 func (c *DeploymentCreateCommand) AutocompleteFlags() complete.Flags {
@@ -189,7 +189,7 @@ func (c *DeploymentDestroyCommand) Flags() *flag.Sets {
 }
 
 func (c *DeploymentDestroyCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictNothing
+	return c.predictAppTarget()
 }
 
 func (c *DeploymentDestroyCommand) AutocompleteFlags() complete.Flags {
@@ -2,7 +2,6 @@ package cli
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
@@ -100,15 +99,20 @@ func (c *DeploymentListCommand) Run(args []string) int {
 			PhysicalState: phyState,
 			Status:        c.filterFlags.statusFilters(),
 			Order:         c.filterFlags.orderOp(),
+			Labels:        c.filterFlags.labels(),
 			LoadDetails:   pb.Deployment_BUILD,
 		})
 		if err != nil {
 			c.project.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
 			return ErrSentinel
 		}
-		sort.Sort(serversort.DeploymentCompleteDesc(resp.Deployments))
+		sort.Sort(c.filterFlags.sortBy(
+			serversort.DeploymentCompleteDesc(resp.Deployments),
+			serversort.DeploymentStartDesc(resp.Deployments),
+			serversort.DeploymentCompleteDesc(resp.Deployments),
+		))
 
-		if c.flagJson {
+		if c.flagJson || c.jsonOutput() {
 			return c.displayJson(resp.Deployments)
 		}
 
@@ -285,14 +289,7 @@ func (c *DeploymentListCommand) displayJson(deployments []*pb.Deployment) error
 		output = append(output, i)
 	}
 
-	data, err := json.MarshalIndent(output, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	c.ui.Output(string(data))
-
-	return nil
+	return c.writeJSON(output)
 }
 
 func (c *DeploymentListCommand) artifactJson(art *pb.PushedArtifact) interface{} {
@@ -328,7 +325,7 @@ func (c *DeploymentListCommand) buildJson(b *pb.Build) interface{} {
 }
 
 func (c *DeploymentListCommand) Flags() *flag.Sets {
-	return c.flagSet(0, func(set *flag.Sets) {
+	return c.flagSet(flagSetCache|flagSetOutput, func(set *flag.Sets) {
 		f := set.NewSet("Command Options")
 		f.BoolVar(&flag.BoolVar{
 			Name:   "workspace-all",
@@ -346,7 +343,7 @@ func (c *DeploymentListCommand) Flags() *flag.Sets {
 		f.BoolVar(&flag.BoolVar{
 			Name:   "json",
 			Target: &c.flagJson,
-			Usage:  "Output the deployment information as JSON.",
+			Usage:  "Output the deployment information as JSON. This is the same as -output=json.",
 		})
 
 		initIdFormat(f, &c.flagId)
@@ -355,7 +352,7 @@ func (c *DeploymentListCommand) Flags() *flag.Sets {
 }
 
 func (c *DeploymentListCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictNothing
+	return c.predictAppTarget()
 }
 
 func (c *DeploymentListCommand) AutocompleteFlags() complete.Flags {
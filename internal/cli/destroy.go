@@ -4,6 +4,8 @@ import (
 	"context"
 
 	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
 	clientpkg "github.com/hashicorp/waypoint/internal/client"
@@ -14,6 +16,10 @@ import (
 
 type DestroyCommand struct {
 	*baseCommand
+
+	flagDeployment string
+	flagRelease    string
+	flagForce      bool
 }
 
 func (c *DestroyCommand) Run(args []string) int {
@@ -26,12 +32,23 @@ func (c *DestroyCommand) Run(args []string) int {
 		return 1
 	}
 
+	if c.flagDeployment != "" && c.flagRelease != "" {
+		c.ui.Output("Only one of -deployment and -release may be set.\n\n%s",
+			c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
 	err := c.DoApp(c.Ctx, func(ctx context.Context, app *clientpkg.App) error {
-		if err := app.Destroy(ctx, &pb.Job_DestroyOp{
-			Target: &pb.Job_DestroyOp_Workspace{
-				Workspace: &empty.Empty{},
-			},
-		}); err != nil {
+		op, err := c.destroyOp(ctx, app)
+		if err != nil {
+			return err
+		}
+		if op == nil {
+			// Nothing to destroy.
+			return nil
+		}
+
+		if err := app.Destroy(ctx, op); err != nil {
 			c.ui.Output("Error destroying: %s", err.Error(), terminal.WithErrorStyle())
 			return ErrSentinel
 		}
@@ -50,8 +67,116 @@ func (c *DestroyCommand) Run(args []string) int {
 	return 0
 }
 
+// destroyOp returns the destroy operation for the flags. This returns nil
+// if there is nothing to destroy because the target is already destroyed.
+func (c *DestroyCommand) destroyOp(ctx context.Context, app *clientpkg.App) (*pb.Job_DestroyOp, error) {
+	client := c.project.Client()
+
+	switch {
+	case c.flagDeployment != "":
+		deployment, err := client.GetDeployment(ctx, &pb.GetDeploymentRequest{
+			Ref: operationRef(app.Ref(), c.flagDeployment),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if deployment.State == pb.Operation_DESTROYED {
+			app.UI.Output("Deployment %s is already destroyed.", deployment.Id,
+				terminal.WithSuccessStyle())
+			return nil, nil
+		}
+
+		// Destroying the released deployment would take down the app, so
+		// we require -force. Force also skips this check in case the
+		// release can't be queried.
+		if !c.flagForce {
+			release, err := client.GetLatestRelease(ctx, &pb.GetLatestReleaseRequest{
+				Application: app.Ref(),
+				Workspace:   c.project.WorkspaceRef(),
+			})
+			if status.Code(err) == codes.NotFound {
+				err = nil
+				release = nil
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			if release != nil &&
+				release.State != pb.Operation_DESTROYED &&
+				release.DeploymentId == deployment.Id {
+				app.UI.Output(
+					"Deployment %s is currently released. Release another deployment "+
+						"first or use -force to destroy it anyway.",
+					deployment.Id, terminal.WithErrorStyle())
+				return nil, ErrSentinel
+			}
+		}
+
+		return &pb.Job_DestroyOp{
+			Target: &pb.Job_DestroyOp_Deployment{
+				Deployment: deployment,
+			},
+			Force: c.flagForce,
+		}, nil
+
+	case c.flagRelease != "":
+		release, err := client.GetRelease(ctx, &pb.GetReleaseRequest{
+			Ref: operationRef(app.Ref(), c.flagRelease),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if release.State == pb.Operation_DESTROYED {
+			app.UI.Output("Release %s is already destroyed.", release.Id,
+				terminal.WithSuccessStyle())
+			return nil, nil
+		}
+
+		return &pb.Job_DestroyOp{
+			Target: &pb.Job_DestroyOp_Release{
+				Release: release,
+			},
+			Force: c.flagForce,
+		}, nil
+
+	default:
+		return &pb.Job_DestroyOp{
+			Target: &pb.Job_DestroyOp_Workspace{
+				Workspace: &empty.Empty{},
+			},
+			Force: c.flagForce,
+		}, nil
+	}
+}
+
 func (c *DestroyCommand) Flags() *flag.Sets {
 	return c.flagSet(flagSetOperation, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:   "deployment",
+			Target: &c.flagDeployment,
+			Usage: "Destroy only the deployment with this ID or sequence number " +
+				"rather than all resources.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "release",
+			Target: &c.flagRelease,
+			Usage: "Destroy only the release with this ID or sequence number " +
+				"rather than all resources.",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:   "force",
+			Target: &c.flagForce,
+			Usage: "Skip state checks and mark resources as destroyed even if " +
+				"the plugin fails to destroy them, such as when they were " +
+				"already removed outside of Waypoint.",
+			Default: false,
+		})
 	})
 }
 
@@ -76,5 +201,13 @@ Usage: waypoint destroy [options]
   This targets one app in one workspace. You must call this for each workspace
   you've used if you want to destroy everything.
 
+  To destroy a single deployment or release rather than everything, use
+  -deployment or -release with its ID or sequence number. The deployment
+  that is currently released can't be destroyed this way unless -force is
+  set.
+
+  If the resources were already removed outside of Waypoint, use -force to
+  mark them as destroyed even though the plugin fails to destroy them.
+
 ` + c.Flags().Help())
 }
@@ -0,0 +1,332 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	clientpkg "github.com/hashicorp/waypoint/internal/client"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	serversort "github.com/hashicorp/waypoint/internal/server/sort"
+)
+
+type DiffCommand struct {
+	*baseCommand
+
+	flagId idFormat
+}
+
+func (c *DiffCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithSingleApp(),
+	); err != nil {
+		return 1
+	}
+	args = c.args
+
+	if len(args) > 2 {
+		c.ui.Output(c.Flags().Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	err := c.DoApp(c.Ctx, func(ctx context.Context, app *clientpkg.App) error {
+		from, to, err := c.deployments(ctx, app, args)
+		if err != nil {
+			app.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return ErrSentinel
+		}
+
+		changes := diffDeployments(from, to, &c.flagId)
+		recorded := from.ConfigFingerprint != nil && to.ConfigFingerprint != nil
+
+		if c.jsonOutput() {
+			return c.writeJSON(map[string]interface{}{
+				"from":            from.Id,
+				"to":              to.Id,
+				"config_recorded": recorded,
+				"changes":         changes,
+			})
+		}
+
+		app.UI.Output("Comparing deployment %s to %s",
+			c.flagId.FormatId(from.Sequence, from.Id),
+			c.flagId.FormatId(to.Sequence, to.Id),
+			terminal.WithHeaderStyle())
+
+		if !recorded {
+			app.UI.Output(
+				"The configuration of one of the deployments wasn't recorded because it was\n"+
+					"created by an older version of Waypoint. Plugin configuration and config\n"+
+					"variables won't be compared.",
+				terminal.WithWarningStyle())
+		}
+
+		if len(changes) == 0 {
+			app.UI.Output("No changes.", terminal.WithSuccessStyle())
+			return nil
+		}
+
+		tbl := terminal.NewTable("Section", "Key", "From", "To")
+		for _, change := range changes {
+			tbl.Rich([]string{
+				change.Section,
+				change.Key,
+				change.From,
+				change.To,
+			}, nil)
+		}
+		c.ui.Table(tbl)
+
+		return nil
+	})
+	if err != nil {
+		if err != ErrSentinel {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		}
+
+		return 1
+	}
+
+	return 0
+}
+
+// deployments returns the deployments to compare based on the arguments.
+// With no arguments, the latest two deployments are compared. With one
+// argument, the given deployment is compared to the latest.
+func (c *DiffCommand) deployments(
+	ctx context.Context,
+	app *clientpkg.App,
+	args []string,
+) (*pb.Deployment, *pb.Deployment, error) {
+	client := c.project.Client()
+
+	get := func(id string) (*pb.Deployment, error) {
+		return client.GetDeployment(ctx, &pb.GetDeploymentRequest{
+			Ref:         operationRef(app.Ref(), id),
+			LoadDetails: pb.Deployment_BUILD,
+		})
+	}
+
+	if len(args) == 2 {
+		from, err := get(args[0])
+		if err != nil {
+			return nil, nil, err
+		}
+
+		to, err := get(args[1])
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return from, to, nil
+	}
+
+	resp, err := client.ListDeployments(ctx, &pb.ListDeploymentsRequest{
+		Application: app.Ref(),
+		Workspace:   c.project.WorkspaceRef(),
+		Order: &pb.OperationOrder{
+			Order: pb.OperationOrder_START_TIME,
+			Desc:  true,
+			Limit: 2,
+		},
+		LoadDetails: pb.Deployment_BUILD,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	deployments := resp.Deployments
+	sort.Sort(serversort.DeploymentStartDesc(deployments))
+
+	if len(args) == 1 {
+		if len(deployments) == 0 {
+			return nil, nil, errors.New("There are no deployments to compare to.")
+		}
+
+		from, err := get(args[0])
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return from, deployments[0], nil
+	}
+
+	if len(deployments) < 2 {
+		return nil, nil, errors.New(
+			"At least two deployments are required to compare. Specify the deployments\n" +
+				"to compare as arguments to compare deployments in other workspaces.")
+	}
+
+	return deployments[1], deployments[0], nil
+}
+
+// deploymentChange is a single difference between two deployments.
+type deploymentChange struct {
+	Section string `json:"section"`
+	Key     string `json:"key"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+}
+
+// diffDeployments returns the differences between two deployments. The
+// deployments should be loaded with their artifacts and builds.
+func diffDeployments(from, to *pb.Deployment, ids *idFormat) []*deploymentChange {
+	result := []*deploymentChange{}
+	add := func(section, key, fromV, toV string) {
+		if fromV != toV {
+			result = append(result, &deploymentChange{
+				Section: section,
+				Key:     key,
+				From:    fromV,
+				To:      toV,
+			})
+		}
+	}
+
+	// The artifact and the image it was built from.
+	fromArt, toArt := from.Preload.GetArtifact(), to.Preload.GetArtifact()
+	add("artifact", "id",
+		ids.FormatId(fromArt.GetSequence(), from.ArtifactId),
+		ids.FormatId(toArt.GetSequence(), to.ArtifactId))
+	add("artifact", "image",
+		from.Preload.GetBuild().GetLabels()["common/image-id"],
+		to.Preload.GetBuild().GetLabels()["common/image-id"])
+
+	// The plugin and its configuration.
+	add("plugin", "type", from.Component.GetName(), to.Component.GetName())
+	if from.ConfigFingerprint != nil && to.ConfigFingerprint != nil {
+		result = append(result, diffMap("plugin config",
+			from.ConfigFingerprint.PluginConfig,
+			to.ConfigFingerprint.PluginConfig,
+			nil)...)
+
+		// Config variables only store a hash of the value so we show a
+		// short form of the hash, which is enough to tell values apart.
+		result = append(result, diffMap("config",
+			from.ConfigFingerprint.ConfigVars,
+			to.ConfigFingerprint.ConfigVars,
+			func(v string) string {
+				if len(v) > 8 {
+					v = v[:8]
+				}
+
+				return v
+			})...)
+	}
+
+	result = append(result, diffMap("labels",
+		withoutSystemLabels(from.Labels),
+		withoutSystemLabels(to.Labels),
+		nil)...)
+
+	return result
+}
+
+// diffMap returns the changes between two maps, sorted by key. If format
+// is non-nil, it is used to format the values for display.
+func diffMap(section string, from, to map[string]string, format func(string) string) []*deploymentChange {
+	keys := map[string]struct{}{}
+	for k := range from {
+		keys[k] = struct{}{}
+	}
+	for k := range to {
+		keys[k] = struct{}{}
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var result []*deploymentChange
+	for _, k := range sorted {
+		fromV, fromOk := from[k]
+		toV, toOk := to[k]
+		if fromOk == toOk && fromV == toV {
+			continue
+		}
+
+		if format != nil {
+			if fromOk {
+				fromV = format(fromV)
+			}
+			if toOk {
+				toV = format(toV)
+			}
+		}
+
+		if !fromOk {
+			fromV = "(not set)"
+		}
+		if !toOk {
+			toV = "(not set)"
+		}
+
+		result = append(result, &deploymentChange{
+			Section: section,
+			Key:     k,
+			From:    fromV,
+			To:      toV,
+		})
+	}
+
+	return result
+}
+
+// withoutSystemLabels returns the labels without the labels that are set
+// by Waypoint itself, which are different for every deployment.
+func withoutSystemLabels(labels map[string]string) map[string]string {
+	result := map[string]string{}
+	for k, v := range labels {
+		if !strings.HasPrefix(k, "waypoint/") {
+			result[k] = v
+		}
+	}
+
+	return result
+}
+
+func (c *DiffCommand) Flags() *flag.Sets {
+	return c.flagSet(flagSetOutput, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		initIdFormat(f, &c.flagId)
+	})
+}
+
+func (c *DiffCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *DiffCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *DiffCommand) Synopsis() string {
+	return "Show what changed between two deployments"
+}
+
+func (c *DiffCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint diff [options] [FROM] [TO]
+
+  Show what changed between two deployments.
+
+  This compares the artifact and image, the plugin configuration, the
+  config variables, and the labels of the deployments. Config variables
+  are compared by a hash of their value so secrets are never shown.
+
+  FROM and TO are the IDs or sequence numbers of the deployments. If only
+  FROM is given, it is compared to the latest deployment. If neither is
+  given, the latest two deployments in the workspace are compared.
+
+` + c.Flags().Help())
+}
@@ -2,9 +2,15 @@ package cli
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/posener/complete"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
 	clientpkg "github.com/hashicorp/waypoint/internal/client"
@@ -16,6 +22,9 @@ import (
 
 type ExecCommand struct {
 	*baseCommand
+
+	flagInstance   string
+	flagDeployment string
 }
 
 func (c *ExecCommand) Run(args []string) int {
@@ -35,36 +44,38 @@ func (c *ExecCommand) Run(args []string) int {
 	var exitCode int
 	client := c.project.Client()
 	err := c.DoApp(c.Ctx, func(ctx context.Context, app *clientpkg.App) error {
-		// Get the latest deployment
-		resp, err := client.ListDeployments(ctx, &pb.ListDeploymentsRequest{
-			Application: app.Ref(),
-			Order: &pb.OperationOrder{
-				Limit: 1,
-				Order: pb.OperationOrder_COMPLETE_TIME,
-				Desc:  true,
-			},
-			PhysicalState: pb.Operation_CREATED,
-		})
+		deploy, err := c.deployment(ctx, client, app)
 		if err != nil {
 			app.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
 			return ErrSentinel
 		}
-		if len(resp.Deployments) == 0 {
+		if deploy == nil {
 			app.UI.Output("No successful deployments found.", terminal.WithErrorStyle())
 			return ErrSentinel
 		}
 
+		instanceId := c.flagInstance
+		if instanceId == "" {
+			instanceId, err = c.pickInstance(ctx, client, deploy)
+			if err != nil {
+				app.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+				return ErrSentinel
+			}
+		}
+
 		client := &execclient.Client{
 			Logger:        c.Log,
 			UI:            c.ui,
 			Context:       ctx,
 			Client:        client,
-			DeploymentId:  resp.Deployments[0].Id,
-			DeploymentSeq: resp.Deployments[0].Sequence,
+			DeploymentId:  deploy.Id,
+			DeploymentSeq: deploy.Sequence,
+			InstanceId:    instanceId,
 			Args:          args,
 			Stdin:         os.Stdin,
 			Stdout:        os.Stdout,
 			Stderr:        os.Stderr,
+			NoPty:         c.nonInteractive(),
 		}
 
 		exitCode, err = client.Run()
@@ -82,12 +93,161 @@ func (c *ExecCommand) Run(args []string) int {
 	return exitCode
 }
 
+// deployment returns the deployment to exec into. This is the deployment
+// given by -deployment, the deployment of the instance given by -instance,
+// or the latest successful deployment, in that order. This returns nil if
+// there are no successful deployments.
+func (c *ExecCommand) deployment(
+	ctx context.Context,
+	client pb.WaypointClient,
+	app *clientpkg.App,
+) (*pb.Deployment, error) {
+	if c.flagDeployment != "" {
+		ref := &pb.Ref_Operation{
+			Target: &pb.Ref_Operation_Id{Id: c.flagDeployment},
+		}
+		if i, err := strconv.ParseUint(c.flagDeployment, 10, 64); err == nil {
+			ref.Target = &pb.Ref_Operation_Sequence{
+				Sequence: &pb.Ref_OperationSeq{
+					Application: app.Ref(),
+					Number:      i,
+				},
+			}
+		}
+
+		return client.GetDeployment(ctx, &pb.GetDeploymentRequest{Ref: ref})
+	}
+
+	if c.flagInstance != "" {
+		resp, err := client.ListInstances(ctx, &pb.ListInstancesRequest{
+			Scope: &pb.ListInstancesRequest_Application_{
+				Application: &pb.ListInstancesRequest_Application{
+					Application: app.Ref(),
+					Workspace:   c.project.WorkspaceRef(),
+				},
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, inst := range resp.Instances {
+			if inst.Id == c.flagInstance {
+				return client.GetDeployment(ctx, &pb.GetDeploymentRequest{
+					Ref: &pb.Ref_Operation{
+						Target: &pb.Ref_Operation_Id{Id: inst.DeploymentId},
+					},
+				})
+			}
+		}
+
+		return nil, status.Errorf(codes.NotFound,
+			"Instance %q not found. Run this command with -deployment to list "+
+				"the instances of a deployment.", c.flagInstance)
+	}
+
+	// Get the latest deployment
+	resp, err := client.ListDeployments(ctx, &pb.ListDeploymentsRequest{
+		Application: app.Ref(),
+		Order: &pb.OperationOrder{
+			Limit: 1,
+			Order: pb.OperationOrder_COMPLETE_TIME,
+			Desc:  true,
+		},
+		PhysicalState: pb.Operation_CREATED,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Deployments) == 0 {
+		return nil, nil
+	}
+
+	return resp.Deployments[0], nil
+}
+
+// pickInstance asks the user which instance of the deployment to exec
+// into if there is more than one. This returns an empty ID to let the
+// server choose the least loaded instance if there is only one instance,
+// the user doesn't choose, or we can't ask.
+func (c *ExecCommand) pickInstance(
+	ctx context.Context,
+	client pb.WaypointClient,
+	deploy *pb.Deployment,
+) (string, error) {
+	if !c.ui.Interactive() {
+		return "", nil
+	}
+
+	resp, err := client.ListInstances(ctx, &pb.ListInstancesRequest{
+		Scope: &pb.ListInstancesRequest_DeploymentId{
+			DeploymentId: deploy.Id,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Instances) < 2 {
+		return "", nil
+	}
+
+	sort.Slice(resp.Instances, func(i, j int) bool {
+		return resp.Instances[i].Id < resp.Instances[j].Id
+	})
+
+	c.ui.Output("Deployment v%d has %d instances:", deploy.Sequence, len(resp.Instances),
+		terminal.WithHeaderStyle())
+	table := terminal.NewTable("", "Instance ID")
+	for i, inst := range resp.Instances {
+		table.Rich([]string{strconv.Itoa(i + 1), inst.Id}, nil)
+	}
+	c.ui.Table(table)
+
+	for {
+		result, err := c.ui.Input(&terminal.Input{
+			Prompt: fmt.Sprintf("Instance to exec into [1-%d, or blank for any]:", len(resp.Instances)),
+		})
+		if err != nil {
+			return "", err
+		}
+
+		result = strings.TrimSpace(result)
+		if result == "" {
+			return "", nil
+		}
+
+		if i, err := strconv.Atoi(result); err == nil && i >= 1 && i <= len(resp.Instances) {
+			return resp.Instances[i-1].Id, nil
+		}
+		for _, inst := range resp.Instances {
+			if inst.Id == result {
+				return inst.Id, nil
+			}
+		}
+	}
+}
+
 func (c *ExecCommand) Flags() *flag.Sets {
-	return c.flagSet(0, nil)
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:   "instance",
+			Target: &c.flagInstance,
+			Usage: "ID of the instance to exec into. By default, you are asked to " +
+				"pick an instance if the deployment has more than one, or the " +
+				"least loaded instance is used if the terminal isn't interactive.",
+		})
+		f.StringVar(&flag.StringVar{
+			Name:   "deployment",
+			Target: &c.flagDeployment,
+			Usage: "ID or sequence number of the deployment to exec into. This " +
+				"defaults to the latest successful deployment.",
+		})
+	})
 }
 
 func (c *ExecCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictNothing
+	return c.predictAppTarget()
 }
 
 func (c *ExecCommand) AutocompleteFlags() complete.Flags {
@@ -104,5 +264,14 @@ Usage: waypoint exec [options]
 
   Execute a command in the context of a running application instance.
 
+  By default this runs in an instance of the latest successful deployment.
+  Use -deployment to choose another deployment and -instance to choose a
+  specific instance, such as a misbehaving replica. If the deployment has
+  more than one instance and the terminal is interactive, you are asked to
+  pick one.
+
+  With -non-interactive, the server chooses the instance unless -instance
+  is given, and the command runs without a pty even in a terminal.
+
 ` + c.Flags().Help())
 }
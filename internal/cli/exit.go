@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/hashicorp/hcl/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint/internal/clierrors"
+)
+
+// Exit codes for all commands. These are documented so that wrappers such
+// as CI scripts can branch on the type of failure, so existing values must
+// never change.
+const (
+	// exitCodeError is for an operation that failed or for any failure
+	// that doesn't have a more specific code.
+	exitCodeError = 1
+
+	// exitCodeConfig is for invalid configuration or arguments.
+	exitCodeConfig = 2
+
+	// exitCodeAuth is for failures to authenticate with the server.
+	exitCodeAuth = 3
+
+	// exitCodeUnreachable is for failures to connect to the server.
+	exitCodeUnreachable = 4
+
+	// exitCodePartial is for commands that target multiple apps where
+	// some apps succeeded and others failed.
+	exitCodePartial = 5
+)
+
+// exitCodeTypes are the names of the exit codes in the JSON error.
+var exitCodeTypes = map[int]string{
+	exitCodeError:       "error",
+	exitCodeConfig:      "config",
+	exitCodeAuth:        "auth",
+	exitCodeUnreachable: "unreachable",
+	exitCodePartial:     "partial",
+}
+
+// exitCodeForError returns the exit code that describes err.
+func exitCodeForError(err error) int {
+	switch status.Code(err) {
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return exitCodeAuth
+
+	case codes.Unavailable:
+		return exitCodeUnreachable
+	}
+
+	// Connecting to the server blocks until it succeeds or times out.
+	if errors.Is(err, context.DeadlineExceeded) {
+		return exitCodeUnreachable
+	}
+
+	switch err.(type) {
+	case hcl.Diagnostics, *hcl.Diagnostic:
+		return exitCodeConfig
+	}
+
+	return exitCodeError
+}
+
+// setExitError records err as the reason the command failed with the
+// given exit code. The first specific failure is kept since later errors
+// are usually a consequence of it.
+func (c *baseCommand) setExitError(code int, err error) {
+	if err == ErrSentinel {
+		err = nil
+	}
+
+	if c.exitCode != 0 && (c.exitCode != exitCodeError || code == exitCodeError) {
+		return
+	}
+
+	c.exitCode = code
+	c.exitErr = err
+}
+
+// exitErrorInterceptor records errors from the server that have a
+// specific exit code, such as authentication failures. Commands usually
+// only output these errors so this is how we find out about them.
+func (c *baseCommand) exitErrorInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if err != nil {
+			if code := exitCodeForError(err); code != exitCodeError {
+				c.setExitError(code, err)
+			}
+		}
+
+		return err
+	}
+}
+
+// Exit returns the exit code for a command that exited with code. A
+// failing command exits with the code for the recorded failure, if any.
+// With -output=json, the failure is also written to stderr as JSON.
+func (c *baseCommand) Exit(code int) int {
+	if code == 0 {
+		return 0
+	}
+
+	if code == exitCodeError && c.exitCode != 0 {
+		code = c.exitCode
+	}
+
+	if c.jsonOutput() {
+		typ, ok := exitCodeTypes[code]
+		if !ok {
+			typ = exitCodeTypes[exitCodeError]
+		}
+
+		payload := map[string]interface{}{
+			"exit_code": code,
+			"type":      typ,
+		}
+		if c.exitErr != nil {
+			payload["message"] = clierrors.Humanize(c.exitErr)
+		}
+
+		data, err := json.Marshal(map[string]interface{}{"error": payload})
+		if err == nil {
+			os.Stderr.Write(append(data, '\n'))
+		}
+	}
+
+	return code
+}
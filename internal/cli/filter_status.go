@@ -9,16 +9,20 @@ import (
 
 type filterOption int
 
+// The filter options are bit flags so they can be combined. The zero
+// value, fillterOptionAll, enables every filter.
 const (
-	fillterOptionAll filterOption = iota
-	filterOptionState
+	fillterOptionAll  filterOption = 0
+	filterOptionState filterOption = 1 << iota
 	filterOptionPhyState
 	filterOptionOrder
+	filterOptionLabel
 )
 
 type filterFlags struct {
 	flagStatusFilter []string
 	flagPhysState    string
+	flagLabels       map[string]string
 
 	order *pb.OperationOrder
 }
@@ -116,6 +120,36 @@ func (ff *filterFlags) orderOp() *pb.OperationOrder {
 	return ff.order
 }
 
+// labels returns the labels that values must have to be listed.
+func (ff *filterFlags) labels() map[string]string {
+	return ff.flagLabels
+}
+
+// sortBy returns the sort for the values based on -order-by and -desc.
+// start and complete must sort the same values by start and complete time,
+// most recent first. If -order-by isn't set, def is returned.
+func (ff *filterFlags) sortBy(def, start, complete sort.Interface) sort.Interface {
+	if ff.order == nil {
+		return def
+	}
+
+	var result sort.Interface
+	switch ff.order.Order {
+	case pb.OperationOrder_START_TIME:
+		result = start
+	case pb.OperationOrder_COMPLETE_TIME:
+		result = complete
+	default:
+		return def
+	}
+
+	if !ff.order.Desc {
+		result = sort.Reverse(result)
+	}
+
+	return result
+}
+
 func initFilterFlags(set *flag.Sets, ff *filterFlags, opts filterOption) {
 	f := set.NewSet("Filter Options")
 
@@ -177,4 +211,13 @@ func initFilterFlags(set *flag.Sets, ff *filterFlags, opts filterOption) {
 			},
 		})
 	}
+
+	if opts == fillterOptionAll || opts&filterOptionLabel != 0 {
+		f.StringMapVar(&flag.StringMapVar{
+			Name:   "label-selector",
+			Target: &ff.flagLabels,
+			Usage: "Only show values with this label set to the given value. " +
+				"Can be specified multiple times, in which case all labels must match.",
+		})
+	}
 }
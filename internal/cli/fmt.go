@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	configpkg "github.com/hashicorp/waypoint/internal/config"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+)
+
+type FmtCommand struct {
+	*baseCommand
+
+	flagCheck bool
+	flagWrite bool
+}
+
+func (c *FmtCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+		WithClient(false),
+	); err != nil {
+		return 1
+	}
+	args = c.args
+
+	// With no arguments we format the configuration for this directory.
+	if len(args) == 0 {
+		path, err := c.initConfigPath()
+		if err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+		if path == "" {
+			c.ui.Output("A Waypoint configuration file is required but wasn't found.",
+				terminal.WithErrorStyle())
+			return 1
+		}
+
+		args = []string{path}
+	}
+
+	var unformatted bool
+	for _, path := range args {
+		changed, err := c.format(path)
+		if err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		unformatted = unformatted || changed
+	}
+
+	if c.flagCheck && unformatted {
+		return 1
+	}
+
+	return 0
+}
+
+// format formats the file at path, or stdin if path is "-", and returns
+// true if the file wasn't already formatted.
+func (c *FmtCommand) format(path string) (bool, error) {
+	if path == "-" {
+		src, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return false, err
+		}
+
+		result, diags := configpkg.Format(src, "<stdin>")
+		if diags.HasErrors() {
+			return false, diags
+		}
+
+		if !c.flagCheck {
+			_, err = os.Stdout.Write(result)
+		}
+
+		return !bytes.Equal(src, result), err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+	if info.IsDir() {
+		path = filepath.Join(path, configpkg.Filename)
+		if info, err = os.Stat(path); err != nil {
+			return false, err
+		}
+	}
+
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	result, diags := configpkg.Format(src, path)
+	if diags.HasErrors() {
+		return false, diags
+	}
+
+	changed := !bytes.Equal(src, result)
+	switch {
+	case c.flagCheck:
+		// List the files that aren't formatted so that it is clear what
+		// to fix when this fails in CI.
+		if changed {
+			c.ui.Output(path)
+		}
+
+	case !c.flagWrite:
+		_, err = os.Stdout.Write(result)
+
+	case changed:
+		c.ui.Output(path)
+		err = ioutil.WriteFile(path, result, info.Mode())
+	}
+
+	return changed, err
+}
+
+func (c *FmtCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.BoolVar(&flag.BoolVar{
+			Name:   "check",
+			Target: &c.flagCheck,
+			Usage: "Check if the files are formatted without changing them. " +
+				"The files that aren't formatted are listed and the exit code " +
+				"is 1 if there are any.",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "write",
+			Target:  &c.flagWrite,
+			Default: true,
+			Usage: "Write the formatted configuration back to the files. If " +
+				"false, the formatted configuration is written to stdout instead.",
+		})
+	})
+}
+
+func (c *FmtCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFiles("*.hcl")
+}
+
+func (c *FmtCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *FmtCommand) Synopsis() string {
+	return "Rewrite waypoint.hcl files in the canonical format"
+}
+
+func (c *FmtCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint fmt [options] [FILE...]
+
+  Rewrite waypoint.hcl files in the canonical format.
+
+  This formats the configuration in the same style as "terraform fmt", so
+  that every waypoint.hcl file looks the same. The files that are changed
+  are listed.
+
+  With no arguments, the waypoint.hcl file for the current directory is
+  formatted. Arguments can be files or directories containing a
+  waypoint.hcl file. If the argument is "-", the configuration is read
+  from stdin and the formatted configuration is written to stdout.
+
+  Use -check in CI to fail if any of the files aren't formatted.
+
+` + c.Flags().Help())
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
 )
 
 type idFormat struct {
@@ -26,3 +27,22 @@ func (i *idFormat) FormatId(seq uint64, long string) string {
 
 	return strconv.FormatUint(seq, 10)
 }
+
+// operationRef returns the reference to an operation of app given by the
+// user. This can be the ID of the operation or its sequence number.
+func operationRef(app *pb.Ref_Application, id string) *pb.Ref_Operation {
+	if v, err := strconv.ParseUint(id, 10, 64); err == nil {
+		return &pb.Ref_Operation{
+			Target: &pb.Ref_Operation_Sequence{
+				Sequence: &pb.Ref_OperationSeq{
+					Application: app,
+					Number:      v,
+				},
+			},
+		}
+	}
+
+	return &pb.Ref_Operation{
+		Target: &pb.Ref_Operation_Id{Id: id},
+	}
+}
@@ -31,6 +31,24 @@ func (c *HostnameListCommand) Run(args []string) int {
 		return 1
 	}
 
+	if c.jsonOutput() {
+		output := []map[string]interface{}{}
+		for _, hostname := range resp.Hostnames {
+			output = append(output, map[string]interface{}{
+				"hostname": hostname.Hostname,
+				"fqdn":     hostname.Fqdn,
+				"labels":   hostname.TargetLabels,
+			})
+		}
+
+		if err := c.writeJSON(output); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
 	table := terminal.NewTable("Hostname", "FQDN", "Labels")
 	for _, hostname := range resp.Hostnames {
 		table.Rich([]string{
@@ -45,7 +63,7 @@ func (c *HostnameListCommand) Run(args []string) int {
 }
 
 func (c *HostnameListCommand) Flags() *flag.Sets {
-	return c.flagSet(0, nil)
+	return c.flagSet(flagSetOutput, nil)
 }
 
 func (c *HostnameListCommand) AutocompleteArgs() complete.Predictor {
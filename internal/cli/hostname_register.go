@@ -63,7 +63,7 @@ func (c *HostnameRegisterCommand) Flags() *flag.Sets {
 }
 
 func (c *HostnameRegisterCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictNothing
+	return c.predictAppTarget()
 }
 
 func (c *HostnameRegisterCommand) AutocompleteFlags() complete.Flags {
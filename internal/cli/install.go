@@ -205,7 +205,7 @@ func (c *InstallCommand) Run(args []string) int {
 		// can connect to the server. We don't want to reuse the bootstrap
 		// token that is shared with the CLI cause that can be revoked.
 		s.Update("Retrieving new auth token for runner...")
-		resp, err := client.GenerateLoginToken(c.Ctx, &empty.Empty{})
+		resp, err := client.GenerateLoginToken(c.Ctx, &pb.LoginTokenRequest{})
 		if err != nil {
 			c.ui.Output(
 				"Error retrieving auth token for runner: %s\n\n%s",
@@ -0,0 +1,248 @@
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/posener/complete"
+	"github.com/skratchdot/open-golang/open"
+
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type LoginCommand struct {
+	*baseCommand
+
+	flagCallbackPort int
+	flagNoBrowser    bool
+}
+
+func (c *LoginCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	if c.project.Local() {
+		c.ui.Output(errLoginLocal, terminal.WithErrorStyle())
+		return 1
+	}
+
+	state, err := loginRandom()
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+	nonce, err := loginRandom()
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	// Listen for the callback before sending the browser anywhere. The
+	// server must allow this redirect URI in its OIDC configuration.
+	ln, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", c.flagCallbackPort))
+	if err != nil {
+		c.ui.Output(
+			"Error listening for the login callback: %s",
+			clierrors.Humanize(err),
+			terminal.WithErrorStyle(),
+		)
+		return 1
+	}
+	defer ln.Close()
+	redirectURI := fmt.Sprintf("http://localhost:%d/oidc/callback", c.flagCallbackPort)
+
+	client := c.project.Client()
+	resp, err := client.GetOIDCAuthURL(c.Ctx, &pb.GetOIDCAuthURLRequest{
+		RedirectUri: redirectURI,
+		State:       state,
+		Nonce:       nonce,
+	})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	c.ui.Output("Complete the login in your browser. If it doesn't open, visit:")
+	c.ui.Output("%s", resp.Url, terminal.WithInfoStyle())
+	if !c.flagNoBrowser {
+		open.Run(resp.Url)
+	}
+
+	code, err := loginCallback(c.Ctx, ln, state)
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	authResp, err := client.CompleteOIDCAuth(c.Ctx, &pb.CompleteOIDCAuthRequest{
+		RedirectUri: redirectURI,
+		Code:        code,
+		Nonce:       nonce,
+	})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	// Store the token in the default context so that future commands use
+	// it. If there is no default context, the connection is from flags or
+	// the environment, so we output the token for the user to use.
+	name, err := c.contextStorage.Default()
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+	if name == "" {
+		c.ui.Output(authResp.Token)
+		return 0
+	}
+
+	config := *c.clientContext
+	config.Server.RequireAuth = true
+	config.Server.AuthToken = authResp.Token
+	if err := c.contextStorage.Set(name, &config); err != nil {
+		c.ui.Output(
+			"Error setting the CLI context: %s",
+			clierrors.Humanize(err),
+			terminal.WithErrorStyle(),
+		)
+		return 1
+	}
+
+	c.ui.Output("Logged in as %q, token stored in the CLI context %q.",
+		authResp.User, name, terminal.WithSuccessStyle())
+	return 0
+}
+
+// loginCallback waits for the browser to be redirected to the callback
+// and returns the authorization code.
+func loginCallback(ctx context.Context, ln net.Listener, state string) (string, error) {
+	type result struct {
+		code string
+		err  error
+	}
+	resultCh := make(chan result, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oidc/callback", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		var res result
+		switch {
+		case query.Get("error") != "":
+			res.err = fmt.Errorf("login failed: %s %s",
+				query.Get("error"), query.Get("error_description"))
+
+		case query.Get("state") != state:
+			res.err = fmt.Errorf("login state doesn't match, please try again")
+
+		default:
+			res.code = query.Get("code")
+		}
+
+		if res.err != nil {
+			http.Error(w, res.err.Error(), http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(w, "Login complete, you can close this window and return to the CLI.")
+		}
+
+		select {
+		case resultCh <- res:
+		default:
+		}
+	})
+
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	select {
+	case res := <-resultCh:
+		return res.code, res.err
+
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// loginRandom returns a random value for the state or nonce of a login.
+func loginRandom() (string, error) {
+	var buf [16]byte
+	if _, err := io.ReadFull(rand.Reader, buf[:]); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf[:]), nil
+}
+
+func (c *LoginCommand) Flags() *flag.Sets {
+	return c.flagSet(flagSetConnection, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.IntVar(&flag.IntVar{
+			Name:    "callback-port",
+			Target:  &c.flagCallbackPort,
+			Default: 9711,
+			Usage: "Local port to listen on for the redirect from the identity provider. " +
+				"The server's OIDC configuration must allow the redirect URI " +
+				"\"http://localhost:PORT/oidc/callback\".",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:   "no-browser",
+			Target: &c.flagNoBrowser,
+			Usage:  "Don't open the browser. Visit the URL that is output instead.",
+		})
+	})
+}
+
+func (c *LoginCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *LoginCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *LoginCommand) Synopsis() string {
+	return "Log in to the server with your identity provider"
+}
+
+func (c *LoginCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint login [options]
+
+  Log in to the server with the OIDC identity provider it is configured
+  with, such as your company's single sign-on.
+
+  This opens a browser to log in to the identity provider. Once you log in,
+  the server gives you a token that expires after some time, usually a few
+  hours. The token is stored in the default CLI context. If there is no
+  default context, the token is output instead.
+
+  The server maps the claims of your identity, such as your groups, to the
+  role and projects the token can access. See the "-oidc-config" flag of
+  "waypoint server run".
+
+` + c.Flags().Help())
+}
+
+var errLoginLocal = strings.TrimSpace(`
+Logging in requires a Waypoint server. Configure the CLI to connect to a
+server with "waypoint context create" or the WAYPOINT_SERVER_ADDR environment
+variable.
+`)
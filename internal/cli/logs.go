@@ -2,7 +2,12 @@ package cli
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/golang/protobuf/ptypes"
@@ -17,6 +22,12 @@ import (
 
 type LogsCommand struct {
 	*baseCommand
+
+	flagSince      string
+	flagUntil      string
+	flagGrep       string
+	flagDeployment string
+	flagInstance   string
 }
 
 var logColors = map[pb.LogBatch_Entry_Source]*color.Color{
@@ -34,8 +45,24 @@ func (c *LogsCommand) Run(args []string) int {
 		return 1
 	}
 
-	err := c.DoApp(c.Ctx, func(ctx context.Context, app *clientpkg.App) error {
-		stream, err := app.Logs(ctx)
+	filter, err := c.filter(time.Now())
+	if err != nil {
+		c.ui.Output(err.Error(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	err = c.DoApp(c.Ctx, func(ctx context.Context, app *clientpkg.App) error {
+		if c.flagDeployment != "" {
+			id, err := c.deploymentId(ctx, app)
+			if err != nil {
+				app.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+				return ErrSentinel
+			}
+
+			filter.DeploymentId = id
+		}
+
+		stream, err := app.Logs(ctx, filter)
 		if err != nil {
 			if !clierrors.IsCanceled(err) {
 				app.UI.Output("Error reading logs: %s", err, terminal.WithErrorStyle())
@@ -45,6 +72,10 @@ func (c *LogsCommand) Run(args []string) int {
 
 		for {
 			batch, err := stream.Recv()
+			if err == io.EOF {
+				// The stream ends once we reach the -until time.
+				return nil
+			}
 			if err != nil {
 				if !clierrors.IsCanceled(err) {
 					app.UI.Output("Error reading logs: %s", err, terminal.WithErrorStyle())
@@ -99,12 +130,123 @@ func (c *LogsCommand) Run(args []string) int {
 	return 0
 }
 
+// filter returns the filter for the logs from the flags. Relative times
+// are relative to now.
+func (c *LogsCommand) filter(now time.Time) (*clientpkg.LogsFilter, error) {
+	filter := &clientpkg.LogsFilter{
+		InstanceId: c.flagInstance,
+		Pattern:    c.flagGrep,
+	}
+
+	var err error
+	if c.flagSince != "" {
+		if filter.Since, err = parseLogTime(c.flagSince, now); err != nil {
+			return nil, fmt.Errorf("Invalid value for -since: %s", err)
+		}
+	}
+	if c.flagUntil != "" {
+		if filter.Until, err = parseLogTime(c.flagUntil, now); err != nil {
+			return nil, fmt.Errorf("Invalid value for -until: %s", err)
+		}
+	}
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && !filter.Since.Before(filter.Until) {
+		return nil, fmt.Errorf("The -since time must be before the -until time.")
+	}
+
+	// We check the pattern here so that we can show a nicer error, but the
+	// server does the matching.
+	if c.flagGrep != "" {
+		if _, err := regexp.Compile(c.flagGrep); err != nil {
+			return nil, fmt.Errorf("Invalid value for -grep: %s", err)
+		}
+	}
+
+	return filter, nil
+}
+
+// deploymentId returns the ID of the deployment given by -deployment,
+// which is either an ID or a sequence number.
+func (c *LogsCommand) deploymentId(ctx context.Context, app *clientpkg.App) (string, error) {
+	ref := &pb.Ref_Operation{
+		Target: &pb.Ref_Operation_Id{Id: c.flagDeployment},
+	}
+	if i, err := strconv.ParseUint(c.flagDeployment, 10, 64); err == nil {
+		ref.Target = &pb.Ref_Operation_Sequence{
+			Sequence: &pb.Ref_OperationSeq{
+				Application: app.Ref(),
+				Number:      i,
+			},
+		}
+	}
+
+	deploy, err := c.project.Client().GetDeployment(ctx, &pb.GetDeploymentRequest{Ref: ref})
+	if err != nil {
+		return "", err
+	}
+
+	return deploy.Id, nil
+}
+
+// parseLogTime parses a time given to -since or -until. This is either an
+// RFC 3339 timestamp or a duration before now, such as "10m".
+func parseLogTime(v string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(v); err == nil {
+		if d < 0 {
+			return time.Time{}, fmt.Errorf("duration %q must not be negative", v)
+		}
+
+		return now.Add(-d), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf(
+			"%q must be a duration such as \"10m\" or a timestamp such as \"%s\"",
+			v, now.UTC().Truncate(time.Second).Format(time.RFC3339))
+	}
+
+	return t, nil
+}
+
 func (c *LogsCommand) Flags() *flag.Sets {
-	return c.flagSet(0, nil)
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:   "since",
+			Target: &c.flagSince,
+			Usage: "Only show logs at or after this time. This is a duration before " +
+				"now such as \"10m\" or an RFC 3339 timestamp.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "until",
+			Target: &c.flagUntil,
+			Usage: "Only show logs before this time, in the same format as -since. " +
+				"The command exits once this time has passed.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "grep",
+			Target: &c.flagGrep,
+			Usage:  "Only show log lines that match this regular expression.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "deployment",
+			Target: &c.flagDeployment,
+			Usage:  "Only show logs from this deployment, by ID or sequence number.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "instance",
+			Target: &c.flagInstance,
+			Usage:  "Only show logs from the instance with this ID.",
+		})
+	})
 }
 
 func (c *LogsCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictNothing
+	return c.predictAppTarget()
 }
 
 func (c *LogsCommand) AutocompleteFlags() complete.Flags {
@@ -128,5 +270,11 @@ Usage: waypoint logs [options]
   characters of the instance ID. This can be used to trace any logs back
   to a specific deployment or filter out certain log messages.
 
+  The logs can be filtered by time, deployment, instance, and a regular
+  expression using the flags below. The filters are applied by the server
+  so only matching lines are downloaded. When filtering by time or
+  pattern, the full log buffer held by the server is searched rather than
+  only the most recent lines.
+
 ` + c.Flags().Help())
 }
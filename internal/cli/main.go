@@ -26,6 +26,9 @@ const (
 	// EnvLogLevel is the env var to set with the log level.
 	EnvLogLevel = "WAYPOINT_LOG_LEVEL"
 
+	// EnvLogJSON is the env var to set to output logs as JSON.
+	EnvLogJSON = "WAYPOINT_LOG_JSON"
+
 	// EnvPlain is the env var that can be set to force plain output mode.
 	EnvPlain = "WAYPOINT_PLAIN"
 )
@@ -99,7 +102,7 @@ func Main(args []string) int {
 		panic(err)
 	}
 
-	return exitCode
+	return base.Exit(exitCode)
 }
 
 // commands returns the map of commands that can be used to initialize a CLI.
@@ -138,6 +141,24 @@ func Commands(
 			}, nil
 		},
 
+		"validate": func() (cli.Command, error) {
+			return &ValidateCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+
+		"diff": func() (cli.Command, error) {
+			return &DiffCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+
+		"fmt": func() (cli.Command, error) {
+			return &FmtCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+
 		"up": func() (cli.Command, error) {
 			return &UpCommand{
 				baseCommand: baseCommand,
@@ -155,6 +176,26 @@ func Commands(
 				baseCommand: baseCommand,
 			}, nil
 		},
+		"completion": func() (cli.Command, error) {
+			return &CompletionCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"status": func() (cli.Command, error) {
+			return &StatusCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"top": func() (cli.Command, error) {
+			return &TopCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"audit": func() (cli.Command, error) {
+			return &AuditCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
 		"config": func() (cli.Command, error) {
 			return &helpCommand{
 				SynopsisText: helpText["config"][0],
@@ -253,6 +294,25 @@ func Commands(
 			}, nil
 		},
 
+		"release list": func() (cli.Command, error) {
+			return &ReleaseListCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+
+		"project": func() (cli.Command, error) {
+			return &helpCommand{
+				SynopsisText: helpText["project"][0],
+				HelpText:     helpText["project"][1],
+			}, nil
+		},
+
+		"project list": func() (cli.Command, error) {
+			return &ProjectListCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+
 		"server": func() (cli.Command, error) {
 			return &helpCommand{
 				SynopsisText: helpText["server"][0],
@@ -284,16 +344,66 @@ func Commands(
 				baseCommand: baseCommand,
 			}, nil
 		},
+		"server snapshot-inspect": func() (cli.Command, error) {
+			return &SnapshotInspectCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"server snapshot-verify": func() (cli.Command, error) {
+			return &SnapshotVerifyCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"server snapshot-list": func() (cli.Command, error) {
+			return &SnapshotListCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"server snapshot-delete": func() (cli.Command, error) {
+			return &SnapshotDeleteCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"server snapshot restore-state": func() (cli.Command, error) {
+			return &SnapshotRestoreStateCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
 		"server restore": func() (cli.Command, error) {
 			return &SnapshotRestoreCommand{
 				baseCommand: baseCommand,
 			}, nil
 		},
+		"server gc": func() (cli.Command, error) {
+			return &ServerGCCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"server gc-policy": func() (cli.Command, error) {
+			return &ServerGCPolicyCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"server prune": func() (cli.Command, error) {
+			return &ServerPruneCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"server log-level": func() (cli.Command, error) {
+			return &ServerLogLevelCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
 		"plugin": func() (cli.Command, error) {
 			return &PluginCommand{
 				baseCommand: baseCommand,
 			}, nil
 		},
+		"plugin scaffold": func() (cli.Command, error) {
+			return &PluginScaffoldCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
 		"version": func() (cli.Command, error) {
 			return &VersionCommand{
 				baseCommand: baseCommand,
@@ -323,6 +433,33 @@ func Commands(
 				baseCommand: baseCommand,
 			}, nil
 		},
+		"org": func() (cli.Command, error) {
+			return &helpCommand{
+				SynopsisText: helpText["org"][0],
+				HelpText:     helpText["org"][1],
+			}, nil
+		},
+		"org set": func() (cli.Command, error) {
+			return &OrgSetCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"org list": func() (cli.Command, error) {
+			return &OrgListCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"org inspect": func() (cli.Command, error) {
+			return &OrgInspectCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"org delete": func() (cli.Command, error) {
+			return &OrgDeleteCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+
 		"token": func() (cli.Command, error) {
 			return &helpCommand{
 				SynopsisText: helpText["token"][0],
@@ -344,6 +481,43 @@ func Commands(
 				baseCommand: baseCommand,
 			}, nil
 		},
+		"token list": func() (cli.Command, error) {
+			return &TokenListCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"token revoke": func() (cli.Command, error) {
+			return &TokenRevokeCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"token rotate-key": func() (cli.Command, error) {
+			return &TokenRotateKeyCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+
+		"webhook": func() (cli.Command, error) {
+			return &helpCommand{
+				SynopsisText: helpText["webhook"][0],
+				HelpText:     helpText["webhook"][1],
+			}, nil
+		},
+		"webhook create": func() (cli.Command, error) {
+			return &WebhookCreateCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"webhook list": func() (cli.Command, error) {
+			return &WebhookListCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"webhook delete": func() (cli.Command, error) {
+			return &WebhookDeleteCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
 
 		"runner": func() (cli.Command, error) {
 			return &helpCommand{
@@ -398,6 +572,16 @@ func Commands(
 				baseCommand: baseCommand,
 			}, nil
 		},
+		"context export": func() (cli.Command, error) {
+			return &ContextExportCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"context import": func() (cli.Command, error) {
+			return &ContextImportCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
 
 		"ui": func() (cli.Command, error) {
 			return &UICommand{
@@ -405,6 +589,12 @@ func Commands(
 			}, nil
 		},
 
+		"login": func() (cli.Command, error) {
+			return &LoginCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+
 		"docs": func() (cli.Command, error) {
 			return &AppDocsCommand{
 				baseCommand: baseCommand,
@@ -444,6 +634,9 @@ func logger(args []string) ([]string, hclog.Logger, io.Writer, error) {
 		}
 	}
 
+	// JSON logs are for consumption by other tools so they are opt-in.
+	jsonFormat := os.Getenv(EnvLogJSON) != ""
+
 	// Process arguments looking for `-v` flags to control the log level.
 	// This overrides whatever the env var set.
 	var outArgs []string
@@ -466,24 +659,35 @@ func logger(args []string) ([]string, hclog.Logger, io.Writer, error) {
 			if level == hclog.NoLevel || level > hclog.Trace {
 				level = hclog.Trace
 			}
+		case "-log-json":
+			jsonFormat = true
 		default:
 			outArgs = append(outArgs, arg)
 		}
 	}
 
+	// Asking for JSON logs without a level enables logging at the
+	// default info level so that the flag works on its own.
+	if jsonFormat && level == hclog.NoLevel {
+		level = hclog.Info
+	}
+
 	// Default output is nowhere unless we enable logging.
 	var output io.Writer = ioutil.Discard
 	color := hclog.ColorOff
 	if level != hclog.NoLevel {
 		output = os.Stderr
-		color = hclog.AutoColor
+		if !jsonFormat {
+			color = hclog.AutoColor
+		}
 	}
 
 	logger := hclog.New(&hclog.LoggerOptions{
-		Name:   app,
-		Level:  level,
-		Color:  color,
-		Output: output,
+		Name:       app,
+		Level:      level,
+		Color:      color,
+		Output:     output,
+		JSONFormat: jsonFormat,
 	})
 
 	return outArgs, logger, output, nil
@@ -536,7 +740,7 @@ func GroupedHelpFunc(f cli.HelpFunc) cli.HelpFunc {
 			glint.Text(" "),
 			glint.Text(cliName),
 			glint.Text(" "),
-			glint.Text("[-version] [-help] [-autocomplete-(un)install] <command> [args]"),
+			glint.Text("[-version] [-help] [-autocomplete-(un)install] [-v|-vv|-vvv] [-log-json] <command> [args]"),
 		).Row())
 		d.Append(glint.Text(""))
 
@@ -639,6 +843,12 @@ Manage configurations for accessing Waypoint servers.
 A context contains all the configuration to connect to a single Waypoint
 server. The Waypoint CLI can have multiple contexts to make it easy to switch
 between different Waypoint servers.
+
+Instead of a stored context, the connection can be configured entirely with
+environment variables. Set WAYPOINT_CONTEXT_CONFIG to the output of
+"waypoint context export", or set WAYPOINT_SERVER_ADDR, WAYPOINT_SERVER_TLS,
+WAYPOINT_SERVER_TLS_SKIP_VERIFY, and WAYPOINT_SERVER_TOKEN. This is useful
+in CI and containers where the CLI config directory may not be writable.
 `,
 	},
 
@@ -666,6 +876,16 @@ For more information see: https://waypointproject.io/docs/url
 `,
 	},
 
+	"project": {
+		"Project management",
+		`
+Project management.
+
+Projects group the applications configured in a waypoint.hcl file. Projects
+are registered with the server by "waypoint init".
+`,
+	},
+
 	"runner": {
 		"Runner management",
 		`
@@ -690,6 +910,17 @@ This command contains further subcommands to work with servers.
 `,
 	},
 
+	"org": {
+		"Manage organizations of projects",
+		`
+Manage organizations of projects.
+
+Organizations let several teams share one Waypoint server. Each organization
+has its own projects, tokens, and quotas, and tokens of an organization can
+only access its projects.
+`,
+	},
+
 	"token": {
 		"Authenticate and invite collaborators",
 		`
@@ -697,6 +928,17 @@ Authenticate and invite collaborators.
 
 Tokens are the primary form of authentication to Waypoint. Everyone who
 accesses a Waypoint server requires a token.
+`,
+	},
+
+	"webhook": {
+		"Send events to external systems",
+		`
+Send events to external systems.
+
+Webhooks are URLs that the server POSTs a JSON payload to when builds,
+deployments, and releases of a project complete, so that other systems can
+react without polling the server.
 `,
 	},
 }
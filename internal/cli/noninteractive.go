@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+)
+
+// nonInteractive returns true if the CLI must never prompt or change its
+// behavior based on whether it is run in a terminal, set via
+// -non-interactive or WAYPOINT_NON_INTERACTIVE. This is meant for CI, so
+// that commands behave the same as they do when run locally and fail
+// rather than wait for input that will never come.
+func (c *baseCommand) nonInteractive() bool {
+	return c.flagNonInteractive
+}
+
+// strictUI is the UI used with -non-interactive. It is never interactive
+// and fails any prompt with an error that explains why, so that a command
+// that needs input fails fast rather than hang or guess.
+type strictUI struct {
+	terminal.UI
+}
+
+func (ui *strictUI) Interactive() bool {
+	return false
+}
+
+func (ui *strictUI) Input(input *terminal.Input) (string, error) {
+	prompt := strings.TrimSpace(input.Prompt)
+	return "", fmt.Errorf(
+		"Input is required for %q, but prompts are disabled with -non-interactive. "+
+			"Use the flags of the command to provide the input instead.", prompt)
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type OrgDeleteCommand struct {
+	*baseCommand
+}
+
+func (c *OrgDeleteCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	if len(c.args) != 1 {
+		c.ui.Output("A single organization name is required.\n\n%s", c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	_, err := c.project.Client().DeleteOrganization(c.Ctx, &pb.DeleteOrganizationRequest{
+		Name: c.args[0],
+	})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	c.ui.Output("Organization deleted.", terminal.WithSuccessStyle())
+	return 0
+}
+
+func (c *OrgDeleteCommand) Flags() *flag.Sets {
+	return c.flagSet(0, nil)
+}
+
+func (c *OrgDeleteCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OrgDeleteCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *OrgDeleteCommand) Synopsis() string {
+	return "Delete an organization"
+}
+
+func (c *OrgDeleteCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint org delete [options] NAME
+
+  Delete an organization. Its projects are kept, but the tokens limited to
+  it can no longer access anything.
+
+` + c.Flags().Help())
+}
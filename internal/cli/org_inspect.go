@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type OrgInspectCommand struct {
+	*baseCommand
+}
+
+func (c *OrgInspectCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	if len(c.args) != 1 {
+		c.ui.Output("A single organization name is required.\n\n%s", c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	resp, err := c.project.Client().GetOrganization(c.Ctx, &pb.GetOrganizationRequest{
+		Name: c.args[0],
+	})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+	org := resp.Organization
+
+	if c.jsonOutput() {
+		err := c.writeJSON(map[string]interface{}{
+			"name":              org.Name,
+			"projects":          orgProjectNames(org),
+			"max_jobs":          org.Quota.GetMaxJobs(),
+			"max_storage_bytes": org.Quota.GetMaxStorageBytes(),
+			"jobs":              resp.Usage.GetJobs(),
+			"storage_bytes":     resp.Usage.GetStorageBytes(),
+		})
+		if err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
+	jobs := fmt.Sprintf("%d (unlimited)", resp.Usage.GetJobs())
+	if v := org.Quota.GetMaxJobs(); v > 0 {
+		jobs = fmt.Sprintf("%d of %d", resp.Usage.GetJobs(), v)
+	}
+
+	storage := humanize.Bytes(resp.Usage.GetStorageBytes()) + " (unlimited)"
+	if v := org.Quota.GetMaxStorageBytes(); v > 0 {
+		storage = humanize.Bytes(resp.Usage.GetStorageBytes()) + " of " + humanize.Bytes(v)
+	}
+
+	c.ui.NamedValues([]terminal.NamedValue{
+		{Name: "Name", Value: org.Name},
+		{Name: "Projects", Value: strings.Join(orgProjectNames(org), ", ")},
+		{Name: "Jobs", Value: jobs},
+		{Name: "Storage", Value: storage},
+	}, terminal.WithInfoStyle())
+	return 0
+}
+
+func (c *OrgInspectCommand) Flags() *flag.Sets {
+	return c.flagSet(flagSetOutput, nil)
+}
+
+func (c *OrgInspectCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OrgInspectCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *OrgInspectCommand) Synopsis() string {
+	return "Show an organization and its use of its quota"
+}
+
+func (c *OrgInspectCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint org inspect [options] NAME
+
+  Show an organization and its use of its quota. Jobs are the jobs that are
+  queued or running, and storage is used by builds, artifacts, deployments,
+  and releases.
+
+` + c.Flags().Help())
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type OrgListCommand struct {
+	*baseCommand
+}
+
+func (c *OrgListCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	resp, err := c.project.Client().ListOrganizations(c.Ctx, &empty.Empty{})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if c.jsonOutput() {
+		output := []map[string]interface{}{}
+		for _, org := range resp.Organizations {
+			output = append(output, map[string]interface{}{
+				"name":              org.Name,
+				"projects":          orgProjectNames(org),
+				"max_jobs":          org.Quota.GetMaxJobs(),
+				"max_storage_bytes": org.Quota.GetMaxStorageBytes(),
+			})
+		}
+
+		if err := c.writeJSON(output); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
+	if len(resp.Organizations) == 0 {
+		c.ui.Output("No organizations found.")
+		return 0
+	}
+
+	table := terminal.NewTable("Name", "Projects", "Max Jobs", "Max Storage")
+	for _, org := range resp.Organizations {
+		maxJobs := "unlimited"
+		if v := org.Quota.GetMaxJobs(); v > 0 {
+			maxJobs = strconv.FormatUint(uint64(v), 10)
+		}
+
+		maxStorage := "unlimited"
+		if v := org.Quota.GetMaxStorageBytes(); v > 0 {
+			maxStorage = humanize.Bytes(v)
+		}
+
+		table.Rich([]string{
+			org.Name,
+			strings.Join(orgProjectNames(org), ", "),
+			maxJobs,
+			maxStorage,
+		}, nil)
+	}
+
+	c.ui.Table(table)
+	return 0
+}
+
+// orgProjectNames returns the names of the projects of an organization.
+func orgProjectNames(org *pb.Organization) []string {
+	result := []string{}
+	for _, p := range org.Projects {
+		result = append(result, p.Project)
+	}
+
+	return result
+}
+
+func (c *OrgListCommand) Flags() *flag.Sets {
+	return c.flagSet(flagSetOutput, nil)
+}
+
+func (c *OrgListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OrgListCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *OrgListCommand) Synopsis() string {
+	return "List the organizations of the server"
+}
+
+func (c *OrgListCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint org list [options]
+
+  List the organizations of the server, their projects, and their quotas.
+
+` + c.Flags().Help())
+}
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"github.com/dustin/go-humanize"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/posener/complete"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type OrgSetCommand struct {
+	*baseCommand
+
+	flagProjects   []string
+	flagMaxJobs    int
+	flagMaxStorage string
+}
+
+func (c *OrgSetCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	if len(c.args) != 1 {
+		c.ui.Output("A single organization name is required.\n\n%s", c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	// Settings that weren't given keep their current values.
+	client := c.project.Client()
+	org := &pb.Organization{Name: c.args[0]}
+	resp, err := client.GetOrganization(c.Ctx, &pb.GetOrganizationRequest{Name: c.args[0]})
+	if err == nil {
+		org = resp.Organization
+	} else if status.Code(err) != codes.NotFound {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+	if org.Quota == nil {
+		org.Quota = &pb.OrganizationQuota{}
+	}
+
+	if len(c.flagProjects) > 0 {
+		org.Projects = nil
+		for _, p := range c.flagProjects {
+			org.Projects = append(org.Projects, &pb.Ref_Project{Project: p})
+		}
+	}
+	if c.flagMaxJobs >= 0 {
+		org.Quota.MaxJobs = uint32(c.flagMaxJobs)
+	}
+	if c.flagMaxStorage != "" {
+		max, err := humanize.ParseBytes(c.flagMaxStorage)
+		if err != nil {
+			c.ui.Output("Invalid -max-storage value: %s", err, terminal.WithErrorStyle())
+			return 1
+		}
+
+		org.Quota.MaxStorageBytes = max
+	}
+
+	if _, err := client.UpsertOrganization(c.Ctx, &pb.UpsertOrganizationRequest{
+		Organization: org,
+	}); err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	c.ui.Output("Organization %q saved.", org.Name, terminal.WithSuccessStyle())
+	return 0
+}
+
+func (c *OrgSetCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringSliceVar(&flag.StringSliceVar{
+			Name:   "project",
+			Target: &c.flagProjects,
+			Usage: "Project of the organization. This can be specified multiple times " +
+				"and replaces the projects of the organization.",
+		})
+
+		f.IntVar(&flag.IntVar{
+			Name:    "max-jobs",
+			Target:  &c.flagMaxJobs,
+			Default: -1,
+			Usage:   "Most jobs that can be queued or running at once. Set to 0 for no limit.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "max-storage",
+			Target: &c.flagMaxStorage,
+			Usage: "Most storage that builds, artifacts, deployments, and releases can " +
+				"use, such as \"500MB\". Set to 0 for no limit.",
+		})
+	})
+}
+
+func (c *OrgSetCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OrgSetCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *OrgSetCommand) Synopsis() string {
+	return "Create or update an organization"
+}
+
+func (c *OrgSetCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint org set [options] NAME
+
+  Create or update an organization. Settings that aren't given keep their
+  current values.
+
+  Organizations group projects so that one server can be shared by several
+  teams. Tokens created with "waypoint token new -scope-org" can only
+  access the projects of their organization, and projects they create are
+  added to it. A project can be in at most one organization.
+
+  When the organization is at its quota of jobs or storage, new jobs for
+  its projects are rejected until jobs complete or old records are deleted,
+  such as by "waypoint server gc".
+
+` + c.Flags().Help())
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"encoding/json"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+)
+
+// The values of the -output flag.
+const (
+	outputHuman = "human"
+	outputJSON  = "json"
+)
+
+// jsonOutput returns true if the output should be JSON, set via
+// -output=json. Only commands with flagSetOutput have the flag. They must
+// write a single JSON value with writeJSON and nothing else so that the
+// output can be parsed. Errors are still written as text since the exit
+// code indicates the failure.
+func (c *baseCommand) jsonOutput() bool {
+	return c.flagOutput == outputJSON
+}
+
+// writeJSON writes v as indented JSON to the UI.
+func (c *baseCommand) writeJSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	c.ui.Output("%s", string(data))
+	return nil
+}
+
+// outputError logs err and outputs it to the UI as an error prefixed with
+// msg. The error is written to stderr so that it doesn't corrupt data that
+// the command writes to stdout, such as a snapshot.
+func (c *baseCommand) outputError(msg string, err error) {
+	c.Log.Error(msg, "err", err)
+	c.setExitError(exitCodeForError(err), err)
+
+	opts := []interface{}{clierrors.Humanize(err), terminal.WithErrorStyle()}
+	if _, stderr, werr := c.ui.OutputWriters(); werr == nil && stderr != nil {
+		opts = append(opts, terminal.WithWriter(stderr))
+	}
+
+	c.ui.Output(msg+": %s", opts...)
+}
@@ -0,0 +1,261 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/cli/datagen"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+)
+
+// pluginNameRegexp is the format of plugin names. The name is used in the
+// binary name, as the Go package name, and as the protobuf package name, so
+// it is restricted to what is valid for all of them.
+var pluginNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// pluginScaffoldTypes are the component types that can be scaffolded,
+// mapped to the name of their component struct and their waypoint.hcl
+// stanza.
+var pluginScaffoldTypes = map[string]struct {
+	Component string
+	TypeName  string
+	Stanza    string
+}{
+	"builder":        {"Builder", "builder", "build"},
+	"platform":       {"Platform", "platform", "deploy"},
+	"releasemanager": {"ReleaseManager", "release manager", "release"},
+}
+
+type PluginScaffoldCommand struct {
+	*baseCommand
+
+	flagType   string
+	flagModule string
+	flagDir    string
+}
+
+func (c *PluginScaffoldCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+		WithClient(false),
+	); err != nil {
+		return 1
+	}
+	args = c.args
+
+	if len(args) != 1 {
+		c.ui.Output(c.Flags().Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	name := args[0]
+	if !pluginNameRegexp.MatchString(name) {
+		c.ui.Output(
+			"Plugin name %q is invalid. Names must start with a lowercase letter "+
+				"and contain only lowercase letters, numbers, and dashes.",
+			name, terminal.WithErrorStyle())
+		return 1
+	}
+
+	data := c.scaffoldData(name)
+	dir := c.flagDir
+	if dir == "" {
+		dir = "waypoint-plugin-" + name
+	}
+
+	// Refuse to overwrite anything. The scaffold is a starting point and
+	// not something that is regenerated.
+	if entries, err := ioutil.ReadDir(dir); err == nil && len(entries) > 0 {
+		c.ui.Output("Directory %q already exists and is not empty.", dir, terminal.WithErrorStyle())
+		return 1
+	}
+
+	files, err := c.render(data)
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	for path, contents := range files {
+		path = filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		if err := ioutil.WriteFile(path, contents, 0644); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+	}
+
+	c.ui.Output("Plugin %q created in %s", name, dir, terminal.WithSuccessStyle())
+	c.ui.Output("")
+	c.ui.Output(strings.TrimSpace(fmt.Sprintf(`
+The plugin provides a %[1]s that is ready to build and test. To get started:
+
+  cd %[2]s
+  go mod tidy
+  make
+
+Building the plugin requires protoc and protoc-gen-go to generate the code
+for plugin.proto. See README.md in the plugin directory for more details.
+`, data.TypeName, dir)))
+
+	return 0
+}
+
+// pluginScaffoldData is the data that the scaffold templates are rendered
+// with.
+type pluginScaffoldData struct {
+	// Name is the name of the plugin as used in waypoint.hcl.
+	Name string
+
+	// Package is the name of the Go and protobuf package of the plugin and
+	// Module is the Go module path.
+	Package string
+	Module  string
+
+	// Type is the component type, such as "builder", TypeName is its
+	// human-readable name, Component is the name of its struct, and Stanza
+	// is the waypoint.hcl stanza that the component is used in.
+	Type      string
+	TypeName  string
+	Component string
+	Stanza    string
+}
+
+func (c *PluginScaffoldCommand) scaffoldData(name string) *pluginScaffoldData {
+	typ := pluginScaffoldTypes[c.flagType]
+
+	module := c.flagModule
+	if module == "" {
+		module = "github.com/example/waypoint-plugin-" + name
+	}
+
+	return &pluginScaffoldData{
+		Name:      name,
+		Package:   strings.Replace(name, "-", "", -1),
+		Module:    module,
+		Type:      c.flagType,
+		TypeName:  typ.TypeName,
+		Component: typ.Component,
+		Stanza:    typ.Stanza,
+	}
+}
+
+// render renders the scaffold templates and returns the contents of each
+// file keyed by its slash separated path within the plugin directory.
+func (c *PluginScaffoldCommand) render(data *pluginScaffoldData) (map[string][]byte, error) {
+	// The templates mapped to the files they're rendered to.
+	files := map[string]string{
+		"main.go.tpl":        "main.go",
+		"Makefile.tpl":       "Makefile",
+		"go.mod.tpl":         "go.mod",
+		"README.md.tpl":      "README.md",
+		"gitignore.tpl":      ".gitignore",
+		"options.go.tpl":     data.Package + "/main.go",
+		"plugin.proto.tpl":   data.Package + "/plugin.proto",
+		"mapper.go.tpl":      data.Package + "/mapper.go",
+		"plugin_test.go.tpl": data.Package + "/plugin_test.go",
+
+		data.Type + ".go.tpl":      data.Package + "/" + data.Type + ".go",
+		data.Type + "_test.go.tpl": data.Package + "/" + data.Type + "_test.go",
+	}
+
+	result := map[string][]byte{}
+	for src, dst := range files {
+		raw, err := datagen.Asset("plugin/" + src)
+		if err != nil {
+			// Should never happen because it is embedded.
+			panic(err)
+		}
+
+		tpl, err := template.New(src).Parse(string(raw))
+		if err != nil {
+			return nil, err
+		}
+
+		var buf bytes.Buffer
+		if err := tpl.Execute(&buf, data); err != nil {
+			return nil, err
+		}
+
+		result[dst] = buf.Bytes()
+	}
+
+	return result, nil
+}
+
+func (c *PluginScaffoldCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.EnumSingleVar(&flag.EnumSingleVar{
+			Name:    "type",
+			Target:  &c.flagType,
+			Values:  []string{"builder", "platform", "releasemanager"},
+			Default: "platform",
+			Usage:   "The type of component the plugin provides.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:    "module",
+			Target:  &c.flagModule,
+			Default: "",
+			Usage: "The Go module path of the plugin. Defaults to " +
+				"github.com/example/waypoint-plugin-NAME.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:    "dir",
+			Target:  &c.flagDir,
+			Default: "",
+			Usage: "The directory to create the plugin in. Defaults to " +
+				"waypoint-plugin-NAME in the current directory.",
+		})
+	})
+}
+
+func (c *PluginScaffoldCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *PluginScaffoldCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *PluginScaffoldCommand) Synopsis() string {
+	return "Generate the skeleton of a new plugin"
+}
+
+func (c *PluginScaffoldCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint plugin scaffold [options] NAME
+
+  Generate the skeleton of a new plugin.
+
+  The generated plugin provides a builder, platform, or release manager that
+  is served with the Waypoint plugin SDK. It includes a Makefile, the protobuf
+  messages and mappers the plugin uses, and tests that launch the plugin and
+  talk to it over gRPC just like Waypoint does. The plugin builds and its
+  tests pass as generated, so you can start by filling in the TODOs.
+
+  NAME is the name the plugin is used with in waypoint.hcl. The plugin is
+  created in the directory "waypoint-plugin-NAME" unless -dir is set, which
+  must not exist or be empty.
+
+` + c.Flags().Help())
+}
@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/adrg/xdg"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/posener/complete"
+	"google.golang.org/grpc"
+
+	"github.com/hashicorp/waypoint/internal/clicache"
+	"github.com/hashicorp/waypoint/internal/clicontext"
+	configpkg "github.com/hashicorp/waypoint/internal/config"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	"github.com/hashicorp/waypoint/internal/serverclient"
+)
+
+// predictTimeout is how long we wait for the server when completing. The
+// shell blocks while we complete, so if the server is slow or unreachable
+// we'd rather show nothing than hang.
+const predictTimeout = 2 * time.Second
+
+// predictCacheTTL is how long completions use cached responses before
+// asking the server again.
+const predictCacheTTL = time.Minute
+
+// predictAppTarget predicts "project/app" targets for commands that accept
+// an app target as their first argument.
+func (c *baseCommand) predictAppTarget() complete.Predictor {
+	return complete.PredictFunc(func(args complete.Args) []string {
+		// Only the first argument can be a target.
+		if len(args.Completed) > 0 {
+			return nil
+		}
+
+		// Only ask the server for the apps once a project is typed since
+		// listing every app of every project can be slow.
+		idx := strings.Index(args.Last, "/")
+		if idx < 0 {
+			var result []string
+			for _, p := range c.predictProjectNames() {
+				result = append(result, p+"/")
+			}
+
+			return result
+		}
+
+		project := args.Last[:idx]
+		var result []string
+		for _, app := range c.predictAppNames(project) {
+			result = append(result, project+"/"+app)
+		}
+
+		return result
+	})
+}
+
+// predictProject predicts the names of the projects on the server.
+func (c *baseCommand) predictProject() complete.Predictor {
+	return complete.PredictFunc(func(complete.Args) []string {
+		return c.predictProjectNames()
+	})
+}
+
+// predictApp predicts the names of the apps in the project in the
+// current directory, as used by the -app flag.
+func (c *baseCommand) predictApp() complete.Predictor {
+	return complete.PredictFunc(func(complete.Args) []string {
+		path, err := configpkg.FindPath("", "", true)
+		if err != nil || path == "" {
+			return nil
+		}
+
+		cfg, err := c.initConfigLoad(path)
+		if err != nil {
+			return nil
+		}
+
+		// The server may know of apps that aren't in the local config
+		// yet, but we use the local config if the server is unavailable.
+		if apps := c.predictAppNames(cfg.Project); len(apps) > 0 {
+			return apps
+		}
+
+		return cfg.Apps()
+	})
+}
+
+func (c *baseCommand) predictProjectNames() []string {
+	client, closer := c.predictClient()
+	if client == nil {
+		return nil
+	}
+	defer closer()
+
+	ctx, cancel := context.WithTimeout(c.Ctx, predictTimeout)
+	defer cancel()
+
+	resp, err := client.ListProjects(ctx, &empty.Empty{})
+	if err != nil {
+		return nil
+	}
+
+	var result []string
+	for _, p := range resp.Projects {
+		result = append(result, p.Project)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+func (c *baseCommand) predictAppNames(project string) []string {
+	client, closer := c.predictClient()
+	if client == nil {
+		return nil
+	}
+	defer closer()
+
+	ctx, cancel := context.WithTimeout(c.Ctx, predictTimeout)
+	defer cancel()
+
+	resp, err := client.GetProject(ctx, &pb.GetProjectRequest{
+		Project: &pb.Ref_Project{Project: project},
+	})
+	if err != nil {
+		return nil
+	}
+
+	var result []string
+	for _, app := range resp.Project.Applications {
+		result = append(result, app.Name)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// predictClient returns a client to the server for completions. Completion
+// runs without Init so we connect using the default context and the
+// environment only. This returns a nil client if no server is configured
+// since completion errors can't be shown to the user.
+//
+// Responses are cached for predictCacheTTL since the shell completes on
+// every tab press. We only connect if a response isn't cached.
+func (c *baseCommand) predictClient() (pb.WaypointClient, func()) {
+	homeConfigPath, err := xdg.ConfigFile("waypoint/.ignore")
+	if err != nil {
+		return nil, nil
+	}
+
+	contextDir := filepath.Join(filepath.Dir(homeConfigPath), "context")
+	st, err := clicontext.NewStorage(clicontext.WithDir(contextDir))
+	if err != nil {
+		return nil, nil
+	}
+
+	opts := []serverclient.ConnectOption{
+		serverclient.FromContext(st, ""),
+		serverclient.FromEnv(),
+		serverclient.Timeout(predictTimeout),
+	}
+	cfg, err := serverclient.ContextConfig(opts...)
+	if err != nil || cfg.Server.Address == "" {
+		return nil, nil
+	}
+
+	var conn *grpc.ClientConn
+	cache := clicache.New(responseCacheDir(contextDir), cfg.Server.Address, c.Log)
+	cc := cache.Conn(predictCacheTTL, func() (grpc.ClientConnInterface, error) {
+		var err error
+		conn, err = serverclient.Connect(c.Ctx, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		return conn, nil
+	})
+
+	return pb.NewWaypointClient(cc), func() {
+		if conn != nil {
+			conn.Close()
+		}
+	}
+}
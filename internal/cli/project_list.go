@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type ProjectListCommand struct {
+	*baseCommand
+}
+
+func (c *ProjectListCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	client := c.project.Client()
+	resp, err := client.ListProjects(c.Ctx, &empty.Empty{})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	// Get each project for its applications.
+	var projects []*pb.Project
+	for _, ref := range resp.Projects {
+		resp, err := client.GetProject(c.Ctx, &pb.GetProjectRequest{Project: ref})
+		if err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		projects = append(projects, resp.Project)
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		return projects[i].Name < projects[j].Name
+	})
+
+	if c.jsonOutput() {
+		output := []map[string]interface{}{}
+		for _, p := range projects {
+			output = append(output, map[string]interface{}{
+				"name":         p.Name,
+				"applications": projectAppNames(p),
+				"remote":       p.RemoteEnabled,
+			})
+		}
+
+		if err := c.writeJSON(output); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
+	if len(projects) == 0 {
+		c.ui.Output("No projects found.")
+		return 0
+	}
+
+	table := terminal.NewTable("Name", "Applications")
+	for _, p := range projects {
+		table.Rich([]string{
+			p.Name,
+			strings.Join(projectAppNames(p), ", "),
+		}, nil)
+	}
+
+	c.ui.Table(table)
+	return 0
+}
+
+// projectAppNames returns the names of the applications of a project.
+func projectAppNames(p *pb.Project) []string {
+	result := []string{}
+	for _, app := range p.Applications {
+		result = append(result, app.Name)
+	}
+
+	return result
+}
+
+func (c *ProjectListCommand) Flags() *flag.Sets {
+	return c.flagSet(flagSetCache|flagSetOutput, nil)
+}
+
+func (c *ProjectListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ProjectListCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ProjectListCommand) Synopsis() string {
+	return "List the projects of the server."
+}
+
+func (c *ProjectListCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint project list [options]
+
+  List the projects registered with the server and their applications.
+
+` + c.Flags().Help())
+}
@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"context"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	clientpkg "github.com/hashicorp/waypoint/internal/client"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type ReleaseListCommand struct {
+	*baseCommand
+
+	flagWorkspaceAll bool
+	flagId           idFormat
+	filterFlags      filterFlags
+}
+
+func (c *ReleaseListCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithSingleApp(),
+	); err != nil {
+		return 1
+	}
+
+	// Get our API client
+	client := c.project.Client()
+
+	err := c.DoApp(c.Ctx, func(ctx context.Context, app *clientpkg.App) error {
+		var wsRef *pb.Ref_Workspace
+		if !c.flagWorkspaceAll {
+			wsRef = c.project.WorkspaceRef()
+		}
+
+		phyState, err := c.filterFlags.physState()
+		if err != nil {
+			return err
+		}
+
+		// List releases
+		resp, err := client.ListReleases(c.Ctx, &pb.ListReleasesRequest{
+			Application:   app.Ref(),
+			Workspace:     wsRef,
+			PhysicalState: phyState,
+			Status:        c.filterFlags.statusFilters(),
+			Order:         c.filterFlags.orderOp(),
+			Labels:        c.filterFlags.labels(),
+		})
+		if err != nil {
+			c.project.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return ErrSentinel
+		}
+
+		if c.jsonOutput() {
+			return c.displayJson(resp.Releases)
+		}
+
+		const bullet = "●"
+
+		table := terminal.NewTable("", "ID", "Platform", "Deployment", "URL", "Started", "Completed")
+		for _, r := range resp.Releases {
+			// Determine our bullet
+			status := ""
+			statusColor := ""
+			switch r.Status.State {
+			case pb.Status_RUNNING:
+				status = bullet
+				statusColor = terminal.Yellow
+
+			case pb.Status_SUCCESS:
+				if r.State == pb.Operation_DESTROYED {
+					status = bullet
+				} else {
+					status = "✔"
+					statusColor = terminal.Green
+				}
+
+			case pb.Status_ERROR:
+				status = "✖"
+				statusColor = terminal.Red
+			}
+
+			// Parse our times
+			var startTime, completeTime string
+			if t, err := ptypes.Timestamp(r.Status.StartTime); err == nil {
+				startTime = humanize.Time(t)
+			}
+			if t, err := ptypes.Timestamp(r.Status.CompleteTime); err == nil {
+				completeTime = humanize.Time(t)
+			}
+
+			table.Rich([]string{
+				status,
+				c.flagId.FormatId(r.Sequence, r.Id),
+				r.Component.Name,
+				r.DeploymentId,
+				r.Url,
+				startTime,
+				completeTime,
+			}, []string{
+				statusColor,
+			})
+		}
+
+		c.ui.Table(table)
+
+		return nil
+	})
+	if err != nil {
+		return 1
+	}
+
+	return 0
+}
+
+func (c *ReleaseListCommand) displayJson(releases []*pb.Release) error {
+	output := []map[string]interface{}{}
+
+	for _, r := range releases {
+		i := map[string]interface{}{}
+
+		i["id"] = r.Id
+		i["sequence"] = r.Sequence
+		i["application"] = r.Application
+		i["labels"] = r.Labels
+		i["component"] = r.Component.Name
+		i["physical_state"] = r.State.String()
+		i["status"] = c.statusJson(r.Status)
+		i["workspace"] = r.Workspace.Workspace
+		i["deployment_id"] = r.DeploymentId
+		i["url"] = r.Url
+
+		output = append(output, i)
+	}
+
+	return c.writeJSON(output)
+}
+
+func (c *ReleaseListCommand) statusJson(status *pb.Status) interface{} {
+	i := map[string]interface{}{}
+
+	i["state"] = status.State.String()
+	i["complete_time"] = status.CompleteTime.AsTime().Format(time.RFC3339Nano)
+	i["start_time"] = status.StartTime.AsTime().Format(time.RFC3339Nano)
+
+	return i
+}
+
+func (c *ReleaseListCommand) Flags() *flag.Sets {
+	return c.flagSet(flagSetCache|flagSetOutput, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.BoolVar(&flag.BoolVar{
+			Name:   "workspace-all",
+			Target: &c.flagWorkspaceAll,
+			Usage:  "List releases in all workspaces for this project and application.",
+		})
+
+		initIdFormat(f, &c.flagId)
+		initFilterFlags(set, &c.filterFlags, fillterOptionAll)
+	})
+}
+
+func (c *ReleaseListCommand) AutocompleteArgs() complete.Predictor {
+	return c.predictAppTarget()
+}
+
+func (c *ReleaseListCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ReleaseListCommand) Synopsis() string {
+	return "List releases."
+}
+
+func (c *ReleaseListCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint release list [options]
+
+  Lists the releases of an application, including the deployment each one
+  released and its URL.
+
+` + c.Flags().Help())
+}
@@ -173,7 +173,7 @@ func (c *ReleaseCreateCommand) Flags() *flag.Sets {
 }
 
 func (c *ReleaseCreateCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictNothing
+	return c.predictAppTarget()
 }
 
 func (c *ReleaseCreateCommand) AutocompleteFlags() complete.Flags {
@@ -1,6 +1,8 @@
 package cli
 
 import (
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
 	"github.com/hashicorp/waypoint/internal/clierrors"
 	"github.com/hashicorp/waypoint/internal/pkg/flag"
@@ -12,6 +14,9 @@ type ServerConfigSetCommand struct {
 	*baseCommand
 
 	flagAdvertiseAddr pb.ServerConfig_AdvertiseAddr
+	flagRuntime       pb.ServerRuntimeConfig
+
+	flagWebhookAttempts uint
 }
 
 func (c *ServerConfigSetCommand) Run(args []string) int {
@@ -24,13 +29,32 @@ func (c *ServerConfigSetCommand) Run(args []string) int {
 		return 1
 	}
 
-	cfg := &pb.ServerConfig{
-		AdvertiseAddrs: []*pb.ServerConfig_AdvertiseAddr{
+	client := c.project.Client()
+	cfg := &pb.ServerConfig{}
+
+	// Runtime settings that aren't given keep their current values. The
+	// advertise address is only left out if runtime settings are given.
+	c.flagRuntime.WebhookAttempts = uint32(c.flagWebhookAttempts)
+	runtimeSet := !proto.Equal(&c.flagRuntime, &pb.ServerRuntimeConfig{})
+	if runtimeSet {
+		resp, err := client.GetServerConfig(c.Ctx, &empty.Empty{})
+		if err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		cfg.Runtime = &pb.ServerRuntimeConfig{}
+		if rc := resp.Config.GetRuntime(); rc != nil {
+			proto.Merge(cfg.Runtime, rc)
+		}
+		proto.Merge(cfg.Runtime, &c.flagRuntime)
+	}
+	if !runtimeSet || c.flagAdvertiseAddr.Addr != "" {
+		cfg.AdvertiseAddrs = []*pb.ServerConfig_AdvertiseAddr{
 			&c.flagAdvertiseAddr,
-		},
+		}
 	}
 
-	client := c.project.Client()
 	_, err := client.SetServerConfig(c.Ctx, &pb.SetServerConfigRequest{
 		Config: cfg,
 	})
@@ -66,6 +90,47 @@ func (c *ServerConfigSetCommand) Flags() *flag.Sets {
 			Usage:   "Do not verify the TLS certificate presented by the server.",
 			Default: false,
 		})
+
+		f = set.NewSet("Runtime Options")
+		f.StringVar(&flag.StringVar{
+			Name:   "log-level",
+			Target: &c.flagRuntime.LogLevel,
+			Usage:  "Level of the server logs: trace, debug, info, warn, or error.",
+		})
+		f.StringVar(&flag.StringVar{
+			Name:   "gc-interval",
+			Target: &c.flagRuntime.GcInterval,
+			Usage: "How often to collect garbage, such as \"1h\". Set to 0 to only " +
+				"collect garbage with \"waypoint server gc\".",
+		})
+		f.UintVar(&flag.UintVar{
+			Name:   "webhook-attempts",
+			Target: &c.flagWebhookAttempts,
+			Usage:  "How many times a webhook payload is sent before giving up.",
+		})
+		f.StringVar(&flag.StringVar{
+			Name:   "webhook-timeout",
+			Target: &c.flagRuntime.WebhookTimeout,
+			Usage:  "How long to wait for a webhook to respond, such as \"10s\".",
+		})
+		f.StringVar(&flag.StringVar{
+			Name:   "webhook-retry-wait",
+			Target: &c.flagRuntime.WebhookRetryWait,
+			Usage: "How long to wait after the first failed attempt to send a webhook " +
+				"payload. The wait grows with each attempt.",
+		})
+		f.StringVar(&flag.StringVar{
+			Name:   "runner-ack-timeout",
+			Target: &c.flagRuntime.RunnerAckTimeout,
+			Usage: "How long a runner has to accept a job assigned to it before the " +
+				"job is queued again.",
+		})
+		f.StringVar(&flag.StringVar{
+			Name:   "runner-heartbeat-timeout",
+			Target: &c.flagRuntime.RunnerHeartbeatTimeout,
+			Usage: "How long a running job waits for a heartbeat from its runner " +
+				"before the job is failed.",
+		})
 	})
 }
 
@@ -91,5 +156,10 @@ Usage: waypoint server config-set [options]
   given via the startup file. This configuration is persisted in the server
   database.
 
+  Runtime options take effect immediately, without restarting the server.
+  Runtime options that aren't given keep their current values. Settings in
+  the file given to "waypoint server run -config" replace these when the
+  server starts or receives SIGHUP.
+
 ` + c.Flags().Help())
 }
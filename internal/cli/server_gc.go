@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"strconv"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type ServerGCCommand struct {
+	*baseCommand
+
+	flagProject string
+	flagDryRun  bool
+}
+
+func (c *ServerGCCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	req := &pb.RunGCRequest{DryRun: c.flagDryRun}
+	if c.flagProject != "" {
+		req.Project = &pb.Ref_Project{Project: c.flagProject}
+	}
+
+	resp, err := c.project.Client().RunGC(c.Ctx, req)
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if c.jsonOutput() {
+		if err := c.writeJSON(map[string]interface{}{
+			"dry_run":     c.flagDryRun,
+			"builds":      resp.Builds,
+			"artifacts":   resp.Artifacts,
+			"deployments": resp.Deployments,
+			"releases":    resp.Releases,
+		}); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
+	if c.flagDryRun {
+		c.ui.Output("Garbage collection would delete:", terminal.WithHeaderStyle())
+	} else {
+		c.ui.Output("Garbage collection deleted:", terminal.WithHeaderStyle())
+	}
+	c.ui.NamedValues([]terminal.NamedValue{
+		{Name: "Builds", Value: strconv.FormatUint(uint64(resp.Builds), 10)},
+		{Name: "Artifacts", Value: strconv.FormatUint(uint64(resp.Artifacts), 10)},
+		{Name: "Deployments", Value: strconv.FormatUint(uint64(resp.Deployments), 10)},
+		{Name: "Releases", Value: strconv.FormatUint(uint64(resp.Releases), 10)},
+	})
+	return 0
+}
+
+func (c *ServerGCCommand) Flags() *flag.Sets {
+	return c.flagSet(flagSetOutput, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:   "project",
+			Target: &c.flagProject,
+			Usage:  "Only collect the records of this project. Every project is collected if not set.",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:   "dry-run",
+			Target: &c.flagDryRun,
+			Usage:  "Show how many records would be deleted without deleting them.",
+		})
+	})
+}
+
+func (c *ServerGCCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ServerGCCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ServerGCCommand) Synopsis() string {
+	return "Delete old builds, artifacts, deployments, and releases"
+}
+
+func (c *ServerGCCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint server gc [options]
+
+  Delete the old builds, artifacts, deployments, and releases that the
+  retention policies don't keep.
+
+  The server also collects garbage periodically, see the "-gc-interval" flag
+  of "waypoint server run". This command collects garbage right away, for
+  example after changing a policy with "waypoint server gc-policy".
+
+  Nothing is deleted for projects without a retention policy.
+
+` + c.Flags().Help())
+}
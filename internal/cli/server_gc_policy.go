@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type ServerGCPolicyCommand struct {
+	*baseCommand
+
+	flagProject    string
+	flagKeepLast   int
+	flagMaxAgeDays int
+	flagDelete     bool
+}
+
+func (c *ServerGCPolicyCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	client := c.project.Client()
+	resp, err := client.ListRetentionPolicies(c.Ctx, &empty.Empty{})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	// Set the policy if any setting was given. Settings that weren't given
+	// keep their current values.
+	if c.flagDelete || c.flagKeepLast >= 0 || c.flagMaxAgeDays >= 0 {
+		policy := &pb.RetentionPolicy{}
+		if c.flagProject != "" {
+			policy.Project = &pb.Ref_Project{Project: c.flagProject}
+		}
+		for _, p := range resp.Policies {
+			if strings.EqualFold(p.Project.GetProject(), c.flagProject) {
+				policy.KeepLast = p.KeepLast
+				policy.MaxAgeDays = p.MaxAgeDays
+			}
+		}
+		if c.flagKeepLast >= 0 {
+			policy.KeepLast = uint32(c.flagKeepLast)
+		}
+		if c.flagMaxAgeDays >= 0 {
+			policy.MaxAgeDays = uint32(c.flagMaxAgeDays)
+		}
+
+		_, err := client.SetRetentionPolicy(c.Ctx, &pb.SetRetentionPolicyRequest{
+			Policy: policy,
+			Delete: c.flagDelete,
+		})
+		if err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		resp, err = client.ListRetentionPolicies(c.Ctx, &empty.Empty{})
+		if err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+	}
+
+	if c.jsonOutput() {
+		output := []map[string]interface{}{}
+		for _, p := range resp.Policies {
+			output = append(output, map[string]interface{}{
+				"project":      p.Project.GetProject(),
+				"keep_last":    p.KeepLast,
+				"max_age_days": p.MaxAgeDays,
+			})
+		}
+
+		if err := c.writeJSON(output); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
+	if len(resp.Policies) == 0 {
+		c.ui.Output("No retention policies are set, garbage collection deletes nothing.")
+		return 0
+	}
+
+	table := terminal.NewTable("Project", "Keep Last", "Max Age (Days)")
+	for _, p := range resp.Policies {
+		project := p.Project.GetProject()
+		if project == "" {
+			project = "(default)"
+		}
+
+		table.Rich([]string{
+			project,
+			strconv.FormatUint(uint64(p.KeepLast), 10),
+			strconv.FormatUint(uint64(p.MaxAgeDays), 10),
+		}, nil)
+	}
+
+	c.ui.Table(table)
+	return 0
+}
+
+func (c *ServerGCPolicyCommand) Flags() *flag.Sets {
+	return c.flagSet(flagSetOutput, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:   "project",
+			Target: &c.flagProject,
+			Usage:  "Project to set the policy of. The default policy is set if not set.",
+		})
+
+		f.IntVar(&flag.IntVar{
+			Name:    "keep-last",
+			Target:  &c.flagKeepLast,
+			Default: -1,
+			Usage: "Number of most recent builds, artifacts, deployments, and releases " +
+				"to keep for each application and workspace.",
+		})
+
+		f.IntVar(&flag.IntVar{
+			Name:    "max-age-days",
+			Target:  &c.flagMaxAgeDays,
+			Default: -1,
+			Usage: "Only delete records that completed more than this many days ago. " +
+				"Set to 0 to delete records of any age.",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:   "delete",
+			Target: &c.flagDelete,
+			Usage:  "Delete the policy of the project so that the default policy applies to it.",
+		})
+	})
+}
+
+func (c *ServerGCPolicyCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ServerGCPolicyCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ServerGCPolicyCommand) Synopsis() string {
+	return "Show or set the retention policies of garbage collection"
+}
+
+func (c *ServerGCPolicyCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint server gc-policy [options]
+
+  Show or set the retention policies that decide which old builds,
+  artifacts, deployments, and releases garbage collection deletes.
+
+  Without -keep-last, -max-age-days, or -delete, this shows the policies.
+  Otherwise it sets the policy of -project, or the default policy of
+  projects without their own, and then shows the policies. Settings that
+  aren't given keep their current values.
+
+  For each application and workspace, the last -keep-last records of each
+  kind are kept, along with the latest successful one. Of the rest, only
+  the records older than -max-age-days are deleted, if it is set.
+  Deployments and releases are only deleted once they're destroyed, and
+  records that kept records refer to, such as the artifact of a deployment,
+  are kept too.
+
+` + c.Flags().Help())
+}
@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	"github.com/hashicorp/waypoint/internal/server"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type ServerLogLevelCommand struct {
+	*baseCommand
+
+	flagSubsystem string
+	flagReset     bool
+}
+
+func (c *ServerLogLevelCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	if len(c.args) > 1 || (c.flagReset && (len(c.args) > 0 || c.flagSubsystem == "")) {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	client := c.project.Client()
+
+	// Set the level if one is given or a subsystem is reset.
+	if len(c.args) == 1 || c.flagReset {
+		req := &pb.SetLogLevelRequest{Subsystem: c.flagSubsystem}
+		if len(c.args) == 1 {
+			req.Level = strings.ToLower(c.args[0])
+		}
+
+		if _, err := client.SetLogLevel(c.Ctx, req); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		target := "Server"
+		if c.flagSubsystem != "" {
+			target = "Subsystem " + c.flagSubsystem
+		}
+		if req.Level == "" {
+			c.ui.Output("%s uses the server log level.", target, terminal.WithSuccessStyle())
+		} else {
+			c.ui.Output("%s log level set to %s.", target, req.Level, terminal.WithSuccessStyle())
+		}
+
+		return 0
+	}
+
+	resp, err := client.GetLogLevel(c.Ctx, &empty.Empty{})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if c.jsonOutput() {
+		subsystems := map[string]string{}
+		for _, sub := range resp.Subsystems {
+			subsystems[sub.Subsystem] = sub.Level
+		}
+
+		if err := c.writeJSON(map[string]interface{}{
+			"level":      resp.Level,
+			"subsystems": subsystems,
+		}); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
+	table := terminal.NewTable("Subsystem", "Level")
+	table.Rich([]string{"(server)", resp.Level}, nil)
+	for _, sub := range resp.Subsystems {
+		level := sub.Level
+		if level == "" {
+			level = resp.Level + " (server)"
+		}
+
+		table.Rich([]string{sub.Subsystem, level}, nil)
+	}
+
+	c.ui.Table(table)
+	return 0
+}
+
+func (c *ServerLogLevelCommand) Flags() *flag.Sets {
+	return c.flagSet(flagSetOutput, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:   "subsystem",
+			Target: &c.flagSubsystem,
+			Usage: "Set the log level of this subsystem instead of the whole server. " +
+				"One of: " + strings.Join(server.LogSubsystems, ", ") + ".",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:   "reset",
+			Target: &c.flagReset,
+			Usage:  "Make the subsystem given with -subsystem use the server log level again.",
+		})
+	})
+}
+
+func (c *ServerLogLevelCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictSet("trace", "debug", "info", "warn", "error")
+}
+
+func (c *ServerLogLevelCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ServerLogLevelCommand) Synopsis() string {
+	return "Show or change the log levels of a running server"
+}
+
+func (c *ServerLogLevelCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint server log-level [options] [LEVEL]
+
+  Show or change the log levels of a running server.
+
+  Without LEVEL, this shows the log level of the server and of each of its
+  subsystems. Subsystems without their own level use the server log level.
+
+  With LEVEL, this sets the log level of the server, or of the subsystem
+  given with "-subsystem". LEVEL is one of trace, debug, info, warn, or
+  error. This is useful for getting more detailed logs while debugging an
+  issue without restarting the server.
+
+  Levels set with this command aren't stored. They last until the server
+  restarts, or until its configuration file is reloaded and sets them.
+
+` + c.Flags().Help())
+}
@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type ServerPruneCommand struct {
+	*baseCommand
+
+	flagOlderThan time.Duration
+	flagProject   string
+	flagDryRun    bool
+}
+
+func (c *ServerPruneCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	if c.flagOlderThan <= 0 {
+		c.ui.Output("-older-than is required and must be positive.\n\n"+c.Help(),
+			terminal.WithErrorStyle())
+		return 1
+	}
+
+	before, err := ptypes.TimestampProto(time.Now().Add(-c.flagOlderThan))
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	req := &pb.PruneDataRequest{Before: before, DryRun: c.flagDryRun}
+	if c.flagProject != "" {
+		req.Project = &pb.Ref_Project{Project: c.flagProject}
+	}
+
+	resp, err := c.project.Client().PruneData(c.Ctx, req)
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if c.jsonOutput() {
+		if err := c.writeJSON(map[string]interface{}{
+			"dry_run":              c.flagDryRun,
+			"deployments":          resp.Deployments,
+			"releases":             resp.Releases,
+			"jobs":                 resp.Jobs,
+			"deleted_record_bytes": resp.DeletedRecordBytes,
+		}); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
+	if c.flagDryRun {
+		c.ui.Output("Pruning would delete:", terminal.WithHeaderStyle())
+	} else {
+		c.ui.Output("Pruning deleted:", terminal.WithHeaderStyle())
+	}
+	c.ui.NamedValues([]terminal.NamedValue{
+		{Name: "Deployments", Value: strconv.FormatUint(uint64(resp.Deployments), 10)},
+		{Name: "Releases", Value: strconv.FormatUint(uint64(resp.Releases), 10)},
+		{Name: "Jobs", Value: strconv.FormatUint(uint64(resp.Jobs), 10)},
+		{Name: "Size of deleted records", Value: humanize.Bytes(resp.DeletedRecordBytes)},
+	})
+	return 0
+}
+
+func (c *ServerPruneCommand) Flags() *flag.Sets {
+	return c.flagSet(flagSetOutput, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.DurationVar(&flag.DurationVar{
+			Name:   "older-than",
+			Target: &c.flagOlderThan,
+			Usage: "Delete the records that completed longer ago than this, such as " +
+				"\"720h\" for 30 days. Required.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "project",
+			Target: &c.flagProject,
+			Usage:  "Only prune the records of this project. Every project is pruned if not set.",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:   "dry-run",
+			Target: &c.flagDryRun,
+			Usage:  "Show how many records would be deleted without deleting them.",
+		})
+	})
+}
+
+func (c *ServerPruneCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ServerPruneCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ServerPruneCommand) Synopsis() string {
+	return "Permanently delete the records of destroyed deployments and releases"
+}
+
+func (c *ServerPruneCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint server prune [options]
+
+  Permanently delete the records of destroyed deployments and releases that
+  completed before the age given with "-older-than", along with the jobs that
+  operated on them and their output.
+
+  Unlike "waypoint server gc", this doesn't depend on the retention policies.
+  Deployments and releases whose resources weren't destroyed are never
+  deleted, nor are deployments of releases that are kept. The latest
+  deployment and release of each application are kept so that their sequence
+  numbers aren't reused.
+
+  The space of the deleted records is reused for new records, but the
+  database file only shrinks once it is compacted by taking a snapshot and
+  restoring it. Snapshots taken before pruning still contain the records.
+
+` + c.Flags().Help())
+}
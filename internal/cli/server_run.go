@@ -1,21 +1,17 @@
 package cli
 
 import (
-	"bytes"
-	"crypto/ecdsa"
-	"crypto/elliptic"
-	"crypto/rand"
+	"context"
 	"crypto/tls"
-	"crypto/x509"
-	"crypto/x509/pkix"
-	"encoding/pem"
 	"fmt"
 	"io"
 	"io/ioutil"
-	"math/big"
 	"net"
+	"net/http"
+	"os"
+	"os/signal"
 	"strings"
-	"time"
+	"syscall"
 
 	"github.com/boltdb/bolt"
 	"github.com/hashicorp/go-hclog"
@@ -66,6 +62,10 @@ type ServerRunCommand struct {
 	flagAdvertiseTLSEnabled    bool
 	flagAdvertiseTLSSkipVerify bool
 	flagAcceptTOS              bool
+	flagOIDCConfig             string
+	flagConfig                 string
+	flagTLSCertFile            string
+	flagTLSKeyFile             string
 }
 
 func (c *ServerRunCommand) Run(args []string) int {
@@ -89,6 +89,48 @@ func (c *ServerRunCommand) Run(args []string) int {
 		return 1
 	}
 
+	// Load the OIDC login configuration
+	if c.flagOIDCConfig != "" {
+		oidc, err := serverconfig.LoadOIDC(c.flagOIDCConfig)
+		if err != nil {
+			c.ui.Output(
+				"Error loading OIDC configuration: %s", err.Error(),
+				terminal.WithErrorStyle(),
+			)
+			return 1
+		}
+
+		c.config.OIDC = oidc
+	}
+
+	// Load the runtime configuration. This is loaded again on SIGHUP.
+	if c.flagConfig != "" {
+		rc, err := serverconfig.LoadRuntime(c.flagConfig)
+		if err != nil {
+			c.ui.Output(
+				"Error loading configuration: %s", err.Error(),
+				terminal.WithErrorStyle(),
+			)
+			return 1
+		}
+
+		c.config.Runtime = rc
+	}
+
+	// Set our log output higher if its not already so that it begins
+	// showing. This is done before creating the server so that the log
+	// level of the runtime configuration isn't replaced.
+	if !log.IsInfo() {
+		log.SetLevel(hclog.Info)
+	}
+
+	// Wrap our logger so that the levels of the server and its subsystems,
+	// such as "state", can be changed while the server is running. The
+	// unwrapped logger is kept so its output can be reset below.
+	baseLog := log
+	logLevels := server.NewLogLevels(log)
+	log = logLevels.Logger()
+
 	// Open our database
 	if c.config.DBPath == "" {
 		c.ui.Output(serverWarnDBPath, terminal.WithWarningStyle())
@@ -153,6 +195,7 @@ func (c *ServerRunCommand) Run(args []string) int {
 		singleprocess.WithDB(db),
 		singleprocess.WithConfig(&c.config),
 		singleprocess.WithLogger(log.Named("singleprocess")),
+		singleprocess.WithLogLevels(logLevels),
 		singleprocess.WithAcceptURLTerms(c.flagAcceptTOS),
 	)
 
@@ -164,8 +207,47 @@ func (c *ServerRunCommand) Run(args []string) int {
 		return 1
 	}
 
+	// Certificate files given with flags are used by both listeners
+	if c.flagTLSCertFile != "" || c.flagTLSKeyFile != "" {
+		if c.flagTLSCertFile == "" || c.flagTLSKeyFile == "" {
+			c.ui.Output(
+				"Both -tls-cert-file and -tls-key-file must be set if either is set.",
+				terminal.WithErrorStyle(),
+			)
+			return 1
+		}
+
+		for _, l := range []*serverconfig.Listener{&c.config.GRPC, &c.config.HTTP} {
+			l.TLSCertFile = c.flagTLSCertFile
+			l.TLSKeyFile = c.flagTLSKeyFile
+		}
+	}
+
+	serverTLS := newServerTLS(log.Named("tls"), c.config.ACME, path)
+	go serverTLS.ReloadOnSignal(c.Ctx)
+
+	if r, ok := impl.(runtimeReloader); ok && c.flagConfig != "" {
+		go reloadRuntimeOnSignal(c.Ctx, log.Named("config"), c.flagConfig, r)
+	}
+
+	// ACME HTTP-01 challenges must be answered on port 80. Without this the
+	// TLS-ALPN-01 challenge is used, which requires a listener on port 443.
+	if serverTLS.acme != nil && c.config.ACME.HTTPAddr != "" {
+		acmeLn, err := net.Listen("tcp", c.config.ACME.HTTPAddr)
+		if err != nil {
+			c.ui.Output(
+				"Error starting listener: %s", err.Error(),
+				terminal.WithErrorStyle(),
+			)
+			return 1
+		}
+		defer acmeLn.Close()
+
+		go http.Serve(acmeLn, serverTLS.acme.HTTPHandler(nil))
+	}
+
 	// We listen on a random locally bound port
-	ln, err := c.listenerForConfig(log.Named("grpc"), &c.config.GRPC)
+	ln, err := c.listenerForConfig(log.Named("grpc"), serverTLS, &c.config.GRPC)
 	if err != nil {
 		c.ui.Output(
 			"Error starting listener: %s", err.Error(),
@@ -175,7 +257,7 @@ func (c *ServerRunCommand) Run(args []string) int {
 	}
 	defer ln.Close()
 
-	httpLn, err := c.listenerForConfig(log.Named("http"), &c.config.HTTP)
+	httpLn, err := c.listenerForConfig(log.Named("http"), serverTLS, &c.config.HTTP)
 	if err != nil {
 		c.ui.Output(
 			"Error starting listener: %s", err.Error(),
@@ -192,6 +274,33 @@ func (c *ServerRunCommand) Run(args []string) int {
 		server.WithHTTP(httpLn),
 		server.WithImpl(impl),
 	}
+
+	// Metrics are served over plain HTTP since that is what Prometheus
+	// expects by default and the metrics don't contain any secrets.
+	var metricsLn net.Listener
+	if addr := c.config.MetricsAddr; addr != "" {
+		metricsLn, err = net.Listen("tcp", addr)
+		if err != nil {
+			c.ui.Output(
+				"Error starting listener: %s", err.Error(),
+				terminal.WithErrorStyle(),
+			)
+			return 1
+		}
+		defer metricsLn.Close()
+
+		options = append(options, server.WithMetrics(metricsLn))
+	}
+	if rl := c.config.RateLimit; rl != nil {
+		options = append(options, server.WithRateLimit(server.RateLimit{
+			TokenRate:  rl.TokenRequestsPerSecond,
+			TokenBurst: rl.TokenBurst,
+			IPRate:     rl.IPRequestsPerSecond,
+			IPBurst:    rl.IPBurst,
+			MaxStreams: rl.MaxStreams,
+		}))
+	}
+
 	auth := false
 	if ac, ok := impl.(server.AuthChecker); ok {
 		options = append(options, server.WithAuthentication(ac))
@@ -213,6 +322,14 @@ func (c *ServerRunCommand) Run(args []string) int {
 		{Name: "gRPC Address", Value: ln.Addr().String()},
 		{Name: "HTTP Address", Value: httpLn.Addr().String()},
 	}
+	if metricsLn != nil {
+		values = append(values, terminal.NamedValue{
+			Name: "Metrics Address", Value: metricsLn.Addr().String(),
+		})
+	}
+	if !c.config.GRPC.TLSDisable || !c.config.HTTP.TLSDisable {
+		values = append(values, terminal.NamedValue{Name: "TLS Certificates", Value: serverTLS.Mode()})
+	}
 	if auth {
 		values = append(values, terminal.NamedValue{Name: "Auth Required", Value: "yes"})
 	}
@@ -259,16 +376,11 @@ This command will bootstrap the server and setup a CLI context.
 		closer.Close()
 	}
 
-	// Set our log output higher if its not already so that it begins showing.
-	if !log.IsInfo() {
-		log.SetLevel(hclog.Info)
-	}
-
 	// If our output is to discard, then we want to redirect the output
 	// to the console. We should be able to do this as long as our logger
 	// supports the OutputResettable interface.
 	if c.LogOutput == ioutil.Discard {
-		if lr, ok := log.(hclog.OutputResettable); ok {
+		if lr, ok := baseLog.(hclog.OutputResettable); ok {
 			output, _, err := c.ui.OutputWriters()
 			if err != nil {
 				c.ui.Output(
@@ -296,6 +408,24 @@ func (c *ServerRunCommand) Flags() *flag.Sets {
 		if c.config.URL == nil {
 			c.config.URL = &serverconfig.URL{}
 		}
+		if c.config.Snapshot == nil {
+			c.config.Snapshot = &serverconfig.Snapshot{}
+		}
+		if c.config.Snapshot.S3 == nil {
+			c.config.Snapshot.S3 = &serverconfig.SnapshotS3{}
+		}
+		if c.config.Audit == nil {
+			c.config.Audit = &serverconfig.Audit{}
+		}
+		if c.config.GC == nil {
+			c.config.GC = &serverconfig.GC{}
+		}
+		if c.config.RateLimit == nil {
+			c.config.RateLimit = &serverconfig.RateLimit{}
+		}
+		if c.config.ACME == nil {
+			c.config.ACME = &serverconfig.ACME{}
+		}
 
 		f := set.NewSet("Command Options")
 		f.StringVar(&flag.StringVar{
@@ -319,6 +449,61 @@ func (c *ServerRunCommand) Flags() *flag.Sets {
 			Default: "127.0.0.1:9702",
 		})
 
+		f.StringVar(&flag.StringVar{
+			Name:   "listen-metrics",
+			Target: &c.config.MetricsAddr,
+			Usage: "Address to bind to for serving Prometheus metrics at /metrics " +
+				"over plain HTTP. Metrics are disabled if this isn't set.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "tls-cert-file",
+			Target: &c.flagTLSCertFile,
+			Usage: "Path to a PEM-encoded TLS certificate for the gRPC and HTTP listeners. " +
+				"Requires -tls-key-file. The files are reloaded when the server receives SIGHUP.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "tls-key-file",
+			Target: &c.flagTLSKeyFile,
+			Usage:  "Path to the PEM-encoded private key of -tls-cert-file.",
+		})
+
+		f.StringSliceVar(&flag.StringSliceVar{
+			Name:   "acme-domain",
+			Target: &c.config.ACME.Domains,
+			Usage: "Domain to request a TLS certificate for from Let's Encrypt or another " +
+				"ACME CA, renewed automatically. Can be repeated. Setting this agrees to the " +
+				"terms of service of the CA. Not used if -tls-cert-file is set.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "acme-email",
+			Target: &c.config.ACME.Email,
+			Usage:  "Contact email for the ACME account, used by the CA for expiry notices.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "acme-cache-dir",
+			Target: &c.config.ACME.CacheDir,
+			Usage: "Directory to store ACME certificates and the account key in. " +
+				"Defaults to \"acme\" next to the database file.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "acme-directory-url",
+			Target: &c.config.ACME.DirectoryURL,
+			Usage: "Directory URL of the ACME CA. Defaults to Let's Encrypt. " +
+				"Set this to the Let's Encrypt staging URL for testing.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "acme-http-address",
+			Target: &c.config.ACME.HTTPAddr,
+			Usage: "Address to answer ACME HTTP challenges on, such as \":80\". If not set, " +
+				"the TLS challenge is used, which requires a listener on port 443.",
+		})
+
 		f.BoolVar(&flag.BoolVar{
 			Name:    "disable-ui",
 			Target:  &c.flagDisableUI,
@@ -396,6 +581,137 @@ func (c *ServerRunCommand) Flags() *flag.Sets {
 			Usage:   "Do not verify the TLS certificate presented by the server.",
 			Default: false,
 		})
+		f.StringVar(&flag.StringVar{
+			Name:   "snapshot-interval",
+			Target: &c.config.Snapshot.Interval,
+			Usage: "How often to automatically take a snapshot of the server data, " +
+				"such as \"6h\". Requires -snapshot-path or -snapshot-s3-bucket. Disabled if not set.",
+		})
+
+		f.IntVar(&flag.IntVar{
+			Name:    "snapshot-retain",
+			Target:  &c.config.Snapshot.Retain,
+			Usage:   "Number of automatic snapshots to keep. Zero keeps all snapshots.",
+			Default: 10,
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "snapshot-max-age",
+			Target: &c.config.Snapshot.MaxAge,
+			Usage: "Maximum age of automatic snapshots to keep, such as \"168h\". " +
+				"Older snapshots are deleted. Snapshots of any age are kept if not set.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "snapshot-path",
+			Target: &c.config.Snapshot.Path,
+			Usage:  "Directory to write automatic snapshots to.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "snapshot-s3-bucket",
+			Target: &c.config.Snapshot.S3.Bucket,
+			Usage: "S3 bucket to write snapshots to instead of -snapshot-path. " +
+				"The server uses the standard AWS credentials, such as AWS_ACCESS_KEY_ID.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "snapshot-s3-prefix",
+			Target: &c.config.Snapshot.S3.Prefix,
+			Usage:  "Prefix of the keys of snapshots in the S3 bucket, such as \"waypoint/\".",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "snapshot-s3-region",
+			Target: &c.config.Snapshot.S3.Region,
+			Usage:  "Region of the S3 bucket. Looked up from the bucket if not set.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "snapshot-s3-endpoint",
+			Target: &c.config.Snapshot.S3.Endpoint,
+			Usage: "URL of an S3 compatible API to use instead of AWS S3, such as " +
+				"\"https://storage.googleapis.com\" for GCS.",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:   "snapshot-s3-force-path-style",
+			Target: &c.config.Snapshot.S3.ForcePathStyle,
+			Usage:  "Put the bucket name in the path of S3 requests rather than the host name.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:    "gc-interval",
+			Target:  &c.config.GC.Interval,
+			Default: "1h",
+			Usage: "How often to delete the old builds, artifacts, deployments, and releases " +
+				"that the retention policies don't keep. Set to 0 to only collect garbage " +
+				"with \"waypoint server gc\".",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "audit-file",
+			Target: &c.config.Audit.File,
+			Usage: "File to append audit events to as JSON, one event per line. " +
+				"Audit events are always stored by the server, this sends a copy.",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:   "audit-syslog",
+			Target: &c.config.Audit.Syslog,
+			Usage:  "Send a copy of audit events to the local syslog daemon. Not supported on Windows.",
+		})
+
+		f.Float64Var(&flag.Float64Var{
+			Name:   "rate-limit",
+			Target: &c.config.RateLimit.TokenRequestsPerSecond,
+			Usage: "Maximum gRPC requests per second for each token. Requests over " +
+				"the limit fail and can be retried. Not limited if not set.",
+		})
+
+		f.IntVar(&flag.IntVar{
+			Name:   "rate-limit-burst",
+			Target: &c.config.RateLimit.TokenBurst,
+			Usage: "Number of gRPC requests each token can make at once before " +
+				"-rate-limit applies. Defaults to one second of requests.",
+		})
+
+		f.Float64Var(&flag.Float64Var{
+			Name:   "rate-limit-ip",
+			Target: &c.config.RateLimit.IPRequestsPerSecond,
+			Usage: "Maximum gRPC requests per second for each IP address, whatever " +
+				"token they use. Not limited if not set.",
+		})
+
+		f.IntVar(&flag.IntVar{
+			Name:   "rate-limit-ip-burst",
+			Target: &c.config.RateLimit.IPBurst,
+			Usage: "Number of gRPC requests each IP address can make at once before " +
+				"-rate-limit-ip applies. Defaults to one second of requests.",
+		})
+
+		f.IntVar(&flag.IntVar{
+			Name:   "max-streams",
+			Target: &c.config.RateLimit.MaxStreams,
+			Usage: "Maximum concurrent gRPC streams, such as log streams and runners, " +
+				"for each token. Not limited if not set.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "config",
+			Target: &c.flagConfig,
+			Usage: "Path to an HCL or JSON file with the settings that can be changed " +
+				"while the server is running, such as the log level. The file is read " +
+				"again when the server receives SIGHUP.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "oidc-config",
+			Target: &c.flagOIDCConfig,
+			Usage: "Path to an HCL or JSON file that configures logging in with an OIDC " +
+				"identity provider using \"waypoint login\" and the UI.",
+		})
+
 		f.BoolVar(&flag.BoolVar{
 			Name:    "accept-tos",
 			Target:  &c.flagAcceptTOS,
@@ -430,7 +746,11 @@ Usage: waypoint server run [options]
 ` + c.Flags().Help())
 }
 
-func (c *ServerRunCommand) listenerForConfig(log hclog.Logger, cfg *serverconfig.Listener) (net.Listener, error) {
+func (c *ServerRunCommand) listenerForConfig(
+	log hclog.Logger,
+	serverTLS *serverTLS,
+	cfg *serverconfig.Listener,
+) (net.Listener, error) {
 	// Start our bare listener
 	log.Debug("starting listener", "addr", cfg.Addr)
 	ln, err := net.Listen("tcp", cfg.Addr)
@@ -444,97 +764,15 @@ func (c *ServerRunCommand) listenerForConfig(log hclog.Logger, cfg *serverconfig
 		return ln, nil
 	}
 
-	// If we don't have a cert then we self-sign.
-	var certPEM, keyPEM []byte
-	if cfg.TLSCertFile != "" {
-		certPEM, err = ioutil.ReadFile(cfg.TLSCertFile)
-		if err != nil {
-			return nil, err
-		}
-		keyPEM, err = ioutil.ReadFile(cfg.TLSKeyFile)
-		if err != nil {
-			return nil, err
-		}
-
-		log.Info("TLS certs loaded from specified files",
-			"cert", cfg.TLSCertFile,
-			"key", cfg.TLSKeyFile)
-	}
-
-	if certPEM == nil {
-		log.Info("TLS cert wasn't specified, a self-signed certificate will be created")
-
-		priv, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
-		if err != nil {
-			return nil, err
-		}
-
-		template := x509.Certificate{
-			SerialNumber: big.NewInt(1),
-			Subject: pkix.Name{
-				Organization: []string{"Waypoint"},
-			},
-			NotBefore:             time.Now(),
-			NotAfter:              time.Now().Add(time.Hour * 24 * 365 * 10),
-			KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
-			ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
-			BasicConstraintsValid: true,
-		}
-
-		derBytes, err := x509.CreateCertificate(rand.Reader, &template, &template, publicKey(priv), priv)
-		if err != nil {
-			return nil, err
-		}
-
-		// Write the cert
-		var out bytes.Buffer
-		err = pem.Encode(&out, &pem.Block{Type: "CERTIFICATE", Bytes: derBytes})
-		if err != nil {
-			return nil, err
-		}
-		certPEM = out.Bytes()
-
-		// Write the key
-		out = bytes.Buffer{}
-		if err := pem.Encode(&out, pemBlockForKey(priv)); err != nil {
-			return nil, err
-		}
-		keyPEM = out.Bytes()
-	}
-
 	// Setup the TLS listener
-	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	tlsConfig, err := serverTLS.Config(log, cfg)
 	if err != nil {
 		ln.Close()
 		return nil, err
 	}
 
 	log.Info("listener is wrapped with TLS")
-	return tls.NewListener(ln, &tls.Config{
-		Certificates: []tls.Certificate{cert},
-	}), nil
-}
-
-func publicKey(priv interface{}) interface{} {
-	switch k := priv.(type) {
-	case *ecdsa.PrivateKey:
-		return &k.PublicKey
-	default:
-		return nil
-	}
-}
-
-func pemBlockForKey(priv interface{}) *pem.Block {
-	switch k := priv.(type) {
-	case *ecdsa.PrivateKey:
-		b, err := x509.MarshalECPrivateKey(k)
-		if err != nil {
-			panic(err)
-		}
-		return &pem.Block{Type: "EC PRIVATE KEY", Bytes: b}
-	default:
-		return nil
-	}
+	return tls.NewListener(ln, tlsConfig), nil
 }
 
 const serverWarnDBPath = `Warning! Default DB path will be used. This is at the path shown below.
@@ -543,3 +781,43 @@ consistent for every server run otherwise data loss will occur. It is
 recommended that you explicitly set the "-db" flag as acknowledgement of
 the importance of the DB file.
 `
+
+// runtimeReloader is implemented by servers that can apply a new runtime
+// configuration while they're running.
+type runtimeReloader interface {
+	ReloadRuntimeConfig(*serverconfig.Runtime) error
+}
+
+// reloadRuntimeOnSignal loads the runtime configuration from path and
+// applies it to the server when the process receives SIGHUP, until ctx is
+// done. Configurations that fail to load or apply are logged and the
+// server keeps its previous configuration.
+func reloadRuntimeOnSignal(
+	ctx context.Context,
+	log hclog.Logger,
+	path string,
+	r runtimeReloader,
+) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	defer signal.Stop(sigCh)
+
+	for {
+		select {
+		case <-sigCh:
+			log.Info("reloading configuration", "path", path)
+			rc, err := serverconfig.LoadRuntime(path)
+			if err == nil {
+				err = r.ReloadRuntimeConfig(rc)
+			}
+			if err != nil {
+				log.Error("error reloading configuration, keeping the previous one",
+					"path", path,
+					"err", err)
+			}
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package cli
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+
+	"github.com/hashicorp/waypoint/internal/serverconfig"
+)
+
+const (
+	// selfSignedValidity is how long self-signed certificates are valid
+	// for. They're replaced selfSignedRenew before they expire.
+	selfSignedValidity = 365 * 24 * time.Hour
+	selfSignedRenew    = 30 * 24 * time.Hour
+)
+
+// serverTLS provides the certificates of the server's TLS listeners. Each
+// listener uses the certificate files it is configured with, or else a
+// certificate from ACME if that is configured, or else a self-signed
+// certificate. Certificates from ACME and self-signed certificates are
+// renewed automatically, and certificate files are reloaded on SIGHUP.
+type serverTLS struct {
+	log  hclog.Logger
+	acme *autocert.Manager
+
+	lock       sync.Mutex
+	files      []*fileCert
+	selfSigned *selfSignedCert
+}
+
+// newServerTLS returns the serverTLS for the ACME configuration, which
+// may be nil. dbPath is used for the default ACME cache directory.
+func newServerTLS(log hclog.Logger, cfg *serverconfig.ACME, dbPath string) *serverTLS {
+	result := &serverTLS{log: log}
+	if cfg == nil || len(cfg.Domains) == 0 {
+		return result
+	}
+
+	cacheDir := cfg.CacheDir
+	if cacheDir == "" {
+		cacheDir = filepath.Join(filepath.Dir(dbPath), "acme")
+	}
+
+	// Using ACME requires agreeing to the terms of service of the CA. This
+	// is noted in the help of the ACME flags.
+	result.acme = &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		Cache:      autocert.DirCache(cacheDir),
+		HostPolicy: autocert.HostWhitelist(cfg.Domains...),
+		Email:      cfg.Email,
+	}
+	if cfg.DirectoryURL != "" {
+		result.acme.Client = &acme.Client{DirectoryURL: cfg.DirectoryURL}
+	}
+
+	log.Info("TLS certificates will be requested with ACME",
+		"domains", cfg.Domains,
+		"cache_dir", cacheDir)
+	return result
+}
+
+// Config returns the TLS configuration for a listener.
+func (s *serverTLS) Config(log hclog.Logger, cfg *serverconfig.Listener) (*tls.Config, error) {
+	if cfg.TLSCertFile != "" {
+		fc := &fileCert{log: log, certFile: cfg.TLSCertFile, keyFile: cfg.TLSKeyFile}
+		if err := fc.load(); err != nil {
+			return nil, err
+		}
+
+		s.lock.Lock()
+		s.files = append(s.files, fc)
+		s.lock.Unlock()
+
+		log.Info("TLS certs loaded from specified files",
+			"cert", cfg.TLSCertFile,
+			"key", cfg.TLSKeyFile)
+		return &tls.Config{GetCertificate: fc.GetCertificate}, nil
+	}
+
+	if s.acme != nil {
+		return s.acme.TLSConfig(), nil
+	}
+
+	// Listeners share the self-signed certificate
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.selfSigned == nil {
+		log.Info("TLS cert wasn't specified, a self-signed certificate will be created")
+		s.selfSigned = &selfSignedCert{log: s.log}
+	}
+
+	return &tls.Config{GetCertificate: s.selfSigned.GetCertificate}, nil
+}
+
+// Mode returns how the certificates are provided, to show to the user.
+func (s *serverTLS) Mode() string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	switch {
+	case len(s.files) > 0:
+		return "certificate files (reloaded on SIGHUP)"
+
+	case s.acme != nil:
+		return "ACME"
+
+	default:
+		return "self-signed"
+	}
+}
+
+// ReloadOnSignal reloads the certificate files when the process receives
+// SIGHUP, until ctx is done. Certificates that fail to load are logged and
+// the previous certificate is kept.
+func (s *serverTLS) ReloadOnSignal(ctx context.Context) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	defer signal.Stop(sigCh)
+
+	for {
+		select {
+		case <-sigCh:
+			s.lock.Lock()
+			files := s.files
+			s.lock.Unlock()
+
+			s.log.Info("reloading TLS certificate files", "count", len(files))
+			for _, fc := range files {
+				if err := fc.load(); err != nil {
+					fc.log.Error("error reloading TLS certificate, keeping the previous one",
+						"cert", fc.certFile,
+						"err", err)
+				}
+			}
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// fileCert is a certificate loaded from files.
+type fileCert struct {
+	log      hclog.Logger
+	certFile string
+	keyFile  string
+
+	lock sync.RWMutex
+	cert *tls.Certificate
+}
+
+func (c *fileCert) load() error {
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.cert = &cert
+	return nil
+}
+
+func (c *fileCert) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.cert, nil
+}
+
+// selfSignedCert is a self-signed certificate that is created when first
+// needed and replaced when it nears expiry.
+type selfSignedCert struct {
+	log hclog.Logger
+
+	lock     sync.Mutex
+	cert     *tls.Certificate
+	notAfter time.Time
+}
+
+func (c *selfSignedCert) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.cert != nil && time.Until(c.notAfter) > selfSignedRenew {
+		return c.cert, nil
+	}
+
+	if c.cert != nil {
+		c.log.Info("self-signed TLS certificate is expiring, creating a new one",
+			"not_after", c.notAfter)
+	}
+
+	cert, notAfter, err := newSelfSignedCert()
+	if err != nil {
+		return nil, err
+	}
+
+	c.cert = cert
+	c.notAfter = notAfter
+	return c.cert, nil
+}
+
+// newSelfSignedCert creates a self-signed certificate valid for
+// selfSignedValidity.
+func newSelfSignedCert() (*tls.Certificate, time.Time, error) {
+	priv, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	now := time.Now()
+	template := x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization: []string{"Waypoint"},
+		},
+		NotBefore:             now,
+		NotAfter:              now.Add(selfSignedValidity),
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+
+	derBytes, err := x509.CreateCertificate(rand.Reader, &template, &template, &priv.PublicKey, priv)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return &tls.Certificate{
+		Certificate: [][]byte{derBytes},
+		PrivateKey:  priv,
+	}, template.NotAfter, nil
+}
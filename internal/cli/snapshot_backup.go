@@ -1,11 +1,17 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"time"
 
+	"github.com/dustin/go-humanize"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
 	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	"github.com/hashicorp/waypoint/internal/pkg/snapshotcrypt"
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
 	"github.com/posener/complete"
 	sshterm "golang.org/x/crypto/ssh/terminal"
@@ -14,16 +20,37 @@ import (
 
 type SnapshotBackupCommand struct {
 	*baseCommand
+
+	// set via -encrypt-key and -encrypt-key-file, the key to encrypt the
+	// snapshot with.
+	flagKey snapshotKeyFlags
+
+	// set via -incremental-from, the snapshot chain to write an
+	// incremental snapshot against.
+	flagIncrementalFrom []string
+
+	// set via -server-side, store the snapshot with the server's snapshot
+	// storage instead of writing it here.
+	flagServerSide bool
 }
 
 // initWriter inspects args to figure out where the snapshot will be written to. It
-// supports args[0] being '-' to force writing to stdout.
+// supports args[0] being '-' to force writing to stdout, or an object storage URL.
 func (c *SnapshotBackupCommand) initWriter(args []string) (io.Writer, io.Closer, error) {
 	if len(args) >= 1 {
 		if args[0] == "-" {
 			return os.Stdout, nil, nil
 		}
 
+		if isSnapshotURL(args[0]) {
+			u, err := createSnapshotURL(c.Ctx, args[0])
+			if err != nil {
+				return nil, nil, err
+			}
+
+			return u, u, nil
+		}
+
 		f, err := os.Create(args[0])
 		if err != nil {
 			return nil, nil, err
@@ -32,6 +59,13 @@ func (c *SnapshotBackupCommand) initWriter(args []string) (io.Writer, io.Closer,
 		return f, f, nil
 	}
 
+	// In non-interactive mode we don't check for a terminal so that the
+	// behavior doesn't depend on it.
+	if c.nonInteractive() {
+		return nil, nil, fmt.Errorf("no snapshot path given; with -non-interactive, " +
+			"use '-' to write the snapshot to stdout")
+	}
+
 	f := os.Stdout
 
 	if sshterm.IsTerminal(int(f.Fd())) {
@@ -51,11 +85,27 @@ func (c *SnapshotBackupCommand) Run(args []string) int {
 		return 1
 	}
 
+	key, err := c.flagKey.Key()
+	if err != nil {
+		c.ui.Output("Invalid encryption key: %s", err, terminal.WithErrorStyle())
+		return 1
+	}
+
 	client := c.project.Client()
 
-	w, closer, err := c.initWriter(args)
+	if c.flagServerSide {
+		if len(c.args) > 0 || key != nil || len(c.flagIncrementalFrom) > 0 {
+			c.ui.Output("-server-side can't be used with a snapshot path, "+
+				"an encryption key, or -incremental-from.", terminal.WithErrorStyle())
+			return 1
+		}
+
+		return c.runServerSide(client)
+	}
+
+	w, closer, err := c.initWriter(c.args)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to open output: %s", err)
+		c.outputError("Failed to open output", err)
 		return 1
 	}
 
@@ -63,21 +113,144 @@ func (c *SnapshotBackupCommand) Run(args []string) int {
 		defer closer.Close()
 	}
 
-	stream, err := client.CreateSnapshot(c.Ctx, &emptypb.Empty{})
+	// If we have a key, encrypt the data before it is written anywhere.
+	out := w
+	var ew *snapshotcrypt.Writer
+	if key != nil {
+		ew, err = snapshotcrypt.NewWriter(w, key)
+		if err != nil {
+			c.outputError("Failed to encrypt snapshot", err)
+			return 1
+		}
+
+		out = ew
+	}
+
+	if len(c.flagIncrementalFrom) > 0 {
+		err = c.writeIncremental(client, out, key)
+	} else {
+		err = writeSnapshot(c.Ctx, client, out)
+	}
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to generate snapshot: %s", err)
+		c.outputError("Failed to write snapshot", err)
 		return 1
 	}
 
-	resp, err := stream.Recv()
+	if ew != nil {
+		if err := ew.Close(); err != nil {
+			c.outputError("Failed to encrypt snapshot", err)
+			return 1
+		}
+	}
+
+	// Uploads are only completed once the whole snapshot is written so
+	// that partial snapshots are never stored.
+	if u, ok := w.(*snapshotUploader); ok {
+		if err := u.Commit(); err != nil {
+			c.outputError("Failed to upload snapshot", err)
+			return 1
+		}
+	}
+
+	// When the snapshot is written to stdout, we can't write anything
+	// else there, including the JSON result.
+	if w != os.Stdout {
+		if c.jsonOutput() {
+			if err := c.writeJSON(map[string]interface{}{
+				"path":        c.args[0],
+				"incremental": len(c.flagIncrementalFrom) > 0,
+				"encrypted":   key != nil,
+			}); err != nil {
+				c.outputError("Failed to write output", err)
+				return 1
+			}
+
+			return 0
+		}
+
+		c.ui.Output("Snapshot written to '%s'", c.args[0])
+	}
+
+	return 0
+}
+
+// runServerSide has the server take a snapshot and store it with the
+// snapshots it takes on a schedule, so the snapshot data never passes
+// through this machine.
+func (c *SnapshotBackupCommand) runServerSide(client pb.WaypointClient) int {
+	snapshot, err := client.CreateStoredSnapshot(c.Ctx, &emptypb.Empty{})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to receive snapshot start message: %s", err)
+		c.outputError("Failed to store snapshot", err)
 		return 1
 	}
 
+	if c.jsonOutput() {
+		if err := c.writeJSON(map[string]interface{}{
+			"name":        snapshot.Name,
+			"size":        snapshot.Size,
+			"create_time": snapshot.CreateTime.AsTime().Format(time.RFC3339Nano),
+		}); err != nil {
+			c.outputError("Failed to write output", err)
+			return 1
+		}
+
+		return 0
+	}
+
+	c.ui.Output("Snapshot stored on the server as '%s' (%s)",
+		snapshot.Name, humanize.Bytes(uint64(snapshot.Size)))
+	return 0
+}
+
+// writeIncremental writes an incremental snapshot to w containing the
+// changes to the server data since the snapshot chain given by
+// -incremental-from. The chain is decrypted with key if it is non-nil.
+func (c *SnapshotBackupCommand) writeIncremental(client pb.WaypointClient, w io.Writer, key []byte) error {
+	parent, err := applySnapshotChain(c.flagIncrementalFrom, key)
+	if err != nil {
+		return err
+	}
+
+	// Buffer the current data to disk rather than memory since we only
+	// need to hold one full copy of the records at a time.
+	f, err := ioutil.TempFile("", "waypoint-snapshot")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if err := writeSnapshot(c.Ctx, client, f); err != nil {
+		return err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	current, err := readSnapshot(f)
+	if err != nil {
+		return fmt.Errorf("error reading snapshot data: %s", err)
+	}
+
+	_, err = writeSnapshotData(w, diffSnapshotData(parent, current))
+	return err
+}
+
+// writeSnapshot requests a snapshot from the server and writes the
+// snapshot data to w.
+func writeSnapshot(ctx context.Context, client pb.WaypointClient, w io.Writer) error {
+	stream, err := client.CreateSnapshot(ctx, &emptypb.Empty{})
+	if err != nil {
+		return fmt.Errorf("failed to generate snapshot: %s", err)
+	}
+
+	resp, err := stream.Recv()
+	if err != nil {
+		return fmt.Errorf("failed to receive snapshot start message: %s", err)
+	}
+
 	if _, ok := resp.Event.(*pb.CreateSnapshotResponse_Open_); !ok {
-		fmt.Fprintf(os.Stderr, "failed to receive snapshot start message: %s", err)
-		return 1
+		return fmt.Errorf("failed to receive snapshot start message: %s", err)
 	}
 
 	for {
@@ -87,32 +260,42 @@ func (c *SnapshotBackupCommand) Run(args []string) int {
 				break
 			}
 
-			fmt.Fprintf(os.Stderr, "error receiving snapshot data: %s", err)
-			return 1
+			return fmt.Errorf("error receiving snapshot data: %s", err)
 		}
 
 		chunk, ok := ev.Event.(*pb.CreateSnapshotResponse_Chunk)
-		if ok {
-			_, err = w.Write(chunk.Chunk)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "error writing snapshot data: %s", err)
-				return 1
-			}
-		} else {
-			fmt.Fprintf(os.Stderr, "unexpected protocol value: %T", ev.Event)
-			return 1
+		if !ok {
+			return fmt.Errorf("unexpected protocol value: %T", ev.Event)
 		}
-	}
 
-	if w != os.Stdout {
-		c.ui.Output("Snapshot written to '%s'", args[0])
+		if _, err := w.Write(chunk.Chunk); err != nil {
+			return fmt.Errorf("error writing snapshot data: %s", err)
+		}
 	}
 
-	return 0
+	return nil
 }
 
 func (c *SnapshotBackupCommand) Flags() *flag.Sets {
-	return c.flagSet(0, nil)
+	return c.flagSet(flagSetOutput, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		c.flagKey.addFlags(f)
+
+		f.StringSliceVar(&flag.StringSliceVar{
+			Name:   "incremental-from",
+			Target: &c.flagIncrementalFrom,
+			Usage: "Write an incremental snapshot with only the changes since the given " +
+				"snapshot. If that snapshot is incremental, specify this multiple times " +
+				"with its full chain, starting with the full snapshot.",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:   "server-side",
+			Target: &c.flagServerSide,
+			Usage: "Have the server store the snapshot where it stores scheduled snapshots, " +
+				"such as an S3 bucket, instead of writing it here.",
+		})
+	})
 }
 
 func (c *SnapshotBackupCommand) AutocompleteArgs() complete.Predictor {
@@ -134,7 +317,27 @@ Usage: waypoint server snapshot [<filenamp>]
 	Generate a snapshot from the current server and write it to a file specified
 	by the given name. If no name is specified and standard out is not a terminal,
 	the backup will written to standard out. Using a name of '-' will force writing
-	to standard out.
+	to standard out. The snapshot may also be uploaded directly to S3 by specifying
+	an s3://bucket/key URL. Credentials are read from the standard AWS credential chain.
+
+	If -encrypt-key or -encrypt-key-file is passed, the snapshot is encrypted with
+	AES-256-GCM before it is written. The same key must be given to restore it.
+
+	If -incremental-from is passed, the snapshot only contains the records that were
+	added, changed, or deleted since the given snapshot chain. To restore it, pass
+	the same chain to "waypoint server restore" followed by this snapshot with
+	-incremental.
+
+	If -server-side is passed, the server writes the snapshot to its own snapshot
+	storage, configured with the "snapshot" block or the -snapshot-path and
+	-snapshot-s3-* flags of "waypoint server run", instead of sending it here.
+	This avoids transferring large snapshots through this machine. The snapshot
+	is listed by "waypoint server snapshot-list" and counts towards the retention
+	limits of scheduled snapshots.
+
+	If -output=json is passed and the snapshot isn't written to standard out, the
+	path of the snapshot is written as a JSON object once it is complete. With
+	-server-side, the name and size of the stored snapshot are written instead.
 
 ` + c.Flags().Help())
 }
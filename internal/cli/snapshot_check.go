@@ -0,0 +1,256 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	"github.com/hashicorp/waypoint/internal/server/singleprocess/state"
+)
+
+// snapshotImpact summarizes how restoring a snapshot affects the projects
+// and deployments on the server.
+type snapshotImpact struct {
+	// Version is the version of the server that created the incoming
+	// snapshot. This may be nil.
+	Version *pb.VersionInfo
+
+	// ProjectsAdded and ProjectsRemoved are the sorted names of the projects
+	// that the restore adds and removes.
+	ProjectsAdded   []string
+	ProjectsRemoved []string
+
+	// Deployments are the changes to the deployments of each application,
+	// keyed by "<project>/<app>". Only applications with changes are set.
+	Deployments map[string]*snapshotTypeDiff
+}
+
+// snapshotImpactOf compares the projects and deployments in the current
+// snapshot with those in the incoming snapshot.
+func snapshotImpactOf(current, incoming io.Reader) (*snapshotImpact, error) {
+	type deployment struct {
+		app  string
+		hash [sha256.Size]byte
+	}
+
+	// read collects the project names and deployments in a snapshot. We
+	// only keep a hash of each deployment to limit memory usage.
+	read := func(r io.Reader) (*pb.Snapshot_Header, map[string]bool, map[string]deployment, error) {
+		projects := map[string]bool{}
+		deployments := map[string]deployment{}
+		header, err := decodeSnapshot(r, func(chunk *pb.Snapshot_BoltChunk) error {
+			switch chunk.Bucket {
+			case "project":
+				for k, v := range chunk.Items {
+					var p pb.Project
+					if err := proto.Unmarshal(v, &p); err != nil {
+						return fmt.Errorf("error decoding project %q: %s", k, err)
+					}
+
+					projects[p.Name] = true
+				}
+
+			case "deployment":
+				for k, v := range chunk.Items {
+					var d pb.Deployment
+					if err := proto.Unmarshal(v, &d); err != nil {
+						return fmt.Errorf("error decoding deployment %q: %s", k, err)
+					}
+
+					var app string
+					if ref := d.Application; ref != nil {
+						app = ref.Project + "/" + ref.Application
+					}
+
+					deployments[k] = deployment{app: app, hash: sha256.Sum256(v)}
+				}
+			}
+
+			return nil
+		})
+
+		return header, projects, deployments, err
+	}
+
+	_, currentProjects, currentDeployments, err := read(current)
+	if err != nil {
+		return nil, fmt.Errorf("error reading current server data: %s", err)
+	}
+	header, incomingProjects, incomingDeployments, err := read(incoming)
+	if err != nil {
+		return nil, fmt.Errorf("error reading snapshot data: %s", err)
+	}
+
+	result := &snapshotImpact{
+		Version:     header.Version,
+		Deployments: map[string]*snapshotTypeDiff{},
+	}
+	for name := range incomingProjects {
+		if !currentProjects[name] {
+			result.ProjectsAdded = append(result.ProjectsAdded, name)
+		}
+	}
+	for name := range currentProjects {
+		if !incomingProjects[name] {
+			result.ProjectsRemoved = append(result.ProjectsRemoved, name)
+		}
+	}
+	sort.Strings(result.ProjectsAdded)
+	sort.Strings(result.ProjectsRemoved)
+
+	get := func(app string) *snapshotTypeDiff {
+		d, ok := result.Deployments[app]
+		if !ok {
+			d = &snapshotTypeDiff{}
+			result.Deployments[app] = d
+		}
+
+		return d
+	}
+	for k, d := range incomingDeployments {
+		old, ok := currentDeployments[k]
+		switch {
+		case !ok:
+			get(d.app).Added++
+		case old.hash != d.hash:
+			get(d.app).Changed++
+		}
+	}
+	for k, d := range currentDeployments {
+		if _, ok := incomingDeployments[k]; !ok {
+			get(d.app).Removed++
+		}
+	}
+
+	return result, nil
+}
+
+// checkDiff buffers the snapshot data in r to a temporary file and shows
+// the operator how restoring the snapshot with the options in preview
+// would change the server data.
+// The returned file contains the buffered snapshot data ready to be read
+// from the start. The caller must close and remove the file.
+func (c *SnapshotRestoreCommand) checkDiff(
+	client pb.WaypointClient,
+	r io.Reader,
+	preview *state.RestorePreview,
+) (*os.File, error) {
+	incoming, err := ioutil.TempFile("", "waypoint-restore")
+	if err != nil {
+		return nil, err
+	}
+
+	// closeErr cleans up the incoming data if we don't return it.
+	closeErr := func(err error) (*os.File, error) {
+		incoming.Close()
+		os.Remove(incoming.Name())
+		return nil, err
+	}
+
+	current, err := ioutil.TempFile("", "waypoint-current")
+	if err != nil {
+		return closeErr(err)
+	}
+	defer os.Remove(current.Name())
+	defer current.Close()
+
+	diff, err := c.diffCurrent(client, r, preview, incoming, current)
+	if err != nil {
+		return closeErr(err)
+	}
+
+	for _, f := range []*os.File{incoming, current} {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return closeErr(err)
+		}
+	}
+	impact, err := snapshotImpactOf(current, incoming)
+	if err != nil {
+		return closeErr(err)
+	}
+	if _, err := incoming.Seek(0, io.SeekStart); err != nil {
+		return closeErr(err)
+	}
+
+	// Check that the server understands the data in the snapshot. The
+	// server only checks the integrity of the data during the dry run.
+	resp, err := client.GetVersionInfo(c.Ctx, &empty.Empty{})
+	if err != nil {
+		return closeErr(err)
+	}
+	if err := checkSnapshotVersion(impact.Version, resp.Info); err != nil {
+		return closeErr(err)
+	}
+
+	c.ui.Output("Restoring this snapshot would make the following changes:", terminal.WithHeaderStyle())
+	c.ui.Table(snapshotDiffTable(diff))
+
+	c.ui.Output("")
+	c.ui.Output("Projects", terminal.WithHeaderStyle())
+	if len(impact.ProjectsAdded) == 0 && len(impact.ProjectsRemoved) == 0 {
+		c.ui.Output("No projects would be added or removed.")
+	}
+	for _, name := range impact.ProjectsAdded {
+		c.ui.Output("+ %s", name)
+	}
+	for _, name := range impact.ProjectsRemoved {
+		c.ui.Output("- %s", name)
+	}
+
+	c.ui.Output("")
+	c.ui.Output("Deployments", terminal.WithHeaderStyle())
+	if len(impact.Deployments) == 0 {
+		c.ui.Output("No deployments would be affected.")
+	} else {
+		var apps []string
+		for app := range impact.Deployments {
+			apps = append(apps, app)
+		}
+		sort.Strings(apps)
+
+		tbl := terminal.NewTable("Application", "Added", "Removed", "Changed")
+		for _, app := range apps {
+			d := impact.Deployments[app]
+			tbl.Rich([]string{
+				app,
+				strconv.Itoa(d.Added),
+				strconv.Itoa(d.Removed),
+				strconv.Itoa(d.Changed),
+			}, nil)
+		}
+		c.ui.Table(tbl)
+	}
+
+	return incoming, nil
+}
+
+// checkSnapshotVersion returns an error if a snapshot created by a server
+// with the snapshot version can't be restored to a server with the server
+// version. If the snapshot version isn't known, this assumes it is valid.
+func checkSnapshotVersion(snapshot, server *pb.VersionInfo) error {
+	if snapshot == nil || snapshot.Api == nil || server == nil || server.Api == nil {
+		return nil
+	}
+
+	if v := snapshot.Api.Current; v > server.Api.Current {
+		return fmt.Errorf(
+			"the snapshot was created by a newer server (API version %d) than "+
+				"this server supports (API version %d)", v, server.Api.Current)
+	}
+	if v := snapshot.Api.Current; v < server.Api.Minimum {
+		return fmt.Errorf(
+			"the snapshot was created by an older server (API version %d) than "+
+				"this server supports (API version %d or later)", v, server.Api.Minimum)
+	}
+
+	return nil
+}
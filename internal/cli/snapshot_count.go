@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"io"
+	"io/ioutil"
+	"sort"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// snapshotRecordCounter is an io.Writer that decodes the snapshot data
+// written to it and counts the number of records of each record type.
+// The counts are only complete once Close has been called.
+type snapshotRecordCounter struct {
+	pw     *io.PipeWriter
+	doneCh chan struct{}
+	counts map[string]int
+	err    error
+
+	// keys is the sorted list of record keys for each record type. This
+	// is only populated if keys were requested when creating the counter.
+	keys map[string][]string
+}
+
+// newSnapshotRecordCounter creates a counter. If keys is true, the key of
+// every record is also recorded and available via Keys after Close.
+func newSnapshotRecordCounter(keys bool) *snapshotRecordCounter {
+	pr, pw := io.Pipe()
+	c := &snapshotRecordCounter{
+		pw:     pw,
+		doneCh: make(chan struct{}),
+		counts: map[string]int{},
+	}
+	if keys {
+		c.keys = map[string][]string{}
+	}
+
+	go c.run(pr)
+	return c
+}
+
+func (c *snapshotRecordCounter) Write(p []byte) (int, error) {
+	return c.pw.Write(p)
+}
+
+// Close finishes counting and returns the number of records for each
+// record type. If the snapshot couldn't be decoded, an error is returned
+// along with any counts that were made before the error.
+func (c *snapshotRecordCounter) Close() (map[string]int, error) {
+	c.pw.Close()
+	<-c.doneCh
+	return c.counts, c.err
+}
+
+// Keys returns the sorted record keys for each record type. This is only
+// valid after Close and if keys were requested when creating the counter.
+func (c *snapshotRecordCounter) Keys() map[string][]string {
+	return c.keys
+}
+
+func (c *snapshotRecordCounter) run(r io.Reader) {
+	defer close(c.doneCh)
+
+	// Always drain the reader so that writers never block, even if we
+	// fail to decode the data.
+	defer io.Copy(ioutil.Discard, r)
+
+	_, c.err = decodeSnapshot(r, func(chunk *pb.Snapshot_BoltChunk) error {
+		c.counts[chunk.Bucket] += len(chunk.Items)
+		if c.keys != nil {
+			for k := range chunk.Items {
+				c.keys[chunk.Bucket] = append(c.keys[chunk.Bucket], k)
+			}
+		}
+
+		return nil
+	})
+
+	for _, keys := range c.keys {
+		sort.Strings(keys)
+	}
+}
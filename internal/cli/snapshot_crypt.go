@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"errors"
+	"io"
+
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	"github.com/hashicorp/waypoint/internal/pkg/snapshotcrypt"
+)
+
+// snapshotKeyFlags are the flags for the key to encrypt or decrypt
+// snapshots with.
+type snapshotKeyFlags struct {
+	key     string
+	keyFile string
+}
+
+func (f *snapshotKeyFlags) addFlags(set *flag.Set) {
+	set.StringVar(&flag.StringVar{
+		Name:   "encrypt-key",
+		Target: &f.key,
+		Usage: "Base64-encoded 32 byte key to encrypt or decrypt the snapshot with " +
+			"using AES-256-GCM. The same key must be used to restore the snapshot.",
+	})
+
+	set.StringVar(&flag.StringVar{
+		Name:   "encrypt-key-file",
+		Target: &f.keyFile,
+		Usage:  "Path to a file containing the key for -encrypt-key.",
+	})
+}
+
+// Key returns the key from the flags, or nil if no key was given.
+func (f *snapshotKeyFlags) Key() ([]byte, error) {
+	switch {
+	case f.key != "" && f.keyFile != "":
+		return nil, errors.New("only one of -encrypt-key and -encrypt-key-file may be set")
+
+	case f.key != "":
+		return snapshotcrypt.ParseKey(f.key)
+
+	case f.keyFile != "":
+		return snapshotcrypt.ReadKeyFile(f.keyFile)
+
+	default:
+		return nil, nil
+	}
+}
+
+// decryptSnapshot returns a reader that decrypts r with key. If key is
+// nil, r is returned unmodified.
+func decryptSnapshot(r io.Reader, key []byte) (io.Reader, error) {
+	if key == nil {
+		return r, nil
+	}
+
+	dr, err := snapshotcrypt.NewReader(r, key)
+	if err == snapshotcrypt.ErrNotEncrypted {
+		return nil, errors.New("snapshot is not encrypted, remove the encryption key flags")
+	}
+
+	return dr, err
+}
@@ -0,0 +1,380 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hashicorp/waypoint/internal/pkg/protowriter"
+	"github.com/hashicorp/waypoint/internal/pkg/snapshotsum"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// snapshotData is a snapshot decoded into memory. This is used for
+// operations that need random access to the records in a snapshot, such
+// as merging or creating incremental snapshots.
+type snapshotData struct {
+	Header *pb.Snapshot_Header
+
+	// ID is the ID of the snapshot, which is the checksum in its trailer.
+	// This is only set for snapshots that were read.
+	ID string
+
+	// Items are the records in the snapshot by record type and key.
+	Items map[string]map[string][]byte
+
+	// Deleted are the keys deleted since the parent snapshot by record
+	// type. This is only set for incremental snapshots.
+	Deleted map[string][]string
+}
+
+func newSnapshotData(header *pb.Snapshot_Header) *snapshotData {
+	return &snapshotData{
+		Header:  header,
+		Items:   map[string]map[string][]byte{},
+		Deleted: map[string][]string{},
+	}
+}
+
+// Put sets the value of a record.
+func (d *snapshotData) Put(bucket, key string, value []byte) {
+	m, ok := d.Items[bucket]
+	if !ok {
+		m = map[string][]byte{}
+		d.Items[bucket] = m
+	}
+
+	m[key] = value
+}
+
+// readSnapshotFile reads the snapshot at path, decrypting it with key if
+// key is non-nil.
+func readSnapshotFile(path string, key []byte) (*snapshotData, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r, err := decryptSnapshot(f, key)
+	if err == nil {
+		r, err = unwrapSnapshot(r)
+	}
+	if err == nil {
+		var d *snapshotData
+		d, err = readSnapshot(r)
+		if err == nil {
+			return d, nil
+		}
+	}
+
+	return nil, fmt.Errorf("error reading snapshot %q: %s", path, err)
+}
+
+// readSnapshot reads the snapshot in r into memory and verifies its
+// checksums.
+func readSnapshot(r io.Reader) (*snapshotData, error) {
+	var d *snapshotData
+	header, id, err := scanSnapshot(r, func(header *pb.Snapshot_Header, chunk *pb.Snapshot_BoltChunk) error {
+		if d == nil {
+			d = newSnapshotData(header)
+		}
+
+		if _, ok := d.Items[chunk.Bucket]; !ok {
+			d.Items[chunk.Bucket] = map[string][]byte{}
+		}
+		for k, v := range chunk.Items {
+			d.Put(chunk.Bucket, k, v)
+		}
+		d.Deleted[chunk.Bucket] = append(d.Deleted[chunk.Bucket], chunk.DeletedKeys...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if d == nil {
+		d = newSnapshotData(header)
+	}
+	d.ID = id
+	return d, nil
+}
+
+// scanSnapshot reads the snapshot in r, calling fn for every bolt chunk up
+// to but not including the final chunk. The checksum of each chunk is
+// verified before fn is called with it, and the checksum of the whole
+// snapshot is verified once all chunks are read. This returns the header
+// and the ID of the snapshot. If an error is returned, fn may have been
+// called with data from a corrupted snapshot.
+func scanSnapshot(
+	r io.Reader,
+	fn func(*pb.Snapshot_Header, *pb.Snapshot_BoltChunk) error,
+) (*pb.Snapshot_Header, string, error) {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, "", err
+	}
+	defer gzr.Close()
+
+	// The checksum is of the raw proto bytes. We only hash the bytes
+	// consumed by the message reader, so we hash as we buffer.
+	checksum := sha256.New()
+	const maxSize = 4096 * 1024 // 4MB, same as the server
+	br := bufio.NewReader(gzr)
+	dr := protowriter.NewDelimitedReader(&hashedByteReader{r: br, h: checksum}, maxSize)
+
+	var header pb.Snapshot_Header
+	if err := dr.ReadMsg(&header); err != nil {
+		return nil, "", err
+	}
+	if header.Format != pb.Snapshot_Header_BOLT {
+		return nil, "", fmt.Errorf("unsupported snapshot format %s", header.Format)
+	}
+
+	for {
+		var chunk pb.Snapshot_BoltChunk
+		if err := dr.ReadMsg(&chunk); err != nil {
+			if err == io.EOF {
+				err = errors.New("snapshot is truncated")
+			}
+
+			return nil, "", err
+		}
+		if err := snapshotsum.Verify(chunk.Sha256, chunk.Bucket, chunk.Items, chunk.DeletedKeys); err != nil {
+			return nil, "", err
+		}
+
+		if chunk.Final {
+			break
+		}
+
+		if err := fn(&header, &chunk); err != nil {
+			return nil, "", err
+		}
+	}
+
+	// The checksum is of everything up to but not including the trailer.
+	sum := hex.EncodeToString(checksum.Sum(nil))
+
+	var trailer pb.Snapshot_Trailer
+	if err := dr.ReadMsg(&trailer); err != nil {
+		if err == io.EOF {
+			err = errors.New("snapshot is truncated")
+		}
+
+		return nil, "", err
+	}
+	v, ok := trailer.Checksum.(*pb.Snapshot_Trailer_Sha256)
+	if !ok {
+		return nil, "", fmt.Errorf("unknown snapshot checksum type")
+	}
+	if !strings.EqualFold(v.Sha256, sum) {
+		return nil, "", fmt.Errorf("checksum mismatch, expected %s got %s", v.Sha256, sum)
+	}
+
+	return &header, sum, nil
+}
+
+// writeSnapshotData writes the snapshot in the same format as the server
+// and returns its ID. Records are written in sorted order.
+func writeSnapshotData(w io.Writer, d *snapshotData) (string, error) {
+	checksum := sha256.New()
+	gzw := gzip.NewWriter(w)
+	dw := protowriter.NewDelimitedWriter(io.MultiWriter(gzw, checksum))
+	if err := dw.WriteMsg(d.Header); err != nil {
+		return "", err
+	}
+
+	writeChunk := func(chunk *pb.Snapshot_BoltChunk) error {
+		chunk.Sha256 = snapshotsum.Chunk(chunk.Bucket, chunk.Items, chunk.DeletedKeys)
+		return dw.WriteMsg(chunk)
+	}
+
+	var buckets []string
+	for b := range d.Items {
+		buckets = append(buckets, b)
+	}
+	sort.Strings(buckets)
+
+	for _, b := range buckets {
+		var keys []string
+		for k := range d.Items[b] {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		const chunkLenMax = 1024 * 1024 // 1 MB, same as the server
+		chunk := &pb.Snapshot_BoltChunk{Bucket: b, Items: map[string][]byte{}}
+		chunkLen := 0
+		for _, k := range keys {
+			v := d.Items[b][k]
+			if len(v)+chunkLen > chunkLenMax && len(chunk.Items) > 0 {
+				if err := writeChunk(chunk); err != nil {
+					return "", err
+				}
+
+				chunk = &pb.Snapshot_BoltChunk{Bucket: b, Items: map[string][]byte{}}
+				chunkLen = 0
+			}
+
+			chunk.Items[k] = v
+			chunkLen += len(v)
+		}
+
+		if err := writeChunk(chunk); err != nil {
+			return "", err
+		}
+	}
+
+	// Deletions are written after all the items in their own chunks.
+	buckets = buckets[:0]
+	for b, keys := range d.Deleted {
+		if len(keys) > 0 {
+			buckets = append(buckets, b)
+		}
+	}
+	sort.Strings(buckets)
+	for _, b := range buckets {
+		keys := append([]string(nil), d.Deleted[b]...)
+		sort.Strings(keys)
+		if err := writeChunk(&pb.Snapshot_BoltChunk{
+			Bucket:      b,
+			DeletedKeys: keys,
+		}); err != nil {
+			return "", err
+		}
+	}
+
+	if err := dw.WriteMsg(&pb.Snapshot_BoltChunk{Final: true}); err != nil {
+		return "", err
+	}
+
+	id := hex.EncodeToString(checksum.Sum(nil))
+	if err := dw.WriteMsg(&pb.Snapshot_Trailer{
+		Checksum: &pb.Snapshot_Trailer_Sha256{
+			Sha256: id,
+		},
+	}); err != nil {
+		return "", err
+	}
+
+	return id, gzw.Close()
+}
+
+// hashedByteReader is an io.Reader and io.ByteReader that writes all the
+// bytes read to a hash.
+type hashedByteReader struct {
+	r *bufio.Reader
+	h io.Writer
+}
+
+func (r *hashedByteReader) ReadByte() (byte, error) {
+	b, err := r.r.ReadByte()
+	if err == nil {
+		r.h.Write([]byte{b})
+	}
+
+	return b, err
+}
+
+func (r *hashedByteReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.h.Write(p[:n])
+	return n, err
+}
+
+// diffSnapshotData returns an incremental snapshot containing the changes
+// from parent to current.
+func diffSnapshotData(parent, current *snapshotData) *snapshotData {
+	header := proto.Clone(current.Header).(*pb.Snapshot_Header)
+	header.ParentId = parent.ID
+	result := newSnapshotData(header)
+
+	for b, items := range current.Items {
+		old := parent.Items[b]
+		for k, v := range items {
+			if ov, ok := old[k]; !ok || !bytes.Equal(ov, v) {
+				result.Put(b, k, v)
+			}
+		}
+	}
+
+	for b, items := range parent.Items {
+		for k := range items {
+			if _, ok := current.Items[b][k]; !ok {
+				result.Deleted[b] = append(result.Deleted[b], k)
+			}
+		}
+	}
+
+	return result
+}
+
+// applySnapshotChain reads the full snapshot at the first path and applies
+// the incremental snapshots at the remaining paths in order. Each
+// incremental snapshot must have the previous snapshot as its parent. The
+// ID of the result is the ID of the last snapshot in the chain.
+func applySnapshotChain(paths []string, key []byte) (*snapshotData, error) {
+	var result *snapshotData
+	for i, path := range paths {
+		d, err := readSnapshotFile(path, key)
+		if err != nil {
+			return nil, err
+		}
+
+		if i == 0 {
+			if d.Header.ParentId != "" {
+				return nil, fmt.Errorf(
+					"snapshot %q is incremental, the first snapshot must be a full snapshot", path)
+			}
+
+			result = d
+			continue
+		}
+
+		if err := applyIncremental(result, d); err != nil {
+			return nil, fmt.Errorf("error applying snapshot %q: %s", path, err)
+		}
+	}
+
+	return result, nil
+}
+
+// applyIncremental applies the incremental snapshot inc to base in place.
+// inc must have base as its parent.
+func applyIncremental(base, inc *snapshotData) error {
+	if inc.Header.ParentId == "" {
+		return fmt.Errorf("snapshot is not incremental")
+	}
+	if inc.Header.ParentId != base.ID {
+		return fmt.Errorf("snapshot has parent %s but is being applied to %s",
+			inc.Header.ParentId, base.ID)
+	}
+
+	for b, keys := range inc.Deleted {
+		for _, k := range keys {
+			delete(base.Items[b], k)
+		}
+	}
+	for b, items := range inc.Items {
+		for k, v := range items {
+			base.Put(b, k, v)
+		}
+	}
+
+	// The result is a full snapshot with the header of the latest snapshot.
+	header := proto.Clone(inc.Header).(*pb.Snapshot_Header)
+	header.ParentId = ""
+	base.Header = header
+	base.ID = inc.ID
+	return nil
+}
@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+
+	"github.com/hashicorp/waypoint/internal/pkg/protowriter"
+	"github.com/hashicorp/waypoint/internal/pkg/snapshotcrypt"
+	"github.com/hashicorp/waypoint/internal/pkg/snapshotsum"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// decodeSnapshot decodes the snapshot data from r, calling fn for every
+// bolt chunk up to but not including the final chunk. The header is
+// returned once all chunks have been read. The checksum of each chunk is
+// verified, but this does not verify the checksum of the whole snapshot;
+// use scanSnapshot for that.
+func decodeSnapshot(r io.Reader, fn func(*pb.Snapshot_BoltChunk) error) (*pb.Snapshot_Header, error) {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gzr.Close()
+
+	const maxSize = 4096 * 1024 // 4MB, same as the server
+	dr := protowriter.NewDelimitedReader(bufio.NewReader(gzr), maxSize)
+
+	var header pb.Snapshot_Header
+	if err := dr.ReadMsg(&header); err != nil {
+		return nil, err
+	}
+
+	for {
+		var chunk pb.Snapshot_BoltChunk
+		if err := dr.ReadMsg(&chunk); err != nil {
+			return nil, err
+		}
+		if err := snapshotsum.Verify(chunk.Sha256, chunk.Bucket, chunk.Items, chunk.DeletedKeys); err != nil {
+			return nil, err
+		}
+
+		if chunk.Final {
+			return &header, nil
+		}
+
+		if err := fn(&chunk); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// peekSnapshotHeader reads the header of the snapshot data in r without
+// consuming it. The returned reader must be used in place of r. If the
+// header can't be read, the header is nil and it is left to whatever reads
+// the data to report the problem.
+func peekSnapshotHeader(r io.Reader) (*pb.Snapshot_Header, io.Reader, error) {
+	// The header is the first message so it is always near the start.
+	const peekSize = 64 * 1024
+	br := bufio.NewReaderSize(r, peekSize)
+	data, err := br.Peek(peekSize)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, nil, err
+	}
+
+	gzr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, br, nil
+	}
+
+	var header pb.Snapshot_Header
+	dr := protowriter.NewDelimitedReader(bufio.NewReader(gzr), peekSize)
+	if err := dr.ReadMsg(&header); err != nil {
+		return nil, br, nil
+	}
+
+	return &header, br, nil
+}
+
+// gzipMagic are the first bytes of gzip-compressed data.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// unwrapSnapshot detects snapshot data that was compressed again after it
+// was written, such as with `gzip snapshot.bak`, and returns a reader
+// that removes the extra compression. Snapshot data is always gzip
+// compressed, so this looks for gzip data within gzip data. Any other
+// data is returned unmodified, except for encrypted data which must be
+// decrypted first.
+func unwrapSnapshot(r io.Reader) (io.Reader, error) {
+	// Peek enough data to decompress the start of the inner stream.
+	const peekSize = 64 * 1024
+	br := bufio.NewReaderSize(r, peekSize)
+	data, err := br.Peek(peekSize)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+	if snapshotcrypt.IsEncrypted(data) {
+		return nil, errors.New("snapshot is encrypted, specify -encrypt-key or -encrypt-key-file")
+	}
+	if !bytes.HasPrefix(data, gzipMagic) {
+		return br, nil
+	}
+
+	gzr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return br, nil
+	}
+
+	var inner [2]byte
+	if _, err := io.ReadFull(gzr, inner[:]); err != nil || !bytes.Equal(inner[:], gzipMagic) {
+		return br, nil
+	}
+
+	return gzip.NewReader(br)
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type SnapshotDeleteCommand struct {
+	*baseCommand
+}
+
+func (c *SnapshotDeleteCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	if len(c.args) == 0 {
+		c.ui.Output("At least one snapshot name is required.\n\n"+c.Help(),
+			terminal.WithErrorStyle())
+		return 1
+	}
+
+	for _, name := range c.args {
+		_, err := c.project.Client().DeleteSnapshot(c.Ctx, &pb.DeleteSnapshotRequest{
+			Name: name,
+		})
+		if err != nil {
+			c.ui.Output("Error deleting snapshot %q: %s", name, clierrors.Humanize(err),
+				terminal.WithErrorStyle())
+			return 1
+		}
+
+		c.ui.Output("Snapshot %q deleted.", name, terminal.WithSuccessStyle())
+	}
+
+	return 0
+}
+
+func (c *SnapshotDeleteCommand) Flags() *flag.Sets {
+	return c.flagSet(0, nil)
+}
+
+func (c *SnapshotDeleteCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *SnapshotDeleteCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *SnapshotDeleteCommand) Synopsis() string {
+	return "Delete snapshots stored on the server."
+}
+
+func (c *SnapshotDeleteCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint server snapshot-delete NAME...
+
+	Delete one or more snapshots stored on the server. The names are those
+	shown by "waypoint server snapshot-list".
+
+`)
+}
@@ -0,0 +1,329 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	"github.com/hashicorp/waypoint/internal/server/singleprocess/state"
+)
+
+// snapshotTypeDiff is the difference between two snapshots for the
+// records of a single record type.
+type snapshotTypeDiff struct {
+	Added     int
+	Removed   int
+	Changed   int
+	Unchanged int
+}
+
+// diffSnapshots compares the records in the current snapshot with the
+// records that restoring the incoming snapshot with the given preview's
+// options would result in, and returns the differences keyed by record
+// type.
+func diffSnapshots(
+	current, incoming io.Reader,
+	preview *state.RestorePreview,
+) (map[string]*snapshotTypeDiff, error) {
+	const projectBucket = "project"
+
+	type currentRecord struct {
+		sum  [sha256.Size]byte
+		keep bool
+	}
+
+	// We only store a hash of each current value so that we don't need to
+	// hold two full copies of the data in memory. Projects are merged with
+	// the incoming projects so we store those in full.
+	existing := map[string]map[string]currentRecord{}
+	currentProjects := map[string][]byte{}
+	if _, err := decodeSnapshot(current, func(chunk *pb.Snapshot_BoltChunk) error {
+		if chunk.Bucket == projectBucket {
+			for k, v := range chunk.Items {
+				currentProjects[k] = v
+			}
+
+			return nil
+		}
+
+		m, ok := existing[chunk.Bucket]
+		if !ok {
+			m = map[string]currentRecord{}
+			existing[chunk.Bucket] = m
+		}
+
+		for k, v := range chunk.Items {
+			keep, err := preview.Keeps(chunk.Bucket, v)
+			if err != nil {
+				return err
+			}
+
+			m[k] = currentRecord{sum: sha256.Sum256(v), keep: keep}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("error reading current server data: %s", err)
+	}
+
+	result := map[string]*snapshotTypeDiff{}
+	get := func(t string) *snapshotTypeDiff {
+		d, ok := result[t]
+		if !ok {
+			d = &snapshotTypeDiff{}
+			result[t] = d
+		}
+
+		return d
+	}
+
+	incomingProjects := map[string][]byte{}
+	if _, err := decodeSnapshot(incoming, func(chunk *pb.Snapshot_BoltChunk) error {
+		d := get(chunk.Bucket)
+		m := existing[chunk.Bucket]
+		for k, v := range chunk.Items {
+			k, v, ok, err := preview.Restores(chunk.Bucket, k, v)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			if chunk.Bucket == projectBucket {
+				incomingProjects[k] = v
+				continue
+			}
+
+			old, ok := m[k]
+			switch {
+			case !ok:
+				d.Added++
+			case old.keep:
+				// Counted with the rest of the kept records below.
+				continue
+			case old.sum != sha256.Sum256(v):
+				d.Changed++
+			default:
+				d.Unchanged++
+			}
+
+			delete(m, k)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("error reading snapshot data: %s", err)
+	}
+
+	// Anything remaining in the current data is either kept or is not in
+	// the snapshot.
+	for t, m := range existing {
+		for _, r := range m {
+			if r.keep {
+				get(t).Unchanged++
+			} else {
+				get(t).Removed++
+			}
+		}
+	}
+
+	// Projects are compared last since they may be merged with the
+	// current projects.
+	projects := map[string]bool{}
+	for k := range currentProjects {
+		projects[k] = true
+	}
+	for k := range incomingProjects {
+		projects[k] = true
+	}
+	for k := range projects {
+		old, exists := currentProjects[k]
+		v, err := preview.Project(old, incomingProjects[k])
+		if err != nil {
+			return nil, err
+		}
+
+		d := get(projectBucket)
+		switch {
+		case !exists && v == nil:
+			// Not restored, such as a project outside of the scope.
+		case !exists:
+			d.Added++
+		case v == nil:
+			d.Removed++
+		case sha256.Sum256(old) != sha256.Sum256(v):
+			d.Changed++
+		default:
+			d.Unchanged++
+		}
+	}
+
+	return result, nil
+}
+
+// restorePreview returns a preview of the restore with the same options
+// that are sent to the server, so that the diff only shows the records
+// the restore will change.
+func restorePreview(
+	skipTypes, projects, apps []string,
+	rename map[string]string,
+) (*state.RestorePreview, error) {
+	opts := []state.RestoreOption{state.WithRestoreSkipRecordTypes(skipTypes...)}
+	if len(projects) > 0 {
+		opts = append(opts, state.WithRestoreProjects(projects...))
+	}
+	if len(apps) > 0 {
+		refs := make([]*pb.Ref_Application, len(apps))
+		for i, app := range apps {
+			idx := strings.Index(app, "/")
+			if idx <= 0 || idx == len(app)-1 {
+				return nil, fmt.Errorf("app %q must be in the form <project>/<app>", app)
+			}
+
+			refs[i] = &pb.Ref_Application{
+				Project:     app[:idx],
+				Application: app[idx+1:],
+			}
+		}
+
+		opts = append(opts, state.WithRestoreApps(refs...))
+	}
+	if len(rename) > 0 {
+		opts = append(opts, state.WithRestoreRenameProjects(rename))
+	}
+
+	return state.NewRestorePreview(opts...)
+}
+
+// confirmDiff buffers the snapshot data in r to a temporary file, shows
+// the operator how restoring the snapshot with the options in preview
+// changes the current server data, and asks them to confirm the restore. If they confirm, the returned
+// file contains the buffered snapshot data ready to be read from the
+// start so that the data only needs to be read and sent once. The caller
+// must close and remove the file.
+func (c *SnapshotRestoreCommand) confirmDiff(
+	client pb.WaypointClient,
+	r io.Reader,
+	preview *state.RestorePreview,
+) (*os.File, bool, error) {
+	incoming, err := ioutil.TempFile("", "waypoint-restore")
+	if err != nil {
+		return nil, false, err
+	}
+
+	// closeErr cleans up the incoming data if we don't return it.
+	closeErr := func(err error) (*os.File, bool, error) {
+		incoming.Close()
+		os.Remove(incoming.Name())
+		return nil, false, err
+	}
+
+	current, err := ioutil.TempFile("", "waypoint-current")
+	if err != nil {
+		return closeErr(err)
+	}
+	defer os.Remove(current.Name())
+	defer current.Close()
+
+	diff, err := c.diffCurrent(client, r, preview, incoming, current)
+	if err != nil {
+		return closeErr(err)
+	}
+
+	c.ui.Output("Restoring this snapshot will make the following changes:", terminal.WithHeaderStyle())
+	c.ui.Table(snapshotDiffTable(diff))
+
+	for {
+		result, err := c.ui.Input(&terminal.Input{
+			Prompt: "Continue with the restore? [y/n]",
+			Style:  terminal.WarningBoldStyle,
+		})
+		if err != nil {
+			return closeErr(err)
+		}
+
+		switch result {
+		case "y":
+			return incoming, true, nil
+		case "n":
+			return closeErr(nil)
+		}
+	}
+}
+
+// diffCurrent copies the snapshot data from r into incoming, writes a
+// snapshot of the current server data to current, and diffs them using
+// the options in preview. On
+// success, incoming is positioned at the start of the data.
+func (c *SnapshotRestoreCommand) diffCurrent(
+	client pb.WaypointClient,
+	r io.Reader,
+	preview *state.RestorePreview,
+	incoming *os.File,
+	current *os.File,
+) (map[string]*snapshotTypeDiff, error) {
+	sg := c.ui.StepGroup()
+	defer sg.Wait()
+
+	step := sg.Add("Comparing snapshot with current server data...")
+	defer step.Abort()
+
+	if _, err := io.Copy(incoming, r); err != nil {
+		return nil, fmt.Errorf("error reading snapshot data: %s", err)
+	}
+	if err := writeSnapshot(c.Ctx, client, current); err != nil {
+		return nil, err
+	}
+	for _, f := range []*os.File{incoming, current} {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+
+	diff, err := diffSnapshots(current, incoming, preview)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := incoming.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	step.Update("Compared snapshot with current server data")
+	step.Done()
+	return diff, nil
+}
+
+// snapshotDiffTable returns a table of the differences for each record
+// type, skipping record types that are in neither snapshot.
+func snapshotDiffTable(diff map[string]*snapshotTypeDiff) *terminal.Table {
+	var types []string
+	for t := range diff {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	tbl := terminal.NewTable("Record Type", "Added", "Removed", "Changed", "Unchanged")
+	for _, t := range types {
+		d := diff[t]
+		if d.Added+d.Removed+d.Changed+d.Unchanged == 0 {
+			continue
+		}
+
+		tbl.Rich([]string{
+			t,
+			strconv.Itoa(d.Added),
+			strconv.Itoa(d.Removed),
+			strconv.Itoa(d.Changed),
+			strconv.Itoa(d.Unchanged),
+		}, nil)
+	}
+
+	return tbl
+}
@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/waypoint/internal/pkg/protowriter"
+	"github.com/hashicorp/waypoint/internal/pkg/snapshotsum"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	project := func(name string, apps ...string) proto.Message {
+		p := &pb.Project{Name: name}
+		for _, app := range apps {
+			p.Applications = append(p.Applications, &pb.Application{
+				Project: &pb.Ref_Project{Project: name},
+				Name:    app,
+			})
+		}
+
+		return p
+	}
+	appRef := func(project, app string) *pb.Ref_Application {
+		return &pb.Ref_Application{Project: project, Application: app}
+	}
+
+	current := testSnapshot(t, map[string]map[string]proto.Message{
+		"project": {
+			"b": project("B", "web", "api"),
+		},
+		"deployment": {
+			"d-bweb-old": &pb.Deployment{Id: "d-bweb-old", Application: appRef("B", "web")},
+			"d-bapi":     &pb.Deployment{Id: "d-bapi", Application: appRef("B", "api")},
+		},
+		"config": {
+			"B/web/PORT": &pb.ConfigVar{
+				Name:  "PORT",
+				Scope: &pb.ConfigVar_Application{Application: appRef("B", "web")},
+				Value: &pb.ConfigVar_Static{Static: "1"},
+			},
+		},
+		"jobs": {
+			"j1": &pb.Job{Id: "j1", Application: appRef("B", "web")},
+		},
+	})
+
+	incoming := testSnapshot(t, map[string]map[string]proto.Message{
+		"project": {
+			"a": project("A", "web"),
+			"b": project("B", "web", "api"),
+		},
+		"deployment": {
+			"d-aweb":     &pb.Deployment{Id: "d-aweb", Application: appRef("A", "web")},
+			"d-bweb-new": &pb.Deployment{Id: "d-bweb-new", Application: appRef("B", "web")},
+			"d-bapi":     &pb.Deployment{Id: "d-bapi", Application: appRef("B", "api")},
+		},
+		"config": {
+			"B/web/PORT": &pb.ConfigVar{
+				Name:  "PORT",
+				Scope: &pb.ConfigVar_Application{Application: appRef("B", "web")},
+				Value: &pb.ConfigVar_Static{Static: "2"},
+			},
+			"A/web/X": &pb.ConfigVar{
+				Name:  "X",
+				Scope: &pb.ConfigVar_Application{Application: appRef("A", "web")},
+				Value: &pb.ConfigVar_Static{Static: "x"},
+			},
+		},
+		"jobs": {
+			"j2": &pb.Job{Id: "j2", Application: appRef("A", "web")},
+		},
+	})
+
+	cases := []struct {
+		Name      string
+		SkipTypes []string
+		Projects  []string
+		Apps      []string
+		Rename    map[string]string
+		Expected  map[string]*snapshotTypeDiff
+	}{
+		{
+			"full restore",
+			nil,
+			nil,
+			nil,
+			nil,
+			map[string]*snapshotTypeDiff{
+				"project":    {Added: 1, Unchanged: 1},
+				"deployment": {Added: 2, Removed: 1, Unchanged: 1},
+				"config":     {Added: 1, Changed: 1},
+				"jobs":       {Added: 1, Removed: 1},
+			},
+		},
+
+		{
+			// A is restored as C alongside B's web app. B's api app and
+			// all config are kept as they are.
+			"skip, scope and rename",
+			[]string{"config"},
+			nil,
+			[]string{"B/web"},
+			map[string]string{"A": "C"},
+			map[string]*snapshotTypeDiff{
+				"project":    {Added: 1, Changed: 1},
+				"deployment": {Added: 2, Removed: 1, Unchanged: 1},
+				"config":     {Unchanged: 1},
+				"jobs":       {Added: 1, Removed: 1},
+			},
+		},
+
+		{
+			"scope to a project",
+			nil,
+			[]string{"A"},
+			nil,
+			nil,
+			map[string]*snapshotTypeDiff{
+				"project":    {Added: 1, Unchanged: 1},
+				"deployment": {Added: 1, Unchanged: 2},
+				"config":     {Added: 1, Unchanged: 1},
+				"jobs":       {Added: 1, Unchanged: 1},
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			require := require.New(t)
+
+			preview, err := restorePreview(tt.SkipTypes, tt.Projects, tt.Apps, tt.Rename)
+			require.NoError(err)
+
+			diff, err := diffSnapshots(
+				bytes.NewReader(current), bytes.NewReader(incoming), preview)
+			require.NoError(err)
+			require.Equal(tt.Expected, diff)
+		})
+	}
+
+	t.Run("invalid app", func(t *testing.T) {
+		require := require.New(t)
+
+		_, err := restorePreview(nil, nil, []string{"web"}, nil)
+		require.Error(err)
+	})
+}
+
+// testSnapshot returns snapshot data with the given records keyed by
+// record type and then by key.
+func testSnapshot(t *testing.T, records map[string]map[string]proto.Message) []byte {
+	require := require.New(t)
+
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	dw := protowriter.NewDelimitedWriter(gzw)
+	require.NoError(dw.WriteMsg(&pb.Snapshot_Header{
+		Format: pb.Snapshot_Header_BOLT,
+	}))
+
+	for bucket, msgs := range records {
+		items := map[string][]byte{}
+		for k, msg := range msgs {
+			v, err := proto.Marshal(msg)
+			require.NoError(err)
+			items[k] = v
+		}
+
+		require.NoError(dw.WriteMsg(&pb.Snapshot_BoltChunk{
+			Bucket: bucket,
+			Items:  items,
+			Sha256: snapshotsum.Chunk(bucket, items, nil),
+		}))
+	}
+
+	require.NoError(dw.WriteMsg(&pb.Snapshot_BoltChunk{Final: true}))
+	require.NoError(gzw.Close())
+	return buf.Bytes()
+}
@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/dustin/go-humanize"
+	"github.com/golang/protobuf/proto"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type SnapshotInspectCommand struct {
+	*baseCommand
+
+	// set via -encrypt-key and -encrypt-key-file, the key to decrypt the
+	// snapshot with.
+	flagKey snapshotKeyFlags
+}
+
+func (c *SnapshotInspectCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+		WithClient(false),
+	); err != nil {
+		return 1
+	}
+
+	key, err := c.flagKey.Key()
+	if err != nil {
+		c.ui.Output("Invalid encryption key: %s", err, terminal.WithErrorStyle())
+		return 1
+	}
+
+	r, closer, err := openSnapshotReader(c.Ctx, c.args, c.nonInteractive())
+	if err != nil {
+		c.outputError("Failed to open snapshot", err)
+		return 1
+	}
+	if closer != nil {
+		defer closer.Close()
+	}
+
+	// Count the bytes we read so that we can report the size of the
+	// snapshot, even when reading from stdin.
+	src := newReadCounter(r)
+	r, err = decryptSnapshot(src, key)
+	if err == nil {
+		r, err = unwrapSnapshot(r)
+	}
+	var d *snapshotData
+	if err == nil {
+		d, err = readSnapshot(r)
+	}
+	if err != nil {
+		c.ui.Output("Error reading snapshot: %s", clierrors.Humanize(err),
+			terminal.WithErrorStyle())
+		return 1
+	}
+
+	// Drain anything left so the size is accurate.
+	if _, err := io.Copy(ioutil.Discard, src); err != nil {
+		c.ui.Output("Error reading snapshot: %s", err, terminal.WithErrorStyle())
+		return 1
+	}
+
+	values := []terminal.NamedValue{
+		{Name: "ID", Value: d.ID},
+		{Name: "Size", Value: humanize.Bytes(uint64(src.n))},
+	}
+	if v := d.Header.Version; v != nil {
+		values = append(values, terminal.NamedValue{Name: "Server version", Value: v.Version})
+	}
+	if v := d.Header.SchemaVersion; v > 0 {
+		values = append(values, terminal.NamedValue{Name: "Schema version", Value: v})
+	}
+	if f, ok := closer.(*os.File); ok {
+		// Snapshots don't record when they were taken, so the file
+		// modification time is the best we have.
+		if fi, err := f.Stat(); err == nil {
+			values = append(values, terminal.NamedValue{
+				Name: "Modified", Value: fi.ModTime().Format("2006-01-02 15:04:05 MST"),
+			})
+		}
+	}
+	if d.Header.ParentId != "" {
+		values = append(values, terminal.NamedValue{Name: "Incremental from", Value: d.Header.ParentId})
+	}
+
+	projects, apps, err := snapshotProjectCounts(d)
+	if err != nil {
+		c.ui.Output("Error reading projects: %s", err, terminal.WithWarningStyle())
+	} else {
+		values = append(values,
+			terminal.NamedValue{Name: "Projects", Value: projects},
+			terminal.NamedValue{Name: "Applications", Value: apps},
+		)
+	}
+
+	c.ui.Output("Snapshot", terminal.WithHeaderStyle())
+	c.ui.NamedValues(values)
+
+	var buckets []string
+	for b := range d.Items {
+		buckets = append(buckets, b)
+	}
+	for b := range d.Deleted {
+		if _, ok := d.Items[b]; !ok && len(d.Deleted[b]) > 0 {
+			buckets = append(buckets, b)
+		}
+	}
+	sort.Strings(buckets)
+
+	tbl := terminal.NewTable("Record Type", "Records", "Deleted")
+	for _, b := range buckets {
+		tbl.Rich([]string{
+			b,
+			strconv.Itoa(len(d.Items[b])),
+			strconv.Itoa(len(d.Deleted[b])),
+		}, nil)
+	}
+
+	c.ui.Output("")
+	c.ui.Output("Records", terminal.WithHeaderStyle())
+	c.ui.Table(tbl)
+
+	return 0
+}
+
+// snapshotProjectCounts returns the number of projects and applications
+// in the snapshot.
+func snapshotProjectCounts(d *snapshotData) (int, int, error) {
+	var projects, apps int
+	for k, v := range d.Items["project"] {
+		var p pb.Project
+		if err := proto.Unmarshal(v, &p); err != nil {
+			return 0, 0, fmt.Errorf("error decoding project %q: %s", k, err)
+		}
+
+		projects++
+		apps += len(p.Applications)
+	}
+
+	return projects, apps, nil
+}
+
+func (c *SnapshotInspectCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		c.flagKey.addFlags(f)
+	})
+}
+
+func (c *SnapshotInspectCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFiles("")
+}
+
+func (c *SnapshotInspectCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *SnapshotInspectCommand) Synopsis() string {
+	return "Show information about a snapshot."
+}
+
+func (c *SnapshotInspectCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint server snapshot-inspect [<filename>]
+
+	Read a snapshot and show information about it, including the version of the
+	server that created it and the number of records of each type, without
+	restoring it. The snapshot checksum is verified.
+
+	The snapshot is read from the given file, or from standard in if no name is
+	specified and standard in is not a terminal. Using a name of '-' will force
+	reading from standard in. An s3://bucket/key URL may also be used.
+
+` + c.Flags().Help())
+}
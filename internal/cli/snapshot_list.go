@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+)
+
+type SnapshotListCommand struct {
+	*baseCommand
+}
+
+func (c *SnapshotListCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	resp, err := c.project.Client().ListSnapshots(c.Ctx, &empty.Empty{})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if c.jsonOutput() {
+		output := []map[string]interface{}{}
+		for _, s := range resp.Snapshots {
+			output = append(output, map[string]interface{}{
+				"name":        s.Name,
+				"size":        s.Size,
+				"create_time": s.CreateTime.AsTime().Format(time.RFC3339Nano),
+			})
+		}
+
+		if err := c.writeJSON(output); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
+	if len(resp.Snapshots) == 0 {
+		c.ui.Output("No snapshots are stored on the server.")
+		return 0
+	}
+
+	table := terminal.NewTable("Name", "Size", "Created")
+	for _, s := range resp.Snapshots {
+		var created string
+		if t, err := ptypes.Timestamp(s.CreateTime); err == nil {
+			created = humanize.Time(t)
+		}
+
+		table.Rich([]string{
+			s.Name,
+			humanize.Bytes(uint64(s.Size)),
+			created,
+		}, nil)
+	}
+
+	c.ui.Table(table)
+	return 0
+}
+
+func (c *SnapshotListCommand) Flags() *flag.Sets {
+	return c.flagSet(flagSetOutput, nil)
+}
+
+func (c *SnapshotListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *SnapshotListCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *SnapshotListCommand) Synopsis() string {
+	return "List the snapshots stored on the server."
+}
+
+func (c *SnapshotListCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint server snapshot-list
+
+	List the snapshots that the server has taken and stored itself, such as
+	scheduled snapshots, oldest first.
+
+	Snapshots are stored in the directory set by the "path" option of the
+	"snapshot" block in the server configuration, or in the bucket set by its
+	"s3" block. Old snapshots are deleted
+	automatically according to the "retain" and "max_age" options, or can be
+	deleted with "waypoint server snapshot-delete".
+
+`)
+}
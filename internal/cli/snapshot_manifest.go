@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"time"
+)
+
+// restoreManifest is written by `server restore -output-manifest` and
+// records exactly which records were staged for restore and which were
+// skipped, keyed by record type.
+type restoreManifest struct {
+	Source   string              `json:"source"`
+	StagedAt time.Time           `json:"staged_at"`
+	Applied  map[string][]string `json:"applied"`
+	Skipped  map[string][]string `json:"skipped"`
+}
+
+// newRestoreManifest builds a manifest from the record keys found in the
+// snapshot and the record types that the server was asked to skip.
+func newRestoreManifest(source string, keys map[string][]string, skip []string) *restoreManifest {
+	skipped := map[string]bool{}
+	for _, t := range skip {
+		skipped[t] = true
+	}
+
+	m := &restoreManifest{
+		Source:   source,
+		StagedAt: time.Now().UTC(),
+		Applied:  map[string][]string{},
+		Skipped:  map[string][]string{},
+	}
+	for t, ks := range keys {
+		if skipped[t] {
+			m.Skipped[t] = ks
+		} else {
+			m.Applied[t] = ks
+		}
+	}
+
+	return m
+}
+
+// Write writes the manifest as JSON to the given path.
+func (m *restoreManifest) Write(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+)
+
+// mergeSnapshots reads the snapshots at the given paths in order and
+// writes a single snapshot to w containing the records from all of them.
+// If the same record (record type and key) is in more than one snapshot,
+// the record from the snapshot latest in the list is used. The header of
+// the last snapshot is used for the merged snapshot.
+//
+// The returned slice has the number of records in the merged snapshot
+// that came from each path, in the same order as paths.
+//
+// If key is non-nil, the snapshots are decrypted with it. The merged
+// snapshot is not encrypted. All records are held in memory while merging.
+func mergeSnapshots(w io.Writer, paths []string, key []byte) ([]int, error) {
+	var merged *snapshotData
+	source := map[string]map[string]int{}
+	for i, path := range paths {
+		d, err := readSnapshotFile(path, key)
+		if err != nil {
+			return nil, err
+		}
+		if d.Header.ParentId != "" {
+			return nil, fmt.Errorf("snapshot %q is incremental and can't be merged", path)
+		}
+
+		if merged == nil {
+			merged = newSnapshotData(d.Header)
+		}
+		merged.Header = d.Header
+
+		for b, items := range d.Items {
+			if _, ok := source[b]; !ok {
+				source[b] = map[string]int{}
+			}
+
+			// Keep empty buckets so they're in the merged snapshot.
+			if _, ok := merged.Items[b]; !ok {
+				merged.Items[b] = map[string][]byte{}
+			}
+
+			for k, v := range items {
+				merged.Put(b, k, v)
+				source[b][k] = i
+			}
+		}
+	}
+
+	counts := make([]int, len(paths))
+	for _, keys := range source {
+		for _, i := range keys {
+			counts[i]++
+		}
+	}
+
+	_, err := writeSnapshotData(w, merged)
+	return counts, err
+}
@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// snapshotComponent is a plugin component referenced by records in a snapshot.
+type snapshotComponent struct {
+	Type pb.Component_Type
+	Name string
+}
+
+// snapshotComponentRecords are the record types that reference the
+// component that created them, along with a constructor for the message
+// stored in each.
+var snapshotComponentRecords = map[string]func() componentRecord{
+	"build":      func() componentRecord { return &pb.Build{} },
+	"deployment": func() componentRecord { return &pb.Deployment{} },
+	"release":    func() componentRecord { return &pb.Release{} },
+}
+
+type componentRecord interface {
+	proto.Message
+	GetComponent() *pb.Component
+}
+
+// snapshotComponents returns the plugin components referenced by the
+// records in the snapshot along with the number of records referencing
+// each. Records of the types in skip are ignored since they won't be
+// restored.
+func snapshotComponents(r io.Reader, skip []string) (map[snapshotComponent]int, error) {
+	skipped := map[string]bool{}
+	for _, t := range skip {
+		skipped[t] = true
+	}
+
+	result := map[snapshotComponent]int{}
+	_, err := decodeSnapshot(r, func(chunk *pb.Snapshot_BoltChunk) error {
+		newRecord, ok := snapshotComponentRecords[chunk.Bucket]
+		if !ok || skipped[chunk.Bucket] {
+			return nil
+		}
+
+		for k, v := range chunk.Items {
+			rec := newRecord()
+			if err := proto.Unmarshal(v, rec); err != nil {
+				return fmt.Errorf("error decoding %s record %q: %s", chunk.Bucket, k, err)
+			}
+
+			if c := rec.GetComponent(); c != nil {
+				result[snapshotComponent{Type: c.Type, Name: c.Name}]++
+			}
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// preflightRunners buffers the snapshot data in r to a temporary file and
+// checks that the plugin components referenced by the snapshot are
+// supported by at least one of the runners given with -preflight-runner.
+// Any missing components are reported as a warning. The returned file
+// contains the buffered snapshot data ready to be read from the start.
+// The caller must close and remove the file.
+func (c *SnapshotRestoreCommand) preflightRunners(
+	client pb.WaypointClient,
+	r io.Reader,
+) (*os.File, error) {
+	f, err := ioutil.TempFile("", "waypoint-restore")
+	if err != nil {
+		return nil, err
+	}
+
+	missing, err := c.missingComponents(client, r, f)
+	if err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+
+	if len(missing) == 0 {
+		c.ui.Output("All plugin components referenced by the snapshot are supported by the given runners.")
+		return f, nil
+	}
+
+	var keys []snapshotComponent
+	for k := range missing {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Type != keys[j].Type {
+			return keys[i].Type < keys[j].Type
+		}
+
+		return keys[i].Name < keys[j].Name
+	})
+
+	tbl := terminal.NewTable("Type", "Plugin", "Records")
+	for _, k := range keys {
+		tbl.Rich([]string{
+			k.Type.String(),
+			k.Name,
+			strconv.Itoa(missing[k]),
+		}, nil)
+	}
+
+	c.ui.Output(
+		"The snapshot references plugin components that none of the given runners "+
+			"support. The restore will continue, but operations on these records will "+
+			"not be runnable until a runner supporting them is registered:",
+		terminal.WithWarningStyle(),
+	)
+	c.ui.Table(tbl)
+
+	return f, nil
+}
+
+// missingComponents copies the snapshot data from r into f and returns the
+// components referenced by the snapshot that the preflight runners don't
+// support. On success, f is positioned at the start of the data.
+func (c *SnapshotRestoreCommand) missingComponents(
+	client pb.WaypointClient,
+	r io.Reader,
+	f *os.File,
+) (map[snapshotComponent]int, error) {
+	sg := c.ui.StepGroup()
+	defer sg.Wait()
+
+	step := sg.Add("Checking runner compatibility...")
+	defer step.Abort()
+
+	supported := map[snapshotComponent]bool{}
+	for _, id := range c.flagPreflightRunners {
+		runner, err := client.GetRunner(c.Ctx, &pb.GetRunnerRequest{RunnerId: id})
+		if err != nil {
+			return nil, fmt.Errorf("error loading runner %q: %s", id, err)
+		}
+
+		for _, comp := range runner.Components {
+			supported[snapshotComponent{Type: comp.Type, Name: comp.Name}] = true
+		}
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		return nil, fmt.Errorf("error reading snapshot data: %s", err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	referenced, err := snapshotComponents(f, c.flagSkipRecordTypes)
+	if err != nil {
+		return nil, fmt.Errorf("error reading snapshot data: %s", err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	for k := range referenced {
+		if supported[k] {
+			delete(referenced, k)
+		}
+	}
+
+	step.Update("Checked runner compatibility")
+	step.Done()
+	return referenced, nil
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+)
+
+// readCounter is an io.Reader that counts the bytes read through it.
+type readCounter struct {
+	r io.Reader
+	n int64
+
+	// total is the total number of bytes that will be read, or zero
+	// if it is unknown.
+	total int64
+}
+
+// newReadCounter returns a counter for r. If r is a regular file, the
+// total is set to the size of the file.
+func newReadCounter(r io.Reader) *readCounter {
+	rc := &readCounter{r: r}
+	if f, ok := r.(*os.File); ok {
+		if fi, err := f.Stat(); err == nil && fi.Mode().IsRegular() {
+			rc.total = fi.Size()
+		}
+	}
+
+	return rc
+}
+
+func (rc *readCounter) Read(p []byte) (int, error) {
+	n, err := rc.r.Read(p)
+	rc.n += int64(n)
+	return n, err
+}
+
+// restoreProgress reports the progress of reading snapshot data from a
+// readCounter using a terminal status.
+type restoreProgress struct {
+	status terminal.Status
+	src    *readCounter
+	start  time.Time
+	last   time.Time
+}
+
+func newRestoreProgress(ui terminal.UI, src *readCounter) *restoreProgress {
+	return &restoreProgress{
+		status: ui.Status(),
+		src:    src,
+		start:  time.Now(),
+	}
+}
+
+// Update updates the status if enough time has passed since the last
+// update. This is cheap enough to call after every chunk.
+func (p *restoreProgress) Update() {
+	now := time.Now()
+	if now.Sub(p.last) < 250*time.Millisecond {
+		return
+	}
+	p.last = now
+
+	n := p.src.n
+	if total := p.src.total; total > 0 {
+		p.status.Update(fmt.Sprintf("Sending snapshot data: %s / %s (%d%%)",
+			humanize.Bytes(uint64(n)), humanize.Bytes(uint64(total)), n*100/total))
+		return
+	}
+
+	rate := float64(n) / now.Sub(p.start).Seconds()
+	p.status.Update(fmt.Sprintf("Sending snapshot data: %s sent (%s/s)",
+		humanize.Bytes(uint64(n)), humanize.Bytes(uint64(rate))))
+}
+
+// Close closes the status.
+func (p *restoreProgress) Close() error {
+	return p.status.Close()
+}
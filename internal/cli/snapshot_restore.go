@@ -1,11 +1,22 @@
 package cli
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/chunksize"
 	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	"github.com/hashicorp/waypoint/internal/pkg/readahead"
+	"github.com/hashicorp/waypoint/internal/pkg/snapshotcrypt"
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
 	"github.com/posener/complete"
 	sshterm "golang.org/x/crypto/ssh/terminal"
@@ -16,16 +27,130 @@ type SnapshotRestoreCommand struct {
 
 	// set via -exit, indicates we should tell the server to exit now to be restarted.
 	flagExit bool
+
+	// set via -allowed-window, restricts when the restore may run.
+	flagAllowedWindow string
+
+	// set via -force, bypasses the -allowed-window check.
+	flagForce bool
+
+	// set via -adaptive-chunk, tunes the chunk size based on the throughput.
+	flagAdaptiveChunk bool
+
+	// set via -chunk-size, the size of each chunk of data sent. With
+	// -adaptive-chunk this is the initial size if it was specified.
+	flagChunkSize int
+
+	// set via -send-window, the number of chunks to read ahead while
+	// earlier chunks are being sent.
+	flagSendWindow int
+
+	// set via -skip-record-type, record types the server should not restore.
+	flagSkipRecordTypes []string
+
+	// set via -pre-backup, a path to back up the current server data to
+	// before restoring.
+	flagPreBackup string
+
+	// set via -max-concurrent-streams, the number of snapshot streams we
+	// allow to be open at once when combining a backup with the restore.
+	flagMaxStreams int
+
+	// set via -interactive-diff, shows the changes the restore will make
+	// and asks for confirmation before sending the data.
+	flagInteractiveDiff bool
+
+	// set via -output-manifest, a path to write the restored record keys to.
+	flagOutputManifest string
+	// set via -preflight-runner, runner IDs to check the snapshot's plugin
+	// components against before restoring.
+	flagPreflightRunners []string
+
+	// set via -webhook-url and -webhook-secret, where to send restore
+	// events and the secret to sign them with.
+	flagWebhookURL    string
+	flagWebhookSecret string
+
+	// set via -dry-run-against-copy, validates the restore by applying it
+	// to a scratch copy on the server instead of staging it.
+	flagDryRun bool
+
+	// set via -apply-rate, the records per second the server should
+	// process the restore at.
+	flagApplyRate int
+
+	// set via -merge-source, snapshots to merge and restore in priority
+	// order instead of reading a single snapshot.
+	flagMergeSources []string
+
+	// set via -encrypt-key and -encrypt-key-file, the key to decrypt the
+	// snapshot with and encrypt any -pre-backup with.
+	flagKey snapshotKeyFlags
+
+	// set via -quiet, disables the progress output while sending data.
+	flagQuiet bool
+
+	// set via -incremental, incremental snapshots to apply to the snapshot
+	// in order before restoring.
+	flagIncrementals []string
+
+	// set via -project, projects to limit the restore to. The global -app
+	// flag further limits the restore to a single app.
+	flagProjects []string
+
+	// set via -resume-retries, the number of times to resume the restore
+	// if the stream to the server fails.
+	flagResumeRetries int
+
+	// set via -resume-id, the ID of an interrupted restore to resume.
+	flagResumeId string
+
+	// set via -rename-project, projects to rename in the form old=new. The
+	// restore is limited to the renamed projects.
+	flagRenameProjects []string
+
+	// set via -check, shows what the restore would change and validates it
+	// with a dry run on the server without changing the server data.
+	flagCheck bool
+
+	// set via -verify, verifies the snapshot checksums before sending any
+	// of the snapshot data.
+	flagVerify bool
 }
 
+const (
+	// The defaults for -chunk-size and -send-window. These are also used
+	// with -check, which ignores the flags.
+	defaultRestoreChunkSize  = 1024 * 1024
+	defaultRestoreSendWindow = 4
+)
+
 // initWriter inspects args to figure out where the snapshot will be read from. It
-// supports args[0] being '-' to force reading from stdin.
+// supports args[0] being '-' to force reading from stdin, or an object storage URL.
 func (c *SnapshotRestoreCommand) initReader(args []string) (io.Reader, io.Closer, error) {
+	return openSnapshotReader(c.Ctx, args, c.nonInteractive())
+}
+
+// openSnapshotReader opens the snapshot named by args[0] for reading. This
+// may be a file, '-' to force reading from stdin, or an object storage URL.
+// If there are no args, stdin is used if it isn't a terminal. If
+// nonInteractive is true, stdin is only used with '-' so that the behavior
+// doesn't depend on whether stdin is a terminal.
+func openSnapshotReader(ctx context.Context, args []string, nonInteractive bool) (io.Reader, io.Closer, error) {
 	if len(args) >= 1 {
 		if args[0] == "-" {
 			return os.Stdin, nil, nil
 		}
 
+		if isSnapshotURL(args[0]) {
+			rc, err := openSnapshotURL(ctx, args[0])
+			if err != nil {
+				return nil, nil, err
+			}
+
+			return rc, rc, nil
+		}
+
 		f, err := os.Open(args[0])
 		if err != nil {
 			return nil, nil, err
@@ -34,6 +159,11 @@ func (c *SnapshotRestoreCommand) initReader(args []string) (io.Reader, io.Closer
 		return f, f, nil
 	}
 
+	if nonInteractive {
+		return nil, nil, fmt.Errorf("no snapshot given; with -non-interactive, " +
+			"use '-' to read the snapshot from stdin")
+	}
+
 	f := os.Stdin
 
 	if sshterm.IsTerminal(int(f.Fd())) {
@@ -43,91 +173,749 @@ func (c *SnapshotRestoreCommand) initReader(args []string) (io.Reader, io.Closer
 	return f, nil, nil
 }
 
+// restoreScope returns the projects and apps to limit the restore to from
+// the -project and -app flags. The -app flag must either be in the form
+// <project>/<app> or be used with a single -project, in which case only
+// that app within the project is restored.
+func (c *SnapshotRestoreCommand) restoreScope() ([]string, []string, error) {
+	switch {
+	case c.flagApp == "":
+		return c.flagProjects, nil, nil
+
+	case strings.Contains(c.flagApp, "/"):
+		return c.flagProjects, []string{c.flagApp}, nil
+
+	case len(c.flagProjects) == 1:
+		return nil, []string{c.flagProjects[0] + "/" + c.flagApp}, nil
+
+	default:
+		return nil, nil, errors.New(
+			"-app must be in the form <project>/<app> or used with a single -project")
+	}
+}
+
+// renameProjects returns the projects to rename from the -rename-project
+// flags, keyed by their name in the snapshot.
+func (c *SnapshotRestoreCommand) renameProjects() (map[string]string, error) {
+	if len(c.flagRenameProjects) == 0 {
+		return nil, nil
+	}
+
+	result := map[string]string{}
+	for _, v := range c.flagRenameProjects {
+		idx := strings.Index(v, "=")
+		if idx <= 0 || idx == len(v)-1 {
+			return nil, fmt.Errorf("-rename-project must be in the form <old>=<new>, got %q", v)
+		}
+
+		from, to := v[:idx], v[idx+1:]
+		if _, ok := result[from]; ok {
+			return nil, fmt.Errorf("project %q is renamed more than once", from)
+		}
+		result[from] = to
+	}
+
+	return result, nil
+}
+
 func (c *SnapshotRestoreCommand) Run(args []string) int {
 	// Initialize. If we fail, we just exit since Init handles the UI.
+	flagSet := c.Flags()
 	if err := c.Init(
 		WithArgs(args),
-		WithFlags(c.Flags()),
+		WithFlags(flagSet),
 		WithNoConfig(),
 	); err != nil {
 		return 1
 	}
 
-	client := c.project.Client()
+	set := setFlags(flagSet)
+	c.warnUnusedFlags(set)
+
+	if c.flagAllowedWindow != "" {
+		window, err := parseRestoreWindow(c.flagAllowedWindow)
+		if err != nil {
+			c.ui.Output("Invalid -allowed-window: %s", err, terminal.WithErrorStyle())
+			return 1
+		}
 
-	r, closer, err := c.initReader(c.args)
+		now := time.Now()
+		if !window.Contains(now) {
+			if !c.flagForce {
+				c.ui.Output(
+					"The current time (%s) is outside the allowed restore window (%s).\n"+
+						"Use -force to restore anyway.",
+					now.Format("Mon 15:04 MST"), window,
+					terminal.WithErrorStyle(),
+				)
+				return 1
+			}
+
+			c.ui.Output(
+				"The current time (%s) is outside the allowed restore window (%s), "+
+					"continuing because -force was specified.",
+				now.Format("Mon 15:04 MST"), window,
+				terminal.WithWarningStyle(),
+			)
+		}
+	}
+
+	if c.flagApplyRate < 0 {
+		c.ui.Output("-apply-rate must not be negative", terminal.WithErrorStyle())
+		return 1
+	}
+
+	if c.flagChunkSize < 1 || c.flagChunkSize > chunksize.DefaultMax {
+		c.ui.Output("-chunk-size must be between 1 and %d bytes", chunksize.DefaultMax,
+			terminal.WithErrorStyle())
+		return 1
+	}
+
+	if c.flagSendWindow < 1 {
+		c.ui.Output("-send-window must be at least 1", terminal.WithErrorStyle())
+		return 1
+	}
+
+	if c.flagResumeRetries < 0 {
+		c.ui.Output("-resume-retries must not be negative", terminal.WithErrorStyle())
+		return 1
+	}
+
+	if c.flagMaxStreams < 1 || c.flagMaxStreams > 2 {
+		c.ui.Output("-max-concurrent-streams must be 1 or 2", terminal.WithErrorStyle())
+		return 1
+	}
+
+	scopeProjects, scopeApps, err := c.restoreScope()
+	if err != nil {
+		c.ui.Output(err.Error(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	renameProjects, err := c.renameProjects()
+	if err != nil {
+		c.ui.Output(err.Error(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	// preview applies the restore options to the records in the diffs we
+	// show so they only include the records the restore changes.
+	preview, err := restorePreview(
+		c.flagSkipRecordTypes, scopeProjects, scopeApps, renameProjects)
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	key, err := c.flagKey.Key()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to open output: %s", err)
+		c.ui.Output("Invalid encryption key: %s", err, terminal.WithErrorStyle())
 		return 1
 	}
 
+	client := c.project.Client()
+
+	if err := c.validateSkipRecordTypes(client); err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	// src counts the snapshot data we read for progress reporting. It
+	// must wrap whatever r is read from before any decoding so that the
+	// count can be compared with the size of the file.
+	var (
+		r           io.Reader
+		src         *readCounter
+		closer      io.Closer
+		mergeCounts []int
+	)
+	if len(c.flagMergeSources) > 0 {
+		if len(c.args) > 0 {
+			c.ui.Output("A snapshot argument can't be given with -merge-source.",
+				terminal.WithErrorStyle())
+			return 1
+		}
+
+		// Merge into a temporary file so that the merged snapshot is
+		// restored as a single snapshot like any other.
+		f, err := ioutil.TempFile("", "waypoint-restore")
+		if err != nil {
+			c.outputError("Failed to create merge file", err)
+			return 1
+		}
+		defer os.Remove(f.Name())
+		closer = f
+
+		mergeCounts, err = mergeSnapshots(f, c.flagMergeSources, key)
+		if err == nil {
+			_, err = f.Seek(0, io.SeekStart)
+		}
+		if err != nil {
+			f.Close()
+			c.outputError("Failed to merge snapshots", err)
+			return 1
+		}
+
+		src = newReadCounter(f)
+		r = src
+	} else {
+		// The snapshot is read again to restore it, so it can only be
+		// verified first if it isn't read from stdin.
+		if c.flagVerify && !c.readsStdin() {
+			if err := verifySnapshotSource(c.Ctx, c.args, key); err != nil {
+				c.outputError("Snapshot is not valid", err)
+				return 1
+			}
+		}
+
+		r, closer, err = c.initReader(c.args)
+		if err != nil {
+			c.outputError("Failed to open snapshot", err)
+			return 1
+		}
+
+		src = newReadCounter(r)
+		r = src
+
+		r, err = decryptSnapshot(r, key)
+		if err != nil {
+			c.outputError("Failed to read snapshot", err)
+			return 1
+		}
+	}
+
 	if closer != nil {
 		defer closer.Close()
 	}
 
-	stream, err := client.RestoreSnapshot(c.Ctx)
+	// Snapshots are already compressed, but operators often compress
+	// them again for storage. Detect and undo that so they can be
+	// restored directly.
+	r, err = unwrapSnapshot(r)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to restore snapshot: %s", err)
+		c.outputError("Failed to read snapshot", err)
 		return 1
 	}
 
-	err = stream.Send(&pb.RestoreSnapshotRequest{
-		Event: &pb.RestoreSnapshotRequest_Open_{
-			Open: &pb.RestoreSnapshotRequest_Open{
-				Exit: c.flagExit,
-			},
-		},
-	})
+	// If we have incremental snapshots, apply them to the snapshot and
+	// restore the result as a full snapshot.
+	if len(c.flagIncrementals) > 0 {
+		if len(c.flagMergeSources) > 0 {
+			c.ui.Output("-incremental can't be used with -merge-source.",
+				terminal.WithErrorStyle())
+			return 1
+		}
+
+		f, err := c.applyIncrementals(r, key)
+		if err != nil {
+			c.outputError("Failed to apply incremental snapshots", err)
+			return 1
+		}
+		defer os.Remove(f.Name())
+		defer f.Close()
+
+		src = newReadCounter(f)
+		r = src
+	}
+
+	// If requested, warn about plugin components in the snapshot that the
+	// given runners can't run. As with the diff below, the snapshot data is
+	// buffered locally so that it only needs to be read once.
+	if len(c.flagPreflightRunners) > 0 {
+		f, err := c.preflightRunners(client, r)
+		if err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+		defer os.Remove(f.Name())
+		defer f.Close()
+
+		src = newReadCounter(f)
+		r = src
+	}
+
+	// If requested, show the operator what will change and get their
+	// confirmation. The snapshot data is buffered locally while we do this
+	// so we read from the buffered copy rather than reading it twice. The
+	// confirmation can't be read from stdin if the snapshot is.
+	if c.flagInteractiveDiff && !c.readsStdin() {
+		if !c.ui.Interactive() {
+			c.ui.Output("-interactive-diff requires an interactive terminal.",
+				terminal.WithErrorStyle())
+			return 1
+		}
+
+		f, ok, err := c.confirmDiff(client, r, preview)
+		if err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+		if !ok {
+			c.ui.Output("Restore cancelled.")
+			return 1
+		}
+		defer os.Remove(f.Name())
+		defer f.Close()
+
+		src = newReadCounter(f)
+		r = src
+	}
+
+	// If we're only checking the restore, report what it would change.
+	// The data is then sent as a dry run so that the server validates it.
+	if c.flagCheck {
+		if c.flagInteractiveDiff && !c.readsStdin() {
+			c.ui.Output("-check can't be used with -interactive-diff.",
+				terminal.WithErrorStyle())
+			return 1
+		}
+
+		f, err := c.checkDiff(client, r, preview)
+		if err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+		defer os.Remove(f.Name())
+		defer f.Close()
+
+		src = newReadCounter(f)
+		r = src
+	}
+
+	// If requested, back up the current server data before we restore.
+	// By default the backup completes before the restore stream is opened
+	// so that only one stream is active at a time. If more streams are
+	// allowed, the backup runs while we send the restore data and we only
+	// wait for it before the restore is committed.
+	var backupCh chan error
+	if c.flagPreBackup != "" && !c.flagCheck {
+		backupCh = make(chan error, 1)
+		go func() {
+			backupCh <- c.preBackup(client, key)
+		}()
+
+		if c.flagMaxStreams < 2 {
+			if err := <-backupCh; err != nil {
+				c.outputError("Failed to back up server data", err)
+				return 1
+			}
+
+			backupCh = nil
+		}
+	}
+
+	// If we're skipping record types or writing a manifest, decode the
+	// records as we send them so we can report on them afterwards. Nothing
+	// is restored with -check, so there is no manifest to write.
+	manifest := c.flagOutputManifest
+	if c.flagCheck {
+		manifest = ""
+	}
+
+	var counter *snapshotRecordCounter
+	if len(c.flagSkipRecordTypes) > 0 || manifest != "" {
+		counter = newSnapshotRecordCounter(manifest != "")
+		r = io.TeeReader(r, counter)
+	}
+
+	source := "stdin"
+	if len(c.flagMergeSources) > 0 {
+		source = strings.Join(c.flagMergeSources, ",")
+	} else if len(c.args) > 0 && c.args[0] != "-" {
+		source = c.args[0]
+	}
+
+	// If requested, notify a webhook that the restore is starting and
+	// how it ended. Webhook failures never fail the restore. The outcome
+	// is determined by restoreErr, which must be set before returning if
+	// the restore fails.
+	var (
+		restoreErr error
+		sent       int64
+	)
+	if c.flagWebhookURL != "" {
+		hook, err := newRestoreWebhook(c.flagWebhookURL, c.flagWebhookSecret, source)
+		if err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		if err := hook.Start(c.Ctx); err != nil {
+			c.Log.Warn("error sending restore start event to webhook", "err", err)
+			c.ui.Output("Error sending restore start event to webhook: %s", err,
+				terminal.WithWarningStyle())
+		}
+
+		defer func() {
+			if err := hook.Complete(c.Ctx, sent, restoreErr); err != nil {
+				c.Log.Warn("error sending restore complete event to webhook", "err", err)
+				c.ui.Output("Error sending restore complete event to webhook: %s", err,
+					terminal.WithWarningStyle())
+			}
+		}()
+	}
+
+	// Send the versions from the snapshot header so that the server can
+	// refuse a snapshot it can't restore before we send all the data.
+	var header *pb.Snapshot_Header
+	header, r, err = peekSnapshotHeader(r)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to send start message: %s", err)
+		restoreErr = err
+		c.outputError("Failed to read snapshot", err)
 		return 1
 	}
 
-	// Write the data in smaller chunks so we don't overwhelm the grpc stream
-	// processing machinary.
-	var buf [1024]byte
+	// A check validates the data as quickly as the server can, so the
+	// apply rate and the chunking of a real restore don't apply to it.
+	dryRun := c.flagDryRun || c.flagCheck
+	applyRate, chunkSize, sendWindow := c.flagApplyRate, c.flagChunkSize, c.flagSendWindow
+	if c.flagCheck {
+		applyRate, chunkSize, sendWindow = 0, defaultRestoreChunkSize, defaultRestoreSendWindow
+	}
 
-	for {
-		// use ReadFull here because if r is an OS pipe, each bare call to Read()
-		// can result in just one or two bytes per call, so we want to batch those
-		// up before sending them off for better performance.
-		n, err := io.ReadFull(r, buf[:])
-		if err == io.EOF || err == io.ErrUnexpectedEOF {
-			err = nil
+	startTime := time.Now()
+	stream, err := newRestoreSender(c.Ctx, client, &pb.RestoreSnapshotRequest_Open{
+		Exit:            c.flagExit,
+		SkipRecordTypes: c.flagSkipRecordTypes,
+		DryRun:          dryRun,
+		ApplyRate:       uint32(applyRate),
+		Projects:        scopeProjects,
+		Apps:            scopeApps,
+		SnapshotVersion: header.GetVersion().GetVersion(),
+		SchemaVersion:   header.GetSchemaVersion(),
+		RenameProjects:  renameProjects,
+	}, c.flagResumeRetries, c.flagResumeId)
+	if err != nil {
+		restoreErr = err
+		c.outputError("Failed to restore snapshot", err)
+		return 1
+	}
+	defer stream.Close()
+	stream.onResume = func(err error) {
+		c.Log.Warn("restore stream failed, resuming", "err", err)
+		c.ui.Output("Sending the restore data failed, resuming: %s", clierrors.Humanize(err),
+			terminal.WithWarningStyle())
+	}
+
+	// Write the data in chunks so we don't overwhelm the grpc stream
+	// processing machinary. The next chunks are read while the current one
+	// is sent so that a slow source such as object storage doesn't stall
+	// the stream. The window bounds how much is read ahead, so a slow
+	// stream in turn slows down reading. If adaptive chunking is enabled,
+	// the meter chooses the size of each chunk based on the throughput of
+	// the stream, up to its max.
+	var meter *chunksize.Meter
+	size, max := chunkSize, chunkSize
+	if c.flagAdaptiveChunk {
+		// Unless a size was given, start modestly so that a slow
+		// connection isn't overwhelmed before it is measured.
+		initial := 0
+		if set["chunk-size"] && !c.flagCheck {
+			initial = chunkSize
 		}
 
-		if n == 0 {
+		sizer := chunksize.New(0, initial, 0)
+		meter = chunksize.NewMeter(sizer, 0)
+		size, max = sizer.Size(), sizer.Max()
+	}
+	chunks := readahead.New(r, size, max, sendWindow)
+	defer chunks.Close()
+
+	// Show progress unless asked not to. This only makes sense on an
+	// interactive terminal since the status is updated in place.
+	var progress *restoreProgress
+	if !c.flagQuiet && c.ui.Interactive() {
+		progress = newRestoreProgress(c.ui, src)
+		defer func() {
+			if progress != nil {
+				progress.Close()
+			}
+		}()
+	}
+
+	for {
+		chunk, err := chunks.Next()
+		if err == io.EOF {
 			break
 		}
-
-		err = stream.Send(&pb.RestoreSnapshotRequest{
-			Event: &pb.RestoreSnapshotRequest_Chunk{
-				Chunk: buf[:n],
-			},
-		})
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "failed to write snapshot data: %s", err)
+			restoreErr = err
+			c.outputError("Failed to read snapshot data", err)
+			c.outputResumeId(stream, err)
+			return 1
+		}
+		n := len(chunk)
+
+		if err := stream.Send(chunk); err != nil {
+			restoreErr = err
+			c.outputError("Failed to write snapshot data", err)
+			c.outputResumeId(stream, err)
 			return 1
 		}
+		sent += int64(n)
+
+		if progress != nil {
+			progress.Update()
+		}
+
+		if meter != nil && meter.Sent(n) {
+			chunks.SetSize(meter.Size())
+		}
+	}
+
+	// Close the progress so that it doesn't interfere with our output.
+	if progress != nil {
+		progress.Close()
+		progress = nil
 	}
 
-	_, err = stream.CloseAndRecv()
-	if err != nil && !c.flagExit {
-		fmt.Fprintf(os.Stderr, "failed to receive snapshot close message: %s", err)
+	// The restore is committed when we close the stream, so we must make
+	// sure any backup of the current data has finished first. Returning
+	// here cancels the stream and aborts the restore.
+	if backupCh != nil {
+		if err := <-backupCh; err != nil {
+			restoreErr = err
+			c.outputError("Failed to back up server data", err)
+			return 1
+		}
+	}
+
+	// The server ignores -exit for dry runs so we always expect a response.
+	err = stream.CloseAndRecv()
+	if err != nil && (!c.flagExit || dryRun) {
+		restoreErr = err
+		c.outputError("Failed to receive snapshot close message", err)
+		c.outputResumeId(stream, err)
 		return 1
 	}
 
-	if r == os.Stdin {
+	if c.jsonOutput() {
+		return c.outputJson(source, sent, time.Since(startTime), mergeCounts, counter, manifest)
+	}
+
+	if c.flagPreBackup != "" && !c.flagCheck {
+		c.ui.Output("Previous server data backed up to '%s'.", c.flagPreBackup)
+	}
+
+	if c.flagCheck {
+		c.ui.Output(
+			"Check passed. The server validated the snapshot and discarded it. "+
+				"The server data was not changed.",
+			terminal.WithSuccessStyle(),
+		)
+	} else if c.flagDryRun {
+		c.ui.Output(
+			"Dry run succeeded. The snapshot was applied to a scratch copy of the "+
+				"server data, which has been removed. The server data was not changed.",
+			terminal.WithSuccessStyle(),
+		)
+		c.ui.Output("Sent %d bytes in %s.", sent, time.Since(startTime).Round(time.Millisecond))
+	} else if len(c.flagMergeSources) > 0 {
+		c.ui.Output("Server data restored from %d merged snapshots.", len(c.flagMergeSources))
+	} else if len(c.args) == 0 || c.args[0] == "-" {
 		c.ui.Output("Server data restored.")
 	} else {
 		c.ui.Output("Server data restored from '%s'.", c.args[0])
 	}
 
+	for i, path := range c.flagMergeSources {
+		c.ui.Output("Used %d record(s) from '%s'.", mergeCounts[i], path)
+	}
+
+	if counter != nil {
+		counts, err := counter.Close()
+		if err != nil {
+			c.ui.Output("Error reading records from snapshot: %s", err, terminal.WithWarningStyle())
+		}
+
+		for _, t := range c.flagSkipRecordTypes {
+			c.ui.Output("Skipped %d record(s) of type %q.", counts[t], t)
+		}
+
+		// We don't write a manifest if we couldn't read every record
+		// since it would be incomplete.
+		if manifest != "" && err == nil {
+			m := newRestoreManifest(source, counter.Keys(), c.flagSkipRecordTypes)
+			if err := m.Write(manifest); err != nil {
+				c.ui.Output("Error writing manifest: %s", err, terminal.WithErrorStyle())
+				return 1
+			}
+
+			c.ui.Output("Manifest of restored records written to '%s'.", manifest)
+		}
+	}
+
+	return 0
+}
+
+// outputResumeId tells the operator how to resume the restore if it was
+// interrupted by err before it completed.
+func (c *SnapshotRestoreCommand) outputResumeId(stream *restoreSender, err error) {
+	id := stream.ResumeId(err)
+	if id == "" {
+		return
+	}
+
+	opts := []interface{}{id, id, id, terminal.WithWarningStyle()}
+	if _, stderr, werr := c.ui.OutputWriters(); werr == nil && stderr != nil {
+		opts = append(opts, terminal.WithWriter(stderr))
+	}
+
+	c.ui.Output(
+		"The server kept the data it received for the restore %q. To resume it, run "+
+			"the same command with -resume-id=%s. Use 'waypoint server snapshot "+
+			"restore-state %s' to see how much data the server has.",
+		opts...,
+	)
+}
+
+// outputJson writes the result of a successful restore as JSON for
+// -output=json. This also writes the manifest to the manifest path if it
+// isn't empty.
+func (c *SnapshotRestoreCommand) outputJson(
+	source string,
+	sent int64,
+	duration time.Duration,
+	mergeCounts []int,
+	counter *snapshotRecordCounter,
+	manifest string,
+) int {
+	result := map[string]interface{}{}
+	result["source"] = source
+	result["check"] = c.flagCheck
+	result["dry_run"] = c.flagDryRun
+	result["bytes_sent"] = sent
+	result["duration_seconds"] = duration.Seconds()
+
+	if c.flagPreBackup != "" && !c.flagCheck {
+		result["pre_backup"] = c.flagPreBackup
+	}
+
+	if len(c.flagMergeSources) > 0 {
+		var merged []map[string]interface{}
+		for i, path := range c.flagMergeSources {
+			merged = append(merged, map[string]interface{}{
+				"path":    path,
+				"records": mergeCounts[i],
+			})
+		}
+
+		result["merge_sources"] = merged
+	}
+
+	if counter != nil {
+		counts, err := counter.Close()
+		if err != nil {
+			result["warnings"] = []string{
+				fmt.Sprintf("error reading records from snapshot: %s", err),
+			}
+		}
+
+		skipped := map[string]int{}
+		for _, t := range c.flagSkipRecordTypes {
+			skipped[t] = counts[t]
+		}
+		result["skipped"] = skipped
+
+		if manifest != "" && err == nil {
+			m := newRestoreManifest(source, counter.Keys(), c.flagSkipRecordTypes)
+			if err := m.Write(manifest); err != nil {
+				c.ui.Output("Error writing manifest: %s", err, terminal.WithErrorStyle())
+				return 1
+			}
+
+			result["manifest"] = manifest
+		}
+	}
+
+	if err := c.writeJSON(result); err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
 	return 0
 }
 
+// applyIncrementals reads the full snapshot in r, applies the snapshots
+// given by -incremental in order, and writes the result to a temporary
+// file. The returned file is positioned at the start of the data and the
+// caller must close and remove it.
+func (c *SnapshotRestoreCommand) applyIncrementals(r io.Reader, key []byte) (*os.File, error) {
+	base, err := readSnapshot(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading snapshot: %s", err)
+	}
+	if base.Header.ParentId != "" {
+		return nil, fmt.Errorf("the snapshot to restore is incremental, " +
+			"specify its parent snapshot and pass it with -incremental")
+	}
+
+	for _, path := range c.flagIncrementals {
+		inc, err := readSnapshotFile(path, key)
+		if err != nil {
+			return nil, err
+		}
+		if err := applyIncremental(base, inc); err != nil {
+			return nil, fmt.Errorf("error applying snapshot %q: %s", path, err)
+		}
+	}
+
+	f, err := ioutil.TempFile("", "waypoint-restore")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = writeSnapshotData(f, base)
+	if err == nil {
+		_, err = f.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// preBackup writes a snapshot of the current server data to the path
+// given by -pre-backup. If key is non-nil, the backup is encrypted with it.
+func (c *SnapshotRestoreCommand) preBackup(client pb.WaypointClient, key []byte) error {
+	f, err := os.Create(c.flagPreBackup)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	bw := bufio.NewWriter(f)
+	var w io.Writer = bw
+	var ew *snapshotcrypt.Writer
+	if key != nil {
+		ew, err = snapshotcrypt.NewWriter(bw, key)
+		if err != nil {
+			return err
+		}
+
+		w = ew
+	}
+
+	if err := writeSnapshot(c.Ctx, client, w); err != nil {
+		return err
+	}
+	if ew != nil {
+		if err := ew.Close(); err != nil {
+			return err
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
 func (c *SnapshotRestoreCommand) Flags() *flag.Sets {
-	return c.flagSet(0, func(set *flag.Sets) {
+	return c.flagSet(flagSetOutput, func(set *flag.Sets) {
 		f := set.NewSet("Command Options")
 		f.BoolVar(&flag.BoolVar{
 			Name:    "exit",
@@ -135,6 +923,211 @@ func (c *SnapshotRestoreCommand) Flags() *flag.Sets {
 			Usage:   "After restoring, the server should exit so it can be restarted.",
 			Default: false,
 		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "allowed-window",
+			Target: &c.flagAllowedWindow,
+			Usage: "Only allow the restore to run within the given window of local time. " +
+				"The window has the form \"[DAYS ]HH:MM-HH:MM\", for example " +
+				"\"Sat,Sun 02:00-06:00\" or \"Mon-Fri 22:00-04:00\". Windows that end " +
+				"before they start wrap past midnight.",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:   "adaptive-chunk",
+			Target: &c.flagAdaptiveChunk,
+			Usage: "Tune the size of each chunk sent to the server based on the measured " +
+				"throughput. Chunks start at 64 KB, or -chunk-size if specified, and stay " +
+				"between 4 KB and 3 MB.",
+			Default: false,
+		})
+
+		f.IntVar(&flag.IntVar{
+			Name:    "chunk-size",
+			Target:  &c.flagChunkSize,
+			Default: defaultRestoreChunkSize,
+			Usage: "Size in bytes of each chunk of data sent to the server. Larger " +
+				"chunks reduce the overhead per message. The maximum is 3 MB.",
+		})
+
+		f.IntVar(&flag.IntVar{
+			Name:    "send-window",
+			Target:  &c.flagSendWindow,
+			Default: defaultRestoreSendWindow,
+			Usage: "Number of chunks to read from the snapshot ahead of sending them. " +
+				"A larger window smooths out a slow source at the cost of memory.",
+		})
+
+		f.StringSliceVar(&flag.StringSliceVar{
+			Name:   "skip-record-type",
+			Target: &c.flagSkipRecordTypes,
+			Usage: "A record type that should not be restored, such as \"jobs\". " +
+				"This can be specified multiple times. Record types unknown to " +
+				"the server are rejected.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "client-cert",
+			Target: &c.flagConnection.Server.TlsClientCertFile,
+			Usage: "Path to a PEM-encoded certificate to present to the server for " +
+				"mutual TLS. Must be used with -client-key.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "client-key",
+			Target: &c.flagConnection.Server.TlsClientKeyFile,
+			Usage: "Path to the PEM-encoded private key for -client-cert. " +
+				"Must be used with -client-cert.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "pre-backup",
+			Target: &c.flagPreBackup,
+			Usage: "Write a snapshot of the current server data to this path before " +
+				"restoring. The restore is aborted if the backup fails.",
+		})
+
+		f.IntVar(&flag.IntVar{
+			Name:    "max-concurrent-streams",
+			Target:  &c.flagMaxStreams,
+			Default: 1,
+			Usage: "The number of snapshot streams that may be open at once when " +
+				"-pre-backup is used. With 1, the backup finishes before the restore " +
+				"begins. With 2, the backup runs while the restore data is sent.",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:   "interactive-diff",
+			Target: &c.flagInteractiveDiff,
+			Usage: "Compare the snapshot with the current server data, show a " +
+				"summary of the changes, and ask for confirmation before restoring.",
+			Default: false,
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "output-manifest",
+			Target: &c.flagOutputManifest,
+			Usage: "Write a JSON manifest to this path listing the key of every " +
+				"record that was restored and skipped, grouped by record type.",
+		})
+
+		f.StringSliceVar(&flag.StringSliceVar{
+			Name:   "preflight-runner",
+			Target: &c.flagPreflightRunners,
+			Usage: "ID of a runner to check the snapshot against before restoring. " +
+				"A warning lists any plugins used by the snapshot that none of these " +
+				"runners support. Can be specified multiple times.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "webhook-url",
+			Target: &c.flagWebhookURL,
+			Usage: "URL to POST a JSON event to when the restore starts and completes. " +
+				"Errors sending the events are reported but do not fail the restore.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "webhook-secret",
+			Target: &c.flagWebhookSecret,
+			Usage: "Secret used to sign webhook events. The hex-encoded HMAC-SHA256 of " +
+				"the body is sent in the X-Waypoint-Signature header as \"sha256=<hex>\".",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:   "dry-run-against-copy",
+			Target: &c.flagDryRun,
+			Usage: "Validate the restore by applying it to a temporary copy on the " +
+				"server instead of staging it. The copy is removed afterwards and the " +
+				"server data is not changed.",
+		})
+
+		f.IntVar(&flag.IntVar{
+			Name:   "apply-rate",
+			Target: &c.flagApplyRate,
+			Usage: "Maximum number of records per second the server processes while " +
+				"staging the restore. A lower rate reduces the load on the server but " +
+				"makes the restore take longer. Zero is unlimited.",
+		})
+
+		f.StringSliceVar(&flag.StringSliceVar{
+			Name:   "merge-source",
+			Target: &c.flagMergeSources,
+			Usage: "Path to a snapshot to merge into the restore. Can be specified " +
+				"multiple times. If a record is in more than one snapshot, the one from " +
+				"the snapshot specified last is used.",
+		})
+
+		c.flagKey.addFlags(f)
+
+		f.BoolVar(&flag.BoolVar{
+			Name:   "quiet",
+			Target: &c.flagQuiet,
+			Usage:  "Don't show the progress of sending the snapshot data.",
+		})
+
+		f.StringSliceVar(&flag.StringSliceVar{
+			Name:   "incremental",
+			Target: &c.flagIncrementals,
+			Usage: "Path to an incremental snapshot to apply to the snapshot before " +
+				"restoring. Can be specified multiple times to apply a chain of " +
+				"incremental snapshots in order.",
+		})
+
+		f.StringSliceVar(&flag.StringSliceVar{
+			Name:   "project",
+			Target: &c.flagProjects,
+			Usage: "Only restore the records belonging to this project. All other " +
+				"data on the server is kept as it is. Can be specified multiple times. " +
+				"Use with -app to only restore a single app within the project.",
+		})
+
+		f.IntVar(&flag.IntVar{
+			Name:   "resume-retries",
+			Target: &c.flagResumeRetries,
+			Usage: "Number of times to reconnect and resume sending the snapshot from " +
+				"where the server left off if the connection fails. The data sent is " +
+				"kept in a temporary file to do this. Zero disables resuming.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "resume-id",
+			Target: &c.flagResumeId,
+			Usage: "Resume the interrupted restore with this ID, which is shown when a " +
+				"resumable restore fails. The data the server already received is " +
+				"skipped. The snapshot and flags must be the same as the interrupted restore.",
+		})
+
+		f.StringSliceVar(&flag.StringSliceVar{
+			Name:   "rename-project",
+			Target: &c.flagRenameProjects,
+			Usage: "Rename a project in the snapshot as it is restored, in the form " +
+				"<old>=<new>. Only the renamed projects are restored, alongside the " +
+				"existing data. Can be specified multiple times.",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:   "check",
+			Target: &c.flagCheck,
+			Usage: "Check the snapshot without restoring it. This shows the projects " +
+				"and deployments the restore would change, checks that the server " +
+				"supports the snapshot version, and has the server validate the data " +
+				"before discarding it.",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:   "verify",
+			Target: &c.flagVerify,
+			Usage: "Verify the checksums of the snapshot before sending any of it to " +
+				"the server. The snapshot is read twice, so this can't be used when " +
+				"reading from stdin.",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "force",
+			Target:  &c.flagForce,
+			Usage:   "Restore even if the current time is outside of -allowed-window.",
+			Default: false,
+		})
 	})
 }
 
@@ -152,7 +1145,7 @@ func (c *SnapshotRestoreCommand) Synopsis() string {
 
 func (c *SnapshotRestoreCommand) Help() string {
 	return formatHelp(`
-Usage: waypoint server restore [-exit] [<filename>]
+Usage: waypoint server restore [-exit] [-allowed-window=<window> [-force]] [<filename>]
 
 	Stage a backup snapshot within the current server. The data in the snapshot is not restored
 	immediately, but rather staged such that on the next server start, it will be restored.
@@ -163,6 +1156,121 @@ Usage: waypoint server restore [-exit] [<filename>]
 	If -exit is not passed, an operator must restart the server manually to finish the restoration
 	process.
 
+	The server verifies the checksums in the snapshot before staging it, so a truncated or
+	corrupted snapshot is rejected without affecting the current data. Use
+	"waypoint server snapshot-verify" to check a snapshot ahead of time.
+
+	If -project is passed, only the records belonging to that project are restored and all
+	other data on the server is kept as it is. Combine it with the -app flag to only restore
+	the records of a single app, or pass -app=<project>/<app> on its own.
+
+	If -resume-retries is passed, the restore can survive a flaky connection. If the stream
+	to the server fails, the CLI reconnects and resends the data from the last point the
+	server acknowledged rather than starting over. The server keeps the data it has received
+	until the restore completes and only stages the restore once it has all of it.
+
+	If a resumable restore fails anyway, or is canceled, its resume ID is shown. The server
+	keeps the data it received for a day. Run the same command again with -resume-id to
+	send only the rest of the data, and use "waypoint server snapshot restore-state" to see
+	how much the server has.
+
+	If -allowed-window is passed, the restore will only proceed if the current local time is
+	within the given window. Use -force to restore outside of the window anyway.
+
+	If -adaptive-chunk is passed, the size of each chunk of data sent to the server is tuned
+	automatically by measuring the throughput of the stream about once a second. Chunks
+	start at 64 KB unless -chunk-size is also passed. This can significantly speed up
+	restores over high-latency connections.
+
+	The snapshot is sent in chunks of -chunk-size bytes, 1 MB by default. Up to -send-window
+	chunks are read from the snapshot while earlier chunks are being sent, so reading and
+	sending overlap. Reading pauses once the window is full, which limits the memory used to
+	about -send-window times the chunk size.
+
+	If -skip-record-type is passed, records of that type in the snapshot are not restored.
+	The number of records skipped for each type is reported after the restore is staged.
+
+	If the server requires mutual TLS, use -client-cert and -client-key to present a
+	client certificate. Both must be specified and the key must match the certificate.
+
+	If -pre-backup is passed, a snapshot of the current server data is written to the given
+	path before the restore is committed. By default the backup completes before the restore
+	data is sent so that only one snapshot stream is open at a time, which keeps memory and
+	connection usage low. Set -max-concurrent-streams=2 to send the restore data while the
+	backup is still running.
+
+	If -interactive-diff is passed, the snapshot is compared with a snapshot of the current
+	server data and a summary of the records that will be added, removed, and changed is
+	shown. The restore only continues if you confirm it. This requires an interactive terminal
+	and reading the snapshot from a file.
+
+	If -output-manifest is passed, a JSON manifest listing the key of every record that was
+	restored or skipped is written to the given path. This can be large for big snapshots.
+
+	If -output=json is passed, the result of the restore is written as a single JSON
+	object with the source, bytes sent, merged and skipped record counts, and the
+	manifest path, instead of the messages above.
+
+	If -preflight-runner is passed, the plugins used by the builds, deployments, and releases
+	in the snapshot are checked against the plugins the given runners support, and any that
+	are not supported are listed before the restore continues.
+
+	If -webhook-url is passed, a JSON event is POSTed to the URL when the restore starts
+	and when it completes, including the snapshot source, bytes sent, and the outcome.
+
+	If -dry-run-against-copy is passed, the server applies the snapshot to a scratch database
+	to validate it end-to-end and then removes it. Nothing is staged and -exit is ignored.
+	This can be combined with -interactive-diff to also see what the restore would change.
+
+	If -rename-project is passed, the project is renamed as it is restored and only the
+	renamed projects are restored. All other data on the server is kept, so this can load a
+	project from another server, such as production, alongside the existing projects. The
+	new name must not already exist on the server. Records keep their IDs, so a snapshot
+	can't be restored with a renamed project to the server it was taken from.
+
+	If -check is passed, the restore is checked without changing the server data. The
+	projects added and removed and the deployments affected by the restore are shown, the
+	snapshot is checked to be from a compatible server version, and the data is sent to the
+	server as with -dry-run-against-copy to validate its integrity. -check can't be combined
+	with -interactive-diff. The data is sent with the default chunk size and send window and
+	no apply rate, and -exit, -pre-backup and -output-manifest are ignored.
+
+	If -verify is passed, the checksums of the snapshot are verified before any of it is
+	sent, so a corrupted snapshot fails without contacting the server. The snapshot is read
+	twice to do this, so it is ignored when reading the snapshot from stdin. Merged
+	snapshots are always verified as they are merged.
+
+	If -apply-rate is passed, the server paces how quickly it processes the records in the
+	snapshot. A lower rate reduces the impact on a busy server but extends the restore.
+
+	If -merge-source is passed one or more times, the snapshots are merged and restored
+	as one snapshot in a single restore, and no snapshot argument may be given. Records
+	are identified by their record type and key. A record in more than one snapshot is
+	taken from the snapshot specified last, and all other records are kept. Records are
+	never deleted by the merge: a record missing from a later snapshot is kept from an
+	earlier one. The number of records used from each snapshot is shown once complete.
+
+	A warning is shown for any flags that are set but have no effect with the other
+	flags given or the snapshot source, such as -webhook-secret without -webhook-url or
+	-interactive-diff when reading the snapshot from stdin.
+
+	The server checks the schema version of the data in the snapshot before the data is
+	sent. A snapshot from a newer server with data this server can't read is refused, and
+	data from an older server is migrated to the current schema when it is staged.
+
+	Snapshots are always gzip compressed. A snapshot that was compressed again with gzip
+	is detected and restored without needing to decompress it first.
+
+	If the snapshot was encrypted, specify the same key with -encrypt-key or
+	-encrypt-key-file. Any -pre-backup is encrypted with the same key.
+
+	The snapshot may also be read directly from S3 by specifying an s3://bucket/key URL.
+	Credentials are read from the standard AWS credential chain.
+
+	To restore an incremental snapshot, pass the full snapshot it was based on as the
+	argument and each incremental snapshot in the chain, in order, with -incremental.
+	Each incremental snapshot must have been taken from the one before it.
+
 	The argument should be to a file written previously by 'waypoint server snapshot'.
 	If no name is specified and standard input is not a terminal, the backup will read from
 	standard input. Using a name of '-' will force reading from standard input.
@@ -0,0 +1,153 @@
+package cli
+
+import (
+	stdflag "flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// unusedFlags returns the flags that were set but have no effect given
+// the other flags, the snapshot source and whether the UI is interactive,
+// mapped to the reason they have no effect. Flags are named without the
+// leading dash.
+func (c *SnapshotRestoreCommand) unusedFlags(set map[string]bool, interactive bool) map[string]string {
+	result := map[string]string{}
+	if set["force"] && c.flagAllowedWindow == "" {
+		result["force"] = "only used with -allowed-window"
+	}
+	if set["webhook-secret"] && c.flagWebhookURL == "" {
+		result["webhook-secret"] = "only used with -webhook-url"
+	}
+	if set["max-concurrent-streams"] && c.flagPreBackup == "" {
+		result["max-concurrent-streams"] = "only used with -pre-backup"
+	}
+	if set["exit"] && c.flagDryRun {
+		result["exit"] = "the server never exits after -dry-run-against-copy"
+	}
+	if set["exit"] && c.flagCheck {
+		result["exit"] = "the server never exits after -check"
+	}
+	if set["pre-backup"] && c.flagCheck {
+		result["pre-backup"] = "no backup is needed since -check doesn't change the server data"
+	}
+	if c.flagCheck {
+		for _, name := range []string{"chunk-size", "send-window", "apply-rate"} {
+			if set[name] {
+				result[name] = "-check sends the snapshot with the default settings"
+			}
+		}
+	}
+	if set["output-manifest"] && c.flagCheck {
+		result["output-manifest"] = "nothing is restored with -check"
+	}
+	if set["verify"] {
+		if len(c.flagMergeSources) > 0 {
+			result["verify"] = "merged snapshots are always verified as they are merged"
+		} else if c.readsStdin() {
+			result["verify"] = "a snapshot read from stdin can't be read twice to verify it first"
+		}
+	}
+	if set["interactive-diff"] && c.readsStdin() {
+		result["interactive-diff"] = "the confirmation can't be read from stdin while the snapshot is"
+	}
+	if set["quiet"] && !interactive {
+		result["quiet"] = "progress is only shown on an interactive terminal"
+	}
+
+	return result
+}
+
+// readsStdin returns true if the snapshot is read from stdin, either
+// because it was given as '-' or because no snapshot was given.
+func (c *SnapshotRestoreCommand) readsStdin() bool {
+	if len(c.flagMergeSources) > 0 {
+		return false
+	}
+
+	return len(c.args) == 0 || c.args[0] == "-"
+}
+
+// setFlags returns the names of the flags in sets that were set on the
+// command line, without the leading dash.
+func setFlags(sets *flag.Sets) map[string]bool {
+	set := map[string]bool{}
+	sets.Visit(func(f *stdflag.Flag) {
+		set[f.Name] = true
+	})
+
+	return set
+}
+
+// warnUnusedFlags outputs a warning listing any flags in set that were
+// set but have no effect.
+func (c *SnapshotRestoreCommand) warnUnusedFlags(set map[string]bool) {
+	unused := c.unusedFlags(set, c.ui.Interactive())
+	if len(unused) == 0 {
+		return
+	}
+
+	var names []string
+	for name := range unused {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var lines []string
+	for _, name := range names {
+		lines = append(lines, "  -"+name+": "+unused[name])
+	}
+
+	c.ui.Output(
+		"The following flags have no effect and will be ignored:\n%s",
+		strings.Join(lines, "\n"),
+		terminal.WithWarningStyle(),
+	)
+}
+
+// validateSkipRecordTypes returns an error if any of the types given with
+// -skip-record-type isn't a record type the server restores. The server
+// checks this too, but only once the upload has started, so checking
+// here means a typo fails before any snapshot data is sent.
+func (c *SnapshotRestoreCommand) validateSkipRecordTypes(client pb.WaypointClient) error {
+	if len(c.flagSkipRecordTypes) == 0 {
+		return nil
+	}
+
+	resp, err := client.GetRestoreSnapshotPreflight(c.Ctx, &empty.Empty{})
+	if status.Code(err) == codes.Unimplemented {
+		// Older servers don't advertise their record types, so we leave
+		// the check to the server.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return checkRecordTypes(resp.RecordTypes, c.flagSkipRecordTypes)
+}
+
+// checkRecordTypes returns an error if any of types isn't in known.
+func checkRecordTypes(known, types []string) error {
+	knownSet := map[string]bool{}
+	for _, t := range known {
+		knownSet[t] = true
+	}
+
+	for _, t := range types {
+		if !knownSet[t] {
+			return fmt.Errorf(
+				"unknown record type %q given with -skip-record-type, valid record types are: %s",
+				t, strings.Join(known, ", "))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRecordTypes(t *testing.T) {
+	known := []string{"build", "deployment", "jobs"}
+
+	t.Run("known types", func(t *testing.T) {
+		require := require.New(t)
+
+		require.NoError(checkRecordTypes(known, []string{"jobs", "build"}))
+	})
+
+	t.Run("no types", func(t *testing.T) {
+		require := require.New(t)
+
+		require.NoError(checkRecordTypes(known, nil))
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		require := require.New(t)
+
+		err := checkRecordTypes(known, []string{"build", "jbos"})
+		require.Error(err)
+		require.Contains(err.Error(), `"jbos"`)
+		require.Contains(err.Error(), "build, deployment, jobs")
+	})
+
+	t.Run("type the server doesn't restore", func(t *testing.T) {
+		require := require.New(t)
+
+		err := checkRecordTypes([]string{"build"}, []string{"jobs"})
+		require.Error(err)
+		require.Contains(err.Error(), `"jobs"`)
+	})
+}
+
+func TestSnapshotRestoreCommand_unusedFlags(t *testing.T) {
+	cases := []struct {
+		Name        string
+		Args        []string
+		Setup       func(*SnapshotRestoreCommand)
+		Set         []string
+		Interactive bool
+		Unused      []string
+	}{
+		{
+			"none",
+			[]string{"snapshot.bin"},
+			nil,
+			[]string{"chunk-size", "verify", "interactive-diff", "quiet"},
+			true,
+			nil,
+		},
+
+		{
+			"verify with stdin",
+			[]string{"-"},
+			nil,
+			[]string{"verify"},
+			true,
+			[]string{"verify"},
+		},
+
+		{
+			"verify with no snapshot",
+			nil,
+			nil,
+			[]string{"verify"},
+			true,
+			[]string{"verify"},
+		},
+
+		{
+			"verify with merge sources",
+			nil,
+			func(c *SnapshotRestoreCommand) {
+				c.flagMergeSources = []string{"a.bin", "b.bin"}
+			},
+			[]string{"verify"},
+			true,
+			[]string{"verify"},
+		},
+
+		{
+			"verify with a URL",
+			[]string{"s3://bucket/snapshot.bin"},
+			nil,
+			[]string{"verify"},
+			true,
+			nil,
+		},
+
+		{
+			"interactive diff with stdin",
+			[]string{"-"},
+			nil,
+			[]string{"interactive-diff"},
+			true,
+			[]string{"interactive-diff"},
+		},
+
+		{
+			"interactive diff with merge sources",
+			nil,
+			func(c *SnapshotRestoreCommand) {
+				c.flagMergeSources = []string{"a.bin"}
+			},
+			[]string{"interactive-diff"},
+			true,
+			nil,
+		},
+
+		{
+			"transfer flags with check",
+			[]string{"snapshot.bin"},
+			func(c *SnapshotRestoreCommand) {
+				c.flagCheck = true
+			},
+			[]string{"chunk-size", "send-window", "apply-rate", "output-manifest", "check"},
+			true,
+			[]string{"chunk-size", "send-window", "apply-rate", "output-manifest"},
+		},
+
+		{
+			"transfer flags without check",
+			[]string{"snapshot.bin"},
+			nil,
+			[]string{"chunk-size", "send-window", "apply-rate", "output-manifest"},
+			true,
+			nil,
+		},
+
+		{
+			"quiet without a terminal",
+			[]string{"snapshot.bin"},
+			nil,
+			[]string{"quiet"},
+			false,
+			[]string{"quiet"},
+		},
+
+		{
+			"quiet with a terminal",
+			[]string{"snapshot.bin"},
+			nil,
+			[]string{"quiet"},
+			true,
+			nil,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			require := require.New(t)
+
+			c := &SnapshotRestoreCommand{baseCommand: &baseCommand{args: tt.Args}}
+			if tt.Setup != nil {
+				tt.Setup(c)
+			}
+
+			set := map[string]bool{}
+			for _, name := range tt.Set {
+				set[name] = true
+			}
+
+			var unused []string
+			for name, reason := range c.unusedFlags(set, tt.Interactive) {
+				require.NotEmpty(reason)
+				unused = append(unused, name)
+			}
+			require.ElementsMatch(tt.Unused, unused)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type SnapshotRestoreStateCommand struct {
+	*baseCommand
+}
+
+func (c *SnapshotRestoreStateCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	if len(c.args) != 1 {
+		c.ui.Output("A single resume ID is required.\n\n"+c.Help(),
+			terminal.WithErrorStyle())
+		return 1
+	}
+	id := c.args[0]
+
+	resp, err := c.project.Client().GetRestoreSnapshotOffset(c.Ctx, &pb.GetRestoreSnapshotOffsetRequest{
+		ResumeId: id,
+	})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if c.jsonOutput() {
+		output := map[string]interface{}{
+			"resume_id": id,
+			"offset":    resp.Offset,
+		}
+		if resp.UpdatedAt != nil {
+			output["updated_at"] = resp.UpdatedAt.AsTime().Format(time.RFC3339Nano)
+		}
+
+		if err := c.writeJSON(output); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
+	// Servers before the upload time was reported don't send it.
+	updated := "unknown"
+	if resp.UpdatedAt != nil {
+		t := resp.UpdatedAt.AsTime()
+		updated = t.Local().Format(time.RFC1123) + " (" + humanize.Time(t) + ")"
+	}
+
+	c.ui.NamedValues([]terminal.NamedValue{
+		{Name: "Resume ID", Value: id},
+		{Name: "Received", Value: humanize.Bytes(resp.Offset)},
+		{Name: "Offset", Value: resp.Offset},
+		{Name: "Last updated", Value: updated},
+	})
+
+	return 0
+}
+
+func (c *SnapshotRestoreStateCommand) Flags() *flag.Sets {
+	return c.flagSet(flagSetOutput, nil)
+}
+
+func (c *SnapshotRestoreStateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *SnapshotRestoreStateCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *SnapshotRestoreStateCommand) Synopsis() string {
+	return "Show the state of an interrupted resumable restore."
+}
+
+func (c *SnapshotRestoreStateCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint server snapshot restore-state RESUME-ID
+
+	Show how much of the data of an interrupted resumable restore the server
+	has received and when it last stored data for it. The resume ID is shown
+	by "waypoint server restore" when a restore with -resume-retries fails.
+
+	Pass the same ID to "waypoint server restore" with -resume-id to resume
+	the restore from the offset shown. The server deletes the data of
+	restores that aren't resumed for a day, so a restore that won't be
+	resumed doesn't need to be cleaned up.
+
+	This fails with a not found error once the restore has completed or its
+	data has been deleted.
+
+`)
+}
@@ -0,0 +1,284 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint/internal/server"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// restoreSender sends restore data to the server. If retries is non-zero,
+// the restore is resumable: when the stream fails, the sender reconnects
+// and continues from the data the server acknowledged receiving. The data
+// is spooled to a temporary file so that any the server didn't receive
+// can be sent again.
+type restoreSender struct {
+	ctx    context.Context
+	client pb.WaypointClient
+	open   *pb.RestoreSnapshotRequest_Open
+	stream pb.Waypoint_RestoreSnapshotClient
+
+	// retries is the number of times left that we may resume the restore.
+	retries int
+
+	// onResume, if set, is called before each attempt to resume with the
+	// error that caused the stream to fail.
+	onResume func(err error)
+
+	// spool holds the data sent so far if the restore is resumable, and
+	// sent is the number of bytes sent.
+	spool *os.File
+	sent  int64
+
+	// skip is the number of bytes at the start of the data that aren't
+	// sent since the server received them for an earlier restore with the
+	// same resume ID.
+	skip int64
+}
+
+// newRestoreSender opens a restore stream with the given options. If
+// retries is greater than zero, the restore is resumable. If resumeId is
+// set, the interrupted restore with that ID is resumed and the data the
+// server already has is skipped. Close must be called to clean up once
+// the restore is complete or abandoned.
+func newRestoreSender(
+	ctx context.Context,
+	client pb.WaypointClient,
+	open *pb.RestoreSnapshotRequest_Open,
+	retries int,
+	resumeId string,
+) (*restoreSender, error) {
+	s := &restoreSender{
+		ctx:     ctx,
+		client:  client,
+		open:    open,
+		retries: retries,
+	}
+
+	if resumeId != "" {
+		resp, err := client.GetRestoreSnapshotOffset(ctx, &pb.GetRestoreSnapshotOffsetRequest{
+			ResumeId: resumeId,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		open.ResumeId = resumeId
+		s.skip = int64(resp.Offset)
+	} else if retries > 0 {
+		id, err := server.Id()
+		if err != nil {
+			return nil, err
+		}
+		open.ResumeId = id
+	}
+
+	if retries > 0 {
+		var err error
+		s.spool, err = ioutil.TempFile("", "waypoint-restore")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.connect(s.skip); err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Send sends the next chunk of restore data.
+func (s *restoreSender) Send(p []byte) error {
+	if s.spool != nil {
+		if _, err := s.spool.Write(p); err != nil {
+			return err
+		}
+	}
+	s.sent += int64(len(p))
+
+	// The server already has the start of the data of a resumed restore.
+	if s.skip > 0 {
+		n := int64(len(p))
+		if n > s.skip {
+			n = s.skip
+		}
+
+		p = p[n:]
+		s.skip -= n
+		if len(p) == 0 {
+			return nil
+		}
+	}
+
+	err := s.stream.Send(&pb.RestoreSnapshotRequest{
+		Event: &pb.RestoreSnapshotRequest_Chunk{
+			Chunk: p,
+		},
+	})
+	if err == io.EOF {
+		// The stream was aborted, the actual error is returned by receiving.
+		_, err = s.stream.CloseAndRecv()
+	}
+	if err != nil {
+		return s.resume(err)
+	}
+
+	return nil
+}
+
+// CloseAndRecv closes the send side of the stream, which commits the
+// restore, and waits for the server to stage the restore.
+func (s *restoreSender) CloseAndRecv() error {
+	for {
+		_, err := s.stream.CloseAndRecv()
+		if err == nil {
+			return nil
+		}
+
+		// If the server exits after staging, the stream failing is expected
+		// so we don't try to resume.
+		if s.open.Exit && !s.open.DryRun {
+			return err
+		}
+
+		if err := s.resume(err); err != nil {
+			return err
+		}
+	}
+}
+
+// ResumeId returns the ID to resume the restore with after it failed with
+// err, or "" if it can't be resumed.
+func (s *restoreSender) ResumeId(err error) string {
+	// Errors from the server about the restore itself, such as an invalid
+	// snapshot, would fail the same way if the restore was resumed. The
+	// server keeps the data it received for any other error, including
+	// the restore being canceled.
+	if _, ok := status.FromError(err); ok && !restoreRetryable(err) && status.Code(err) != codes.Canceled {
+		return ""
+	}
+
+	return s.open.ResumeId
+}
+
+// Close removes the spooled data.
+func (s *restoreSender) Close() error {
+	if s.spool == nil {
+		return nil
+	}
+
+	s.spool.Close()
+	return os.Remove(s.spool.Name())
+}
+
+// connect opens a new stream for data starting at offset.
+func (s *restoreSender) connect(offset int64) error {
+	stream, err := s.client.RestoreSnapshot(s.ctx)
+	if err != nil {
+		return err
+	}
+
+	s.open.Offset = uint64(offset)
+	if err := stream.Send(&pb.RestoreSnapshotRequest{
+		Event: &pb.RestoreSnapshotRequest_Open_{
+			Open: s.open,
+		},
+	}); err != nil {
+		return err
+	}
+
+	s.stream = stream
+	return nil
+}
+
+// resume tries to resume the restore after the stream failed with err. If
+// the restore can't be resumed, the error is returned.
+func (s *restoreSender) resume(err error) error {
+	for attempt := 1; s.spool != nil && s.retries > 0 && restoreRetryable(err); attempt++ {
+		s.retries--
+		if s.onResume != nil {
+			s.onResume(err)
+		}
+
+		// Back off a little so that the server can notice the old stream
+		// failed and a temporary network issue has a chance to clear up.
+		select {
+		case <-time.After(time.Duration(attempt) * time.Second):
+		case <-s.ctx.Done():
+			return s.ctx.Err()
+		}
+
+		var resp *pb.GetRestoreSnapshotOffsetResponse
+		resp, err = s.client.GetRestoreSnapshotOffset(s.ctx, &pb.GetRestoreSnapshotOffsetRequest{
+			ResumeId: s.open.ResumeId,
+		})
+		if err != nil {
+			continue
+		}
+
+		offset := int64(resp.Offset)
+		if err = s.connect(offset); err != nil {
+			continue
+		}
+		if err = s.resend(offset); err != nil {
+			continue
+		}
+
+		return nil
+	}
+
+	return err
+}
+
+// resend sends the spooled data from offset up to what has been sent.
+func (s *restoreSender) resend(offset int64) error {
+	buf := make([]byte, 64*1024)
+	for offset < s.sent {
+		n := int64(len(buf))
+		if remaining := s.sent - offset; remaining < n {
+			n = remaining
+		}
+
+		if _, err := s.spool.ReadAt(buf[:n], offset); err != nil {
+			return err
+		}
+
+		err := s.stream.Send(&pb.RestoreSnapshotRequest{
+			Event: &pb.RestoreSnapshotRequest_Chunk{
+				Chunk: buf[:n],
+			},
+		})
+		if err == io.EOF {
+			_, err = s.stream.CloseAndRecv()
+		}
+		if err != nil {
+			return err
+		}
+
+		offset += n
+	}
+
+	return nil
+}
+
+// restoreRetryable returns true if a restore stream that failed with err
+// may succeed if resumed. Errors returned by the server about the restore
+// itself, such as an invalid snapshot, are not retryable.
+func restoreRetryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.Aborted, codes.DeadlineExceeded:
+		return true
+
+	default:
+		return false
+	}
+}
@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// isSnapshotURL returns true if the snapshot path looks like an object
+// storage URL rather than a local file.
+func isSnapshotURL(path string) bool {
+	return strings.Contains(path, "://")
+}
+
+// parseSnapshotURL parses an object storage URL, returning the bucket and
+// key. Only s3:// URLs are currently supported.
+func parseSnapshotURL(raw string) (string, string, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", "", err
+	}
+
+	switch u.Scheme {
+	case "s3":
+	case "gs", "azblob":
+		return "", "", fmt.Errorf("%s:// URLs are not supported, only s3:// URLs are", u.Scheme)
+	default:
+		return "", "", fmt.Errorf("unsupported snapshot URL scheme %q, only s3:// URLs are supported", u.Scheme)
+	}
+
+	key := strings.TrimPrefix(u.Path, "/")
+	if u.Host == "" || key == "" {
+		return "", "", fmt.Errorf("snapshot URL must have the form s3://bucket/key")
+	}
+
+	return u.Host, key, nil
+}
+
+// s3Session returns an AWS session for the given bucket using the standard
+// AWS credential chain. If no region is configured, the region of the
+// bucket is looked up.
+func s3Session(ctx context.Context, bucket string) (*session.Session, error) {
+	sess, err := session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if aws.StringValue(sess.Config.Region) == "" {
+		region, err := s3manager.GetBucketRegion(ctx, sess, bucket, "us-east-1")
+		if err != nil {
+			return nil, fmt.Errorf("error determining region of bucket %q: %s", bucket, err)
+		}
+
+		sess = sess.Copy(aws.NewConfig().WithRegion(region))
+	}
+
+	return sess, nil
+}
+
+// openSnapshotURL opens the snapshot at the given URL for reading.
+func openSnapshotURL(ctx context.Context, raw string) (io.ReadCloser, error) {
+	bucket, key, err := parseSnapshotURL(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	sess, err := s3Session(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := s3.New(sess).GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out.Body, nil
+}
+
+// errSnapshotUploadAborted is the error the upload is aborted with if the
+// writer is closed without being committed.
+var errSnapshotUploadAborted = errors.New("snapshot upload aborted")
+
+// snapshotUploader streams the data written to it to object storage.
+// Commit must be called to complete the upload. Closing the uploader
+// without committing aborts the upload so that partial snapshots are
+// never stored.
+type snapshotUploader struct {
+	pw     *io.PipeWriter
+	doneCh chan error
+	err    error
+	done   bool
+}
+
+// createSnapshotURL starts an upload of a snapshot to the given URL.
+func createSnapshotURL(ctx context.Context, raw string) (*snapshotUploader, error) {
+	bucket, key, err := parseSnapshotURL(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	sess, err := s3Session(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	u := &snapshotUploader{
+		pw:     pw,
+		doneCh: make(chan error, 1),
+	}
+
+	go func() {
+		_, err := s3manager.NewUploader(sess).UploadWithContext(ctx, &s3manager.UploadInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+			Body:   pr,
+		})
+
+		// Unblock any writers if the upload failed.
+		pr.CloseWithError(err)
+		u.doneCh <- err
+	}()
+
+	return u, nil
+}
+
+func (u *snapshotUploader) Write(p []byte) (int, error) {
+	return u.pw.Write(p)
+}
+
+// Commit completes the upload and waits for it to finish.
+func (u *snapshotUploader) Commit() error {
+	return u.finish(u.pw.Close())
+}
+
+// Close aborts the upload if it wasn't committed.
+func (u *snapshotUploader) Close() error {
+	u.finish(u.pw.CloseWithError(errSnapshotUploadAborted))
+	return nil
+}
+
+func (u *snapshotUploader) finish(err error) error {
+	if !u.done {
+		u.done = true
+		u.err = <-u.doneCh
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return u.err
+}
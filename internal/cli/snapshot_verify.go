@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type SnapshotVerifyCommand struct {
+	*baseCommand
+
+	// set via -encrypt-key and -encrypt-key-file, the key to decrypt the
+	// snapshot with.
+	flagKey snapshotKeyFlags
+}
+
+func (c *SnapshotVerifyCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+		WithClient(false),
+	); err != nil {
+		return 1
+	}
+
+	key, err := c.flagKey.Key()
+	if err != nil {
+		c.ui.Output("Invalid encryption key: %s", err, terminal.WithErrorStyle())
+		return 1
+	}
+
+	r, closer, err := openSnapshotReader(c.Ctx, c.args, c.nonInteractive())
+	if err != nil {
+		c.outputError("Failed to open snapshot", err)
+		return 1
+	}
+	if closer != nil {
+		defer closer.Close()
+	}
+
+	r, err = decryptSnapshot(r, key)
+	if err == nil {
+		r, err = unwrapSnapshot(r)
+	}
+
+	var chunks, records int
+	var id string
+	if err == nil {
+		_, id, err = scanSnapshot(r, func(_ *pb.Snapshot_Header, chunk *pb.Snapshot_BoltChunk) error {
+			chunks++
+			records += len(chunk.Items)
+			return nil
+		})
+	}
+	if err != nil {
+		c.ui.Output("Snapshot is not valid: %s", clierrors.Humanize(err),
+			terminal.WithErrorStyle())
+		return 1
+	}
+
+	c.ui.Output("Snapshot is valid.", terminal.WithSuccessStyle())
+	c.ui.NamedValues([]terminal.NamedValue{
+		{Name: "ID", Value: id},
+		{Name: "Chunks", Value: chunks},
+		{Name: "Records", Value: records},
+	})
+
+	return 0
+}
+
+// verifySnapshotSource opens the snapshot named by args[0] and verifies
+// its checksums. The snapshot must not be read from stdin since it can't
+// be read again afterwards.
+func verifySnapshotSource(ctx context.Context, args []string, key []byte) error {
+	r, closer, err := openSnapshotReader(ctx, args, true)
+	if err != nil {
+		return err
+	}
+	if closer != nil {
+		defer closer.Close()
+	}
+
+	r, err = decryptSnapshot(r, key)
+	if err != nil {
+		return err
+	}
+	r, err = unwrapSnapshot(r)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = scanSnapshot(r, func(*pb.Snapshot_Header, *pb.Snapshot_BoltChunk) error {
+		return nil
+	})
+	return err
+}
+
+func (c *SnapshotVerifyCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		c.flagKey.addFlags(f)
+	})
+}
+
+func (c *SnapshotVerifyCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFiles("")
+}
+
+func (c *SnapshotVerifyCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *SnapshotVerifyCommand) Synopsis() string {
+	return "Verify the checksums of a snapshot."
+}
+
+func (c *SnapshotVerifyCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint server snapshot-verify [<filename>]
+
+	Read a snapshot and verify that it is complete and not corrupted. The
+	checksum of each chunk of data in the snapshot is verified, as well as the
+	checksum of the snapshot as a whole. This exits with a non-zero exit code
+	if the snapshot is not valid.
+
+	The server also verifies snapshots before it stages a restore, so a
+	corrupted snapshot is never restored. This command can be used to check
+	backups ahead of time.
+
+	The snapshot is read from the given file, or from standard in if no name is
+	specified and standard in is not a terminal. Using a name of '-' will force
+	reading from standard in. An s3://bucket/key URL may also be used.
+
+` + c.Flags().Help())
+}
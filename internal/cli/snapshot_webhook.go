@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/waypoint/internal/server"
+)
+
+// restoreWebhookSignatureHeader is the header containing the HMAC-SHA256
+// signature of the request body when a webhook secret is set.
+const restoreWebhookSignatureHeader = "X-Waypoint-Signature"
+
+// restoreWebhookEvent is the JSON body POSTed to the -webhook-url.
+type restoreWebhookEvent struct {
+	// Event is "start" or "complete".
+	Event     string    `json:"event"`
+	RequestId string    `json:"request_id"`
+	Source    string    `json:"source"`
+	Time      time.Time `json:"time"`
+
+	// These are only set for the "complete" event. Outcome is "success"
+	// or "failure".
+	Bytes   int64  `json:"bytes,omitempty"`
+	Outcome string `json:"outcome,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// restoreWebhook sends restore events to a webhook.
+type restoreWebhook struct {
+	url       string
+	secret    string
+	requestId string
+	source    string
+	client    *http.Client
+}
+
+func newRestoreWebhook(url, secret, source string) (*restoreWebhook, error) {
+	id, err := server.Id()
+	if err != nil {
+		return nil, err
+	}
+
+	return &restoreWebhook{
+		url:       url,
+		secret:    secret,
+		requestId: id,
+		source:    source,
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Start sends the event for the start of the restore.
+func (w *restoreWebhook) Start(ctx context.Context) error {
+	return w.send(ctx, &restoreWebhookEvent{Event: "start"})
+}
+
+// Complete sends the event for the end of the restore. If err is non-nil
+// the restore is reported as failed.
+func (w *restoreWebhook) Complete(ctx context.Context, n int64, err error) error {
+	ev := &restoreWebhookEvent{
+		Event:   "complete",
+		Bytes:   n,
+		Outcome: "success",
+	}
+	if err != nil {
+		ev.Outcome = "failure"
+		ev.Error = err.Error()
+	}
+
+	return w.send(ctx, ev)
+}
+
+func (w *restoreWebhook) send(ctx context.Context, ev *restoreWebhookEvent) error {
+	ev.RequestId = w.requestId
+	ev.Source = w.source
+	ev.Time = time.Now().UTC()
+
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.secret != "" {
+		req.Header.Set(restoreWebhookSignatureHeader, "sha256="+signRestoreWebhook(w.secret, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %s", resp.Status)
+	}
+
+	return nil
+}
+
+// signRestoreWebhook returns the hex-encoded HMAC-SHA256 of body.
+func signRestoreWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// restoreWindow is a recurring window of time during which a restore is
+// allowed to proceed. It is parsed from a spec of the form
+// "[DAYS ]HH:MM-HH:MM" where DAYS is an optional comma-separated list of
+// weekdays or weekday ranges, for example "Sat,Sun 02:00-06:00" or
+// "Mon-Fri 22:00-04:00". Windows whose end is before their start wrap
+// past midnight, in which case DAYS refers to the day the window opens.
+type restoreWindow struct {
+	spec  string
+	days  map[time.Weekday]bool
+	start time.Duration
+	end   time.Duration
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// parseRestoreWindow parses a restore window spec. See restoreWindow for
+// the accepted format.
+func parseRestoreWindow(spec string) (*restoreWindow, error) {
+	w := &restoreWindow{spec: spec}
+
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 1:
+		// Time range only, every day is allowed.
+
+	case 2:
+		days, err := parseWeekdays(fields[0])
+		if err != nil {
+			return nil, err
+		}
+
+		w.days = days
+		fields = fields[1:]
+
+	default:
+		return nil, fmt.Errorf(
+			"invalid window %q, expected the form \"[DAYS ]HH:MM-HH:MM\"", spec)
+	}
+
+	idx := strings.Index(fields[0], "-")
+	if idx == -1 {
+		return nil, fmt.Errorf(
+			"invalid window %q, time range must be of the form HH:MM-HH:MM", spec)
+	}
+
+	var err error
+	if w.start, err = parseClock(fields[0][:idx]); err != nil {
+		return nil, err
+	}
+	if w.end, err = parseClock(fields[0][idx+1:]); err != nil {
+		return nil, err
+	}
+	if w.start == w.end {
+		return nil, fmt.Errorf("invalid window %q, start and end must differ", spec)
+	}
+
+	return w, nil
+}
+
+// Contains returns true if the time t is within the window. The window is
+// evaluated in the location of t.
+func (w *restoreWindow) Contains(t time.Time) bool {
+	// Use the wall clock time of day rather than the time elapsed since
+	// midnight, which differs by an hour on daylight saving time changes.
+	offset := time.Duration(t.Hour())*time.Hour +
+		time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second +
+		time.Duration(t.Nanosecond())
+
+	// Normal window within a single day.
+	if w.start < w.end {
+		return w.dayAllowed(t.Weekday()) && offset >= w.start && offset < w.end
+	}
+
+	// The window wraps past midnight. We're either in the opening portion
+	// of today's window or the closing portion of yesterday's.
+	if offset >= w.start {
+		return w.dayAllowed(t.Weekday())
+	}
+	if offset < w.end {
+		return w.dayAllowed((t.Weekday() + 6) % 7)
+	}
+
+	return false
+}
+
+func (w *restoreWindow) String() string {
+	return w.spec
+}
+
+func (w *restoreWindow) dayAllowed(d time.Weekday) bool {
+	return w.days == nil || w.days[d]
+}
+
+// parseWeekdays parses a comma-separated list of weekdays such as
+// "Mon,Wed" or "Mon-Fri".
+func parseWeekdays(v string) (map[time.Weekday]bool, error) {
+	result := map[time.Weekday]bool{}
+	for _, part := range strings.Split(v, ",") {
+		from, to := part, part
+		if idx := strings.Index(part, "-"); idx != -1 {
+			from, to = part[:idx], part[idx+1:]
+		}
+
+		start, ok := weekdayNames[strings.ToLower(from)]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday %q", from)
+		}
+		end, ok := weekdayNames[strings.ToLower(to)]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday %q", to)
+		}
+
+		for d := start; ; d = (d + 1) % 7 {
+			result[d] = true
+			if d == end {
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// parseClock parses a 24-hour "HH:MM" time of day into an offset from
+// midnight.
+func parseClock(v string) (time.Duration, error) {
+	t, err := time.Parse("15:04", v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", v)
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
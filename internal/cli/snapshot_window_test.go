@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRestoreWindow(t *testing.T) {
+	cases := []struct {
+		Name  string
+		Spec  string
+		Err   string
+		Start time.Duration
+		End   time.Duration
+		Days  []time.Weekday
+	}{
+		{
+			Name:  "time range only",
+			Spec:  "02:00-06:00",
+			Start: 2 * time.Hour,
+			End:   6 * time.Hour,
+		},
+		{
+			Name:  "weekday list",
+			Spec:  "Sat,Sun 02:00-06:30",
+			Start: 2 * time.Hour,
+			End:   6*time.Hour + 30*time.Minute,
+			Days:  []time.Weekday{time.Saturday, time.Sunday},
+		},
+		{
+			Name:  "weekday range wrapping the week",
+			Spec:  "fri-mon 22:00-04:00",
+			Start: 22 * time.Hour,
+			End:   4 * time.Hour,
+			Days:  []time.Weekday{time.Friday, time.Saturday, time.Sunday, time.Monday},
+		},
+		{
+			Name: "too many fields",
+			Spec: "Mon 02:00-04:00 extra",
+			Err:  "expected the form",
+		},
+		{
+			Name: "empty",
+			Spec: "",
+			Err:  "expected the form",
+		},
+		{
+			Name: "missing range",
+			Spec: "02:00",
+			Err:  "HH:MM-HH:MM",
+		},
+		{
+			Name: "invalid clock",
+			Spec: "25:00-04:00",
+			Err:  "invalid time of day",
+		},
+		{
+			Name: "invalid weekday",
+			Spec: "Mon,Funday 02:00-04:00",
+			Err:  "invalid weekday",
+		},
+		{
+			Name: "empty window",
+			Spec: "02:00-02:00",
+			Err:  "start and end must differ",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			require := require.New(t)
+
+			w, err := parseRestoreWindow(tt.Spec)
+			if tt.Err != "" {
+				require.Error(err)
+				require.Contains(err.Error(), tt.Err)
+				return
+			}
+
+			require.NoError(err)
+			require.Equal(tt.Start, w.start)
+			require.Equal(tt.End, w.end)
+
+			if tt.Days == nil {
+				require.Nil(w.days)
+				return
+			}
+
+			require.Len(w.days, len(tt.Days))
+			for _, d := range tt.Days {
+				require.True(w.days[d], d.String())
+			}
+		})
+	}
+}
+
+func TestRestoreWindowContains(t *testing.T) {
+	// 2020-11-06 is a Friday.
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2020, 11, day, hour, min, 0, 0, time.UTC)
+	}
+
+	cases := []struct {
+		Name     string
+		Spec     string
+		Time     time.Time
+		Expected bool
+	}{
+		{"within", "02:00-04:00", at(6, 3, 0), true},
+		{"at start", "02:00-04:00", at(6, 2, 0), true},
+		{"at end", "02:00-04:00", at(6, 4, 0), false},
+		{"before", "02:00-04:00", at(6, 1, 59), false},
+		{"day not allowed", "Sat,Sun 02:00-04:00", at(6, 3, 0), false},
+		{"day allowed", "Sat,Sun 02:00-04:00", at(7, 3, 0), true},
+		{"wrapping opening portion", "Fri 22:00-04:00", at(6, 23, 0), true},
+		{"wrapping closing portion", "Fri 22:00-04:00", at(7, 3, 0), true},
+		{"wrapping closing portion of other day", "Fri 22:00-04:00", at(6, 3, 0), false},
+		{"wrapping outside", "Fri 22:00-04:00", at(6, 12, 0), false},
+
+		// The closing portion of a Saturday window falls on Sunday, so the
+		// previous weekday rolls over from Sunday to Saturday.
+		{"weekday rollover", "Sat 22:00-04:00", at(8, 1, 0), true},
+		{"weekday rollover not allowed", "Sun 22:00-04:00", at(8, 1, 0), false},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			require := require.New(t)
+
+			w, err := parseRestoreWindow(tt.Spec)
+			require.NoError(err)
+			require.Equal(tt.Expected, w.Contains(tt.Time))
+		})
+	}
+}
+
+func TestRestoreWindowContains_dst(t *testing.T) {
+	require := require.New(t)
+
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data not available: %s", err)
+	}
+
+	// On 2020-11-01 clocks in New York went back from 02:00 to 01:00, so
+	// 02:30 on the wall clock is three and a half hours after midnight.
+	w, err := parseRestoreWindow("02:00-04:00")
+	require.NoError(err)
+	require.True(w.Contains(time.Date(2020, 11, 1, 2, 30, 0, 0, loc)))
+	require.False(w.Contains(time.Date(2020, 11, 1, 4, 30, 0, 0, loc)))
+
+	// On 2020-03-08 clocks went forward from 02:00 to 03:00, so 04:30 on
+	// the wall clock is three and a half hours after midnight.
+	require.False(w.Contains(time.Date(2020, 3, 8, 4, 30, 0, 0, loc)))
+	require.True(w.Contains(time.Date(2020, 3, 8, 3, 30, 0, 0, loc)))
+}
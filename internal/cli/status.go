@@ -0,0 +1,471 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/posener/complete"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type StatusCommand struct {
+	*baseCommand
+
+	flagId idFormat
+}
+
+// appStatus is the rolled up status of a single application.
+type appStatus struct {
+	Project     string
+	Application string
+
+	// The latest build, deployment, and release. Any of these may be nil
+	// if the app has none.
+	Build      *pb.Build
+	Deployment *pb.Deployment
+	Release    *pb.Release
+
+	// Instances is the number of instances of the latest deployment.
+	Instances int
+
+	// Jobs are the jobs for the app that are queued or running.
+	Jobs []*pb.Job
+}
+
+func (c *StatusCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	if len(c.args) > 1 {
+		c.ui.Output(c.Flags().Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	// An optional PROJECT or PROJECT/APP argument limits the output.
+	var filterProject, filterApp string
+	if len(c.args) == 1 {
+		parts := strings.SplitN(c.args[0], "/", 2)
+		filterProject = parts[0]
+		if len(parts) == 2 {
+			filterApp = parts[1]
+		}
+	}
+
+	statuses, err := c.statuses(c.Ctx, filterProject, filterApp)
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if c.jsonOutput() {
+		if err := c.displayJson(statuses); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
+	if len(statuses) == 0 {
+		if filterProject != "" {
+			c.ui.Output("No applications found for %q.", c.args[0], terminal.WithErrorStyle())
+			return 1
+		}
+
+		c.ui.Output("No projects. Create one with `waypoint init`.")
+		return 0
+	}
+
+	var project string
+	var table *terminal.Table
+	for _, s := range statuses {
+		if s.Project != project {
+			if table != nil {
+				c.ui.Table(table)
+			}
+
+			project = s.Project
+			table = terminal.NewTable("App", "Build", "Deployment", "Health", "Release", "Pending Jobs")
+			c.ui.Output("Project: %s", project, terminal.WithHeaderStyle())
+		}
+
+		health, healthColor := s.health()
+
+		var build, deployment, release string
+		if b := s.Build; b != nil {
+			build = fmt.Sprintf("%s %s", c.flagId.FormatId(b.Sequence, b.Id), statusSummary(b.Status))
+		}
+		if d := s.Deployment; d != nil {
+			deployment = fmt.Sprintf("%s %s", c.flagId.FormatId(d.Sequence, d.Id), statusSummary(d.Status))
+		}
+		if r := s.Release; r != nil {
+			release = c.flagId.FormatId(r.Sequence, r.Id)
+			if r.Url != "" {
+				release += " " + r.Url
+			}
+		}
+
+		var jobs string
+		if len(s.Jobs) > 0 {
+			var ops []string
+			for _, job := range s.Jobs {
+				ops = append(ops, jobOperationName(job))
+			}
+
+			jobs = fmt.Sprintf("%d (%s)", len(s.Jobs), strings.Join(ops, ", "))
+		}
+
+		table.Rich([]string{
+			s.Application,
+			build,
+			deployment,
+			health,
+			release,
+			jobs,
+		}, []string{
+			"",
+			"",
+			"",
+			healthColor,
+		})
+	}
+	c.ui.Table(table)
+
+	return 0
+}
+
+// statuses returns the status of every app on the server, optionally limited
+// to a single project or app, sorted by project and app name.
+func (c *StatusCommand) statuses(ctx context.Context, project, app string) ([]*appStatus, error) {
+	client := c.project.Client()
+
+	var projects []*pb.Ref_Project
+	if project != "" {
+		projects = append(projects, &pb.Ref_Project{Project: project})
+	} else {
+		resp, err := client.ListProjects(ctx, &empty.Empty{})
+		if err != nil {
+			return nil, err
+		}
+
+		projects = resp.Projects
+	}
+
+	// Get all the jobs once and find the pending ones for each app rather
+	// than querying per app.
+	jobsResp, err := client.XListJobs(ctx, &pb.ListJobsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	pending := map[string][]*pb.Job{}
+	for _, job := range jobsResp.Jobs {
+		switch job.State {
+		case pb.Job_QUEUED, pb.Job_WAITING, pb.Job_RUNNING:
+		default:
+			continue
+		}
+
+		if job.Application == nil {
+			continue
+		}
+
+		key := job.Application.Project + "/" + job.Application.Application
+		pending[key] = append(pending[key], job)
+	}
+
+	var result []*appStatus
+	for _, ref := range projects {
+		resp, err := client.GetProject(ctx, &pb.GetProjectRequest{Project: ref})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, a := range resp.Project.Applications {
+			if app != "" && a.Name != app {
+				continue
+			}
+
+			appRef := &pb.Ref_Application{
+				Project:     resp.Project.Name,
+				Application: a.Name,
+			}
+
+			s, err := c.appStatus(ctx, appRef)
+			if err != nil {
+				return nil, err
+			}
+			s.Jobs = pending[appRef.Project+"/"+appRef.Application]
+
+			result = append(result, s)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Project != result[j].Project {
+			return result[i].Project < result[j].Project
+		}
+
+		return result[i].Application < result[j].Application
+	})
+
+	return result, nil
+}
+
+// appStatus returns the status of a single app in the current workspace.
+// The pending jobs are not set.
+func (c *StatusCommand) appStatus(ctx context.Context, ref *pb.Ref_Application) (*appStatus, error) {
+	client := c.project.Client()
+	result := &appStatus{
+		Project:     ref.Project,
+		Application: ref.Application,
+	}
+
+	build, err := client.GetLatestBuild(ctx, &pb.GetLatestBuildRequest{
+		Application: ref,
+		Workspace:   c.refWorkspace,
+	})
+	if status.Code(err) == codes.NotFound {
+		err = nil
+		build = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.Build = build
+
+	deployResp, err := client.ListDeployments(ctx, &pb.ListDeploymentsRequest{
+		Application: ref,
+		Workspace:   c.refWorkspace,
+		Order: &pb.OperationOrder{
+			Limit: 1,
+			Order: pb.OperationOrder_START_TIME,
+			Desc:  true,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(deployResp.Deployments) > 0 {
+		result.Deployment = deployResp.Deployments[0]
+
+		instResp, err := client.ListInstances(ctx, &pb.ListInstancesRequest{
+			Scope: &pb.ListInstancesRequest_DeploymentId{
+				DeploymentId: result.Deployment.Id,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		result.Instances = len(instResp.Instances)
+	}
+
+	release, err := client.GetLatestRelease(ctx, &pb.GetLatestReleaseRequest{
+		Application: ref,
+		Workspace:   c.refWorkspace,
+	})
+	if status.Code(err) == codes.NotFound {
+		err = nil
+		release = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.Release = release
+
+	return result, nil
+}
+
+// health returns a short description of the health of the latest
+// deployment and the color to show it in.
+func (s *appStatus) health() (string, string) {
+	d := s.Deployment
+	if d == nil {
+		return "", ""
+	}
+
+	switch {
+	case d.Status.State == pb.Status_RUNNING:
+		return "deploying", terminal.Yellow
+
+	case d.Status.State == pb.Status_ERROR:
+		return "failed", terminal.Red
+
+	case d.State == pb.Operation_DESTROYED:
+		return "destroyed", ""
+
+	case s.Instances > 0:
+		return fmt.Sprintf("%d instance(s)", s.Instances), terminal.Green
+
+	case d.HasEntrypointConfig:
+		// The entrypoint connected before but no instances are
+		// connected now, so the deployment may be down.
+		return "no instances", terminal.Yellow
+
+	default:
+		// Without the entrypoint we have no instance information.
+		return "deployed", terminal.Green
+	}
+}
+
+func (c *StatusCommand) displayJson(statuses []*appStatus) error {
+	output := []map[string]interface{}{}
+
+	for _, s := range statuses {
+		i := map[string]interface{}{}
+
+		i["project"] = s.Project
+		i["application"] = s.Application
+		i["workspace"] = c.refWorkspace.Workspace
+
+		if b := s.Build; b != nil {
+			i["build"] = map[string]interface{}{
+				"id":       b.Id,
+				"sequence": b.Sequence,
+				"state":    b.Status.State.String(),
+			}
+		}
+
+		if d := s.Deployment; d != nil {
+			health, _ := s.health()
+			i["deployment"] = map[string]interface{}{
+				"id":             d.Id,
+				"sequence":       d.Sequence,
+				"state":          d.Status.State.String(),
+				"physical_state": d.State.String(),
+				"health":         health,
+				"instances":      s.Instances,
+			}
+		}
+
+		if r := s.Release; r != nil {
+			i["release"] = map[string]interface{}{
+				"id":            r.Id,
+				"sequence":      r.Sequence,
+				"deployment_id": r.DeploymentId,
+				"url":           r.Url,
+			}
+		}
+
+		jobs := []map[string]interface{}{}
+		for _, job := range s.Jobs {
+			jobs = append(jobs, map[string]interface{}{
+				"id":        job.Id,
+				"operation": jobOperationName(job),
+				"state":     job.State.String(),
+			})
+		}
+		i["pending_jobs"] = jobs
+
+		output = append(output, i)
+	}
+
+	return c.writeJSON(output)
+}
+
+// statusSummary returns a short description of an operation status such
+// as "success 2 hours ago".
+func statusSummary(s *pb.Status) string {
+	if s == nil {
+		return ""
+	}
+
+	switch s.State {
+	case pb.Status_RUNNING:
+		return "running"
+
+	case pb.Status_SUCCESS, pb.Status_ERROR:
+		result := strings.ToLower(s.State.String())
+		if t, err := ptypes.Timestamp(s.CompleteTime); err == nil {
+			result += " " + humanize.Time(t)
+		}
+
+		return result
+
+	default:
+		return "unknown"
+	}
+}
+
+// jobOperationName returns the name of the operation the job runs.
+func jobOperationName(job *pb.Job) string {
+	switch job.Operation.(type) {
+	case *pb.Job_Noop_:
+		return "noop"
+	case *pb.Job_Build:
+		return "build"
+	case *pb.Job_Push:
+		return "push"
+	case *pb.Job_Deploy:
+		return "deploy"
+	case *pb.Job_Destroy:
+		return "destroy"
+	case *pb.Job_Release:
+		return "release"
+	case *pb.Job_Validate:
+		return "validate"
+	case *pb.Job_Auth:
+		return "auth"
+	case *pb.Job_Docs:
+		return "docs"
+	case *pb.Job_ConfigSync:
+		return "config sync"
+	default:
+		return "unknown"
+	}
+}
+
+func (c *StatusCommand) Flags() *flag.Sets {
+	return c.flagSet(flagSetCache|flagSetOutput, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		initIdFormat(f, &c.flagId)
+	})
+}
+
+func (c *StatusCommand) AutocompleteArgs() complete.Predictor {
+	return c.predictAppTarget()
+}
+
+func (c *StatusCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *StatusCommand) Synopsis() string {
+	return "Show the status of all projects and applications."
+}
+
+func (c *StatusCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint status [PROJECT[/APP]]
+
+  Show the status of every application known to the server.
+
+  For each application this shows the latest build, the latest deployment
+  and its health, the active release, and any jobs that are queued or
+  running. Pass a project or project/app to limit the output.
+
+  The health of a deployment is the number of instances connected to the
+  server through the Waypoint entrypoint. Deployments without the
+  entrypoint show "deployed" once they succeed.
+
+  Builds, deployments, and releases are shown for the workspace given by
+  -workspace.
+
+` + c.Flags().Help())
+}
@@ -5,7 +5,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
 	"github.com/hashicorp/waypoint/internal/clierrors"
 	"github.com/hashicorp/waypoint/internal/pkg/flag"
@@ -17,6 +16,7 @@ type GetInviteCommand struct {
 	*baseCommand
 
 	duration time.Duration
+	access   tokenAccessFlags
 }
 
 func (c *GetInviteCommand) Run(args []string) int {
@@ -32,9 +32,9 @@ func (c *GetInviteCommand) Run(args []string) int {
 	// Get our API client
 	client := c.project.Client()
 
-	resp, err := client.GenerateInviteToken(c.Ctx, &pb.InviteTokenRequest{
-		Duration: c.duration.String(),
-	})
+	req := &pb.InviteTokenRequest{Duration: c.duration.String()}
+	c.access.apply(req)
+	resp, err := client.GenerateInviteToken(c.Ctx, req)
 	if err != nil {
 		c.project.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
 		return 1
@@ -55,6 +55,8 @@ func (c *GetInviteCommand) Flags() *flag.Sets {
 			Usage:   "How long the invite token will valid for, starting now.",
 			Default: 5 * time.Minute,
 		})
+
+		c.access.flags(f)
 	})
 }
 
@@ -76,6 +78,10 @@ Usage: waypoint token invite [options]
 
   Request a new invite token. This token can be exchanged for a normal token to login.
 
+  The token it is exchanged for has the role and is limited to the projects,
+  workspaces, and organization given here. By default, it is an admin for
+  every project.
+
 ` + c.Flags().Help())
 }
 
@@ -154,6 +160,9 @@ Usage: waypoint token exchange [options]
 
 type GetTokenCommand struct {
 	*baseCommand
+
+	ttl    time.Duration
+	access tokenAccessFlags
 }
 
 func (c *GetTokenCommand) Run(args []string) int {
@@ -169,7 +178,17 @@ func (c *GetTokenCommand) Run(args []string) int {
 	// Get our API client
 	client := c.project.Client()
 
-	resp, err := client.GenerateLoginToken(c.Ctx, &empty.Empty{})
+	req := &pb.LoginTokenRequest{
+		Role:         c.access.tokenRole(),
+		Projects:     c.access.projects,
+		Workspaces:   c.access.workspaces,
+		Organization: c.access.org,
+	}
+	if c.ttl > 0 {
+		req.Duration = c.ttl.String()
+	}
+
+	resp, err := client.GenerateLoginToken(c.Ctx, req)
 	if err != nil {
 		c.project.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
 		return 1
@@ -182,7 +201,16 @@ func (c *GetTokenCommand) Run(args []string) int {
 }
 
 func (c *GetTokenCommand) Flags() *flag.Sets {
-	return c.flagSet(0, func(set *flag.Sets) {})
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.DurationVar(&flag.DurationVar{
+			Name:   "ttl",
+			Target: &c.ttl,
+			Usage:  "How long the token is valid for, starting now. By default, the token doesn't expire.",
+		})
+
+		c.access.flags(f)
+	})
 }
 
 func (c *GetTokenCommand) AutocompleteArgs() complete.Predictor {
@@ -203,7 +231,67 @@ Usage: waypoint token new [options]
 
   Request a new token to log into the server.
 
+  By default, the token is an admin for every project and doesn't expire.
+  Use the flags below to create a token with a different role, that is
+  limited to some projects, workspaces, or an organization, or that expires.
+
+  Tokens can be listed with "waypoint token list" and revoked with
+  "waypoint token revoke".
+
 ` + c.Flags().Help()
 
 	return strings.TrimSpace(helpText)
 }
+
+// tokenAccessFlags are the flags for the role and scope of a new token.
+type tokenAccessFlags struct {
+	role       string
+	projects   []string
+	workspaces []string
+	org        string
+}
+
+func (a *tokenAccessFlags) flags(f *flag.Set) {
+	f.EnumSingleVar(&flag.EnumSingleVar{
+		Name:    "role",
+		Target:  &a.role,
+		Values:  []string{"admin", "operator", "read-only"},
+		Default: "admin",
+		Usage: "The role of the token. Operators can do anything to projects, such " +
+			"as deploying, but can't perform server-wide operations such as restoring " +
+			"snapshots or creating tokens. Read-only tokens can't change anything.",
+	})
+
+	f.StringSliceVar(&flag.StringSliceVar{
+		Name:   "scope-project",
+		Target: &a.projects,
+		Usage: "Limit the token to this project. This can be specified multiple " +
+			"times. Tokens limited to projects or workspaces can't perform " +
+			"server-wide operations.",
+	})
+
+	f.StringSliceVar(&flag.StringSliceVar{
+		Name:   "scope-workspace",
+		Target: &a.workspaces,
+		Usage:  "Limit the token to this workspace. This can be specified multiple times.",
+	})
+
+	f.StringVar(&flag.StringVar{
+		Name:   "scope-org",
+		Target: &a.org,
+		Usage: "Limit the token to the projects of this organization. Projects " +
+			"the token creates are added to the organization.",
+	})
+}
+
+// tokenRole returns the role given by the -role flag.
+func (a *tokenAccessFlags) tokenRole() pb.Token_Role {
+	return pb.Token_Role(pb.Token_Role_value[strings.ToUpper(strings.Replace(a.role, "-", "_", -1))])
+}
+
+func (a *tokenAccessFlags) apply(req *pb.InviteTokenRequest) {
+	req.Role = a.tokenRole()
+	req.Projects = a.projects
+	req.Workspaces = a.workspaces
+	req.Organization = a.org
+}
@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type TokenListCommand struct {
+	*baseCommand
+
+	flagAll bool
+}
+
+func (c *TokenListCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	resp, err := c.project.Client().ListTokens(c.Ctx, &pb.ListTokensRequest{
+		All: c.flagAll,
+	})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if c.jsonOutput() {
+		output := []map[string]interface{}{}
+		for _, t := range resp.Tokens {
+			output = append(output, map[string]interface{}{
+				"id":          t.Id,
+				"key_id":      t.KeyId,
+				"user":        t.User,
+				"invite":      t.Invite,
+				"role":        tokenRoleName(t.Role),
+				"projects":    t.Projects,
+				"workspaces":  t.Workspaces,
+				"org":         t.Organization,
+				"created_at":  tokenTime(t.CreatedAt, time.RFC3339Nano),
+				"valid_until": tokenTime(t.ValidUntil, time.RFC3339Nano),
+				"revoked_at":  tokenTime(t.RevokedAt, time.RFC3339Nano),
+			})
+		}
+
+		if err := c.writeJSON(output); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
+	if len(resp.Tokens) == 0 {
+		c.ui.Output("No tokens found.")
+		return 0
+	}
+
+	table := terminal.NewTable("ID", "User", "Role", "Scope", "Created", "Expires", "Revoked")
+	for _, t := range resp.Tokens {
+		var scope []string
+		if len(t.Projects) > 0 {
+			scope = append(scope, "projects: "+strings.Join(t.Projects, ", "))
+		}
+		if len(t.Workspaces) > 0 {
+			scope = append(scope, "workspaces: "+strings.Join(t.Workspaces, ", "))
+		}
+		if t.Organization != "" {
+			scope = append(scope, "org: "+t.Organization)
+		}
+
+		role := tokenRoleName(t.Role)
+		if t.Invite {
+			role += " (invite)"
+		}
+
+		expires := tokenTime(t.ValidUntil, time.RFC3339)
+		if expires == "" {
+			expires = "never"
+		}
+
+		table.Rich([]string{
+			t.Id,
+			t.User,
+			role,
+			strings.Join(scope, "; "),
+			tokenTime(t.CreatedAt, time.RFC3339),
+			expires,
+			tokenTime(t.RevokedAt, time.RFC3339),
+		}, nil)
+	}
+
+	c.ui.Table(table)
+	return 0
+}
+
+// tokenRoleName returns the name of the role as it is given to the -role
+// flag, such as "read-only". Tokens created before tokens had roles are
+// admin tokens.
+func tokenRoleName(role pb.Token_Role) string {
+	if role == pb.Token_ROLE_UNSPECIFIED {
+		role = pb.Token_ADMIN
+	}
+
+	return strings.Replace(strings.ToLower(role.String()), "_", "-", -1)
+}
+
+// tokenTime formats ts in the local time zone, or returns "" if it isn't set.
+func tokenTime(ts *timestamp.Timestamp, layout string) string {
+	if ts == nil {
+		return ""
+	}
+
+	return ts.AsTime().Local().Format(layout)
+}
+
+func (c *TokenListCommand) Flags() *flag.Sets {
+	return c.flagSet(flagSetOutput, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.BoolVar(&flag.BoolVar{
+			Name:   "all",
+			Target: &c.flagAll,
+			Usage:  "Also list tokens that have expired or were revoked.",
+		})
+	})
+}
+
+func (c *TokenListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *TokenListCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *TokenListCommand) Synopsis() string {
+	return "List the tokens created on the server"
+}
+
+func (c *TokenListCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint token list [options]
+
+  List the tokens created on the server, oldest first.
+
+  Tokens are identified by their ID, never the token itself. The ID is the
+  same ID that is shown in the audit log and can be given to
+  "waypoint token revoke". Tokens created for deployment entrypoints and
+  tokens created before the server recorded tokens aren't listed.
+
+  By default, only tokens that can still be used are listed.
+
+` + c.Flags().Help())
+}
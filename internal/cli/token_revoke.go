@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type TokenRevokeCommand struct {
+	*baseCommand
+}
+
+func (c *TokenRevokeCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	if len(c.args) != 1 {
+		c.ui.Output("A single token ID is required.\n\n%s", c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	_, err := c.project.Client().RevokeToken(c.Ctx, &pb.RevokeTokenRequest{
+		Id: c.args[0],
+	})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	c.ui.Output("Token revoked.", terminal.WithSuccessStyle())
+	return 0
+}
+
+func (c *TokenRevokeCommand) Flags() *flag.Sets {
+	return c.flagSet(0, nil)
+}
+
+func (c *TokenRevokeCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *TokenRevokeCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *TokenRevokeCommand) Synopsis() string {
+	return "Revoke a token so it can no longer be used"
+}
+
+func (c *TokenRevokeCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint token revoke [options] ID
+
+  Revoke a token so it can no longer be used.
+
+  The ID is the token ID shown by "waypoint token list" and in the audit
+  log. Revoking an invite token also prevents it from being exchanged, but
+  doesn't revoke the tokens it was already exchanged for.
+
+` + c.Flags().Help())
+}
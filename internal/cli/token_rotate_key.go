@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type TokenRotateKeyCommand struct {
+	*baseCommand
+
+	flagPreviousKeyTTL time.Duration
+}
+
+func (c *TokenRotateKeyCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	if c.flagPreviousKeyTTL < 0 {
+		c.ui.Output("-previous-key-ttl must not be negative", terminal.WithErrorStyle())
+		return 1
+	}
+
+	req := &pb.RotateTokenKeyRequest{}
+	if c.flagPreviousKeyTTL > 0 {
+		req.PreviousKeyDuration = c.flagPreviousKeyTTL.String()
+	}
+
+	resp, err := c.project.Client().RotateTokenKey(c.Ctx, req)
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	c.ui.Output("New tokens are now signed with key %q.", resp.KeyId, terminal.WithSuccessStyle())
+	if c.flagPreviousKeyTTL > 0 {
+		c.ui.Output("Tokens signed with previous keys are valid for %s.", c.flagPreviousKeyTTL)
+	}
+	return 0
+}
+
+func (c *TokenRotateKeyCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.DurationVar(&flag.DurationVar{
+			Name:    "previous-key-ttl",
+			Target:  &c.flagPreviousKeyTTL,
+			Default: 7 * 24 * time.Hour,
+			Usage: "How long tokens signed with the previous keys remain valid. " +
+				"Set to 0 to keep them valid until the tokens themselves expire.",
+		})
+	})
+}
+
+func (c *TokenRotateKeyCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *TokenRotateKeyCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *TokenRotateKeyCommand) Synopsis() string {
+	return "Rotate the key the server signs tokens with"
+}
+
+func (c *TokenRotateKeyCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint token rotate-key [options]
+
+  Rotate the key the server signs tokens with.
+
+  New tokens are signed with a new key. Tokens signed with the previous
+  keys remain valid for the time given by -previous-key-ttl so that
+  everyone can get a new token before the old ones stop working. Keys that
+  were already retired by an earlier rotation keep their retire time.
+
+` + c.Flags().Help())
+}
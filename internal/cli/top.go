@@ -0,0 +1,288 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mitchellh/go-glint"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	clientpkg "github.com/hashicorp/waypoint/internal/client"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// The number of rows each pane of "waypoint top" may use at most. The
+// logs pane uses the rest of the terminal.
+const (
+	topJobRows    = 10
+	topRunnerRows = 5
+	topLogRows    = 1000
+)
+
+type TopCommand struct {
+	*baseCommand
+
+	flagId    idFormat
+	flagLimit int
+}
+
+func (c *TopCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithSingleApp(),
+	); err != nil {
+		return 1
+	}
+
+	if !c.ui.Interactive() {
+		c.ui.Output("waypoint top requires an interactive terminal. Use "+
+			"`waypoint status` to show the status of an application instead.",
+			terminal.WithErrorStyle())
+		return 1
+	}
+
+	if c.flagLimit < 1 {
+		c.ui.Output("-limit must be at least 1.", terminal.WithErrorStyle())
+		return 1
+	}
+
+	err := c.DoApp(c.Ctx, func(ctx context.Context, app *clientpkg.App) error {
+		// We draw the whole terminal ourselves, so close the UI as exec
+		// does so that it doesn't draw over us.
+		if closer, ok := app.UI.(io.Closer); ok {
+			closer.Close()
+		}
+
+		stream := newTopStream(ctx, app, uint32(c.flagLimit), topLogRows)
+
+		// Render until we're interrupted.
+		d := glint.New()
+		d.Append(glint.TextFunc(func(rows, cols uint) string {
+			return c.render(app.Ref(), stream, rows, cols)
+		}))
+		d.Render(ctx)
+
+		// If a stream failed, that is the likely reason the user quit.
+		if _, _, _, err := stream.State(); err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		if err != ErrSentinel {
+			// The UI is closed so we output the error ourselves.
+			fmt.Fprintln(os.Stderr, clierrors.Humanize(err))
+		}
+
+		return 1
+	}
+
+	return 0
+}
+
+// render renders the panes of the dashboard to fit in a terminal with the
+// given size.
+func (c *TopCommand) render(
+	ref *pb.Ref_Application,
+	stream *topStream,
+	rows, cols uint,
+) string {
+	dashboard, updated, logs, err := stream.State()
+
+	var lines []string
+	status := "connecting..."
+	if !updated.IsZero() {
+		status = "updated " + updated.Format("15:04:05")
+	}
+	lines = append(lines, fmt.Sprintf("%s/%s (workspace: %s) - %s - Ctrl-C to quit",
+		ref.Project, ref.Application, c.refWorkspace.Workspace, status))
+	if err != nil {
+		lines = append(lines, "Error: "+clierrors.Humanize(err))
+	}
+
+	if dashboard == nil {
+		dashboard = &pb.GetDashboardStreamResponse{}
+	}
+
+	// Deployments
+	lines = append(lines, "", "DEPLOYMENTS")
+	lines = append(lines, c.deploymentRows(dashboard.Deployments)...)
+
+	// Jobs
+	lines = append(lines, "", "JOBS")
+	lines = append(lines, topLimit(c.jobRows(dashboard.Jobs), topJobRows)...)
+
+	// Runners
+	lines = append(lines, "", "RUNNERS")
+	lines = append(lines, topLimit(c.runnerRows(dashboard.Runners), topRunnerRows)...)
+
+	// Logs fill the rest of the terminal, showing the latest lines.
+	lines = append(lines, "", "LOGS")
+	if avail := int(rows) - len(lines) - 1; avail > 0 && len(logs) > 0 {
+		if len(logs) > avail {
+			logs = logs[len(logs)-avail:]
+		}
+
+		lines = append(lines, logs...)
+	} else if len(logs) == 0 {
+		lines = append(lines, "  No logs yet.")
+	}
+
+	// Clamp to the terminal so that the screen doesn't scroll.
+	if rows > 0 && len(lines) > int(rows)-1 {
+		lines = lines[:int(rows)-1]
+	}
+	for i, line := range lines {
+		if cols > 0 && len(line) > int(cols) {
+			lines[i] = line[:cols]
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (c *TopCommand) deploymentRows(deployments []*pb.Deployment) []string {
+	if len(deployments) == 0 {
+		return []string{"  No deployments."}
+	}
+
+	var rows [][]string
+	for _, d := range deployments {
+		var component string
+		if d.Component != nil {
+			component = d.Component.Name
+		}
+
+		rows = append(rows, []string{
+			c.flagId.FormatId(d.Sequence, d.Id),
+			component,
+			statusSummary(d.Status),
+			strings.ToLower(d.State.String()),
+		})
+	}
+
+	return topTable([]string{"ID", "PLATFORM", "STATUS", "STATE"}, rows)
+}
+
+func (c *TopCommand) jobRows(jobs []*pb.Job) []string {
+	if len(jobs) == 0 {
+		return []string{"  No queued or running jobs."}
+	}
+
+	var rows [][]string
+	for _, job := range jobs {
+		var app, runner string
+		if job.Application != nil {
+			app = job.Application.Project + "/" + job.Application.Application
+		}
+		if job.AssignedRunner != nil {
+			runner = job.AssignedRunner.Id
+		}
+
+		rows = append(rows, []string{
+			job.Id,
+			jobOperationName(job),
+			app,
+			strings.ToLower(job.State.String()),
+			runner,
+		})
+	}
+
+	return topTable([]string{"ID", "OPERATION", "APP", "STATE", "RUNNER"}, rows)
+}
+
+func (c *TopCommand) runnerRows(runners []*pb.Runner) []string {
+	if len(runners) == 0 {
+		return []string{"  No runners connected."}
+	}
+
+	var rows [][]string
+	for _, r := range runners {
+		kind := "remote"
+		if r.ByIdOnly {
+			kind = "local"
+		}
+
+		rows = append(rows, []string{r.Id, kind})
+	}
+
+	return topTable([]string{"ID", "KIND"}, rows)
+}
+
+// topTable formats rows as aligned columns under the header.
+func topTable(header []string, rows [][]string) []string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "  "+strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, "  "+strings.Join(row, "\t"))
+	}
+	w.Flush()
+
+	return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+}
+
+// topLimit limits a pane to n rows, replacing the last row with a count of
+// the rows that don't fit.
+func topLimit(lines []string, n int) []string {
+	if len(lines) <= n {
+		return lines
+	}
+
+	more := len(lines) - n + 1
+	return append(lines[:n-1], fmt.Sprintf("  ... and %d more", more))
+}
+
+func (c *TopCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		initIdFormat(f, &c.flagId)
+
+		f.IntVar(&flag.IntVar{
+			Name:    "limit",
+			Target:  &c.flagLimit,
+			Default: 5,
+			Usage:   "Number of recent deployments to show.",
+		})
+	})
+}
+
+func (c *TopCommand) AutocompleteArgs() complete.Predictor {
+	return c.predictAppTarget()
+}
+
+func (c *TopCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *TopCommand) Synopsis() string {
+	return "Live dashboard of deployments, jobs, runners, and logs"
+}
+
+func (c *TopCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint top [options]
+
+  Show a live dashboard for an application.
+
+  The dashboard shows the latest deployments of the application, the jobs
+  that are queued or running on the server, the runners connected to the
+  server, and the latest application logs. It updates as soon as any of
+  these change. Press Ctrl-C to quit.
+
+  This requires an interactive terminal. Use "waypoint status" for a
+  one-time summary that works in scripts.
+
+` + c.Flags().Help())
+}
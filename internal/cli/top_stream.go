@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+
+	clientpkg "github.com/hashicorp/waypoint/internal/client"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// topStream keeps the latest state from the server streams used by
+// "waypoint top". The streams are received in the background so that the
+// UI can render the latest state whenever it likes.
+type topStream struct {
+	mu sync.Mutex
+
+	// dashboard is the latest dashboard message and updated is when it
+	// was received. dashboard is nil until the first message arrives.
+	dashboard *pb.GetDashboardStreamResponse
+	updated   time.Time
+
+	// logs are the latest log lines, oldest first, up to maxLogs lines.
+	logs    []string
+	maxLogs int
+
+	// dashboardErr and logsErr are set if the streams end with an error.
+	dashboardErr error
+	logsErr      error
+}
+
+// newTopStream starts receiving the dashboard and logs of app. The streams
+// are closed when ctx is done.
+func newTopStream(ctx context.Context, app *clientpkg.App, limit uint32, maxLogs int) *topStream {
+	s := &topStream{maxLogs: maxLogs}
+	go s.recvDashboard(ctx, app, limit)
+	go s.recvLogs(ctx, app)
+	return s
+}
+
+// State returns the latest state. The returned values must not be modified.
+func (s *topStream) State() (*pb.GetDashboardStreamResponse, time.Time, []string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.dashboardErr
+	if err == nil {
+		err = s.logsErr
+	}
+
+	// Copy the logs since we append to them in the background.
+	logs := make([]string, len(s.logs))
+	copy(logs, s.logs)
+
+	return s.dashboard, s.updated, logs, err
+}
+
+func (s *topStream) recvDashboard(ctx context.Context, app *clientpkg.App, limit uint32) {
+	stream, err := app.Dashboard(ctx, limit)
+	if err != nil {
+		s.setErr(&s.dashboardErr, err)
+		return
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			s.setErr(&s.dashboardErr, err)
+			return
+		}
+
+		s.mu.Lock()
+		s.dashboard = resp
+		s.updated = time.Now()
+		s.mu.Unlock()
+	}
+}
+
+func (s *topStream) recvLogs(ctx context.Context, app *clientpkg.App) {
+	stream, err := app.Logs(ctx, nil)
+	if err != nil {
+		s.setErr(&s.logsErr, err)
+		return
+	}
+
+	for {
+		batch, err := stream.Recv()
+		if err != nil {
+			s.setErr(&s.logsErr, err)
+			return
+		}
+
+		short := batch.InstanceId
+		if len(short) > 6 {
+			short = short[len(short)-6:]
+		}
+
+		var lines []string
+		for _, event := range batch.Lines {
+			ts, _ := ptypes.Timestamp(event.Timestamp)
+			for _, line := range strings.Split(strings.TrimSuffix(event.Line, "\n"), "\n") {
+				lines = append(lines, ts.Local().Format("15:04:05")+" "+short+": "+line)
+			}
+		}
+
+		s.mu.Lock()
+		s.logs = append(s.logs, lines...)
+		if len(s.logs) > s.maxLogs {
+			s.logs = s.logs[len(s.logs)-s.maxLogs:]
+		}
+		s.mu.Unlock()
+	}
+}
+
+// setErr sets the stream error at dst unless the stream ended normally or
+// because we were canceled.
+func (s *topStream) setErr(dst *error, err error) {
+	if err == io.EOF || clierrors.IsCanceled(err) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	*dst = err
+}
@@ -43,6 +43,7 @@ func (c *UICommand) Run(args []string) int {
 
 		resp, err := client.GenerateInviteToken(c.Ctx, &pb.InviteTokenRequest{
 			Duration: (2 * time.Minute).String(),
+			Role:     pb.Token_ADMIN,
 		})
 		if err != nil {
 			c.project.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
@@ -2,17 +2,23 @@ package cli
 
 import (
 	"context"
+	"path/filepath"
 	"strings"
 
 	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
 	clientpkg "github.com/hashicorp/waypoint/internal/client"
 	"github.com/hashicorp/waypoint/internal/clierrors"
+	configpkg "github.com/hashicorp/waypoint/internal/config"
+	"github.com/hashicorp/waypoint/internal/pkg/filewatch"
 	"github.com/hashicorp/waypoint/internal/pkg/flag"
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
 )
 
 type UpCommand struct {
 	*baseCommand
+
+	flagWatch       bool
+	flagWatchIgnore []string
 }
 
 func (c *UpCommand) Run(args []string) int {
@@ -25,6 +31,15 @@ func (c *UpCommand) Run(args []string) int {
 		return 1
 	}
 
+	if c.flagWatch {
+		return c.watch()
+	}
+
+	return c.up()
+}
+
+// up performs the build, deploy, and release steps once.
+func (c *UpCommand) up() int {
 	client := c.project.Client()
 
 	err := c.DoApp(c.Ctx, func(ctx context.Context, app *clientpkg.App) error {
@@ -121,8 +136,100 @@ func (c *UpCommand) Run(args []string) int {
 	return 0
 }
 
+// watch runs up and then runs it again whenever the files of the project
+// change, until we're interrupted.
+func (c *UpCommand) watch() int {
+	path, err := configpkg.FindPath("", "", true)
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+	if path == "" {
+		c.ui.Output("-watch requires a waypoint.hcl file to find the project "+
+			"directory to watch.", terminal.WithErrorStyle())
+		return 1
+	}
+	root := filepath.Dir(path)
+
+	ignore, err := filewatch.IgnorePatterns(c.flagWatchIgnore...)
+	if err != nil {
+		c.ui.Output("Invalid -watch-ignore value: %s", err, terminal.WithErrorStyle())
+		return 1
+	}
+
+	// We start watching before the first run so that changes made while
+	// it runs trigger the next run.
+	w, err := filewatch.New(root, filewatch.WithIgnore(ignore))
+	if err != nil {
+		c.ui.Output("Error watching %s: %s", root, err, terminal.WithErrorStyle())
+		return 1
+	}
+
+	// A failed run doesn't stop us, the next change may fix it.
+	c.up()
+	for {
+		c.ui.Output("")
+		c.ui.Output("Watching %s for changes. Press Ctrl-C to stop.", root,
+			terminal.WithInfoStyle())
+
+		changes, err := w.Wait(c.Ctx)
+		if err != nil {
+			if c.Ctx.Err() != nil {
+				return 0
+			}
+
+			c.ui.Output("Error watching %s: %s", root, err, terminal.WithErrorStyle())
+			return 1
+		}
+
+		c.ui.Output("")
+		c.ui.Output("Detected changes to %d file(s):", len(changes), terminal.WithHeaderStyle())
+		c.outputChanges(changes)
+		c.up()
+	}
+}
+
+// watchChangesShown is the most changed files we list before each run.
+const watchChangesShown = 20
+
+// outputChanges lists the changed files that triggered a run, like a diff
+// summary: "+" for created, "~" for written, and "-" for removed files.
+func (c *UpCommand) outputChanges(changes []filewatch.Change) {
+	for i, change := range changes {
+		if i == watchChangesShown {
+			c.ui.Output("  ... and %d more", len(changes)-i)
+			break
+		}
+
+		switch change.Op {
+		case filewatch.Create:
+			c.ui.Output("  + %s", change.Path, terminal.WithSuccessStyle())
+		case filewatch.Remove:
+			c.ui.Output("  - %s", change.Path, terminal.WithErrorStyle())
+		default:
+			c.ui.Output("  ~ %s", change.Path, terminal.WithWarningStyle())
+		}
+	}
+}
+
 func (c *UpCommand) Flags() *flag.Sets {
 	return c.flagSet(flagSetOperation, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.BoolVar(&flag.BoolVar{
+			Name:   "watch",
+			Target: &c.flagWatch,
+			Usage: "Watch the project directory and run the build, deploy, and " +
+				"release steps again whenever files change.",
+		})
+
+		f.StringSliceVar(&flag.StringSliceVar{
+			Name:   "watch-ignore",
+			Target: &c.flagWatchIgnore,
+			Usage: "A pattern of files to not watch with -watch, such as \"*.log\". " +
+				"Patterns with a slash match the path relative to the project " +
+				"directory. Hidden files are never watched. This can be specified " +
+				"multiple times.",
+		})
 	})
 }
 
@@ -136,5 +243,11 @@ Usage: waypoint up [options]
 
   Perform the build, deploy, and release steps for the app.
 
+  With -watch, this keeps running after the first run and runs the steps
+  again whenever files in the project directory change. Changes are
+  collected until no files have changed for a second, so saving many files
+  at once triggers a single run. The changed files are listed before each
+  run. A failed run doesn't stop watching.
+
 ` + c.Flags().Help())
 }
@@ -0,0 +1,557 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/go-argmapper"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/posener/complete"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/component"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	configpkg "github.com/hashicorp/waypoint/internal/config"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	"github.com/hashicorp/waypoint/internal/plugin"
+)
+
+// validateRuntimeVars are the variables that are only available to the
+// configuration when an operation runs, such as "artifact" in a deploy
+// stanza. They are unknown during validation so expressions that use them
+// can't be checked.
+var validateRuntimeVars = []string{"artifact", "deploy", "entrypoint"}
+
+type ValidateCommand struct {
+	*baseCommand
+}
+
+// validateProblem is a single problem found in the configuration.
+type validateProblem struct {
+	App      string `json:"app,omitempty"`
+	Stage    string `json:"stage,omitempty"`
+	Plugin   string `json:"plugin,omitempty"`
+	Severity string `json:"severity"`
+	Summary  string `json:"summary"`
+	Detail   string `json:"detail,omitempty"`
+	Filename string `json:"filename,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+}
+
+// Location returns the location of the problem in the configuration as
+// "file:line,column", or an empty string if the location is unknown.
+func (p *validateProblem) Location() string {
+	if p.Filename == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%s:%d,%d", p.Filename, p.Line, p.Column)
+}
+
+// validateStage is a stage of an application that is configured with a
+// plugin.
+type validateStage struct {
+	Name string
+	Type component.Type
+	Use  string
+	Load func(*hcl.EvalContext) (*configpkg.Operation, error)
+}
+
+func (c *ValidateCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	// We load the configuration ourselves so that we can report every
+	// problem rather than fail on the first.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+		WithClient(false),
+	); err != nil {
+		return 1
+	}
+
+	path, err := c.initConfigPath()
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+	if path == "" {
+		c.ui.Output("A Waypoint configuration file is required but wasn't found.",
+			terminal.WithErrorStyle())
+		return 1
+	}
+
+	problems := c.validate(c.Ctx, path)
+
+	var errored bool
+	for _, p := range problems {
+		if p.Severity == "error" {
+			errored = true
+		}
+	}
+
+	if c.jsonOutput() {
+		if problems == nil {
+			problems = []*validateProblem{}
+		}
+
+		if err := c.writeJSON(map[string]interface{}{
+			"path":     path,
+			"valid":    !errored,
+			"problems": problems,
+		}); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+	} else {
+		c.outputProblems(path, problems, errored)
+	}
+
+	if errored {
+		return 1
+	}
+
+	return 0
+}
+
+// validate validates the configuration at path and returns the problems
+// found. This never contacts the server.
+func (c *ValidateCommand) validate(ctx context.Context, path string) []*validateProblem {
+	cfg, err := configpkg.Load(path, filepath.Dir(path))
+	if err != nil {
+		return validateErrProblems(err, nil)
+	}
+
+	// If the structure is invalid we can't load the apps, so stop here.
+	if err := cfg.Validate(); err != nil {
+		return validateErrProblems(err, nil)
+	}
+
+	// Find the plugins. Plugins that aren't found are problems, but we
+	// continue so that the config of the other plugins is validated.
+	cmds, problems := c.discoverPlugins(cfg, filepath.Dir(path))
+
+	// The runtime variables are unknown so that expressions that use them
+	// evaluate rather than fail as undefined.
+	evalCtx := &hcl.EvalContext{Variables: map[string]cty.Value{}}
+	for _, n := range validateRuntimeVars {
+		evalCtx.Variables[n] = cty.DynamicVal
+	}
+
+	for _, name := range cfg.Apps() {
+		app, err := cfg.App(name, nil)
+		if err != nil {
+			problems = append(problems, validateErrProblems(err, &validateProblem{
+				App: name,
+			})...)
+			continue
+		}
+
+		// If the app is invalid its stages may not be configured, so we
+		// can't validate them.
+		if err := app.Validate(); err != nil {
+			problems = append(problems, validateErrProblems(err, &validateProblem{
+				App: name,
+			})...)
+			continue
+		}
+
+		for _, stage := range validateStages(app) {
+			if stage.Use == "" {
+				continue
+			}
+
+			base := &validateProblem{App: name, Stage: stage.Name, Plugin: stage.Use}
+			if _, ok := cmds[stage.Use]; !ok {
+				if _, ok := plugin.Builtins[stage.Use]; !ok {
+					// Already reported by discoverPlugins.
+					continue
+				}
+			}
+
+			op, err := stage.Load(evalCtx)
+			if err != nil {
+				problems = append(problems, validateErrProblems(
+					validateFilterRuntime(err, nil), base)...)
+				continue
+			}
+
+			problems = append(problems, c.validateOperation(
+				ctx, stage, op, cmds[stage.Use], evalCtx, base)...)
+		}
+	}
+
+	return problems
+}
+
+// validateOperation configures the plugin of the stage with the operation
+// to validate its configuration.
+func (c *ValidateCommand) validateOperation(
+	ctx context.Context,
+	stage *validateStage,
+	op *configpkg.Operation,
+	cmd *exec.Cmd,
+	evalCtx *hcl.EvalContext,
+	base *validateProblem,
+) []*validateProblem {
+	// Start the plugin the same way the runner does.
+	var fn interface{}
+	if cmd != nil {
+		fn = plugin.Factory(cmd, stage.Type)
+	} else {
+		fn = plugin.BuiltinFactory(stage.Use, stage.Type)
+	}
+
+	f := plugin.BaseFactories[stage.Type].Copy()
+	if err := f.Register(stage.Use, fn); err != nil {
+		return validateErrProblems(err, base)
+	}
+
+	result := f.Func(stage.Use).Call(argmapper.Typed(ctx, c.Log))
+	if err := result.Err(); err != nil {
+		return validateErrProblems(fmt.Errorf(
+			"plugin %q could not be started as a %s: %s",
+			stage.Use, strings.ToLower(stage.Type.String()), err), base)
+	}
+
+	raw := result.Out(0)
+	if pinst, ok := raw.(*plugin.Instance); ok {
+		defer pinst.Close()
+		raw = pinst.Component
+	}
+
+	diags := op.Configure(raw, evalCtx)
+	return validateErrProblems(validateFilterRuntime(diags, op.Use.Body), base)
+}
+
+// discoverPlugins finds all the plugins used by the configuration. The
+// result maps the name of each external plugin to its command. Built-in
+// plugins that aren't overridden by an external plugin aren't included.
+func (c *ValidateCommand) discoverPlugins(
+	cfg *configpkg.Config,
+	wd string,
+) (map[string]*exec.Cmd, []*validateProblem) {
+	paths, err := plugin.DefaultPaths(wd)
+	if err != nil {
+		return nil, validateErrProblems(err, nil)
+	}
+
+	cmds := map[string]*exec.Cmd{}
+	var problems []*validateProblem
+	for _, pluginCfg := range cfg.Plugins() {
+		base := &validateProblem{Plugin: pluginCfg.Name}
+
+		cmd, err := plugin.Discover(pluginCfg, paths)
+		if err != nil {
+			problems = append(problems, validateErrProblems(err, base)...)
+			continue
+		}
+
+		if cmd != nil {
+			cmds[pluginCfg.Name] = cmd
+			continue
+		}
+
+		if _, ok := plugin.Builtins[pluginCfg.Name]; !ok {
+			problems = append(problems, validateErrProblems(fmt.Errorf(
+				"plugin %q not found. It isn't a built-in plugin and wasn't "+
+					"found in any of the plugin search paths: %s",
+				pluginCfg.Name, strings.Join(paths, ", ")), base)...)
+		}
+	}
+
+	return cmds, problems
+}
+
+// validateStages returns the stages of the app that are configured with a
+// plugin.
+func validateStages(app *configpkg.App) []*validateStage {
+	return []*validateStage{
+		{
+			Name: "build",
+			Type: component.BuilderType,
+			Use:  app.BuildUse(),
+			Load: func(ctx *hcl.EvalContext) (*configpkg.Operation, error) {
+				v, err := app.Build(ctx)
+				if err != nil {
+					return nil, err
+				}
+
+				return v.Operation(), nil
+			},
+		},
+
+		{
+			Name: "registry",
+			Type: component.RegistryType,
+			Use:  app.RegistryUse(),
+			Load: func(ctx *hcl.EvalContext) (*configpkg.Operation, error) {
+				v, err := app.Registry(ctx)
+				if err != nil {
+					return nil, err
+				}
+
+				return v.Operation(), nil
+			},
+		},
+
+		{
+			Name: "deploy",
+			Type: component.PlatformType,
+			Use:  app.DeployUse(),
+			Load: func(ctx *hcl.EvalContext) (*configpkg.Operation, error) {
+				v, err := app.Deploy(ctx)
+				if err != nil {
+					return nil, err
+				}
+
+				return v.Operation(), nil
+			},
+		},
+
+		{
+			Name: "release",
+			Type: component.ReleaseManagerType,
+			Use:  app.ReleaseUse(),
+			Load: func(ctx *hcl.EvalContext) (*configpkg.Operation, error) {
+				v, err := app.Release(ctx)
+				if err != nil {
+					return nil, err
+				}
+
+				return v.Operation(), nil
+			},
+		},
+	}
+}
+
+// validateFilterRuntime removes the error diagnostics in err that are
+// caused by values that use the runtime variables, since those values are
+// unknown until the operation runs. body is the body the diagnostics are
+// for. It is used to find the diagnostics that don't say which expression
+// they are for.
+func validateFilterRuntime(err error, body hcl.Body) error {
+	var diags hcl.Diagnostics
+	if !errors.As(err, &diags) {
+		return err
+	}
+
+	ranges := validateRuntimeRanges(body)
+
+	var result hcl.Diagnostics
+	for _, diag := range diags {
+		if diag.Severity == hcl.DiagError && validateIsRuntime(diag, ranges) {
+			continue
+		}
+
+		result = append(result, diag)
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+
+	return result
+}
+
+// validateIsRuntime returns true if the diagnostic is about an expression
+// that uses a runtime variable.
+func validateIsRuntime(diag *hcl.Diagnostic, ranges []hcl.Range) bool {
+	if diag.Expression != nil && validateUsesRuntime(diag.Expression) {
+		return true
+	}
+
+	if diag.Subject == nil {
+		return false
+	}
+
+	for _, r := range ranges {
+		if r.Filename == diag.Subject.Filename &&
+			r.Start.Byte <= diag.Subject.Start.Byte &&
+			diag.Subject.End.Byte <= r.End.Byte {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validateRuntimeRanges returns the ranges of all the expressions in body
+// that use runtime variables. Only native syntax bodies are inspected.
+func validateRuntimeRanges(body hcl.Body) []hcl.Range {
+	syntaxBody, ok := body.(*hclsyntax.Body)
+	if !ok {
+		return nil
+	}
+
+	var result []hcl.Range
+	for _, attr := range syntaxBody.Attributes {
+		if validateUsesRuntime(attr.Expr) {
+			result = append(result, attr.Expr.Range())
+		}
+	}
+
+	for _, block := range syntaxBody.Blocks {
+		result = append(result, validateRuntimeRanges(block.Body)...)
+	}
+
+	return result
+}
+
+// validateUsesRuntime returns true if expr references a runtime variable.
+func validateUsesRuntime(expr hcl.Expression) bool {
+	for _, traversal := range expr.Variables() {
+		for _, n := range validateRuntimeVars {
+			if traversal.RootName() == n {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// validateErrProblems turns err into problems. Every HCL diagnostic in err
+// is a separate problem. The fields of base are copied to each problem.
+func validateErrProblems(err error, base *validateProblem) []*validateProblem {
+	if err == nil {
+		return nil
+	}
+
+	if base == nil {
+		base = &validateProblem{}
+	}
+
+	if merr, ok := err.(*multierror.Error); ok {
+		var result []*validateProblem
+		for _, err := range merr.Errors {
+			result = append(result, validateErrProblems(err, base)...)
+		}
+
+		return result
+	}
+
+	var diags hcl.Diagnostics
+	if diag, ok := err.(*hcl.Diagnostic); ok {
+		diags = hcl.Diagnostics{diag}
+	} else if !errors.As(err, &diags) {
+		p := *base
+		p.Severity = "error"
+		p.Summary = err.Error()
+		return []*validateProblem{&p}
+	}
+
+	var result []*validateProblem
+	for _, diag := range diags {
+		p := *base
+		p.Severity = "error"
+		if diag.Severity == hcl.DiagWarning {
+			p.Severity = "warning"
+		}
+		p.Summary = diag.Summary
+		p.Detail = diag.Detail
+		if diag.Subject != nil {
+			p.Filename = diag.Subject.Filename
+			p.Line = diag.Subject.Start.Line
+			p.Column = diag.Subject.Start.Column
+		}
+
+		result = append(result, &p)
+	}
+
+	return result
+}
+
+func (c *ValidateCommand) outputProblems(path string, problems []*validateProblem, errored bool) {
+	for _, p := range problems {
+		style := terminal.ErrorStyle
+		label := "Error"
+		if p.Severity == "warning" {
+			style = terminal.WarningStyle
+			label = "Warning"
+		}
+
+		// Describe where in the configuration the problem is.
+		var where []string
+		if p.App != "" {
+			where = append(where, fmt.Sprintf("app %q", p.App))
+		}
+		if p.Stage != "" {
+			where = append(where, fmt.Sprintf("%s stage", p.Stage))
+		}
+		if p.Plugin != "" {
+			where = append(where, fmt.Sprintf("plugin %q", p.Plugin))
+		}
+		if loc := p.Location(); loc != "" {
+			where = append(where, loc)
+		}
+
+		msg := fmt.Sprintf("%s: %s", label, p.Summary)
+		if len(where) > 0 {
+			msg += fmt.Sprintf(" (%s)", strings.Join(where, ", "))
+		}
+		c.ui.Output(msg, terminal.WithStyle(style))
+
+		if p.Detail != "" {
+			c.ui.Output("  %s", p.Detail, terminal.WithStyle(style))
+		}
+	}
+
+	if errored {
+		c.ui.Output("")
+		c.ui.Output("The configuration in %s is invalid.", path, terminal.WithErrorStyle())
+		return
+	}
+
+	if len(problems) > 0 {
+		c.ui.Output("")
+	}
+
+	c.ui.Output("The configuration in %s is valid.", path, terminal.WithSuccessStyle())
+}
+
+func (c *ValidateCommand) Flags() *flag.Sets {
+	return c.flagSet(flagSetOutput, nil)
+}
+
+func (c *ValidateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ValidateCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ValidateCommand) Synopsis() string {
+	return "Validate the Waypoint configuration"
+}
+
+func (c *ValidateCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint validate [options]
+
+  Validate the Waypoint configuration file.
+
+  This checks the structure of waypoint.hcl and then starts the plugin of
+  every stage of every app to validate the configuration of the plugin.
+  Unknown attributes, missing required attributes, and values of the wrong
+  type are all reported, along with plugins that can't be found.
+
+  This doesn't contact the server or run any operation, so it can be run
+  anywhere the plugins are available, such as in CI. Values that use
+  variables only available when an operation runs, such as "artifact",
+  can't be validated and are skipped.
+
+  With -output=json, the problems are output as JSON. The command exits
+  with a non-zero status if there are any errors.
+
+` + c.Flags().Help())
+}
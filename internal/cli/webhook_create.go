@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type WebhookCreateCommand struct {
+	*baseCommand
+
+	flagURL    string
+	flagSecret string
+	flagEvents []string
+}
+
+func (c *WebhookCreateCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+	); err != nil {
+		return 1
+	}
+
+	if c.flagURL == "" {
+		c.ui.Output("-url is required.\n\n%s", c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	resp, err := c.project.Client().UpsertWebhook(c.Ctx, &pb.UpsertWebhookRequest{
+		Webhook: &pb.Webhook{
+			Project: c.project.Ref(),
+			Url:     c.flagURL,
+			Secret:  c.flagSecret,
+			Events:  c.flagEvents,
+		},
+	})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	c.ui.Output("Webhook %q created.", resp.Webhook.Id, terminal.WithSuccessStyle())
+	return 0
+}
+
+func (c *WebhookCreateCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:   "url",
+			Target: &c.flagURL,
+			Usage:  "URL to POST events to.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "secret",
+			Target: &c.flagSecret,
+			EnvVar: "WAYPOINT_WEBHOOK_SECRET",
+			Usage: "Secret to sign payloads with. The signature is sent in the " +
+				"X-Waypoint-Signature header so that the receiver can verify " +
+				"the payload was sent by the server.",
+		})
+
+		f.EnumVar(&flag.EnumVar{
+			Name:   "event",
+			Target: &c.flagEvents,
+			Values: []string{"build", "deploy", "release", "restore"},
+			Usage: "Event to send to the webhook. This can be specified multiple " +
+				"times. By default, every event is sent.",
+		})
+	})
+}
+
+func (c *WebhookCreateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *WebhookCreateCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *WebhookCreateCommand) Synopsis() string {
+	return "Create a webhook that is sent events of this project"
+}
+
+func (c *WebhookCreateCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint webhook create [options]
+
+  Create a webhook that is sent events when operations of this project
+  complete.
+
+  The server POSTs a JSON payload to the URL when a build, deployment, or
+  release completes, successfully or not, and when a snapshot restore is
+  staged. Payloads are retried a few times if the URL can't be reached or
+  doesn't respond with a 2xx status.
+
+  The server sends the payloads from its own network, so only admin tokens
+  that aren't limited to projects can create webhooks.
+
+` + c.Flags().Help())
+}
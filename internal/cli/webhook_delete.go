@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type WebhookDeleteCommand struct {
+	*baseCommand
+}
+
+func (c *WebhookDeleteCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+	); err != nil {
+		return 1
+	}
+
+	if len(c.args) != 1 {
+		c.ui.Output("A single webhook ID is required.\n\n%s", c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	_, err := c.project.Client().DeleteWebhook(c.Ctx, &pb.DeleteWebhookRequest{
+		Project: c.project.Ref(),
+		Id:      c.args[0],
+	})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	c.ui.Output("Webhook deleted.", terminal.WithSuccessStyle())
+	return 0
+}
+
+func (c *WebhookDeleteCommand) Flags() *flag.Sets {
+	return c.flagSet(0, nil)
+}
+
+func (c *WebhookDeleteCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *WebhookDeleteCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *WebhookDeleteCommand) Synopsis() string {
+	return "Delete a webhook of this project"
+}
+
+func (c *WebhookDeleteCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint webhook delete [options] ID
+
+  Delete a webhook of this project. The ID is shown by "waypoint webhook list".
+
+` + c.Flags().Help())
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type WebhookListCommand struct {
+	*baseCommand
+}
+
+func (c *WebhookListCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+	); err != nil {
+		return 1
+	}
+
+	resp, err := c.project.Client().ListWebhooks(c.Ctx, &pb.ListWebhooksRequest{
+		Project: c.project.Ref(),
+	})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if c.jsonOutput() {
+		output := []map[string]interface{}{}
+		for _, hook := range resp.Webhooks {
+			output = append(output, map[string]interface{}{
+				"id":      hook.Id,
+				"project": hook.Project.GetProject(),
+				"url":     hook.Url,
+				"events":  hook.Events,
+			})
+		}
+
+		if err := c.writeJSON(output); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
+	if len(resp.Webhooks) == 0 {
+		c.ui.Output("No webhooks found.")
+		return 0
+	}
+
+	table := terminal.NewTable("ID", "URL", "Events")
+	for _, hook := range resp.Webhooks {
+		events := "all"
+		if len(hook.Events) > 0 {
+			events = strings.Join(hook.Events, ", ")
+		}
+
+		table.Rich([]string{
+			hook.Id,
+			hook.Url,
+			events,
+		}, nil)
+	}
+
+	c.ui.Table(table)
+	return 0
+}
+
+func (c *WebhookListCommand) Flags() *flag.Sets {
+	return c.flagSet(flagSetOutput, nil)
+}
+
+func (c *WebhookListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *WebhookListCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *WebhookListCommand) Synopsis() string {
+	return "List the webhooks of this project"
+}
+
+func (c *WebhookListCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint webhook list [options]
+
+  List the webhooks of this project. Secrets are never shown.
+
+` + c.Flags().Help())
+}
@@ -0,0 +1,164 @@
+// Package clicache caches responses from the Waypoint server on disk so
+// that read-only CLI commands can work when the server is unreachable and
+// so that repeated lookups, such as for autocomplete, don't always need a
+// round trip to the server.
+package clicache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/protobuf/proto"
+)
+
+// methodPrefix is the prefix of the full gRPC method names of the
+// Waypoint service.
+const methodPrefix = "/hashicorp.waypoint.Waypoint/"
+
+// cacheable are the methods whose responses are cached. These must be
+// read-only and their responses must not contain secrets since they are
+// stored on disk. Config variables are not cached for this reason.
+var cacheable = map[string]struct{}{
+	methodPrefix + "GetVersionInfo":          {},
+	methodPrefix + "ListProjects":            {},
+	methodPrefix + "GetProject":              {},
+	methodPrefix + "ListBuilds":              {},
+	methodPrefix + "GetLatestBuild":          {},
+	methodPrefix + "ListPushedArtifacts":     {},
+	methodPrefix + "GetLatestPushedArtifact": {},
+	methodPrefix + "ListDeployments":         {},
+	methodPrefix + "GetDeployment":           {},
+	methodPrefix + "ListInstances":           {},
+	methodPrefix + "ListReleases":            {},
+	methodPrefix + "GetLatestRelease":        {},
+	methodPrefix + "GetRelease":              {},
+	methodPrefix + "ListHostnames":           {},
+	methodPrefix + "XListJobs":               {},
+}
+
+// Cacheable returns true if responses for the given full gRPC method name
+// are cached.
+func Cacheable(method string) bool {
+	_, ok := cacheable[method]
+	return ok
+}
+
+// Cache is an on-disk cache of responses from a single server.
+type Cache struct {
+	dir    string
+	server string
+	logger hclog.Logger
+}
+
+// New returns a cache that stores the responses from the server with the
+// given address in dir. The directory is created when a response is
+// first stored.
+func New(dir, server string, logger hclog.Logger) *Cache {
+	if logger == nil {
+		logger = hclog.NewNullLogger()
+	}
+
+	return &Cache{
+		dir:    dir,
+		server: server,
+		logger: logger.Named("clicache"),
+	}
+}
+
+// Get loads the cached response to req for the method into reply. This
+// returns the time the response was cached, and false if there is no
+// cached response or it is older than maxAge. A maxAge of zero accepts
+// responses of any age.
+func (c *Cache) Get(method string, req, reply proto.Message, maxAge time.Duration) (time.Time, bool) {
+	path, err := c.path(method, req)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if maxAge > 0 && time.Since(fi.ModTime()) > maxAge {
+		return time.Time{}, false
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		c.logger.Debug("error reading cached response", "path", path, "err", err)
+		return time.Time{}, false
+	}
+
+	if err := proto.Unmarshal(data, reply); err != nil {
+		// This can happen if the cache was written by an incompatible
+		// version. We treat it as missing and it is replaced next time.
+		c.logger.Debug("error decoding cached response", "path", path, "err", err)
+		return time.Time{}, false
+	}
+
+	return fi.ModTime(), true
+}
+
+// Put stores reply as the response to req for the method. Only responses
+// for cacheable methods are stored, others are ignored.
+func (c *Cache) Put(method string, req, reply proto.Message) error {
+	if !Cacheable(method) {
+		return nil
+	}
+
+	path, err := c.path(method, req)
+	if err != nil {
+		return err
+	}
+
+	data, err := proto.Marshal(reply)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	// Write to a temporary file and rename it so that a concurrent Get
+	// never reads a partial response.
+	f, err := ioutil.TempFile(filepath.Dir(path), ".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// path returns the path of the cached response to req. The path is a hash
+// of the server, method, and request so different servers and requests,
+// such as lists with different filters, are cached separately.
+func (c *Cache) path(method string, req proto.Message) (string, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	h.Write([]byte(c.server))
+	h.Write([]byte{0})
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write(data)
+
+	return filepath.Join(c.dir, hex.EncodeToString(h.Sum(nil))), nil
+}
@@ -0,0 +1,106 @@
+package clicache
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func TestCache(t *testing.T) {
+	require := require.New(t)
+
+	c := testCache(t, "server-a")
+	method := methodPrefix + "ListDeployments"
+	req := &pb.ListDeploymentsRequest{
+		Application: &pb.Ref_Application{Project: "p", Application: "a"},
+	}
+
+	// Initially empty
+	var resp pb.ListDeploymentsResponse
+	_, ok := c.Get(method, req, &resp, 0)
+	require.False(ok)
+
+	// Store and load
+	require.NoError(c.Put(method, req, &pb.ListDeploymentsResponse{
+		Deployments: []*pb.Deployment{{Id: "d1"}},
+	}))
+	_, ok = c.Get(method, req, &resp, 0)
+	require.True(ok)
+	require.Len(resp.Deployments, 1)
+	require.Equal("d1", resp.Deployments[0].Id)
+
+	// A different request isn't cached
+	_, ok = c.Get(method, &pb.ListDeploymentsRequest{
+		Application: &pb.Ref_Application{Project: "p", Application: "b"},
+	}, &resp, 0)
+	require.False(ok)
+
+	// A different server isn't cached
+	other := New(c.dir, "server-b", nil)
+	_, ok = other.Get(method, req, &resp, 0)
+	require.False(ok)
+
+	// Expired responses aren't returned
+	path, err := c.path(method, req)
+	require.NoError(err)
+	old := time.Now().Add(-time.Hour)
+	require.NoError(os.Chtimes(path, old, old))
+	_, ok = c.Get(method, req, &resp, time.Minute)
+	require.False(ok)
+	_, ok = c.Get(method, req, &resp, 0)
+	require.True(ok)
+}
+
+func TestCache_notCacheable(t *testing.T) {
+	require := require.New(t)
+
+	c := testCache(t, "server")
+	method := methodPrefix + "GetConfig"
+	req := &pb.ConfigGetRequest{Prefix: "secret"}
+
+	require.NoError(c.Put(method, req, &pb.ConfigGetResponse{}))
+
+	var resp pb.ConfigGetResponse
+	_, ok := c.Get(method, req, &resp, 0)
+	require.False(ok)
+}
+
+func TestConn_offline(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	c := testCache(t, "server")
+	require.NoError(c.Put(methodPrefix+"ListProjects", &empty.Empty{},
+		&pb.ListProjectsResponse{
+			Projects: []*pb.Ref_Project{{Project: "p"}},
+		}))
+
+	client := pb.NewWaypointClient(c.Conn(0, nil))
+
+	// Cached responses are served
+	resp, err := client.ListProjects(ctx, &empty.Empty{})
+	require.NoError(err)
+	require.Len(resp.Projects, 1)
+
+	// Other requests are unavailable
+	_, err = client.ListDeployments(ctx, &pb.ListDeploymentsRequest{})
+	require.Error(err)
+	require.Equal(codes.Unavailable, status.Code(err))
+}
+
+func testCache(t *testing.T, server string) *Cache {
+	td, err := ioutil.TempDir("", "waypoint-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(td) })
+
+	return New(td, server, nil)
+}
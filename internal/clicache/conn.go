@@ -0,0 +1,157 @@
+package clicache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// UnaryClientInterceptor returns an interceptor that stores the responses
+// to cacheable requests in the cache. Requests are always sent to the
+// server, this only keeps the cache up to date.
+func (c *Cache) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		if err := invoker(ctx, method, req, reply, cc, opts...); err != nil {
+			return err
+		}
+
+		c.put(method, req, reply)
+		return nil
+	}
+}
+
+// Conn returns a connection that serves cacheable requests from the cache
+// if the cached response is newer than maxAge. A maxAge of zero accepts
+// responses of any age.
+//
+// If a request can't be served from the cache, dial is called to connect
+// to the server, at most once, and the response is cached. If dial is nil,
+// requests that can't be served from the cache fail as unavailable.
+func (c *Cache) Conn(
+	maxAge time.Duration,
+	dial func() (grpc.ClientConnInterface, error),
+) *Conn {
+	return &Conn{cache: c, maxAge: maxAge, dial: dial}
+}
+
+// Conn is a grpc.ClientConnInterface that serves responses from a Cache.
+// Use pb.NewWaypointClient to get a client that uses it.
+type Conn struct {
+	cache  *Cache
+	maxAge time.Duration
+	dial   func() (grpc.ClientConnInterface, error)
+
+	mu      sync.Mutex
+	conn    grpc.ClientConnInterface
+	dialErr error
+	oldest  time.Time
+}
+
+// Oldest returns the time the oldest response served from the cache was
+// cached. This is the zero time if no cached response was served.
+func (c *Conn) Oldest() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.oldest
+}
+
+// Invoke implements grpc.ClientConnInterface.
+func (c *Conn) Invoke(
+	ctx context.Context,
+	method string,
+	args, reply interface{},
+	opts ...grpc.CallOption,
+) error {
+	if Cacheable(method) {
+		req, reqOk := args.(proto.Message)
+		resp, respOk := reply.(proto.Message)
+		if reqOk && respOk {
+			if t, ok := c.cache.Get(method, req, resp, c.maxAge); ok {
+				c.mu.Lock()
+				if c.oldest.IsZero() || t.Before(c.oldest) {
+					c.oldest = t
+				}
+				c.mu.Unlock()
+
+				return nil
+			}
+		}
+	}
+
+	conn, err := c.connect()
+	if err != nil {
+		return err
+	}
+
+	if err := conn.Invoke(ctx, method, args, reply, opts...); err != nil {
+		return err
+	}
+
+	c.cache.put(method, args, reply)
+	return nil
+}
+
+// NewStream implements grpc.ClientConnInterface. Streams are never cached
+// so they always require a connection to the server.
+func (c *Conn) NewStream(
+	ctx context.Context,
+	desc *grpc.StreamDesc,
+	method string,
+	opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	conn, err := c.connect()
+	if err != nil {
+		return nil, err
+	}
+
+	return conn.NewStream(ctx, desc, method, opts...)
+}
+
+// connect returns the connection to the server, dialing it if necessary.
+func (c *Conn) connect() (grpc.ClientConnInterface, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn != nil || c.dialErr != nil {
+		return c.conn, c.dialErr
+	}
+
+	if c.dial == nil {
+		c.dialErr = status.Error(codes.Unavailable,
+			"the response isn't cached; run the command while connected "+
+				"to the server to cache it")
+		return nil, c.dialErr
+	}
+
+	c.conn, c.dialErr = c.dial()
+	return c.conn, c.dialErr
+}
+
+// put stores the response if both values are protobuf messages. Errors are
+// only logged since failing to cache must never fail the request.
+func (c *Cache) put(method string, req, reply interface{}) {
+	reqMsg, ok := req.(proto.Message)
+	if !ok {
+		return
+	}
+	replyMsg, ok := reply.(proto.Message)
+	if !ok {
+		return
+	}
+
+	if err := c.Put(method, reqMsg, replyMsg); err != nil {
+		c.logger.Warn("error caching response", "method", method, "err", err)
+	}
+}
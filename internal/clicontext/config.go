@@ -1,6 +1,8 @@
 package clicontext
 
 import (
+	"encoding/json"
+	"fmt"
 	"io"
 
 	"github.com/hashicorp/hcl/v2/gohcl"
@@ -30,3 +32,43 @@ func (c *Config) WriteTo(w io.Writer) (int64, error) {
 	gohcl.EncodeIntoBody(c, f.Body())
 	return f.WriteTo(w)
 }
+
+// exportVersion is the version of the format written by Export. This must
+// be incremented if the format changes in a way older versions can't read.
+const exportVersion = 1
+
+// exportedConfig is the structure of an exported context.
+type exportedConfig struct {
+	Version int                 `json:"version"`
+	Server  serverconfig.Client `json:"server"`
+}
+
+// Export encodes this config as a single JSON document that can be loaded
+// with Import. Unlike the file written by WriteTo, this is small enough to
+// be passed around as an environment variable or CI secret.
+func (c *Config) Export() ([]byte, error) {
+	return json.Marshal(&exportedConfig{
+		Version: exportVersion,
+		Server:  c.Server,
+	})
+}
+
+// Import decodes a config that was encoded with Export.
+func Import(data []byte) (*Config, error) {
+	var v exportedConfig
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("invalid context: %s", err)
+	}
+
+	if v.Version != exportVersion {
+		return nil, fmt.Errorf(
+			"unsupported context version %d, this version of Waypoint supports %d",
+			v.Version, exportVersion)
+	}
+
+	if v.Server.Address == "" {
+		return nil, fmt.Errorf("invalid context: server address is required")
+	}
+
+	return &Config{Server: v.Server}, nil
+}
@@ -0,0 +1,46 @@
+package clicontext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/waypoint/internal/serverconfig"
+)
+
+func TestConfigExport(t *testing.T) {
+	require := require.New(t)
+
+	cfg := &Config{
+		Server: serverconfig.Client{
+			Address:       "waypoint.example.com:9701",
+			Tls:           true,
+			TlsSkipVerify: true,
+			RequireAuth:   true,
+			AuthToken:     "abcd",
+		},
+	}
+
+	data, err := cfg.Export()
+	require.NoError(err)
+
+	actual, err := Import(data)
+	require.NoError(err)
+	require.Equal(cfg, actual)
+}
+
+func TestImport_invalid(t *testing.T) {
+	cases := map[string]string{
+		"not json":    `waypoint`,
+		"no version":  `{"server": {"address": "localhost:9701"}}`,
+		"new version": `{"version": 99, "server": {"address": "localhost:9701"}}`,
+		"no address":  `{"version": 1, "server": {"tls": true}}`,
+	}
+
+	for name, data := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Import([]byte(data))
+			require.Error(t, err)
+		})
+	}
+}
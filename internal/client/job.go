@@ -354,6 +354,13 @@ func (c *Project) queueAndStreamJob(
 				stateEventTimer = nil
 			}
 
+			// If the runner stopped responding while running the job, the
+			// server queues the job to run again on another runner.
+			if event.State.Previous == pb.Job_RUNNING && event.State.Current == pb.Job_QUEUED {
+				ui.Output("The runner running this operation stopped responding. "+
+					"The operation is queued to run again.", terminal.WithWarningStyle())
+			}
+
 			// For certain states, we do a quality of life UI message if
 			// the wait time ends up being long.
 			switch event.State.Current {
@@ -2,6 +2,9 @@ package client
 
 import (
 	"context"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
 
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
 )
@@ -157,19 +160,68 @@ func (c *App) Release(ctx context.Context, op *pb.Job_ReleaseOp) (*pb.Job_Releas
 	return result.Release, nil
 }
 
-func (a *App) Logs(ctx context.Context) (pb.Waypoint_GetLogStreamClient, error) {
+// LogsFilter filters the logs streamed by Logs. The filters are applied by
+// the server so that only the matching lines are sent. The zero value
+// doesn't filter the logs.
+type LogsFilter struct {
+	// DeploymentId limits the logs to a single deployment rather than all
+	// the deployments of the app in the workspace.
+	DeploymentId string
+
+	// InstanceId limits the logs to a single instance.
+	InstanceId string
+
+	// Since and Until limit the logs to the lines with a timestamp in the
+	// range. If Until is set, the stream ends once the time has passed.
+	Since time.Time
+	Until time.Time
+
+	// Pattern is a regular expression that lines must match.
+	Pattern string
+}
+
+func (a *App) Logs(ctx context.Context, filter *LogsFilter) (pb.Waypoint_GetLogStreamClient, error) {
 	log := a.project.logger.Named("logs")
 
-	// First we attempt to query the server for logs for this deployment.
-	log.Info("requesting log stream")
-	client, err := a.project.client.GetLogStream(ctx, &pb.GetLogStreamRequest{
+	if filter == nil {
+		filter = &LogsFilter{}
+	}
+
+	req := &pb.GetLogStreamRequest{
 		Scope: &pb.GetLogStreamRequest_Application_{
 			Application: &pb.GetLogStreamRequest_Application{
 				Application: a.Ref(),
 				Workspace:   a.project.WorkspaceRef(),
 			},
 		},
-	})
+		InstanceId: filter.InstanceId,
+		Pattern:    filter.Pattern,
+	}
+	if filter.DeploymentId != "" {
+		req.Scope = &pb.GetLogStreamRequest_DeploymentId{
+			DeploymentId: filter.DeploymentId,
+		}
+	}
+	if !filter.Since.IsZero() {
+		ts, err := ptypes.TimestampProto(filter.Since)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Since = ts
+	}
+	if !filter.Until.IsZero() {
+		ts, err := ptypes.TimestampProto(filter.Until)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Until = ts
+	}
+
+	// First we attempt to query the server for logs for this deployment.
+	log.Info("requesting log stream")
+	client, err := a.project.client.GetLogStream(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -178,6 +230,17 @@ func (a *App) Logs(ctx context.Context) (pb.Waypoint_GetLogStreamClient, error)
 	return client, nil
 }
 
+// Dashboard opens a stream of the app's latest deployments along with the
+// job queue and runners on the server. A new message is received whenever
+// any of these change.
+func (a *App) Dashboard(ctx context.Context, limit uint32) (pb.Waypoint_GetDashboardStreamClient, error) {
+	return a.project.client.GetDashboardStream(ctx, &pb.GetDashboardStreamRequest{
+		Application:     a.Ref(),
+		Workspace:       a.project.WorkspaceRef(),
+		DeploymentLimit: limit,
+	})
+}
+
 func (c *App) ConfigSync(ctx context.Context, op *pb.Job_ConfigSyncOp) (*pb.Job_ConfigSyncResult, error) {
 	if op == nil {
 		op = &pb.Job_ConfigSyncOp{}
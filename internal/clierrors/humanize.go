@@ -1,7 +1,11 @@
 package clierrors
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/mitchellh/go-wordwrap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/status"
 )
 
@@ -16,8 +20,55 @@ func Humanize(err error) string {
 
 	v := err.Error()
 	if s, ok := status.FromError(err); ok {
-		v = s.Message()
+		v = humanizeStatus(s)
 	}
 
 	return wordwrap.WrapString(v, 80)
 }
+
+// humanizeStatus returns the message of a gRPC status along with the
+// standard error details that plugins and the server can attach to it,
+// such as the fields that are invalid and links to documentation.
+func humanizeStatus(s *status.Status) string {
+	msg := s.Message()
+	var extra, help []string
+	for _, d := range s.Details() {
+		switch d := d.(type) {
+		case *errdetails.LocalizedMessage:
+			// This is the message intended for people, so it replaces
+			// the message of the status.
+			if d.Message != "" {
+				msg = d.Message
+			}
+
+		case *errdetails.BadRequest:
+			for _, v := range d.FieldViolations {
+				extra = append(extra, fmt.Sprintf("  - %s: %s", v.Field, v.Description))
+			}
+
+		case *errdetails.PreconditionFailure:
+			for _, v := range d.Violations {
+				extra = append(extra, "  - "+v.Description)
+			}
+
+		case *errdetails.Help:
+			for _, l := range d.Links {
+				if l.Description != "" {
+					help = append(help, fmt.Sprintf("%s: %s", l.Description, l.Url))
+				} else {
+					help = append(help, "More information: "+l.Url)
+				}
+			}
+		}
+	}
+
+	parts := []string{msg}
+	if len(extra) > 0 {
+		parts = append(parts, "\n"+strings.Join(extra, "\n"))
+	}
+	if len(help) > 0 {
+		parts = append(parts, "\n"+strings.Join(help, "\n"))
+	}
+
+	return strings.Join(parts, "\n")
+}
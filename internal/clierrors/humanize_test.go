@@ -0,0 +1,56 @@
+package clierrors
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestHumanize(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		require.Equal(t, "", Humanize(nil))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		require.Equal(t, "oh no", Humanize(errors.New("oh no")))
+	})
+
+	t.Run("status", func(t *testing.T) {
+		err := status.Errorf(codes.FailedPrecondition, "oh no")
+		require.Equal(t, "oh no", Humanize(err))
+	})
+
+	t.Run("status with details", func(t *testing.T) {
+		require := require.New(t)
+
+		s, err := status.New(codes.InvalidArgument, "invalid config").WithDetails(
+			&errdetails.LocalizedMessage{
+				Locale:  "en-US",
+				Message: "The plugin configuration is invalid.",
+			},
+			&errdetails.BadRequest{
+				FieldViolations: []*errdetails.BadRequest_FieldViolation{
+					{Field: "image", Description: "must be set"},
+				},
+			},
+			&errdetails.Help{
+				Links: []*errdetails.Help_Link{
+					{Description: "Plugin docs", Url: "https://example.com/docs"},
+					{Url: "https://example.com/more"},
+				},
+			},
+		)
+		require.NoError(err)
+
+		require.Equal(`The plugin configuration is invalid.
+
+  - image: must be set
+
+Plugin docs: https://example.com/docs
+More information: https://example.com/more`, Humanize(s.Err()))
+	})
+}
@@ -0,0 +1,21 @@
+package config
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// Format returns the configuration in src formatted in the canonical
+// style. The filename is only used in diagnostics. The configuration must
+// be syntactically valid but it is otherwise not validated.
+func Format(src []byte, filename string) ([]byte, hcl.Diagnostics) {
+	// The formatter works on tokens and will happily format invalid
+	// configuration, so we parse it first to report syntax errors.
+	_, diags := hclsyntax.ParseConfig(src, filename, hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	return hclwrite.Format(src), nil
+}
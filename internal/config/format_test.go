@@ -0,0 +1,70 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Input    string
+		Expected string
+		Err      bool
+	}{
+		{
+			"already formatted",
+			"project = \"foo\"\n",
+			"project = \"foo\"\n",
+			false,
+		},
+
+		{
+			"aligns and indents",
+			`
+app "web" {
+build {
+use "docker" {
+foo = 1
+foobar  = 2
+}
+}
+}
+`,
+			`
+app "web" {
+  build {
+    use "docker" {
+      foo    = 1
+      foobar = 2
+    }
+  }
+}
+`,
+			false,
+		},
+
+		{
+			"syntax error",
+			"app \"web\" {\n",
+			"",
+			true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			require := require.New(t)
+
+			result, diags := Format([]byte(tt.Input), "waypoint.hcl")
+			if tt.Err {
+				require.True(diags.HasErrors())
+				return
+			}
+
+			require.False(diags.HasErrors())
+			require.Equal(tt.Expected, string(result))
+		})
+	}
+}
@@ -1,8 +1,13 @@
 package config
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/mitchellh/mapstructure"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
 
 	"github.com/hashicorp/waypoint-plugin-sdk/component"
 )
@@ -29,6 +34,84 @@ func (op *Operation) Configure(plugin interface{}, ctx *hcl.EvalContext) hcl.Dia
 	return component.Configure(plugin, op.Use.Body, ctx)
 }
 
+// UseConfig returns the configuration in the use body of this operation in
+// a normalized form that can be compared between operations. The keys are
+// the paths to each attribute, such as "resources.memory", and the values
+// are the JSON encoding of the evaluated attributes. Values that can't be
+// evaluated with ctx are left out.
+func (op *Operation) UseConfig(ctx *hcl.EvalContext) (map[string]string, hcl.Diagnostics) {
+	result := map[string]string{}
+	if op.Use == nil || op.Use.Body == nil {
+		return result, nil
+	}
+
+	ctx = appendContext(op.ctx, ctx)
+	ctx = finalizeContext(ctx)
+
+	diags := useConfigBody(result, "", op.Use.Body, ctx)
+	return result, diags
+}
+
+func useConfigBody(result map[string]string, prefix string, body hcl.Body, ctx *hcl.EvalContext) hcl.Diagnostics {
+	// Only native syntax bodies can have nested blocks that we can walk
+	// without a schema. Other bodies, such as JSON, are read as just
+	// attributes which covers most plugin configuration.
+	syntaxBody, ok := body.(*hclsyntax.Body)
+	if !ok {
+		attrs, diags := body.JustAttributes()
+		if diags.HasErrors() {
+			return diags
+		}
+
+		for name, attr := range attrs {
+			diags = append(diags, useConfigAttr(result, prefix+name, attr.Expr, ctx)...)
+		}
+
+		return diags
+	}
+
+	var diags hcl.Diagnostics
+	for name, attr := range syntaxBody.Attributes {
+		diags = append(diags, useConfigAttr(result, prefix+name, attr.Expr, ctx)...)
+	}
+
+	// Blocks are keyed by their type and labels. Repeated blocks get an
+	// index so that each has a unique path.
+	seen := map[string]int{}
+	for _, block := range syntaxBody.Blocks {
+		name := strings.Join(append([]string{block.Type}, block.Labels...), ".")
+		key := name
+		if n := seen[name]; n > 0 {
+			key = fmt.Sprintf("%s[%d]", name, n)
+		}
+		seen[name]++
+
+		diags = append(diags, useConfigBody(result, prefix+key+".", block.Body, ctx)...)
+	}
+
+	return diags
+}
+
+func useConfigAttr(result map[string]string, key string, expr hcl.Expression, ctx *hcl.EvalContext) hcl.Diagnostics {
+	v, diags := expr.Value(ctx)
+	if diags.HasErrors() || !v.IsWhollyKnown() {
+		return nil
+	}
+
+	raw, err := ctyjson.SimpleJSONValue{Value: v}.MarshalJSON()
+	if err != nil {
+		return hcl.Diagnostics{&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Failed to encode configuration value",
+			Detail:   err.Error(),
+			Subject:  expr.Range().Ptr(),
+		}}
+	}
+
+	result[key] = string(raw)
+	return nil
+}
+
 func (b *Build) Operation() *Operation {
 	return mapoperation(b, true)
 }
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationUseConfig(t *testing.T) {
+	require := require.New(t)
+
+	cfg := TestConfig(t, `
+project = "foo"
+
+app "web" {
+	build {
+		use "docker" {}
+	}
+
+	deploy {
+		use "kubernetes" {
+			replicas = 1 + 2
+			image    = "web:${artifact.tag}"
+
+			probe "http" {
+				path = "/health"
+			}
+
+			port { number = 80 }
+			port { number = 443 }
+		}
+	}
+}
+`)
+
+	app, err := cfg.App("web", nil)
+	require.NoError(err)
+
+	deploy, err := app.Deploy(nil)
+	require.NoError(err)
+
+	result, diags := deploy.Operation().UseConfig(nil)
+	require.False(diags.HasErrors())
+
+	// The image can't be evaluated without an artifact so it isn't set.
+	require.Equal(map[string]string{
+		"replicas":        "3",
+		"probe.http.path": `"/health"`,
+		"port.number":     "80",
+		"port[1].number":  "443",
+	}, result)
+}
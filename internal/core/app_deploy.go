@@ -53,10 +53,24 @@ func (a *App) Deploy(ctx context.Context, push *pb.PushedArtifact) (*pb.Deployme
 	}
 	defer c.Close()
 
+	// Get the normalized plugin configuration so that we can record it
+	// with the deployment. This is only used to compare deployments so
+	// we don't fail the deploy if it doesn't work.
+	var pluginConfig map[string]string
+	if cfg, err := a.config.Deploy(&evalCtx); err == nil {
+		var diags hcl.Diagnostics
+		pluginConfig, diags = cfg.Operation().UseConfig(&evalCtx)
+		if diags.HasErrors() {
+			a.logger.Warn("failed to normalize plugin config, will not be recorded",
+				"err", diags)
+		}
+	}
+
 	_, msg, err := a.doOperation(ctx, a.logger.Named("deploy"), &deployOperation{
 		Component:        c,
 		Push:             push,
 		DeploymentConfig: deployConfig,
+		PluginConfig:     pluginConfig,
 	})
 	if err != nil {
 		return nil, err
@@ -70,6 +84,10 @@ type deployOperation struct {
 	Push             *pb.PushedArtifact
 	DeploymentConfig *component.DeploymentConfig
 
+	// PluginConfig is the normalized plugin configuration that is recorded
+	// in the config fingerprint of the deployment.
+	PluginConfig map[string]string
+
 	// Set by init
 	autoHostname pb.UpsertDeploymentRequest_Tristate
 
@@ -150,12 +168,21 @@ func (op *deployOperation) Upsert(
 	return resp.Deployment, nil
 }
 
-func (op *deployOperation) Do(ctx context.Context, log hclog.Logger, app *App, _ proto.Message) (interface{}, error) {
+func (op *deployOperation) Do(ctx context.Context, log hclog.Logger, app *App, msg proto.Message) (interface{}, error) {
 	// Sync our config first
 	if err := app.ConfigSync(ctx); err != nil {
 		return nil, err
 	}
 
+	// Record the configuration the deployment sees. This is only used to
+	// compare deployments so any errors are logged but ignored.
+	fp, err := app.deployConfigFingerprint(ctx, op.Component.Info, op.PluginConfig)
+	if err != nil {
+		log.Warn("error getting config fingerprint, will not be recorded", "err", err)
+	} else {
+		msg.(*pb.Deployment).ConfigFingerprint = fp
+	}
+
 	dconfig := *op.DeploymentConfig
 	dconfig.Id = op.id
 	dconfig.EntrypointInviteToken = op.cebToken
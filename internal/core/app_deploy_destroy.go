@@ -30,7 +30,9 @@ func (a *App) CanDestroyDeploy() bool {
 }
 
 // DestroyDeploy destroyes a specific deployment.
-func (a *App) DestroyDeploy(ctx context.Context, d *pb.Deployment) error {
+func (a *App) DestroyDeploy(ctx context.Context, d *pb.Deployment, opts ...DestroyOption) error {
+	force := newDestroyOptions(opts).Force
+
 	// If the deploy is destroyed already then do nothing.
 	if d.State == pb.Operation_DESTROYED {
 		a.logger.Info("deployment already destroyed, doing nothing", "id", d.Id)
@@ -57,6 +59,13 @@ func (a *App) DestroyDeploy(ctx context.Context, d *pb.Deployment) error {
 			a.logger.Warn("artifact not found, will attempt destroy regardless",
 				"artifact_id", d.ArtifactId)
 		}
+		if err != nil && force {
+			resp = nil
+			a.logger.Warn("error querying artifact, will attempt destroy regardless due to force",
+				"artifact_id", d.ArtifactId,
+				"error", err)
+			err = nil
+		}
 		if err != nil {
 			a.logger.Error("error querying artifact",
 				"artifact_id", d.ArtifactId,
@@ -74,8 +83,15 @@ func (a *App) DestroyDeploy(ctx context.Context, d *pb.Deployment) error {
 			"err", err)
 	}
 
-	// Start the plugin
+	// Start the plugin. If we're forcing the destroy then we still mark
+	// the deployment as destroyed without a plugin.
 	c, err := componentCreatorMap[component.PlatformType].Create(ctx, a, &evalCtx)
+	if err != nil && force {
+		a.logger.Warn("error starting platform plugin, marking destroyed due to force",
+			"error", err)
+		c = nil
+		err = nil
+	}
 	if err != nil {
 		return err
 	}
@@ -85,11 +101,22 @@ func (a *App) DestroyDeploy(ctx context.Context, d *pb.Deployment) error {
 		Component:  c,
 		Deployment: d,
 	})
+	if err != nil && force {
+		// The operation marks the deployment as destroyed even if the
+		// plugin fails, so we only have to warn.
+		a.logger.Warn("error destroying deployment, marked destroyed due to force",
+			"id", d.Id,
+			"error", err)
+		a.UI.Output("Error destroying deployment %s, marked destroyed due to -force: %s",
+			d.Id, err, terminal.WithWarningStyle())
+		err = nil
+	}
+
 	return err
 }
 
 // destroyAllDeploys will destroy all non-destroyed releases.
-func (a *App) destroyAllDeploys(ctx context.Context) error {
+func (a *App) destroyAllDeploys(ctx context.Context, opts ...DestroyOption) error {
 	resp, err := a.client.ListDeployments(ctx, &pb.ListDeploymentsRequest{
 		Application:   a.ref,
 		Workspace:     a.workspace,
@@ -117,7 +144,7 @@ func (a *App) destroyAllDeploys(ctx context.Context) error {
 
 	a.UI.Output(fmt.Sprintf("Destroying deployments for application '%s'...", a.config.Name), terminal.WithHeaderStyle())
 	for _, v := range results {
-		err := a.DestroyDeploy(ctx, v)
+		err := a.DestroyDeploy(ctx, v, opts...)
 		if err != nil {
 			return err
 		}
@@ -213,6 +240,11 @@ func (op *deployDestroyOperation) Upsert(
 }
 
 func (op *deployDestroyOperation) Do(ctx context.Context, log hclog.Logger, app *App, _ proto.Message) (interface{}, error) {
+	// If we have no platform then we're done.
+	if op.Component == nil {
+		return nil, nil
+	}
+
 	destroyer, ok := op.Component.Value.(component.Destroyer)
 	if !ok || destroyer.DestroyFunc() == nil {
 		return nil, nil
@@ -142,6 +142,52 @@ func TestAppDestroyDeploy_happy(t *testing.T) {
 	}
 }
 
+func TestAppDestroyDeploy_force(t *testing.T) {
+	require := require.New(t)
+
+	// Our mock platform, which must also implement Destroyer
+	mock := struct {
+		*componentmocks.Platform
+		*componentmocks.Destroyer
+	}{
+		&componentmocks.Platform{},
+		&componentmocks.Destroyer{},
+	}
+
+	// Make our factory for platforms
+	factory := TestFactory(t, component.PlatformType)
+	TestFactoryRegister(t, factory, "test", mock)
+
+	// Make our app
+	app := TestApp(t, TestProject(t,
+		WithConfig(config.TestConfig(t, testPlatformConfig)),
+		WithFactory(component.PlatformType, factory),
+	), "test")
+
+	// The plugin fails, such as when the resources are already gone.
+	deployment, err := ptypes.MarshalAny(&empty.Empty{})
+	require.NoError(err)
+	mock.Destroyer.On("DestroyFunc").Return(func() error {
+		return fmt.Errorf("error!")
+	})
+
+	// Destroy with force should not error
+	require.NoError(app.DestroyDeploy(context.Background(), &pb.Deployment{
+		Application: app.ref,
+		Workspace:   app.workspace,
+		Deployment:  deployment,
+	}, WithDestroyForce(true)))
+
+	// Verify that we still marked it destroyed
+	resp, err := app.client.ListDeployments(context.Background(), &pb.ListDeploymentsRequest{
+		Application: app.ref,
+		Workspace:   app.workspace,
+	})
+	require.NoError(err)
+	require.Len(resp.Deployments, 1)
+	require.Equal(pb.Operation_DESTROYED, resp.Deployments[0].State)
+}
+
 const testPlatformConfig = `
 project = "test"
 
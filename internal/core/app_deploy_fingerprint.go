@@ -0,0 +1,89 @@
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// deployConfigFingerprint returns the fingerprint of the configuration of
+// a deployment that uses the given plugin configuration. This must be
+// called after the config is synced so that the config variables are the
+// ones the deployment will see.
+func (a *App) deployConfigFingerprint(
+	ctx context.Context,
+	component *pb.Component,
+	pluginConfig map[string]string,
+) (*pb.Deployment_ConfigFingerprint, error) {
+	resp, err := a.client.GetConfig(ctx, &pb.ConfigGetRequest{
+		Scope: &pb.ConfigGetRequest_Application{
+			Application: a.ref,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	vars := map[string]string{}
+	for _, v := range resp.Variables {
+		if h := configVarHash(v); h != "" {
+			vars[v.Name] = h
+		}
+	}
+
+	// The hash covers the plugin, its configuration, and the variables.
+	// We sort everything since map iteration order is random.
+	h := sha256.New()
+	if component != nil {
+		fmt.Fprintf(h, "component\x00%s\x00%s\x00", component.Type, component.Name)
+	}
+	hashMap(h, "plugin", pluginConfig)
+	hashMap(h, "var", vars)
+
+	return &pb.Deployment_ConfigFingerprint{
+		PluginConfig: pluginConfig,
+		ConfigVars:   vars,
+		Hash:         hex.EncodeToString(h.Sum(nil)),
+	}, nil
+}
+
+// configVarHash returns a hash of the value of a config variable. This
+// returns an empty string if the variable is unset.
+func configVarHash(v *pb.ConfigVar) string {
+	h := sha256.New()
+	switch value := v.Value.(type) {
+	case *pb.ConfigVar_Static:
+		if value.Static == "" {
+			return ""
+		}
+
+		fmt.Fprintf(h, "static\x00%s", value.Static)
+
+	case *pb.ConfigVar_Dynamic:
+		fmt.Fprintf(h, "dynamic\x00%s\x00", value.Dynamic.From)
+		hashMap(h, "config", value.Dynamic.Config)
+
+	default:
+		return ""
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// hashMap writes the map m to w in a stable order.
+func hashMap(w io.Writer, prefix string, m map[string]string) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s\x00%s\x00%s\x00", prefix, k, m[k])
+	}
+}
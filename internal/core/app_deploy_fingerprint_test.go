@@ -0,0 +1,69 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/component"
+	componentmocks "github.com/hashicorp/waypoint-plugin-sdk/component/mocks"
+	"github.com/hashicorp/waypoint/internal/config"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func TestAppDeployConfigFingerprint(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	// Make our factory for platforms
+	factory := TestFactory(t, component.PlatformType)
+	TestFactoryRegister(t, factory, "test", &componentmocks.Platform{})
+
+	// Make our app
+	app := TestApp(t, TestProject(t,
+		WithConfig(config.TestConfig(t, testPlatformConfig)),
+		WithFactory(component.PlatformType, factory),
+	), "test")
+
+	setVar := func(name, value string) {
+		_, err := app.client.SetConfig(ctx, &pb.ConfigSetRequest{
+			Variables: []*pb.ConfigVar{
+				{
+					Scope: &pb.ConfigVar_Application{Application: app.ref},
+					Name:  name,
+					Value: &pb.ConfigVar_Static{Static: value},
+				},
+			},
+		})
+		require.NoError(err)
+	}
+
+	info := &pb.Component{Type: pb.Component_PLATFORM, Name: "test"}
+	pluginConfig := map[string]string{"replicas": "3"}
+
+	setVar("DATABASE_URL", "postgres://a")
+	fp1, err := app.deployConfigFingerprint(ctx, info, pluginConfig)
+	require.NoError(err)
+	require.Equal(pluginConfig, fp1.PluginConfig)
+	require.Contains(fp1.ConfigVars, "DATABASE_URL")
+	require.NotEqual("postgres://a", fp1.ConfigVars["DATABASE_URL"])
+	require.NotEmpty(fp1.Hash)
+
+	// The same config has the same fingerprint
+	fp2, err := app.deployConfigFingerprint(ctx, info, pluginConfig)
+	require.NoError(err)
+	require.Equal(fp1.Hash, fp2.Hash)
+
+	// Changing a variable changes the fingerprint
+	setVar("DATABASE_URL", "postgres://b")
+	fp3, err := app.deployConfigFingerprint(ctx, info, pluginConfig)
+	require.NoError(err)
+	require.NotEqual(fp1.ConfigVars["DATABASE_URL"], fp3.ConfigVars["DATABASE_URL"])
+	require.NotEqual(fp1.Hash, fp3.Hash)
+
+	// Changing the plugin config changes the fingerprint
+	fp4, err := app.deployConfigFingerprint(ctx, info, map[string]string{"replicas": "4"})
+	require.NoError(err)
+	require.NotEqual(fp3.Hash, fp4.Hash)
+}
@@ -2,14 +2,44 @@ package core
 
 import (
 	"context"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
 )
 
+// DestroyOption is used to configure a destroy.
+type DestroyOption func(*destroyOptions)
+
+type destroyOptions struct {
+	// Force continues the destroy if the plugin fails to destroy the
+	// resources, such as when they were already removed outside of
+	// Waypoint. The records are marked as destroyed regardless.
+	Force bool
+}
+
+// WithDestroyForce sets whether the destroy is forced. A forced destroy
+// reports plugin errors as warnings and marks the records as destroyed
+// so that records whose resources are already gone can be cleaned up.
+func WithDestroyForce(v bool) DestroyOption {
+	return func(opts *destroyOptions) {
+		opts.Force = v
+	}
+}
+
+func newDestroyOptions(opts []DestroyOption) *destroyOptions {
+	var result destroyOptions
+	for _, opt := range opts {
+		opt(&result)
+	}
+
+	return &result
+}
+
 // Destroy will destroy all the physical resources for this app in the current
 // configured workspace. If this returns an error, it is possible that the
 // destroy is in a partial state.
-func (a *App) Destroy(ctx context.Context) error {
+func (a *App) Destroy(ctx context.Context, opts ...DestroyOption) error {
 	destroyers := []struct {
-		DestroyFunc          func(context.Context) error
+		DestroyFunc          func(context.Context, ...DestroyOption) error
 		DestroyWorkspaceFunc func(context.Context) error
 	}{
 		{
@@ -24,7 +54,7 @@ func (a *App) Destroy(ctx context.Context) error {
 
 	// First we need to destroy all operations.
 	for _, d := range destroyers {
-		if err := d.DestroyFunc(ctx); err != nil {
+		if err := d.DestroyFunc(ctx, opts...); err != nil {
 			return err
 		}
 	}
@@ -32,7 +62,14 @@ func (a *App) Destroy(ctx context.Context) error {
 	// Next we call the destroy workspace hooks.
 	for _, d := range destroyers {
 		if err := d.DestroyWorkspaceFunc(ctx); err != nil {
-			return err
+			if !newDestroyOptions(opts).Force {
+				return err
+			}
+
+			a.logger.Warn("error destroying workspace resources, continuing due to force",
+				"error", err)
+			a.UI.Output("Error destroying shared resources, continuing due to -force: %s",
+				err, terminal.WithWarningStyle())
 		}
 	}
 
@@ -8,6 +8,7 @@ import (
 	"github.com/golang/protobuf/ptypes/any"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -31,11 +32,19 @@ func (a *App) PushBuild(ctx context.Context, optFuncs ...PushBuildOption) (*pb.P
 
 	// Add our build to our config
 	var evalCtx hcl.EvalContext
+	evalCtx.Variables = map[string]cty.Value{}
 	if err := evalCtxTemplateProto(&evalCtx, "artifact", opts.Build); err != nil {
 		a.logger.Warn("failed to prepare template variables, will not be available",
 			"err", err)
 	}
 
+	// Expose the build itself so that pushes can be named after it, such
+	// as tagging images with the build sequence.
+	evalCtx.Variables["build"] = cty.ObjectVal(map[string]cty.Value{
+		"id":       cty.StringVal(opts.Build.Id),
+		"sequence": cty.NumberUIntVal(opts.Build.Sequence),
+	})
+
 	// Make our registry
 	cr, err := componentCreatorMap[component.RegistryType].Create(ctx, a, &evalCtx)
 	if status.Code(err) == codes.Unimplemented {
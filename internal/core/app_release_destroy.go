@@ -36,7 +36,9 @@ func (a *App) CanDestroyRelease() bool {
 }
 
 // DestroyRelease destroyes a specific release.
-func (a *App) DestroyRelease(ctx context.Context, d *pb.Release) error {
+func (a *App) DestroyRelease(ctx context.Context, d *pb.Release, opts ...DestroyOption) error {
+	force := newDestroyOptions(opts).Force
+
 	// If the release is destroyed already then do nothing.
 	if d.State == pb.Operation_DESTROYED {
 		a.logger.Info("release already destroyed, doing nothing", "id", d.Id)
@@ -60,6 +62,13 @@ func (a *App) DestroyRelease(ctx context.Context, d *pb.Release) error {
 		a.logger.Warn("deployment not found, will attempt destroy regardless",
 			"deployment_id", d.DeploymentId)
 	}
+	if err != nil && force {
+		resp = nil
+		a.logger.Warn("error querying deployment, will attempt destroy regardless due to force",
+			"deployment_id", d.DeploymentId,
+			"error", err)
+		err = nil
+	}
 	if err != nil {
 		a.logger.Error("error querying deployment",
 			"deployment_id", d.DeploymentId,
@@ -86,6 +95,12 @@ func (a *App) DestroyRelease(ctx context.Context, d *pb.Release) error {
 		c = nil
 		err = nil
 	}
+	if err != nil && force {
+		a.logger.Warn("error starting release plugin, marking destroyed due to force",
+			"error", err)
+		c = nil
+		err = nil
+	}
 	if err != nil {
 		return err
 	}
@@ -95,11 +110,22 @@ func (a *App) DestroyRelease(ctx context.Context, d *pb.Release) error {
 		Component: c,
 		Release:   d,
 	})
+	if err != nil && force {
+		// The operation marks the release as destroyed even if the
+		// plugin fails, so we only have to warn.
+		a.logger.Warn("error destroying release, marked destroyed due to force",
+			"id", d.Id,
+			"error", err)
+		a.UI.Output("Error destroying release %s, marked destroyed due to -force: %s",
+			d.Id, err, terminal.WithWarningStyle())
+		err = nil
+	}
+
 	return err
 }
 
 // destroyAllReleases will destroy all non-destroyed releases.
-func (a *App) destroyAllReleases(ctx context.Context) error {
+func (a *App) destroyAllReleases(ctx context.Context, opts ...DestroyOption) error {
 	resp, err := a.client.ListReleases(ctx, &pb.ListReleasesRequest{
 		Application:   a.ref,
 		Workspace:     a.workspace,
@@ -116,7 +142,7 @@ func (a *App) destroyAllReleases(ctx context.Context) error {
 
 	a.UI.Output(fmt.Sprintf("Destroying releases for application '%s'...", a.config.Name), terminal.WithHeaderStyle())
 	for _, rel := range rels {
-		err := a.DestroyRelease(ctx, rel)
+		err := a.DestroyRelease(ctx, rel, opts...)
 		if err != nil {
 			return err
 		}
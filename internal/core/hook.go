@@ -2,16 +2,22 @@ package core
 
 import (
 	"context"
+	"os"
 	"os/exec"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hashicorp/go-hclog"
 
 	"github.com/hashicorp/waypoint/internal/config"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
 )
 
 // execHook executes the given hook. This will return any errors. This ignores
 // on_failure configurations so this must be processed external.
-func (a *App) execHook(ctx context.Context, log hclog.Logger, h *config.Hook) error {
+//
+// msg is the metadata of the operation the hook is for. The hook command
+// is given the details of it with the environment variables from hookEnv.
+func (a *App) execHook(ctx context.Context, log hclog.Logger, h *config.Hook, msg proto.Message) error {
 	log.Debug("executing hook", "command", h.Command)
 
 	// Get our writers
@@ -25,6 +31,7 @@ func (a *App) execHook(ctx context.Context, log hclog.Logger, h *config.Hook) er
 	cmd := exec.CommandContext(ctx, h.Command[0], h.Command[1:]...)
 	cmd.Stdout = stdout
 	cmd.Stderr = stderr
+	cmd.Env = append(os.Environ(), a.hookEnv(h, msg)...)
 
 	// Start
 	if err := cmd.Start(); err != nil {
@@ -47,3 +54,49 @@ func (a *App) execHook(ctx context.Context, log hclog.Logger, h *config.Hook) er
 
 	return nil
 }
+
+// hookEnv returns the environment variables that tell a hook command what
+// operation it is running for, so that hooks don't need wrapper scripts to
+// find out which artifact or deployment they should act on.
+func (a *App) hookEnv(h *config.Hook, msg proto.Message) []string {
+	env := []string{
+		"WAYPOINT_PROJECT=" + a.ref.Project,
+		"WAYPOINT_APP=" + a.ref.Application,
+		"WAYPOINT_WORKSPACE=" + a.workspace.Workspace,
+		"WAYPOINT_HOOK_WHEN=" + h.When,
+	}
+	if a.jobInfo != nil && a.jobInfo.Id != "" {
+		env = append(env, "WAYPOINT_JOB_ID="+a.jobInfo.Id)
+	}
+
+	switch v := msg.(type) {
+	case *pb.Build:
+		env = append(env,
+			"WAYPOINT_OPERATION=build",
+			"WAYPOINT_BUILD_ID="+v.Id,
+		)
+
+	case *pb.PushedArtifact:
+		env = append(env,
+			"WAYPOINT_OPERATION=push",
+			"WAYPOINT_BUILD_ID="+v.BuildId,
+			"WAYPOINT_ARTIFACT_ID="+v.Id,
+		)
+
+	case *pb.Deployment:
+		env = append(env,
+			"WAYPOINT_OPERATION=deploy",
+			"WAYPOINT_ARTIFACT_ID="+v.ArtifactId,
+			"WAYPOINT_DEPLOYMENT_ID="+v.Id,
+		)
+
+	case *pb.Release:
+		env = append(env,
+			"WAYPOINT_OPERATION=release",
+			"WAYPOINT_DEPLOYMENT_ID="+v.DeploymentId,
+			"WAYPOINT_RELEASE_ID="+v.Id,
+		)
+	}
+
+	return env
+}
@@ -0,0 +1,76 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/component"
+	componentmocks "github.com/hashicorp/waypoint-plugin-sdk/component/mocks"
+	"github.com/hashicorp/waypoint/internal/config"
+)
+
+func TestAppHook_env(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook command requires sh")
+	}
+
+	require := require.New(t)
+
+	td, err := ioutil.TempDir("", "waypoint")
+	require.NoError(err)
+	defer os.RemoveAll(td)
+	out := filepath.Join(td, "env")
+
+	// Make our factory for builders
+	mock := &componentmocks.Builder{}
+	factory := TestFactory(t, component.BuilderType)
+	TestFactoryRegister(t, factory, "test", mock)
+
+	// Make our app
+	app := TestApp(t, TestProject(t,
+		WithConfig(config.TestConfig(t, fmt.Sprintf(testHookEnvConfig, out))),
+		WithFactory(component.BuilderType, factory),
+		WithJobInfo(&component.JobInfo{Id: "hello"}),
+	), "test")
+
+	// Setup our value
+	artifact := &componentmocks.Artifact{}
+	artifact.On("Labels").Return(map[string]string{})
+	mock.On("BuildFunc").Return(func() component.Artifact {
+		return artifact
+	})
+
+	build, _, err := app.Build(context.Background())
+	require.NoError(err)
+
+	// The hook sees the build it ran for
+	data, err := ioutil.ReadFile(out)
+	require.NoError(err)
+	require.Equal(fmt.Sprintf("build %s after hello test test\n", build.Id), string(data))
+}
+
+const testHookEnvConfig = `
+project = "test"
+
+app "test" {
+	build {
+		use "test" {}
+
+		hook {
+			when    = "after"
+			command = ["sh", "-c", "echo $WAYPOINT_OPERATION $WAYPOINT_BUILD_ID $WAYPOINT_HOOK_WHEN $WAYPOINT_JOB_ID $WAYPOINT_PROJECT $WAYPOINT_APP > %s"]
+		}
+	}
+
+	deploy {
+		use "test" {}
+	}
+}
+`
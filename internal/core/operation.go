@@ -108,7 +108,7 @@ func (a *App) doOperation(
 
 	// If we have before hooks, run those
 	for i, h := range hooks["before"] {
-		if err := a.execHook(ctx, log.Named(fmt.Sprintf("hook-before-%d", i)), h); err != nil {
+		if err := a.execHook(ctx, log.Named(fmt.Sprintf("hook-before-%d", i)), h, msg); err != nil {
 			doErr = fmt.Errorf("Error running before hook index %d: %w", i, err)
 			log.Warn("error running before hook", "err", err)
 
@@ -151,7 +151,7 @@ func (a *App) doOperation(
 	// Run after hooks
 	if doErr == nil {
 		for i, h := range hooks["after"] {
-			if err := a.execHook(ctx, log.Named(fmt.Sprintf("hook-after-%d", i)), h); err != nil {
+			if err := a.execHook(ctx, log.Named(fmt.Sprintf("hook-after-%d", i)), h, msg); err != nil {
 				doErr = fmt.Errorf("Error running after hook index %d: %w", i, err)
 				log.Warn("error running after hook", "err", err)
 
@@ -0,0 +1,110 @@
+// Package chunksize provides an adaptive chunk size for streaming data
+// over a connection with unknown latency and bandwidth.
+package chunksize
+
+import (
+	"time"
+)
+
+const (
+	// DefaultMin is the smallest chunk size a Sizer will shrink to by default.
+	DefaultMin = 4 * 1024 // 4 KB
+
+	// DefaultInitial is the default starting chunk size. This is modest so
+	// that slow connections aren't overwhelmed before we take measurements.
+	DefaultInitial = 64 * 1024 // 64 KB
+
+	// DefaultMax is the largest chunk size a Sizer will grow to by default.
+	// This is kept below the default 4 MB gRPC receive limit of the server
+	// to leave room for message framing.
+	DefaultMax = 3 * 1024 * 1024 // 3 MB
+
+	// tolerance is the fractional drop in throughput we allow before we
+	// decide a change made things worse. Network timings are noisy so
+	// without this we'd reverse direction constantly.
+	tolerance = 0.05
+)
+
+// Sizer chooses a chunk size by measuring the throughput of each send
+// and climbing towards the size with the best throughput. It doubles the
+// size while throughput improves, and reverses direction when it gets
+// worse, always staying within [Min, Max].
+//
+// Sizer is not safe for concurrent use.
+type Sizer struct {
+	min, max int
+
+	size     int
+	grow     bool
+	lastRate float64
+}
+
+// New returns a Sizer that starts at the initial size and stays within
+// the min and max bounds. If any value is zero or negative, the
+// corresponding default is used.
+func New(min, initial, max int) *Sizer {
+	if min <= 0 {
+		min = DefaultMin
+	}
+	if max <= 0 {
+		max = DefaultMax
+	}
+	if initial <= 0 {
+		initial = DefaultInitial
+	}
+
+	s := &Sizer{min: min, max: max, grow: true}
+	s.size = s.clamp(initial)
+	return s
+}
+
+// Size returns the chunk size that should be used for the next send.
+func (s *Sizer) Size() int {
+	return s.size
+}
+
+// Max returns the largest size this Sizer will ever return from Size.
+// This can be used to allocate a single buffer up front.
+func (s *Sizer) Max() int {
+	return s.max
+}
+
+// Observe records that n bytes took d to send and adjusts the chunk size
+// for the next send. Sends of zero bytes or zero duration are ignored.
+func (s *Sizer) Observe(n int, d time.Duration) {
+	if n <= 0 || d <= 0 {
+		return
+	}
+
+	rate := float64(n) / d.Seconds()
+	if s.lastRate > 0 && rate < s.lastRate*(1-tolerance) {
+		// Our last change made things worse, go the other way.
+		s.grow = !s.grow
+	}
+	s.lastRate = rate
+
+	next := s.size / 2
+	if s.grow {
+		next = s.size * 2
+	}
+	next = s.clamp(next)
+
+	// If we hit a bound then turn around so we keep probing rather than
+	// sitting at the bound without learning anything.
+	if next == s.size {
+		s.grow = !s.grow
+	}
+
+	s.size = next
+}
+
+func (s *Sizer) clamp(v int) int {
+	if v < s.min {
+		return s.min
+	}
+	if v > s.max {
+		return s.max
+	}
+
+	return v
+}
@@ -0,0 +1,103 @@
+package chunksize
+
+import (
+	"testing"
+	"time"
+)
+
+// link simulates a connection where each send pays a fixed round trip
+// latency plus the time to push the bytes through the available bandwidth.
+type link struct {
+	latency   time.Duration
+	bandwidth float64 // bytes per second
+}
+
+func (l link) send(n int) time.Duration {
+	return l.latency + time.Duration(float64(n)/l.bandwidth*float64(time.Second))
+}
+
+// throughput runs the sizer over the link for the given number of sends
+// and returns the throughput in bytes per second over the final half of
+// the sends, once the sizer has had time to settle.
+func throughput(s *Sizer, l link, sends int) float64 {
+	var total int
+	var elapsed time.Duration
+	for i := 0; i < sends; i++ {
+		n := s.Size()
+		d := l.send(n)
+		s.Observe(n, d)
+
+		if i >= sends/2 {
+			total += n
+			elapsed += d
+		}
+	}
+
+	return float64(total) / elapsed.Seconds()
+}
+
+func TestSizer_bounds(t *testing.T) {
+	s := New(1024, 1, 4096)
+	if s.Size() != 1024 {
+		t.Fatalf("initial size should be clamped to min, got %d", s.Size())
+	}
+
+	for i := 0; i < 100; i++ {
+		s.Observe(s.Size(), time.Millisecond)
+		if v := s.Size(); v < 1024 || v > 4096 {
+			t.Fatalf("size out of bounds: %d", v)
+		}
+	}
+}
+
+func TestSizer_defaults(t *testing.T) {
+	s := New(0, 0, 0)
+	if s.Size() != DefaultInitial {
+		t.Fatalf("bad initial size: %d", s.Size())
+	}
+	if s.Max() != DefaultMax {
+		t.Fatalf("bad max: %d", s.Max())
+	}
+}
+
+func TestSizer_ignoresEmpty(t *testing.T) {
+	s := New(0, 0, 0)
+	s.Observe(0, time.Second)
+	s.Observe(1024, 0)
+	if s.Size() != DefaultInitial {
+		t.Fatalf("size should not change, got %d", s.Size())
+	}
+}
+
+func TestSizer_highLatencyConverges(t *testing.T) {
+	l := link{latency: 100 * time.Millisecond, bandwidth: 10 * 1024 * 1024}
+
+	// The best we can do on this link is always sending the max chunk size.
+	optimal := float64(DefaultMax) / l.send(DefaultMax).Seconds()
+
+	actual := throughput(New(0, 0, 0), l, 200)
+	if actual < optimal*0.8 {
+		t.Fatalf("throughput %.0f B/s is not within 80%% of optimal %.0f B/s",
+			actual, optimal)
+	}
+
+	// Compare against the old fixed 1 KB chunk to make sure we're an
+	// improvement by a wide margin.
+	fixed := float64(1024) / l.send(1024).Seconds()
+	if actual < fixed*100 {
+		t.Fatalf("throughput %.0f B/s is not much better than fixed %.0f B/s",
+			actual, fixed)
+	}
+}
+
+func BenchmarkSizer_highLatency(b *testing.B) {
+	l := link{latency: 150 * time.Millisecond, bandwidth: 5 * 1024 * 1024}
+	optimal := float64(DefaultMax) / l.send(DefaultMax).Seconds()
+
+	var ratio float64
+	for i := 0; i < b.N; i++ {
+		ratio = throughput(New(0, 0, 0), l, 200) / optimal
+	}
+
+	b.ReportMetric(ratio, "of-optimal")
+}
@@ -0,0 +1,65 @@
+package chunksize
+
+import (
+	"time"
+)
+
+// DefaultWindow is the default period over which a Meter measures
+// throughput. This is long enough for the buffers of a typical stream to
+// fill, so that sends are held back by flow control for most of it.
+const DefaultWindow = time.Second
+
+// Meter measures the throughput of a stream of sends over a window of
+// time and reports it to a Sizer.
+//
+// Timing each send of a stream isn't a useful signal since a send returns
+// as soon as the data is buffered locally. Once the buffers are full,
+// sends wait until the peer has received earlier data, so the rate over a
+// window spanning many sends reflects the throughput on the wire.
+//
+// Meter is not safe for concurrent use.
+type Meter struct {
+	sizer  *Sizer
+	window time.Duration
+	now    func() time.Time
+
+	start time.Time
+	bytes int
+}
+
+// NewMeter returns a Meter that reports to the Sizer once per window. The
+// first window starts now. If window is zero or negative, DefaultWindow
+// is used.
+func NewMeter(s *Sizer, window time.Duration) *Meter {
+	if window <= 0 {
+		window = DefaultWindow
+	}
+
+	m := &Meter{sizer: s, window: window, now: time.Now}
+	m.start = m.now()
+	return m
+}
+
+// Size returns the chunk size that should be used for the next send.
+func (m *Meter) Size() int {
+	return m.sizer.Size()
+}
+
+// Sent records that n more bytes were sent. Once the window has passed,
+// the throughput over it is reported to the Sizer and a new window is
+// started. This returns true if the Sizer was updated, in which case Size
+// may have changed.
+func (m *Meter) Sent(n int) bool {
+	m.bytes += n
+
+	now := m.now()
+	elapsed := now.Sub(m.start)
+	if elapsed < m.window {
+		return false
+	}
+
+	m.sizer.Observe(m.bytes, elapsed)
+	m.start = now
+	m.bytes = 0
+	return true
+}
@@ -0,0 +1,126 @@
+package chunksize
+
+import (
+	"testing"
+	"time"
+)
+
+// bufferedLink simulates a stream where a send returns as soon as its data
+// is copied into a local buffer, while the buffer drains over a link with a
+// fixed cost per message plus the time to push the bytes through the
+// bandwidth. Timing each send on such a link mostly measures the copy.
+type bufferedLink struct {
+	overhead  time.Duration
+	bandwidth float64 // bytes per second
+	copyRate  float64 // bytes per second
+	buffer    int
+
+	now      time.Time
+	inflight []queued
+}
+
+type queued struct {
+	n    int
+	done time.Time
+}
+
+func (l *bufferedLink) send(n int) {
+	// Wait for earlier messages to drain until this one fits.
+	for len(l.inflight) > 0 && l.buffered()+n > l.buffer {
+		if l.inflight[0].done.After(l.now) {
+			l.now = l.inflight[0].done
+		}
+		l.inflight = l.inflight[1:]
+	}
+
+	start := l.now
+	if len(l.inflight) > 0 {
+		start = l.inflight[len(l.inflight)-1].done
+	}
+
+	l.inflight = append(l.inflight, queued{
+		n:    n,
+		done: start.Add(l.overhead + time.Duration(float64(n)/l.bandwidth*float64(time.Second))),
+	})
+
+	l.now = l.now.Add(time.Duration(float64(n) / l.copyRate * float64(time.Second)))
+}
+
+func (l *bufferedLink) buffered() int {
+	var total int
+	for _, q := range l.inflight {
+		total += q.n
+	}
+
+	return total
+}
+
+func TestMeter_bufferedLinkConverges(t *testing.T) {
+	l := &bufferedLink{
+		overhead:  10 * time.Millisecond,
+		bandwidth: 10 * 1024 * 1024,
+		copyRate:  1024 * 1024 * 1024,
+		buffer:    64 * 1024,
+		now:       time.Unix(0, 0),
+	}
+
+	m := NewMeter(New(0, 0, 0), 0)
+	m.now = func() time.Time { return l.now }
+	m.start = l.now
+
+	// Run for a simulated minute, measuring over the second half once the
+	// sizer has had time to settle.
+	var total int
+	var from time.Time
+	for l.now.Before(time.Unix(60, 0)) {
+		n := m.Size()
+		l.send(n)
+		m.Sent(n)
+
+		if l.now.After(time.Unix(30, 0)) {
+			if from.IsZero() {
+				from = l.now
+			}
+			total += n
+		}
+	}
+	actual := float64(total) / l.now.Sub(from).Seconds()
+
+	// The best we can do on this link is always sending the max chunk size.
+	optimal := float64(DefaultMax) /
+		(l.overhead.Seconds() + float64(DefaultMax)/l.bandwidth)
+	if actual < optimal*0.8 {
+		t.Fatalf("throughput %.0f B/s is not within 80%% of optimal %.0f B/s",
+			actual, optimal)
+	}
+}
+
+func TestMeter_window(t *testing.T) {
+	now := time.Unix(0, 0)
+	m := NewMeter(New(0, 0, 0), time.Second)
+	m.now = func() time.Time { return now }
+	m.start = now
+
+	// Sends within the window don't update the sizer.
+	now = now.Add(500 * time.Millisecond)
+	if m.Sent(1024) {
+		t.Fatal("sizer should not be updated within the window")
+	}
+	if m.Size() != DefaultInitial {
+		t.Fatalf("size should not change, got %d", m.Size())
+	}
+
+	// The first send after the window updates it.
+	now = now.Add(500 * time.Millisecond)
+	if !m.Sent(1024) {
+		t.Fatal("sizer should be updated after the window")
+	}
+	if m.Size() != DefaultInitial*2 {
+		t.Fatalf("size should grow, got %d", m.Size())
+	}
+
+	// A new window started, so the next send doesn't update it.
+	if m.Sent(1024) {
+		t.Fatal("sizer should not be updated at the start of a new window")
+	}
+}
@@ -0,0 +1,279 @@
+// Package filewatch watches a directory tree for changes by polling it.
+//
+// Polling is used rather than filesystem notifications since it behaves the
+// same on every platform and filesystem, including network filesystems and
+// mounts into VMs and containers where notifications are often missed. The
+// trees we watch are source trees, which are small enough to scan often.
+package filewatch
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultInterval is how often the tree is scanned by default.
+	DefaultInterval = 500 * time.Millisecond
+
+	// DefaultDebounce is how long the tree must be unchanged by default
+	// before Wait returns. This lets editors and tools finish writing all
+	// the files of a change before we act on it.
+	DefaultDebounce = time.Second
+)
+
+// Op is the type of change to a file.
+type Op uint8
+
+const (
+	Create Op = iota + 1
+	Write
+	Remove
+)
+
+func (o Op) String() string {
+	switch o {
+	case Create:
+		return "create"
+	case Write:
+		return "write"
+	case Remove:
+		return "remove"
+	default:
+		return "unknown"
+	}
+}
+
+// Change is a change to a single file.
+type Change struct {
+	// Path is the path of the file relative to the root, using forward
+	// slashes on every platform.
+	Path string
+	Op   Op
+}
+
+// IgnoreFunc returns true if the file or directory at the slash separated
+// path relative to the root should not be watched. If a directory is
+// ignored, nothing within it is watched.
+type IgnoreFunc func(rel string, dir bool) bool
+
+// Option configures a Watcher.
+type Option func(*Watcher)
+
+// WithInterval sets how often the tree is scanned.
+func WithInterval(d time.Duration) Option {
+	return func(w *Watcher) { w.interval = d }
+}
+
+// WithDebounce sets how long the tree must be unchanged before Wait
+// returns the changes.
+func WithDebounce(d time.Duration) Option {
+	return func(w *Watcher) { w.debounce = d }
+}
+
+// WithIgnore sets the function that decides which files aren't watched.
+// By default, hidden files and directories are ignored.
+func WithIgnore(f IgnoreFunc) Option {
+	return func(w *Watcher) { w.ignore = f }
+}
+
+// Watcher watches a directory tree for changes. Watcher is not safe for
+// concurrent use.
+type Watcher struct {
+	root     string
+	interval time.Duration
+	debounce time.Duration
+	ignore   IgnoreFunc
+
+	// files is the state of the tree when the changes were last returned.
+	files map[string]fileState
+}
+
+// fileState is the state of a file that we compare to detect changes.
+type fileState struct {
+	size    int64
+	modTime int64
+	mode    os.FileMode
+}
+
+// New returns a Watcher for the tree at root. The tree is scanned before
+// this returns so that Wait only reports changes made after New.
+func New(root string, opts ...Option) (*Watcher, error) {
+	w := &Watcher{
+		root:     root,
+		interval: DefaultInterval,
+		debounce: DefaultDebounce,
+		ignore:   IgnoreHidden,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	files, err := w.scan()
+	if err != nil {
+		return nil, err
+	}
+	w.files = files
+
+	return w, nil
+}
+
+// Wait blocks until files in the tree change and the tree is then unchanged
+// for the debounce period. It returns the changes since New or the last
+// call to Wait, sorted by path. Files that change and then change back,
+// such as temporary files that are removed, aren't reported.
+func (w *Watcher) Wait(ctx context.Context) ([]Change, error) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	// latest is the state of the tree at the last scan and changed is when
+	// we last saw it change. While changed is zero, the latest scan is the
+	// same as the state we last returned.
+	latest := w.files
+	var changed time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+
+		files, err := w.scan()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(diff(latest, files)) > 0 {
+			latest = files
+			changed = time.Now()
+			continue
+		}
+
+		if changed.IsZero() || time.Since(changed) < w.debounce {
+			continue
+		}
+
+		// The tree has settled.
+		changes := diff(w.files, latest)
+		w.files = latest
+		changed = time.Time{}
+		if len(changes) > 0 {
+			return changes, nil
+		}
+	}
+}
+
+// scan returns the state of every watched file in the tree.
+func (w *Watcher) scan() (map[string]fileState, error) {
+	result := map[string]fileState{}
+	err := filepath.Walk(w.root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			// Files may be removed while we walk, which isn't an error.
+			if os.IsNotExist(err) {
+				return nil
+			}
+
+			return err
+		}
+
+		rel, err := filepath.Rel(w.root, p)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		if w.ignore != nil && w.ignore(rel, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		result[rel] = fileState{
+			size:    info.Size(),
+			modTime: info.ModTime().UnixNano(),
+			mode:    info.Mode(),
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// diff returns the changes from the files in old to the files in cur,
+// sorted by path.
+func diff(old, cur map[string]fileState) []Change {
+	var result []Change
+	for rel, state := range cur {
+		prev, ok := old[rel]
+		switch {
+		case !ok:
+			result = append(result, Change{Path: rel, Op: Create})
+		case prev != state:
+			result = append(result, Change{Path: rel, Op: Write})
+		}
+	}
+
+	for rel := range old {
+		if _, ok := cur[rel]; !ok {
+			result = append(result, Change{Path: rel, Op: Remove})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Path < result[j].Path
+	})
+
+	return result
+}
+
+// IgnoreHidden ignores files and directories with names that start with a
+// dot, such as ".git".
+func IgnoreHidden(rel string, dir bool) bool {
+	return strings.HasPrefix(path.Base(rel), ".")
+}
+
+// IgnorePatterns returns an IgnoreFunc that ignores hidden files and
+// directories along with those that match any of the patterns. A pattern
+// that contains a slash is matched against the path relative to the root,
+// otherwise it is matched against the name of the file or directory. The
+// patterns use the syntax of path.Match.
+func IgnorePatterns(patterns ...string) (IgnoreFunc, error) {
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %s", p, err)
+		}
+	}
+
+	return func(rel string, dir bool) bool {
+		if IgnoreHidden(rel, dir) {
+			return true
+		}
+
+		for _, p := range patterns {
+			target := path.Base(rel)
+			if strings.Contains(p, "/") {
+				target = rel
+			}
+
+			if ok, _ := path.Match(p, target); ok {
+				return true
+			}
+		}
+
+		return false
+	}, nil
+}
@@ -0,0 +1,164 @@
+package filewatch
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testWatcher(t *testing.T, root string, opts ...Option) *Watcher {
+	t.Helper()
+
+	opts = append([]Option{
+		WithInterval(10 * time.Millisecond),
+		WithDebounce(50 * time.Millisecond),
+	}, opts...)
+
+	w, err := New(root, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return w
+}
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func wait(t *testing.T, w *Watcher) []Change {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	changes, err := w.Wait(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return changes
+}
+
+func TestWatcher(t *testing.T) {
+	root, err := ioutil.TempDir("", "filewatch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	writeFile(t, filepath.Join(root, "a.txt"), "a")
+	writeFile(t, filepath.Join(root, "b.txt"), "b")
+	w := testWatcher(t, root)
+
+	// Create, write, and remove files.
+	writeFile(t, filepath.Join(root, "a.txt"), "changed")
+	writeFile(t, filepath.Join(root, "dir", "c.txt"), "c")
+	if err := os.Remove(filepath.Join(root, "b.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Change{
+		{Path: "a.txt", Op: Write},
+		{Path: "b.txt", Op: Remove},
+		{Path: "dir/c.txt", Op: Create},
+	}
+	if actual := wait(t, w); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	// The next wait only has the new changes.
+	writeFile(t, filepath.Join(root, "dir", "c.txt"), "changed")
+	expected = []Change{
+		{Path: "dir/c.txt", Op: Write},
+	}
+	if actual := wait(t, w); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestWatcher_temporary(t *testing.T) {
+	root, err := ioutil.TempDir("", "filewatch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	w := testWatcher(t, root)
+
+	// A file that is created and removed before the tree settles isn't
+	// reported, so we only see the second file.
+	tmp := filepath.Join(root, "tmp.txt")
+	writeFile(t, tmp, "tmp")
+	time.Sleep(20 * time.Millisecond)
+	if err := os.Remove(tmp); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(root, "a.txt"), "a")
+
+	expected := []Change{
+		{Path: "a.txt", Op: Create},
+	}
+	if actual := wait(t, w); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestWatcher_canceled(t *testing.T) {
+	root, err := ioutil.TempDir("", "filewatch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	w := testWatcher(t, root)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := w.Wait(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("bad: %s", err)
+	}
+}
+
+func TestIgnorePatterns(t *testing.T) {
+	ignore, err := IgnorePatterns("*.log", "build/out")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		Path     string
+		Expected bool
+	}{
+		{"main.go", false},
+		{".git", true},
+		{"src/.hidden", true},
+		{"server.log", true},
+		{"logs/server.log", true},
+		{"build", false},
+		{"build/out", true},
+		{"src/build/out", false},
+	}
+
+	for _, tc := range cases {
+		if actual := ignore(tc.Path, false); actual != tc.Expected {
+			t.Errorf("%s: expected %v, got %v", tc.Path, tc.Expected, actual)
+		}
+	}
+
+	if _, err := IgnorePatterns("["); err == nil {
+		t.Fatal("expected error for invalid pattern")
+	}
+}
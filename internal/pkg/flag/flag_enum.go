@@ -38,6 +38,12 @@ func (f *Set) EnumVar(i *EnumVar) {
 
 	possible := strings.Join(i.Values, ", ")
 
+	// Complete the possible values unless told otherwise.
+	completion := i.Completion
+	if completion == nil {
+		completion = complete.PredictSet(i.Values...)
+	}
+
 	f.VarFlag(&VarFlag{
 		Name:       i.Name,
 		Aliases:    i.Aliases,
@@ -45,7 +51,7 @@ func (f *Set) EnumVar(i *EnumVar) {
 		Default:    def,
 		EnvVar:     i.EnvVar,
 		Value:      newEnumValue(i, initial, i.Target, i.Hidden),
-		Completion: i.Completion,
+		Completion: completion,
 	})
 }
 
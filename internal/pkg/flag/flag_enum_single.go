@@ -32,6 +32,12 @@ func (f *Set) EnumSingleVar(i *EnumSingleVar) {
 
 	possible := strings.Join(i.Values, ", ")
 
+	// Complete the possible values unless told otherwise.
+	completion := i.Completion
+	if completion == nil {
+		completion = complete.PredictSet(i.Values...)
+	}
+
 	f.VarFlag(&VarFlag{
 		Name:       i.Name,
 		Aliases:    i.Aliases,
@@ -39,7 +45,7 @@ func (f *Set) EnumSingleVar(i *EnumSingleVar) {
 		Default:    def,
 		EnvVar:     i.EnvVar,
 		Value:      newEnumSingleValue(i, initial, i.Target, i.Hidden),
-		Completion: i.Completion,
+		Completion: completion,
 	})
 }
 
@@ -3,6 +3,7 @@ package flag
 import (
 	"testing"
 
+	"github.com/posener/complete"
 	"github.com/stretchr/testify/require"
 )
 
@@ -33,3 +34,28 @@ func TestSets(t *testing.T) {
 	require.Equal(int(21), valA)
 	require.Equal(int(42), valB)
 }
+
+func TestSets_enumCompletion(t *testing.T) {
+	require := require.New(t)
+
+	var one string
+	var many []string
+	sets := NewSets()
+	{
+		set := sets.NewSet("A")
+		set.EnumSingleVar(&EnumSingleVar{
+			Name:   "one",
+			Target: &one,
+			Values: []string{"a", "b"},
+		})
+		set.EnumVar(&EnumVar{
+			Name:   "many",
+			Target: &many,
+			Values: []string{"c", "d"},
+		})
+	}
+
+	comps := sets.Completions()
+	require.ElementsMatch([]string{"a", "b"}, comps["-one"].Predict(complete.Args{}))
+	require.ElementsMatch([]string{"c", "d"}, comps["-many"].Predict(complete.Args{}))
+}
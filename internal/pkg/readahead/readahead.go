@@ -0,0 +1,154 @@
+// Package readahead reads data in chunks ahead of when it is needed so that
+// reading the next chunk overlaps with processing the current one, such as
+// sending it over a slow connection.
+package readahead
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// Reader reads chunks from an underlying reader in the background. At most
+// window chunks are read ahead of the caller, which bounds the memory used
+// to window+1 buffers of the max chunk size and ensures a slow consumer
+// applies back pressure to the underlying reader.
+//
+// Next and Close must not be called concurrently. SetSize is safe to call
+// at any time.
+type Reader struct {
+	size int64
+
+	chunks chan chunk
+	free   chan []byte
+	done   chan struct{}
+	once   sync.Once
+
+	// last is the chunk most recently returned by Next, which is reused
+	// once the caller asks for the next chunk.
+	last []byte
+	err  error
+}
+
+type chunk struct {
+	data []byte
+	err  error
+}
+
+// New returns a Reader that reads chunks of size bytes from r. The size may
+// be changed later with SetSize but never exceeds max. The window is the
+// number of chunks to read ahead and is at least one.
+func New(r io.Reader, size, max, window int) *Reader {
+	if window < 1 {
+		window = 1
+	}
+	if size > max {
+		size = max
+	}
+
+	result := &Reader{
+		size:   int64(size),
+		chunks: make(chan chunk, window),
+		free:   make(chan []byte, window+1),
+		done:   make(chan struct{}),
+	}
+	for i := 0; i < window+1; i++ {
+		result.free <- make([]byte, max)
+	}
+
+	go result.run(r)
+	return result
+}
+
+// SetSize sets the size of the chunks read from now on. Chunks that were
+// already read ahead keep their size. The size is limited to the max given
+// to New.
+func (r *Reader) SetSize(n int) {
+	atomic.StoreInt64(&r.size, int64(n))
+}
+
+// Next returns the next chunk of data. The chunk is only valid until the
+// next call to Next or Close. Each chunk is the current size except for the
+// last chunk which may be shorter. Once all data is read, this returns
+// io.EOF.
+func (r *Reader) Next() ([]byte, error) {
+	if r.last != nil {
+		r.free <- r.last[:cap(r.last)]
+		r.last = nil
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	c, ok := <-r.chunks
+	if !ok {
+		r.err = io.EOF
+		return nil, r.err
+	}
+	if c.err != nil {
+		r.err = c.err
+		return nil, r.err
+	}
+
+	r.last = c.data
+	return c.data, nil
+}
+
+// Close stops reading ahead. This doesn't close the underlying reader, but
+// once Close returns nothing more is read from it.
+func (r *Reader) Close() error {
+	r.once.Do(func() { close(r.done) })
+
+	// Wait for the background reader to finish. It closes chunks once it
+	// stops so drain them until then.
+	for range r.chunks {
+	}
+
+	return nil
+}
+
+func (r *Reader) run(src io.Reader) {
+	defer close(r.chunks)
+
+	for {
+		var buf []byte
+		select {
+		case buf = <-r.free:
+		case <-r.done:
+			return
+		}
+
+		size := int(atomic.LoadInt64(&r.size))
+		if size <= 0 || size > len(buf) {
+			size = len(buf)
+		}
+
+		// Use ReadFull so that readers such as OS pipes that return a few
+		// bytes per call still produce full chunks.
+		n, err := io.ReadFull(src, buf[:size])
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			// A short read is the end of the data.
+			err = nil
+			size = -1
+		}
+
+		var c chunk
+		switch {
+		case err != nil:
+			c.err = err
+		case n > 0:
+			c.data = buf[:n]
+		}
+		if c.err != nil || c.data != nil {
+			select {
+			case r.chunks <- c:
+			case <-r.done:
+				return
+			}
+		}
+
+		if err != nil || size < 0 {
+			return
+		}
+	}
+}
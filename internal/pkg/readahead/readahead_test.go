@@ -0,0 +1,193 @@
+package readahead
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestReader(t *testing.T) {
+	data := make([]byte, 10*1024+7)
+	rand.Read(data)
+
+	for _, window := range []int{0, 1, 4} {
+		r := New(bytes.NewReader(data), 1024, 1024, window)
+
+		var out bytes.Buffer
+		var chunks int
+		for {
+			chunk, err := r.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("window %d: err: %s", window, err)
+			}
+
+			out.Write(chunk)
+			chunks++
+		}
+		r.Close()
+
+		if !bytes.Equal(out.Bytes(), data) {
+			t.Fatalf("window %d: data doesn't match", window)
+		}
+		if chunks != 11 {
+			t.Fatalf("window %d: expected 11 chunks, got %d", window, chunks)
+		}
+	}
+}
+
+func TestReader_setSize(t *testing.T) {
+	data := make([]byte, 64*1024)
+	r := New(bytes.NewReader(data), 1024, 4096, 1)
+	defer r.Close()
+
+	chunk, err := r.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chunk) != 1024 {
+		t.Fatalf("bad initial chunk size: %d", len(chunk))
+	}
+
+	// Chunks already read ahead keep their size, so eventually we should
+	// see the new size, and it must be capped at the max.
+	r.SetSize(1 << 20)
+	for i := 0; i < 4; i++ {
+		chunk, err = r.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(chunk) != 4096 {
+		t.Fatalf("chunk size should be capped at the max, got %d", len(chunk))
+	}
+}
+
+func TestReader_error(t *testing.T) {
+	expected := errors.New("boom")
+	src := io.MultiReader(bytes.NewReader(make([]byte, 100)), errReader{expected})
+
+	r := New(src, 10, 10, 2)
+	defer r.Close()
+
+	var total int
+	for {
+		chunk, err := r.Next()
+		if err != nil {
+			if err != expected {
+				t.Fatalf("bad error: %v", err)
+			}
+			break
+		}
+
+		total += len(chunk)
+	}
+	if total != 100 {
+		t.Fatalf("expected the data before the error, got %d bytes", total)
+	}
+
+	// The error is sticky
+	if _, err := r.Next(); err != expected {
+		t.Fatalf("bad error: %v", err)
+	}
+}
+
+func TestReader_closeEarly(t *testing.T) {
+	src := &countReader{}
+	r := New(src, 10, 10, 2)
+	if _, err := r.Next(); err != nil {
+		t.Fatal(err)
+	}
+	r.Close()
+
+	// Nothing more should be read once Close returns.
+	n := src.n
+	time.Sleep(10 * time.Millisecond)
+	if src.n != n {
+		t.Fatal("data was read after close")
+	}
+}
+
+// BenchmarkReader compares reading and sending each chunk in turn with
+// reading ahead while sending when both the reader and the sender are slow,
+// which is the case when restoring a snapshot from object storage over a
+// high latency connection.
+func BenchmarkReader(b *testing.B) {
+	const (
+		chunks = 20
+		delay  = time.Millisecond
+	)
+
+	send := func([]byte) { time.Sleep(delay) }
+
+	b.Run("sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			src := &slowReader{remaining: chunks * 1024, delay: delay}
+			buf := make([]byte, 1024)
+			for {
+				n, _ := io.ReadFull(src, buf)
+				if n == 0 {
+					break
+				}
+
+				send(buf[:n])
+			}
+		}
+	})
+
+	for _, window := range []int{1, 4} {
+		window := window
+		b.Run(fmt.Sprintf("window-%d", window), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				src := &slowReader{remaining: chunks * 1024, delay: delay}
+				r := New(src, 1024, 1024, window)
+				for {
+					chunk, err := r.Next()
+					if err != nil {
+						break
+					}
+
+					send(chunk)
+				}
+				r.Close()
+			}
+		})
+	}
+}
+
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
+
+// countReader returns endless data, counting the reads made.
+type countReader struct{ n int }
+
+func (r *countReader) Read(p []byte) (int, error) {
+	r.n++
+	return len(p), nil
+}
+
+// slowReader returns remaining bytes, sleeping for delay on each read.
+type slowReader struct {
+	remaining int
+	delay     time.Duration
+}
+
+func (r *slowReader) Read(p []byte) (int, error) {
+	if r.remaining == 0 {
+		return 0, io.EOF
+	}
+
+	time.Sleep(r.delay)
+	if len(p) > r.remaining {
+		p = p[:r.remaining]
+	}
+	r.remaining -= len(p)
+	return len(p), nil
+}
@@ -0,0 +1,318 @@
+// Package snapshotcrypt encrypts and decrypts server snapshot streams
+// with AES-256-GCM so that snapshots can be stored on untrusted storage.
+//
+// The stream is split into segments that are sealed individually so
+// that it can be encrypted and decrypted without buffering the whole
+// snapshot. The format is:
+//
+//	magic (4 bytes) | nonce prefix (8 bytes) | segment...
+//
+// Each segment is a 4-byte big-endian ciphertext length followed by the
+// ciphertext. The nonce of a segment is the nonce prefix followed by the
+// 4-byte big-endian segment index. The additional data of a segment is a
+// single byte that is 1 for the last segment and 0 otherwise, so that a
+// truncated stream can't be decrypted successfully.
+package snapshotcrypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+const (
+	// KeySize is the size of an encryption key in bytes.
+	KeySize = 32
+
+	// segmentSize is the maximum plaintext size of a single segment.
+	segmentSize = 64 * 1024
+
+	prefixSize = 8
+)
+
+// magic is the start of every encrypted stream.
+var magic = []byte("WPE1")
+
+var (
+	// ErrNotEncrypted is returned by NewReader if the data isn't encrypted.
+	ErrNotEncrypted = errors.New("snapshot data is not encrypted")
+
+	// ErrDecrypt is returned when the data can't be decrypted, either
+	// because the key is wrong or the data was modified or truncated.
+	ErrDecrypt = errors.New("snapshot data could not be decrypted, the key may be wrong or the data corrupted")
+)
+
+// ParseKey parses a base64-encoded key. Surrounding whitespace is ignored
+// so the key can be read directly from a file.
+func ParseKey(s string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("key must be base64 encoded: %s", err)
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", KeySize, len(key))
+	}
+
+	return key, nil
+}
+
+// ReadKeyFile reads a base64-encoded key from the file at path.
+func ReadKeyFile(path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseKey(string(data))
+}
+
+// IsEncrypted reports whether data starts like an encrypted stream.
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, magic)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", KeySize, len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func segmentNonce(prefix []byte, idx uint32) []byte {
+	nonce := make([]byte, prefixSize+4)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[prefixSize:], idx)
+	return nonce
+}
+
+func segmentAD(final bool) []byte {
+	if final {
+		return []byte{1}
+	}
+
+	return []byte{0}
+}
+
+// Writer encrypts the data written to it. Close must be called to write
+// the final segment; the stream can't be decrypted without it.
+type Writer struct {
+	w      io.Writer
+	aead   cipher.AEAD
+	prefix []byte
+	idx    uint32
+	buf    []byte
+	err    error
+}
+
+// NewWriter returns a Writer that writes the data encrypted with key to w.
+func NewWriter(w io.Writer, key []byte) (*Writer, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := make([]byte, prefixSize)
+	if _, err := io.ReadFull(rand.Reader, prefix); err != nil {
+		return nil, err
+	}
+
+	if _, err := w.Write(append(append([]byte{}, magic...), prefix...)); err != nil {
+		return nil, err
+	}
+
+	return &Writer{
+		w:      w,
+		aead:   aead,
+		prefix: prefix,
+		buf:    make([]byte, 0, segmentSize),
+	}, nil
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	n := 0
+	for len(p) > 0 {
+		// We only seal a full buffer once more data arrives, since the
+		// last segment must be sealed as final by Close.
+		if len(w.buf) == segmentSize {
+			if err := w.seal(false); err != nil {
+				return n, err
+			}
+		}
+
+		c := copy(w.buf[len(w.buf):segmentSize], p)
+		w.buf = w.buf[:len(w.buf)+c]
+		p = p[c:]
+		n += c
+	}
+
+	return n, nil
+}
+
+// Close writes the final segment. This does not close the underlying writer.
+func (w *Writer) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+
+	if err := w.seal(true); err != nil {
+		return err
+	}
+
+	w.err = errors.New("snapshotcrypt: write to closed writer")
+	return nil
+}
+
+func (w *Writer) seal(final bool) error {
+	ct := w.aead.Seal(nil, segmentNonce(w.prefix, w.idx), w.buf, segmentAD(final))
+
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(ct)))
+	if _, err := w.w.Write(length[:]); err != nil {
+		w.err = err
+		return err
+	}
+	if _, err := w.w.Write(ct); err != nil {
+		w.err = err
+		return err
+	}
+
+	w.idx++
+	w.buf = w.buf[:0]
+	return nil
+}
+
+// Reader decrypts data written by a Writer.
+type Reader struct {
+	r      io.Reader
+	aead   cipher.AEAD
+	prefix []byte
+	idx    uint32
+	buf    []byte
+	done   bool
+	next   []byte
+}
+
+// NewReader returns a Reader that decrypts the data in r with key. This
+// returns ErrNotEncrypted if r doesn't contain encrypted data.
+func NewReader(r io.Reader, key []byte) (*Reader, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, len(magic)+prefixSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, ErrNotEncrypted
+		}
+
+		return nil, err
+	}
+	if !IsEncrypted(header) {
+		return nil, ErrNotEncrypted
+	}
+
+	return &Reader{
+		r:      r,
+		aead:   aead,
+		prefix: header[len(magic):],
+	}, nil
+}
+
+func (r *Reader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// open reads and decrypts the next segment. We need to read one segment
+// ahead to know whether the current segment is the last one.
+func (r *Reader) open() error {
+	if r.next == nil {
+		ct, err := r.readSegment()
+		if err != nil {
+			if err == io.EOF {
+				return ErrDecrypt
+			}
+
+			return err
+		}
+
+		r.next = ct
+	}
+
+	ct := r.next
+	next, err := r.readSegment()
+	final := err == io.EOF
+	if err != nil && !final {
+		return err
+	}
+	r.next = next
+
+	pt, err := r.aead.Open(nil, segmentNonce(r.prefix, r.idx), ct, segmentAD(final))
+	if err != nil {
+		return ErrDecrypt
+	}
+
+	r.idx++
+	r.buf = pt
+	r.done = final
+	return nil
+}
+
+// readSegment reads the next segment ciphertext. This returns io.EOF
+// only if there are no more segments.
+func (r *Reader) readSegment() ([]byte, error) {
+	var length [4]byte
+	if _, err := io.ReadFull(r.r, length[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, ErrDecrypt
+		}
+
+		return nil, err
+	}
+
+	n := binary.BigEndian.Uint32(length[:])
+	if n > segmentSize+uint32(r.aead.Overhead()) {
+		return nil, ErrDecrypt
+	}
+
+	ct := make([]byte, n)
+	if _, err := io.ReadFull(r.r, ct); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, ErrDecrypt
+		}
+
+		return nil, err
+	}
+
+	return ct, nil
+}
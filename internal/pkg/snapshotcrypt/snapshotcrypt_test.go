@@ -0,0 +1,137 @@
+package snapshotcrypt
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+func testKey(t *testing.T) []byte {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+func encrypt(t *testing.T, key, data []byte) []byte {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func decrypt(key, data []byte) ([]byte, error) {
+	r, err := NewReader(bytes.NewReader(data), key)
+	if err != nil {
+		return nil, err
+	}
+
+	return ioutil.ReadAll(r)
+}
+
+func TestRoundTrip(t *testing.T) {
+	key := testKey(t)
+
+	for _, size := range []int{0, 1, segmentSize - 1, segmentSize, segmentSize + 1, 3*segmentSize + 17} {
+		data := make([]byte, size)
+		if _, err := rand.Read(data); err != nil {
+			t.Fatal(err)
+		}
+
+		// Short random plaintext can appear in the ciphertext by chance,
+		// so only check that longer plaintext doesn't leak.
+		ct := encrypt(t, key, data)
+		if size >= 16 && bytes.Contains(ct, data) {
+			t.Fatalf("size %d: ciphertext contains plaintext", size)
+		}
+
+		pt, err := decrypt(key, ct)
+		if err != nil {
+			t.Fatalf("size %d: %s", size, err)
+		}
+		if !bytes.Equal(pt, data) {
+			t.Fatalf("size %d: data mismatch", size)
+		}
+	}
+}
+
+func TestReader_wrongKey(t *testing.T) {
+	ct := encrypt(t, testKey(t), []byte("hello"))
+	if _, err := decrypt(testKey(t), ct); err != ErrDecrypt {
+		t.Fatalf("expected ErrDecrypt, got %v", err)
+	}
+}
+
+func TestReader_truncated(t *testing.T) {
+	key := testKey(t)
+	data := make([]byte, 2*segmentSize+5)
+	ct := encrypt(t, key, data)
+
+	// Drop the final segment entirely, which leaves a valid stream of
+	// segments that isn't marked as complete.
+	finalLen := 4 + 5 + 16 // length, plaintext, GCM tag
+	if _, err := decrypt(key, ct[:len(ct)-finalLen]); err != ErrDecrypt {
+		t.Fatalf("expected ErrDecrypt, got %v", err)
+	}
+
+	// Cut in the middle of a segment.
+	if _, err := decrypt(key, ct[:len(ct)-3]); err != ErrDecrypt {
+		t.Fatalf("expected ErrDecrypt, got %v", err)
+	}
+}
+
+func TestReader_notEncrypted(t *testing.T) {
+	key := testKey(t)
+	for _, data := range [][]byte{nil, []byte("hi"), []byte("not an encrypted snapshot")} {
+		if _, err := NewReader(bytes.NewReader(data), key); err != ErrNotEncrypted {
+			t.Fatalf("expected ErrNotEncrypted, got %v", err)
+		}
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	key := testKey(t)
+	got, err := ParseKey(" " + base64.StdEncoding.EncodeToString(key) + "\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, key) {
+		t.Fatal("key mismatch")
+	}
+
+	if _, err := ParseKey("not base64!"); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, err := ParseKey(base64.StdEncoding.EncodeToString([]byte("short"))); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestWriter_closed(t *testing.T) {
+	w, err := NewWriter(ioutil.Discard, testKey(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("x")); err == nil {
+		t.Fatal("expected error")
+	}
+
+	var _ io.WriteCloser = w
+}
@@ -0,0 +1,73 @@
+// Package snapshotsum computes the checksums embedded in each chunk of a
+// server snapshot.
+//
+// The whole-snapshot checksum in the trailer detects corruption only once
+// all of the data has been read. Chunk checksums let a reader reject a
+// corrupted chunk as soon as it is read, and identify which chunk is bad.
+// The checksum covers the decoded contents of the chunk rather than its
+// encoded bytes, since protobuf map encoding order is not deterministic.
+package snapshotsum
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"sort"
+	"strings"
+)
+
+// Chunk returns the hex-encoded SHA-256 checksum of a chunk with the given
+// bucket, items and deleted keys. The order of deleted does not matter.
+func Chunk(bucket string, items map[string][]byte, deleted []string) string {
+	h := sha256.New()
+	writeBytes(h, []byte(bucket))
+
+	keys := make([]string, 0, len(items))
+	for k := range items {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	writeLen(h, len(keys))
+	for _, k := range keys {
+		writeBytes(h, []byte(k))
+		writeBytes(h, items[k])
+	}
+
+	deleted = append([]string(nil), deleted...)
+	sort.Strings(deleted)
+	writeLen(h, len(deleted))
+	for _, k := range deleted {
+		writeBytes(h, []byte(k))
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Verify checks that sum is the checksum of the given chunk contents. An
+// empty sum is accepted since snapshots written by older versions don't
+// have chunk checksums; those are only covered by the trailer checksum.
+func Verify(sum string, bucket string, items map[string][]byte, deleted []string) error {
+	if sum == "" {
+		return nil
+	}
+
+	if actual := Chunk(bucket, items, deleted); !strings.EqualFold(sum, actual) {
+		return fmt.Errorf("chunk checksum mismatch for %q, expected %s got %s",
+			bucket, sum, actual)
+	}
+
+	return nil
+}
+
+func writeLen(h hash.Hash, n int) {
+	var buf [binary.MaxVarintLen64]byte
+	h.Write(buf[:binary.PutUvarint(buf[:], uint64(n))])
+}
+
+func writeBytes(h hash.Hash, b []byte) {
+	writeLen(h, len(b))
+	h.Write(b)
+}
@@ -0,0 +1,51 @@
+package snapshotsum
+
+import (
+	"testing"
+)
+
+func TestChunk(t *testing.T) {
+	items := map[string][]byte{"a": []byte("1"), "b": []byte("2")}
+	sum := Chunk("bucket", items, []string{"x", "y"})
+
+	cases := []struct {
+		Name    string
+		Bucket  string
+		Items   map[string][]byte
+		Deleted []string
+		Equal   bool
+	}{
+		{"same", "bucket", map[string][]byte{"b": []byte("2"), "a": []byte("1")}, []string{"y", "x"}, true},
+		{"bucket", "other", items, []string{"x", "y"}, false},
+		{"value", "bucket", map[string][]byte{"a": []byte("1"), "b": []byte("3")}, []string{"x", "y"}, false},
+		{"missing item", "bucket", map[string][]byte{"a": []byte("1")}, []string{"x", "y"}, false},
+		{"deleted", "bucket", items, []string{"x"}, false},
+
+		// Key and value boundaries are part of the checksum.
+		{"boundary", "bucket", map[string][]byte{"a1": []byte(""), "b": []byte("2")}, []string{"x", "y"}, false},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			actual := Chunk(tt.Bucket, tt.Items, tt.Deleted)
+			if (actual == sum) != tt.Equal {
+				t.Fatalf("expected equal=%v, got %s and %s", tt.Equal, actual, sum)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	items := map[string][]byte{"a": []byte("1")}
+	sum := Chunk("bucket", items, nil)
+
+	if err := Verify(sum, "bucket", items, nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := Verify("", "bucket", items, nil); err != nil {
+		t.Fatalf("empty checksum should be accepted: %s", err)
+	}
+	if err := Verify(sum, "bucket", map[string][]byte{"a": []byte("2")}, nil); err == nil {
+		t.Fatal("expected error")
+	}
+}
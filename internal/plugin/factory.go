@@ -1,6 +1,7 @@
 package plugin
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
 	"runtime"
@@ -50,6 +51,18 @@ func Factory(cmd *exec.Cmd, typ component.Type) interface{} {
 		if err != nil {
 			log.Error("error creating plugin client", "err", err)
 			client.Kill()
+
+			// go-plugin negotiates the protocol version with the versions
+			// the SDK supports. If there is no version in common, explain
+			// that rather than returning the raw handshake error.
+			if strings.Contains(err.Error(), "Incompatible API version") {
+				return nil, fmt.Errorf(
+					"plugin %q was built with a version of the Waypoint plugin SDK "+
+						"that this version of Waypoint doesn't support. Please use a "+
+						"version of the plugin built for this version of Waypoint. "+
+						"Error: %w", cmd.Path, err)
+			}
+
 			return nil, err
 		}
 
@@ -73,7 +86,8 @@ func Factory(cmd *exec.Cmd, typ component.Type) interface{} {
 			return nil, err
 		}
 
-		log.Debug("plugin successfully launched and connected")
+		log.Debug("plugin successfully launched and connected",
+			"protocol_version", client.NegotiatedVersion())
 		return &Instance{
 			Component: raw,
 			Mappers:   mappers,
@@ -10,14 +10,19 @@ import (
 	"github.com/hashicorp/waypoint/builtin/aws/ec2"
 	"github.com/hashicorp/waypoint/builtin/aws/ecr"
 	"github.com/hashicorp/waypoint/builtin/aws/ecs"
+	"github.com/hashicorp/waypoint/builtin/aws/lambda"
+	"github.com/hashicorp/waypoint/builtin/aws/secretsmanager"
 	"github.com/hashicorp/waypoint/builtin/aws/ssm"
 	"github.com/hashicorp/waypoint/builtin/azure/aci"
 	"github.com/hashicorp/waypoint/builtin/docker"
+	dockercompose "github.com/hashicorp/waypoint/builtin/docker/compose"
 	dockerpull "github.com/hashicorp/waypoint/builtin/docker/pull"
 	"github.com/hashicorp/waypoint/builtin/exec"
 	"github.com/hashicorp/waypoint/builtin/files"
 	"github.com/hashicorp/waypoint/builtin/google/cloudrun"
+	"github.com/hashicorp/waypoint/builtin/helm"
 	"github.com/hashicorp/waypoint/builtin/k8s"
+	"github.com/hashicorp/waypoint/builtin/k8s/kaniko"
 	"github.com/hashicorp/waypoint/builtin/netlify"
 	"github.com/hashicorp/waypoint/builtin/nomad"
 	"github.com/hashicorp/waypoint/builtin/pack"
@@ -32,17 +37,22 @@ var (
 		"pack":                     pack.Options,
 		"docker":                   docker.Options,
 		"docker-pull":              dockerpull.Options,
+		"docker-compose":           dockercompose.Options,
 		"exec":                     exec.Options,
 		"google-cloud-run":         cloudrun.Options,
 		"azure-container-instance": aci.Options,
 		"kubernetes":               k8s.Options,
+		"helm":                     helm.Options,
+		"kaniko":                   kaniko.Options,
 		"netlify":                  netlify.Options,
 		"aws-ecs":                  ecs.Options,
 		"aws-ecr":                  ecr.Options,
+		"aws-lambda":               lambda.Options,
 		"nomad":                    nomad.Options,
 		"aws-ami":                  ami.Options,
 		"aws-ec2":                  ec2.Options,
 		"aws-alb":                  alb.Options,
+		"aws-secretsmanager":       secretsmanager.Options,
 		"aws-ssm":                  ssm.Options,
 		"vault":                    vault.Options,
 	}
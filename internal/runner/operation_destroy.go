@@ -25,12 +25,16 @@ func (r *Runner) executeDestroyOp(
 		panic("operation not expected type")
 	}
 
+	force := core.WithDestroyForce(op.Destroy.Force)
 	switch t := op.Destroy.Target.(type) {
 	case *pb.Job_DestroyOp_Deployment:
-		err = app.DestroyDeploy(ctx, t.Deployment)
+		err = app.DestroyDeploy(ctx, t.Deployment, force)
+
+	case *pb.Job_DestroyOp_Release:
+		err = app.DestroyRelease(ctx, t.Release, force)
 
 	case *pb.Job_DestroyOp_Workspace:
-		err = app.Destroy(ctx)
+		err = app.Destroy(ctx, force)
 
 	default:
 		err = fmt.Errorf("unknown destruction target: %T", op.Destroy.Target)
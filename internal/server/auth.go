@@ -14,8 +14,35 @@ import (
 // An interface implemented by something that wishes to authenticate the server
 // actions.
 type AuthChecker interface {
-	// Called before each RPC to authenticate it.
-	Authenticate(ctx context.Context, token, endpoint string, effects []string) error
+	// Called before each RPC to authenticate it. The returned context is
+	// used for the rest of the RPC and must be derived from ctx. Checkers
+	// should mark authenticated RPCs with ContextWithTokenId so that they
+	// are rate limited by token.
+	Authenticate(ctx context.Context, token, endpoint string, effects []string) (context.Context, error)
+}
+
+// An interface optionally implemented by an AuthChecker that also wishes to
+// authorize the messages of each RPC, such as to limit a token to some projects.
+type MessageAuthorizer interface {
+	// Called after an RPC is authenticated. The returned function is called
+	// with every request and response message of the RPC, including each
+	// message sent on a stream. If the function is nil, no messages are checked.
+	AuthorizeMessages(ctx context.Context, token, endpoint string) (func(msg interface{}) error, error)
+}
+
+type tokenIdKey struct{}
+
+// ContextWithTokenId returns a context for an RPC that was authenticated
+// with the token with the given ID.
+func ContextWithTokenId(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tokenIdKey{}, id)
+}
+
+// TokenIdFromContext returns the ID of the token that authenticated the
+// RPC of ctx, or "" if the RPC wasn't authenticated with a token.
+func TokenIdFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(tokenIdKey{}).(string)
+	return id
 }
 
 var readonly = []string{"readonly"}
@@ -23,7 +50,44 @@ var readonly = []string{"readonly"}
 // Information about the effects of endpoints that are authenticated. If a endpoint
 // is not listed, the DefaultEffect value is used.
 var Effects = map[string][]string{
-	"ListBuilds": readonly,
+	"GetVersionInfo":              readonly,
+	"GetServerHealth":             readonly,
+	"ListWorkspaces":              readonly,
+	"GetWorkspace":                readonly,
+	"GetProject":                  readonly,
+	"ListProjects":                readonly,
+	"ListBuilds":                  readonly,
+	"GetBuild":                    readonly,
+	"ListPushedArtifacts":         readonly,
+	"GetPushedArtifact":           readonly,
+	"ListDeployments":             readonly,
+	"ListInstances":               readonly,
+	"GetDeployment":               readonly,
+	"GetLatestBuild":              readonly,
+	"GetLatestPushedArtifact":     readonly,
+	"ListReleases":                readonly,
+	"GetRelease":                  readonly,
+	"GetLatestRelease":            readonly,
+	"GetLogStream":                readonly,
+	"GetConfig":                   readonly,
+	"GetConfigSource":             readonly,
+	"ListHostnames":               readonly,
+	"GetJob":                      readonly,
+	"ValidateJob":                 readonly,
+	"GetJobStream":                readonly,
+	"GetDashboardStream":          readonly,
+	"GetRunner":                   readonly,
+	"GetServerConfig":             readonly,
+	"GetRestoreSnapshotOffset":    readonly,
+	"GetRestoreSnapshotPreflight": readonly,
+	"ListSnapshots":               readonly,
+	"ListAuditEvents":             readonly,
+	"ListTokens":                  readonly,
+	"ListWebhooks":                readonly,
+	"ListRetentionPolicies":       readonly,
+	"GetOrganization":             readonly,
+	"ListOrganizations":           readonly,
+	"GetLogLevel":                 readonly,
 }
 
 var DefaultEffects = []string{"mutable"}
@@ -60,11 +124,33 @@ func authUnaryInterceptor(checker AuthChecker) grpc.UnaryServerInterceptor {
 			token = authHeader[0]
 		}
 
-		err := checker.Authenticate(ctx, token, name, effects)
+		ctx, err := checker.Authenticate(ctx, token, name, effects)
+		if err != nil {
+			return nil, err
+		}
+
+		check, err := authorizeMessages(ctx, checker, token, name)
+		if err != nil {
+			return nil, err
+		}
+		if check == nil {
+			return handler(ctx, req)
+		}
+
+		if err := check(req); err != nil {
+			return nil, err
+		}
+
+		resp, err := handler(ctx, req)
 		if err != nil {
+			return resp, err
+		}
+
+		if err := check(resp); err != nil {
 			return nil, err
 		}
-		return handler(ctx, req)
+
+		return resp, nil
 	}
 }
 
@@ -102,12 +188,72 @@ func authStreamInterceptor(checker AuthChecker) grpc.StreamServerInterceptor {
 
 		token := authHeader[0]
 
-		err := checker.Authenticate(ss.Context(), token, name, effects)
+		ctx, err := checker.Authenticate(ss.Context(), token, name, effects)
+		if err != nil {
+			return err
+		}
+		ss = &authenticatedStream{ServerStream: ss, ctx: ctx}
+
+		check, err := authorizeMessages(ctx, checker, token, name)
 		if err != nil {
 			return err
 		}
+		if check != nil {
+			ss = &authorizedStream{ServerStream: ss, check: check}
+		}
 
 		// Invoke the handler.
 		return handler(srv, ss)
 	}
 }
+
+// authorizeMessages returns the function to check the messages of an RPC
+// with if checker implements MessageAuthorizer.
+func authorizeMessages(
+	ctx context.Context,
+	checker AuthChecker,
+	token, endpoint string,
+) (func(interface{}) error, error) {
+	authz, ok := checker.(MessageAuthorizer)
+	if !ok {
+		return nil, nil
+	}
+
+	return authz.AuthorizeMessages(ctx, token, endpoint)
+}
+
+// authenticatedStream wraps a grpc.ServerStream to use the context
+// returned by the AuthChecker.
+type authenticatedStream struct {
+	grpc.ServerStream
+
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// authorizedStream wraps a grpc.ServerStream to check every message that
+// is received or sent on it.
+type authorizedStream struct {
+	grpc.ServerStream
+
+	check func(interface{}) error
+}
+
+func (s *authorizedStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	return s.check(m)
+}
+
+func (s *authorizedStream) SendMsg(m interface{}) error {
+	if err := s.check(m); err != nil {
+		return err
+	}
+
+	return s.ServerStream.SendMsg(m)
+}
@@ -6,7 +6,9 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 type trivialAuth struct {
@@ -16,11 +18,11 @@ type trivialAuth struct {
 }
 
 // Called before each RPC to authenticate it.
-func (t *trivialAuth) Authenticate(ctx context.Context, token string, endpoint string, effects []string) error {
+func (t *trivialAuth) Authenticate(ctx context.Context, token string, endpoint string, effects []string) (context.Context, error) {
 	t.method = endpoint
 	t.token = token
 	t.effects = effects
-	return nil
+	return ContextWithTokenId(ctx, "id-"+token), nil
 }
 
 func TestAuthUnaryInterceptor(t *testing.T) {
@@ -43,6 +45,9 @@ func TestAuthUnaryInterceptor(t *testing.T) {
 	resp, err := f(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/foo/bar"},
 		func(ctx context.Context, req interface{}) (interface{}, error) {
 			called = true
+
+			// The handler gets the context from the checker
+			require.Equal("id-"+tokenVal, TokenIdFromContext(ctx))
 			return "hello", nil
 		},
 	)
@@ -55,3 +60,50 @@ func TestAuthUnaryInterceptor(t *testing.T) {
 	require.Equal("bar", chk.method)
 	require.Equal(DefaultEffects, chk.effects)
 }
+
+type messageAuth struct {
+	trivialAuth
+
+	messages []interface{}
+}
+
+func (m *messageAuth) AuthorizeMessages(ctx context.Context, token, endpoint string) (func(interface{}) error, error) {
+	return func(msg interface{}) error {
+		m.messages = append(m.messages, msg)
+		if msg == "denied" {
+			return status.Errorf(codes.PermissionDenied, "denied")
+		}
+
+		return nil
+	}, nil
+}
+
+func TestAuthUnaryInterceptor_messages(t *testing.T) {
+	require := require.New(t)
+
+	var chk messageAuth
+
+	f := authUnaryInterceptor(&chk)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.MD{
+		"authorization": []string{"this-is-a-token"},
+	})
+	info := &grpc.UnaryServerInfo{FullMethod: "/foo/bar"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "hello", nil
+	}
+
+	// The request and response are checked
+	resp, err := f(ctx, "request", info, handler)
+	require.NoError(err)
+	require.Equal("hello", resp)
+	require.Equal([]interface{}{"request", "hello"}, chk.messages)
+
+	// Denied requests don't call the handler
+	chk.messages = nil
+	resp, err = f(ctx, "denied", info, handler)
+	require.Error(err)
+	require.Equal(codes.PermissionDenied, status.Code(err))
+	require.Nil(resp)
+	require.Equal([]interface{}{"denied"}, chk.messages)
+}
@@ -28,10 +28,15 @@ type Client struct {
 	Client        pb.WaypointClient
 	DeploymentId  string
 	DeploymentSeq uint64
+	InstanceId    string
 	Args          []string
 	Stdin         io.Reader
 	Stdout        io.Writer
 	Stderr        io.Writer
+
+	// NoPty disables allocating a pty when stdout is a terminal, so that
+	// the command runs the same way whether or not it is in a terminal.
+	NoPty bool
 }
 
 func (c *Client) Run() (int, error) {
@@ -41,7 +46,7 @@ func (c *Client) Run() (int, error) {
 	var ptyF *os.File
 	var status terminal.Status
 
-	if f, ok := c.Stdout.(*os.File); ok && sshterm.IsTerminal(int(f.Fd())) {
+	if f, ok := c.Stdout.(*os.File); ok && !c.NoPty && sshterm.IsTerminal(int(f.Fd())) {
 		status = c.UI.Status()
 		defer status.Close()
 		status.Update(fmt.Sprintf("Connecting to deployment v%d...", c.DeploymentSeq))
@@ -87,6 +92,7 @@ func (c *Client) Run() (int, error) {
 		Event: &pb.ExecStreamRequest_Start_{
 			Start: &pb.ExecStreamRequest_Start{
 				DeploymentId: c.DeploymentId,
+				InstanceId:   c.InstanceId,
 				Args:         c.Args,
 				Pty:          ptyReq,
 			},
@@ -78,6 +78,36 @@ func (_m *WaypointClient) CancelJob(ctx context.Context, in *gen.CancelJobReques
 	return r0, r1
 }
 
+// CompleteOIDCAuth provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) CompleteOIDCAuth(ctx context.Context, in *gen.CompleteOIDCAuthRequest, opts ...grpc.CallOption) (*gen.CompleteOIDCAuthResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *gen.CompleteOIDCAuthResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.CompleteOIDCAuthRequest, ...grpc.CallOption) *gen.CompleteOIDCAuthResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.CompleteOIDCAuthResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.CompleteOIDCAuthRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ConvertInviteToken provides a mock function with given fields: ctx, in, opts
 func (_m *WaypointClient) ConvertInviteToken(ctx context.Context, in *gen.ConvertInviteTokenRequest, opts ...grpc.CallOption) (*gen.NewTokenResponse, error) {
 	_va := make([]interface{}, len(opts))
@@ -168,6 +198,36 @@ func (_m *WaypointClient) CreateSnapshot(ctx context.Context, in *emptypb.Empty,
 	return r0, r1
 }
 
+// CreateStoredSnapshot provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) CreateStoredSnapshot(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*gen.StoredSnapshot, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *gen.StoredSnapshot
+	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty, ...grpc.CallOption) *gen.StoredSnapshot); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.StoredSnapshot)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *emptypb.Empty, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteHostname provides a mock function with given fields: ctx, in, opts
 func (_m *WaypointClient) DeleteHostname(ctx context.Context, in *gen.DeleteHostnameRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	_va := make([]interface{}, len(opts))
@@ -198,6 +258,96 @@ func (_m *WaypointClient) DeleteHostname(ctx context.Context, in *gen.DeleteHost
 	return r0, r1
 }
 
+// DeleteOrganization provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) DeleteOrganization(ctx context.Context, in *gen.DeleteOrganizationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *emptypb.Empty
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.DeleteOrganizationRequest, ...grpc.CallOption) *emptypb.Empty); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emptypb.Empty)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.DeleteOrganizationRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteSnapshot provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) DeleteSnapshot(ctx context.Context, in *gen.DeleteSnapshotRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *emptypb.Empty
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.DeleteSnapshotRequest, ...grpc.CallOption) *emptypb.Empty); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emptypb.Empty)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.DeleteSnapshotRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteWebhook provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) DeleteWebhook(ctx context.Context, in *gen.DeleteWebhookRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *emptypb.Empty
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.DeleteWebhookRequest, ...grpc.CallOption) *emptypb.Empty); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emptypb.Empty)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.DeleteWebhookRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // EntrypointConfig provides a mock function with given fields: ctx, in, opts
 func (_m *WaypointClient) EntrypointConfig(ctx context.Context, in *gen.EntrypointConfigRequest, opts ...grpc.CallOption) (gen.Waypoint_EntrypointConfigClient, error) {
 	_va := make([]interface{}, len(opts))
@@ -319,7 +469,7 @@ func (_m *WaypointClient) GenerateInviteToken(ctx context.Context, in *gen.Invit
 }
 
 // GenerateLoginToken provides a mock function with given fields: ctx, in, opts
-func (_m *WaypointClient) GenerateLoginToken(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*gen.NewTokenResponse, error) {
+func (_m *WaypointClient) GenerateLoginToken(ctx context.Context, in *gen.LoginTokenRequest, opts ...grpc.CallOption) (*gen.NewTokenResponse, error) {
 	_va := make([]interface{}, len(opts))
 	for _i := range opts {
 		_va[_i] = opts[_i]
@@ -330,7 +480,7 @@ func (_m *WaypointClient) GenerateLoginToken(ctx context.Context, in *emptypb.Em
 	ret := _m.Called(_ca...)
 
 	var r0 *gen.NewTokenResponse
-	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty, ...grpc.CallOption) *gen.NewTokenResponse); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.LoginTokenRequest, ...grpc.CallOption) *gen.NewTokenResponse); ok {
 		r0 = rf(ctx, in, opts...)
 	} else {
 		if ret.Get(0) != nil {
@@ -339,7 +489,7 @@ func (_m *WaypointClient) GenerateLoginToken(ctx context.Context, in *emptypb.Em
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *emptypb.Empty, ...grpc.CallOption) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.LoginTokenRequest, ...grpc.CallOption) error); ok {
 		r1 = rf(ctx, in, opts...)
 	} else {
 		r1 = ret.Error(1)
@@ -438,6 +588,36 @@ func (_m *WaypointClient) GetConfigSource(ctx context.Context, in *gen.GetConfig
 	return r0, r1
 }
 
+// GetDashboardStream provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) GetDashboardStream(ctx context.Context, in *gen.GetDashboardStreamRequest, opts ...grpc.CallOption) (gen.Waypoint_GetDashboardStreamClient, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 gen.Waypoint_GetDashboardStreamClient
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetDashboardStreamRequest, ...grpc.CallOption) gen.Waypoint_GetDashboardStreamClient); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(gen.Waypoint_GetDashboardStreamClient)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetDashboardStreamRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetDeployment provides a mock function with given fields: ctx, in, opts
 func (_m *WaypointClient) GetDeployment(ctx context.Context, in *gen.GetDeploymentRequest, opts ...grpc.CallOption) (*gen.Deployment, error) {
 	_va := make([]interface{}, len(opts))
@@ -618,8 +798,8 @@ func (_m *WaypointClient) GetLatestRelease(ctx context.Context, in *gen.GetLates
 	return r0, r1
 }
 
-// GetLogStream provides a mock function with given fields: ctx, in, opts
-func (_m *WaypointClient) GetLogStream(ctx context.Context, in *gen.GetLogStreamRequest, opts ...grpc.CallOption) (gen.Waypoint_GetLogStreamClient, error) {
+// GetLogLevel provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) GetLogLevel(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*gen.GetLogLevelResponse, error) {
 	_va := make([]interface{}, len(opts))
 	for _i := range opts {
 		_va[_i] = opts[_i]
@@ -629,17 +809,17 @@ func (_m *WaypointClient) GetLogStream(ctx context.Context, in *gen.GetLogStream
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
-	var r0 gen.Waypoint_GetLogStreamClient
-	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetLogStreamRequest, ...grpc.CallOption) gen.Waypoint_GetLogStreamClient); ok {
+	var r0 *gen.GetLogLevelResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty, ...grpc.CallOption) *gen.GetLogLevelResponse); ok {
 		r0 = rf(ctx, in, opts...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(gen.Waypoint_GetLogStreamClient)
+			r0 = ret.Get(0).(*gen.GetLogLevelResponse)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetLogStreamRequest, ...grpc.CallOption) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *emptypb.Empty, ...grpc.CallOption) error); ok {
 		r1 = rf(ctx, in, opts...)
 	} else {
 		r1 = ret.Error(1)
@@ -648,8 +828,8 @@ func (_m *WaypointClient) GetLogStream(ctx context.Context, in *gen.GetLogStream
 	return r0, r1
 }
 
-// GetProject provides a mock function with given fields: ctx, in, opts
-func (_m *WaypointClient) GetProject(ctx context.Context, in *gen.GetProjectRequest, opts ...grpc.CallOption) (*gen.GetProjectResponse, error) {
+// GetLogStream provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) GetLogStream(ctx context.Context, in *gen.GetLogStreamRequest, opts ...grpc.CallOption) (gen.Waypoint_GetLogStreamClient, error) {
 	_va := make([]interface{}, len(opts))
 	for _i := range opts {
 		_va[_i] = opts[_i]
@@ -659,17 +839,17 @@ func (_m *WaypointClient) GetProject(ctx context.Context, in *gen.GetProjectRequ
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
-	var r0 *gen.GetProjectResponse
-	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetProjectRequest, ...grpc.CallOption) *gen.GetProjectResponse); ok {
+	var r0 gen.Waypoint_GetLogStreamClient
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetLogStreamRequest, ...grpc.CallOption) gen.Waypoint_GetLogStreamClient); ok {
 		r0 = rf(ctx, in, opts...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gen.GetProjectResponse)
+			r0 = ret.Get(0).(gen.Waypoint_GetLogStreamClient)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetProjectRequest, ...grpc.CallOption) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetLogStreamRequest, ...grpc.CallOption) error); ok {
 		r1 = rf(ctx, in, opts...)
 	} else {
 		r1 = ret.Error(1)
@@ -678,8 +858,8 @@ func (_m *WaypointClient) GetProject(ctx context.Context, in *gen.GetProjectRequ
 	return r0, r1
 }
 
-// GetPushedArtifact provides a mock function with given fields: ctx, in, opts
-func (_m *WaypointClient) GetPushedArtifact(ctx context.Context, in *gen.GetPushedArtifactRequest, opts ...grpc.CallOption) (*gen.PushedArtifact, error) {
+// GetOIDCAuthURL provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) GetOIDCAuthURL(ctx context.Context, in *gen.GetOIDCAuthURLRequest, opts ...grpc.CallOption) (*gen.GetOIDCAuthURLResponse, error) {
 	_va := make([]interface{}, len(opts))
 	for _i := range opts {
 		_va[_i] = opts[_i]
@@ -689,17 +869,17 @@ func (_m *WaypointClient) GetPushedArtifact(ctx context.Context, in *gen.GetPush
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
-	var r0 *gen.PushedArtifact
-	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetPushedArtifactRequest, ...grpc.CallOption) *gen.PushedArtifact); ok {
+	var r0 *gen.GetOIDCAuthURLResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetOIDCAuthURLRequest, ...grpc.CallOption) *gen.GetOIDCAuthURLResponse); ok {
 		r0 = rf(ctx, in, opts...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gen.PushedArtifact)
+			r0 = ret.Get(0).(*gen.GetOIDCAuthURLResponse)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetPushedArtifactRequest, ...grpc.CallOption) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetOIDCAuthURLRequest, ...grpc.CallOption) error); ok {
 		r1 = rf(ctx, in, opts...)
 	} else {
 		r1 = ret.Error(1)
@@ -708,8 +888,8 @@ func (_m *WaypointClient) GetPushedArtifact(ctx context.Context, in *gen.GetPush
 	return r0, r1
 }
 
-// GetRelease provides a mock function with given fields: ctx, in, opts
-func (_m *WaypointClient) GetRelease(ctx context.Context, in *gen.GetReleaseRequest, opts ...grpc.CallOption) (*gen.Release, error) {
+// GetOrganization provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) GetOrganization(ctx context.Context, in *gen.GetOrganizationRequest, opts ...grpc.CallOption) (*gen.GetOrganizationResponse, error) {
 	_va := make([]interface{}, len(opts))
 	for _i := range opts {
 		_va[_i] = opts[_i]
@@ -719,17 +899,17 @@ func (_m *WaypointClient) GetRelease(ctx context.Context, in *gen.GetReleaseRequ
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
-	var r0 *gen.Release
-	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetReleaseRequest, ...grpc.CallOption) *gen.Release); ok {
+	var r0 *gen.GetOrganizationResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetOrganizationRequest, ...grpc.CallOption) *gen.GetOrganizationResponse); ok {
 		r0 = rf(ctx, in, opts...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gen.Release)
+			r0 = ret.Get(0).(*gen.GetOrganizationResponse)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetReleaseRequest, ...grpc.CallOption) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetOrganizationRequest, ...grpc.CallOption) error); ok {
 		r1 = rf(ctx, in, opts...)
 	} else {
 		r1 = ret.Error(1)
@@ -738,8 +918,8 @@ func (_m *WaypointClient) GetRelease(ctx context.Context, in *gen.GetReleaseRequ
 	return r0, r1
 }
 
-// GetRunner provides a mock function with given fields: ctx, in, opts
-func (_m *WaypointClient) GetRunner(ctx context.Context, in *gen.GetRunnerRequest, opts ...grpc.CallOption) (*gen.Runner, error) {
+// GetProject provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) GetProject(ctx context.Context, in *gen.GetProjectRequest, opts ...grpc.CallOption) (*gen.GetProjectResponse, error) {
 	_va := make([]interface{}, len(opts))
 	for _i := range opts {
 		_va[_i] = opts[_i]
@@ -749,17 +929,17 @@ func (_m *WaypointClient) GetRunner(ctx context.Context, in *gen.GetRunnerReques
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
-	var r0 *gen.Runner
-	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetRunnerRequest, ...grpc.CallOption) *gen.Runner); ok {
+	var r0 *gen.GetProjectResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetProjectRequest, ...grpc.CallOption) *gen.GetProjectResponse); ok {
 		r0 = rf(ctx, in, opts...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gen.Runner)
+			r0 = ret.Get(0).(*gen.GetProjectResponse)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetRunnerRequest, ...grpc.CallOption) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetProjectRequest, ...grpc.CallOption) error); ok {
 		r1 = rf(ctx, in, opts...)
 	} else {
 		r1 = ret.Error(1)
@@ -768,8 +948,8 @@ func (_m *WaypointClient) GetRunner(ctx context.Context, in *gen.GetRunnerReques
 	return r0, r1
 }
 
-// GetServerConfig provides a mock function with given fields: ctx, in, opts
-func (_m *WaypointClient) GetServerConfig(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*gen.GetServerConfigResponse, error) {
+// GetPushedArtifact provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) GetPushedArtifact(ctx context.Context, in *gen.GetPushedArtifactRequest, opts ...grpc.CallOption) (*gen.PushedArtifact, error) {
 	_va := make([]interface{}, len(opts))
 	for _i := range opts {
 		_va[_i] = opts[_i]
@@ -779,17 +959,17 @@ func (_m *WaypointClient) GetServerConfig(ctx context.Context, in *emptypb.Empty
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
-	var r0 *gen.GetServerConfigResponse
-	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty, ...grpc.CallOption) *gen.GetServerConfigResponse); ok {
+	var r0 *gen.PushedArtifact
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetPushedArtifactRequest, ...grpc.CallOption) *gen.PushedArtifact); ok {
 		r0 = rf(ctx, in, opts...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gen.GetServerConfigResponse)
+			r0 = ret.Get(0).(*gen.PushedArtifact)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *emptypb.Empty, ...grpc.CallOption) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetPushedArtifactRequest, ...grpc.CallOption) error); ok {
 		r1 = rf(ctx, in, opts...)
 	} else {
 		r1 = ret.Error(1)
@@ -798,8 +978,8 @@ func (_m *WaypointClient) GetServerConfig(ctx context.Context, in *emptypb.Empty
 	return r0, r1
 }
 
-// GetVersionInfo provides a mock function with given fields: ctx, in, opts
-func (_m *WaypointClient) GetVersionInfo(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*gen.GetVersionInfoResponse, error) {
+// GetRelease provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) GetRelease(ctx context.Context, in *gen.GetReleaseRequest, opts ...grpc.CallOption) (*gen.Release, error) {
 	_va := make([]interface{}, len(opts))
 	for _i := range opts {
 		_va[_i] = opts[_i]
@@ -809,17 +989,17 @@ func (_m *WaypointClient) GetVersionInfo(ctx context.Context, in *emptypb.Empty,
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
-	var r0 *gen.GetVersionInfoResponse
-	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty, ...grpc.CallOption) *gen.GetVersionInfoResponse); ok {
+	var r0 *gen.Release
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetReleaseRequest, ...grpc.CallOption) *gen.Release); ok {
 		r0 = rf(ctx, in, opts...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gen.GetVersionInfoResponse)
+			r0 = ret.Get(0).(*gen.Release)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *emptypb.Empty, ...grpc.CallOption) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetReleaseRequest, ...grpc.CallOption) error); ok {
 		r1 = rf(ctx, in, opts...)
 	} else {
 		r1 = ret.Error(1)
@@ -828,8 +1008,8 @@ func (_m *WaypointClient) GetVersionInfo(ctx context.Context, in *emptypb.Empty,
 	return r0, r1
 }
 
-// GetWorkspace provides a mock function with given fields: ctx, in, opts
-func (_m *WaypointClient) GetWorkspace(ctx context.Context, in *gen.GetWorkspaceRequest, opts ...grpc.CallOption) (*gen.GetWorkspaceResponse, error) {
+// GetRestoreSnapshotOffset provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) GetRestoreSnapshotOffset(ctx context.Context, in *gen.GetRestoreSnapshotOffsetRequest, opts ...grpc.CallOption) (*gen.GetRestoreSnapshotOffsetResponse, error) {
 	_va := make([]interface{}, len(opts))
 	for _i := range opts {
 		_va[_i] = opts[_i]
@@ -839,17 +1019,17 @@ func (_m *WaypointClient) GetWorkspace(ctx context.Context, in *gen.GetWorkspace
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
-	var r0 *gen.GetWorkspaceResponse
-	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetWorkspaceRequest, ...grpc.CallOption) *gen.GetWorkspaceResponse); ok {
+	var r0 *gen.GetRestoreSnapshotOffsetResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetRestoreSnapshotOffsetRequest, ...grpc.CallOption) *gen.GetRestoreSnapshotOffsetResponse); ok {
 		r0 = rf(ctx, in, opts...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gen.GetWorkspaceResponse)
+			r0 = ret.Get(0).(*gen.GetRestoreSnapshotOffsetResponse)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetWorkspaceRequest, ...grpc.CallOption) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetRestoreSnapshotOffsetRequest, ...grpc.CallOption) error); ok {
 		r1 = rf(ctx, in, opts...)
 	} else {
 		r1 = ret.Error(1)
@@ -858,8 +1038,8 @@ func (_m *WaypointClient) GetWorkspace(ctx context.Context, in *gen.GetWorkspace
 	return r0, r1
 }
 
-// ListBuilds provides a mock function with given fields: ctx, in, opts
-func (_m *WaypointClient) ListBuilds(ctx context.Context, in *gen.ListBuildsRequest, opts ...grpc.CallOption) (*gen.ListBuildsResponse, error) {
+// GetRestoreSnapshotPreflight provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) GetRestoreSnapshotPreflight(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*gen.GetRestoreSnapshotPreflightResponse, error) {
 	_va := make([]interface{}, len(opts))
 	for _i := range opts {
 		_va[_i] = opts[_i]
@@ -869,17 +1049,17 @@ func (_m *WaypointClient) ListBuilds(ctx context.Context, in *gen.ListBuildsRequ
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
-	var r0 *gen.ListBuildsResponse
-	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListBuildsRequest, ...grpc.CallOption) *gen.ListBuildsResponse); ok {
+	var r0 *gen.GetRestoreSnapshotPreflightResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty, ...grpc.CallOption) *gen.GetRestoreSnapshotPreflightResponse); ok {
 		r0 = rf(ctx, in, opts...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gen.ListBuildsResponse)
+			r0 = ret.Get(0).(*gen.GetRestoreSnapshotPreflightResponse)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListBuildsRequest, ...grpc.CallOption) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *emptypb.Empty, ...grpc.CallOption) error); ok {
 		r1 = rf(ctx, in, opts...)
 	} else {
 		r1 = ret.Error(1)
@@ -888,8 +1068,8 @@ func (_m *WaypointClient) ListBuilds(ctx context.Context, in *gen.ListBuildsRequ
 	return r0, r1
 }
 
-// ListDeployments provides a mock function with given fields: ctx, in, opts
-func (_m *WaypointClient) ListDeployments(ctx context.Context, in *gen.ListDeploymentsRequest, opts ...grpc.CallOption) (*gen.ListDeploymentsResponse, error) {
+// GetRunner provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) GetRunner(ctx context.Context, in *gen.GetRunnerRequest, opts ...grpc.CallOption) (*gen.Runner, error) {
 	_va := make([]interface{}, len(opts))
 	for _i := range opts {
 		_va[_i] = opts[_i]
@@ -899,17 +1079,17 @@ func (_m *WaypointClient) ListDeployments(ctx context.Context, in *gen.ListDeplo
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
-	var r0 *gen.ListDeploymentsResponse
-	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListDeploymentsRequest, ...grpc.CallOption) *gen.ListDeploymentsResponse); ok {
+	var r0 *gen.Runner
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetRunnerRequest, ...grpc.CallOption) *gen.Runner); ok {
 		r0 = rf(ctx, in, opts...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gen.ListDeploymentsResponse)
+			r0 = ret.Get(0).(*gen.Runner)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListDeploymentsRequest, ...grpc.CallOption) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetRunnerRequest, ...grpc.CallOption) error); ok {
 		r1 = rf(ctx, in, opts...)
 	} else {
 		r1 = ret.Error(1)
@@ -918,8 +1098,8 @@ func (_m *WaypointClient) ListDeployments(ctx context.Context, in *gen.ListDeplo
 	return r0, r1
 }
 
-// ListHostnames provides a mock function with given fields: ctx, in, opts
-func (_m *WaypointClient) ListHostnames(ctx context.Context, in *gen.ListHostnamesRequest, opts ...grpc.CallOption) (*gen.ListHostnamesResponse, error) {
+// GetServerConfig provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) GetServerConfig(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*gen.GetServerConfigResponse, error) {
 	_va := make([]interface{}, len(opts))
 	for _i := range opts {
 		_va[_i] = opts[_i]
@@ -929,17 +1109,347 @@ func (_m *WaypointClient) ListHostnames(ctx context.Context, in *gen.ListHostnam
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
-	var r0 *gen.ListHostnamesResponse
-	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListHostnamesRequest, ...grpc.CallOption) *gen.ListHostnamesResponse); ok {
+	var r0 *gen.GetServerConfigResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty, ...grpc.CallOption) *gen.GetServerConfigResponse); ok {
 		r0 = rf(ctx, in, opts...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gen.ListHostnamesResponse)
+			r0 = ret.Get(0).(*gen.GetServerConfigResponse)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListHostnamesRequest, ...grpc.CallOption) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *emptypb.Empty, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServerHealth provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) GetServerHealth(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*gen.GetServerHealthResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *gen.GetServerHealthResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty, ...grpc.CallOption) *gen.GetServerHealthResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.GetServerHealthResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *emptypb.Empty, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetVersionInfo provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) GetVersionInfo(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*gen.GetVersionInfoResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *gen.GetVersionInfoResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty, ...grpc.CallOption) *gen.GetVersionInfoResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.GetVersionInfoResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *emptypb.Empty, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWorkspace provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) GetWorkspace(ctx context.Context, in *gen.GetWorkspaceRequest, opts ...grpc.CallOption) (*gen.GetWorkspaceResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *gen.GetWorkspaceResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetWorkspaceRequest, ...grpc.CallOption) *gen.GetWorkspaceResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.GetWorkspaceResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetWorkspaceRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAuditEvents provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) ListAuditEvents(ctx context.Context, in *gen.ListAuditEventsRequest, opts ...grpc.CallOption) (*gen.ListAuditEventsResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *gen.ListAuditEventsResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListAuditEventsRequest, ...grpc.CallOption) *gen.ListAuditEventsResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.ListAuditEventsResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListAuditEventsRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListBuilds provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) ListBuilds(ctx context.Context, in *gen.ListBuildsRequest, opts ...grpc.CallOption) (*gen.ListBuildsResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *gen.ListBuildsResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListBuildsRequest, ...grpc.CallOption) *gen.ListBuildsResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.ListBuildsResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListBuildsRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListDeployments provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) ListDeployments(ctx context.Context, in *gen.ListDeploymentsRequest, opts ...grpc.CallOption) (*gen.ListDeploymentsResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *gen.ListDeploymentsResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListDeploymentsRequest, ...grpc.CallOption) *gen.ListDeploymentsResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.ListDeploymentsResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListDeploymentsRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListHostnames provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) ListHostnames(ctx context.Context, in *gen.ListHostnamesRequest, opts ...grpc.CallOption) (*gen.ListHostnamesResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *gen.ListHostnamesResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListHostnamesRequest, ...grpc.CallOption) *gen.ListHostnamesResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.ListHostnamesResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListHostnamesRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListInstances provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) ListInstances(ctx context.Context, in *gen.ListInstancesRequest, opts ...grpc.CallOption) (*gen.ListInstancesResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *gen.ListInstancesResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListInstancesRequest, ...grpc.CallOption) *gen.ListInstancesResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.ListInstancesResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListInstancesRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListOrganizations provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) ListOrganizations(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*gen.ListOrganizationsResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *gen.ListOrganizationsResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty, ...grpc.CallOption) *gen.ListOrganizationsResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.ListOrganizationsResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *emptypb.Empty, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListProjects provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) ListProjects(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*gen.ListProjectsResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *gen.ListProjectsResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty, ...grpc.CallOption) *gen.ListProjectsResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.ListProjectsResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *emptypb.Empty, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPushedArtifacts provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) ListPushedArtifacts(ctx context.Context, in *gen.ListPushedArtifactsRequest, opts ...grpc.CallOption) (*gen.ListPushedArtifactsResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *gen.ListPushedArtifactsResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListPushedArtifactsRequest, ...grpc.CallOption) *gen.ListPushedArtifactsResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.ListPushedArtifactsResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListPushedArtifactsRequest, ...grpc.CallOption) error); ok {
 		r1 = rf(ctx, in, opts...)
 	} else {
 		r1 = ret.Error(1)
@@ -948,8 +1458,8 @@ func (_m *WaypointClient) ListHostnames(ctx context.Context, in *gen.ListHostnam
 	return r0, r1
 }
 
-// ListInstances provides a mock function with given fields: ctx, in, opts
-func (_m *WaypointClient) ListInstances(ctx context.Context, in *gen.ListInstancesRequest, opts ...grpc.CallOption) (*gen.ListInstancesResponse, error) {
+// ListReleases provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) ListReleases(ctx context.Context, in *gen.ListReleasesRequest, opts ...grpc.CallOption) (*gen.ListReleasesResponse, error) {
 	_va := make([]interface{}, len(opts))
 	for _i := range opts {
 		_va[_i] = opts[_i]
@@ -959,17 +1469,17 @@ func (_m *WaypointClient) ListInstances(ctx context.Context, in *gen.ListInstanc
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
-	var r0 *gen.ListInstancesResponse
-	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListInstancesRequest, ...grpc.CallOption) *gen.ListInstancesResponse); ok {
+	var r0 *gen.ListReleasesResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListReleasesRequest, ...grpc.CallOption) *gen.ListReleasesResponse); ok {
 		r0 = rf(ctx, in, opts...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gen.ListInstancesResponse)
+			r0 = ret.Get(0).(*gen.ListReleasesResponse)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListInstancesRequest, ...grpc.CallOption) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListReleasesRequest, ...grpc.CallOption) error); ok {
 		r1 = rf(ctx, in, opts...)
 	} else {
 		r1 = ret.Error(1)
@@ -978,8 +1488,8 @@ func (_m *WaypointClient) ListInstances(ctx context.Context, in *gen.ListInstanc
 	return r0, r1
 }
 
-// ListProjects provides a mock function with given fields: ctx, in, opts
-func (_m *WaypointClient) ListProjects(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*gen.ListProjectsResponse, error) {
+// ListRetentionPolicies provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) ListRetentionPolicies(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*gen.ListRetentionPoliciesResponse, error) {
 	_va := make([]interface{}, len(opts))
 	for _i := range opts {
 		_va[_i] = opts[_i]
@@ -989,12 +1499,12 @@ func (_m *WaypointClient) ListProjects(ctx context.Context, in *emptypb.Empty, o
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
-	var r0 *gen.ListProjectsResponse
-	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty, ...grpc.CallOption) *gen.ListProjectsResponse); ok {
+	var r0 *gen.ListRetentionPoliciesResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty, ...grpc.CallOption) *gen.ListRetentionPoliciesResponse); ok {
 		r0 = rf(ctx, in, opts...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gen.ListProjectsResponse)
+			r0 = ret.Get(0).(*gen.ListRetentionPoliciesResponse)
 		}
 	}
 
@@ -1008,8 +1518,8 @@ func (_m *WaypointClient) ListProjects(ctx context.Context, in *emptypb.Empty, o
 	return r0, r1
 }
 
-// ListPushedArtifacts provides a mock function with given fields: ctx, in, opts
-func (_m *WaypointClient) ListPushedArtifacts(ctx context.Context, in *gen.ListPushedArtifactsRequest, opts ...grpc.CallOption) (*gen.ListPushedArtifactsResponse, error) {
+// ListSnapshots provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) ListSnapshots(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*gen.ListSnapshotsResponse, error) {
 	_va := make([]interface{}, len(opts))
 	for _i := range opts {
 		_va[_i] = opts[_i]
@@ -1019,17 +1529,17 @@ func (_m *WaypointClient) ListPushedArtifacts(ctx context.Context, in *gen.ListP
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
-	var r0 *gen.ListPushedArtifactsResponse
-	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListPushedArtifactsRequest, ...grpc.CallOption) *gen.ListPushedArtifactsResponse); ok {
+	var r0 *gen.ListSnapshotsResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty, ...grpc.CallOption) *gen.ListSnapshotsResponse); ok {
 		r0 = rf(ctx, in, opts...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gen.ListPushedArtifactsResponse)
+			r0 = ret.Get(0).(*gen.ListSnapshotsResponse)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListPushedArtifactsRequest, ...grpc.CallOption) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *emptypb.Empty, ...grpc.CallOption) error); ok {
 		r1 = rf(ctx, in, opts...)
 	} else {
 		r1 = ret.Error(1)
@@ -1038,8 +1548,8 @@ func (_m *WaypointClient) ListPushedArtifacts(ctx context.Context, in *gen.ListP
 	return r0, r1
 }
 
-// ListReleases provides a mock function with given fields: ctx, in, opts
-func (_m *WaypointClient) ListReleases(ctx context.Context, in *gen.ListReleasesRequest, opts ...grpc.CallOption) (*gen.ListReleasesResponse, error) {
+// ListTokens provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) ListTokens(ctx context.Context, in *gen.ListTokensRequest, opts ...grpc.CallOption) (*gen.ListTokensResponse, error) {
 	_va := make([]interface{}, len(opts))
 	for _i := range opts {
 		_va[_i] = opts[_i]
@@ -1049,17 +1559,47 @@ func (_m *WaypointClient) ListReleases(ctx context.Context, in *gen.ListReleases
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
-	var r0 *gen.ListReleasesResponse
-	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListReleasesRequest, ...grpc.CallOption) *gen.ListReleasesResponse); ok {
+	var r0 *gen.ListTokensResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListTokensRequest, ...grpc.CallOption) *gen.ListTokensResponse); ok {
 		r0 = rf(ctx, in, opts...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gen.ListReleasesResponse)
+			r0 = ret.Get(0).(*gen.ListTokensResponse)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListReleasesRequest, ...grpc.CallOption) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListTokensRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListWebhooks provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) ListWebhooks(ctx context.Context, in *gen.ListWebhooksRequest, opts ...grpc.CallOption) (*gen.ListWebhooksResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *gen.ListWebhooksResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListWebhooksRequest, ...grpc.CallOption) *gen.ListWebhooksResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.ListWebhooksResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListWebhooksRequest, ...grpc.CallOption) error); ok {
 		r1 = rf(ctx, in, opts...)
 	} else {
 		r1 = ret.Error(1)
@@ -1098,6 +1638,36 @@ func (_m *WaypointClient) ListWorkspaces(ctx context.Context, in *emptypb.Empty,
 	return r0, r1
 }
 
+// PruneData provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) PruneData(ctx context.Context, in *gen.PruneDataRequest, opts ...grpc.CallOption) (*gen.PruneDataResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *gen.PruneDataResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.PruneDataRequest, ...grpc.CallOption) *gen.PruneDataResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.PruneDataResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.PruneDataRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // QueueJob provides a mock function with given fields: ctx, in, opts
 func (_m *WaypointClient) QueueJob(ctx context.Context, in *gen.QueueJobRequest, opts ...grpc.CallOption) (*gen.QueueJobResponse, error) {
 	_va := make([]interface{}, len(opts))
@@ -1158,6 +1728,96 @@ func (_m *WaypointClient) RestoreSnapshot(ctx context.Context, opts ...grpc.Call
 	return r0, r1
 }
 
+// RevokeToken provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) RevokeToken(ctx context.Context, in *gen.RevokeTokenRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *emptypb.Empty
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.RevokeTokenRequest, ...grpc.CallOption) *emptypb.Empty); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emptypb.Empty)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.RevokeTokenRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RotateTokenKey provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) RotateTokenKey(ctx context.Context, in *gen.RotateTokenKeyRequest, opts ...grpc.CallOption) (*gen.RotateTokenKeyResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *gen.RotateTokenKeyResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.RotateTokenKeyRequest, ...grpc.CallOption) *gen.RotateTokenKeyResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.RotateTokenKeyResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.RotateTokenKeyRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RunGC provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) RunGC(ctx context.Context, in *gen.RunGCRequest, opts ...grpc.CallOption) (*gen.RunGCResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *gen.RunGCResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.RunGCRequest, ...grpc.CallOption) *gen.RunGCResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.RunGCResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.RunGCRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RunnerConfig provides a mock function with given fields: ctx, opts
 func (_m *WaypointClient) RunnerConfig(ctx context.Context, opts ...grpc.CallOption) (gen.Waypoint_RunnerConfigClient, error) {
 	_va := make([]interface{}, len(opts))
@@ -1308,6 +1968,66 @@ func (_m *WaypointClient) SetConfigSource(ctx context.Context, in *gen.SetConfig
 	return r0, r1
 }
 
+// SetLogLevel provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) SetLogLevel(ctx context.Context, in *gen.SetLogLevelRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *emptypb.Empty
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.SetLogLevelRequest, ...grpc.CallOption) *emptypb.Empty); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emptypb.Empty)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.SetLogLevelRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetRetentionPolicy provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) SetRetentionPolicy(ctx context.Context, in *gen.SetRetentionPolicyRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *emptypb.Empty
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.SetRetentionPolicyRequest, ...grpc.CallOption) *emptypb.Empty); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emptypb.Empty)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.SetRetentionPolicyRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SetServerConfig provides a mock function with given fields: ctx, in, opts
 func (_m *WaypointClient) SetServerConfig(ctx context.Context, in *gen.SetServerConfigRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	_va := make([]interface{}, len(opts))
@@ -1458,6 +2178,36 @@ func (_m *WaypointClient) UpsertDeployment(ctx context.Context, in *gen.UpsertDe
 	return r0, r1
 }
 
+// UpsertOrganization provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) UpsertOrganization(ctx context.Context, in *gen.UpsertOrganizationRequest, opts ...grpc.CallOption) (*gen.UpsertOrganizationResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *gen.UpsertOrganizationResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.UpsertOrganizationRequest, ...grpc.CallOption) *gen.UpsertOrganizationResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.UpsertOrganizationResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.UpsertOrganizationRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpsertProject provides a mock function with given fields: ctx, in, opts
 func (_m *WaypointClient) UpsertProject(ctx context.Context, in *gen.UpsertProjectRequest, opts ...grpc.CallOption) (*gen.UpsertProjectResponse, error) {
 	_va := make([]interface{}, len(opts))
@@ -1548,6 +2298,36 @@ func (_m *WaypointClient) UpsertRelease(ctx context.Context, in *gen.UpsertRelea
 	return r0, r1
 }
 
+// UpsertWebhook provides a mock function with given fields: ctx, in, opts
+func (_m *WaypointClient) UpsertWebhook(ctx context.Context, in *gen.UpsertWebhookRequest, opts ...grpc.CallOption) (*gen.UpsertWebhookResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *gen.UpsertWebhookResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.UpsertWebhookRequest, ...grpc.CallOption) *gen.UpsertWebhookResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.UpsertWebhookResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.UpsertWebhookRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ValidateJob provides a mock function with given fields: ctx, in, opts
 func (_m *WaypointClient) ValidateJob(ctx context.Context, in *gen.ValidateJobRequest, opts ...grpc.CallOption) (*gen.ValidateJobResponse, error) {
 	_va := make([]interface{}, len(opts))
@@ -62,6 +62,29 @@ func (_m *WaypointServer) CancelJob(_a0 context.Context, _a1 *gen.CancelJobReque
 	return r0, r1
 }
 
+// CompleteOIDCAuth provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) CompleteOIDCAuth(_a0 context.Context, _a1 *gen.CompleteOIDCAuthRequest) (*gen.CompleteOIDCAuthResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.CompleteOIDCAuthResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.CompleteOIDCAuthRequest) *gen.CompleteOIDCAuthResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.CompleteOIDCAuthResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.CompleteOIDCAuthRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ConvertInviteToken provides a mock function with given fields: _a0, _a1
 func (_m *WaypointServer) ConvertInviteToken(_a0 context.Context, _a1 *gen.ConvertInviteTokenRequest) (*gen.NewTokenResponse, error) {
 	ret := _m.Called(_a0, _a1)
@@ -122,6 +145,29 @@ func (_m *WaypointServer) CreateSnapshot(_a0 *emptypb.Empty, _a1 gen.Waypoint_Cr
 	return r0
 }
 
+// CreateStoredSnapshot provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) CreateStoredSnapshot(_a0 context.Context, _a1 *emptypb.Empty) (*gen.StoredSnapshot, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.StoredSnapshot
+	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty) *gen.StoredSnapshot); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.StoredSnapshot)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *emptypb.Empty) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteHostname provides a mock function with given fields: _a0, _a1
 func (_m *WaypointServer) DeleteHostname(_a0 context.Context, _a1 *gen.DeleteHostnameRequest) (*emptypb.Empty, error) {
 	ret := _m.Called(_a0, _a1)
@@ -145,6 +191,75 @@ func (_m *WaypointServer) DeleteHostname(_a0 context.Context, _a1 *gen.DeleteHos
 	return r0, r1
 }
 
+// DeleteOrganization provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) DeleteOrganization(_a0 context.Context, _a1 *gen.DeleteOrganizationRequest) (*emptypb.Empty, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *emptypb.Empty
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.DeleteOrganizationRequest) *emptypb.Empty); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emptypb.Empty)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.DeleteOrganizationRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteSnapshot provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) DeleteSnapshot(_a0 context.Context, _a1 *gen.DeleteSnapshotRequest) (*emptypb.Empty, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *emptypb.Empty
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.DeleteSnapshotRequest) *emptypb.Empty); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emptypb.Empty)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.DeleteSnapshotRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteWebhook provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) DeleteWebhook(_a0 context.Context, _a1 *gen.DeleteWebhookRequest) (*emptypb.Empty, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *emptypb.Empty
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.DeleteWebhookRequest) *emptypb.Empty); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emptypb.Empty)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.DeleteWebhookRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // EntrypointConfig provides a mock function with given fields: _a0, _a1
 func (_m *WaypointServer) EntrypointConfig(_a0 *gen.EntrypointConfigRequest, _a1 gen.Waypoint_EntrypointConfigServer) error {
 	ret := _m.Called(_a0, _a1)
@@ -211,11 +326,11 @@ func (_m *WaypointServer) GenerateInviteToken(_a0 context.Context, _a1 *gen.Invi
 }
 
 // GenerateLoginToken provides a mock function with given fields: _a0, _a1
-func (_m *WaypointServer) GenerateLoginToken(_a0 context.Context, _a1 *emptypb.Empty) (*gen.NewTokenResponse, error) {
+func (_m *WaypointServer) GenerateLoginToken(_a0 context.Context, _a1 *gen.LoginTokenRequest) (*gen.NewTokenResponse, error) {
 	ret := _m.Called(_a0, _a1)
 
 	var r0 *gen.NewTokenResponse
-	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty) *gen.NewTokenResponse); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.LoginTokenRequest) *gen.NewTokenResponse); ok {
 		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
@@ -224,7 +339,7 @@ func (_m *WaypointServer) GenerateLoginToken(_a0 context.Context, _a1 *emptypb.E
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *emptypb.Empty) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.LoginTokenRequest) error); ok {
 		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
@@ -302,6 +417,20 @@ func (_m *WaypointServer) GetConfigSource(_a0 context.Context, _a1 *gen.GetConfi
 	return r0, r1
 }
 
+// GetDashboardStream provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) GetDashboardStream(_a0 *gen.GetDashboardStreamRequest, _a1 gen.Waypoint_GetDashboardStreamServer) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*gen.GetDashboardStreamRequest, gen.Waypoint_GetDashboardStreamServer) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetDeployment provides a mock function with given fields: _a0, _a1
 func (_m *WaypointServer) GetDeployment(_a0 context.Context, _a1 *gen.GetDeploymentRequest) (*gen.Deployment, error) {
 	ret := _m.Called(_a0, _a1)
@@ -431,6 +560,29 @@ func (_m *WaypointServer) GetLatestRelease(_a0 context.Context, _a1 *gen.GetLate
 	return r0, r1
 }
 
+// GetLogLevel provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) GetLogLevel(_a0 context.Context, _a1 *emptypb.Empty) (*gen.GetLogLevelResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.GetLogLevelResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty) *gen.GetLogLevelResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.GetLogLevelResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *emptypb.Empty) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLogStream provides a mock function with given fields: _a0, _a1
 func (_m *WaypointServer) GetLogStream(_a0 *gen.GetLogStreamRequest, _a1 gen.Waypoint_GetLogStreamServer) error {
 	ret := _m.Called(_a0, _a1)
@@ -445,6 +597,52 @@ func (_m *WaypointServer) GetLogStream(_a0 *gen.GetLogStreamRequest, _a1 gen.Way
 	return r0
 }
 
+// GetOIDCAuthURL provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) GetOIDCAuthURL(_a0 context.Context, _a1 *gen.GetOIDCAuthURLRequest) (*gen.GetOIDCAuthURLResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.GetOIDCAuthURLResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetOIDCAuthURLRequest) *gen.GetOIDCAuthURLResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.GetOIDCAuthURLResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetOIDCAuthURLRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrganization provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) GetOrganization(_a0 context.Context, _a1 *gen.GetOrganizationRequest) (*gen.GetOrganizationResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.GetOrganizationResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetOrganizationRequest) *gen.GetOrganizationResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.GetOrganizationResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetOrganizationRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetProject provides a mock function with given fields: _a0, _a1
 func (_m *WaypointServer) GetProject(_a0 context.Context, _a1 *gen.GetProjectRequest) (*gen.GetProjectResponse, error) {
 	ret := _m.Called(_a0, _a1)
@@ -514,6 +712,52 @@ func (_m *WaypointServer) GetRelease(_a0 context.Context, _a1 *gen.GetReleaseReq
 	return r0, r1
 }
 
+// GetRestoreSnapshotOffset provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) GetRestoreSnapshotOffset(_a0 context.Context, _a1 *gen.GetRestoreSnapshotOffsetRequest) (*gen.GetRestoreSnapshotOffsetResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.GetRestoreSnapshotOffsetResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetRestoreSnapshotOffsetRequest) *gen.GetRestoreSnapshotOffsetResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.GetRestoreSnapshotOffsetResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetRestoreSnapshotOffsetRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRestoreSnapshotPreflight provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) GetRestoreSnapshotPreflight(_a0 context.Context, _a1 *emptypb.Empty) (*gen.GetRestoreSnapshotPreflightResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.GetRestoreSnapshotPreflightResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty) *gen.GetRestoreSnapshotPreflightResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.GetRestoreSnapshotPreflightResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *emptypb.Empty) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetRunner provides a mock function with given fields: _a0, _a1
 func (_m *WaypointServer) GetRunner(_a0 context.Context, _a1 *gen.GetRunnerRequest) (*gen.Runner, error) {
 	ret := _m.Called(_a0, _a1)
@@ -560,6 +804,29 @@ func (_m *WaypointServer) GetServerConfig(_a0 context.Context, _a1 *emptypb.Empt
 	return r0, r1
 }
 
+// GetServerHealth provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) GetServerHealth(_a0 context.Context, _a1 *emptypb.Empty) (*gen.GetServerHealthResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.GetServerHealthResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty) *gen.GetServerHealthResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.GetServerHealthResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *emptypb.Empty) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetVersionInfo provides a mock function with given fields: _a0, _a1
 func (_m *WaypointServer) GetVersionInfo(_a0 context.Context, _a1 *emptypb.Empty) (*gen.GetVersionInfoResponse, error) {
 	ret := _m.Called(_a0, _a1)
@@ -606,6 +873,29 @@ func (_m *WaypointServer) GetWorkspace(_a0 context.Context, _a1 *gen.GetWorkspac
 	return r0, r1
 }
 
+// ListAuditEvents provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) ListAuditEvents(_a0 context.Context, _a1 *gen.ListAuditEventsRequest) (*gen.ListAuditEventsResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.ListAuditEventsResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListAuditEventsRequest) *gen.ListAuditEventsResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.ListAuditEventsResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListAuditEventsRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListBuilds provides a mock function with given fields: _a0, _a1
 func (_m *WaypointServer) ListBuilds(_a0 context.Context, _a1 *gen.ListBuildsRequest) (*gen.ListBuildsResponse, error) {
 	ret := _m.Called(_a0, _a1)
@@ -698,6 +988,29 @@ func (_m *WaypointServer) ListInstances(_a0 context.Context, _a1 *gen.ListInstan
 	return r0, r1
 }
 
+// ListOrganizations provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) ListOrganizations(_a0 context.Context, _a1 *emptypb.Empty) (*gen.ListOrganizationsResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.ListOrganizationsResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty) *gen.ListOrganizationsResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.ListOrganizationsResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *emptypb.Empty) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListProjects provides a mock function with given fields: _a0, _a1
 func (_m *WaypointServer) ListProjects(_a0 context.Context, _a1 *emptypb.Empty) (*gen.ListProjectsResponse, error) {
 	ret := _m.Called(_a0, _a1)
@@ -767,6 +1080,98 @@ func (_m *WaypointServer) ListReleases(_a0 context.Context, _a1 *gen.ListRelease
 	return r0, r1
 }
 
+// ListRetentionPolicies provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) ListRetentionPolicies(_a0 context.Context, _a1 *emptypb.Empty) (*gen.ListRetentionPoliciesResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.ListRetentionPoliciesResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty) *gen.ListRetentionPoliciesResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.ListRetentionPoliciesResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *emptypb.Empty) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListSnapshots provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) ListSnapshots(_a0 context.Context, _a1 *emptypb.Empty) (*gen.ListSnapshotsResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.ListSnapshotsResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty) *gen.ListSnapshotsResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.ListSnapshotsResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *emptypb.Empty) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTokens provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) ListTokens(_a0 context.Context, _a1 *gen.ListTokensRequest) (*gen.ListTokensResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.ListTokensResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListTokensRequest) *gen.ListTokensResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.ListTokensResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListTokensRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListWebhooks provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) ListWebhooks(_a0 context.Context, _a1 *gen.ListWebhooksRequest) (*gen.ListWebhooksResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.ListWebhooksResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListWebhooksRequest) *gen.ListWebhooksResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.ListWebhooksResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListWebhooksRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListWorkspaces provides a mock function with given fields: _a0, _a1
 func (_m *WaypointServer) ListWorkspaces(_a0 context.Context, _a1 *emptypb.Empty) (*gen.ListWorkspacesResponse, error) {
 	ret := _m.Called(_a0, _a1)
@@ -790,6 +1195,29 @@ func (_m *WaypointServer) ListWorkspaces(_a0 context.Context, _a1 *emptypb.Empty
 	return r0, r1
 }
 
+// PruneData provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) PruneData(_a0 context.Context, _a1 *gen.PruneDataRequest) (*gen.PruneDataResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.PruneDataResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.PruneDataRequest) *gen.PruneDataResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.PruneDataResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.PruneDataRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // QueueJob provides a mock function with given fields: _a0, _a1
 func (_m *WaypointServer) QueueJob(_a0 context.Context, _a1 *gen.QueueJobRequest) (*gen.QueueJobResponse, error) {
 	ret := _m.Called(_a0, _a1)
@@ -827,6 +1255,75 @@ func (_m *WaypointServer) RestoreSnapshot(_a0 gen.Waypoint_RestoreSnapshotServer
 	return r0
 }
 
+// RevokeToken provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) RevokeToken(_a0 context.Context, _a1 *gen.RevokeTokenRequest) (*emptypb.Empty, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *emptypb.Empty
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.RevokeTokenRequest) *emptypb.Empty); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emptypb.Empty)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.RevokeTokenRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RotateTokenKey provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) RotateTokenKey(_a0 context.Context, _a1 *gen.RotateTokenKeyRequest) (*gen.RotateTokenKeyResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.RotateTokenKeyResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.RotateTokenKeyRequest) *gen.RotateTokenKeyResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.RotateTokenKeyResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.RotateTokenKeyRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RunGC provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) RunGC(_a0 context.Context, _a1 *gen.RunGCRequest) (*gen.RunGCResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.RunGCResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.RunGCRequest) *gen.RunGCResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.RunGCResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.RunGCRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RunnerConfig provides a mock function with given fields: _a0
 func (_m *WaypointServer) RunnerConfig(_a0 gen.Waypoint_RunnerConfigServer) error {
 	ret := _m.Called(_a0)
@@ -924,6 +1421,52 @@ func (_m *WaypointServer) SetConfigSource(_a0 context.Context, _a1 *gen.SetConfi
 	return r0, r1
 }
 
+// SetLogLevel provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) SetLogLevel(_a0 context.Context, _a1 *gen.SetLogLevelRequest) (*emptypb.Empty, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *emptypb.Empty
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.SetLogLevelRequest) *emptypb.Empty); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emptypb.Empty)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.SetLogLevelRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetRetentionPolicy provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) SetRetentionPolicy(_a0 context.Context, _a1 *gen.SetRetentionPolicyRequest) (*emptypb.Empty, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *emptypb.Empty
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.SetRetentionPolicyRequest) *emptypb.Empty); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emptypb.Empty)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.SetRetentionPolicyRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SetServerConfig provides a mock function with given fields: _a0, _a1
 func (_m *WaypointServer) SetServerConfig(_a0 context.Context, _a1 *gen.SetServerConfigRequest) (*emptypb.Empty, error) {
 	ret := _m.Called(_a0, _a1)
@@ -1030,6 +1573,29 @@ func (_m *WaypointServer) UpsertDeployment(_a0 context.Context, _a1 *gen.UpsertD
 	return r0, r1
 }
 
+// UpsertOrganization provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) UpsertOrganization(_a0 context.Context, _a1 *gen.UpsertOrganizationRequest) (*gen.UpsertOrganizationResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.UpsertOrganizationResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.UpsertOrganizationRequest) *gen.UpsertOrganizationResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.UpsertOrganizationResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.UpsertOrganizationRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpsertProject provides a mock function with given fields: _a0, _a1
 func (_m *WaypointServer) UpsertProject(_a0 context.Context, _a1 *gen.UpsertProjectRequest) (*gen.UpsertProjectResponse, error) {
 	ret := _m.Called(_a0, _a1)
@@ -1099,6 +1665,29 @@ func (_m *WaypointServer) UpsertRelease(_a0 context.Context, _a1 *gen.UpsertRele
 	return r0, r1
 }
 
+// UpsertWebhook provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) UpsertWebhook(_a0 context.Context, _a1 *gen.UpsertWebhookRequest) (*gen.UpsertWebhookResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.UpsertWebhookResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.UpsertWebhookRequest) *gen.UpsertWebhookResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.UpsertWebhookResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.UpsertWebhookRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ValidateJob provides a mock function with given fields: _a0, _a1
 func (_m *WaypointServer) ValidateJob(_a0 context.Context, _a1 *gen.ValidateJobRequest) (*gen.ValidateJobResponse, error) {
 	ret := _m.Called(_a0, _a1)
@@ -39,6 +39,24 @@ func grpcInit(group *run.Group, opts *options) error {
 		),
 	)
 
+	// Metrics are recorded before authentication so that requests that
+	// fail authentication are counted too.
+	if m := opts.grpcMetrics; m != nil {
+		so = append(so,
+			grpc.ChainUnaryInterceptor(m.unaryInterceptor()),
+			grpc.ChainStreamInterceptor(m.streamInterceptor()),
+		)
+	}
+
+	// IP address limits are checked before authentication so that requests
+	// with invalid tokens are limited too.
+	if r := opts.RateLimiter; r != nil {
+		so = append(so,
+			grpc.ChainUnaryInterceptor(r.ipUnaryInterceptor()),
+			grpc.ChainStreamInterceptor(r.ipStreamInterceptor()),
+		)
+	}
+
 	if opts.AuthChecker != nil {
 		so = append(so,
 			grpc.ChainUnaryInterceptor(authUnaryInterceptor(opts.AuthChecker)),
@@ -46,8 +64,18 @@ func grpcInit(group *run.Group, opts *options) error {
 		)
 	}
 
+	// Token limits are checked after authentication so that they are only
+	// kept for tokens that the server issued.
+	if r := opts.RateLimiter; r != nil {
+		so = append(so,
+			grpc.ChainUnaryInterceptor(r.unaryInterceptor()),
+			grpc.ChainStreamInterceptor(r.streamInterceptor()),
+		)
+	}
+
 	s := grpc.NewServer(so...)
 	opts.grpcServer = s
+	opts.versionInfo = resp.Info
 
 	// Register the reflection service. This makes using tools like grpcurl
 	// easier. It makes it slightly easier for malicious users to know about
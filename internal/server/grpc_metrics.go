@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// grpcMetrics records Prometheus metrics for gRPC requests.
+type grpcMetrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+func newGRPCMetrics() *grpcMetrics {
+	return &grpcMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "waypoint_grpc_requests_total",
+			Help: "Number of completed gRPC requests by method and status code.",
+		}, []string{"method", "code"}),
+
+		// Only unary requests are timed since streams, such as for
+		// runners, stay open as long as the client is connected.
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "waypoint_grpc_request_duration_seconds",
+			Help: "Time taken by unary gRPC requests by method.",
+		}, []string{"method"}),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (m *grpcMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.requests.Describe(ch)
+	m.duration.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (m *grpcMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.requests.Collect(ch)
+	m.duration.Collect(ch)
+}
+
+// unaryInterceptor returns a gRPC unary interceptor that records the
+// count and duration of requests.
+func (m *grpcMetrics) unaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		m.duration.WithLabelValues(info.FullMethod).Observe(time.Since(start).Seconds())
+		m.requests.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+		return resp, err
+	}
+}
+
+// streamInterceptor returns a gRPC stream interceptor that records the
+// count of streams when they end.
+func (m *grpcMetrics) streamInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		err := handler(srv, ss)
+
+		m.requests.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+		return err
+	}
+}
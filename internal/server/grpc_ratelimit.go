@@ -0,0 +1,296 @@
+package server
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// rateLimitIdle is how long a client must make no requests and have no
+// open streams before its limits are forgotten.
+const rateLimitIdle = 10 * time.Minute
+
+// RateLimit configures the limits of WithRateLimit. Zero values aren't
+// limited.
+type RateLimit struct {
+	// TokenRate and TokenBurst limit the requests per second of each
+	// token, including opening streams. Requests that aren't authenticated
+	// with a valid token are only limited by IP.
+	TokenRate  float64
+	TokenBurst int
+
+	// IPRate and IPBurst limit the requests per second of each IP
+	// address, whatever token the requests are sent with. This should be
+	// higher than the token limit if many clients share an address.
+	IPRate  float64
+	IPBurst int
+
+	// MaxStreams limits how many streams each token, or IP address for
+	// requests without a token, can have open at once.
+	MaxStreams int
+}
+
+// rateLimiter enforces a RateLimit so that a misbehaving client, such as
+// a CI job that spams operations, can't affect the others.
+type rateLimiter struct {
+	config RateLimit
+
+	lock      sync.Mutex
+	clients   map[string]*rateLimitClient
+	lastSweep time.Time
+}
+
+// rateLimitClient is the state of a single token or IP address.
+type rateLimitClient struct {
+	limiter  *rate.Limiter
+	streams  int
+	lastSeen time.Time
+}
+
+func newRateLimiter(config RateLimit) *rateLimiter {
+	return &rateLimiter{
+		config:    config,
+		clients:   map[string]*rateLimitClient{},
+		lastSweep: time.Now(),
+	}
+}
+
+// client returns the state of the client with the given key, creating it
+// with the given limits if it doesn't exist. The lock must be held.
+func (r *rateLimiter) client(key string, limit float64, burst int) *rateLimitClient {
+	now := time.Now()
+
+	// Forget clients that have been idle so that the map doesn't grow
+	// forever. We only sweep occasionally since this is O(n).
+	if now.Sub(r.lastSweep) > rateLimitIdle {
+		for k, c := range r.clients {
+			if c.streams == 0 && now.Sub(c.lastSeen) > rateLimitIdle {
+				delete(r.clients, k)
+			}
+		}
+
+		r.lastSweep = now
+	}
+
+	c, ok := r.clients[key]
+	if !ok {
+		c = &rateLimitClient{}
+		if limit > 0 {
+			// Allow a second's worth of requests at once by default.
+			if burst <= 0 {
+				burst = int(limit)
+			}
+			if burst < 1 {
+				burst = 1
+			}
+
+			c.limiter = rate.NewLimiter(rate.Limit(limit), burst)
+		}
+
+		r.clients[key] = c
+	}
+
+	c.lastSeen = now
+	return c
+}
+
+// allowIP returns a ResourceExhausted error if the request exceeds the
+// rate of its IP address.
+func (r *rateLimiter) allowIP(ip string) error {
+	if r.config.IPRate <= 0 || ip == "" {
+		return nil
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	c := r.client("ip:"+ip, r.config.IPRate, r.config.IPBurst)
+	if !c.limiter.Allow() {
+		return status.Errorf(codes.ResourceExhausted,
+			"rate limit exceeded: each IP address can make %v requests per second, "+
+				"please retry later", r.config.IPRate)
+	}
+
+	return nil
+}
+
+// allowToken returns a ResourceExhausted error if the request exceeds the
+// rate of its token. The lock must be held.
+func (r *rateLimiter) allowToken(tokenId string) error {
+	if r.config.TokenRate <= 0 || tokenId == "" {
+		return nil
+	}
+
+	c := r.client("token:"+tokenId, r.config.TokenRate, r.config.TokenBurst)
+	if !c.limiter.Allow() {
+		return status.Errorf(codes.ResourceExhausted,
+			"rate limit exceeded: each token can make %v requests per second, "+
+				"please retry later", r.config.TokenRate)
+	}
+
+	return nil
+}
+
+// requestIP checks the IP address limit of a request. This is checked
+// before authentication so that requests with invalid tokens are limited.
+func (r *rateLimiter) requestIP(ctx context.Context) error {
+	ip, _ := rateLimitKeys(ctx)
+	return r.allowIP(ip)
+}
+
+// request checks the token limit of an authenticated unary request.
+func (r *rateLimiter) request(ctx context.Context) error {
+	_, tokenId := rateLimitKeys(ctx)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.allowToken(tokenId)
+}
+
+// openStream checks the token limits of a new authenticated stream. The
+// returned function must be called when the stream closes.
+func (r *rateLimiter) openStream(ctx context.Context) (func(), error) {
+	ip, tokenId := rateLimitKeys(ctx)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if err := r.allowToken(tokenId); err != nil {
+		return nil, err
+	}
+
+	if r.config.MaxStreams <= 0 {
+		return func() {}, nil
+	}
+
+	key := "streams:ip:" + ip
+	if tokenId != "" {
+		key = "streams:token:" + tokenId
+	}
+
+	c := r.client(key, 0, 0)
+	if c.streams >= r.config.MaxStreams {
+		return nil, status.Errorf(codes.ResourceExhausted,
+			"too many concurrent streams: each client can have %d streams open at once, "+
+				"please close some and retry", r.config.MaxStreams)
+	}
+
+	c.streams++
+	return func() {
+		r.lock.Lock()
+		defer r.lock.Unlock()
+
+		c.streams--
+		c.lastSeen = time.Now()
+	}, nil
+}
+
+// rateLimitKeys returns the IP address of the client of ctx and the ID of
+// the token that authenticated it. Either may be empty. The token ID is
+// only set once the auth interceptor has verified the token, so that
+// clients can't create limits by sending made up tokens.
+func rateLimitKeys(ctx context.Context) (ip, tokenId string) {
+	tokenId = TokenIdFromContext(ctx)
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		ip = p.Addr.String()
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+
+		// Requests from the JSON gateway come over an in-memory connection,
+		// so the gateway sends the IP of its client.
+		if values := md[gatewayClientIPHeader]; p.Addr.Network() == "bufconn" && len(values) > 0 {
+			ip = values[0]
+		}
+	}
+
+	return ip, tokenId
+}
+
+// ipUnaryInterceptor returns a gRPC unary interceptor that limits the
+// request rate of each IP address. It runs before authentication.
+func (r *rateLimiter) ipUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		if err := r.requestIP(ctx); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// ipStreamInterceptor returns a gRPC stream interceptor that limits the
+// rate that each IP address opens streams. It runs before authentication.
+func (r *rateLimiter) ipStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		// Reflection is used by tools such as grpcurl and isn't limited.
+		if strings.HasPrefix(info.FullMethod, "/grpc.reflection.v1alpha.ServerReflection/") {
+			return handler(srv, ss)
+		}
+
+		if err := r.requestIP(ss.Context()); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+// unaryInterceptor returns a gRPC unary interceptor that limits the
+// request rate of each token. It runs after authentication.
+func (r *rateLimiter) unaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		if err := r.request(ctx); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// streamInterceptor returns a gRPC stream interceptor that limits the
+// rate that each token opens streams and how many each client has open.
+// It runs after authentication.
+func (r *rateLimiter) streamInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		// Reflection is used by tools such as grpcurl and isn't limited.
+		if strings.HasPrefix(info.FullMethod, "/grpc.reflection.v1alpha.ServerReflection/") {
+			return handler(srv, ss)
+		}
+
+		done, err := r.openStream(ss.Context())
+		if err != nil {
+			return err
+		}
+		defer done()
+
+		return handler(srv, ss)
+	}
+}
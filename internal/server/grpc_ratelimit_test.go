@@ -0,0 +1,124 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestRateLimiter(t *testing.T) {
+	// testCtx returns the context of a request from ip that was
+	// authenticated with the token with the given ID.
+	testCtx := func(ip, tokenId string) context.Context {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{
+			Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 1234},
+		})
+
+		if tokenId != "" {
+			ctx = ContextWithTokenId(ctx, tokenId)
+		}
+
+		return ctx
+	}
+
+	t.Run("limits each token", func(t *testing.T) {
+		require := require.New(t)
+
+		r := newRateLimiter(RateLimit{TokenRate: 0.001, TokenBurst: 2})
+		ctx := testCtx("127.0.0.1", "a")
+		require.NoError(r.request(ctx))
+		require.NoError(r.request(ctx))
+
+		err := r.request(ctx)
+		require.Error(err)
+		require.Equal(codes.ResourceExhausted, status.Code(err))
+
+		// Other tokens aren't affected, even from the same IP
+		require.NoError(r.request(testCtx("127.0.0.1", "b")))
+
+		// Requests without a token aren't limited by token
+		require.NoError(r.request(testCtx("127.0.0.1", "")))
+		require.NoError(r.request(testCtx("127.0.0.1", "")))
+		require.NoError(r.request(testCtx("127.0.0.1", "")))
+	})
+
+	t.Run("limits each IP whatever the token", func(t *testing.T) {
+		require := require.New(t)
+
+		r := newRateLimiter(RateLimit{IPRate: 0.001, IPBurst: 2})
+		require.NoError(r.requestIP(testCtx("127.0.0.1", "a")))
+		require.NoError(r.requestIP(testCtx("127.0.0.1", "b")))
+
+		err := r.requestIP(testCtx("127.0.0.1", "c"))
+		require.Error(err)
+		require.Equal(codes.ResourceExhausted, status.Code(err))
+
+		// Other IPs aren't affected
+		require.NoError(r.requestIP(testCtx("127.0.0.2", "c")))
+	})
+
+	t.Run("ignores tokens that weren't verified", func(t *testing.T) {
+		require := require.New(t)
+
+		r := newRateLimiter(RateLimit{
+			TokenRate: 0.001, TokenBurst: 1,
+			IPRate: 0.001, IPBurst: 3,
+		})
+
+		// Each request has a different made up token, which must not
+		// get its own limit.
+		for i := 0; i < 3; i++ {
+			ctx := metadata.NewIncomingContext(testCtx("127.0.0.1", ""),
+				metadata.Pairs("authorization", fmt.Sprintf("junk-%d", i)))
+			require.NoError(r.requestIP(ctx))
+			require.NoError(r.request(ctx))
+		}
+
+		err := r.requestIP(testCtx("127.0.0.1", ""))
+		require.Error(err)
+		require.Equal(codes.ResourceExhausted, status.Code(err))
+		require.Len(r.clients, 1)
+	})
+
+	t.Run("burst defaults to one second", func(t *testing.T) {
+		require := require.New(t)
+
+		r := newRateLimiter(RateLimit{TokenRate: 3})
+		ctx := testCtx("127.0.0.1", "a")
+		for i := 0; i < 3; i++ {
+			require.NoError(r.request(ctx))
+		}
+		require.Error(r.request(ctx))
+	})
+
+	t.Run("limits concurrent streams", func(t *testing.T) {
+		require := require.New(t)
+
+		r := newRateLimiter(RateLimit{MaxStreams: 1})
+		ctx := testCtx("127.0.0.1", "a")
+		done, err := r.openStream(ctx)
+		require.NoError(err)
+
+		_, err = r.openStream(ctx)
+		require.Error(err)
+		require.Equal(codes.ResourceExhausted, status.Code(err))
+
+		// Other tokens aren't affected
+		otherDone, err := r.openStream(testCtx("127.0.0.1", "b"))
+		require.NoError(err)
+		otherDone()
+
+		// Closing the stream allows another
+		done()
+		done, err = r.openStream(ctx)
+		require.NoError(err)
+		done()
+	})
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hashicorp/go-hclog"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+const (
+	// healthPath and readyPath are the HTTP paths of the health checks.
+	// healthPath responds with 200 if the server is healthy and readyPath
+	// responds with 200 if it is ready. Both respond with 503 otherwise.
+	healthPath = "/healthz"
+	readyPath  = "/readyz"
+
+	// healthTimeout is how long the health checks can take.
+	healthTimeout = 10 * time.Second
+)
+
+// healthStatus is the body of the health checks. It only says whether
+// each check passed, since the checks aren't authenticated. The details,
+// such as the database path and job counts, are only available with the
+// GetServerHealth RPC.
+type healthStatus struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// healthResult returns "ok" or "fail" for a check result.
+func healthResult(ok bool) string {
+	if ok {
+		return "ok"
+	}
+
+	return "fail"
+}
+
+// healthHandler serves the health checks of the service. These don't
+// require authentication so that they can be used by load balancers and
+// orchestrators.
+func healthHandler(log hclog.Logger, svc pb.WaypointServer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), healthTimeout)
+		defer cancel()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+
+		resp, err := svc.GetServerHealth(ctx, &empty.Empty{})
+		if err != nil {
+			log.Warn("error checking server health", "err", err)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			if err := json.NewEncoder(w).Encode(&healthStatus{Status: "fail"}); err != nil {
+				log.Warn("error writing server health", "err", err)
+			}
+			return
+		}
+
+		ok := resp.Healthy
+		if r.URL.Path == readyPath {
+			ok = resp.Ready
+		}
+
+		body := &healthStatus{
+			Status: healthResult(ok),
+			Checks: map[string]string{},
+		}
+		for _, c := range resp.Checks {
+			body.Checks[c.Name] = healthResult(c.Ok)
+		}
+
+		if !ok {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		if err := json.NewEncoder(w).Encode(body); err != nil {
+			log.Warn("error writing server health", "err", err)
+		}
+	})
+}
@@ -11,8 +11,15 @@ import (
 	"github.com/hashicorp/waypoint/internal/server/gen"
 	"github.com/improbable-eng/grpc-web/go/grpcweb"
 	"github.com/oklog/run"
+	"google.golang.org/grpc/test/bufconn"
 )
 
+// OIDCHandler is optionally implemented by the service to handle OIDC
+// logins from the browser UI. The handler serves paths under "/oidc/".
+type OIDCHandler interface {
+	OIDCHTTPHandler() http.Handler
+}
+
 // httpInit initializes the HTTP server and adds it to the run group.
 func httpInit(group *run.Group, opts *options) error {
 	log := opts.Logger.Named("http")
@@ -36,11 +43,39 @@ func httpInit(group *run.Group, opts *options) error {
 		Fallback:  "index.html",
 	})
 
+	// The service can handle OIDC logins from the browser UI.
+	var oidcHandler http.Handler
+	if h, ok := opts.Service.(OIDCHandler); ok && opts.BrowserUIEnabled {
+		oidcHandler = h.OIDCHTTPHandler()
+	}
+
+	// The JSON gateway calls the gRPC server over an in-memory listener.
+	// The gRPC server closes the listener when it stops.
+	gatewayLn := bufconn.Listen(1 << 20)
+	group.Add(func() error {
+		return opts.grpcServer.Serve(gatewayLn)
+	}, func(error) {
+		gatewayLn.Close()
+	})
+
+	gateway, err := newJSONGateway(opts.Context, log.Named("json"), gatewayLn, opts.versionInfo)
+	if err != nil {
+		return err
+	}
+
+	health := healthHandler(log.Named("health"), opts.Service)
+
 	// If the path has a grpc prefix we assume it's a GRPC gateway request,
 	// otherwise fall back to serving the UI from the filesystem
 	rootHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if strings.HasPrefix(r.URL.Path, "/grpc") {
+		if r.URL.Path == healthPath || r.URL.Path == readyPath {
+			health.ServeHTTP(w, r)
+		} else if strings.HasPrefix(r.URL.Path, "/grpc") {
 			grpcWrapped.ServeHTTP(w, r)
+		} else if strings.HasPrefix(r.URL.Path, jsonGatewayPrefix) {
+			gateway.ServeHTTP(w, r)
+		} else if oidcHandler != nil && strings.HasPrefix(r.URL.Path, "/oidc/") {
+			oidcHandler.ServeHTTP(w, r)
 		} else if opts.BrowserUIEnabled {
 			uifs.ServeHTTP(w, r)
 		}
@@ -65,6 +100,7 @@ func httpInit(group *run.Group, opts *options) error {
 	}, func(err error) {
 		ctx, cancelFunc := context.WithCancel(context.Background())
 		defer cancelFunc()
+		defer gateway.Close()
 
 		// Graceful in a goroutine so we can timeout
 		gracefulCh := make(chan struct{})
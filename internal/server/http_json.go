@@ -0,0 +1,363 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/hashicorp/waypoint/internal/protocolversion"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+const (
+	// jsonGatewayPrefix is the path prefix of the HTTP/JSON API. The rest
+	// of the path is the name of the RPC, such as "/v1/ListDeployments".
+	jsonGatewayPrefix = "/v1/"
+
+	// jsonGatewayMaxBody is the largest request body that is accepted. This
+	// is the default max message size of gRPC servers.
+	jsonGatewayMaxBody = 4 << 20
+
+	// gatewayClientIPHeader is the metadata key the JSON gateway sets to
+	// the IP address of its HTTP client. It is only trusted on requests
+	// that come from the gateway.
+	gatewayClientIPHeader = "x-waypoint-gateway-client-ip"
+)
+
+// jsonGateway serves the gRPC API as JSON over HTTP for clients that
+// can't use gRPC. Requests are made to the gRPC server over an in-memory
+// connection so that they are authenticated, rate limited, and logged the
+// same as gRPC requests.
+//
+// Each RPC is called with a POST request to its path with the JSON of the
+// request message as the body. RPCs that only read can also be called with
+// a GET request, which sends an empty request message. The responses of
+// RPCs that stream are sent as newline-delimited JSON.
+type jsonGateway struct {
+	log     hclog.Logger
+	conn    *grpc.ClientConn
+	methods map[string]*jsonGatewayMethod
+
+	marshaler   *jsonpb.Marshaler
+	unmarshaler *jsonpb.Unmarshaler
+}
+
+type jsonGatewayMethod struct {
+	fullName string
+	desc     protoreflect.MethodDescriptor
+	input    protoreflect.MessageType
+	output   protoreflect.MessageType
+}
+
+// newJSONGateway returns a gateway that calls the gRPC server listening
+// on ln. info is the version info of the server, which is sent as the
+// version info of the gateway's client.
+func newJSONGateway(
+	ctx context.Context,
+	log hclog.Logger,
+	ln *bufconn.Listener,
+	info *pb.VersionInfo,
+) (*jsonGateway, error) {
+	conn, err := grpc.DialContext(ctx, "bufconn",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return ln.Dial()
+		}),
+		grpc.WithInsecure(),
+		grpc.WithChainUnaryInterceptor(protocolversion.UnaryClientInterceptor(info)),
+		grpc.WithChainStreamInterceptor(protocolversion.StreamClientInterceptor(info)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	methods := map[string]*jsonGatewayMethod{}
+	sd := pb.File_internal_server_proto_server_proto.Services().ByName("Waypoint")
+	for i := 0; i < sd.Methods().Len(); i++ {
+		md := sd.Methods().Get(i)
+
+		input, err := protoregistry.GlobalTypes.FindMessageByName(md.Input().FullName())
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		output, err := protoregistry.GlobalTypes.FindMessageByName(md.Output().FullName())
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		methods[string(md.Name())] = &jsonGatewayMethod{
+			fullName: "/" + string(sd.FullName()) + "/" + string(md.Name()),
+			desc:     md,
+			input:    input,
+			output:   output,
+		}
+	}
+
+	return &jsonGateway{
+		log:         log,
+		conn:        conn,
+		methods:     methods,
+		marshaler:   &jsonpb.Marshaler{AnyResolver: jsonGatewayResolver{}},
+		unmarshaler: &jsonpb.Unmarshaler{AnyResolver: jsonGatewayResolver{}},
+	}, nil
+}
+
+// Close closes the connection to the gRPC server.
+func (g *jsonGateway) Close() error {
+	return g.conn.Close()
+}
+
+func (g *jsonGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, jsonGatewayPrefix)
+	m, ok := g.methods[name]
+	if !ok {
+		g.writeError(w, status.Errorf(codes.Unimplemented, "unknown method %q", name))
+		return
+	}
+
+	if m.desc.IsStreamingClient() {
+		g.writeError(w, status.Errorf(codes.Unimplemented,
+			"%s streams requests and can only be called with gRPC", name))
+		return
+	}
+
+	req := proto.MessageV1(m.input.New().Interface())
+	switch r.Method {
+	case http.MethodPost:
+		body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, jsonGatewayMaxBody))
+		if err != nil {
+			g.writeError(w, status.Errorf(codes.InvalidArgument,
+				"error reading request: %s", err))
+			return
+		}
+
+		// An empty body is an empty request so that RPCs without
+		// arguments can be called without one.
+		if len(bytes.TrimSpace(body)) > 0 {
+			if err := g.unmarshaler.Unmarshal(bytes.NewReader(body), req); err != nil {
+				g.writeError(w, status.Errorf(codes.InvalidArgument,
+					"error decoding request: %s", err))
+				return
+			}
+		}
+
+	case http.MethodGet:
+		if !jsonGatewayReadonly(name) {
+			w.Header().Set("Allow", http.MethodPost)
+			g.writeErrorStatus(w, http.StatusMethodNotAllowed, status.Errorf(codes.Unimplemented,
+				"%s changes data and must be called with POST", name))
+			return
+		}
+
+	default:
+		w.Header().Set("Allow", http.MethodPost)
+		g.writeErrorStatus(w, http.StatusMethodNotAllowed, status.Errorf(codes.Unimplemented,
+			"method %s isn't allowed, use POST", r.Method))
+		return
+	}
+
+	// Only the token and client IP are sent to the gRPC server. Tokens can
+	// be given as bearer tokens or on their own.
+	md := []string{gatewayClientIPHeader, httpClientIP(r)}
+	if token := r.Header.Get("Authorization"); token != "" {
+		md = append(md, "authorization", strings.TrimPrefix(token, "Bearer "))
+	}
+	ctx := metadata.AppendToOutgoingContext(r.Context(), md...)
+
+	if m.desc.IsStreamingServer() {
+		g.serveStream(ctx, w, m, req)
+		return
+	}
+
+	resp := proto.MessageV1(m.output.New().Interface())
+	if err := g.conn.Invoke(ctx, m.fullName, req, resp); err != nil {
+		g.writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := g.marshaler.Marshal(w, resp); err != nil {
+		g.log.Warn("error writing response", "method", name, "err", err)
+	}
+}
+
+// serveStream calls an RPC that streams responses and writes each
+// response as a line of JSON as it is received. If the RPC fails after
+// the first response, the error is written as the last line since the
+// status code has already been sent.
+func (g *jsonGateway) serveStream(
+	ctx context.Context,
+	w http.ResponseWriter,
+	m *jsonGatewayMethod,
+	req proto.Message,
+) {
+	stream, err := g.conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, m.fullName)
+	if err != nil {
+		g.writeError(w, err)
+		return
+	}
+
+	// If sending fails, the error of the RPC is returned by RecvMsg.
+	if err := stream.SendMsg(req); err != nil && err != io.EOF {
+		g.writeError(w, err)
+		return
+	}
+	if err := stream.CloseSend(); err != nil {
+		g.writeError(w, err)
+		return
+	}
+
+	flusher, _ := w.(http.Flusher)
+	started := false
+	for {
+		resp := proto.MessageV1(m.output.New().Interface())
+		err := stream.RecvMsg(resp)
+		if err == io.EOF {
+			if !started {
+				w.Header().Set("Content-Type", "application/x-ndjson")
+				w.WriteHeader(http.StatusOK)
+			}
+
+			return
+		}
+		if err != nil {
+			if !started {
+				g.writeError(w, err)
+				return
+			}
+
+			json.NewEncoder(w).Encode(jsonGatewayErrorBody(err))
+			return
+		}
+
+		if !started {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.WriteHeader(http.StatusOK)
+			started = true
+		}
+
+		if err := g.marshaler.Marshal(w, resp); err != nil {
+			g.log.Warn("error writing response", "method", m.desc.Name(), "err", err)
+			return
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
+// writeError writes the JSON of a gRPC error with the matching HTTP status.
+func (g *jsonGateway) writeError(w http.ResponseWriter, err error) {
+	g.writeErrorStatus(w, jsonGatewayHTTPStatus(status.Code(err)), err)
+}
+
+func (g *jsonGateway) writeErrorStatus(w http.ResponseWriter, code int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(jsonGatewayErrorBody(err))
+}
+
+type jsonGatewayError struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func jsonGatewayErrorBody(err error) *jsonGatewayError {
+	st := status.Convert(err)
+
+	var result jsonGatewayError
+	result.Error.Code = st.Code().String()
+	result.Error.Message = st.Message()
+	return &result
+}
+
+// jsonGatewayHTTPStatus returns the HTTP status code for a gRPC code.
+func jsonGatewayHTTPStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.FailedPrecondition:
+		return http.StatusPreconditionFailed
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// jsonGatewayReadonly returns true if the RPC only reads.
+func jsonGatewayReadonly(name string) bool {
+	for _, e := range Effects[name] {
+		if e == "readonly" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// httpClientIP returns the IP address of the client of r.
+func httpClientIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return ip
+}
+
+// jsonGatewayResolver resolves the types of Any values. Plugins can store
+// values of types that the server doesn't know, such as deployments of
+// plugins that aren't built in. Those are written with only their type
+// rather than failing the whole response.
+type jsonGatewayResolver struct{}
+
+func (jsonGatewayResolver) Resolve(typeURL string) (proto.Message, error) {
+	mt, err := protoregistry.GlobalTypes.FindMessageByURL(typeURL)
+	if err != nil {
+		return &empty.Empty{}, nil
+	}
+
+	return proto.MessageV1(mt.New().Interface()), nil
+}
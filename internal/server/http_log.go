@@ -34,7 +34,14 @@ func httpLogHandler(handler http.Handler, log hclog.Logger) http.Handler {
 			scheme = forwardedProto
 		}
 
-		log.Info(
+		// Health checks are made often by load balancers, so they're only
+		// logged at trace level.
+		level := hclog.Info
+		if req.URL.Path == healthPath || req.URL.Path == readyPath {
+			level = hclog.Trace
+		}
+
+		log.Log(level,
 			fmt.Sprintf("HTTP request: %s %s", req.Method, req.URL.Path),
 			"date", params.TimeStamp.Format(time.RFC3339Nano),
 			"http.host", req.Host,
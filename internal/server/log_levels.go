@@ -0,0 +1,231 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// LogSubsystems are the subsystems of the server whose log level can be
+// set separately from the level of the rest of the server logs. Loggers
+// named after a subsystem, such as with log.Named("state"), and the
+// loggers created from them use the level of the subsystem.
+var LogSubsystems = []string{"grpc", "jobs", "runner", "state"}
+
+// LogLevels sets the levels of the server logs while the server is
+// running. Each subsystem in LogSubsystems can have its own level. A
+// subsystem without a level uses the level of the server.
+type LogLevels struct {
+	base hclog.Logger
+
+	// server is the level of the server logs and subsystems are the
+	// levels of the subsystems, which are hclog.NoLevel if they use the
+	// level of the server. These are read and written atomically.
+	server     *int32
+	subsystems map[string]*int32
+}
+
+// NewLogLevels returns the levels of the logs of log. The level that log
+// is set to becomes the level of the server. Only the loggers returned by
+// Logger and the loggers created from them are filtered by these levels,
+// so other users of log or its parent loggers log everything from then
+// on.
+func NewLogLevels(log hclog.Logger) *LogLevels {
+	server := int32(loggerLevel(log))
+	l := &LogLevels{
+		base:       log,
+		server:     &server,
+		subsystems: map[string]*int32{},
+	}
+	for _, name := range LogSubsystems {
+		level := int32(hclog.NoLevel)
+		l.subsystems[name] = &level
+	}
+
+	log.SetLevel(hclog.Trace)
+	return l
+}
+
+// Logger returns the logger of the server, whose level is the level of
+// the server.
+func (l *LogLevels) Logger() hclog.Logger {
+	return &levelLogger{Logger: l.base, levels: l}
+}
+
+// Level returns the level of the subsystem, or of the server if subsystem
+// is empty. This is hclog.NoLevel for subsystems that use the level of the
+// server.
+func (l *LogLevels) Level(subsystem string) (hclog.Level, error) {
+	v, err := l.level(subsystem)
+	if err != nil {
+		return hclog.NoLevel, err
+	}
+
+	return hclog.Level(atomic.LoadInt32(v)), nil
+}
+
+// SetLevel sets the level of the subsystem, or of the server if subsystem
+// is empty. Setting a subsystem to hclog.NoLevel makes it use the level of
+// the server again.
+func (l *LogLevels) SetLevel(subsystem string, level hclog.Level) error {
+	v, err := l.level(subsystem)
+	if err != nil {
+		return err
+	}
+	if subsystem == "" && level == hclog.NoLevel {
+		return fmt.Errorf("the server must have a log level")
+	}
+
+	atomic.StoreInt32(v, int32(level))
+	return nil
+}
+
+func (l *LogLevels) level(subsystem string) (*int32, error) {
+	if subsystem == "" {
+		return l.server, nil
+	}
+
+	v, ok := l.subsystems[subsystem]
+	if !ok {
+		return nil, fmt.Errorf(
+			"unknown log subsystem %q, must be one of %s",
+			subsystem, strings.Join(LogSubsystems, ", "))
+	}
+
+	return v, nil
+}
+
+// enabled returns true if messages of level are logged for the
+// subsystem, or for the server if subsystem is empty.
+func (l *LogLevels) enabled(subsystem string, level hclog.Level) bool {
+	min := hclog.NoLevel
+	if v, ok := l.subsystems[subsystem]; ok {
+		min = hclog.Level(atomic.LoadInt32(v))
+	}
+	if min == hclog.NoLevel {
+		min = hclog.Level(atomic.LoadInt32(l.server))
+	}
+
+	return level >= min
+}
+
+// LogLevelString returns the name of a level, such as "debug", which
+// hclog.LevelFromString accepts. This is empty for hclog.NoLevel.
+func LogLevelString(level hclog.Level) string {
+	switch level {
+	case hclog.Trace:
+		return "trace"
+	case hclog.Debug:
+		return "debug"
+	case hclog.Info:
+		return "info"
+	case hclog.Warn:
+		return "warn"
+	case hclog.Error:
+		return "error"
+	default:
+		return ""
+	}
+}
+
+// loggerLevel returns the most verbose level that log logs.
+func loggerLevel(log hclog.Logger) hclog.Level {
+	switch {
+	case log.IsTrace():
+		return hclog.Trace
+	case log.IsDebug():
+		return hclog.Debug
+	case log.IsInfo():
+		return hclog.Info
+	case log.IsWarn():
+		return hclog.Warn
+	default:
+		return hclog.Error
+	}
+}
+
+// levelLogger is a logger that filters messages by the level of its
+// subsystem. The logger it wraps logs every level.
+type levelLogger struct {
+	hclog.Logger
+
+	levels    *LogLevels
+	subsystem string
+}
+
+func (l *levelLogger) Log(level hclog.Level, msg string, args ...interface{}) {
+	if l.levels.enabled(l.subsystem, level) {
+		l.Logger.Log(level, msg, args...)
+	}
+}
+
+func (l *levelLogger) Trace(msg string, args ...interface{}) {
+	if l.IsTrace() {
+		l.Logger.Trace(msg, args...)
+	}
+}
+
+func (l *levelLogger) Debug(msg string, args ...interface{}) {
+	if l.IsDebug() {
+		l.Logger.Debug(msg, args...)
+	}
+}
+
+func (l *levelLogger) Info(msg string, args ...interface{}) {
+	if l.IsInfo() {
+		l.Logger.Info(msg, args...)
+	}
+}
+
+func (l *levelLogger) Warn(msg string, args ...interface{}) {
+	if l.IsWarn() {
+		l.Logger.Warn(msg, args...)
+	}
+}
+
+func (l *levelLogger) Error(msg string, args ...interface{}) {
+	if l.IsError() {
+		l.Logger.Error(msg, args...)
+	}
+}
+
+func (l *levelLogger) IsTrace() bool { return l.levels.enabled(l.subsystem, hclog.Trace) }
+func (l *levelLogger) IsDebug() bool { return l.levels.enabled(l.subsystem, hclog.Debug) }
+func (l *levelLogger) IsInfo() bool  { return l.levels.enabled(l.subsystem, hclog.Info) }
+func (l *levelLogger) IsWarn() bool  { return l.levels.enabled(l.subsystem, hclog.Warn) }
+func (l *levelLogger) IsError() bool { return l.levels.enabled(l.subsystem, hclog.Error) }
+
+func (l *levelLogger) With(args ...interface{}) hclog.Logger {
+	return &levelLogger{
+		Logger:    l.Logger.With(args...),
+		levels:    l.levels,
+		subsystem: l.subsystem,
+	}
+}
+
+// Named returns a sub-logger. If name is a subsystem, the sub-logger uses
+// the level of that subsystem.
+func (l *levelLogger) Named(name string) hclog.Logger {
+	return l.named(l.Logger.Named(name), name)
+}
+
+func (l *levelLogger) ResetNamed(name string) hclog.Logger {
+	return l.named(l.Logger.ResetNamed(name), name)
+}
+
+func (l *levelLogger) named(log hclog.Logger, name string) hclog.Logger {
+	subsystem := l.subsystem
+	if _, ok := l.levels.subsystems[name]; ok {
+		subsystem = name
+	}
+
+	return &levelLogger{Logger: log, levels: l.levels, subsystem: subsystem}
+}
+
+// SetLevel sets the level of the subsystem of the logger, or of the
+// server if it isn't in a subsystem.
+func (l *levelLogger) SetLevel(level hclog.Level) {
+	l.levels.SetLevel(l.subsystem, level)
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogLevels(t *testing.T) {
+	require := require.New(t)
+
+	var buf bytes.Buffer
+	levels := NewLogLevels(hclog.New(&hclog.LoggerOptions{
+		Name:   "test",
+		Level:  hclog.Info,
+		Output: &buf,
+	}))
+	log := levels.Logger()
+
+	level, err := levels.Level("")
+	require.NoError(err)
+	require.Equal(hclog.Info, level)
+
+	// Subsystems use the level of the server by default
+	state := log.Named("singleprocess").Named("state")
+	state.Debug("hidden")
+	require.Empty(buf.String())
+
+	// Subsystems can be more verbose than the server
+	require.NoError(levels.SetLevel("state", hclog.Debug))
+	state.Debug("state debug")
+	log.Debug("server debug")
+	require.Contains(buf.String(), "state debug")
+	require.NotContains(buf.String(), "server debug")
+
+	// Sub-loggers of a subsystem use its level
+	buf.Reset()
+	state.With("job", "A").Named("restore").Debug("restore debug")
+	require.Contains(buf.String(), "restore debug")
+
+	// Subsystems can be less verbose than the server
+	buf.Reset()
+	require.NoError(levels.SetLevel("grpc", hclog.Error))
+	log.Named("grpc").Info("grpc info")
+	log.Info("server info")
+	require.NotContains(buf.String(), "grpc info")
+	require.Contains(buf.String(), "server info")
+
+	// Setting the level of a logger sets the level of its subsystem
+	log.Named("runner").SetLevel(hclog.Trace)
+	level, err = levels.Level("runner")
+	require.NoError(err)
+	require.Equal(hclog.Trace, level)
+
+	// Unknown subsystems
+	_, err = levels.Level("nope")
+	require.Error(err)
+	require.Error(levels.SetLevel("nope", hclog.Debug))
+	require.Error(levels.SetLevel("", hclog.NoLevel))
+}
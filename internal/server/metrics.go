@@ -0,0 +1,82 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/oklog/run"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// metricsInit initializes the Prometheus metrics HTTP server and adds it
+// to the run group. This must be called before grpcInit so that the gRPC
+// server records metrics.
+func metricsInit(group *run.Group, opts *options) error {
+	log := opts.Logger.Named("metrics")
+	if opts.MetricsListener == nil {
+		log.Debug("metrics listener not specified, metrics are disabled")
+		return nil
+	}
+
+	// We use our own registry rather than the global default so that
+	// multiple servers in one process (such as in tests) don't conflict.
+	reg := prometheus.NewRegistry()
+	opts.grpcMetrics = newGRPCMetrics()
+	collectors := []prometheus.Collector{
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		opts.grpcMetrics,
+	}
+
+	// The service can report its own metrics, such as the job queue.
+	if c, ok := opts.Service.(prometheus.Collector); ok {
+		collectors = append(collectors, c)
+	}
+
+	for _, c := range collectors {
+		if err := reg.Register(c); err != nil {
+			return err
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{
+		ErrorLog: log.StandardLogger(&hclog.StandardLoggerOptions{
+			InferLevels: true,
+		}),
+	}))
+
+	// The health checks are also served here since the metrics listener
+	// usually doesn't use TLS, which is simpler for orchestrators.
+	health := healthHandler(log.Named("health"), opts.Service)
+	mux.Handle(healthPath, health)
+	mux.Handle(readyPath, health)
+
+	httpSrv := &http.Server{
+		ReadHeaderTimeout: 5 * time.Second,
+		IdleTimeout:       120 * time.Second,
+		Handler:           mux,
+		BaseContext: func(net.Listener) context.Context {
+			return opts.Context
+		},
+	}
+
+	// Add our metrics server to the run group
+	group.Add(func() error {
+		ln := opts.MetricsListener
+		log.Info("starting metrics server", "addr", ln.Addr().String())
+		return httpSrv.Serve(ln)
+	}, func(err error) {
+		ctx, cancelFunc := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancelFunc()
+
+		log.Info("shutting down metrics server")
+		httpSrv.Shutdown(ctx)
+	})
+
+	return nil
+}
@@ -27,9 +27,12 @@ func TestServerConfig(t testing.T, src *pb.ServerConfig) *pb.ServerConfig {
 	return src
 }
 
-// ValidateServerConfig validates the server config structure.
+// ValidateServerConfig validates the server config structure. The
+// advertise addresses can be left out if the runtime config is set.
 func ValidateServerConfig(c *pb.ServerConfig) error {
 	return validation.ValidateStruct(c,
-		validation.Field(&c.AdvertiseAddrs, validation.Required, validation.Length(1, 1)),
+		validation.Field(&c.AdvertiseAddrs,
+			validation.When(c.Runtime == nil, validation.Required),
+			validation.Length(1, 1)),
 	)
 }
@@ -33,6 +33,15 @@ func TestValidateServerConfig(t *testing.T) {
 			},
 			"advertise_addrs: the length must be exactly 1",
 		},
+
+		{
+			"runtime without advertise addrs",
+			func(c *pb.ServerConfig) {
+				c.AdvertiseAddrs = nil
+				c.Runtime = &pb.ServerRuntimeConfig{LogLevel: "debug"}
+			},
+			"",
+		},
 	}
 
 	for _, tt := range cases {
@@ -46,6 +46,12 @@ func Run(opts ...Option) error {
 		return ctx.Err()
 	}, func(error) { cancelCtx() })
 
+	// Setup our metrics server. This has to happen before the gRPC server
+	// is created so that requests are recorded.
+	if err := metricsInit(&group, &cfg); err != nil {
+		return err
+	}
+
 	// Setup our gRPC server.
 	if err := grpcInit(&group, &cfg); err != nil {
 		return err
@@ -85,13 +91,22 @@ type options struct {
 	// the HTTP-based API will be disabled.
 	HTTPListener net.Listener
 
+	// MetricsListener will setup the Prometheus metrics HTTP server. If
+	// this is nil, then metrics are disabled.
+	MetricsListener net.Listener
+
 	// AuthChecker, if set, activates authentication checking on the server.
 	AuthChecker AuthChecker
 
 	// BrowserUIEnabled determines if the browser UI should be mounted
 	BrowserUIEnabled bool
 
-	grpcServer *grpc.Server
+	// RateLimiter, if set, limits the gRPC requests of each client.
+	RateLimiter *rateLimiter
+
+	grpcServer  *grpc.Server
+	grpcMetrics *grpcMetrics
+	versionInfo *pb.VersionInfo
 }
 
 // WithContext sets the context for the server. When this context is cancelled,
@@ -119,6 +134,14 @@ func WithHTTP(ln net.Listener) Option {
 	return func(opts *options) { opts.HTTPListener = ln }
 }
 
+// WithMetrics sets the listener for the Prometheus metrics endpoint. This
+// listener must be closed manually by the caller. Prior to closing the
+// listener, it is recommended that you cancel the context set with
+// WithContext and wait for Run to return.
+func WithMetrics(ln net.Listener) Option {
+	return func(opts *options) { opts.MetricsListener = ln }
+}
+
 // WithImpl sets the service implementation to serve.
 func WithImpl(impl pb.WaypointServer) Option {
 	return func(opts *options) { opts.Service = impl }
@@ -129,6 +152,16 @@ func WithAuthentication(ac AuthChecker) Option {
 	return func(opts *options) { opts.AuthChecker = ac }
 }
 
+// WithRateLimit limits the gRPC requests of each token and IP address.
+// Requests over the limits fail with ResourceExhausted.
+func WithRateLimit(limit RateLimit) Option {
+	return func(opts *options) {
+		if limit != (RateLimit{}) {
+			opts.RateLimiter = newRateLimiter(limit)
+		}
+	}
+}
+
 // WithBrowserUI configures the server to enable the browser UI.
 func WithBrowserUI(enabled bool) Option {
 	return func(opts *options) { opts.BrowserUIEnabled = enabled }
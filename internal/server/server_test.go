@@ -2,12 +2,17 @@ package server
 
 import (
 	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -79,3 +84,189 @@ func TestRun_reconnect(t *testing.T) {
 		return err == nil
 	}, 5*time.Second, 10*time.Millisecond)
 }
+
+func TestRun_metrics(t *testing.T) {
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := &pbmocks.WaypointServer{}
+	m.On("GetVersionInfo", mock.Anything, mock.Anything).Return(testVersionInfoResponse(), nil)
+
+	grpcLn, err := net.Listen("tcp", "127.0.0.1:")
+	require.NoError(err)
+	defer grpcLn.Close()
+	metricsLn, err := net.Listen("tcp", "127.0.0.1:")
+	require.NoError(err)
+	defer metricsLn.Close()
+
+	go Run(
+		WithContext(ctx),
+		WithGRPC(grpcLn),
+		WithMetrics(metricsLn),
+		WithImpl(m),
+	)
+
+	// Make a request so that it is recorded
+	conn, err := grpc.DialContext(ctx, grpcLn.Addr().String(),
+		grpc.WithBlock(),
+		grpc.WithInsecure(),
+	)
+	require.NoError(err)
+	defer conn.Close()
+	_, err = pb.NewWaypointClient(conn).GetVersionInfo(ctx, &empty.Empty{})
+	require.NoError(err)
+
+	// The request should be in our metrics
+	resp, err := http.Get("http://" + metricsLn.Addr().String() + "/metrics")
+	require.NoError(err)
+	defer resp.Body.Close()
+	require.Equal(http.StatusOK, resp.StatusCode)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(err)
+	require.Contains(string(body),
+		`waypoint_grpc_requests_total{code="OK",method="/hashicorp.waypoint.Waypoint/GetVersionInfo"} 1`)
+}
+
+func TestRun_httpJSON(t *testing.T) {
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := &pbmocks.WaypointServer{}
+	m.On("GetVersionInfo", mock.Anything, mock.Anything).Return(testVersionInfoResponse(), nil)
+	m.On("GetWorkspace", mock.Anything, mock.MatchedBy(func(req *pb.GetWorkspaceRequest) bool {
+		return req.Workspace.GetWorkspace() == "test"
+	})).Return(&pb.GetWorkspaceResponse{
+		Workspace: &pb.Workspace{Name: "test"},
+	}, nil)
+	m.On("GetWorkspace", mock.Anything, mock.Anything).Return(
+		nil, status.Errorf(codes.NotFound, "not found"))
+
+	grpcLn, err := net.Listen("tcp", "127.0.0.1:")
+	require.NoError(err)
+	defer grpcLn.Close()
+	httpLn, err := net.Listen("tcp", "127.0.0.1:")
+	require.NoError(err)
+	defer httpLn.Close()
+
+	go Run(
+		WithContext(ctx),
+		WithGRPC(grpcLn),
+		WithHTTP(httpLn),
+		WithImpl(m),
+	)
+
+	addr := "http://" + httpLn.Addr().String() + jsonGatewayPrefix
+	post := func(method, body string) (int, string) {
+		var resp *http.Response
+		require.Eventually(func() bool {
+			resp, err = http.Post(addr+method, "application/json", strings.NewReader(body))
+			return err == nil
+		}, 5*time.Second, 10*time.Millisecond)
+		defer resp.Body.Close()
+
+		data, err := ioutil.ReadAll(resp.Body)
+		require.NoError(err)
+		return resp.StatusCode, string(data)
+	}
+
+	{
+		// Request is decoded and response is encoded
+		code, body := post("GetWorkspace", `{"workspace": {"workspace": "test"}}`)
+		require.Equal(http.StatusOK, code)
+		require.JSONEq(`{"workspace": {"name": "test"}}`, body)
+	}
+
+	{
+		// Errors are mapped to HTTP statuses
+		code, body := post("GetWorkspace", `{"workspace": {"workspace": "other"}}`)
+		require.Equal(http.StatusNotFound, code)
+		require.Contains(body, `"code":"NotFound"`)
+	}
+
+	{
+		// Invalid requests
+		code, _ := post("GetWorkspace", `{"nope": true}`)
+		require.Equal(http.StatusBadRequest, code)
+	}
+
+	{
+		// Unknown methods
+		code, _ := post("Nope", "")
+		require.Equal(http.StatusNotImplemented, code)
+	}
+
+	{
+		// GET is only allowed for methods that only read
+		resp, err := http.Get(addr + "UpsertProject")
+		require.NoError(err)
+		resp.Body.Close()
+		require.Equal(http.StatusMethodNotAllowed, resp.StatusCode)
+
+		resp, err = http.Get(addr + "GetVersionInfo")
+		require.NoError(err)
+		resp.Body.Close()
+		require.Equal(http.StatusOK, resp.StatusCode)
+	}
+}
+
+func TestRun_health(t *testing.T) {
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := &pbmocks.WaypointServer{}
+	m.On("GetVersionInfo", mock.Anything, mock.Anything).Return(testVersionInfoResponse(), nil)
+	m.On("GetServerHealth", mock.Anything, mock.Anything).Return(&pb.GetServerHealthResponse{
+		Healthy: true,
+		Checks: []*pb.ServerHealthCheck{
+			{Name: "db", Ok: true, Critical: true},
+			{Name: "disk", Message: "only 1 MB free"},
+		},
+	}, nil)
+
+	grpcLn, err := net.Listen("tcp", "127.0.0.1:")
+	require.NoError(err)
+	defer grpcLn.Close()
+	metricsLn, err := net.Listen("tcp", "127.0.0.1:")
+	require.NoError(err)
+	defer metricsLn.Close()
+
+	go Run(
+		WithContext(ctx),
+		WithGRPC(grpcLn),
+		WithMetrics(metricsLn),
+		WithImpl(m),
+	)
+
+	get := func(path string) (int, string) {
+		var resp *http.Response
+		require.Eventually(func() bool {
+			resp, err = http.Get("http://" + metricsLn.Addr().String() + path)
+			return err == nil
+		}, 5*time.Second, 10*time.Millisecond)
+		defer resp.Body.Close()
+
+		data, err := ioutil.ReadAll(resp.Body)
+		require.NoError(err)
+		return resp.StatusCode, string(data)
+	}
+
+	{
+		// Healthy
+		code, body := get(healthPath)
+		require.Equal(http.StatusOK, code)
+		require.JSONEq(`{"status":"ok","checks":{"db":"ok","disk":"fail"}}`, body)
+	}
+
+	{
+		// Not ready since a check failed. The details of the check aren't
+		// shown since the endpoint isn't authenticated.
+		code, body := get(readyPath)
+		require.Equal(http.StatusServiceUnavailable, code)
+		require.JSONEq(`{"status":"fail","checks":{"db":"ok","disk":"fail"}}`, body)
+		require.NotContains(body, "only 1 MB free")
+	}
+}
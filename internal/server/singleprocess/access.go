@@ -0,0 +1,517 @@
+package singleprocess
+
+import (
+	"context"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// publicEndpoints can be called without a token so that clients can get
+// a token and check the server version.
+var publicEndpoints = map[string]bool{
+	"ConvertInviteToken": true,
+	"BootstrapToken":     true,
+	"GetVersionInfo":     true,
+	"GetOIDCAuthURL":     true,
+	"CompleteOIDCAuth":   true,
+}
+
+// adminEndpoints can only be called by admin tokens since they affect
+// every project on the server or can be used to gain more access.
+// Webhooks make the server send requests to any URL from its network,
+// which could reach hosts that only the server can, so only admins can
+// create them.
+var adminEndpoints = map[string]bool{
+	"SetServerConfig":      true,
+	"CreateSnapshot":       true,
+	"RestoreSnapshot":      true,
+	"DeleteSnapshot":       true,
+	"CreateStoredSnapshot": true,
+	"GenerateInviteToken":  true,
+	"GenerateLoginToken":   true,
+	"ListAuditEvents":      true,
+	"ListTokens":           true,
+	"RevokeToken":          true,
+	"RotateTokenKey":       true,
+	"SetConfigSource":      true,
+	"GetConfigSource":      true,
+	"SetRetentionPolicy":   true,
+	"RunGC":                true,
+	"PruneData":            true,
+	"UpsertOrganization":   true,
+	"DeleteOrganization":   true,
+	"ListOrganizations":    true,
+	"SetLogLevel":          true,
+	"UpsertWebhook":        true,
+}
+
+// serverEndpoints don't operate on projects, so they can't be called by
+// tokens limited to some projects or workspaces. The adminEndpoints are
+// also server endpoints.
+var serverEndpoints = map[string]bool{
+	"GetServerConfig":             true,
+	"ListSnapshots":               true,
+	"GetRestoreSnapshotOffset":    true,
+	"GetRestoreSnapshotPreflight": true,
+	"ListRetentionPolicies":       true,
+	"GetLogLevel":                 true,
+	"GetServerHealth":             true,
+}
+
+// checkRole checks that the role and scope of the token allow calling
+// the endpoint. Read-only tokens can only call endpoints with the
+// "readonly" effect.
+func checkRole(body *pb.Token, endpoint string, effects []string) error {
+	var allowed bool
+	switch body.Role {
+	case pb.Token_ADMIN:
+		allowed = true
+
+	case pb.Token_OPERATOR:
+		allowed = !adminEndpoints[endpoint]
+
+	case pb.Token_READ_ONLY:
+		allowed = !adminEndpoints[endpoint] && hasEffect(effects, "readonly")
+	}
+	if !allowed {
+		return status.Errorf(codes.PermissionDenied,
+			"tokens with the %s role can't call %s", roleName(body.Role), endpoint)
+	}
+
+	if tokenScoped(body) && (adminEndpoints[endpoint] || serverEndpoints[endpoint]) {
+		return status.Errorf(codes.PermissionDenied,
+			"tokens limited to projects or workspaces can't call %s", endpoint)
+	}
+
+	return nil
+}
+
+// roleName returns the name of a role as it is shown to and given by
+// users, such as "read-only".
+func roleName(role pb.Token_Role) string {
+	return strings.Replace(strings.ToLower(role.String()), "_", "-", -1)
+}
+
+// parseRole returns the role with the name given by roleName. Tokens
+// can't be given the unspecified role.
+func parseRole(name string) (pb.Token_Role, bool) {
+	for v := range pb.Token_Role_name {
+		role := pb.Token_Role(v)
+		if role != pb.Token_ROLE_UNSPECIFIED && roleName(role) == name {
+			return role, true
+		}
+	}
+
+	return pb.Token_ROLE_UNSPECIFIED, false
+}
+
+// tokenScoped returns true if the token is limited to some projects,
+// workspaces, or an organization.
+func tokenScoped(body *pb.Token) bool {
+	return len(body.Projects) > 0 || len(body.Workspaces) > 0 || body.Organization != ""
+}
+
+func hasEffect(effects []string, effect string) bool {
+	for _, e := range effects {
+		if e == effect {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AuthorizeMessages implements server.MessageAuthorizer. The messages of
+// tokens limited to some projects or workspaces are checked so that they
+// can't access the others.
+func (s *service) AuthorizeMessages(
+	ctx context.Context,
+	token, endpoint string,
+) (func(interface{}) error, error) {
+	if publicEndpoints[endpoint] {
+		return nil, nil
+	}
+
+	body, err := s.tokenBody(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if !tokenScoped(body) {
+		return nil, nil
+	}
+
+	a := &tokenAccess{
+		s:          s,
+		projects:   accessSet(body.Projects),
+		workspaces: accessSet(body.Workspaces),
+		org:        body.Organization,
+	}
+
+	// The projects of the organization are read once for the RPC, so
+	// projects added to it during a stream aren't accessible until the
+	// next RPC.
+	if body.Organization != "" {
+		org, err := s.state.OrganizationGet(body.Organization)
+		if status.Code(err) == codes.NotFound {
+			return nil, status.Errorf(codes.PermissionDenied,
+				"organization %q of the token doesn't exist", body.Organization)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		a.orgProjects = map[string]bool{}
+		for _, p := range org.Projects {
+			a.orgProjects[strings.ToLower(p.Project)] = true
+		}
+	}
+
+	return a.check, nil
+}
+
+// tokenAccess checks the messages of a token limited to some projects,
+// workspaces, or an organization. Names are compared case insensitively,
+// like the state does.
+type tokenAccess struct {
+	s *service
+
+	// projects and workspaces are the lowercased names the token can
+	// access. If either is nil, the token can access all of them.
+	projects   map[string]bool
+	workspaces map[string]bool
+
+	// org is the organization of the token, if any, and orgProjects are
+	// the lowercased names of its projects. The token can only access
+	// projects that are in both projects and orgProjects.
+	org         string
+	orgProjects map[string]bool
+}
+
+// accessSet returns the set of lowercased names, or nil if there are none.
+func accessSet(names []string) map[string]bool {
+	if len(names) == 0 {
+		return nil
+	}
+
+	result := map[string]bool{}
+	for _, n := range names {
+		result[strings.ToLower(n)] = true
+	}
+
+	return result
+}
+
+// project returns true if the token can access the project. An empty name
+// isn't a wildcard: the server uses names as they are given, so an empty
+// name is only accessible to tokens that can access every project.
+func (a *tokenAccess) project(name string) bool {
+	name = strings.ToLower(name)
+	return (a.projects == nil || a.projects[name]) &&
+		(a.org == "" || a.orgProjects[name])
+}
+
+// workspace returns true if the token can access the workspace. As with
+// project, an empty name isn't a wildcard.
+func (a *tokenAccess) workspace(name string) bool {
+	return a.workspaces == nil || a.workspaces[strings.ToLower(name)]
+}
+
+// check checks a request or response message. Every project and workspace
+// the message refers to must be accessible. Requests that refer to jobs and
+// deployments by ID are checked against the job or deployment.
+func (a *tokenAccess) check(msg interface{}) error {
+	switch m := msg.(type) {
+	// Lists of projects and workspaces are filtered rather than denied so
+	// that the token can still list what it can access.
+	case *pb.ListProjectsResponse:
+		var projects []*pb.Ref_Project
+		for _, p := range m.Projects {
+			if a.project(p.Project) {
+				projects = append(projects, p)
+			}
+		}
+
+		m.Projects = projects
+		return nil
+
+	case *pb.ListWorkspacesResponse:
+		var workspaces []*pb.Workspace
+		for _, ws := range m.Workspaces {
+			if a.workspace(ws.Name) {
+				a.filterWorkspace(ws)
+				workspaces = append(workspaces, ws)
+			}
+		}
+
+		m.Workspaces = workspaces
+		return nil
+
+	case *pb.ListWebhooksResponse:
+		var hooks []*pb.Webhook
+		for _, hook := range m.Webhooks {
+			if a.project(hook.Project.GetProject()) {
+				hooks = append(hooks, hook)
+			}
+		}
+
+		m.Webhooks = hooks
+		return nil
+
+	case *pb.GetWorkspaceResponse:
+		if m.Workspace != nil {
+			a.filterWorkspace(m.Workspace)
+		}
+
+	// Tokens of an organization can create projects, which UpsertProject
+	// adds to the organization. Existing projects must already be in it.
+	case *pb.UpsertProjectRequest:
+		if a.org != "" {
+			return a.checkNewProject(m.Project.GetName())
+		}
+
+	// Tokens of an organization can only get their own organization.
+	case *pb.GetOrganizationRequest:
+		if a.org == "" || !strings.EqualFold(a.org, m.Name) {
+			return status.Errorf(codes.PermissionDenied,
+				"token can't access organization %q", m.Name)
+		}
+
+		return nil
+
+	case *pb.GetOrganizationResponse:
+		return nil
+
+	case *pb.GetJobStreamRequest:
+		return a.checkJob(m.JobId)
+
+	case *pb.CancelJobRequest:
+		return a.checkJob(m.JobId)
+
+	case *pb.GetLogStreamRequest:
+		if id := m.GetDeploymentId(); id != "" {
+			return a.checkDeployment(id)
+		}
+
+	case *pb.ListInstancesRequest:
+		if id := m.GetDeploymentId(); id != "" {
+			return a.checkDeployment(id)
+		}
+
+	case *pb.ExecStreamRequest:
+		if start := m.GetStart(); start != nil {
+			return a.checkDeployment(start.DeploymentId)
+		}
+
+	// Runner config applies to every project.
+	case *pb.ConfigGetRequest:
+		if m.GetRunner() != nil {
+			return status.Errorf(codes.PermissionDenied,
+				"tokens limited to projects or workspaces can't access runner config")
+		}
+
+	case *pb.ConfigSetRequest:
+		for _, v := range m.Variables {
+			if v.GetRunner() != nil {
+				return status.Errorf(codes.PermissionDenied,
+					"tokens limited to projects or workspaces can't access runner config")
+			}
+		}
+
+	// Runners that accept any job would be assigned jobs of other projects,
+	// so the token can only run runners that are targeted by ID, such as
+	// the runner the CLI starts for its own jobs.
+	case *pb.RunnerConfigRequest:
+		if open := m.GetOpen(); open != nil && !open.Runner.GetByIdOnly() {
+			return status.Errorf(codes.PermissionDenied,
+				"tokens limited to projects or workspaces can only register runners "+
+					"that only run jobs targeted to them by ID")
+		}
+
+	case *pb.RunnerJobStreamRequest:
+		if req := m.GetRequest(); req != nil {
+			r, err := a.s.state.RunnerById(req.RunnerId)
+			if err != nil {
+				return err
+			}
+			if !r.ByIdOnly {
+				return status.Errorf(codes.PermissionDenied,
+					"tokens limited to projects or workspaces can only request jobs "+
+						"for runners that only run jobs targeted to them by ID")
+			}
+		}
+	}
+
+	m, ok := msg.(proto.Message)
+	if !ok {
+		return nil
+	}
+
+	return a.checkRefs(proto.MessageReflect(m))
+}
+
+// filterWorkspace removes the applications the token can't access from ws.
+func (a *tokenAccess) filterWorkspace(ws *pb.Workspace) {
+	var apps []*pb.Workspace_Application
+	for _, app := range ws.Applications {
+		if a.project(app.Application.GetProject()) {
+			apps = append(apps, app)
+		}
+	}
+
+	ws.Applications = apps
+}
+
+// checkNewProject checks an upsert of a project by a token of an
+// organization. Projects that don't exist yet can be created if the
+// token's project list allows them.
+func (a *tokenAccess) checkNewProject(name string) error {
+	if a.project(name) {
+		return nil
+	}
+
+	_, err := a.s.state.ProjectGet(&pb.Ref_Project{Project: name})
+	if status.Code(err) != codes.NotFound {
+		if err != nil {
+			return err
+		}
+
+		return status.Errorf(codes.PermissionDenied,
+			"token can't access project %q", name)
+	}
+
+	if a.projects != nil && !a.projects[strings.ToLower(name)] {
+		return status.Errorf(codes.PermissionDenied,
+			"token can't access project %q", name)
+	}
+
+	// The project may have been added to another organization before it
+	// was created.
+	org, err := a.s.state.OrganizationForProject(&pb.Ref_Project{Project: name})
+	if err != nil {
+		return err
+	}
+	if org != nil && !strings.EqualFold(org.Name, a.org) {
+		return status.Errorf(codes.PermissionDenied,
+			"token can't access project %q", name)
+	}
+
+	// The response refers to the new project.
+	a.orgProjects[strings.ToLower(name)] = true
+	return nil
+}
+
+func (a *tokenAccess) checkJob(id string) error {
+	job, err := a.s.state.JobById(id, nil)
+	if err != nil {
+		return err
+	}
+
+	// The handler reports jobs that don't exist.
+	if job == nil {
+		return nil
+	}
+
+	return a.checkRefs(proto.MessageReflect(job.Job))
+}
+
+func (a *tokenAccess) checkDeployment(id string) error {
+	d, err := a.s.state.DeploymentGet(&pb.Ref_Operation{
+		Target: &pb.Ref_Operation_Id{Id: id},
+	})
+	if status.Code(err) == codes.NotFound {
+		// The handler reports deployments that don't exist.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return a.checkRefs(proto.MessageReflect(d))
+}
+
+var (
+	accessRefProjectName     = (&pb.Ref_Project{}).ProtoReflect().Descriptor().FullName()
+	accessRefApplicationName = (&pb.Ref_Application{}).ProtoReflect().Descriptor().FullName()
+	accessRefWorkspaceName   = (&pb.Ref_Workspace{}).ProtoReflect().Descriptor().FullName()
+	accessProjectName        = (&pb.Project{}).ProtoReflect().Descriptor().FullName()
+	accessWorkspaceName      = (&pb.Workspace{}).ProtoReflect().Descriptor().FullName()
+)
+
+func (a *tokenAccess) checkProject(name string) error {
+	if !a.project(name) {
+		return status.Errorf(codes.PermissionDenied,
+			"token can't access project %q", name)
+	}
+
+	return nil
+}
+
+func (a *tokenAccess) checkWorkspace(name string) error {
+	if !a.workspace(name) {
+		return status.Errorf(codes.PermissionDenied,
+			"token can't access workspace %q", name)
+	}
+
+	return nil
+}
+
+// checkRefs checks every project and workspace that m refers to, including
+// in nested messages. Values within Any fields are not checked.
+func (a *tokenAccess) checkRefs(m protoreflect.Message) error {
+	var err error
+	fields := m.Descriptor().Fields()
+	switch m.Descriptor().FullName() {
+	case accessRefProjectName, accessRefApplicationName:
+		err = a.checkProject(m.Get(fields.ByName("project")).String())
+
+	case accessProjectName:
+		err = a.checkProject(m.Get(fields.ByName("name")).String())
+
+	case accessRefWorkspaceName:
+		err = a.checkWorkspace(m.Get(fields.ByName("workspace")).String())
+
+	case accessWorkspaceName:
+		err = a.checkWorkspace(m.Get(fields.ByName("name")).String())
+	}
+	if err != nil {
+		return err
+	}
+
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsList():
+			if fd.Message() == nil {
+				return true
+			}
+
+			list := v.List()
+			for i := 0; i < list.Len() && err == nil; i++ {
+				err = a.checkRefs(list.Get(i).Message())
+			}
+
+		case fd.IsMap():
+			if fd.MapValue().Message() == nil {
+				return true
+			}
+
+			v.Map().Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+				err = a.checkRefs(v.Message())
+				return err == nil
+			})
+
+		case fd.Message() != nil:
+			err = a.checkRefs(v.Message())
+		}
+
+		return err == nil
+	})
+
+	return err
+}
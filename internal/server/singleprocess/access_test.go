@@ -0,0 +1,204 @@
+package singleprocess
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint/internal/server"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	serverptypes "github.com/hashicorp/waypoint/internal/server/ptypes"
+)
+
+func TestServiceAccess_role(t *testing.T) {
+	ctx := context.Background()
+
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(t, err)
+	s := impl.(*service)
+
+	authenticate := func(token, endpoint string) error {
+		effects, ok := server.Effects[endpoint]
+		if !ok {
+			effects = server.DefaultEffects
+		}
+
+		_, err := s.Authenticate(ctx, token, endpoint, effects)
+		return err
+	}
+
+	cases := []struct {
+		Role     pb.Token_Role
+		Endpoint string
+		Allowed  bool
+	}{
+		{pb.Token_ADMIN, "RestoreSnapshot", true},
+		{pb.Token_ADMIN, "QueueJob", true},
+		{pb.Token_ADMIN, "UpsertWebhook", true},
+		{pb.Token_OPERATOR, "QueueJob", true},
+		{pb.Token_OPERATOR, "ListBuilds", true},
+		{pb.Token_OPERATOR, "RestoreSnapshot", false},
+		{pb.Token_OPERATOR, "GenerateLoginToken", false},
+		{pb.Token_OPERATOR, "UpsertWebhook", false},
+		{pb.Token_OPERATOR, "DeleteWebhook", true},
+		{pb.Token_READ_ONLY, "ListBuilds", true},
+		{pb.Token_READ_ONLY, "GetJobStream", true},
+		{pb.Token_READ_ONLY, "QueueJob", false},
+		{pb.Token_READ_ONLY, "ListAuditEvents", false},
+	}
+
+	for _, tt := range cases {
+		t.Run(roleName(tt.Role)+" "+tt.Endpoint, func(t *testing.T) {
+			require := require.New(t)
+
+			token, err := s.newLoginToken(DefaultKeyId, nil, &pb.Token{Role: tt.Role})
+			require.NoError(err)
+
+			err = authenticate(token, tt.Endpoint)
+			if tt.Allowed {
+				require.NoError(err)
+				return
+			}
+
+			require.Error(err)
+			require.Equal(codes.PermissionDenied, status.Code(err))
+		})
+	}
+
+	t.Run("scoped tokens can't call server endpoints", func(t *testing.T) {
+		require := require.New(t)
+
+		token, err := s.newLoginToken(DefaultKeyId, nil, &pb.Token{
+			Role:     pb.Token_OPERATOR,
+			Projects: []string{"p_test"},
+		})
+		require.NoError(err)
+
+		require.NoError(authenticate(token, "QueueJob"))
+
+		err = authenticate(token, "ListSnapshots")
+		require.Error(err)
+		require.Equal(codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("invites carry the role and scope", func(t *testing.T) {
+		require := require.New(t)
+
+		invite, err := s.newInviteToken(time.Minute, DefaultKeyId, nil, &pb.Token{
+			Role:       pb.Token_READ_ONLY,
+			Projects:   []string{"p_test"},
+			Workspaces: []string{"w_test"},
+		})
+		require.NoError(err)
+
+		token, err := s.ExchangeInvite(DefaultKeyId, invite)
+		require.NoError(err)
+
+		_, body, err := s.DecodeToken(token)
+		require.NoError(err)
+		require.Equal(pb.Token_READ_ONLY, body.Role)
+		require.Equal([]string{"p_test"}, body.Projects)
+		require.Equal([]string{"w_test"}, body.Workspaces)
+	})
+}
+
+func TestServiceAccess_scope(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+	s := impl.(*service)
+
+	// Create two projects with a job each
+	job := serverptypes.TestJobNew(t, nil)
+	TestApp(t, client, job.Application)
+	queueResp, err := client.QueueJob(ctx, &pb.QueueJobRequest{Job: job})
+	require.NoError(err)
+
+	otherJob := serverptypes.TestJobNew(t, &pb.Job{
+		Application: &pb.Ref_Application{Project: "p_other", Application: "a_test"},
+	})
+	TestApp(t, client, otherJob.Application)
+	otherResp, err := client.QueueJob(ctx, &pb.QueueJobRequest{Job: otherJob})
+	require.NoError(err)
+
+	// Our token can only access p_test, in any case
+	token, err := s.newLoginToken(DefaultKeyId, nil, &pb.Token{
+		Role:     pb.Token_OPERATOR,
+		Projects: []string{"P_TEST"},
+	})
+	require.NoError(err)
+	check, err := s.AuthorizeMessages(ctx, token, "QueueJob")
+	require.NoError(err)
+	require.NotNil(check)
+
+	// Messages that refer to the project, or to nothing, are allowed
+	require.NoError(check(&pb.QueueJobRequest{Job: job}))
+	require.NoError(check(&pb.GetJobStreamRequest{JobId: queueResp.JobId}))
+	require.NoError(check(&pb.GetVersionInfoResponse{}))
+
+	// Messages that refer to other projects are denied
+	err = check(&pb.QueueJobRequest{Job: otherJob})
+	require.Error(err)
+	require.Equal(codes.PermissionDenied, status.Code(err))
+
+	err = check(&pb.CancelJobRequest{JobId: otherResp.JobId})
+	require.Error(err)
+	require.Equal(codes.PermissionDenied, status.Code(err))
+
+	err = check(&pb.ConfigSetRequest{Variables: []*pb.ConfigVar{{
+		Scope: &pb.ConfigVar_Runner{Runner: &pb.Ref_Runner{
+			Target: &pb.Ref_Runner_Any{Any: &pb.Ref_RunnerAny{}},
+		}},
+		Name: "foo",
+	}}})
+	require.Error(err)
+	require.Equal(codes.PermissionDenied, status.Code(err))
+
+	// Lists of projects are filtered
+	list := &pb.ListProjectsResponse{Projects: []*pb.Ref_Project{
+		{Project: "p_test"},
+		{Project: "p_other"},
+	}}
+	require.NoError(check(list))
+	require.Len(list.Projects, 1)
+	require.Equal("p_test", list.Projects[0].Project)
+
+	// Empty names aren't wildcards
+	noProject := serverptypes.TestJobNew(t, nil)
+	noProject.Application.Project = ""
+	err = check(&pb.QueueJobRequest{Job: noProject})
+	require.Error(err)
+	require.Equal(codes.PermissionDenied, status.Code(err))
+
+	// Tokens limited to workspaces can't write to other workspaces,
+	// including the empty one
+	token, err = s.newLoginToken(DefaultKeyId, nil, &pb.Token{
+		Role:       pb.Token_OPERATOR,
+		Workspaces: []string{"w_test"},
+	})
+	require.NoError(err)
+	check, err = s.AuthorizeMessages(ctx, token, "QueueJob")
+	require.NoError(err)
+	require.NoError(check(&pb.QueueJobRequest{Job: job}))
+	for _, ws := range []string{"w_other", ""} {
+		otherWs := serverptypes.TestJobNew(t, nil)
+		otherWs.Workspace.Workspace = ws
+		err = check(&pb.QueueJobRequest{Job: otherWs})
+		require.Error(err)
+		require.Equal(codes.PermissionDenied, status.Code(err))
+	}
+
+	// Tokens for every project aren't checked
+	token, err = s.NewLoginToken(DefaultKeyId, nil, nil)
+	require.NoError(err)
+	check, err = s.AuthorizeMessages(ctx, token, "QueueJob")
+	require.NoError(err)
+	require.Nil(check)
+}
@@ -0,0 +1,14 @@
+// +build !windows
+
+package singleprocess
+
+import (
+	"io"
+	"log/syslog"
+)
+
+// auditSyslog returns a writer that sends audit events to the local
+// syslog daemon.
+func auditSyslog() (io.Writer, error) {
+	return syslog.New(syslog.LOG_NOTICE|syslog.LOG_AUTH, "waypoint")
+}
@@ -0,0 +1,13 @@
+// +build windows
+
+package singleprocess
+
+import (
+	"errors"
+	"io"
+)
+
+// auditSyslog returns an error since syslog isn't available on Windows.
+func auditSyslog() (io.Writer, error) {
+	return nil, errors.New("syslog is not supported on Windows")
+}
@@ -10,14 +10,17 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/mr-tron/base58"
 	"github.com/pkg/errors"
 	"golang.org/x/crypto/blake2b"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
+	"github.com/hashicorp/waypoint/internal/server"
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
 )
 
@@ -40,6 +43,10 @@ const (
 
 	// A prefix added to the key id when looking up the HMAC key from the database
 	dbKeyPrefix = "hmacKey:"
+
+	// How long the records of expired tokens are kept so that they can be
+	// listed with ListTokens.
+	tokenRecordRetention = 7 * 24 * time.Hour
 )
 
 var (
@@ -69,6 +76,14 @@ func (s *service) DecodeToken(token string) (*pb.TokenTransport, *pb.Token, erro
 		return nil, nil, errors.Wrapf(ErrInvalidToken, "unknown key")
 	}
 
+	// Keys that were rotated are only valid for some time after.
+	if key.ValidUntil != nil {
+		vt := time.Unix(key.ValidUntil.Seconds, int64(key.ValidUntil.Nanos))
+		if vt.Before(time.Now()) {
+			return nil, nil, errors.Wrapf(ErrInvalidToken, "token is signed with a retired key")
+		}
+	}
+
 	h, err := blake2b.New256(key.Key)
 	if err != nil {
 		return nil, nil, err
@@ -89,6 +104,11 @@ func (s *service) DecodeToken(token string) (*pb.TokenTransport, *pb.Token, erro
 		return nil, nil, err
 	}
 
+	// Tokens created before tokens had roles are admin tokens.
+	if body.Role == pb.Token_ROLE_UNSPECIFIED {
+		body.Role = pb.Token_ADMIN
+	}
+
 	if body.ValidUntil != nil {
 		vt := time.Unix(body.ValidUntil.Seconds, int64(body.ValidUntil.Nanos))
 
@@ -98,43 +118,100 @@ func (s *service) DecodeToken(token string) (*pb.TokenTransport, *pb.Token, erro
 		}
 	}
 
+	info, err := s.state.TokenGet(base58.Encode(body.TokenId))
+	if err != nil {
+		return nil, nil, err
+	}
+	if info != nil && info.RevokedAt != nil {
+		return nil, nil, errors.Wrapf(ErrInvalidToken, "token has been revoked")
+	}
+
 	return &tt, &body, nil
 }
 
+type tokenBodyKey struct{}
+
+// tokenBody returns the decoded body of token. If Authenticate already
+// decoded it for the RPC of ctx, that body is used so that the token
+// record isn't read again.
+func (s *service) tokenBody(ctx context.Context, token string) (*pb.Token, error) {
+	if body, ok := ctx.Value(tokenBodyKey{}).(*pb.Token); ok {
+		return body, nil
+	}
+
+	_, body, err := s.DecodeToken(token)
+	return body, err
+}
+
+// requestToken returns the body of the token that authenticated the
+// request in ctx. This returns nil if the request wasn't authenticated.
+func (s *service) requestToken(ctx context.Context) *pb.Token {
+	if body, ok := ctx.Value(tokenBodyKey{}).(*pb.Token); ok {
+		return body
+	}
+
+	// Public endpoints aren't authenticated, so the token is only decoded
+	// if they need it.
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	values := md["authorization"]
+	if len(values) == 0 {
+		return nil
+	}
+
+	_, body, err := s.DecodeToken(values[0])
+	if err != nil {
+		return nil
+	}
+
+	return body
+}
+
 // Authenticate checks if the given endpoint should be allowed. This is called during a
 // gRPC request. Effects is some information about the endpoint, at present these are
 // either ["readonly"] or ["mutable"] to indicate if the endpoint will be only reading
 // data or also mutating it.
-func (s *service) Authenticate(ctx context.Context, token, endpoint string, effects []string) error {
+func (s *service) Authenticate(ctx context.Context, token, endpoint string, effects []string) (context.Context, error) {
 	// We always allow ConvertInviteToken so that folks can actually get authentication data
-	if endpoint == "ConvertInviteToken" || endpoint == "BootstrapToken" || endpoint == "GetVersionInfo" {
-		return nil
+	if publicEndpoints[endpoint] {
+		return ctx, nil
 	}
 
 	if token == "" {
-		return status.Errorf(codes.Unauthenticated, "Authorization token is not supplied")
+		return nil, status.Errorf(codes.Unauthenticated, "Authorization token is not supplied")
 	}
 
 	_, body, err := s.DecodeToken(token)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if !body.Login {
-		return ErrInvalidToken
+		return nil, ErrInvalidToken
 	}
 
 	// If this is an entrypoint token then we can only access entrypoint APIs.
 	if body.Entrypoint != nil && !strings.HasPrefix(endpoint, "Entrypoint") {
-		return status.Errorf(codes.Unauthenticated, "Unauthorized endpoint")
+		return nil, status.Errorf(codes.Unauthenticated, "Unauthorized endpoint")
 	}
 
-	// TODO When we have a user model, this is where you'll check for the user.
-	if body.User != DefaultUser {
-		return ErrInvalidToken
+	// Tokens are for the default user unless they were created by an
+	// OIDC login, which sets the user from the identity provider.
+	if body.User == "" {
+		return nil, ErrInvalidToken
 	}
 
-	return nil
+	if err := checkRole(body, endpoint, effects); err != nil {
+		return nil, err
+	}
+
+	// Keep the body so that the rest of the RPC doesn't decode the token
+	// again, which reads the token record each time.
+	ctx = context.WithValue(ctx, tokenBodyKey{}, body)
+	return server.ContextWithTokenId(ctx, base58.Encode(body.TokenId)), nil
 }
 
 // Generate a new token by signing the data in body.
@@ -173,9 +250,51 @@ func (s *service) GenerateToken(keyId string, metadata map[string]string, body *
 	buf.WriteString(tokenMagic)
 	buf.Write(ttData)
 
+	// Record the token so that it can be listed and revoked. Entrypoint
+	// tokens are created for every deployment, so they aren't recorded.
+	if body.Entrypoint == nil {
+		if err := s.recordToken(keyId, body); err != nil {
+			return "", err
+		}
+	}
+
 	return base58.Encode(buf.Bytes()), nil
 }
 
+// recordToken records a token that was created and prunes the records of
+// tokens that expired long ago.
+func (s *service) recordToken(keyId string, body *pb.Token) error {
+	if _, err := s.state.TokenPrune(time.Now().Add(-tokenRecordRetention)); err != nil {
+		return err
+	}
+
+	return s.state.TokenPut(&pb.TokenInfo{
+		Id:           base58.Encode(body.TokenId),
+		KeyId:        keyId,
+		User:         body.User,
+		Invite:       body.Invite,
+		Role:         body.Role,
+		Projects:     body.Projects,
+		Workspaces:   body.Workspaces,
+		Organization: body.Organization,
+		CreatedAt:    ptypes.TimestampNow(),
+		ValidUntil:   body.ValidUntil,
+	})
+}
+
+// tokenKeyId returns the ID of the key to sign new tokens with.
+func (s *service) tokenKeyId() (string, error) {
+	id, err := s.state.HMACKeyCurrent()
+	if err != nil {
+		return "", err
+	}
+	if id == "" {
+		id = DefaultKeyId
+	}
+
+	return id, nil
+}
+
 // Create a new login token.
 // keyId controls which key is used to sign the key (key values are generated lazily).
 // metadata is attached to the token transport as configuration style information
@@ -184,11 +303,33 @@ func (s *service) NewLoginToken(
 	metadata map[string]string,
 	entrypoint *pb.Token_Entrypoint,
 ) (string, error) {
+	return s.newLoginToken(keyId, metadata, &pb.Token{
+		Entrypoint: entrypoint,
+		Role:       pb.Token_ADMIN,
+	})
+}
+
+// newLoginToken creates a new login token with the expiry, entrypoint, role
+// and scope of access.
+func (s *service) newLoginToken(
+	keyId string,
+	metadata map[string]string,
+	access *pb.Token,
+) (string, error) {
+	if err := checkTokenRole(access.Role); err != nil {
+		return "", err
+	}
+
 	var body pb.Token
 	body.Login = true
 	body.User = DefaultUser
 	body.TokenId = make([]byte, 16)
-	body.Entrypoint = entrypoint
+	body.ValidUntil = access.ValidUntil
+	body.Entrypoint = access.Entrypoint
+	body.Role = access.Role
+	body.Projects = access.Projects
+	body.Workspaces = access.Workspaces
+	body.Organization = access.Organization
 
 	_, err := io.ReadFull(rand.Reader, body.TokenId)
 	if err != nil {
@@ -198,13 +339,53 @@ func (s *service) NewLoginToken(
 	return s.GenerateToken(keyId, metadata, &body)
 }
 
-// Create a new login token. This is just a gRPC wrapper around NewLoginToken.
-func (s *service) GenerateLoginToken(ctx context.Context, _ *empty.Empty) (*pb.NewTokenResponse, error) {
-	token, err := s.NewLoginToken(DefaultKeyId, nil, nil)
+// Create a new login token. This is just a gRPC wrapper around newLoginToken.
+func (s *service) GenerateLoginToken(ctx context.Context, req *pb.LoginTokenRequest) (*pb.NewTokenResponse, error) {
+	access := &pb.Token{
+		Role:         req.Role,
+		Projects:     req.Projects,
+		Workspaces:   req.Workspaces,
+		Organization: req.Organization,
+	}
+	if err := checkTokenRole(req.Role); err != nil {
+		return nil, err
+	}
+	if err := s.checkTokenOrganization(req.Organization); err != nil {
+		return nil, err
+	}
+
+	var dur time.Duration
+	if req.Duration != "" {
+		var err error
+		dur, err = time.ParseDuration(req.Duration)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid duration: %s", err)
+		}
+		if dur <= 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "duration must be positive")
+		}
+
+		access.ValidUntil, err = ptypes.TimestampProto(time.Now().Add(dur))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	keyId, err := s.tokenKeyId()
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := s.newLoginToken(keyId, nil, access)
 	if err != nil {
 		return nil, err
 	}
 
+	detail := "login token"
+	if dur > 0 {
+		detail += " valid for " + dur.String()
+	}
+	s.audit(ctx, auditOpTokenCreate, "", detail+tokenAccessDetail(access))
 	return &pb.NewTokenResponse{Token: token}, nil
 }
 
@@ -217,10 +398,32 @@ func (s *service) NewInviteToken(
 	metadata map[string]string,
 	entrypoint *pb.Token_Entrypoint,
 ) (string, error) {
+	return s.newInviteToken(duration, keyId, metadata, &pb.Token{
+		Entrypoint: entrypoint,
+		Role:       pb.Token_ADMIN,
+	})
+}
+
+// newInviteToken creates a new invite token for a login token with the
+// entrypoint, role and scope of access.
+func (s *service) newInviteToken(
+	duration time.Duration,
+	keyId string,
+	metadata map[string]string,
+	access *pb.Token,
+) (string, error) {
+	if err := checkTokenRole(access.Role); err != nil {
+		return "", err
+	}
+
 	var body pb.Token
 	body.Invite = true
 	body.TokenId = make([]byte, 16)
-	body.Entrypoint = entrypoint
+	body.Entrypoint = access.Entrypoint
+	body.Role = access.Role
+	body.Projects = access.Projects
+	body.Workspaces = access.Workspaces
+	body.Organization = access.Organization
 
 	now := time.Now().UTC().Add(duration)
 	body.ValidUntil = &timestamp.Timestamp{
@@ -243,11 +446,40 @@ func (s *service) GenerateInviteToken(ctx context.Context, req *pb.InviteTokenRe
 		return nil, err
 	}
 
-	token, err := s.NewInviteToken(dur, DefaultKeyId, nil, req.Entrypoint)
+	if err := checkTokenRole(req.Role); err != nil {
+		return nil, err
+	}
+	if err := s.checkTokenOrganization(req.Organization); err != nil {
+		return nil, err
+	}
+
+	keyId, err := s.tokenKeyId()
 	if err != nil {
 		return nil, err
 	}
 
+	token, err := s.newInviteToken(dur, keyId, nil, &pb.Token{
+		Entrypoint:   req.Entrypoint,
+		Role:         req.Role,
+		Projects:     req.Projects,
+		Workspaces:   req.Workspaces,
+		Organization: req.Organization,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	detail := "invite token valid for " + dur.String()
+	if req.Entrypoint != nil {
+		detail = "entrypoint " + detail + " for deployment " + req.Entrypoint.DeploymentId
+	}
+	s.audit(ctx, auditOpTokenCreate, "", detail+tokenAccessDetail(&pb.Token{
+		Role:         req.Role,
+		Projects:     req.Projects,
+		Workspaces:   req.Workspaces,
+		Organization: req.Organization,
+	}))
+
 	return &pb.NewTokenResponse{Token: token}, nil
 }
 
@@ -262,16 +494,30 @@ func (s *service) ExchangeInvite(keyId, invite string) (string, error) {
 		return "", errors.Wrapf(ErrInvalidToken, "not an invite token")
 	}
 
-	return s.NewLoginToken(keyId, tt.Metadata, body.Entrypoint)
+	// The login token has the access of the invite but doesn't expire
+	// with it.
+	return s.newLoginToken(keyId, tt.Metadata, &pb.Token{
+		Entrypoint:   body.Entrypoint,
+		Role:         body.Role,
+		Projects:     body.Projects,
+		Workspaces:   body.Workspaces,
+		Organization: body.Organization,
+	})
 }
 
 // Given an invite token, validate it and return a login token. This is a gRPC wrapper around ExchangeInvite.
 func (s *service) ConvertInviteToken(ctx context.Context, req *pb.ConvertInviteTokenRequest) (*pb.NewTokenResponse, error) {
-	token, err := s.ExchangeInvite(DefaultKeyId, req.Token)
+	keyId, err := s.tokenKeyId()
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := s.ExchangeInvite(keyId, req.Token)
 	if err != nil {
 		return nil, err
 	}
 
+	s.audit(ctx, auditOpTokenCreate, "", "login token from invite")
 	return &pb.NewTokenResponse{Token: token}, nil
 }
 
@@ -285,9 +531,139 @@ func (s *service) BootstrapToken(ctx context.Context, req *empty.Empty) (*pb.New
 		return nil, err
 	}
 
+	s.audit(ctx, auditOpTokenCreate, "", "bootstrap token")
 	return &pb.NewTokenResponse{Token: token}, nil
 }
 
 func (s *service) Bootstrapped() bool {
 	return !s.state.HMACKeyEmpty()
 }
+
+// checkTokenRole checks the role of a new token. New tokens must have a
+// role so that a request that leaves it unset doesn't create an admin
+// token.
+func checkTokenRole(role pb.Token_Role) error {
+	if role == pb.Token_ROLE_UNSPECIFIED {
+		return status.Errorf(codes.InvalidArgument, "a role is required for new tokens")
+	}
+	if _, ok := pb.Token_Role_name[int32(role)]; !ok {
+		return status.Errorf(codes.InvalidArgument, "unknown role %d", role)
+	}
+
+	return nil
+}
+
+// tokenAccessDetail describes the role and scope of a token for the audit
+// log.
+func tokenAccessDetail(access *pb.Token) string {
+	detail := " with the " + roleName(access.Role) + " role"
+	if len(access.Projects) > 0 {
+		detail += ", projects " + strings.Join(access.Projects, ", ")
+	}
+	if len(access.Workspaces) > 0 {
+		detail += ", workspaces " + strings.Join(access.Workspaces, ", ")
+	}
+	if access.Organization != "" {
+		detail += ", organization " + access.Organization
+	}
+
+	return detail
+}
+
+func (s *service) ListTokens(
+	ctx context.Context,
+	req *pb.ListTokensRequest,
+) (*pb.ListTokensResponse, error) {
+	tokens, err := s.state.TokenList()
+	if err != nil {
+		return nil, err
+	}
+
+	// Tokens created before tokens had roles are admin tokens.
+	for _, info := range tokens {
+		if info.Role == pb.Token_ROLE_UNSPECIFIED {
+			info.Role = pb.Token_ADMIN
+		}
+	}
+
+	if !req.All {
+		now := time.Now()
+		var active []*pb.TokenInfo
+		for _, info := range tokens {
+			if info.RevokedAt != nil {
+				continue
+			}
+			if vu := info.ValidUntil; vu != nil && time.Unix(vu.Seconds, int64(vu.Nanos)).Before(now) {
+				continue
+			}
+
+			active = append(active, info)
+		}
+
+		tokens = active
+	}
+
+	return &pb.ListTokensResponse{Tokens: tokens}, nil
+}
+
+func (s *service) RevokeToken(
+	ctx context.Context,
+	req *pb.RevokeTokenRequest,
+) (*empty.Empty, error) {
+	id, err := base58.Decode(req.Id)
+	if err != nil || len(id) != 16 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid token ID %q", req.Id)
+	}
+
+	// Tokens created before tokens were recorded have no record, but can
+	// still be revoked by ID.
+	info, err := s.state.TokenGet(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		info = &pb.TokenInfo{Id: req.Id}
+	}
+	if info.RevokedAt != nil {
+		return &empty.Empty{}, nil
+	}
+
+	info.RevokedAt = ptypes.TimestampNow()
+	if err := s.state.TokenPut(info); err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, auditOpTokenRevoke, req.Id, "")
+	return &empty.Empty{}, nil
+}
+
+func (s *service) RotateTokenKey(
+	ctx context.Context,
+	req *pb.RotateTokenKeyRequest,
+) (*pb.RotateTokenKeyResponse, error) {
+	var retireAt time.Time
+	if req.PreviousKeyDuration != "" {
+		dur, err := time.ParseDuration(req.PreviousKeyDuration)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid previous key duration: %s", err)
+		}
+		if dur < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "previous key duration must not be negative")
+		}
+
+		retireAt = time.Now().Add(dur)
+	}
+
+	key, err := s.state.HMACKeyRotate(hmacKeySize, retireAt)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := "previous keys valid until their tokens expire"
+	if !retireAt.IsZero() {
+		detail = "previous keys valid for " + req.PreviousKeyDuration
+	}
+	s.audit(ctx, auditOpTokenKeyRotate, key.Id, detail)
+
+	return &pb.RotateTokenKeyResponse{KeyId: key.Id}, nil
+}
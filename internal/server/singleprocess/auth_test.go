@@ -15,6 +15,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/hashicorp/waypoint/internal/server"
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
 )
 
@@ -44,9 +45,15 @@ func TestServiceAuth(t *testing.T) {
 		assert.Equal(t, DefaultUser, body.User)
 		assert.Equal(t, md, tt.Metadata)
 
-		err = s.Authenticate(context.Background(), token, "test", nil)
+		ctx, err := s.Authenticate(context.Background(), token, "test", nil)
 		require.NoError(t, err)
 
+		// The rest of the RPC uses the body that was decoded
+		reqBody := s.requestToken(ctx)
+		require.NotNil(t, reqBody)
+		assert.Equal(t, body.TokenId, reqBody.TokenId)
+		assert.Equal(t, base58.Encode(body.TokenId), server.TokenIdFromContext(ctx))
+
 		// Now corrupt the token and check that validation fails
 		data := []byte(token)
 
@@ -64,12 +71,12 @@ func TestServiceAuth(t *testing.T) {
 		require.NoError(t, err)
 
 		{
-			err := s.Authenticate(context.Background(), token, "EntrypointConfig", nil)
+			_, err := s.Authenticate(context.Background(), token, "EntrypointConfig", nil)
 			require.NoError(t, err)
 		}
 
 		{
-			err := s.Authenticate(context.Background(), token, "UpsertDeployment", nil)
+			_, err := s.Authenticate(context.Background(), token, "UpsertDeployment", nil)
 			require.Error(t, err)
 		}
 	})
@@ -180,3 +187,175 @@ func TestServiceBootstrapToken(t *testing.T) {
 		require.Nil(resp)
 	}
 }
+
+func TestServiceToken(t *testing.T) {
+	ctx := context.Background()
+
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(t, err)
+	s := impl.(*service)
+
+	t.Run("create, list, and revoke", func(t *testing.T) {
+		require := require.New(t)
+
+		resp, err := s.GenerateLoginToken(ctx, &pb.LoginTokenRequest{
+			Duration: "1h",
+			Role:     pb.Token_READ_ONLY,
+			Projects: []string{"p_test"},
+		})
+		require.NoError(err)
+
+		_, body, err := s.DecodeToken(resp.Token)
+		require.NoError(err)
+		require.NotNil(body.ValidUntil)
+		require.Equal(pb.Token_READ_ONLY, body.Role)
+		id := base58.Encode(body.TokenId)
+
+		// The token is listed with its metadata
+		list, err := s.ListTokens(ctx, &pb.ListTokensRequest{})
+		require.NoError(err)
+		require.Len(list.Tokens, 1)
+		require.Equal(id, list.Tokens[0].Id)
+		require.Equal(DefaultKeyId, list.Tokens[0].KeyId)
+		require.Equal(pb.Token_READ_ONLY, list.Tokens[0].Role)
+		require.Equal([]string{"p_test"}, list.Tokens[0].Projects)
+		require.NotNil(list.Tokens[0].CreatedAt)
+		require.NotNil(list.Tokens[0].ValidUntil)
+
+		// Revoke it
+		_, err = s.Authenticate(ctx, resp.Token, "ListBuilds", []string{"readonly"})
+		require.NoError(err)
+		_, err = s.RevokeToken(ctx, &pb.RevokeTokenRequest{Id: id})
+		require.NoError(err)
+		_, err = s.Authenticate(ctx, resp.Token, "ListBuilds", []string{"readonly"})
+		require.Error(err)
+		require.Equal(codes.Unauthenticated, status.Code(err))
+
+		// Revoked tokens are only listed with all
+		list, err = s.ListTokens(ctx, &pb.ListTokensRequest{})
+		require.NoError(err)
+		require.Empty(list.Tokens)
+		list, err = s.ListTokens(ctx, &pb.ListTokensRequest{All: true})
+		require.NoError(err)
+		require.Len(list.Tokens, 1)
+		require.NotNil(list.Tokens[0].RevokedAt)
+	})
+
+	t.Run("tokens without a TTL don't expire", func(t *testing.T) {
+		require := require.New(t)
+
+		resp, err := s.GenerateLoginToken(ctx, &pb.LoginTokenRequest{Role: pb.Token_ADMIN})
+		require.NoError(err)
+
+		_, body, err := s.DecodeToken(resp.Token)
+		require.NoError(err)
+		require.Nil(body.ValidUntil)
+		require.Equal(pb.Token_ADMIN, body.Role)
+	})
+
+	t.Run("tokens without a role are admin tokens", func(t *testing.T) {
+		require := require.New(t)
+
+		// Tokens created before tokens had roles have no role
+		token, err := s.GenerateToken(DefaultKeyId, nil, &pb.Token{
+			Login:   true,
+			User:    DefaultUser,
+			TokenId: []byte("0123456789abcdef"),
+		})
+		require.NoError(err)
+
+		_, body, err := s.DecodeToken(token)
+		require.NoError(err)
+		require.Equal(pb.Token_ADMIN, body.Role)
+
+		// New tokens must have a role
+		_, err = s.GenerateLoginToken(ctx, &pb.LoginTokenRequest{Role: pb.Token_ADMIN})
+		require.Error(err)
+		require.Equal(codes.InvalidArgument, status.Code(err))
+
+		_, err = s.GenerateInviteToken(ctx, &pb.InviteTokenRequest{Duration: "1h"})
+		require.Error(err)
+		require.Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("expired tokens are rejected", func(t *testing.T) {
+		require := require.New(t)
+
+		resp, err := s.GenerateLoginToken(ctx, &pb.LoginTokenRequest{
+			Duration: "1s",
+			Role:     pb.Token_ADMIN,
+		})
+		require.NoError(err)
+
+		time.Sleep(2 * time.Second)
+		_, _, err = s.DecodeToken(resp.Token)
+		require.Error(err)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		require := require.New(t)
+
+		_, err := s.GenerateLoginToken(ctx, &pb.LoginTokenRequest{
+			Duration: "forever",
+			Role:     pb.Token_ADMIN,
+		})
+		require.Error(err)
+		require.Equal(codes.InvalidArgument, status.Code(err))
+
+		_, err = s.RevokeToken(ctx, &pb.RevokeTokenRequest{Id: "nope"})
+		require.Error(err)
+		require.Equal(codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestServiceToken_rotateKey(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("previous tokens stay valid", func(t *testing.T) {
+		require := require.New(t)
+
+		impl, err := New(WithDB(testDB(t)))
+		require.NoError(err)
+		s := impl.(*service)
+
+		old, err := s.GenerateLoginToken(ctx, &pb.LoginTokenRequest{Role: pb.Token_ADMIN})
+		require.NoError(err)
+
+		resp, err := s.RotateTokenKey(ctx, &pb.RotateTokenKeyRequest{
+			PreviousKeyDuration: "1h",
+		})
+		require.NoError(err)
+		require.Equal("k2", resp.KeyId)
+
+		// New tokens use the new key
+		token, err := s.GenerateLoginToken(ctx, &pb.LoginTokenRequest{Role: pb.Token_ADMIN})
+		require.NoError(err)
+		tt, _, err := s.DecodeToken(token.Token)
+		require.NoError(err)
+		require.Equal("k2", tt.KeyId)
+
+		// Old tokens still work
+		tt, _, err = s.DecodeToken(old.Token)
+		require.NoError(err)
+		require.Equal(DefaultKeyId, tt.KeyId)
+	})
+
+	t.Run("retired keys are rejected", func(t *testing.T) {
+		require := require.New(t)
+
+		impl, err := New(WithDB(testDB(t)))
+		require.NoError(err)
+		s := impl.(*service)
+
+		old, err := s.GenerateLoginToken(ctx, &pb.LoginTokenRequest{Role: pb.Token_ADMIN})
+		require.NoError(err)
+
+		_, err = s.RotateTokenKey(ctx, &pb.RotateTokenKeyRequest{
+			PreviousKeyDuration: "0s",
+		})
+		require.NoError(err)
+
+		_, _, err = s.DecodeToken(old.Token)
+		require.Error(err)
+	})
+}
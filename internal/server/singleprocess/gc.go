@@ -0,0 +1,331 @@
+package singleprocess
+
+import (
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hashicorp/go-hclog"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// gcRecord is a build, artifact, deployment, or release that garbage
+// collection may delete.
+type gcRecord struct {
+	id        string
+	workspace string
+	sequence  uint64
+	status    *pb.Status
+
+	// deletable is false if the record has physical resources that
+	// haven't been destroyed. Deleting the record would orphan them.
+	deletable bool
+
+	// needed is true if a record that is kept refers to this record,
+	// such as the artifact of a deployment that is kept.
+	needed bool
+}
+
+// gcSelect returns the IDs of the records the policy doesn't keep. For
+// each workspace, the latest record and latest successful record are
+// always kept so that sequence numbers aren't reused and operations that
+// use the latest build or artifact keep working. Records that are running
+// aren't deleted either.
+func gcSelect(records []*gcRecord, policy *pb.RetentionPolicy, now time.Time) []string {
+	byWorkspace := map[string][]*gcRecord{}
+	for _, r := range records {
+		byWorkspace[r.workspace] = append(byWorkspace[r.workspace], r)
+	}
+
+	maxAge := time.Duration(policy.MaxAgeDays) * 24 * time.Hour
+
+	var result []string
+	for _, list := range byWorkspace {
+		sort.Slice(list, func(i, j int) bool {
+			return list[i].sequence > list[j].sequence
+		})
+
+		latestSuccess := false
+		for i, r := range list {
+			keep := i == 0 || uint32(i) < policy.KeepLast || r.needed || !r.deletable
+
+			if r.status.GetState() == pb.Status_SUCCESS {
+				keep = keep || !latestSuccess
+				latestSuccess = true
+			}
+
+			// Running records have no complete time.
+			completed, err := ptypes.Timestamp(r.status.GetCompleteTime())
+			if err != nil {
+				keep = true
+			} else if maxAge > 0 && now.Sub(completed) < maxAge {
+				keep = true
+			}
+
+			if !keep {
+				result = append(result, r.id)
+			}
+		}
+	}
+
+	return result
+}
+
+// gcPolicyEnabled returns true if the policy deletes anything.
+func gcPolicyEnabled(policy *pb.RetentionPolicy) bool {
+	return policy != nil && (policy.KeepLast > 0 || policy.MaxAgeDays > 0)
+}
+
+// collectGarbage deletes the records of a project, or of every project if
+// ref is nil, that their retention policies don't keep. If dryRun is true,
+// the records are only counted.
+func (s *service) collectGarbage(
+	log hclog.Logger,
+	ref *pb.Ref_Project,
+	dryRun bool,
+	now time.Time,
+) (*pb.RunGCResponse, error) {
+	projects := []*pb.Ref_Project{ref}
+	if ref == nil {
+		var err error
+		projects, err = s.state.ProjectList()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var result pb.RunGCResponse
+	for _, projectRef := range projects {
+		policy, err := s.state.RetentionPolicyGet(projectRef)
+		if err != nil {
+			return nil, err
+		}
+		if !gcPolicyEnabled(policy) {
+			continue
+		}
+
+		project, err := s.state.ProjectGet(projectRef)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, app := range project.Applications {
+			appRef := &pb.Ref_Application{
+				Project:     project.Name,
+				Application: app.Name,
+			}
+
+			if err := s.collectAppGarbage(log, appRef, policy, dryRun, now, &result); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &result, nil
+}
+
+// collectAppGarbage collects the records of a single application and adds
+// the number deleted to result. Releases are selected first since a release
+// that is kept keeps its deployment, which keeps its artifact, which keeps
+// its build.
+func (s *service) collectAppGarbage(
+	log hclog.Logger,
+	ref *pb.Ref_Application,
+	policy *pb.RetentionPolicy,
+	dryRun bool,
+	now time.Time,
+	result *pb.RunGCResponse,
+) error {
+	releases, err := s.state.ReleaseList(ref)
+	if err != nil {
+		return err
+	}
+	deployments, err := s.state.DeploymentList(ref)
+	if err != nil {
+		return err
+	}
+	artifacts, err := s.state.ArtifactList(ref)
+	if err != nil {
+		return err
+	}
+	builds, err := s.state.BuildList(ref)
+	if err != nil {
+		return err
+	}
+
+	// Releases
+	var records []*gcRecord
+	for _, v := range releases {
+		records = append(records, &gcRecord{
+			id:        v.Id,
+			workspace: v.Workspace.GetWorkspace(),
+			sequence:  v.Sequence,
+			status:    v.Status,
+			deletable: gcDestroyed(v.State, v.Status),
+		})
+	}
+	deleteReleases := gcSelect(records, policy, now)
+	needed := gcKept(records, deleteReleases, func(i int) string {
+		return releases[i].DeploymentId
+	})
+
+	// Deployments
+	records = nil
+	for _, v := range deployments {
+		records = append(records, &gcRecord{
+			id:        v.Id,
+			workspace: v.Workspace.GetWorkspace(),
+			sequence:  v.Sequence,
+			status:    v.Status,
+			deletable: gcDestroyed(v.State, v.Status),
+			needed:    needed[v.Id],
+		})
+	}
+	deleteDeployments := gcSelect(records, policy, now)
+	needed = gcKept(records, deleteDeployments, func(i int) string {
+		return deployments[i].ArtifactId
+	})
+
+	// Artifacts
+	records = nil
+	for _, v := range artifacts {
+		records = append(records, &gcRecord{
+			id:        v.Id,
+			workspace: v.Workspace.GetWorkspace(),
+			sequence:  v.Sequence,
+			status:    v.Status,
+			deletable: true,
+			needed:    needed[v.Id],
+		})
+	}
+	deleteArtifacts := gcSelect(records, policy, now)
+	needed = gcKept(records, deleteArtifacts, func(i int) string {
+		return artifacts[i].BuildId
+	})
+
+	// Builds
+	records = nil
+	for _, v := range builds {
+		records = append(records, &gcRecord{
+			id:        v.Id,
+			workspace: v.Workspace.GetWorkspace(),
+			sequence:  v.Sequence,
+			status:    v.Status,
+			deletable: true,
+			needed:    needed[v.Id],
+		})
+	}
+	deleteBuilds := gcSelect(records, policy, now)
+
+	result.Releases += uint32(len(deleteReleases))
+	result.Deployments += uint32(len(deleteDeployments))
+	result.Artifacts += uint32(len(deleteArtifacts))
+	result.Builds += uint32(len(deleteBuilds))
+	if dryRun {
+		return nil
+	}
+
+	// Delete the records that refer to others first so that an error
+	// partway through never leaves a record referring to a deleted one.
+	if err := s.state.ReleaseDelete(deleteReleases...); err != nil {
+		return err
+	}
+	if err := s.state.DeploymentDelete(deleteDeployments...); err != nil {
+		return err
+	}
+	if err := s.state.ArtifactDelete(deleteArtifacts...); err != nil {
+		return err
+	}
+	if err := s.state.BuildDelete(deleteBuilds...); err != nil {
+		return err
+	}
+
+	if n := len(deleteReleases) + len(deleteDeployments) + len(deleteArtifacts) + len(deleteBuilds); n > 0 {
+		log.Debug("deleted old records",
+			"project", ref.Project,
+			"application", ref.Application,
+			"count", n)
+	}
+
+	return nil
+}
+
+// gcKept returns the set of IDs that the records that are kept refer to.
+// The records must be in the same order as the list refFunc indexes.
+func gcKept(records []*gcRecord, deleted []string, refFunc func(int) string) map[string]bool {
+	deletedSet := map[string]bool{}
+	for _, id := range deleted {
+		deletedSet[id] = true
+	}
+
+	result := map[string]bool{}
+	for i, r := range records {
+		if !deletedSet[r.id] {
+			result[refFunc(i)] = true
+		}
+	}
+
+	return result
+}
+
+// gcDestroyed returns true if a deployment or release has no physical
+// resources: they were destroyed, or the operation failed before creating
+// them.
+func gcDestroyed(state pb.Operation_PhysicalState, st *pb.Status) bool {
+	return state == pb.Operation_DESTROYED ||
+		(state != pb.Operation_CREATED && st.GetState() == pb.Status_ERROR)
+}
+
+// setGCSchedule collects garbage every interval, replacing the previous
+// schedule. If interval is zero, garbage is only collected on request.
+func (s *service) setGCSchedule(interval time.Duration) {
+	s.gcLock.Lock()
+	defer s.gcLock.Unlock()
+	if interval == s.gcInterval {
+		return
+	}
+
+	if s.gcStopCh != nil {
+		close(s.gcStopCh)
+		s.gcStopCh = nil
+	}
+	s.gcInterval = interval
+
+	log := s.log.Named("gc")
+	if interval <= 0 {
+		log.Info("scheduled garbage collection disabled")
+		return
+	}
+
+	s.gcStopCh = make(chan struct{})
+	go s.runGCSchedule(log, interval, s.gcStopCh)
+}
+
+// runGCSchedule collects garbage every interval until stopCh is closed.
+func (s *service) runGCSchedule(log hclog.Logger, interval time.Duration, stopCh <-chan struct{}) {
+	log.Info("scheduled garbage collection enabled", "interval", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		var now time.Time
+		select {
+		case now = <-ticker.C:
+		case <-stopCh:
+			return
+		}
+
+		resp, err := s.collectGarbage(log, nil, false, now)
+		if err != nil {
+			log.Error("error collecting garbage", "err", err)
+			continue
+		}
+
+		log.Info("garbage collection complete",
+			"builds", resp.Builds,
+			"artifacts", resp.Artifacts,
+			"deployments", resp.Deployments,
+			"releases", resp.Releases)
+	}
+}
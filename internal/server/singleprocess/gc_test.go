@@ -0,0 +1,65 @@
+package singleprocess
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func TestGCSelect(t *testing.T) {
+	now := time.Now()
+	status := func(state pb.Status_State, age time.Duration) *pb.Status {
+		pt, err := ptypes.TimestampProto(now.Add(-age))
+		require.NoError(t, err)
+
+		result := &pb.Status{State: state, StartTime: pt}
+		if state != pb.Status_RUNNING {
+			result.CompleteTime = pt
+		}
+
+		return result
+	}
+
+	day := 24 * time.Hour
+	records := func() []*gcRecord {
+		return []*gcRecord{
+			{id: "1", workspace: "default", sequence: 1, status: status(pb.Status_SUCCESS, 90*day), deletable: true},
+			{id: "2", workspace: "default", sequence: 2, status: status(pb.Status_SUCCESS, 60*day), deletable: true},
+			{id: "3", workspace: "default", sequence: 3, status: status(pb.Status_SUCCESS, 10*day), deletable: true},
+			{id: "4", workspace: "default", sequence: 4, status: status(pb.Status_ERROR, 5*day), deletable: true},
+			{id: "5", workspace: "default", sequence: 5, status: status(pb.Status_RUNNING, 0), deletable: true},
+			{id: "6", workspace: "dev", sequence: 6, status: status(pb.Status_SUCCESS, 90*day), deletable: true},
+		}
+	}
+
+	t.Run("keep last", func(t *testing.T) {
+		require := require.New(t)
+
+		// 5 is the latest, 3 is the latest success, and 6 is the latest
+		// in its workspace.
+		result := gcSelect(records(), &pb.RetentionPolicy{KeepLast: 1}, now)
+		require.ElementsMatch([]string{"1", "2", "4"}, result)
+	})
+
+	t.Run("max age", func(t *testing.T) {
+		require := require.New(t)
+
+		result := gcSelect(records(), &pb.RetentionPolicy{MaxAgeDays: 30}, now)
+		require.ElementsMatch([]string{"1", "2"}, result)
+	})
+
+	t.Run("keeps needed and undeletable records", func(t *testing.T) {
+		require := require.New(t)
+
+		list := records()
+		list[0].needed = true
+		list[1].deletable = false
+
+		result := gcSelect(list, &pb.RetentionPolicy{KeepLast: 1}, now)
+		require.ElementsMatch([]string{"4"}, result)
+	})
+}
@@ -0,0 +1,168 @@
+package singleprocess
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/prometheus/client_golang/prometheus"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+var (
+	metricJobsDesc = prometheus.NewDesc(
+		"waypoint_jobs",
+		"Number of jobs by state. Queued jobs are the job queue depth.",
+		[]string{"state"}, nil,
+	)
+
+	metricRunnersDesc = prometheus.NewDesc(
+		"waypoint_runners_connected",
+		"Number of runners connected to the server.",
+		nil, nil,
+	)
+
+	metricEntrypointsDesc = prometheus.NewDesc(
+		"waypoint_entrypoints_connected",
+		"Number of deployment entrypoints connected to the server.",
+		nil, nil,
+	)
+)
+
+// serviceMetrics are the metrics the service records as it handles
+// requests. The service implements prometheus.Collector to report these
+// along with metrics that are read from the state when collected.
+type serviceMetrics struct {
+	jobDuration      *prometheus.HistogramVec
+	snapshotBytes    *prometheus.CounterVec
+	snapshotDuration *prometheus.HistogramVec
+}
+
+func newServiceMetrics() *serviceMetrics {
+	return &serviceMetrics{
+		jobDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "waypoint_job_duration_seconds",
+			Help: "Time jobs ran for, from ack to completion, by operation.",
+
+			// Jobs range from seconds to tens of minutes.
+			Buckets: []float64{1, 5, 10, 30, 60, 120, 300, 600, 1200, 1800, 3600},
+		}, []string{"operation", "result"}),
+
+		snapshotBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "waypoint_snapshot_bytes_total",
+			Help: "Bytes of snapshot data written by snapshots and read by restores.",
+		}, []string{"operation"}),
+
+		snapshotDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "waypoint_snapshot_duration_seconds",
+			Help: "Time taken by successful snapshots and restores.",
+		}, []string{"operation"}),
+	}
+}
+
+// observeJob records the duration of a job that completed. result is nil
+// if the job succeeded.
+func (m *serviceMetrics) observeJob(job *pb.Job, result error) {
+	ackTime, err := ptypes.Timestamp(job.AckTime)
+	if err != nil {
+		// Jobs are always acked before they can complete.
+		return
+	}
+
+	label := "success"
+	if result != nil {
+		label = "error"
+	}
+
+	m.jobDuration.
+		WithLabelValues(jobOperationName(job), label).
+		Observe(time.Since(ackTime).Seconds())
+}
+
+// Describe implements prometheus.Collector
+func (s *service) Describe(ch chan<- *prometheus.Desc) {
+	ch <- metricJobsDesc
+	ch <- metricRunnersDesc
+	ch <- metricEntrypointsDesc
+	s.metrics.jobDuration.Describe(ch)
+	s.metrics.snapshotBytes.Describe(ch)
+	s.metrics.snapshotDuration.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (s *service) Collect(ch chan<- prometheus.Metric) {
+	if counts, err := s.state.JobCountByState(); err != nil {
+		ch <- prometheus.NewInvalidMetric(metricJobsDesc, err)
+	} else {
+		// Report every state so that queries don't have gaps when
+		// there are no jobs in a state.
+		for v, name := range pb.Job_State_name {
+			ch <- prometheus.MustNewConstMetric(
+				metricJobsDesc, prometheus.GaugeValue,
+				float64(counts[pb.Job_State(v)]), strings.ToLower(name))
+		}
+	}
+
+	if runners, err := s.state.RunnerList(nil); err != nil {
+		ch <- prometheus.NewInvalidMetric(metricRunnersDesc, err)
+	} else {
+		ch <- prometheus.MustNewConstMetric(
+			metricRunnersDesc, prometheus.GaugeValue, float64(len(runners)))
+	}
+
+	if count, err := s.state.InstanceCount(); err != nil {
+		ch <- prometheus.NewInvalidMetric(metricEntrypointsDesc, err)
+	} else {
+		ch <- prometheus.MustNewConstMetric(
+			metricEntrypointsDesc, prometheus.GaugeValue, float64(count))
+	}
+
+	s.metrics.jobDuration.Collect(ch)
+	s.metrics.snapshotBytes.Collect(ch)
+	s.metrics.snapshotDuration.Collect(ch)
+}
+
+// jobOperationName returns the name of the operation the job runs. This
+// is used as a metric label so it must not change.
+func jobOperationName(job *pb.Job) string {
+	switch job.Operation.(type) {
+	case *pb.Job_Noop_:
+		return "noop"
+	case *pb.Job_Build:
+		return "build"
+	case *pb.Job_Push:
+		return "push"
+	case *pb.Job_Deploy:
+		return "deploy"
+	case *pb.Job_Destroy:
+		return "destroy"
+	case *pb.Job_Release:
+		return "release"
+	case *pb.Job_Validate:
+		return "validate"
+	case *pb.Job_Auth:
+		return "auth"
+	case *pb.Job_Docs:
+		return "docs"
+	case *pb.Job_ConfigSync:
+		return "config_sync"
+	default:
+		return "unknown"
+	}
+}
+
+// countWriter adds the number of bytes written through it to a counter.
+type countWriter struct {
+	w io.Writer
+	c prometheus.Counter
+}
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.c.Add(float64(n))
+	return n, err
+}
+
+var _ prometheus.Collector = (*service)(nil)
@@ -0,0 +1,70 @@
+package singleprocess
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/waypoint/internal/server"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	serverptypes "github.com/hashicorp/waypoint/internal/server/ptypes"
+)
+
+func TestServiceMetrics(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	// Create our server
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+
+	reg := prometheus.NewRegistry()
+	require.NoError(reg.Register(impl.(prometheus.Collector)))
+
+	// Nothing yet
+	require.Equal(float64(0), testGaugeValue(t, reg, "waypoint_jobs", "queued"))
+	require.Equal(float64(0), testGaugeValue(t, reg, "waypoint_runners_connected", ""))
+
+	// Queue a job and register a runner
+	TestApp(t, client, serverptypes.TestJobNew(t, nil).Application)
+	_, err = client.QueueJob(ctx, &pb.QueueJobRequest{Job: serverptypes.TestJobNew(t, nil)})
+	require.NoError(err)
+	TestRunner(t, client, nil)
+
+	require.Equal(float64(1), testGaugeValue(t, reg, "waypoint_jobs", "queued"))
+	require.Equal(float64(0), testGaugeValue(t, reg, "waypoint_jobs", "running"))
+	require.Equal(float64(1), testGaugeValue(t, reg, "waypoint_runners_connected", ""))
+	require.Equal(float64(0), testGaugeValue(t, reg, "waypoint_entrypoints_connected", ""))
+}
+
+// testGaugeValue returns the value of the gauge with the given name. If
+// state is set, the value for that state label is returned.
+func testGaugeValue(t *testing.T, reg *prometheus.Registry, name, state string) float64 {
+	mfs, err := reg.Gather()
+	require.NoError(t, err)
+
+	for _, mf := range mfs {
+		if mf.GetName() != name {
+			continue
+		}
+
+		for _, m := range mf.GetMetric() {
+			match := state == ""
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "state" && l.GetValue() == state {
+					match = true
+				}
+			}
+
+			if match {
+				return m.GetGauge().GetValue()
+			}
+		}
+	}
+
+	t.Fatalf("metric %q not found", name)
+	return 0
+}
@@ -0,0 +1,256 @@
+package singleprocess
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hashicorp/go-hclog"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// pruneRecord is a deployment or release that pruning may delete.
+type pruneRecord struct {
+	id       string
+	sequence uint64
+	state    pb.Operation_PhysicalState
+	status   *pb.Status
+	size     int
+
+	// needed is true if a record that is kept refers to this record,
+	// such as the deployment of a release that is kept.
+	needed bool
+}
+
+// pruneSelect returns the records of an application to delete: the ones
+// without physical resources that completed before the cutoff. The record
+// with the highest sequence number is always kept, since sequence numbers
+// are assigned from the highest one stored and would otherwise be reused
+// after a restart.
+func pruneSelect(records []*pruneRecord, before time.Time) []*pruneRecord {
+	var latest uint64
+	for _, r := range records {
+		if r.sequence > latest {
+			latest = r.sequence
+		}
+	}
+
+	var result []*pruneRecord
+	for _, r := range records {
+		if r.sequence == latest || r.needed || !gcDestroyed(r.state, r.status) {
+			continue
+		}
+
+		// Running records have no complete time.
+		completed, err := ptypes.Timestamp(r.status.GetCompleteTime())
+		if err != nil || !completed.Before(before) {
+			continue
+		}
+
+		result = append(result, r)
+	}
+
+	return result
+}
+
+// pruneJobRefs returns the IDs of the artifacts, deployments and releases
+// that a job operated on. The job record contains copies of them. Deploy
+// jobs that failed have no result, so they are also found by the artifact
+// they deployed.
+func pruneJobRefs(job *pb.Job) []string {
+	var result []string
+	switch op := job.Operation.(type) {
+	case *pb.Job_Deploy:
+		result = append(result, op.Deploy.GetArtifact().GetId())
+
+	case *pb.Job_Release:
+		result = append(result, op.Release.GetDeployment().GetId())
+
+	case *pb.Job_Destroy:
+		result = append(result,
+			op.Destroy.GetDeployment().GetId(),
+			op.Destroy.GetRelease().GetId())
+	}
+
+	return append(result,
+		job.Result.GetDeploy().GetDeployment().GetId(),
+		job.Result.GetRelease().GetRelease().GetId())
+}
+
+// pruneData deletes the destroyed deployments and releases of a project,
+// or of every project if ref is nil, that completed before the cutoff,
+// along with the completed jobs that operated on them. If dryRun is true,
+// the records are only counted.
+func (s *service) pruneData(
+	log hclog.Logger,
+	ref *pb.Ref_Project,
+	before time.Time,
+	dryRun bool,
+) (*pb.PruneDataResponse, error) {
+	projects := []*pb.Ref_Project{ref}
+	if ref == nil {
+		var err error
+		projects, err = s.state.ProjectList()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var result pb.PruneDataResponse
+	var deleteDeployments, deleteReleases []string
+
+	// The jobs that created the deleted records, and the artifacts whose
+	// deployments are all deleted. Jobs that deployed these artifacts
+	// are deleted even if they failed before reporting a result.
+	deletedJobs := map[string]bool{}
+	artifacts := map[string]bool{}
+	for _, projectRef := range projects {
+		project, err := s.state.ProjectGet(projectRef)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, app := range project.Applications {
+			appRef := &pb.Ref_Application{
+				Project:     project.Name,
+				Application: app.Name,
+			}
+
+			releases, err := s.state.ReleaseList(appRef)
+			if err != nil {
+				return nil, err
+			}
+			deployments, err := s.state.DeploymentList(appRef)
+			if err != nil {
+				return nil, err
+			}
+
+			// Releases are selected first since a release that is kept
+			// keeps its deployment.
+			var records []*pruneRecord
+			for _, v := range releases {
+				records = append(records, &pruneRecord{
+					id:       v.Id,
+					sequence: v.Sequence,
+					state:    v.State,
+					status:   v.Status,
+					size:     proto.Size(v),
+				})
+			}
+			deleted := map[string]bool{}
+			for _, r := range pruneSelect(records, before) {
+				deleted[r.id] = true
+				deleteReleases = append(deleteReleases, r.id)
+				result.DeletedRecordBytes += uint64(r.size)
+			}
+
+			needed := map[string]bool{}
+			for _, v := range releases {
+				if deleted[v.Id] {
+					deletedJobs[v.JobId] = true
+				} else {
+					needed[v.DeploymentId] = true
+				}
+			}
+
+			records = nil
+			for _, v := range deployments {
+				records = append(records, &pruneRecord{
+					id:       v.Id,
+					sequence: v.Sequence,
+					state:    v.State,
+					status:   v.Status,
+					size:     proto.Size(v),
+					needed:   needed[v.Id],
+				})
+			}
+			for _, r := range pruneSelect(records, before) {
+				deleted[r.id] = true
+				deleteDeployments = append(deleteDeployments, r.id)
+				result.DeletedRecordBytes += uint64(r.size)
+			}
+
+			for _, v := range deployments {
+				if deleted[v.Id] {
+					deletedJobs[v.JobId] = true
+					if _, ok := artifacts[v.ArtifactId]; !ok {
+						artifacts[v.ArtifactId] = true
+					}
+				} else {
+					artifacts[v.ArtifactId] = false
+				}
+			}
+		}
+	}
+
+	// The jobs that operated on the deleted records.
+	deletedSet := map[string]bool{}
+	for _, id := range deleteReleases {
+		deletedSet[id] = true
+	}
+	for _, id := range deleteDeployments {
+		deletedSet[id] = true
+	}
+	for id, deleted := range artifacts {
+		if deleted {
+			deletedSet[id] = true
+		}
+	}
+
+	var deleteJobs []string
+	if len(deletedSet) > 0 {
+		jobs, err := s.state.JobList()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, job := range jobs {
+			if job.State != pb.Job_SUCCESS && job.State != pb.Job_ERROR {
+				continue
+			}
+
+			deleteJob := deletedJobs[job.Id]
+			for _, id := range pruneJobRefs(job) {
+				if id != "" && deletedSet[id] {
+					deleteJob = true
+					break
+				}
+			}
+
+			if deleteJob {
+				deleteJobs = append(deleteJobs, job.Id)
+				result.DeletedRecordBytes += uint64(proto.Size(job))
+			}
+		}
+	}
+
+	result.Deployments = uint32(len(deleteDeployments))
+	result.Releases = uint32(len(deleteReleases))
+	result.Jobs = uint32(len(deleteJobs))
+	if dryRun {
+		return &result, nil
+	}
+
+	// Delete the jobs first since they are only found through the records
+	// they operated on. If this fails partway, pruning again finishes.
+	if err := s.state.JobDelete(deleteJobs...); err != nil {
+		return nil, err
+	}
+	if err := s.state.ReleaseDelete(deleteReleases...); err != nil {
+		return nil, err
+	}
+	if err := s.state.DeploymentDelete(deleteDeployments...); err != nil {
+		return nil, err
+	}
+
+	if n := len(deleteJobs) + len(deleteReleases) + len(deleteDeployments); n > 0 {
+		log.Info("pruned records",
+			"deployments", len(deleteDeployments),
+			"releases", len(deleteReleases),
+			"jobs", len(deleteJobs),
+			"deleted_record_bytes", result.DeletedRecordBytes)
+	}
+
+	return &result, nil
+}
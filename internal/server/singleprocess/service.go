@@ -2,9 +2,13 @@ package singleprocess
 
 import (
 	"crypto/tls"
+	"fmt"
+	"io"
+	"sync"
+	"time"
 
 	"github.com/boltdb/bolt"
-
+	"github.com/golang/protobuf/proto"
 	"github.com/hashicorp/go-hclog"
 	grpctoken "github.com/hashicorp/horizon/pkg/grpc/token"
 	wphznpb "github.com/hashicorp/waypoint-hzn/pkg/pb"
@@ -32,12 +36,46 @@ type service struct {
 	// to have the configs set.
 	urlConfig *serverconfig.URL
 	urlClient wphznpb.WaypointHznClient
+
+	// snapshots is where the server stores its own snapshots. This is nil
+	// if neither a snapshot path nor a bucket is configured.
+	snapshots snapshotStore
+
+	// metrics are recorded as requests are handled and reported when the
+	// service is registered as a Prometheus collector.
+	metrics *serviceMetrics
+
+	// auditSinks are where audit events are sent in addition to the
+	// state. auditLock must be held to write to them.
+	auditSinks []io.Writer
+	auditLock  sync.Mutex
+
+	// oidc logs users in with an OIDC provider. This is nil if OIDC
+	// login isn't configured.
+	oidc *oidcProvider
+
+	// webhooks sends events to the webhooks of projects.
+	webhooks *webhookSender
+
+	// log is the logger of the server. Its level is set by the runtime
+	// config.
+	log hclog.Logger
+
+	// logLevels sets the levels of the server logs and its subsystems.
+	// This is nil if the levels can't be changed.
+	logLevels *server.LogLevels
+
+	// gcInterval is how often garbage is collected, and closing gcStopCh
+	// stops the schedule. gcLock must be held to use these.
+	gcInterval time.Duration
+	gcStopCh   chan struct{}
+	gcLock     sync.Mutex
 }
 
 // New returns a Waypoint server implementation that uses BotlDB plus
 // in-memory locks to operate safely.
 func New(opts ...Option) (pb.WaypointServer, error) {
-	var s service
+	s := service{metrics: newServiceMetrics()}
 	var cfg config
 	for _, opt := range opts {
 		if err := opt(&s, &cfg); err != nil {
@@ -49,9 +87,12 @@ func New(opts ...Option) (pb.WaypointServer, error) {
 	if log == nil {
 		log = hclog.L()
 	}
+	s.log = log
+	s.logLevels = cfg.logLevels
+	s.webhooks = newWebhookSender(log.Named("webhook"))
 
 	// Initialize our state
-	st, err := state.New(log, cfg.db)
+	st, err := state.New(log.Named("state"), cfg.db)
 	if err != nil {
 		return nil, err
 	}
@@ -74,6 +115,9 @@ func New(opts ...Option) (pb.WaypointServer, error) {
 	}
 	s.id = id
 
+	// Delete the data of resumable restores that clients abandoned.
+	go s.runRestoreUploadExpiry(log.Named("restore"), restoreUploadExpiryInterval)
+
 	// Setup our URL service config if it is enabled.
 	if scfg := cfg.serverConfig; scfg != nil && scfg.URL != nil && scfg.URL.Enabled {
 		// Set our config
@@ -113,8 +157,14 @@ func New(opts ...Option) (pb.WaypointServer, error) {
 			TlsSkipVerify: scfg.CEBConfig.TLSSkipVerify,
 		}
 
+		current, err := s.state.ServerConfigGet()
+		if err != nil {
+			return nil, err
+		}
+
 		conf := &pb.ServerConfig{
 			AdvertiseAddrs: []*pb.ServerConfig_AdvertiseAddr{addr},
+			Runtime:        current.Runtime,
 		}
 
 		if err := s.state.ServerConfigSet(conf); err != nil {
@@ -122,6 +172,87 @@ func New(opts ...Option) (pb.WaypointServer, error) {
 		}
 	}
 
+	// Setup where we send audit events.
+	if scfg := cfg.serverConfig; scfg != nil && scfg.Audit != nil {
+		s.auditSinks, err = newAuditSinks(scfg.Audit)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Setup OIDC login if it is configured.
+	if scfg := cfg.serverConfig; scfg != nil && scfg.OIDC != nil {
+		s.oidc, err = newOIDCProvider(scfg.OIDC)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Start taking scheduled snapshots if they're enabled.
+	if scfg := cfg.serverConfig; scfg != nil && scfg.Snapshot != nil {
+		if s3 := scfg.Snapshot.S3; s3 != nil && s3.Bucket != "" {
+			s.snapshots, err = newS3SnapshotStore(s3)
+			if err != nil {
+				return nil, err
+			}
+		} else if scfg.Snapshot.Path != "" {
+			s.snapshots = &dirSnapshotStore{dir: scfg.Snapshot.Path}
+		}
+
+		var retention snapshotRetention
+		retention.count = scfg.Snapshot.Retain
+		if v := scfg.Snapshot.MaxAge; v != "" {
+			maxAge, err := time.ParseDuration(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid snapshot max_age: %s", err)
+			}
+			if maxAge <= 0 {
+				return nil, fmt.Errorf("snapshot max_age must be positive")
+			}
+
+			retention.maxAge = maxAge
+		}
+
+		if scfg.Snapshot.Interval != "" {
+			interval, err := time.ParseDuration(scfg.Snapshot.Interval)
+			if err != nil {
+				return nil, fmt.Errorf("invalid snapshot interval: %s", err)
+			}
+			if interval <= 0 {
+				return nil, fmt.Errorf("snapshot interval must be positive")
+			}
+			if s.snapshots == nil {
+				return nil, fmt.Errorf(
+					"snapshot path or s3 bucket must be set to take scheduled snapshots")
+			}
+
+			go s.runSnapshotSchedule(log.Named("snapshot"),
+				interval, retention, s.snapshots)
+		}
+	}
+
+	// Apply the runtime config, such as how often to collect garbage. The
+	// settings the server is started with replace the ones that were set
+	// while it was running before.
+	runtime := &pb.ServerRuntimeConfig{}
+	if scfg := cfg.serverConfig; scfg != nil {
+		if scfg.GC != nil {
+			runtime.GcInterval = scfg.GC.Interval
+		}
+		if scfg.Runtime != nil {
+			proto.Merge(runtime, runtimeConfigProto(scfg.Runtime))
+
+			if len(scfg.Runtime.LogLevels) > 0 {
+				if err := s.setLogLevels(scfg.Runtime.LogLevels); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	if err := s.mergeRuntimeConfig(runtime); err != nil {
+		return nil, err
+	}
+
 	return &s, nil
 }
 
@@ -129,6 +260,7 @@ type config struct {
 	db           *bolt.DB
 	serverConfig *serverconfig.Config
 	log          hclog.Logger
+	logLevels    *server.LogLevels
 
 	acceptUrlTerms bool
 }
@@ -159,6 +291,16 @@ func WithLogger(log hclog.Logger) Option {
 	}
 }
 
+// WithLogLevels sets the levels of the loggers of the server, so that they
+// can be changed with SetLogLevel and the runtime config. The logger set
+// with WithLogger should be created from the logger of levels.
+func WithLogLevels(levels *server.LogLevels) Option {
+	return func(s *service, cfg *config) error {
+		cfg.logLevels = levels
+		return nil
+	}
+}
+
 func WithAcceptURLTerms(accept bool) Option {
 	return func(s *service, cfg *config) error {
 		cfg.acceptUrlTerms = true
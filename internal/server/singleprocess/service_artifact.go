@@ -45,6 +45,7 @@ func (s *service) ListPushedArtifacts(
 		state.ListWithStatusFilter(req.Status...),
 		state.ListWithOrder(req.Order),
 		state.ListWithWorkspace(req.Workspace),
+		state.ListWithLabels(req.Labels),
 	)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,140 @@
+package singleprocess
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hashicorp/go-hclog"
+	"github.com/mr-tron/base58"
+
+	"github.com/hashicorp/waypoint/internal/server"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	"github.com/hashicorp/waypoint/internal/serverconfig"
+)
+
+const (
+	auditOpRestoreSnapshot    = "restore_snapshot"
+	auditOpTokenCreate        = "token_create"
+	auditOpTokenRevoke        = "token_revoke"
+	auditOpTokenKeyRotate     = "token_key_rotate"
+	auditOpWebhookUpsert      = "webhook_upsert"
+	auditOpWebhookDelete      = "webhook_delete"
+	auditOpRetentionPolicySet = "retention_policy_set"
+	auditOpGC                 = "gc"
+	auditOpPrune              = "prune"
+	auditOpOrganizationUpsert = "organization_upsert"
+	auditOpOrganizationDelete = "organization_delete"
+	auditOpLogLevelSet        = "log_level_set"
+)
+
+// auditJobOperations are the job operations that are recorded in the audit
+// log when they're queued. The other operations don't change anything.
+var auditJobOperations = map[string]bool{
+	"build":   true,
+	"push":    true,
+	"deploy":  true,
+	"destroy": true,
+	"release": true,
+}
+
+func (s *service) ListAuditEvents(
+	ctx context.Context,
+	req *pb.ListAuditEventsRequest,
+) (*pb.ListAuditEventsResponse, error) {
+	events, err := s.state.AuditEventList(req.Operation, int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListAuditEventsResponse{Events: events}, nil
+}
+
+// audit records that the caller in ctx performed an operation. Errors are
+// only logged since the operation has already been performed.
+func (s *service) audit(ctx context.Context, operation, target, detail string) {
+	log := hclog.FromContext(ctx)
+
+	id, err := server.Id()
+	if err != nil {
+		log.Error("error generating audit event ID", "err", err)
+		return
+	}
+
+	event := &pb.AuditEvent{
+		Id:        id,
+		Time:      ptypes.TimestampNow(),
+		Operation: operation,
+		Target:    target,
+		Detail:    detail,
+	}
+	event.User, event.TokenId = s.auditIdentity(ctx)
+
+	if err := s.state.AuditEventPut(event); err != nil {
+		log.Error("error recording audit event", "operation", operation, "err", err)
+	}
+
+	if len(s.auditSinks) == 0 {
+		return
+	}
+
+	data, err := event.MarshalJSON()
+	if err != nil {
+		log.Error("error encoding audit event", "operation", operation, "err", err)
+		return
+	}
+	data = append(data, '\n')
+
+	// Lock so that concurrent events are never interleaved.
+	s.auditLock.Lock()
+	defer s.auditLock.Unlock()
+	for _, w := range s.auditSinks {
+		if _, err := w.Write(data); err != nil {
+			log.Error("error sending audit event", "operation", operation, "err", err)
+		}
+	}
+}
+
+// auditIdentity returns the user and token ID of the token that
+// authenticated the request in ctx. These are empty if the request
+// wasn't authenticated.
+func (s *service) auditIdentity(ctx context.Context) (string, string) {
+	body := s.requestToken(ctx)
+	if body == nil {
+		return "", ""
+	}
+
+	return body.User, base58.Encode(body.TokenId)
+}
+
+// auditJobTarget returns the audit event target for a job.
+func auditJobTarget(job *pb.Job) string {
+	return fmt.Sprintf("%s/%s", job.Application.Project, job.Application.Application)
+}
+
+// newAuditSinks returns the writers to send audit events to for the
+// given config. Each event is written with a single call to Write.
+func newAuditSinks(cfg *serverconfig.Audit) ([]io.Writer, error) {
+	var result []io.Writer
+	if cfg.File != "" {
+		f, err := os.OpenFile(cfg.File, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("error opening audit file: %s", err)
+		}
+
+		result = append(result, f)
+	}
+
+	if cfg.Syslog {
+		w, err := auditSyslog()
+		if err != nil {
+			return nil, fmt.Errorf("error connecting to syslog for audit events: %s", err)
+		}
+
+		result = append(result, w)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,79 @@
+package singleprocess
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/waypoint/internal/server"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	serverptypes "github.com/hashicorp/waypoint/internal/server/ptypes"
+	"github.com/hashicorp/waypoint/internal/serverconfig"
+)
+
+func TestServiceAudit(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	td, err := ioutil.TempDir("", "waypoint-test")
+	require.NoError(err)
+	defer os.RemoveAll(td)
+	auditPath := filepath.Join(td, "audit.log")
+
+	// Create our server
+	impl, err := New(WithDB(testDB(t)), WithConfig(&serverconfig.Config{
+		Audit: &serverconfig.Audit{File: auditPath},
+	}))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+
+	// Queue a deploy and a job that isn't audited
+	TestApp(t, client, serverptypes.TestJobNew(t, nil).Application)
+	queueResp, err := client.QueueJob(ctx, &pb.QueueJobRequest{
+		Job: serverptypes.TestJobNew(t, &pb.Job{
+			Operation: &pb.Job_Deploy{Deploy: &pb.Job_DeployOp{}},
+		}),
+	})
+	require.NoError(err)
+	_, err = client.QueueJob(ctx, &pb.QueueJobRequest{
+		Job: serverptypes.TestJobNew(t, nil),
+	})
+	require.NoError(err)
+
+	// Create a token
+	_, err = client.GenerateLoginToken(ctx, &pb.LoginTokenRequest{Role: pb.Token_ADMIN})
+	require.NoError(err)
+
+	// List everything
+	resp, err := client.ListAuditEvents(ctx, &pb.ListAuditEventsRequest{})
+	require.NoError(err)
+	require.Len(resp.Events, 2)
+	require.Equal("token_create", resp.Events[0].Operation)
+	require.Equal("deploy", resp.Events[1].Operation)
+	require.Equal("p_test/a_test", resp.Events[1].Target)
+	require.Contains(resp.Events[1].Detail, queueResp.JobId)
+	require.NotNil(resp.Events[1].Time)
+
+	// Filter
+	resp, err = client.ListAuditEvents(ctx, &pb.ListAuditEventsRequest{
+		Operation: "deploy",
+	})
+	require.NoError(err)
+	require.Len(resp.Events, 1)
+
+	// The events should be in the file too, oldest first
+	data, err := ioutil.ReadFile(auditPath)
+	require.NoError(err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(lines, 2)
+
+	var event map[string]interface{}
+	require.NoError(json.Unmarshal([]byte(lines[0]), &event))
+	require.Equal("deploy", event["operation"])
+}
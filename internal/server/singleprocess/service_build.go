@@ -42,8 +42,10 @@ func (s *service) ListBuilds(
 	req *pb.ListBuildsRequest,
 ) (*pb.ListBuildsResponse, error) {
 	result, err := s.state.BuildList(req.Application,
+		state.ListWithStatusFilter(req.Status...),
 		state.ListWithWorkspace(req.Workspace),
 		state.ListWithOrder(req.Order),
+		state.ListWithLabels(req.Labels),
 	)
 	if err != nil {
 		return nil, err
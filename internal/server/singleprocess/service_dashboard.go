@@ -0,0 +1,113 @@
+package singleprocess
+
+import (
+	"time"
+
+	"github.com/hashicorp/go-memdb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	"github.com/hashicorp/waypoint/internal/server/singleprocess/state"
+)
+
+const (
+	// dashboardDeploymentLimit is the number of deployments sent if the
+	// request doesn't set a limit.
+	dashboardDeploymentLimit = 10
+
+	// dashboardMinInterval is the minimum time between dashboard updates.
+	// Jobs change state often while they run, so this limits how much we
+	// send to slower clients.
+	dashboardMinInterval = 500 * time.Millisecond
+)
+
+func (s *service) GetDashboardStream(
+	req *pb.GetDashboardStreamRequest,
+	server pb.Waypoint_GetDashboardStreamServer,
+) error {
+	ctx := server.Context()
+
+	if req.Application == nil {
+		return status.Errorf(codes.FailedPrecondition, "application must be set")
+	}
+
+	limit := req.DeploymentLimit
+	if limit == 0 {
+		limit = dashboardDeploymentLimit
+	}
+
+	for {
+		ws := memdb.NewWatchSet()
+		resp, err := s.dashboard(req, limit, ws)
+		if err != nil {
+			return err
+		}
+
+		if err := server.Send(resp); err != nil {
+			return err
+		}
+
+		// Wait for the minimum interval so that we batch up changes.
+		select {
+		case <-time.After(dashboardMinInterval):
+		case <-ctx.Done():
+			return nil
+		}
+
+		// Wait for something to change
+		if err := ws.WatchCtx(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return err
+		}
+	}
+}
+
+// dashboard returns the current state for the dashboard and adds watches
+// for every part of it to ws.
+func (s *service) dashboard(
+	req *pb.GetDashboardStreamRequest,
+	limit uint32,
+	ws memdb.WatchSet,
+) (*pb.GetDashboardStreamResponse, error) {
+	deployments, err := s.state.DeploymentList(req.Application,
+		state.ListWithWorkspace(req.Workspace),
+		state.ListWithOrder(&pb.OperationOrder{
+			Order: pb.OperationOrder_START_TIME,
+			Desc:  true,
+			Limit: limit,
+		}),
+		state.ListWithWatchSet(ws),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	allJobs, err := s.state.JobListWatch(ws)
+	if err != nil {
+		return nil, err
+	}
+
+	// We only send the jobs that haven't completed.
+	var jobs []*pb.Job
+	for _, job := range allJobs {
+		switch job.State {
+		case pb.Job_QUEUED, pb.Job_WAITING, pb.Job_RUNNING:
+			jobs = append(jobs, job)
+		}
+	}
+
+	runners, err := s.state.RunnerList(ws)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetDashboardStreamResponse{
+		Deployments: deployments,
+		Jobs:        jobs,
+		Runners:     runners,
+	}, nil
+}
@@ -0,0 +1,78 @@
+package singleprocess
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint/internal/server"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	serverptypes "github.com/hashicorp/waypoint/internal/server/ptypes"
+)
+
+func TestServiceGetDashboardStream(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	require := require.New(t)
+
+	// Create our server
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+
+	// Create a deployment
+	resp, err := client.UpsertDeployment(ctx, &pb.UpsertDeploymentRequest{
+		Deployment: serverptypes.TestValidDeployment(t, nil),
+	})
+	require.NoError(err)
+	deployment := resp.Deployment
+
+	// Start the stream
+	stream, err := client.GetDashboardStream(ctx, &pb.GetDashboardStreamRequest{
+		Application: deployment.Application,
+	})
+	require.NoError(err)
+
+	// We should get the initial state
+	{
+		resp, err := stream.Recv()
+		require.NoError(err)
+		require.Len(resp.Deployments, 1)
+		require.Equal(deployment.Id, resp.Deployments[0].Id)
+		require.Empty(resp.Jobs)
+	}
+
+	// Create another deployment
+	_, err = client.UpsertDeployment(ctx, &pb.UpsertDeploymentRequest{
+		Deployment: serverptypes.TestValidDeployment(t, nil),
+	})
+	require.NoError(err)
+
+	// We should get an update
+	{
+		resp, err := stream.Recv()
+		require.NoError(err)
+		require.Len(resp.Deployments, 2)
+	}
+}
+
+func TestServiceGetDashboardStream_noApp(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	// Create our server
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+
+	stream, err := client.GetDashboardStream(ctx, &pb.GetDashboardStreamRequest{})
+	require.NoError(err)
+
+	_, err = stream.Recv()
+	require.Error(err)
+	require.Equal(codes.FailedPrecondition, status.Code(err))
+}
@@ -83,6 +83,7 @@ func (s *service) ListDeployments(
 		state.ListWithOrder(req.Order),
 		state.ListWithWorkspace(req.Workspace),
 		state.ListWithPhysicalState(req.PhysicalState),
+		state.ListWithLabels(req.Labels),
 	)
 	if err != nil {
 		return nil, err
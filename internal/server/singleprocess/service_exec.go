@@ -27,7 +27,10 @@ func (s *service) StartExecStream(
 		return status.Errorf(codes.FailedPrecondition,
 			"first message must be start type")
 	}
-	log = log.With("deployment_id", start.Start.DeploymentId)
+	log = log.With(
+		"deployment_id", start.Start.DeploymentId,
+		"instance_id", start.Start.InstanceId,
+	)
 	log.Debug("exec requested", "args", start.Start.Args)
 
 	// Create our exec. We have to populate everything here first because
@@ -42,8 +45,12 @@ func (s *service) StartExecStream(
 		EntrypointEventCh: eventCh,
 	}
 
-	// Register the exec session
-	err = s.state.InstanceExecCreateByDeployment(start.Start.DeploymentId, execRec)
+	// Register the exec session, on the requested instance if there is one.
+	if id := start.Start.InstanceId; id != "" {
+		err = s.state.InstanceExecCreateByInstance(start.Start.DeploymentId, id, execRec)
+	} else {
+		err = s.state.InstanceExecCreateByDeployment(start.Start.DeploymentId, execRec)
+	}
 	if err != nil {
 		return err
 	}
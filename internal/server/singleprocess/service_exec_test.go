@@ -93,6 +93,61 @@ func TestServiceStartExecStream_start(t *testing.T) {
 	require.False(active)
 }
 
+func TestServiceStartExecStream_instance(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	// Create our server
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+
+	// Create an instance
+	instanceId, deploymentId, closer := TestEntrypoint(t, client)
+	defer closer()
+
+	// Unknown instances are rejected
+	{
+		stream, err := client.StartExecStream(ctx)
+		require.NoError(err)
+		require.NoError(stream.Send(&pb.ExecStreamRequest{
+			Event: &pb.ExecStreamRequest_Start_{
+				Start: &pb.ExecStreamRequest_Start{
+					DeploymentId: deploymentId,
+					InstanceId:   "nope",
+					Args:         []string{"foo"},
+				},
+			},
+		}))
+
+		_, err = stream.Recv()
+		require.Error(err)
+		require.Equal(codes.NotFound, status.Code(err))
+	}
+
+	// The requested instance is used
+	stream, err := client.StartExecStream(ctx)
+	require.NoError(err)
+	require.NoError(stream.Send(&pb.ExecStreamRequest{
+		Event: &pb.ExecStreamRequest_Start_{
+			Start: &pb.ExecStreamRequest_Start{
+				DeploymentId: deploymentId,
+				InstanceId:   instanceId,
+				Args:         []string{"foo"},
+			},
+		},
+	}))
+
+	resp, err := stream.Recv()
+	require.NoError(err)
+	_, ok := resp.Event.(*pb.ExecStreamResponse_Open_)
+	require.True(ok, "should be an open")
+
+	exec := testGetInstanceExec(t, impl, instanceId)
+	require.Equal([]string{"foo"}, exec.Args)
+	require.NoError(stream.CloseSend())
+}
+
 func TestServiceStartExecStream_eventExit(t *testing.T) {
 	ctx := context.Background()
 	require := require.New(t)
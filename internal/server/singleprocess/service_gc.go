@@ -0,0 +1,118 @@
+package singleprocess
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func (s *service) SetRetentionPolicy(
+	ctx context.Context,
+	req *pb.SetRetentionPolicyRequest,
+) (*empty.Empty, error) {
+	policy := req.Policy
+	if policy == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "policy is required")
+	}
+
+	// The default policy is audited with an empty target.
+	target := policy.Project.GetProject()
+	if policy.Project != nil && target == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "project name is required if project is set")
+	}
+
+	if req.Delete {
+		if err := s.state.RetentionPolicyDelete(policy.Project); err != nil {
+			return nil, err
+		}
+
+		s.audit(ctx, auditOpRetentionPolicySet, target, "deleted")
+		return &empty.Empty{}, nil
+	}
+
+	if err := s.state.RetentionPolicyPut(policy); err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, auditOpRetentionPolicySet, target, fmt.Sprintf(
+		"keep last %d, max age %d days", policy.KeepLast, policy.MaxAgeDays))
+	return &empty.Empty{}, nil
+}
+
+func (s *service) ListRetentionPolicies(
+	ctx context.Context,
+	req *empty.Empty,
+) (*pb.ListRetentionPoliciesResponse, error) {
+	policies, err := s.state.RetentionPolicyList()
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListRetentionPoliciesResponse{Policies: policies}, nil
+}
+
+func (s *service) RunGC(
+	ctx context.Context,
+	req *pb.RunGCRequest,
+) (*pb.RunGCResponse, error) {
+	if req.Project != nil {
+		// Report projects that don't exist rather than doing nothing.
+		if _, err := s.state.ProjectGet(req.Project); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := s.collectGarbage(hclog.FromContext(ctx), req.Project, req.DryRun, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if !req.DryRun {
+		s.audit(ctx, auditOpGC, req.Project.GetProject(), fmt.Sprintf(
+			"deleted %d builds, %d artifacts, %d deployments, %d releases",
+			resp.Builds, resp.Artifacts, resp.Deployments, resp.Releases))
+	}
+
+	return resp, nil
+}
+
+func (s *service) PruneData(
+	ctx context.Context,
+	req *pb.PruneDataRequest,
+) (*pb.PruneDataResponse, error) {
+	if req.Before == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "before is required")
+	}
+	before, err := ptypes.Timestamp(req.Before)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid before: %s", err)
+	}
+
+	if req.Project != nil {
+		// Report projects that don't exist rather than doing nothing.
+		if _, err := s.state.ProjectGet(req.Project); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := s.pruneData(hclog.FromContext(ctx), req.Project, before, req.DryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	if !req.DryRun {
+		s.audit(ctx, auditOpPrune, req.Project.GetProject(), fmt.Sprintf(
+			"deleted %d deployments, %d releases, %d jobs completed before %s",
+			resp.Deployments, resp.Releases, resp.Jobs, before.UTC().Format(time.RFC3339)))
+	}
+
+	return resp, nil
+}
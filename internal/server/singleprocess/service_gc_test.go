@@ -0,0 +1,259 @@
+package singleprocess
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint/internal/server"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	serverptypes "github.com/hashicorp/waypoint/internal/server/ptypes"
+)
+
+func TestServiceGC(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+	s := impl.(*service)
+
+	// Three builds. The first two were pushed and deployed, and the first
+	// deployment was destroyed.
+	for _, id := range []string{"b1", "b2", "b3"} {
+		require.NoError(s.state.BuildPut(false, serverptypes.TestValidBuild(t, &pb.Build{
+			Id: id,
+		})))
+	}
+	for _, id := range []string{"1", "2"} {
+		require.NoError(s.state.ArtifactPut(false, serverptypes.TestValidArtifact(t, &pb.PushedArtifact{
+			Id:      "a" + id,
+			BuildId: "b" + id,
+		})))
+	}
+	require.NoError(s.state.DeploymentPut(false, serverptypes.TestValidDeployment(t, &pb.Deployment{
+		Id:         "d1",
+		ArtifactId: "a1",
+		State:      pb.Operation_DESTROYED,
+	})))
+	require.NoError(s.state.DeploymentPut(false, serverptypes.TestValidDeployment(t, &pb.Deployment{
+		Id:         "d2",
+		ArtifactId: "a2",
+		State:      pb.Operation_CREATED,
+	})))
+
+	project := &pb.Ref_Project{Project: "p_test"}
+	ref := &pb.Ref_Application{Project: "p_test", Application: "a_test"}
+
+	// Nothing is deleted without a policy
+	resp, err := client.RunGC(ctx, &pb.RunGCRequest{})
+	require.NoError(err)
+	require.Equal(uint32(0), resp.Builds)
+
+	// Records aren't old enough for this policy
+	_, err = client.SetRetentionPolicy(ctx, &pb.SetRetentionPolicyRequest{
+		Policy: &pb.RetentionPolicy{KeepLast: 1, MaxAgeDays: 30},
+	})
+	require.NoError(err)
+	resp, err = client.RunGC(ctx, &pb.RunGCRequest{})
+	require.NoError(err)
+	require.Equal(uint32(0), resp.Builds)
+
+	// The project's own policy overrides the default
+	_, err = client.SetRetentionPolicy(ctx, &pb.SetRetentionPolicyRequest{
+		Policy: &pb.RetentionPolicy{Project: project, KeepLast: 1},
+	})
+	require.NoError(err)
+	policies, err := client.ListRetentionPolicies(ctx, &empty.Empty{})
+	require.NoError(err)
+	require.Len(policies.Policies, 2)
+
+	// A dry run deletes nothing. The destroyed deployment, its artifact,
+	// and its build are collected. The created deployment keeps the
+	// second artifact and build, and the latest build is always kept.
+	resp, err = client.RunGC(ctx, &pb.RunGCRequest{Project: project, DryRun: true})
+	require.NoError(err)
+	require.Equal(uint32(1), resp.Builds)
+	require.Equal(uint32(1), resp.Artifacts)
+	require.Equal(uint32(1), resp.Deployments)
+	require.Equal(uint32(0), resp.Releases)
+
+	builds, err := s.state.BuildList(ref)
+	require.NoError(err)
+	require.Len(builds, 3)
+
+	// Collect
+	resp, err = client.RunGC(ctx, &pb.RunGCRequest{Project: project})
+	require.NoError(err)
+	require.Equal(uint32(1), resp.Builds)
+	require.Equal(uint32(1), resp.Deployments)
+
+	_, err = s.state.BuildGet(&pb.Ref_Operation{Target: &pb.Ref_Operation_Id{Id: "b1"}})
+	require.Equal(codes.NotFound, status.Code(err))
+	_, err = s.state.ArtifactGet(&pb.Ref_Operation{Target: &pb.Ref_Operation_Id{Id: "a1"}})
+	require.Equal(codes.NotFound, status.Code(err))
+	_, err = s.state.DeploymentGet(&pb.Ref_Operation{Target: &pb.Ref_Operation_Id{Id: "d1"}})
+	require.Equal(codes.NotFound, status.Code(err))
+	for _, id := range []string{"b2", "b3"} {
+		_, err = s.state.BuildGet(&pb.Ref_Operation{Target: &pb.Ref_Operation_Id{Id: id}})
+		require.NoError(err)
+	}
+
+	// Nothing more to collect
+	resp, err = client.RunGC(ctx, &pb.RunGCRequest{Project: project})
+	require.NoError(err)
+	require.Equal(uint32(0), resp.Builds)
+
+	// Projects that don't exist are reported
+	_, err = client.RunGC(ctx, &pb.RunGCRequest{Project: &pb.Ref_Project{Project: "nope"}})
+	require.Error(err)
+	require.Equal(codes.NotFound, status.Code(err))
+}
+
+func TestServicePruneData(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+	s := impl.(*service)
+
+	// Two destroyed deployments and the latest one, which is created. The
+	// first deployment was released and the release was destroyed.
+	for _, d := range []struct {
+		id       string
+		artifact string
+		state    pb.Operation_PhysicalState
+	}{
+		{"d1", "a1", pb.Operation_DESTROYED},
+		{"d2", "a2", pb.Operation_DESTROYED},
+		{"d3", "a3", pb.Operation_CREATED},
+	} {
+		require.NoError(s.state.DeploymentPut(false, serverptypes.TestValidDeployment(t, &pb.Deployment{
+			Id:         d.id,
+			ArtifactId: d.artifact,
+			State:      d.state,
+		})))
+	}
+	require.NoError(s.state.ReleasePut(false, serverptypes.TestValidRelease(t, &pb.Release{
+		Id:           "r1",
+		DeploymentId: "d1",
+		State:        pb.Operation_DESTROYED,
+	})))
+	require.NoError(s.state.ReleasePut(false, serverptypes.TestValidRelease(t, &pb.Release{
+		Id:           "r2",
+		DeploymentId: "d3",
+		State:        pb.Operation_CREATED,
+	})))
+
+	// The job that destroyed the first deployment completed, a job that
+	// deployed the artifact of the second deployment failed without a
+	// result, and a job for the second deployment is still queued.
+	require.NoError(s.state.JobCreate(serverptypes.TestJobNew(t, &pb.Job{
+		Id: "j1",
+		Operation: &pb.Job_Destroy{
+			Destroy: &pb.Job_DestroyOp{
+				Target: &pb.Job_DestroyOp_Deployment{
+					Deployment: &pb.Deployment{Id: "d1"},
+				},
+			},
+		},
+	})))
+	job, err := s.state.JobAssignForRunner(ctx, &pb.Runner{Id: "R_A"})
+	require.NoError(err)
+	_, err = s.state.JobAck(job.Id, true)
+	require.NoError(err)
+	require.NoError(s.state.JobComplete(job.Id, &pb.Job_Result{}, nil))
+	require.NoError(s.state.JobCreate(serverptypes.TestJobNew(t, &pb.Job{
+		Id: "j3",
+		Operation: &pb.Job_Deploy{
+			Deploy: &pb.Job_DeployOp{
+				Artifact: &pb.PushedArtifact{Id: "a2"},
+			},
+		},
+	})))
+	job, err = s.state.JobAssignForRunner(ctx, &pb.Runner{Id: "R_A"})
+	require.NoError(err)
+	require.Equal("j3", job.Id)
+	_, err = s.state.JobAck(job.Id, true)
+	require.NoError(err)
+	require.NoError(s.state.JobComplete(job.Id, nil, errors.New("deploy failed")))
+	require.NoError(s.state.JobCreate(serverptypes.TestJobNew(t, &pb.Job{
+		Id: "j2",
+		Operation: &pb.Job_Destroy{
+			Destroy: &pb.Job_DestroyOp{
+				Target: &pb.Job_DestroyOp_Deployment{
+					Deployment: &pb.Deployment{Id: "d2"},
+				},
+			},
+		},
+	})))
+
+	// A cutoff is required
+	_, err = client.PruneData(ctx, &pb.PruneDataRequest{})
+	require.Error(err)
+	require.Equal(codes.InvalidArgument, status.Code(err))
+
+	// Nothing completed before an hour ago
+	before, err := ptypes.TimestampProto(time.Now().Add(-time.Hour))
+	require.NoError(err)
+	resp, err := client.PruneData(ctx, &pb.PruneDataRequest{Before: before})
+	require.NoError(err)
+	require.Equal(uint32(0), resp.Deployments)
+	require.Equal(uint64(0), resp.DeletedRecordBytes)
+
+	// A dry run deletes nothing. The destroyed release and deployments are
+	// pruned along with the completed jobs. The latest records are kept.
+	before, err = ptypes.TimestampProto(time.Now().Add(time.Minute))
+	require.NoError(err)
+	resp, err = client.PruneData(ctx, &pb.PruneDataRequest{Before: before, DryRun: true})
+	require.NoError(err)
+	require.Equal(uint32(2), resp.Deployments)
+	require.Equal(uint32(1), resp.Releases)
+	require.Equal(uint32(2), resp.Jobs)
+	require.True(resp.DeletedRecordBytes > 0)
+
+	deployments, err := s.state.DeploymentList(&pb.Ref_Application{Project: "p_test", Application: "a_test"})
+	require.NoError(err)
+	require.Len(deployments, 3)
+
+	// Prune
+	resp, err = client.PruneData(ctx, &pb.PruneDataRequest{Before: before})
+	require.NoError(err)
+	require.Equal(uint32(2), resp.Deployments)
+	require.Equal(uint32(1), resp.Releases)
+	require.Equal(uint32(2), resp.Jobs)
+
+	for _, id := range []string{"d1", "d2"} {
+		_, err = s.state.DeploymentGet(&pb.Ref_Operation{Target: &pb.Ref_Operation_Id{Id: id}})
+		require.Equal(codes.NotFound, status.Code(err))
+	}
+	_, err = s.state.ReleaseGet(&pb.Ref_Operation{Target: &pb.Ref_Operation_Id{Id: "r1"}})
+	require.Equal(codes.NotFound, status.Code(err))
+	_, err = s.state.DeploymentGet(&pb.Ref_Operation{Target: &pb.Ref_Operation_Id{Id: "d3"}})
+	require.NoError(err)
+
+	for _, id := range []string{"j1", "j3"} {
+		job, err = s.state.JobById(id, nil)
+		require.NoError(err)
+		require.Nil(job)
+	}
+	job, err = s.state.JobById("j2", nil)
+	require.NoError(err)
+	require.NotNil(job)
+
+	// Nothing more to prune
+	resp, err = client.PruneData(ctx, &pb.PruneDataRequest{Before: before})
+	require.NoError(err)
+	require.Equal(uint32(0), resp.Deployments)
+	require.Equal(uint32(0), resp.Jobs)
+}
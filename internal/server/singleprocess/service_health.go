@@ -0,0 +1,87 @@
+package singleprocess
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/hashicorp/waypoint/internal/protocolversion"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// healthDiskMinFree is the free disk space below which the server isn't
+// ready, since writes to the database may soon fail.
+const healthDiskMinFree = 100 << 20
+
+func (s *service) GetServerHealth(
+	ctx context.Context,
+	req *empty.Empty,
+) (*pb.GetServerHealthResponse, error) {
+	result := &pb.GetServerHealthResponse{
+		Version: protocolversion.Current(),
+	}
+
+	// The database must be readable for the server to work at all.
+	dbCheck := &pb.ServerHealthCheck{Name: "db", Critical: true}
+	diskCheck := &pb.ServerHealthCheck{Name: "disk"}
+	if status, err := s.state.DBStatus(); err != nil {
+		dbCheck.Message = err.Error()
+		diskCheck.Message = "database status is unavailable"
+	} else {
+		result.DbSizeBytes = uint64(status.SizeBytes)
+		result.DiskTotalBytes = status.DiskTotalBytes
+		result.DiskFreeBytes = status.DiskFreeBytes
+
+		dbCheck.Ok = true
+		dbCheck.Message = fmt.Sprintf("%s at %s",
+			humanize.Bytes(result.DbSizeBytes), status.Path)
+
+		switch {
+		case status.DiskTotalBytes == 0:
+			diskCheck.Ok = true
+			diskCheck.Message = "disk usage is unavailable on this platform"
+
+		case status.DiskFreeBytes < healthDiskMinFree:
+			diskCheck.Message = fmt.Sprintf("only %s free of %s",
+				humanize.Bytes(status.DiskFreeBytes), humanize.Bytes(status.DiskTotalBytes))
+
+		default:
+			diskCheck.Ok = true
+			diskCheck.Message = fmt.Sprintf("%s free of %s",
+				humanize.Bytes(status.DiskFreeBytes), humanize.Bytes(status.DiskTotalBytes))
+		}
+	}
+
+	// The scheduler is working if the job queue and runners can be read.
+	schedulerCheck := &pb.ServerHealthCheck{Name: "scheduler", Critical: true}
+	if counts, err := s.state.JobCountByState(); err != nil {
+		schedulerCheck.Message = err.Error()
+	} else if runners, err := s.state.RunnerList(nil); err != nil {
+		schedulerCheck.Message = err.Error()
+	} else {
+		result.JobsQueued = uint32(counts[pb.Job_QUEUED] + counts[pb.Job_WAITING])
+		result.JobsRunning = uint32(counts[pb.Job_RUNNING])
+		result.Runners = uint32(len(runners))
+
+		schedulerCheck.Ok = true
+		schedulerCheck.Message = fmt.Sprintf(
+			"%d jobs queued, %d jobs running, %d runners connected",
+			result.JobsQueued, result.JobsRunning, result.Runners)
+	}
+
+	result.Checks = []*pb.ServerHealthCheck{dbCheck, diskCheck, schedulerCheck}
+	result.Healthy = true
+	result.Ready = true
+	for _, check := range result.Checks {
+		if !check.Ok {
+			result.Ready = false
+			if check.Critical {
+				result.Healthy = false
+			}
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,46 @@
+package singleprocess
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/waypoint/internal/server"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	serverptypes "github.com/hashicorp/waypoint/internal/server/ptypes"
+)
+
+func TestServiceGetServerHealth(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+
+	// Queue a job
+	TestApp(t, client, serverptypes.TestJobNew(t, nil).Application)
+	_, err = client.QueueJob(ctx, &pb.QueueJobRequest{
+		Job: serverptypes.TestJobNew(t, nil),
+	})
+	require.NoError(err)
+
+	resp, err := client.GetServerHealth(ctx, &empty.Empty{})
+	require.NoError(err)
+	require.True(resp.Healthy)
+	require.NotNil(resp.Version)
+	require.True(resp.DbSizeBytes > 0)
+	require.Equal(uint32(1), resp.JobsQueued)
+	require.Equal(uint32(0), resp.Runners)
+
+	names := map[string]bool{}
+	for _, check := range resp.Checks {
+		names[check.Name] = true
+		if check.Critical {
+			require.True(check.Ok, check.Message)
+		}
+	}
+	require.Equal(map[string]bool{"db": true, "disk": true, "scheduler": true}, names)
+}
@@ -2,6 +2,7 @@ package singleprocess
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/golang/protobuf/ptypes"
@@ -95,6 +96,10 @@ func (s *service) QueueJob(
 		)
 	}
 
+	if err := s.checkOrganizationQuota(&pb.Ref_Project{Project: job.Application.Project}); err != nil {
+		return nil, err
+	}
+
 	if job.DataSource == nil {
 		if project.DataSource == nil {
 			return nil, status.Errorf(codes.FailedPrecondition,
@@ -134,6 +139,11 @@ func (s *service) QueueJob(
 		return nil, err
 	}
 
+	if op := jobOperationName(job); auditJobOperations[op] {
+		s.audit(ctx, op, auditJobTarget(job), fmt.Sprintf(
+			"job %s in workspace %s", job.Id, job.Workspace.GetWorkspace()))
+	}
+
 	return &pb.QueueJobResponse{JobId: job.Id}, nil
 }
 
@@ -164,7 +174,7 @@ func (s *service) GetJobStream(
 	req *pb.GetJobStreamRequest,
 	server pb.Waypoint_GetJobStreamServer,
 ) error {
-	log := hclog.FromContext(server.Context())
+	log := hclog.FromContext(server.Context()).Named("jobs")
 	ctx := server.Context()
 
 	// Get the job
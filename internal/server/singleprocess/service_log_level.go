@@ -0,0 +1,103 @@
+package singleprocess
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint/internal/server"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func (s *service) GetLogLevel(
+	ctx context.Context,
+	req *empty.Empty,
+) (*pb.GetLogLevelResponse, error) {
+	if s.logLevels == nil {
+		return nil, status.Errorf(codes.Unimplemented,
+			"this server doesn't support changing log levels")
+	}
+
+	level, err := s.logLevels.Level("")
+	if err != nil {
+		return nil, err
+	}
+
+	result := &pb.GetLogLevelResponse{Level: server.LogLevelString(level)}
+	for _, name := range server.LogSubsystems {
+		level, err := s.logLevels.Level(name)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Subsystems = append(result.Subsystems, &pb.SubsystemLogLevel{
+			Subsystem: name,
+			Level:     server.LogLevelString(level),
+		})
+	}
+
+	return result, nil
+}
+
+func (s *service) SetLogLevel(
+	ctx context.Context,
+	req *pb.SetLogLevelRequest,
+) (*empty.Empty, error) {
+	if err := s.setLogLevels(map[string]string{req.Subsystem: req.Level}); err != nil {
+		return nil, err
+	}
+
+	target := req.Subsystem
+	if target == "" {
+		target = "server"
+	}
+	level := req.Level
+	if level == "" {
+		level = "server level"
+	}
+	s.audit(ctx, auditOpLogLevelSet, target, level)
+	return &empty.Empty{}, nil
+}
+
+// setLogLevels sets the log levels of the subsystems in levels, where the
+// empty subsystem is the server. An empty level makes a subsystem use the
+// level of the server. Nothing is set if any subsystem or level is invalid.
+// The levels aren't stored, so they last until the server restarts.
+func (s *service) setLogLevels(levels map[string]string) error {
+	if s.logLevels == nil {
+		return status.Errorf(codes.Unimplemented,
+			"this server doesn't support changing log levels")
+	}
+
+	parsed := map[string]hclog.Level{}
+	for subsystem, v := range levels {
+		if _, err := s.logLevels.Level(subsystem); err != nil {
+			return status.Errorf(codes.InvalidArgument, err.Error())
+		}
+
+		level := hclog.NoLevel
+		if v != "" {
+			level = hclog.LevelFromString(v)
+			if level == hclog.NoLevel {
+				return status.Errorf(codes.InvalidArgument,
+					"invalid log level %q, must be one of trace, debug, info, warn, or error", v)
+			}
+		}
+		if subsystem == "" && level == hclog.NoLevel {
+			return status.Errorf(codes.InvalidArgument, "the server log level is required")
+		}
+
+		parsed[subsystem] = level
+	}
+
+	for subsystem, level := range parsed {
+		if err := s.logLevels.SetLevel(subsystem, level); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package singleprocess
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint/internal/server"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func TestServiceLogLevel(t *testing.T) {
+	ctx := context.Background()
+
+	log := hclog.New(&hclog.LoggerOptions{Level: hclog.Info})
+	levels := server.NewLogLevels(log)
+
+	impl, err := New(
+		WithDB(testDB(t)),
+		WithLogger(levels.Logger()),
+		WithLogLevels(levels),
+	)
+	require.NoError(t, err)
+	client := server.TestServer(t, impl)
+
+	t.Run("get", func(t *testing.T) {
+		require := require.New(t)
+
+		resp, err := client.GetLogLevel(ctx, &empty.Empty{})
+		require.NoError(err)
+		require.Equal("info", resp.Level)
+		require.Len(resp.Subsystems, len(server.LogSubsystems))
+		for _, sub := range resp.Subsystems {
+			require.Empty(sub.Level)
+		}
+	})
+
+	t.Run("set a subsystem", func(t *testing.T) {
+		require := require.New(t)
+
+		_, err := client.SetLogLevel(ctx, &pb.SetLogLevelRequest{
+			Subsystem: "state",
+			Level:     "trace",
+		})
+		require.NoError(err)
+
+		level, err := levels.Level("state")
+		require.NoError(err)
+		require.Equal(hclog.Trace, level)
+
+		// Clear it again
+		_, err = client.SetLogLevel(ctx, &pb.SetLogLevelRequest{Subsystem: "state"})
+		require.NoError(err)
+
+		level, err = levels.Level("state")
+		require.NoError(err)
+		require.Equal(hclog.NoLevel, level)
+	})
+
+	t.Run("set the server", func(t *testing.T) {
+		require := require.New(t)
+
+		_, err := client.SetLogLevel(ctx, &pb.SetLogLevelRequest{Level: "debug"})
+		require.NoError(err)
+
+		resp, err := client.GetLogLevel(ctx, &empty.Empty{})
+		require.NoError(err)
+		require.Equal("debug", resp.Level)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		require := require.New(t)
+
+		for _, req := range []*pb.SetLogLevelRequest{
+			{Subsystem: "nope", Level: "debug"},
+			{Subsystem: "state", Level: "loud"},
+			{},
+		} {
+			_, err := client.SetLogLevel(ctx, req)
+			require.Error(err)
+			require.Equal(codes.InvalidArgument, status.Code(err))
+		}
+	})
+}
+
+func TestServiceLogLevel_unsupported(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+
+	_, err = client.GetLogLevel(ctx, &empty.Empty{})
+	require.Error(err)
+	require.Equal(codes.Unimplemented, status.Code(err))
+}
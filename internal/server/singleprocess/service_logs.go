@@ -1,14 +1,19 @@
 package singleprocess
 
 import (
+	"context"
+	"regexp"
 	"sync"
+	"time"
 
+	"github.com/golang/protobuf/ptypes"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-memdb"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	"github.com/hashicorp/waypoint/internal/server/logbuffer"
 	"github.com/hashicorp/waypoint/internal/server/singleprocess/state"
 )
 
@@ -22,9 +27,26 @@ func (s *service) GetLogStream(
 ) error {
 	log := hclog.FromContext(srv.Context())
 
-	// Default the limit
+	filter, err := newLogFilter(req)
+	if err != nil {
+		return err
+	}
+
+	// Default the limit. If we're filtering then we search the full
+	// backlog since the lines we're looking for may be anywhere in it.
 	if req.LimitBacklog == 0 {
 		req.LimitBacklog = defaultLogLimitBacklog
+		if filter.searchBacklog() {
+			req.LimitBacklog = -1
+		}
+	}
+
+	// If we have an end time, the stream ends once we reach it.
+	ctx := srv.Context()
+	if !filter.until.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, filter.until)
+		defer cancel()
 	}
 
 	var instanceFunc func(ws memdb.WatchSet) ([]*state.Instance, error)
@@ -70,6 +92,10 @@ func (s *service) GetLogStream(
 	var instanceSetLock sync.Mutex
 	instanceSet := make(map[string]struct{})
 
+	// readers tracks the running readers so that we can wait for them to
+	// send the rest of the backlog when we reach the end time.
+	var readers sync.WaitGroup
+
 	// We loop forever so that we can automatically get any new instances that
 	// join as we have an open log stream.
 	for {
@@ -85,6 +111,9 @@ func (s *service) GetLogStream(
 		for _, record := range records {
 			instanceId := record.Id
 			deploymentId := record.DeploymentId
+			if !filter.matchInstance(instanceId) {
+				continue
+			}
 
 			// If we already have a reader for this, then do nothing.
 			instanceSetLock.Lock()
@@ -95,12 +124,23 @@ func (s *service) GetLogStream(
 				continue
 			}
 
-			// Start our reader up
+			// Start our reader up. If the end time has already passed, we
+			// only send the backlog so we don't block for new lines. We can't
+			// close the reader at the end time in that case, since that
+			// would be before we read the backlog.
 			r := record.LogBuffer.Reader(req.LimitBacklog)
+			block := !filter.ended()
+			if block {
+				go r.CloseContext(ctx)
+			} else {
+				go r.CloseContext(srv.Context())
+			}
+
 			instanceLog := log.With("instance_id", instanceId)
 			instanceLog.Trace("instance log stream starting")
-			go r.CloseContext(srv.Context())
+			readers.Add(1)
 			go func() {
+				defer readers.Done()
 				defer instanceLog.Debug("instance log stream ending")
 				defer func() {
 					instanceSetLock.Lock()
@@ -109,17 +149,17 @@ func (s *service) GetLogStream(
 				}()
 
 				for {
-					entries := r.Read(64, true)
-					if entries == nil {
+					entries := r.Read(64, block)
+					if len(entries) == 0 {
 						return
 					}
 
-					lines := make([]*pb.LogBatch_Entry, len(entries))
-					for i, v := range entries {
-						lines[i] = v.(*pb.LogBatch_Entry)
+					lines := filter.lines(entries)
+					if len(lines) == 0 {
+						continue
 					}
 
-					instanceLog.Trace("sending instance log data", "entries", len(entries))
+					instanceLog.Trace("sending instance log data", "entries", len(lines))
 					srv.Send(&pb.LogBatch{
 						DeploymentId: deploymentId,
 						InstanceId:   instanceId,
@@ -130,13 +170,125 @@ func (s *service) GetLogStream(
 		}
 
 		// Wait for changes or to be done
-		if err := ws.WatchCtx(srv.Context()); err != nil {
+		if err := ws.WatchCtx(ctx); err != nil {
 			// If our context ended, exit with that
 			if err := srv.Context().Err(); err != nil {
 				return err
 			}
 
+			// If we reached the end time, wait for the readers to send
+			// what they have left and end the stream.
+			if ctx.Err() != nil {
+				readers.Wait()
+				return nil
+			}
+
 			return err
 		}
 	}
 }
+
+// logFilter filters the log lines sent by GetLogStream.
+type logFilter struct {
+	// since and until are the time range of the lines to send. These are
+	// zero if the range is open at that end.
+	since time.Time
+	until time.Time
+
+	// pattern, if non-nil, must match the lines to send.
+	pattern *regexp.Regexp
+
+	// instanceId, if non-empty, is the only instance to send lines of.
+	instanceId string
+}
+
+func newLogFilter(req *pb.GetLogStreamRequest) (*logFilter, error) {
+	f := &logFilter{instanceId: req.InstanceId}
+
+	if req.Since != nil {
+		t, err := ptypes.Timestamp(req.Since)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid since time: %s", err)
+		}
+
+		f.since = t
+	}
+
+	if req.Until != nil {
+		t, err := ptypes.Timestamp(req.Until)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid until time: %s", err)
+		}
+
+		f.until = t
+	}
+
+	if !f.since.IsZero() && !f.until.IsZero() && !f.since.Before(f.until) {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"since time must be before the until time")
+	}
+
+	if req.Pattern != "" {
+		re, err := regexp.Compile(req.Pattern)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid pattern: %s", err)
+		}
+
+		f.pattern = re
+	}
+
+	return f, nil
+}
+
+// searchBacklog returns true if the lines we're looking for may be
+// anywhere in the backlog.
+func (f *logFilter) searchBacklog() bool {
+	return !f.since.IsZero() || f.pattern != nil
+}
+
+// ended returns true if the end time has passed, so no new lines can match.
+func (f *logFilter) ended() bool {
+	return !f.until.IsZero() && !time.Now().Before(f.until)
+}
+
+// matchInstance returns true if lines of the instance with the given ID
+// should be sent.
+func (f *logFilter) matchInstance(id string) bool {
+	return f.instanceId == "" || f.instanceId == id
+}
+
+// lines returns the entries that match the filter.
+func (f *logFilter) lines(entries []logbuffer.Entry) []*pb.LogBatch_Entry {
+	result := make([]*pb.LogBatch_Entry, 0, len(entries))
+	for _, v := range entries {
+		entry := v.(*pb.LogBatch_Entry)
+		if f.match(entry) {
+			result = append(result, entry)
+		}
+	}
+
+	return result
+}
+
+// match returns true if the entry matches the filter. Entries without a
+// valid timestamp never match a time range.
+func (f *logFilter) match(entry *pb.LogBatch_Entry) bool {
+	if !f.since.IsZero() || !f.until.IsZero() {
+		t, err := ptypes.Timestamp(entry.Timestamp)
+		if err != nil {
+			return false
+		}
+		if t.Before(f.since) {
+			return false
+		}
+		if !f.until.IsZero() && !t.Before(f.until) {
+			return false
+		}
+	}
+
+	if f.pattern != nil && !f.pattern.MatchString(entry.Line) {
+		return false
+	}
+
+	return true
+}
@@ -2,11 +2,15 @@ package singleprocess
 
 import (
 	"context"
+	"io"
 	"strconv"
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/ptypes"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/hashicorp/waypoint/internal/server"
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
@@ -158,3 +162,104 @@ func TestServiceGetLogStream_byApp(t *testing.T) {
 	require.NotEmpty(batch.Lines)
 	require.Len(batch.Lines, 25)
 }
+
+func TestServiceGetLogStream_filter(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	// Create our server
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+
+	// Register our instances
+	resp, err := client.UpsertDeployment(ctx, &pb.UpsertDeploymentRequest{
+		Deployment: serverptypes.TestValidDeployment(t, &pb.Deployment{
+			Component: &pb.Component{
+				Name: "testapp",
+			},
+		}),
+	})
+	require.NoError(err)
+
+	dep := resp.Deployment
+	for _, id := range []string{"1", "2"} {
+		configClient, err := client.EntrypointConfig(ctx, &pb.EntrypointConfigRequest{
+			DeploymentId: dep.Id,
+			InstanceId:   id,
+		})
+		require.NoError(err)
+		_, err = configClient.Recv()
+		require.NoError(err)
+	}
+
+	// Send some log messages to both instances
+	since := ptypes.TimestampNow()
+	logSendClient, err := client.EntrypointLogStream(ctx)
+	require.NoError(err)
+	for _, id := range []string{"1", "2"} {
+		var entries []*pb.LogBatch_Entry
+		for i := 0; i < 10; i++ {
+			entries = append(entries, &pb.LogBatch_Entry{
+				Timestamp: ptypes.TimestampNow(),
+				Line:      strconv.Itoa(i),
+			})
+		}
+
+		logSendClient.Send(&pb.EntrypointLogBatch{
+			InstanceId: id,
+			Lines:      entries,
+		})
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	// Download the matching logs of one instance. Since the end time has
+	// passed, the stream should end after the backlog.
+	logRecvClient, err := client.GetLogStream(ctx, &pb.GetLogStreamRequest{
+		Scope: &pb.GetLogStreamRequest_DeploymentId{
+			DeploymentId: dep.Id,
+		},
+		Since:      since,
+		Until:      ptypes.TimestampNow(),
+		Pattern:    "^[37]$",
+		InstanceId: "2",
+	})
+	require.NoError(err)
+
+	var lines []string
+	for {
+		batch, err := logRecvClient.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(err)
+		require.Equal("2", batch.InstanceId)
+
+		for _, entry := range batch.Lines {
+			lines = append(lines, entry.Line)
+		}
+	}
+	require.Equal([]string{"3", "7"}, lines)
+}
+
+func TestServiceGetLogStream_invalidPattern(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	// Create our server
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+
+	logRecvClient, err := client.GetLogStream(ctx, &pb.GetLogStreamRequest{
+		Scope: &pb.GetLogStreamRequest_DeploymentId{
+			DeploymentId: "A",
+		},
+		Pattern: "[",
+	})
+	require.NoError(err)
+
+	_, err = logRecvClient.Recv()
+	require.Error(err)
+	require.Equal(codes.InvalidArgument, status.Code(err))
+}
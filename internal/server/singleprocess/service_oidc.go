@@ -0,0 +1,496 @@
+package singleprocess
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"golang.org/x/oauth2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	"github.com/hashicorp/waypoint/internal/serverconfig"
+)
+
+// oidcDefaultTokenTTL is how long the tokens of users that log in with
+// OIDC are valid for if the config doesn't say.
+const oidcDefaultTokenTTL = 8 * time.Hour
+
+func (s *service) GetOIDCAuthURL(
+	ctx context.Context,
+	req *pb.GetOIDCAuthURLRequest,
+) (*pb.GetOIDCAuthURLResponse, error) {
+	if s.oidc == nil {
+		return nil, errOIDCNotConfigured
+	}
+	if req.State == "" || req.Nonce == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "state and nonce are required")
+	}
+
+	url, err := s.oidc.authURL(ctx, req.RedirectUri, req.State, req.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetOIDCAuthURLResponse{Url: url}, nil
+}
+
+func (s *service) CompleteOIDCAuth(
+	ctx context.Context,
+	req *pb.CompleteOIDCAuthRequest,
+) (*pb.CompleteOIDCAuthResponse, error) {
+	if s.oidc == nil {
+		return nil, errOIDCNotConfigured
+	}
+	if req.Code == "" || req.Nonce == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "code and nonce are required")
+	}
+
+	claims, err := s.oidc.login(ctx, req.RedirectUri, req.Code, req.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := s.oidc.access(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	// The token is a normal login token except that it is for the user
+	// from the provider and expires.
+	body.Login = true
+	body.TokenId = make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, body.TokenId); err != nil {
+		return nil, err
+	}
+	body.ValidUntil, err = ptypes.TimestampProto(time.Now().Add(s.oidc.tokenTTL))
+	if err != nil {
+		return nil, err
+	}
+
+	keyId, err := s.tokenKeyId()
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := s.GenerateToken(keyId, nil, body)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, auditOpTokenCreate, "", fmt.Sprintf(
+		"OIDC login token for %s with the %s role", body.User, roleName(body.Role)))
+	return &pb.CompleteOIDCAuthResponse{Token: token, User: body.User}, nil
+}
+
+var errOIDCNotConfigured = status.Errorf(codes.FailedPrecondition,
+	"OIDC login is not configured on the server")
+
+// oidcCookie is the cookie that holds the state and nonce of a browser
+// login between the redirect to the provider and the callback.
+const oidcCookie = "waypoint-oidc"
+
+// OIDCHTTPHandler implements server.OIDCHandler. The browser UI links to
+// "/oidc/login", which redirects to the provider. The provider redirects
+// back to "/oidc/callback", which redirects to the UI with the token or
+// error in the URL fragment so that it isn't sent to any server.
+func (s *service) OIDCHTTPHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oidc/login", s.oidcHTTPLogin)
+	mux.HandleFunc("/oidc/callback", s.oidcHTTPCallback)
+	return mux
+}
+
+func (s *service) oidcHTTPLogin(w http.ResponseWriter, r *http.Request) {
+	state, err := oidcRandom()
+	if err != nil {
+		oidcHTTPError(w, r, err)
+		return
+	}
+	nonce, err := oidcRandom()
+	if err != nil {
+		oidcHTTPError(w, r, err)
+		return
+	}
+
+	resp, err := s.GetOIDCAuthURL(r.Context(), &pb.GetOIDCAuthURLRequest{
+		RedirectUri: oidcRedirectURI(r),
+		State:       state,
+		Nonce:       nonce,
+	})
+	if err != nil {
+		oidcHTTPError(w, r, err)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcCookie,
+		Value:    state + "." + nonce,
+		Path:     "/oidc/",
+		MaxAge:   600,
+		Secure:   r.TLS != nil,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, resp.Url, http.StatusFound)
+}
+
+func (s *service) oidcHTTPCallback(w http.ResponseWriter, r *http.Request) {
+	// The cookie is only used once.
+	http.SetCookie(w, &http.Cookie{Name: oidcCookie, Path: "/oidc/", MaxAge: -1})
+
+	query := r.URL.Query()
+	if v := query.Get("error"); v != "" {
+		if desc := query.Get("error_description"); desc != "" {
+			v += ": " + desc
+		}
+
+		oidcHTTPError(w, r, status.Errorf(codes.Unauthenticated, "OIDC provider returned an error: %s", v))
+		return
+	}
+
+	var state, nonce string
+	if c, err := r.Cookie(oidcCookie); err == nil {
+		idx := strings.IndexByte(c.Value, '.')
+		if idx > 0 {
+			state, nonce = c.Value[:idx], c.Value[idx+1:]
+		}
+	}
+	if state == "" || query.Get("state") != state {
+		oidcHTTPError(w, r, status.Errorf(codes.Unauthenticated,
+			"login state doesn't match, please try logging in again"))
+		return
+	}
+
+	resp, err := s.CompleteOIDCAuth(r.Context(), &pb.CompleteOIDCAuthRequest{
+		RedirectUri: oidcRedirectURI(r),
+		Code:        query.Get("code"),
+		Nonce:       nonce,
+	})
+	if err != nil {
+		oidcHTTPError(w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, "/auth/oidc#"+url.Values{"token": {resp.Token}}.Encode(), http.StatusFound)
+}
+
+// oidcHTTPError redirects to the UI to show the error.
+func oidcHTTPError(w http.ResponseWriter, r *http.Request, err error) {
+	msg := err.Error()
+	if st, ok := status.FromError(err); ok {
+		msg = st.Message()
+	}
+
+	http.Redirect(w, r, "/auth/oidc#"+url.Values{"error": {msg}}.Encode(), http.StatusFound)
+}
+
+// oidcRedirectURI returns the URI the provider redirects the browser to
+// after logging in, which must be allowed in the OIDC configuration.
+func oidcRedirectURI(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	return scheme + "://" + r.Host + "/oidc/callback"
+}
+
+// oidcRandom returns a random value for the state or nonce of a login.
+func oidcRandom() (string, error) {
+	var buf [16]byte
+	if _, err := io.ReadFull(rand.Reader, buf[:]); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf[:]), nil
+}
+
+// oidcProvider logs users in with an OpenID Connect provider using the
+// authorization code flow. The provider configuration and keys are
+// fetched for each login so that key rotation needs no restart.
+type oidcProvider struct {
+	config   *serverconfig.OIDC
+	tokenTTL time.Duration
+	roles    []pb.Token_Role
+	client   *http.Client
+}
+
+func newOIDCProvider(cfg *serverconfig.OIDC) (*oidcProvider, error) {
+	if cfg.DiscoveryURL == "" || cfg.ClientID == "" {
+		return nil, fmt.Errorf("oidc discovery_url and client_id are required")
+	}
+	if len(cfg.AllowedRedirectURIs) == 0 {
+		return nil, fmt.Errorf("oidc allowed_redirect_uris must have at least one URI")
+	}
+
+	p := &oidcProvider{
+		config:   cfg,
+		tokenTTL: oidcDefaultTokenTTL,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+
+	if cfg.TokenTTL != "" {
+		ttl, err := time.ParseDuration(cfg.TokenTTL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid oidc token_ttl: %s", err)
+		}
+		if ttl <= 0 {
+			return nil, fmt.Errorf("oidc token_ttl must be positive")
+		}
+
+		p.tokenTTL = ttl
+	}
+
+	for i, a := range cfg.Access {
+		role, ok := parseRole(a.Role)
+		if !ok {
+			return nil, fmt.Errorf(
+				"oidc access block %d: role must be one of \"admin\", \"operator\", or \"read-only\", got %q",
+				i+1, a.Role)
+		}
+
+		p.roles = append(p.roles, role)
+	}
+
+	return p, nil
+}
+
+// oidcDiscovery is the part of the provider configuration that we use.
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+func (p *oidcProvider) discover(ctx context.Context) (*oidcDiscovery, error) {
+	issuer := strings.TrimSuffix(p.config.DiscoveryURL, "/")
+
+	var d oidcDiscovery
+	if err := p.getJSON(ctx, issuer+"/.well-known/openid-configuration", &d); err != nil {
+		return nil, err
+	}
+
+	// The issuer must match so that tokens from other issuers at the
+	// same provider aren't accepted.
+	if strings.TrimSuffix(d.Issuer, "/") != issuer {
+		return nil, status.Errorf(codes.Unavailable,
+			"OIDC provider issuer %q doesn't match the discovery URL %q", d.Issuer, issuer)
+	}
+
+	return &d, nil
+}
+
+func (p *oidcProvider) getJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "error contacting OIDC provider: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return status.Errorf(codes.Unavailable,
+			"OIDC provider returned status %d for %s", resp.StatusCode, url)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return status.Errorf(codes.Unavailable,
+			"error decoding response of OIDC provider for %s: %s", url, err)
+	}
+
+	return nil
+}
+
+func (p *oidcProvider) oauth2Config(d *oidcDiscovery, redirectURI string) (*oauth2.Config, error) {
+	var allowed bool
+	for _, uri := range p.config.AllowedRedirectURIs {
+		if uri == redirectURI {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"redirect URI %q is not allowed by the server configuration", redirectURI)
+	}
+
+	return &oauth2.Config{
+		ClientID:     p.config.ClientID,
+		ClientSecret: p.config.ClientSecret,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  d.AuthorizationEndpoint,
+			TokenURL: d.TokenEndpoint,
+		},
+		RedirectURL: redirectURI,
+		Scopes:      append([]string{"openid"}, p.config.Scopes...),
+	}, nil
+}
+
+// authURL returns the URL of the provider to log in at.
+func (p *oidcProvider) authURL(ctx context.Context, redirectURI, state, nonce string) (string, error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	cfg, err := p.oauth2Config(d, redirectURI)
+	if err != nil {
+		return "", err
+	}
+
+	return cfg.AuthCodeURL(state, oauth2.SetAuthURLParam("nonce", nonce)), nil
+}
+
+// login exchanges the code for an ID token and returns its claims once
+// the token is verified.
+func (p *oidcProvider) login(
+	ctx context.Context,
+	redirectURI, code, nonce string,
+) (map[string]interface{}, error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := p.oauth2Config(d, redirectURI)
+	if err != nil {
+		return nil, err
+	}
+
+	tok, err := cfg.Exchange(context.WithValue(ctx, oauth2.HTTPClient, p.client), code)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "error exchanging OIDC code: %s", err)
+	}
+
+	raw, ok := tok.Extra("id_token").(string)
+	if !ok || raw == "" {
+		return nil, status.Errorf(codes.Unauthenticated, "OIDC provider didn't return an ID token")
+	}
+
+	return p.verify(ctx, d, raw, nonce)
+}
+
+// verify checks the signature and claims of an ID token and returns its
+// claims.
+func (p *oidcProvider) verify(
+	ctx context.Context,
+	d *oidcDiscovery,
+	raw, nonce string,
+) (map[string]interface{}, error) {
+	idToken, err := jwt.ParseSigned(raw)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid ID token: %s", err)
+	}
+	if len(idToken.Headers) != 1 {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid ID token: expected one signature")
+	}
+
+	var keySet jose.JSONWebKeySet
+	if err := p.getJSON(ctx, d.JWKSURI, &keySet); err != nil {
+		return nil, err
+	}
+
+	keys := keySet.Keys
+	if kid := idToken.Headers[0].KeyID; kid != "" {
+		keys = keySet.Key(kid)
+	}
+
+	var claims jwt.Claims
+	var all map[string]interface{}
+	verified := false
+	for _, key := range keys {
+		if err := idToken.Claims(key, &claims, &all); err == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, status.Errorf(codes.Unauthenticated,
+			"ID token isn't signed by a key of the OIDC provider")
+	}
+
+	err = claims.ValidateWithLeeway(jwt.Expected{
+		Issuer:   d.Issuer,
+		Audience: jwt.Audience{p.config.ClientID},
+		Time:     time.Now(),
+	}, time.Minute)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid ID token: %s", err)
+	}
+
+	if v, _ := all["nonce"].(string); v != nonce {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid ID token: nonce doesn't match")
+	}
+
+	return all, nil
+}
+
+// access returns the token body for the user with the claims, with the
+// user and access set. The first access block that matches is used.
+func (p *oidcProvider) access(claims map[string]interface{}) (*pb.Token, error) {
+	userClaim := p.config.UserClaim
+	if userClaim == "" {
+		userClaim = "sub"
+	}
+
+	user, _ := claims[userClaim].(string)
+	if user == "" {
+		return nil, status.Errorf(codes.PermissionDenied,
+			"ID token has no %q claim to use as the user", userClaim)
+	}
+
+	groups := map[string]bool{}
+	if p.config.GroupsClaim != "" {
+		switch v := claims[p.config.GroupsClaim].(type) {
+		case string:
+			groups[v] = true
+
+		case []interface{}:
+			for _, g := range v {
+				if g, ok := g.(string); ok {
+					groups[g] = true
+				}
+			}
+		}
+	}
+
+	for i, a := range p.config.Access {
+		match := len(a.Groups) == 0
+		for _, g := range a.Groups {
+			if groups[g] {
+				match = true
+				break
+			}
+		}
+		if !match {
+			continue
+		}
+
+		return &pb.Token{
+			User:       user,
+			Role:       p.roles[i],
+			Projects:   a.Projects,
+			Workspaces: a.Workspaces,
+		}, nil
+	}
+
+	return nil, status.Errorf(codes.PermissionDenied,
+		"user %q is not allowed to log in to this server", user)
+}
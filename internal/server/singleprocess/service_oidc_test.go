@@ -0,0 +1,209 @@
+package singleprocess
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	"github.com/hashicorp/waypoint/internal/server"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	"github.com/hashicorp/waypoint/internal/serverconfig"
+)
+
+func TestServiceOIDC(t *testing.T) {
+	ctx := context.Background()
+
+	// Our fake provider returns an ID token for whatever claims are set.
+	var claims map[string]interface{}
+	provider := testOIDCProvider(t, func() map[string]interface{} { return claims })
+
+	const redirectURI = "http://localhost:9711/oidc/callback"
+	impl, err := New(WithDB(testDB(t)), WithConfig(&serverconfig.Config{
+		OIDC: &serverconfig.OIDC{
+			DiscoveryURL:        provider.URL,
+			ClientID:            "waypoint",
+			AllowedRedirectURIs: []string{redirectURI},
+			GroupsClaim:         "groups",
+			Access: []*serverconfig.OIDCAccess{
+				{Groups: []string{"platform"}, Role: "admin"},
+				{Groups: []string{"dev"}, Role: "read-only", Projects: []string{"p_test"}},
+			},
+		},
+	}))
+	require.NoError(t, err)
+	client := server.TestServer(t, impl)
+	s := impl.(*service)
+
+	t.Run("auth URL", func(t *testing.T) {
+		require := require.New(t)
+
+		resp, err := client.GetOIDCAuthURL(ctx, &pb.GetOIDCAuthURLRequest{
+			RedirectUri: redirectURI,
+			State:       "state",
+			Nonce:       "nonce",
+		})
+		require.NoError(err)
+		require.True(strings.HasPrefix(resp.Url, provider.URL+"/authorize?"))
+		require.Contains(resp.Url, "nonce=nonce")
+		require.Contains(resp.Url, "state=state")
+
+		_, err = client.GetOIDCAuthURL(ctx, &pb.GetOIDCAuthURLRequest{
+			RedirectUri: "http://evil.example.com/oidc/callback",
+			State:       "state",
+			Nonce:       "nonce",
+		})
+		require.Error(err)
+		require.Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("login maps groups to access", func(t *testing.T) {
+		require := require.New(t)
+
+		claims = map[string]interface{}{
+			"sub":    "alice",
+			"nonce":  "nonce",
+			"groups": []string{"dev"},
+		}
+		resp, err := client.CompleteOIDCAuth(ctx, &pb.CompleteOIDCAuthRequest{
+			RedirectUri: redirectURI,
+			Code:        "code",
+			Nonce:       "nonce",
+		})
+		require.NoError(err)
+		require.Equal("alice", resp.User)
+
+		_, body, err := s.DecodeToken(resp.Token)
+		require.NoError(err)
+		require.Equal("alice", body.User)
+		require.Equal(pb.Token_READ_ONLY, body.Role)
+		require.Equal([]string{"p_test"}, body.Projects)
+		require.NotNil(body.ValidUntil)
+
+		// The token can be used
+		_, err = s.Authenticate(ctx, resp.Token, "ListBuilds", []string{"readonly"})
+		require.NoError(err)
+	})
+
+	t.Run("users without access are denied", func(t *testing.T) {
+		require := require.New(t)
+
+		claims = map[string]interface{}{
+			"sub":    "mallory",
+			"nonce":  "nonce",
+			"groups": []string{"sales"},
+		}
+		_, err := client.CompleteOIDCAuth(ctx, &pb.CompleteOIDCAuthRequest{
+			RedirectUri: redirectURI,
+			Code:        "code",
+			Nonce:       "nonce",
+		})
+		require.Error(err)
+		require.Equal(codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("nonce must match", func(t *testing.T) {
+		require := require.New(t)
+
+		claims = map[string]interface{}{
+			"sub":    "alice",
+			"nonce":  "other",
+			"groups": "platform",
+		}
+		_, err := client.CompleteOIDCAuth(ctx, &pb.CompleteOIDCAuthRequest{
+			RedirectUri: redirectURI,
+			Code:        "code",
+			Nonce:       "nonce",
+		})
+		require.Error(err)
+		require.Equal(codes.Unauthenticated, status.Code(err))
+	})
+}
+
+func TestServiceOIDC_notConfigured(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+
+	_, err = client.GetOIDCAuthURL(ctx, &pb.GetOIDCAuthURLRequest{
+		RedirectUri: "http://localhost:9711/oidc/callback",
+		State:       "state",
+		Nonce:       "nonce",
+	})
+	require.Error(err)
+	require.Equal(codes.FailedPrecondition, status.Code(err))
+}
+
+// testOIDCProvider starts a fake OIDC provider. The token endpoint returns
+// an ID token with the claims returned by claimsFunc plus the standard
+// claims for the "waypoint" client.
+func testOIDCProvider(t *testing.T, claimsFunc func() map[string]interface{}) *httptest.Server {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	signer, err := jose.NewSigner(jose.SigningKey{
+		Algorithm: jose.RS256,
+		Key:       jose.JSONWebKey{Key: key, KeyID: "test"},
+	}, nil)
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	writeJSON := func(w http.ResponseWriter, v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(v)
+	}
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]string{
+			"issuer":                 srv.URL,
+			"authorization_endpoint": srv.URL + "/authorize",
+			"token_endpoint":         srv.URL + "/token",
+			"jwks_uri":               srv.URL + "/keys",
+		})
+	})
+
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &key.PublicKey, KeyID: "test", Algorithm: "RS256", Use: "sig"},
+		}})
+	})
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		now := time.Now()
+		raw, err := jwt.Signed(signer).Claims(jwt.Claims{
+			Issuer:   srv.URL,
+			Audience: jwt.Audience{"waypoint"},
+			IssuedAt: jwt.NewNumericDate(now),
+			Expiry:   jwt.NewNumericDate(now.Add(time.Minute)),
+		}).Claims(claimsFunc()).CompactSerialize()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, map[string]interface{}{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"id_token":     raw,
+		})
+	})
+
+	return srv
+}
@@ -0,0 +1,168 @@
+package singleprocess
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func (s *service) UpsertOrganization(
+	ctx context.Context,
+	req *pb.UpsertOrganizationRequest,
+) (*pb.UpsertOrganizationResponse, error) {
+	org := req.Organization
+	if org.GetName() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "organization name is required")
+	}
+	for _, p := range org.Projects {
+		if p.Project == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "project names are required")
+		}
+	}
+
+	if err := s.state.OrganizationPut(org); err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, auditOpOrganizationUpsert, org.Name, fmt.Sprintf(
+		"%d projects, max jobs %d, max storage %d bytes",
+		len(org.Projects), org.Quota.GetMaxJobs(), org.Quota.GetMaxStorageBytes()))
+	return &pb.UpsertOrganizationResponse{Organization: org}, nil
+}
+
+func (s *service) GetOrganization(
+	ctx context.Context,
+	req *pb.GetOrganizationRequest,
+) (*pb.GetOrganizationResponse, error) {
+	org, err := s.state.OrganizationGet(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := s.organizationUsage(org)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetOrganizationResponse{Organization: org, Usage: usage}, nil
+}
+
+func (s *service) ListOrganizations(
+	ctx context.Context,
+	req *empty.Empty,
+) (*pb.ListOrganizationsResponse, error) {
+	orgs, err := s.state.OrganizationList()
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListOrganizationsResponse{Organizations: orgs}, nil
+}
+
+func (s *service) DeleteOrganization(
+	ctx context.Context,
+	req *pb.DeleteOrganizationRequest,
+) (*empty.Empty, error) {
+	// Report organizations that don't exist rather than doing nothing.
+	if _, err := s.state.OrganizationGet(req.Name); err != nil {
+		return nil, err
+	}
+
+	if err := s.state.OrganizationDelete(req.Name); err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, auditOpOrganizationDelete, req.Name, "")
+	return &empty.Empty{}, nil
+}
+
+// organizationUsage returns the use of the resources that the quota of
+// an organization limits.
+func (s *service) organizationUsage(org *pb.Organization) (*pb.OrganizationUsage, error) {
+	projects := map[string]bool{}
+	for _, p := range org.Projects {
+		projects[strings.ToLower(p.Project)] = true
+	}
+
+	jobs, err := s.state.JobCountActive(projects)
+	if err != nil {
+		return nil, err
+	}
+
+	storage, err := s.state.OrganizationStorageSize(org)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.OrganizationUsage{
+		Jobs:         uint32(jobs),
+		StorageBytes: storage,
+	}, nil
+}
+
+// checkOrganizationQuota returns a ResourceExhausted error if the
+// organization of a project is at its quota, so no more jobs can be
+// queued for the project. Storage is only checked when jobs are queued,
+// so jobs that are running can go over the quota.
+func (s *service) checkOrganizationQuota(ref *pb.Ref_Project) error {
+	org, err := s.state.OrganizationForProject(ref)
+	if err != nil {
+		return err
+	}
+	if org == nil || org.Quota == nil {
+		return nil
+	}
+
+	usage, err := s.organizationUsage(org)
+	if err != nil {
+		return err
+	}
+
+	if max := org.Quota.MaxJobs; max > 0 && usage.Jobs >= max {
+		return status.Errorf(codes.ResourceExhausted,
+			"organization %q has %d jobs queued or running, which is its quota; "+
+				"please wait for some to complete", org.Name, usage.Jobs)
+	}
+
+	if max := org.Quota.MaxStorageBytes; max > 0 && usage.StorageBytes >= max {
+		return status.Errorf(codes.ResourceExhausted,
+			"organization %q uses %d bytes of storage, which is over its quota of %d bytes; "+
+				"old builds and deployments can be deleted with a retention policy",
+			org.Name, usage.StorageBytes, max)
+	}
+
+	return nil
+}
+
+// addProjectToTokenOrganization adds a project to the organization of the
+// token of the request in ctx, if it has one.
+func (s *service) addProjectToTokenOrganization(ctx context.Context, ref *pb.Ref_Project) error {
+	body := s.requestToken(ctx)
+	if body == nil || body.Organization == "" {
+		return nil
+	}
+
+	return s.state.OrganizationAddProject(body.Organization, ref)
+}
+
+// checkTokenOrganization returns an error if tokens can't be created for
+// an organization because it doesn't exist. An empty name is no
+// organization.
+func (s *service) checkTokenOrganization(name string) error {
+	if name == "" {
+		return nil
+	}
+
+	_, err := s.state.OrganizationGet(name)
+	if status.Code(err) == codes.NotFound {
+		return status.Errorf(codes.InvalidArgument, "organization %q doesn't exist", name)
+	}
+
+	return err
+}
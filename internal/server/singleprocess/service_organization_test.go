@@ -0,0 +1,170 @@
+package singleprocess
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint/internal/server"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	serverptypes "github.com/hashicorp/waypoint/internal/server/ptypes"
+)
+
+func TestServiceOrganization(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("CRUD", func(t *testing.T) {
+		require := require.New(t)
+
+		impl, err := New(WithDB(testDB(t)))
+		require.NoError(err)
+		client := server.TestServer(t, impl)
+
+		// Names are required
+		_, err = client.UpsertOrganization(ctx, &pb.UpsertOrganizationRequest{
+			Organization: &pb.Organization{},
+		})
+		require.Error(err)
+		require.Equal(codes.InvalidArgument, status.Code(err))
+
+		_, err = client.UpsertOrganization(ctx, &pb.UpsertOrganizationRequest{
+			Organization: &pb.Organization{
+				Name:     "foo",
+				Projects: []*pb.Ref_Project{{Project: "p_test"}},
+			},
+		})
+		require.NoError(err)
+
+		resp, err := client.GetOrganization(ctx, &pb.GetOrganizationRequest{Name: "foo"})
+		require.NoError(err)
+		require.Len(resp.Organization.Projects, 1)
+		require.NotNil(resp.Usage)
+
+		list, err := client.ListOrganizations(ctx, &empty.Empty{})
+		require.NoError(err)
+		require.Len(list.Organizations, 1)
+
+		_, err = client.DeleteOrganization(ctx, &pb.DeleteOrganizationRequest{Name: "foo"})
+		require.NoError(err)
+
+		// Deleting again reports that it doesn't exist
+		_, err = client.DeleteOrganization(ctx, &pb.DeleteOrganizationRequest{Name: "foo"})
+		require.Error(err)
+		require.Equal(codes.NotFound, status.Code(err))
+	})
+
+	t.Run("job quota", func(t *testing.T) {
+		require := require.New(t)
+
+		impl, err := New(WithDB(testDB(t)))
+		require.NoError(err)
+		client := server.TestServer(t, impl)
+
+		_, err = client.UpsertOrganization(ctx, &pb.UpsertOrganizationRequest{
+			Organization: &pb.Organization{
+				Name:     "foo",
+				Projects: []*pb.Ref_Project{{Project: "p_test"}},
+				Quota:    &pb.OrganizationQuota{MaxJobs: 1},
+			},
+		})
+		require.NoError(err)
+
+		job := serverptypes.TestJobNew(t, nil)
+		TestApp(t, client, job.Application)
+		_, err = client.QueueJob(ctx, &pb.QueueJobRequest{Job: job})
+		require.NoError(err)
+
+		// The first job is still queued
+		_, err = client.QueueJob(ctx, &pb.QueueJobRequest{Job: serverptypes.TestJobNew(t, nil)})
+		require.Error(err)
+		require.Equal(codes.ResourceExhausted, status.Code(err))
+
+		// Other projects aren't limited
+		otherJob := serverptypes.TestJobNew(t, &pb.Job{
+			Application: &pb.Ref_Application{Project: "p_other", Application: "a_test"},
+		})
+		TestApp(t, client, otherJob.Application)
+		_, err = client.QueueJob(ctx, &pb.QueueJobRequest{Job: otherJob})
+		require.NoError(err)
+	})
+
+	t.Run("tokens of an organization", func(t *testing.T) {
+		require := require.New(t)
+
+		impl, err := New(WithDB(testDB(t)))
+		require.NoError(err)
+		client := server.TestServer(t, impl)
+		s := impl.(*service)
+
+		TestApp(t, client, &pb.Ref_Application{Project: "p_test", Application: "a_test"})
+		TestApp(t, client, &pb.Ref_Application{Project: "p_other", Application: "a_test"})
+		_, err = client.UpsertOrganization(ctx, &pb.UpsertOrganizationRequest{
+			Organization: &pb.Organization{
+				Name:     "foo",
+				Projects: []*pb.Ref_Project{{Project: "p_test"}},
+			},
+		})
+		require.NoError(err)
+
+		// Tokens can't be created for organizations that don't exist
+		_, err = client.GenerateLoginToken(ctx, &pb.LoginTokenRequest{
+			Role:         pb.Token_OPERATOR,
+			Organization: "nope",
+		})
+		require.Error(err)
+		require.Equal(codes.InvalidArgument, status.Code(err))
+
+		tokenResp, err := client.GenerateLoginToken(ctx, &pb.LoginTokenRequest{
+			Role:         pb.Token_OPERATOR,
+			Organization: "foo",
+		})
+		require.NoError(err)
+		token := tokenResp.Token
+
+		check, err := s.AuthorizeMessages(ctx, token, "GetProject")
+		require.NoError(err)
+		require.NotNil(check)
+
+		// Projects of the organization are allowed, others aren't
+		require.NoError(check(&pb.GetProjectRequest{Project: &pb.Ref_Project{Project: "p_test"}}))
+		err = check(&pb.GetProjectRequest{Project: &pb.Ref_Project{Project: "p_other"}})
+		require.Error(err)
+		require.Equal(codes.PermissionDenied, status.Code(err))
+
+		// Only its own organization can be read
+		require.NoError(check(&pb.GetOrganizationRequest{Name: "FOO"}))
+		err = check(&pb.GetOrganizationRequest{Name: "bar"})
+		require.Error(err)
+		require.Equal(codes.PermissionDenied, status.Code(err))
+
+		// New projects can be created, but existing ones can't be taken
+		require.NoError(check(&pb.UpsertProjectRequest{Project: &pb.Project{Name: "p_new"}}))
+		err = check(&pb.UpsertProjectRequest{Project: &pb.Project{Name: "p_other"}})
+		require.Error(err)
+		require.Equal(codes.PermissionDenied, status.Code(err))
+
+		// Projects the token creates are added to the organization
+		tokenCtx := metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", token))
+		_, err = s.UpsertProject(tokenCtx, &pb.UpsertProjectRequest{
+			Project: &pb.Project{Name: "p_new"},
+		})
+		require.NoError(err)
+
+		org, err := s.state.OrganizationForProject(&pb.Ref_Project{Project: "p_new"})
+		require.NoError(err)
+		require.NotNil(org)
+		require.Equal("foo", org.Name)
+
+		// Deleting the organization denies its tokens
+		_, err = client.DeleteOrganization(ctx, &pb.DeleteOrganizationRequest{Name: "foo"})
+		require.NoError(err)
+		_, err = s.AuthorizeMessages(ctx, token, "GetProject")
+		require.Error(err)
+		require.Equal(codes.PermissionDenied, status.Code(err))
+	})
+}
@@ -19,6 +19,11 @@ func (s *service) UpsertProject(
 		return nil, err
 	}
 
+	// Projects created by tokens of an organization are in it.
+	if err := s.addProjectToTokenOrganization(ctx, &pb.Ref_Project{Project: result.Name}); err != nil {
+		return nil, err
+	}
+
 	return &pb.UpsertProjectResponse{Project: result}, nil
 }
 
@@ -47,6 +47,7 @@ func (s *service) ListReleases(
 		state.ListWithOrder(req.Order),
 		state.ListWithWorkspace(req.Workspace),
 		state.ListWithPhysicalState(req.PhysicalState),
+		state.ListWithLabels(req.Labels),
 	)
 	if err != nil {
 		return nil, err
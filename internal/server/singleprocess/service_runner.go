@@ -60,7 +60,7 @@ func (s *service) RunnerGetDeploymentConfig(
 func (s *service) RunnerConfig(
 	srv pb.Waypoint_RunnerConfigServer,
 ) error {
-	log := hclog.FromContext(srv.Context())
+	log := hclog.FromContext(srv.Context()).Named("runner")
 	ctx, cancel := context.WithCancel(srv.Context())
 	defer cancel()
 
@@ -150,7 +150,7 @@ func (s *service) RunnerConfig(
 func (s *service) RunnerJobStream(
 	server pb.Waypoint_RunnerJobStreamServer,
 ) error {
-	log := hclog.FromContext(server.Context())
+	log := hclog.FromContext(server.Context()).Named("runner")
 	ctx, cancel := context.WithCancel(server.Context())
 	defer cancel()
 
@@ -237,6 +237,10 @@ func (s *service) RunnerJobStream(
 		return err
 	}
 
+	// attempts identifies this run of the job, since the job can be run
+	// again, even by the same runner, if our lease on it expires.
+	attempts := job.Attempts
+
 	// Start a goroutine that watches for job changes
 	jobCh := make(chan *state.Job, 1)
 	errCh := make(chan error, 1)
@@ -324,7 +328,7 @@ func (s *service) RunnerJobStream(
 			for {
 				select {
 				case req := <-eventCh:
-					if err := s.handleJobStreamRequest(log, job, server, req); err != nil {
+					if err := s.handleJobStreamRequest(log, job, runner.Id, attempts, server, req); err != nil {
 						return err
 					}
 				default:
@@ -336,7 +340,7 @@ func (s *service) RunnerJobStream(
 			return err
 
 		case req := <-eventCh:
-			if err := s.handleJobStreamRequest(log, job, server, req); err != nil {
+			if err := s.handleJobStreamRequest(log, job, runner.Id, attempts, server, req); err != nil {
 				return err
 			}
 
@@ -345,6 +349,20 @@ func (s *service) RunnerJobStream(
 				continue
 			}
 
+			// If the lease of this runner on the job expired, the job was
+			// queued again for another runner, so this runner must stop.
+			if !jobLeaseHeld(job.Job, runner.Id, attempts) {
+				log.Warn("runner lost its lease on the job, canceling it on the runner",
+					"job_id", job.Id)
+				return server.Send(&pb.RunnerJobStreamResponse{
+					Event: &pb.RunnerJobStreamResponse_Cancel{
+						Cancel: &pb.RunnerJobStreamResponse_JobCancel{
+							Force: true,
+						},
+					},
+				})
+			}
+
 			// If the job is canceled, send that event. We send this each time
 			// the cancel time changes. The cancel time only changes if multiple
 			// cancel requests are made.
@@ -380,16 +398,48 @@ func (s *service) RunnerJobStream(
 func (s *service) handleJobStreamRequest(
 	log hclog.Logger,
 	job *state.Job,
+	runnerId string,
+	attempts uint32,
 	srv pb.Waypoint_RunnerJobStreamServer,
 	req *pb.RunnerJobStreamRequest,
 ) error {
 	log.Trace("event received", "event", req.Event)
+
+	// Results and heartbeats are only accepted from the runner that holds
+	// the lease on the job, since the job may have been queued again.
+	switch req.Event.(type) {
+	case *pb.RunnerJobStreamRequest_Complete_,
+		*pb.RunnerJobStreamRequest_Error_,
+		*pb.RunnerJobStreamRequest_Heartbeat_:
+		current, err := s.state.JobById(job.Id, nil)
+		if err != nil {
+			return err
+		}
+		if current == nil || !jobLeaseHeld(current.Job, runnerId, attempts) {
+			return status.Errorf(codes.FailedPrecondition,
+				"runner %s no longer holds the lease on job %s", runnerId, job.Id)
+		}
+	}
+
 	switch event := req.Event.(type) {
 	case *pb.RunnerJobStreamRequest_Complete_:
-		return s.state.JobComplete(job.Id, event.Complete.Result, nil)
+		if err := s.state.JobComplete(job.Id, event.Complete.Result, nil); err != nil {
+			return err
+		}
+
+		s.metrics.observeJob(job.Job, nil)
+		s.webhookJob(job.Job, event.Complete.Result, nil)
+		return nil
 
 	case *pb.RunnerJobStreamRequest_Error_:
-		return s.state.JobComplete(job.Id, nil, status.FromProto(event.Error.Error).Err())
+		jobErr := status.FromProto(event.Error.Error).Err()
+		if err := s.state.JobComplete(job.Id, nil, jobErr); err != nil {
+			return err
+		}
+
+		s.metrics.observeJob(job.Job, jobErr)
+		s.webhookJob(job.Job, nil, jobErr)
+		return nil
 
 	case *pb.RunnerJobStreamRequest_Heartbeat_:
 		return s.state.JobHeartbeat(job.Id)
@@ -419,3 +469,9 @@ func (s *service) handleJobStreamRequest(
 
 	return nil
 }
+
+// jobLeaseHeld returns true if the runner holds the lease on the job for
+// the given attempt of the job.
+func jobLeaseHeld(job *pb.Job, runnerId string, attempts uint32) bool {
+	return job.AssignedRunner.GetId() == runnerId && job.Attempts == attempts
+}
@@ -2,13 +2,17 @@ package singleprocess
 
 import (
 	"context"
+	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hashicorp/go-hclog"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
 	serverptypes "github.com/hashicorp/waypoint/internal/server/ptypes"
+	"github.com/hashicorp/waypoint/internal/serverconfig"
 )
 
 func (s *service) SetServerConfig(
@@ -19,7 +23,22 @@ func (s *service) SetServerConfig(
 		return nil, status.Errorf(codes.FailedPrecondition, err.Error())
 	}
 
-	if err := s.state.ServerConfigSet(req.Config); err != nil {
+	// Settings that aren't given keep their previous values.
+	current, err := s.state.ServerConfigGet()
+	if err != nil {
+		return nil, err
+	}
+	cfg := req.Config
+	if len(cfg.AdvertiseAddrs) == 0 {
+		cfg.AdvertiseAddrs = current.AdvertiseAddrs
+	}
+	if cfg.Runtime == nil {
+		cfg.Runtime = current.Runtime
+	} else if err := s.setRuntimeConfig(cfg.Runtime); err != nil {
+		return nil, err
+	}
+
+	if err := s.state.ServerConfigSet(cfg); err != nil {
 		return nil, err
 	}
 
@@ -37,3 +56,106 @@ func (s *service) GetServerConfig(
 
 	return &pb.GetServerConfigResponse{Config: cfg}, nil
 }
+
+// ReloadRuntimeConfig applies the runtime config, such as after the config
+// file of the server is read again. Settings that aren't set keep their
+// previous values.
+func (s *service) ReloadRuntimeConfig(rc *serverconfig.Runtime) error {
+	if len(rc.LogLevels) > 0 {
+		if err := s.setLogLevels(rc.LogLevels); err != nil {
+			return err
+		}
+	}
+
+	return s.mergeRuntimeConfig(runtimeConfigProto(rc))
+}
+
+// mergeRuntimeConfig applies the settings of rc that are set on top of
+// the stored runtime config, and stores the result.
+func (s *service) mergeRuntimeConfig(rc *pb.ServerRuntimeConfig) error {
+	current, err := s.state.ServerConfigGet()
+	if err != nil {
+		return err
+	}
+
+	runtime := &pb.ServerRuntimeConfig{}
+	if current.Runtime != nil {
+		proto.Merge(runtime, current.Runtime)
+	}
+	proto.Merge(runtime, rc)
+	if err := s.setRuntimeConfig(runtime); err != nil {
+		return err
+	}
+
+	cfg := proto.Clone(current).(*pb.ServerConfig)
+	cfg.Runtime = runtime
+	return s.state.ServerConfigSet(cfg)
+}
+
+// setRuntimeConfig validates the runtime config and applies it. Nothing
+// is applied if any setting is invalid.
+func (s *service) setRuntimeConfig(rc *pb.ServerRuntimeConfig) error {
+	level := hclog.NoLevel
+	if rc.LogLevel != "" {
+		level = hclog.LevelFromString(rc.LogLevel)
+		if level == hclog.NoLevel {
+			return status.Errorf(codes.InvalidArgument,
+				"invalid log_level %q, must be one of trace, debug, info, warn, or error",
+				rc.LogLevel)
+		}
+	}
+
+	var durations [5]time.Duration
+	for i, v := range []struct {
+		name  string
+		value string
+	}{
+		{"gc_interval", rc.GcInterval},
+		{"webhook_timeout", rc.WebhookTimeout},
+		{"webhook_retry_wait", rc.WebhookRetryWait},
+		{"runner_ack_timeout", rc.RunnerAckTimeout},
+		{"runner_heartbeat_timeout", rc.RunnerHeartbeatTimeout},
+	} {
+		if v.value == "" {
+			continue
+		}
+
+		d, err := time.ParseDuration(v.value)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid %s: %s", v.name, err)
+		}
+		if d < 0 {
+			return status.Errorf(codes.InvalidArgument, "%s can't be negative", v.name)
+		}
+
+		durations[i] = d
+	}
+
+	if level != hclog.NoLevel {
+		s.log.SetLevel(level)
+	}
+	s.setGCSchedule(durations[0])
+	s.webhooks.configure(int(rc.WebhookAttempts), durations[1], durations[2])
+	s.state.JobTimeoutsSet(durations[3], durations[4])
+	return nil
+}
+
+// runtimeConfigProto converts the runtime config of a config file to the
+// config stored by the server.
+func runtimeConfigProto(rc *serverconfig.Runtime) *pb.ServerRuntimeConfig {
+	result := &pb.ServerRuntimeConfig{
+		LogLevel:   rc.LogLevel,
+		GcInterval: rc.GCInterval,
+	}
+	if w := rc.Webhook; w != nil {
+		result.WebhookAttempts = uint32(w.Attempts)
+		result.WebhookTimeout = w.Timeout
+		result.WebhookRetryWait = w.RetryWait
+	}
+	if r := rc.Runner; r != nil {
+		result.RunnerAckTimeout = r.AckTimeout
+		result.RunnerHeartbeatTimeout = r.HeartbeatTimeout
+	}
+
+	return result
+}
@@ -0,0 +1,84 @@
+package singleprocess
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint/internal/server"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	serverptypes "github.com/hashicorp/waypoint/internal/server/ptypes"
+	"github.com/hashicorp/waypoint/internal/serverconfig"
+)
+
+func TestServiceServerConfig_runtime(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+	s := impl.(*service)
+
+	_, err = client.SetServerConfig(ctx, &pb.SetServerConfigRequest{
+		Config: serverptypes.TestServerConfig(t, nil),
+	})
+	require.NoError(err)
+
+	// Invalid settings aren't applied
+	_, err = client.SetServerConfig(ctx, &pb.SetServerConfigRequest{
+		Config: &pb.ServerConfig{
+			Runtime: &pb.ServerRuntimeConfig{LogLevel: "loud"},
+		},
+	})
+	require.Error(err)
+	require.Equal(codes.InvalidArgument, status.Code(err))
+
+	// Runtime settings can be set without the advertise address
+	_, err = client.SetServerConfig(ctx, &pb.SetServerConfigRequest{
+		Config: &pb.ServerConfig{
+			Runtime: &pb.ServerRuntimeConfig{
+				WebhookAttempts:  5,
+				RunnerAckTimeout: "5m",
+			},
+		},
+	})
+	require.NoError(err)
+	require.Equal(5, s.webhooks.attempts)
+	ack, _ := s.state.JobTimeoutsGet()
+	require.Equal(5*time.Minute, ack)
+
+	resp, err := client.GetServerConfig(ctx, &empty.Empty{})
+	require.NoError(err)
+	require.Len(resp.Config.AdvertiseAddrs, 1)
+	require.Equal(uint32(5), resp.Config.Runtime.WebhookAttempts)
+
+	// Setting the advertise address keeps the runtime settings
+	_, err = client.SetServerConfig(ctx, &pb.SetServerConfigRequest{
+		Config: serverptypes.TestServerConfig(t, nil),
+	})
+	require.NoError(err)
+	resp, err = client.GetServerConfig(ctx, &empty.Empty{})
+	require.NoError(err)
+	require.Equal(uint32(5), resp.Config.Runtime.WebhookAttempts)
+
+	// Reloading the config file replaces the settings it sets
+	require.NoError(s.ReloadRuntimeConfig(&serverconfig.Runtime{
+		Webhook: &serverconfig.RuntimeWebhook{Timeout: "30s"},
+		Runner:  &serverconfig.RuntimeRunner{AckTimeout: "1m"},
+	}))
+	require.Equal(5, s.webhooks.attempts)
+	require.Equal(30*time.Second, s.webhooks.client.Timeout)
+	ack, _ = s.state.JobTimeoutsGet()
+	require.Equal(time.Minute, ack)
+
+	resp, err = client.GetServerConfig(ctx, &empty.Empty{})
+	require.NoError(err)
+	require.Equal("30s", resp.Config.Runtime.WebhookTimeout)
+	require.Equal("1m", resp.Config.Runtime.RunnerAckTimeout)
+}
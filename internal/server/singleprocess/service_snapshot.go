@@ -3,16 +3,21 @@ package singleprocess
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/hashicorp/go-hclog"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	"github.com/hashicorp/waypoint/internal/server/singleprocess/state"
 )
 
 func (s *service) CreateSnapshot(
@@ -29,18 +34,27 @@ func (s *service) CreateSnapshot(
 	}
 
 	// Create the snapshot and write the data
-	bw := bufio.NewWriter(&snapshotWriter{srv: srv})
+	start := time.Now()
+	bw := bufio.NewWriter(&countWriter{
+		w: &snapshotWriter{srv: srv},
+		c: s.metrics.snapshotBytes.WithLabelValues("create"),
+	})
 	if err := s.state.CreateSnapshot(bw); err != nil {
 		return err
 	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
 
-	return bw.Flush()
+	s.metrics.snapshotDuration.WithLabelValues("create").Observe(time.Since(start).Seconds())
+	return nil
 }
 
 func (s *service) RestoreSnapshot(
 	srv pb.Waypoint_RestoreSnapshotServer,
 ) error {
 	log := hclog.FromContext(srv.Context())
+	start := time.Now()
 
 	// Read our first event which must be an Open event.
 	log.Trace("waiting for Open message")
@@ -54,6 +68,63 @@ func (s *service) RestoreSnapshot(
 			"first message must be Open type")
 	}
 
+	// Validate our options up front so that we don't read all the data
+	// just to fail.
+	if err := state.ValidateRecordTypes(open.Open.SkipRecordTypes); err != nil {
+		return err
+	}
+	if v := open.Open.SchemaVersion; v > 0 {
+		// The schema version in the data is checked again when the restore
+		// is staged, this lets us refuse the restore before receiving it.
+		if err := state.CheckSnapshotSchema(v, open.Open.SnapshotVersion); err != nil {
+			return err
+		}
+	}
+
+	var restoreOpts []state.RestoreOption
+	if v := open.Open.SkipRecordTypes; len(v) > 0 {
+		restoreOpts = append(restoreOpts, state.WithRestoreSkipRecordTypes(v...))
+	}
+	if v := open.Open.ApplyRate; v > 0 {
+		restoreOpts = append(restoreOpts, state.WithRestoreApplyRate(int(v)))
+	}
+	if open.Open.DryRun {
+		restoreOpts = append(restoreOpts, state.WithRestoreDryRun())
+	}
+	if v := open.Open.Projects; len(v) > 0 {
+		restoreOpts = append(restoreOpts, state.WithRestoreProjects(v...))
+	}
+	if v := open.Open.Apps; len(v) > 0 {
+		refs := make([]*pb.Ref_Application, len(v))
+		for i, app := range v {
+			idx := strings.Index(app, "/")
+			if idx <= 0 || idx == len(app)-1 {
+				return status.Errorf(codes.InvalidArgument,
+					"app %q must be in the form <project>/<app>", app)
+			}
+
+			refs[i] = &pb.Ref_Application{
+				Project:     app[:idx],
+				Application: app[idx+1:],
+			}
+		}
+
+		restoreOpts = append(restoreOpts, state.WithRestoreApps(refs...))
+	}
+	if v := open.Open.RenameProjects; len(v) > 0 {
+		restoreOpts = append(restoreOpts, state.WithRestoreRenameProjects(v))
+	}
+
+	// If the restore is resumable, we store the data as it is received
+	// and only stage the restore once we have all of it.
+	if open.Open.ResumeId != "" {
+		return s.restoreSnapshotResumable(log, srv, start, open.Open, restoreOpts)
+	}
+	if open.Open.Offset > 0 {
+		return status.Errorf(codes.InvalidArgument,
+			"offset can only be set with resume_id")
+	}
+
 	// Start our receive loop to read data from the client
 	clientEventCh := make(chan *pb.RestoreSnapshotRequest)
 	clientCloseCh := make(chan error, 1)
@@ -89,11 +160,12 @@ func (s *service) RestoreSnapshot(
 	go func() {
 		defer close(restoreCloseCh)
 		defer pr.Close()
-		restoreCloseCh <- s.state.StageRestoreSnapshot(pr)
+		restoreCloseCh <- s.state.StageRestoreSnapshot(pr, restoreOpts...)
 	}()
 
 	// Buffer our writes so that we store some window of restore data in memory
 	bw := bufio.NewWriterSize(pw, 1024*1024) // buffer 1 MB of write data
+	restoreBytes := s.metrics.snapshotBytes.WithLabelValues("restore")
 
 	// Loop through and read events
 	for {
@@ -117,7 +189,13 @@ func (s *service) RestoreSnapshot(
 			}
 
 			// Restore was successful.
-			if open.Open.Exit {
+			s.metrics.snapshotDuration.WithLabelValues("restore").Observe(time.Since(start).Seconds())
+			if !open.Open.DryRun {
+				s.audit(srv.Context(), auditOpRestoreSnapshot, "", "")
+				s.webhookRestore()
+			}
+			if open.Open.Exit && !open.Open.DryRun {
+				s.webhooks.wait(webhookExitTimeout)
 				log.Warn("restore requested exit, closing database and exiting NOW")
 				s.state.Close()
 				os.Exit(2) // kind of a weird exit code to note this was manufactured
@@ -158,8 +236,232 @@ func (s *service) RestoreSnapshot(
 				return status.Errorf(codes.Aborted,
 					"error reading request data: %s", err)
 			}
+			restoreBytes.Add(float64(len(chunk.Chunk)))
+		}
+	}
+}
+
+func (s *service) ListSnapshots(
+	ctx context.Context,
+	req *empty.Empty,
+) (*pb.ListSnapshotsResponse, error) {
+	if s.snapshots == nil {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"server is not configured with a snapshot path or bucket")
+	}
+
+	snapshots, err := s.snapshots.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListSnapshotsResponse{Snapshots: snapshots}, nil
+}
+
+func (s *service) DeleteSnapshot(
+	ctx context.Context,
+	req *pb.DeleteSnapshotRequest,
+) (*empty.Empty, error) {
+	if s.snapshots == nil {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"server is not configured with a snapshot path or bucket")
+	}
+
+	// Only allow deleting snapshots so that this can't be used to delete
+	// arbitrary files.
+	if !isScheduledSnapshotName(req.Name) {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"%q is not a snapshot name", req.Name)
+	}
+
+	err := s.snapshots.Delete(ctx, req.Name)
+	if os.IsNotExist(err) {
+		return nil, status.Errorf(codes.NotFound,
+			"snapshot %q not found", req.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	hclog.FromContext(ctx).Info("deleted snapshot", "name", req.Name)
+	return &empty.Empty{}, nil
+}
+
+func (s *service) CreateStoredSnapshot(
+	ctx context.Context,
+	req *empty.Empty,
+) (*pb.StoredSnapshot, error) {
+	if s.snapshots == nil {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"server is not configured with a snapshot path or bucket")
+	}
+
+	name, err := s.storeSnapshot(ctx, s.snapshots, "stored", time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := s.snapshots.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, snapshot := range snapshots {
+		if snapshot.Name == name {
+			hclog.FromContext(ctx).Info("stored snapshot",
+				"name", name, "store", s.snapshots.String())
+			return snapshot, nil
 		}
 	}
+
+	return nil, status.Errorf(codes.Internal,
+		"snapshot %q was stored but isn't listed", name)
+}
+
+// restoreSnapshotResumable receives the restore data for a resumable
+// restore and stores it. If the stream fails, the data received so far is
+// kept so that the client can resume the restore with a new stream. Once
+// the client closes the stream, the stored data is staged for restore.
+func (s *service) restoreSnapshotResumable(
+	log hclog.Logger,
+	srv pb.Waypoint_RestoreSnapshotServer,
+	start time.Time,
+	open *pb.RestoreSnapshotRequest_Open,
+	restoreOpts []state.RestoreOption,
+) error {
+	log = log.With("resume_id", open.ResumeId)
+	restoreBytes := s.metrics.snapshotBytes.WithLabelValues("restore")
+
+	upload, err := s.state.OpenRestoreUpload(open.ResumeId, int64(open.Offset))
+	if err != nil {
+		return err
+	}
+	log.Debug("receiving resumable restore data", "offset", open.Offset)
+
+	for {
+		req, err := srv.Recv()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			// The stream failed, keep what we have so it can be resumed.
+			log.Info("restore stream ended early, keeping data to resume", "err", err)
+			if err := upload.Close(); err != nil {
+				log.Warn("error closing restore upload", "err", err)
+			}
+
+			return err
+		}
+
+		chunk, ok := req.Event.(*pb.RestoreSnapshotRequest_Chunk)
+		if !ok {
+			upload.Close()
+			log.Info("restore received unexpected event",
+				"type", fmt.Sprintf("%T", req.Event))
+			return status.Errorf(codes.FailedPrecondition,
+				"all messages after Open must be data chunks")
+		}
+
+		if _, err := upload.Write(chunk.Chunk); err != nil {
+			upload.Close()
+			return status.Errorf(codes.Aborted,
+				"error storing request data: %s", err)
+		}
+		restoreBytes.Add(float64(len(chunk.Chunk)))
+	}
+
+	// We have all the data now so we no longer need to keep it, whether
+	// or not the restore succeeds.
+	r, err := upload.Reader()
+	if err == nil {
+		err = s.state.StageRestoreSnapshot(r, restoreOpts...)
+	}
+	if err := upload.Delete(); err != nil {
+		log.Warn("error deleting restore upload", "err", err)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Restore was successful. For a resumed restore, this is only the
+	// time taken by the final stream.
+	s.metrics.snapshotDuration.WithLabelValues("restore").Observe(time.Since(start).Seconds())
+	if !open.DryRun {
+		s.audit(srv.Context(), auditOpRestoreSnapshot, "", "resumed upload "+open.ResumeId)
+		s.webhookRestore()
+	}
+	if open.Exit && !open.DryRun {
+		s.webhooks.wait(webhookExitTimeout)
+		log.Warn("restore requested exit, closing database and exiting NOW")
+		s.state.Close()
+		os.Exit(2) // kind of a weird exit code to note this was manufactured
+	}
+
+	return srv.SendAndClose(&empty.Empty{})
+}
+
+func (s *service) GetRestoreSnapshotOffset(
+	ctx context.Context,
+	req *pb.GetRestoreSnapshotOffsetRequest,
+) (*pb.GetRestoreSnapshotOffsetResponse, error) {
+	offset, updated, err := s.state.RestoreUploadOffset(req.ResumeId)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedAt, err := ptypes.TimestampProto(updated)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetRestoreSnapshotOffsetResponse{
+		Offset:    uint64(offset),
+		UpdatedAt: updatedAt,
+	}, nil
+}
+
+func (s *service) GetRestoreSnapshotPreflight(
+	ctx context.Context,
+	req *empty.Empty,
+) (*pb.GetRestoreSnapshotPreflightResponse, error) {
+	return &pb.GetRestoreSnapshotPreflightResponse{
+		RecordTypes: state.RecordTypes(),
+	}, nil
+}
+
+const (
+	// restoreUploadMaxAge is how long the data of an interrupted resumable
+	// restore is kept for the client to resume it. Clients resume within
+	// minutes, so anything older was abandoned.
+	restoreUploadMaxAge = 24 * time.Hour
+
+	// restoreUploadExpiryInterval is how often expired restore data is
+	// deleted.
+	restoreUploadExpiryInterval = time.Hour
+)
+
+// runRestoreUploadExpiry deletes the data of abandoned resumable restores
+// at startup and then every interval. This runs for the lifetime of the
+// server.
+func (s *service) runRestoreUploadExpiry(log hclog.Logger, interval time.Duration) {
+	expire := func(now time.Time) {
+		count, err := s.state.ExpireRestoreUploads(restoreUploadMaxAge, now)
+		if err != nil {
+			log.Error("error deleting expired restore data", "err", err)
+			return
+		}
+		if count > 0 {
+			log.Info("deleted expired restore data", "count", count)
+		}
+	}
+
+	expire(time.Now())
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		expire(now)
+	}
 }
 
 type snapshotWriter struct {
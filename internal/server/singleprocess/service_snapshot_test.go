@@ -3,9 +3,17 @@ package singleprocess
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
@@ -13,6 +21,8 @@ import (
 
 	"github.com/hashicorp/waypoint/internal/server"
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	"github.com/hashicorp/waypoint/internal/server/singleprocess/state"
+	"github.com/hashicorp/waypoint/internal/serverconfig"
 )
 
 func TestServiceRestoreSnapshot_badOpen(t *testing.T) {
@@ -40,6 +50,75 @@ func TestServiceRestoreSnapshot_badOpen(t *testing.T) {
 	require.Nil(resp)
 }
 
+func TestServiceRestoreSnapshot_badSkipRecordType(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	// Create our server
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+
+	// Start a restore skipping a record type that doesn't exist
+	stream, err := client.RestoreSnapshot(ctx)
+	require.NoError(err)
+	require.NoError(stream.Send(&pb.RestoreSnapshotRequest{
+		Event: &pb.RestoreSnapshotRequest_Open_{
+			Open: &pb.RestoreSnapshotRequest_Open{
+				SkipRecordTypes: []string{"nope"},
+			},
+		},
+	}))
+
+	resp, err := stream.CloseAndRecv()
+	require.Error(err)
+	require.Equal(codes.InvalidArgument, status.Code(err))
+	require.Nil(resp)
+}
+
+func TestServiceGetRestoreSnapshotPreflight(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	// Create our server
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+
+	resp, err := client.GetRestoreSnapshotPreflight(ctx, &empty.Empty{})
+	require.NoError(err)
+	require.Equal(state.RecordTypes(), resp.RecordTypes)
+	require.Contains(resp.RecordTypes, "jobs")
+}
+
+func TestServiceRestoreSnapshot_newerSchema(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	// Create our server
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+
+	// Start a restore of a snapshot with a schema from the future
+	stream, err := client.RestoreSnapshot(ctx)
+	require.NoError(err)
+	require.NoError(stream.Send(&pb.RestoreSnapshotRequest{
+		Event: &pb.RestoreSnapshotRequest_Open_{
+			Open: &pb.RestoreSnapshotRequest_Open{
+				SnapshotVersion: "v99.0.0",
+				SchemaVersion:   99,
+			},
+		},
+	}))
+
+	resp, err := stream.CloseAndRecv()
+	require.Error(err)
+	require.Equal(codes.FailedPrecondition, status.Code(err))
+	require.Contains(err.Error(), "v99.0.0")
+	require.Nil(resp)
+}
+
 func TestServiceRestoreSnapshot_full(t *testing.T) {
 	ctx := context.Background()
 	require := require.New(t)
@@ -109,3 +188,314 @@ func TestServiceRestoreSnapshot_full(t *testing.T) {
 	require.NoError(err)
 	require.NotNil(resp)
 }
+
+func TestServiceRestoreSnapshot_dryRun(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	// Create our server
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+
+	// Take a snapshot and write the contents to a buf
+	var snapshotBuf bytes.Buffer
+	{
+		stream, err := client.CreateSnapshot(ctx, &empty.Empty{})
+		require.NoError(err)
+
+		for {
+			resp, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(err)
+
+			if chunk, ok := resp.Event.(*pb.CreateSnapshotResponse_Chunk); ok {
+				snapshotBuf.Write(chunk.Chunk)
+			}
+		}
+	}
+
+	// Dry run the restore. Exit is ignored for dry runs so this test
+	// would exit if it weren't.
+	stream, err := client.RestoreSnapshot(ctx)
+	require.NoError(err)
+	require.NoError(stream.Send(&pb.RestoreSnapshotRequest{
+		Event: &pb.RestoreSnapshotRequest_Open_{
+			Open: &pb.RestoreSnapshotRequest_Open{
+				Exit:   true,
+				DryRun: true,
+			},
+		},
+	}))
+	require.NoError(stream.Send(&pb.RestoreSnapshotRequest{
+		Event: &pb.RestoreSnapshotRequest_Chunk{
+			Chunk: snapshotBuf.Bytes(),
+		},
+	}))
+
+	resp, err := stream.CloseAndRecv()
+	require.NoError(err)
+	require.NotNil(resp)
+}
+
+func TestServiceListSnapshots(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	td, err := ioutil.TempDir("", "waypoint-snapshots")
+	require.NoError(err)
+	defer os.RemoveAll(td)
+
+	// Create our server
+	impl, err := New(WithDB(testDB(t)), WithConfig(&serverconfig.Config{
+		Snapshot: &serverconfig.Snapshot{Path: td},
+	}))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+
+	// Nothing yet
+	resp, err := client.ListSnapshots(ctx, &empty.Empty{})
+	require.NoError(err)
+	require.Empty(resp.Snapshots)
+
+	// Take a couple snapshots
+	s := impl.(*service)
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		_, err := s.storeSnapshot(ctx, s.snapshots, "scheduled", start.Add(time.Duration(i)*time.Hour))
+		require.NoError(err)
+	}
+
+	resp, err = client.ListSnapshots(ctx, &empty.Empty{})
+	require.NoError(err)
+	require.Len(resp.Snapshots, 2)
+	require.True(resp.Snapshots[0].Size > 0)
+	createTime, err := ptypes.Timestamp(resp.Snapshots[0].CreateTime)
+	require.NoError(err)
+	require.Equal(start.Unix(), createTime.Unix())
+
+	// Delete the first one
+	_, err = client.DeleteSnapshot(ctx, &pb.DeleteSnapshotRequest{
+		Name: resp.Snapshots[0].Name,
+	})
+	require.NoError(err)
+
+	resp, err = client.ListSnapshots(ctx, &empty.Empty{})
+	require.NoError(err)
+	require.Len(resp.Snapshots, 1)
+	require.Equal(start.Add(time.Hour).Unix(), resp.Snapshots[0].CreateTime.Seconds)
+
+	// Can't delete it again
+	_, err = client.DeleteSnapshot(ctx, &pb.DeleteSnapshotRequest{
+		Name: scheduledSnapshotPrefix + start.UTC().Format(scheduledSnapshotTime) + scheduledSnapshotSuffix,
+	})
+	require.Error(err)
+	require.Equal(codes.NotFound, status.Code(err))
+
+	// Can't delete other files
+	_, err = client.DeleteSnapshot(ctx, &pb.DeleteSnapshotRequest{
+		Name: "../waypoint.db",
+	})
+	require.Error(err)
+	require.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func TestServiceCreateStoredSnapshot(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	td, err := ioutil.TempDir("", "waypoint-snapshots")
+	require.NoError(err)
+	defer os.RemoveAll(td)
+
+	// Create our server
+	impl, err := New(WithDB(testDB(t)), WithConfig(&serverconfig.Config{
+		Snapshot: &serverconfig.Snapshot{Path: td},
+	}))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+
+	snapshot, err := client.CreateStoredSnapshot(ctx, &empty.Empty{})
+	require.NoError(err)
+	require.True(isScheduledSnapshotName(snapshot.Name))
+	require.True(snapshot.Size > 0)
+
+	// It is listed with the other stored snapshots
+	resp, err := client.ListSnapshots(ctx, &empty.Empty{})
+	require.NoError(err)
+	require.Len(resp.Snapshots, 1)
+	require.Equal(snapshot.Name, resp.Snapshots[0].Name)
+
+	fi, err := os.Stat(filepath.Join(td, snapshot.Name))
+	require.NoError(err)
+	require.Equal(snapshot.Size, fi.Size())
+}
+
+func TestServiceListSnapshots_noPath(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	// Create our server
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+
+	_, err = client.ListSnapshots(ctx, &empty.Empty{})
+	require.Error(err)
+	require.Equal(codes.FailedPrecondition, status.Code(err))
+}
+
+func TestServiceRestoreSnapshot_resume(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	// Create our server
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+
+	// Take a snapshot
+	var buf bytes.Buffer
+	require.NoError(impl.(*service).state.CreateSnapshot(&buf))
+	data := buf.Bytes()
+	half := len(data) / 2
+
+	// Send the first half and then drop the stream
+	{
+		ctx, cancel := context.WithCancel(ctx)
+		stream, err := client.RestoreSnapshot(ctx)
+		require.NoError(err)
+		require.NoError(stream.Send(&pb.RestoreSnapshotRequest{
+			Event: &pb.RestoreSnapshotRequest_Open_{
+				Open: &pb.RestoreSnapshotRequest_Open{
+					ResumeId: "test",
+				},
+			},
+		}))
+		require.NoError(stream.Send(&pb.RestoreSnapshotRequest{
+			Event: &pb.RestoreSnapshotRequest_Chunk{
+				Chunk: data[:half],
+			},
+		}))
+
+		// Wait for the server to store it
+		require.Eventually(func() bool {
+			resp, err := client.GetRestoreSnapshotOffset(ctx, &pb.GetRestoreSnapshotOffsetRequest{
+				ResumeId: "test",
+			})
+			return err == nil && resp.Offset == uint64(half) && resp.UpdatedAt != nil
+		}, 5*time.Second, 10*time.Millisecond)
+
+		cancel()
+	}
+
+	// Resume with the rest. The server may still be cleaning up the old
+	// stream so we retry until the upload is available.
+	require.Eventually(func() bool {
+		stream, err := client.RestoreSnapshot(ctx)
+		require.NoError(err)
+		require.NoError(stream.Send(&pb.RestoreSnapshotRequest{
+			Event: &pb.RestoreSnapshotRequest_Open_{
+				Open: &pb.RestoreSnapshotRequest_Open{
+					ResumeId: "test",
+					Offset:   uint64(half),
+				},
+			},
+		}))
+		stream.Send(&pb.RestoreSnapshotRequest{
+			Event: &pb.RestoreSnapshotRequest_Chunk{
+				Chunk: data[half:],
+			},
+		})
+
+		_, err = stream.CloseAndRecv()
+		if status.Code(err) == codes.Aborted {
+			return false
+		}
+		require.NoError(err)
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The upload is gone once the restore is staged
+	_, err = client.GetRestoreSnapshotOffset(ctx, &pb.GetRestoreSnapshotOffsetRequest{
+		ResumeId: "test",
+	})
+	require.Error(err)
+	require.Equal(codes.NotFound, status.Code(err))
+}
+
+// BenchmarkServiceRestoreSnapshot_chunkSize measures the restore throughput
+// for different sizes of the chunks sent to the server. Small chunks are
+// dominated by the overhead of each gRPC message.
+func BenchmarkServiceRestoreSnapshot_chunkSize(b *testing.B) {
+	ctx := context.Background()
+	t := benchT{b}
+	require := require.New(b)
+
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+
+	// Fill the server with enough data that the snapshot is a few MB. The
+	// names are random so that they don't compress well.
+	s := impl.(*service)
+	for i := 0; i < 100; i++ {
+		p := &pb.Project{Name: fmt.Sprintf("project-%d", i)}
+		for j := 0; j < 100; j++ {
+			name := make([]byte, 256)
+			_, err := rand.Read(name)
+			require.NoError(err)
+
+			p.Applications = append(p.Applications, &pb.Application{
+				Project: &pb.Ref_Project{Project: p.Name},
+				Name:    hex.EncodeToString(name),
+			})
+		}
+		require.NoError(s.state.ProjectPut(p))
+	}
+
+	var buf bytes.Buffer
+	require.NoError(s.state.CreateSnapshot(&buf))
+	data := buf.Bytes()
+
+	for _, size := range []int{1024, 64 * 1024, 1024 * 1024} {
+		size := size
+		b.Run(fmt.Sprintf("%dKB", size/1024), func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				stream, err := client.RestoreSnapshot(ctx)
+				require.NoError(err)
+				require.NoError(stream.Send(&pb.RestoreSnapshotRequest{
+					Event: &pb.RestoreSnapshotRequest_Open_{
+						Open: &pb.RestoreSnapshotRequest_Open{},
+					},
+				}))
+
+				for offset := 0; offset < len(data); offset += size {
+					end := offset + size
+					if end > len(data) {
+						end = len(data)
+					}
+
+					require.NoError(stream.Send(&pb.RestoreSnapshotRequest{
+						Event: &pb.RestoreSnapshotRequest_Chunk{
+							Chunk: data[offset:end],
+						},
+					}))
+				}
+
+				_, err = stream.CloseAndRecv()
+				require.NoError(err)
+			}
+		})
+	}
+}
+
+// benchT lets a benchmark use the test helpers, which expect a test.
+type benchT struct {
+	*testing.B
+}
+
+func (benchT) Parallel() {}
@@ -0,0 +1,433 @@
+package singleprocess
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint/internal/server"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// The events that are sent to webhooks. These are part of the payload
+// and the webhook config, so they must not change.
+const (
+	webhookEventBuild   = "build"
+	webhookEventDeploy  = "deploy"
+	webhookEventRelease = "release"
+	webhookEventRestore = "restore"
+)
+
+// webhookExitTimeout is how long to wait for webhooks to be sent before
+// the server exits after a restore.
+const webhookExitTimeout = 15 * time.Second
+
+// The defaults for sending webhooks, which can be changed with the runtime
+// server config.
+const (
+	webhookDefaultAttempts  = 3
+	webhookDefaultTimeout   = 10 * time.Second
+	webhookDefaultRetryWait = 5 * time.Second
+)
+
+var webhookEvents = map[string]bool{
+	webhookEventBuild:   true,
+	webhookEventDeploy:  true,
+	webhookEventRelease: true,
+	webhookEventRestore: true,
+}
+
+func (s *service) UpsertWebhook(
+	ctx context.Context,
+	req *pb.UpsertWebhookRequest,
+) (*pb.UpsertWebhookResponse, error) {
+	hook := req.Webhook
+	if hook == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "webhook is required")
+	}
+	if err := validateWebhook(hook); err != nil {
+		return nil, err
+	}
+
+	// If we have no ID, then we're inserting and need to generate an ID.
+	insert := hook.Id == ""
+	if insert {
+		id, err := server.Id()
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "uuid generation failed: %s", err)
+		}
+
+		hook.Id = id
+	} else {
+		existing, err := s.state.WebhookGet(hook.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		// Webhooks can't be moved to another project. We report the webhook
+		// as not found so that tokens limited to a project can't tell that
+		// the webhooks of other projects exist.
+		if !strings.EqualFold(existing.Project.GetProject(), hook.Project.Project) {
+			return nil, status.Errorf(codes.NotFound, "webhook not found: %s", hook.Id)
+		}
+
+		// The secret is never returned, so keep it unless a new one is set.
+		if hook.Secret == "" {
+			hook.Secret = existing.Secret
+		}
+	}
+
+	if err := s.state.WebhookPut(!insert, hook); err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, auditOpWebhookUpsert, hook.Project.Project, hook.Id+" "+hook.Url)
+	return &pb.UpsertWebhookResponse{Webhook: webhookRedacted(hook)}, nil
+}
+
+func (s *service) ListWebhooks(
+	ctx context.Context,
+	req *pb.ListWebhooksRequest,
+) (*pb.ListWebhooksResponse, error) {
+	hooks, err := s.state.WebhookList(req.Project)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, hook := range hooks {
+		hooks[i] = webhookRedacted(hook)
+	}
+
+	return &pb.ListWebhooksResponse{Webhooks: hooks}, nil
+}
+
+func (s *service) DeleteWebhook(
+	ctx context.Context,
+	req *pb.DeleteWebhookRequest,
+) (*empty.Empty, error) {
+	if req.Project == nil || req.Project.Project == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "project is required")
+	}
+
+	existing, err := s.state.WebhookGet(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(existing.Project.GetProject(), req.Project.Project) {
+		return nil, status.Errorf(codes.NotFound, "webhook not found: %s", req.Id)
+	}
+
+	if err := s.state.WebhookDelete(req.Id); err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, auditOpWebhookDelete, req.Project.Project, req.Id)
+	return &empty.Empty{}, nil
+}
+
+// validateWebhook validates the config of a webhook.
+func validateWebhook(hook *pb.Webhook) error {
+	if hook.Project == nil || hook.Project.Project == "" {
+		return status.Errorf(codes.InvalidArgument, "project is required")
+	}
+
+	u, err := url.Parse(hook.Url)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid URL: %s", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return status.Errorf(codes.InvalidArgument,
+			"URL must be an absolute http or https URL, got %q", hook.Url)
+	}
+
+	for _, e := range hook.Events {
+		if !webhookEvents[e] {
+			return status.Errorf(codes.InvalidArgument,
+				"unknown event %q, must be one of: build, deploy, release, restore", e)
+		}
+	}
+
+	return nil
+}
+
+// webhookRedacted returns a copy of hook without its secret.
+func webhookRedacted(hook *pb.Webhook) *pb.Webhook {
+	return &pb.Webhook{
+		Id:      hook.Id,
+		Project: hook.Project,
+		Url:     hook.Url,
+		Events:  hook.Events,
+	}
+}
+
+// webhookPayload is the JSON payload that is POSTed to webhooks.
+type webhookPayload struct {
+	// Id uniquely identifies this event. Receivers can use it to ignore
+	// events that are retried after they were received.
+	Id    string `json:"id"`
+	Event string `json:"event"`
+	Time  string `json:"time"`
+
+	// Status is "success" or "error". Error is set to the error message
+	// if the operation failed.
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+
+	// The operation that completed. These are empty for restores, which
+	// aren't for any project.
+	Project      string `json:"project,omitempty"`
+	Application  string `json:"application,omitempty"`
+	Workspace    string `json:"workspace,omitempty"`
+	JobId        string `json:"job_id,omitempty"`
+	BuildId      string `json:"build_id,omitempty"`
+	DeploymentId string `json:"deployment_id,omitempty"`
+	ReleaseId    string `json:"release_id,omitempty"`
+	ReleaseUrl   string `json:"release_url,omitempty"`
+}
+
+// webhookJob sends the event for a job that completed to the webhooks of
+// its project. result is nil and jobErr is set if the job failed. Jobs for
+// operations that aren't webhook events are ignored.
+func (s *service) webhookJob(job *pb.Job, result *pb.Job_Result, jobErr error) {
+	payload := &webhookPayload{
+		Project:     job.Application.GetProject(),
+		Application: job.Application.GetApplication(),
+		Workspace:   job.Workspace.GetWorkspace(),
+		JobId:       job.Id,
+	}
+
+	switch job.Operation.(type) {
+	case *pb.Job_Build:
+		payload.Event = webhookEventBuild
+		payload.BuildId = result.GetBuild().GetBuild().GetId()
+
+	case *pb.Job_Deploy:
+		payload.Event = webhookEventDeploy
+		payload.DeploymentId = result.GetDeploy().GetDeployment().GetId()
+
+	case *pb.Job_Release:
+		payload.Event = webhookEventRelease
+		payload.ReleaseId = result.GetRelease().GetRelease().GetId()
+		payload.ReleaseUrl = result.GetRelease().GetRelease().GetUrl()
+
+	default:
+		return
+	}
+
+	payload.Status = "success"
+	if jobErr != nil {
+		payload.Status = "error"
+		payload.Error = jobErr.Error()
+	}
+
+	s.sendWebhooks(&pb.Ref_Project{Project: payload.Project}, payload)
+}
+
+// webhookRestore sends the event for a restore to the webhooks of every
+// project.
+func (s *service) webhookRestore() {
+	s.sendWebhooks(nil, &webhookPayload{
+		Event:  webhookEventRestore,
+		Status: "success",
+	})
+}
+
+// sendWebhooks sends payload to the webhooks of the project that want its
+// event. If project is nil, it is sent to the webhooks of every project.
+// The webhooks are sent in the background so this doesn't block.
+func (s *service) sendWebhooks(project *pb.Ref_Project, payload *webhookPayload) {
+	log := s.webhooks.log
+
+	hooks, err := s.state.WebhookList(project)
+	if err != nil {
+		log.Error("error listing webhooks", "event", payload.Event, "err", err)
+		return
+	}
+	if len(hooks) == 0 {
+		return
+	}
+
+	payload.Id, err = server.Id()
+	if err != nil {
+		log.Error("error generating webhook event ID", "err", err)
+		return
+	}
+	payload.Time = time.Now().UTC().Format(time.RFC3339Nano)
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Error("error encoding webhook payload", "event", payload.Event, "err", err)
+		return
+	}
+
+	for _, hook := range hooks {
+		if webhookWants(hook, payload.Event) {
+			s.webhooks.send(hook, payload.Event, payload.Id, body)
+		}
+	}
+}
+
+// webhookWants returns true if the event should be sent to the webhook.
+func webhookWants(hook *pb.Webhook, event string) bool {
+	if len(hook.Events) == 0 {
+		return true
+	}
+
+	for _, e := range hook.Events {
+		if e == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+// webhookSignature returns the value of the signature header for body.
+// Receivers verify it by computing the HMAC-SHA256 of the body with the
+// secret of the webhook.
+func webhookSignature(secret string, body []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write(body)
+	return "sha256=" + hex.EncodeToString(h.Sum(nil))
+}
+
+// webhookSender POSTs payloads to webhooks in the background, retrying
+// failed attempts.
+type webhookSender struct {
+	log hclog.Logger
+
+	// client sends the payloads. attempts is how many times a payload is
+	// sent before giving up, and retryWait is how long to wait after the
+	// first failed attempt. The wait grows with each attempt. lock must be
+	// held to use these.
+	client    *http.Client
+	attempts  int
+	retryWait time.Duration
+	lock      sync.Mutex
+
+	wg sync.WaitGroup
+}
+
+func newWebhookSender(log hclog.Logger) *webhookSender {
+	w := &webhookSender{log: log}
+	w.configure(0, 0, 0)
+	return w
+}
+
+// configure sets how payloads are sent. Zero values use the defaults.
+// Payloads that are already being sent keep the previous settings.
+func (w *webhookSender) configure(attempts int, timeout, retryWait time.Duration) {
+	if attempts <= 0 {
+		attempts = webhookDefaultAttempts
+	}
+	if timeout <= 0 {
+		timeout = webhookDefaultTimeout
+	}
+	if retryWait <= 0 {
+		retryWait = webhookDefaultRetryWait
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.client = &http.Client{Timeout: timeout}
+	w.attempts = attempts
+	w.retryWait = retryWait
+}
+
+// send sends body to the webhook in the background.
+func (w *webhookSender) send(hook *pb.Webhook, event, id string, body []byte) {
+	log := w.log.With("webhook", hook.Id, "event", event, "event_id", id)
+
+	w.lock.Lock()
+	client, attempts, retryWait := w.client, w.attempts, w.retryWait
+	w.lock.Unlock()
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		for attempt := 1; ; attempt++ {
+			err := w.post(client, hook, event, id, body)
+			if err == nil {
+				log.Debug("sent webhook")
+				return
+			}
+
+			if attempt >= attempts {
+				log.Warn("error sending webhook, giving up", "attempts", attempt, "err", err)
+				return
+			}
+
+			log.Warn("error sending webhook, will retry", "attempt", attempt, "err", err)
+			time.Sleep(time.Duration(attempt) * retryWait)
+		}
+	}()
+}
+
+func (w *webhookSender) post(
+	client *http.Client,
+	hook *pb.Webhook,
+	event, id string,
+	body []byte,
+) error {
+	req, err := http.NewRequest("POST", hook.Url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "waypoint")
+	req.Header.Set("X-Waypoint-Event", event)
+	req.Header.Set("X-Waypoint-Event-Id", id)
+	if hook.Secret != "" {
+		req.Header.Set("X-Waypoint-Signature", webhookSignature(hook.Secret, body))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Read some of the body so the connection can be reused.
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+
+	return nil
+}
+
+// wait waits for the webhooks being sent for up to timeout. This returns
+// false if they weren't all sent in time.
+func (w *webhookSender) wait(timeout time.Duration) bool {
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		w.wg.Wait()
+	}()
+
+	select {
+	case <-doneCh:
+		return true
+
+	case <-time.After(timeout):
+		return false
+	}
+}
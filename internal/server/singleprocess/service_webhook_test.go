@@ -0,0 +1,184 @@
+package singleprocess
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint/internal/server"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func TestServiceWebhook(t *testing.T) {
+	ctx := context.Background()
+
+	// Create our server
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(t, err)
+	client := server.TestServer(t, impl)
+
+	project := &pb.Ref_Project{Project: "p_test"}
+
+	t.Run("create, list, and delete", func(t *testing.T) {
+		require := require.New(t)
+
+		resp, err := client.UpsertWebhook(ctx, &pb.UpsertWebhookRequest{
+			Webhook: &pb.Webhook{
+				Project: project,
+				Url:     "https://example.com/hook",
+				Secret:  "secret",
+				Events:  []string{"deploy"},
+			},
+		})
+		require.NoError(err)
+		require.NotEmpty(resp.Webhook.Id)
+		require.Empty(resp.Webhook.Secret)
+
+		list, err := client.ListWebhooks(ctx, &pb.ListWebhooksRequest{Project: project})
+		require.NoError(err)
+		require.Len(list.Webhooks, 1)
+		require.Equal("https://example.com/hook", list.Webhooks[0].Url)
+		require.Empty(list.Webhooks[0].Secret)
+
+		// Updating without a secret keeps the secret
+		hook := list.Webhooks[0]
+		hook.Events = nil
+		_, err = client.UpsertWebhook(ctx, &pb.UpsertWebhookRequest{Webhook: hook})
+		require.NoError(err)
+		stored, err := impl.(*service).state.WebhookGet(hook.Id)
+		require.NoError(err)
+		require.Equal("secret", stored.Secret)
+		require.Empty(stored.Events)
+
+		// Webhooks can't be deleted through another project
+		_, err = client.DeleteWebhook(ctx, &pb.DeleteWebhookRequest{
+			Project: &pb.Ref_Project{Project: "other"},
+			Id:      hook.Id,
+		})
+		require.Error(err)
+		require.Equal(codes.NotFound, status.Code(err))
+
+		_, err = client.DeleteWebhook(ctx, &pb.DeleteWebhookRequest{
+			Project: project,
+			Id:      hook.Id,
+		})
+		require.NoError(err)
+
+		list, err = client.ListWebhooks(ctx, &pb.ListWebhooksRequest{Project: project})
+		require.NoError(err)
+		require.Empty(list.Webhooks)
+	})
+
+	t.Run("invalid webhooks", func(t *testing.T) {
+		require := require.New(t)
+
+		for _, hook := range []*pb.Webhook{
+			{Url: "https://example.com/hook"},
+			{Project: project, Url: "example.com/hook"},
+			{Project: project, Url: "https://example.com/hook", Events: []string{"nope"}},
+		} {
+			_, err := client.UpsertWebhook(ctx, &pb.UpsertWebhookRequest{Webhook: hook})
+			require.Error(err)
+			require.Equal(codes.InvalidArgument, status.Code(err))
+		}
+	})
+}
+
+func TestServiceWebhook_send(t *testing.T) {
+	require := require.New(t)
+
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	s := impl.(*service)
+	s.webhooks.retryWait = 10 * time.Millisecond
+
+	// Our receiver fails the first request so that it is retried.
+	var lock sync.Mutex
+	var received []*http.Request
+	var bodies [][]byte
+	var failed bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if !failed {
+			failed = true
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		body, _ := ioutil.ReadAll(r.Body)
+		received = append(received, r)
+		bodies = append(bodies, body)
+	}))
+	defer srv.Close()
+
+	for _, hook := range []*pb.Webhook{
+		{Id: "deploys", Project: &pb.Ref_Project{Project: "p_test"}, Url: srv.URL, Secret: "secret", Events: []string{"deploy"}},
+		{Id: "builds", Project: &pb.Ref_Project{Project: "p_test"}, Url: srv.URL, Events: []string{"build"}},
+		{Id: "other", Project: &pb.Ref_Project{Project: "other"}, Url: srv.URL},
+	} {
+		require.NoError(s.state.WebhookPut(false, hook))
+	}
+
+	job := &pb.Job{
+		Id:          "j_test",
+		Application: &pb.Ref_Application{Project: "p_test", Application: "a_test"},
+		Workspace:   &pb.Ref_Workspace{Workspace: "default"},
+		Operation:   &pb.Job_Deploy{Deploy: &pb.Job_DeployOp{}},
+	}
+
+	// A successful deploy is only sent to the deploy webhook
+	s.webhookJob(job, &pb.Job_Result{
+		Deploy: &pb.Job_DeployResult{Deployment: &pb.Deployment{Id: "d_test"}},
+	}, nil)
+	require.True(s.webhooks.wait(5 * time.Second))
+
+	lock.Lock()
+	require.Len(received, 1)
+	require.Equal("deploy", received[0].Header.Get("X-Waypoint-Event"))
+	require.Equal(webhookSignature("secret", bodies[0]), received[0].Header.Get("X-Waypoint-Signature"))
+
+	var payload webhookPayload
+	require.NoError(json.Unmarshal(bodies[0], &payload))
+	require.Equal("deploy", payload.Event)
+	require.Equal("success", payload.Status)
+	require.Equal("p_test", payload.Project)
+	require.Equal("a_test", payload.Application)
+	require.Equal("default", payload.Workspace)
+	require.Equal("j_test", payload.JobId)
+	require.Equal("d_test", payload.DeploymentId)
+	require.NotEmpty(payload.Id)
+	lock.Unlock()
+
+	// A failed deploy reports the error
+	s.webhookJob(job, nil, errors.New("boom"))
+	require.True(s.webhooks.wait(5 * time.Second))
+
+	lock.Lock()
+	require.Len(received, 2)
+	require.NoError(json.Unmarshal(bodies[1], &payload))
+	require.Equal("error", payload.Status)
+	require.Equal("boom", payload.Error)
+	lock.Unlock()
+
+	// Restores are sent to the webhooks of every project that want them
+	s.webhookRestore()
+	require.True(s.webhooks.wait(5 * time.Second))
+
+	lock.Lock()
+	require.Len(received, 3)
+	require.Equal("restore", received[2].Header.Get("X-Waypoint-Event"))
+	require.Empty(received[2].Header.Get("X-Waypoint-Signature"))
+	lock.Unlock()
+}
@@ -0,0 +1,168 @@
+package singleprocess
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// scheduledSnapshotPrefix and scheduledSnapshotSuffix surround the
+	// timestamp in the file name of scheduled snapshots. The timestamp
+	// format sorts chronologically so that we can rotate by name.
+	scheduledSnapshotPrefix = "waypoint-"
+	scheduledSnapshotSuffix = ".snap"
+	scheduledSnapshotTime   = "20060102T150405Z"
+)
+
+// snapshotRetention is the policy for which scheduled snapshots to keep.
+// A snapshot is deleted if either limit excludes it.
+type snapshotRetention struct {
+	// count is the number of newest snapshots to keep. Zero or less keeps
+	// any number of snapshots.
+	count int
+
+	// maxAge is the maximum age of snapshots to keep. Zero keeps snapshots
+	// of any age.
+	maxAge time.Duration
+}
+
+// runSnapshotSchedule takes a snapshot every interval and writes it to
+// store, deleting old snapshots according to the retention policy. This
+// runs for the lifetime of the server.
+func (s *service) runSnapshotSchedule(log hclog.Logger, interval time.Duration, retention snapshotRetention, store snapshotStore) {
+	log.Info("scheduled snapshots enabled",
+		"interval", interval,
+		"retain", retention.count,
+		"max_age", retention.maxAge,
+		"store", store.String())
+
+	ctx := context.Background()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		name, err := s.storeSnapshot(ctx, store, "scheduled", now)
+		if err != nil {
+			log.Error("error taking scheduled snapshot", "err", err)
+			continue
+		}
+		log.Info("scheduled snapshot written", "name", name)
+
+		if err := rotateSnapshots(ctx, log, store, retention.count); err != nil {
+			log.Error("error removing old scheduled snapshots", "err", err)
+		}
+		if err := expireSnapshots(ctx, log, store, retention.maxAge, now); err != nil {
+			log.Error("error removing expired scheduled snapshots", "err", err)
+		}
+	}
+}
+
+// storeSnapshot writes a snapshot for the given time to store and returns
+// its name. operation labels the snapshot metrics.
+func (s *service) storeSnapshot(
+	ctx context.Context,
+	store snapshotStore,
+	operation string,
+	now time.Time,
+) (string, error) {
+	name := scheduledSnapshotPrefix + now.UTC().Format(scheduledSnapshotTime) + scheduledSnapshotSuffix
+
+	start := time.Now()
+	err := store.Write(ctx, name, func(w io.Writer) error {
+		return s.state.CreateSnapshot(&countWriter{
+			w: w,
+			c: s.metrics.snapshotBytes.WithLabelValues(operation),
+		})
+	})
+	if err != nil {
+		return "", err
+	}
+	s.metrics.snapshotDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+
+	return name, nil
+}
+
+// rotateSnapshots deletes all but the newest retain scheduled snapshots
+// in store. If retain is zero or less, nothing is deleted.
+func rotateSnapshots(ctx context.Context, log hclog.Logger, store snapshotStore, retain int) error {
+	if retain <= 0 {
+		return nil
+	}
+
+	snapshots, err := store.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(snapshots) <= retain {
+		return nil
+	}
+
+	for _, snapshot := range snapshots[:len(snapshots)-retain] {
+		log.Debug("removing old scheduled snapshot", "name", snapshot.Name)
+		if err := store.Delete(ctx, snapshot.Name); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// expireSnapshots deletes the scheduled snapshots in store that were
+// taken more than maxAge before now. If maxAge is zero or less, nothing
+// is deleted.
+func expireSnapshots(ctx context.Context, log hclog.Logger, store snapshotStore, maxAge time.Duration, now time.Time) error {
+	if maxAge <= 0 {
+		return nil
+	}
+
+	snapshots, err := store.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	cutoff := now.Add(-maxAge)
+	for _, snapshot := range snapshots {
+		t, ok := scheduledSnapshotTimeFromName(snapshot.Name)
+		if !ok || !t.Before(cutoff) {
+			continue
+		}
+
+		log.Debug("removing expired scheduled snapshot", "name", snapshot.Name)
+		if err := store.Delete(ctx, snapshot.Name); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isScheduledSnapshotName returns true if name is the file name of a
+// scheduled snapshot.
+func isScheduledSnapshotName(name string) bool {
+	return strings.HasPrefix(name, scheduledSnapshotPrefix) &&
+		strings.HasSuffix(name, scheduledSnapshotSuffix) &&
+		filepath.Base(name) == name
+}
+
+// scheduledSnapshotTimeFromName returns the time a scheduled snapshot was
+// taken from its file name. This returns false if the name doesn't
+// contain a valid time.
+func scheduledSnapshotTimeFromName(name string) (time.Time, bool) {
+	if !isScheduledSnapshotName(name) {
+		return time.Time{}, false
+	}
+
+	v := strings.TrimSuffix(strings.TrimPrefix(name, scheduledSnapshotPrefix), scheduledSnapshotSuffix)
+	t, err := time.Parse(scheduledSnapshotTime, v)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return t, true
+}
@@ -0,0 +1,115 @@
+package singleprocess
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/waypoint/internal/serverconfig"
+)
+
+func TestServiceScheduledSnapshot(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	s := impl.(*service)
+
+	td, err := ioutil.TempDir("", "waypoint-snapshots")
+	require.NoError(err)
+	defer os.RemoveAll(td)
+	store := &dirSnapshotStore{dir: td}
+
+	// Take a few snapshots an hour apart
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		_, err := s.storeSnapshot(ctx, store, "scheduled", start.Add(time.Duration(i)*time.Hour))
+		require.NoError(err)
+	}
+
+	// A file we didn't write should never be removed
+	require.NoError(ioutil.WriteFile(filepath.Join(td, "other.txt"), nil, 0600))
+
+	// Rotate, keeping two
+	require.NoError(rotateSnapshots(ctx, hclog.L(), store, 2))
+
+	entries, err := ioutil.ReadDir(td)
+	require.NoError(err)
+
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	require.Equal([]string{
+		"other.txt",
+		scheduledSnapshotPrefix + start.Add(2*time.Hour).UTC().Format(scheduledSnapshotTime) + scheduledSnapshotSuffix,
+		scheduledSnapshotPrefix + start.Add(3*time.Hour).UTC().Format(scheduledSnapshotTime) + scheduledSnapshotSuffix,
+	}, names)
+}
+
+func TestServiceScheduledSnapshot_badConfig(t *testing.T) {
+	require := require.New(t)
+
+	_, err := New(WithDB(testDB(t)), WithConfig(&serverconfig.Config{
+		Snapshot: &serverconfig.Snapshot{Interval: "nope", Path: "/tmp"},
+	}))
+	require.Error(err)
+
+	_, err = New(WithDB(testDB(t)), WithConfig(&serverconfig.Config{
+		Snapshot: &serverconfig.Snapshot{Interval: "1h"},
+	}))
+	require.Error(err)
+
+	_, err = New(WithDB(testDB(t)), WithConfig(&serverconfig.Config{
+		Snapshot: &serverconfig.Snapshot{Interval: "1h", S3: &serverconfig.SnapshotS3{}},
+	}))
+	require.Error(err)
+
+	_, err = New(WithDB(testDB(t)), WithConfig(&serverconfig.Config{
+		Snapshot: &serverconfig.Snapshot{Interval: "1h", Path: "/tmp", MaxAge: "nope"},
+	}))
+	require.Error(err)
+}
+
+func TestServiceScheduledSnapshot_maxAge(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	s := impl.(*service)
+
+	td, err := ioutil.TempDir("", "waypoint-snapshots")
+	require.NoError(err)
+	defer os.RemoveAll(td)
+	store := &dirSnapshotStore{dir: td}
+
+	// Take a few snapshots a day apart
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		_, err := s.storeSnapshot(ctx, store, "scheduled", start.Add(time.Duration(i)*24*time.Hour))
+		require.NoError(err)
+	}
+
+	// Expire anything older than a day and a half at the last snapshot
+	require.NoError(expireSnapshots(ctx, hclog.L(), store, 36*time.Hour, start.Add(72*time.Hour)))
+
+	snapshots, err := store.List(ctx)
+	require.NoError(err)
+
+	var names []string
+	for _, snapshot := range snapshots {
+		names = append(names, snapshot.Name)
+	}
+	require.Equal([]string{
+		scheduledSnapshotPrefix + start.Add(48*time.Hour).UTC().Format(scheduledSnapshotTime) + scheduledSnapshotSuffix,
+		scheduledSnapshotPrefix + start.Add(72*time.Hour).UTC().Format(scheduledSnapshotTime) + scheduledSnapshotSuffix,
+	}, names)
+}
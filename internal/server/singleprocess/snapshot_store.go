@@ -0,0 +1,122 @@
+package singleprocess
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/golang/protobuf/ptypes"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// snapshotStore is where the server stores the snapshots it takes itself,
+// such as scheduled snapshots.
+type snapshotStore interface {
+	// Write stores a snapshot named name with the data that write writes.
+	// If write or storing fails, no snapshot named name is stored.
+	Write(ctx context.Context, name string, write func(io.Writer) error) error
+
+	// List returns the stored snapshots that have scheduled snapshot
+	// names, oldest first.
+	List(ctx context.Context) ([]*pb.StoredSnapshot, error)
+
+	// Delete deletes the snapshot named name. If it doesn't exist, this
+	// returns an error for which os.IsNotExist is true.
+	Delete(ctx context.Context, name string) error
+
+	// String describes where snapshots are stored, for logs.
+	String() string
+}
+
+// dirSnapshotStore stores snapshots as files in a directory.
+type dirSnapshotStore struct {
+	dir string
+}
+
+func (d *dirSnapshotStore) Write(ctx context.Context, name string, write func(io.Writer) error) error {
+	if err := os.MkdirAll(d.dir, 0700); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so that a partial snapshot never
+	// has a snapshot name.
+	f, err := ioutil.TempFile(d.dir, scheduledSnapshotPrefix+"*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	bw := bufio.NewWriter(f)
+	if err := write(bw); err != nil {
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), filepath.Join(d.dir, name))
+}
+
+func (d *dirSnapshotStore) List(ctx context.Context) ([]*pb.StoredSnapshot, error) {
+	entries, err := ioutil.ReadDir(d.dir)
+	if os.IsNotExist(err) {
+		// No snapshots have been taken yet.
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*pb.StoredSnapshot
+	for _, e := range entries {
+		if !e.Mode().IsRegular() || !isScheduledSnapshotName(e.Name()) {
+			continue
+		}
+
+		// The name records when the snapshot was taken. If it can't be
+		// parsed, the modification time is close enough.
+		t, ok := scheduledSnapshotTimeFromName(e.Name())
+		if !ok {
+			t = e.ModTime()
+		}
+
+		createTime, err := ptypes.TimestampProto(t)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, &pb.StoredSnapshot{
+			Name:       e.Name(),
+			Size:       e.Size(),
+			CreateTime: createTime,
+		})
+	}
+	sortStoredSnapshots(result)
+
+	return result, nil
+}
+
+func (d *dirSnapshotStore) Delete(ctx context.Context, name string) error {
+	return os.Remove(filepath.Join(d.dir, name))
+}
+
+func (d *dirSnapshotStore) String() string {
+	return d.dir
+}
+
+// sortStoredSnapshots sorts snapshots by name, which is oldest first for
+// scheduled snapshot names.
+func sortStoredSnapshots(snapshots []*pb.StoredSnapshot) {
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Name < snapshots[j].Name
+	})
+}
@@ -0,0 +1,217 @@
+package singleprocess
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/golang/protobuf/ptypes"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	"github.com/hashicorp/waypoint/internal/serverconfig"
+)
+
+// s3SnapshotPartSize is the size of the parts that snapshots are uploaded
+// in. Uploads have at most 10,000 parts, so this allows snapshots of up
+// to about 160GB while buffering a few parts in memory.
+const s3SnapshotPartSize = 16 << 20
+
+// s3SnapshotStore stores snapshots as objects in an S3 bucket, or in any
+// object storage with an S3 compatible API.
+type s3SnapshotStore struct {
+	cfg serverconfig.SnapshotS3
+
+	// client is created on first use, since it may need to look up the
+	// region of the bucket. Use s3Client to get it.
+	mu     sync.Mutex
+	client *s3.S3
+}
+
+func newS3SnapshotStore(cfg *serverconfig.SnapshotS3) (*s3SnapshotStore, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("snapshot s3 bucket must be set")
+	}
+
+	return &s3SnapshotStore{cfg: *cfg}, nil
+}
+
+func (s *s3SnapshotStore) Write(ctx context.Context, name string, write func(io.Writer) error) error {
+	client, err := s.s3Client(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Stream the snapshot into the upload so that it is never held in
+	// memory or on disk in full. If the upload fails, the uploader aborts
+	// it so that no partial object is left behind.
+	pr, pw := io.Pipe()
+	writeErrCh := make(chan error, 1)
+	go func() {
+		bw := bufio.NewWriter(pw)
+		err := write(bw)
+		if err == nil {
+			err = bw.Flush()
+		}
+
+		pw.CloseWithError(err)
+		writeErrCh <- err
+	}()
+
+	uploader := s3manager.NewUploaderWithClient(client, func(u *s3manager.Uploader) {
+		u.PartSize = s3SnapshotPartSize
+	})
+	_, err = uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket: aws.String(s.cfg.Bucket),
+		Key:    aws.String(s.key(name)),
+		Body:   pr,
+	})
+
+	// Unblock the writer if the upload stopped reading early.
+	pr.CloseWithError(err)
+	if writeErr := <-writeErrCh; writeErr != nil {
+		return writeErr
+	}
+
+	return err
+}
+
+func (s *s3SnapshotStore) List(ctx context.Context) ([]*pb.StoredSnapshot, error) {
+	client, err := s.s3Client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*pb.StoredSnapshot
+	var listErr error
+	err = client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.cfg.Bucket),
+		Prefix: aws.String(s.cfg.Prefix),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, obj := range page.Contents {
+			name := strings.TrimPrefix(aws.StringValue(obj.Key), s.cfg.Prefix)
+			if !isScheduledSnapshotName(name) {
+				continue
+			}
+
+			// The name records when the snapshot was taken. If it can't
+			// be parsed, the modification time is close enough.
+			t, ok := scheduledSnapshotTimeFromName(name)
+			if !ok {
+				t = aws.TimeValue(obj.LastModified)
+			}
+
+			createTime, err := ptypes.TimestampProto(t)
+			if err != nil {
+				listErr = err
+				return false
+			}
+
+			result = append(result, &pb.StoredSnapshot{
+				Name:       name,
+				Size:       aws.Int64Value(obj.Size),
+				CreateTime: createTime,
+			})
+		}
+
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if listErr != nil {
+		return nil, listErr
+	}
+	sortStoredSnapshots(result)
+
+	return result, nil
+}
+
+func (s *s3SnapshotStore) Delete(ctx context.Context, name string) error {
+	client, err := s.s3Client(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Deleting an object that doesn't exist succeeds, so check first to
+	// report snapshots that don't exist.
+	key := s.key(name)
+	_, err = client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.cfg.Bucket),
+		Key:    aws.String(key),
+	})
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "NotFound" {
+		return &os.PathError{Op: "delete", Path: key, Err: os.ErrNotExist}
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.cfg.Bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+func (s *s3SnapshotStore) String() string {
+	return "s3://" + s.cfg.Bucket + "/" + s.cfg.Prefix
+}
+
+// key returns the key of the object for the snapshot named name.
+func (s *s3SnapshotStore) key(name string) string {
+	return s.cfg.Prefix + name
+}
+
+// s3Client returns the client for the bucket. The client uses the
+// standard AWS credential chain of the server. If no region is configured
+// and the storage is AWS S3, the region of the bucket is looked up.
+func (s *s3SnapshotStore) s3Client(ctx context.Context) (*s3.S3, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.client != nil {
+		return s.client, nil
+	}
+
+	config := aws.NewConfig().WithS3ForcePathStyle(s.cfg.ForcePathStyle)
+	if s.cfg.Region != "" {
+		config = config.WithRegion(s.cfg.Region)
+	}
+	if s.cfg.Endpoint != "" {
+		config = config.WithEndpoint(s.cfg.Endpoint)
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *config,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if aws.StringValue(sess.Config.Region) == "" {
+		// Other storage usually ignores the region, but requests must
+		// still be signed with one.
+		region := "us-east-1"
+		if s.cfg.Endpoint == "" {
+			region, err = s3manager.GetBucketRegion(ctx, sess, s.cfg.Bucket, region)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"error determining region of bucket %q: %s", s.cfg.Bucket, err)
+			}
+		}
+
+		sess = sess.Copy(aws.NewConfig().WithRegion(region))
+	}
+
+	s.client = s3.New(sess)
+	return s.client, nil
+}
@@ -104,6 +104,62 @@ func (op *appOperation) Put(s *State, update bool, value proto.Message) error {
 	return err
 }
 
+// Delete deletes operation records by ID. IDs that don't exist are ignored.
+// The latest record of each app should be kept since the sequence numbers
+// are initialized from the records on boot and would otherwise be reused.
+func (op *appOperation) Delete(s *State, ids ...string) error {
+	memTxn := s.inmem.Txn(true)
+	defer memTxn.Abort()
+
+	err := s.db.Update(func(dbTxn *bolt.Tx) error {
+		b := dbTxn.Bucket(op.Bucket)
+		for _, id := range ids {
+			if err := b.Delete([]byte(id)); err != nil {
+				return err
+			}
+
+			err := memTxn.Delete(op.memTableName(), &operationIndexRecord{Id: id})
+			if err != nil && err != memdb.ErrNotFound {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err == nil {
+		memTxn.Commit()
+	}
+
+	return err
+}
+
+// Size returns the bytes stored by the records of the projects in the
+// set, which must have lowercased names.
+func (op *appOperation) Size(s *State, projects map[string]bool) (uint64, error) {
+	memTxn := s.inmem.Txn(false)
+	defer memTxn.Abort()
+
+	iter, err := memTxn.Get(op.memTableName(), opIdIndexName+"_prefix", "")
+	if err != nil {
+		return 0, err
+	}
+
+	var result uint64
+	err = s.db.View(func(dbTxn *bolt.Tx) error {
+		b := dbTxn.Bucket(op.Bucket)
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			record := raw.(*operationIndexRecord)
+			if projects[strings.ToLower(record.Project)] {
+				result += uint64(len(b.Get([]byte(record.Id))))
+			}
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
 // Get gets an operation record by reference.
 func (op *appOperation) Get(s *State, ref *pb.Ref_Operation) (interface{}, error) {
 	memTxn := s.inmem.Txn(false)
@@ -195,6 +251,18 @@ func (op *appOperation) List(s *State, opts *listOperationsOptions) ([]interface
 		return nil, err
 	}
 
+	// The lower bound iterator has no watch channel, so if we're watching
+	// we watch the whole table. Any change to an operation of this type
+	// triggers the watch, even if it isn't in the result.
+	if opts.WatchSet != nil {
+		watchIter, err := memTxn.Get(op.memTableName(), opIdIndexName+"_prefix", "")
+		if err != nil {
+			return nil, err
+		}
+
+		opts.WatchSet.Add(watchIter.WatchCh())
+	}
+
 	var result []interface{}
 	s.db.View(func(tx *bolt.Tx) error {
 		for {
@@ -237,6 +305,13 @@ func (op *appOperation) List(s *State, opts *listOperationsOptions) ([]interface
 				}
 			}
 
+			if len(opts.Labels) > 0 {
+				labels, _ := op.valueField(value, "Labels").(map[string]string)
+				if !labelsMatch(opts.Labels, labels) {
+					continue
+				}
+			}
+
 			result = append(result, value)
 
 			// If we have a limit, check that now
@@ -677,6 +752,8 @@ type listOperationsOptions struct {
 	Status        []*pb.StatusFilter
 	Order         *pb.OperationOrder
 	PhysicalState pb.Operation_PhysicalState
+	Labels        map[string]string
+	WatchSet      memdb.WatchSet
 }
 
 func buildListOperationsOptions(ref *pb.Ref_Application, opts ...ListOperationOption) *listOperationsOptions {
@@ -720,6 +797,22 @@ func ListWithWorkspace(f *pb.Ref_Workspace) ListOperationOption {
 	}
 }
 
+// ListWithLabels only lists operations that have all the given labels
+// set to the given values.
+func ListWithLabels(f map[string]string) ListOperationOption {
+	return func(opts *listOperationsOptions) {
+		opts.Labels = f
+	}
+}
+
+// ListWithWatchSet sets a WatchSet that is triggered when any operation
+// of the listed type changes.
+func ListWithWatchSet(ws memdb.WatchSet) ListOperationOption {
+	return func(opts *listOperationsOptions) {
+		opts.WatchSet = ws
+	}
+}
+
 // statusFilterMatch is a helper that compares a pb.Status to a set of
 // StatusFilters. This returns true if the filters match.
 func statusFilterMatch(
@@ -758,3 +851,15 @@ func statusFilterMatchSingle(
 		return false
 	}
 }
+
+// labelsMatch returns true if labels has every key in filter set to the
+// same value.
+func labelsMatch(filter, labels map[string]string) bool {
+	for k, v := range filter {
+		if actual, ok := labels[k]; !ok || actual != v {
+			return false
+		}
+	}
+
+	return true
+}
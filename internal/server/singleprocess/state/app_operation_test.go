@@ -10,6 +10,7 @@ import (
 	"github.com/golang/protobuf/ptypes"
 	"github.com/stretchr/testify/require"
 
+	"github.com/hashicorp/go-memdb"
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
 	serverptypes "github.com/hashicorp/waypoint/internal/server/ptypes"
 )
@@ -364,6 +365,49 @@ func TestAppOperation(t *testing.T) {
 		require.Equal("C", ids[2])
 	})
 
+	t.Run("list with labels", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+
+		{
+			require.NoError(op.Put(s, false, serverptypes.TestValidBuild(t, &pb.Build{
+				Id:     "A",
+				Labels: map[string]string{"env": "prod", "team": "a"},
+			})))
+		}
+		{
+			require.NoError(op.Put(s, false, serverptypes.TestValidBuild(t, &pb.Build{
+				Id:     "B",
+				Labels: map[string]string{"env": "dev", "team": "a"},
+			})))
+		}
+		{
+			require.NoError(op.Put(s, false, serverptypes.TestValidBuild(t, &pb.Build{
+				Id: "C",
+			})))
+		}
+
+		// List with a single label
+		build := serverptypes.TestValidBuild(t, nil)
+		results, err := op.List(s, &listOperationsOptions{
+			Application: build.Application,
+			Labels:      map[string]string{"team": "a"},
+		})
+		require.NoError(err)
+		require.Len(results, 2)
+
+		// List with all labels required to match
+		results, err = op.List(s, &listOperationsOptions{
+			Application: build.Application,
+			Labels:      map[string]string{"team": "a", "env": "prod"},
+		})
+		require.NoError(err)
+		require.Len(results, 1)
+		require.Equal("A", results[0].(*pb.Build).Id)
+	})
+
 	t.Run("list with physical state filter on unsupported struct", func(t *testing.T) {
 		require := require.New(t)
 
@@ -459,6 +503,35 @@ func TestAppOperation_deploy(t *testing.T) {
 	})
 }
 
+func TestAppOperationList_watch(t *testing.T) {
+	require := require.New(t)
+	op := buildOp
+
+	s := TestState(t)
+	defer s.Close()
+
+	build := serverptypes.TestValidBuild(t, &pb.Build{Id: "A"})
+	require.NoError(op.Put(s, false, build))
+
+	// List with a watch
+	ws := memdb.NewWatchSet()
+	results, err := op.List(s, &listOperationsOptions{
+		Application: build.Application,
+		WatchSet:    ws,
+	})
+	require.NoError(err)
+	require.Len(results, 1)
+
+	// Should not be triggered
+	require.True(ws.Watch(time.After(10 * time.Millisecond)))
+
+	// Create another build
+	require.NoError(op.Put(s, false, serverptypes.TestValidBuild(t, &pb.Build{Id: "B"})))
+
+	// Should be triggered
+	require.False(ws.Watch(time.After(100 * time.Millisecond)))
+}
+
 func TestAppOperation_workspaceResource(t *testing.T) {
 	op := &appOperation{
 		Struct: (*pb.Build)(nil),
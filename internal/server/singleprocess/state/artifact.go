@@ -18,6 +18,11 @@ func (s *State) ArtifactPut(update bool, b *pb.PushedArtifact) error {
 	return artifactOp.Put(s, update, b)
 }
 
+// ArtifactDelete deletes artifact records by ID. IDs that don't exist are ignored.
+func (s *State) ArtifactDelete(ids ...string) error {
+	return artifactOp.Delete(s, ids...)
+}
+
 // ArtifactGet gets a artifact by ref.
 func (s *State) ArtifactGet(ref *pb.Ref_Operation) (*pb.PushedArtifact, error) {
 	result, err := artifactOp.Get(s, ref)
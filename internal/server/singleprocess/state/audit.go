@@ -0,0 +1,113 @@
+package state
+
+import (
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/golang/protobuf/proto"
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+var auditEventBucket = []byte("audit_events")
+
+func init() {
+	dbBuckets = append(dbBuckets, auditEventBucket)
+}
+
+// AuditEventPut appends an event to the audit log. The event must have a
+// unique ID since existing events can't be replaced.
+func (s *State) AuditEventPut(e *pb.AuditEvent) error {
+	if e.Id == "" {
+		return status.Errorf(codes.InvalidArgument, "audit event ID is required")
+	}
+
+	return s.db.Update(func(dbTxn *bolt.Tx) error {
+		b := dbTxn.Bucket(auditEventBucket)
+		if b.Get([]byte(e.Id)) != nil {
+			return status.Errorf(codes.AlreadyExists,
+				"audit event %q already exists", e.Id)
+		}
+
+		return dbPut(b, []byte(e.Id), e)
+	})
+}
+
+// AuditEventList returns the audit events, newest first. If operation is
+// set, only events for that operation are returned. If limit is greater
+// than zero, at most that many events are returned.
+func (s *State) AuditEventList(operation string, limit int) ([]*pb.AuditEvent, error) {
+	var result []*pb.AuditEvent
+	err := s.db.View(func(dbTxn *bolt.Tx) error {
+		// IDs sort in the order they were recorded so we walk backwards
+		// to get the newest events first.
+		c := dbTxn.Bucket(auditEventBucket).Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			var e pb.AuditEvent
+			if err := proto.Unmarshal(v, &e); err != nil {
+				return err
+			}
+			if operation != "" && e.Operation != operation {
+				continue
+			}
+
+			result = append(result, &e)
+			if limit > 0 && len(result) >= limit {
+				break
+			}
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// keepAuditEvents replaces the audit log in the bolt database at path,
+// which is being restored, with the audit log from db. A restore would
+// otherwise replace the audit log with the older one in the snapshot,
+// losing the record of everything since, including the restore itself.
+func keepAuditEvents(log hclog.Logger, db *bolt.DB, path string) error {
+	restoreDb, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 2 * time.Second})
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	err = db.View(func(dbTxn *bolt.Tx) error {
+		src := dbTxn.Bucket(auditEventBucket)
+
+		return restoreDb.Update(func(restoreTxn *bolt.Tx) error {
+			err := restoreTxn.DeleteBucket(auditEventBucket)
+			if err != nil && err != bolt.ErrBucketNotFound {
+				return err
+			}
+
+			dst, err := restoreTxn.CreateBucket(auditEventBucket)
+			if err != nil {
+				return err
+			}
+
+			// The bucket won't exist if the database was created by a
+			// version before audit logging.
+			if src == nil {
+				return nil
+			}
+
+			return src.ForEach(func(k, v []byte) error {
+				count++
+				return dst.Put(k, v)
+			})
+		})
+	})
+	if err != nil {
+		restoreDb.Close()
+		return err
+	}
+
+	log.Info("kept existing audit events", "count", count)
+	return restoreDb.Close()
+}
@@ -0,0 +1,84 @@
+package state
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func TestAuditEvent(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	// Empty to start
+	events, err := s.AuditEventList("", 0)
+	require.NoError(err)
+	require.Empty(events)
+
+	require.NoError(s.AuditEventPut(&pb.AuditEvent{Id: "1", Operation: "deploy"}))
+	require.NoError(s.AuditEventPut(&pb.AuditEvent{Id: "2", Operation: "destroy"}))
+	require.NoError(s.AuditEventPut(&pb.AuditEvent{Id: "3", Operation: "deploy"}))
+
+	// Newest first
+	events, err = s.AuditEventList("", 0)
+	require.NoError(err)
+	require.Len(events, 3)
+	require.Equal("3", events[0].Id)
+	require.Equal("1", events[2].Id)
+
+	// Filtered by operation
+	events, err = s.AuditEventList("deploy", 0)
+	require.NoError(err)
+	require.Len(events, 2)
+	require.Equal("3", events[0].Id)
+	require.Equal("1", events[1].Id)
+
+	// Limited
+	events, err = s.AuditEventList("", 1)
+	require.NoError(err)
+	require.Len(events, 1)
+	require.Equal("3", events[0].Id)
+
+	// Events can't be replaced
+	err = s.AuditEventPut(&pb.AuditEvent{Id: "1", Operation: "release"})
+	require.Error(err)
+	require.Equal(codes.AlreadyExists, status.Code(err))
+
+	// IDs are required
+	err = s.AuditEventPut(&pb.AuditEvent{Operation: "release"})
+	require.Error(err)
+	require.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func TestAuditEvent_restore(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	// Snapshot with an event
+	require.NoError(s.AuditEventPut(&pb.AuditEvent{Id: "1", Operation: "deploy"}))
+	var buf bytes.Buffer
+	require.NoError(s.CreateSnapshot(&buf))
+
+	// Record another event after the snapshot
+	require.NoError(s.AuditEventPut(&pb.AuditEvent{Id: "2", Operation: "restore_snapshot"}))
+
+	// Restore
+	require.NoError(s.StageRestoreSnapshot(bytes.NewReader(buf.Bytes())))
+	s, err := TestStateRestart(t, s)
+	require.NoError(err)
+
+	// We should still have both events
+	events, err := s.AuditEventList("", 0)
+	require.NoError(err)
+	require.Len(events, 2)
+	require.Equal("2", events[0].Id)
+}
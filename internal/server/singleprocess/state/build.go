@@ -18,6 +18,11 @@ func (s *State) BuildPut(update bool, b *pb.Build) error {
 	return buildOp.Put(s, update, b)
 }
 
+// BuildDelete deletes build records by ID. IDs that don't exist are ignored.
+func (s *State) BuildDelete(ids ...string) error {
+	return buildOp.Delete(s, ids...)
+}
+
 // BuildGet gets a build by ref.
 func (s *State) BuildGet(ref *pb.Ref_Operation) (*pb.Build, error) {
 	result, err := buildOp.Get(s, ref)
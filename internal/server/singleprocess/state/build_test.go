@@ -8,6 +8,8 @@ import (
 
 	"github.com/golang/protobuf/ptypes"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
 	serverptypes "github.com/hashicorp/waypoint/internal/server/ptypes"
@@ -130,4 +132,34 @@ func TestBuild(t *testing.T) {
 		require.Error(err)
 		require.Nil(b)
 	})
+
+	t.Run("delete", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+
+		require.NoError(s.BuildPut(false, serverptypes.TestValidBuild(t, &pb.Build{
+			Id: "A",
+		})))
+		require.NoError(s.BuildPut(false, serverptypes.TestValidBuild(t, &pb.Build{
+			Id: "B",
+		})))
+
+		// Deleting IDs that don't exist is ignored
+		require.NoError(s.BuildDelete("A", "C"))
+
+		_, err := s.BuildGet(appOpById("A"))
+		require.Error(err)
+		require.Equal(codes.NotFound, status.Code(err))
+
+		// The index is updated too
+		list, err := s.BuildList(&pb.Ref_Application{
+			Application: "a_test",
+			Project:     "p_test",
+		})
+		require.NoError(err)
+		require.Len(list, 1)
+		require.Equal("B", list[0].Id)
+	})
 }
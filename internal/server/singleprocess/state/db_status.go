@@ -0,0 +1,43 @@
+package state
+
+import (
+	"path/filepath"
+
+	"github.com/boltdb/bolt"
+)
+
+// DBStatus is the status of the on-disk database.
+type DBStatus struct {
+	// Path is the path of the database file.
+	Path string
+
+	// SizeBytes is the size of the database.
+	SizeBytes int64
+
+	// DiskTotalBytes and DiskFreeBytes are the size of the filesystem the
+	// database is on and the space on it that the server can use. These
+	// are zero if they can't be read on this platform.
+	DiskTotalBytes uint64
+	DiskFreeBytes  uint64
+}
+
+// DBStatus checks that the database can be read and returns its status.
+func (s *State) DBStatus() (*DBStatus, error) {
+	result := &DBStatus{Path: s.db.Path()}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		result.SizeBytes = tx.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	total, free, err := diskUsage(filepath.Dir(result.Path))
+	if err != nil {
+		return nil, err
+	}
+	result.DiskTotalBytes = total
+	result.DiskFreeBytes = free
+
+	return result, nil
+}
@@ -0,0 +1,25 @@
+package state
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDBStatus(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	status, err := s.DBStatus()
+	require.NoError(err)
+	require.NotEmpty(status.Path)
+	require.True(status.SizeBytes > 0)
+
+	if runtime.GOOS != "windows" {
+		require.True(status.DiskTotalBytes > 0)
+		require.True(status.DiskFreeBytes <= status.DiskTotalBytes)
+	}
+}
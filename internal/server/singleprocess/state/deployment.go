@@ -18,6 +18,11 @@ func (s *State) DeploymentPut(update bool, b *pb.Deployment) error {
 	return deploymentOp.Put(s, update, b)
 }
 
+// DeploymentDelete deletes deployment records by ID. IDs that don't exist are ignored.
+func (s *State) DeploymentDelete(ids ...string) error {
+	return deploymentOp.Delete(s, ids...)
+}
+
 // DeploymentGet gets a deployment by ref.
 func (s *State) DeploymentGet(ref *pb.Ref_Operation) (*pb.Deployment, error) {
 	result, err := deploymentOp.Get(s, ref)
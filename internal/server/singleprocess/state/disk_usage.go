@@ -0,0 +1,18 @@
+// +build !windows
+
+package state
+
+import (
+	"syscall"
+)
+
+// diskUsage returns the size of the filesystem that path is on and the
+// space on it that is available to the server.
+func diskUsage(path string) (uint64, uint64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, 0, err
+	}
+
+	return uint64(st.Blocks) * uint64(st.Bsize), uint64(st.Bavail) * uint64(st.Bsize), nil
+}
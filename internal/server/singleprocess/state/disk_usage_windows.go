@@ -0,0 +1,8 @@
+// +build windows
+
+package state
+
+// diskUsage returns zero since disk usage isn't read on Windows.
+func diskUsage(path string) (uint64, uint64, error) {
+	return 0, 0, nil
+}
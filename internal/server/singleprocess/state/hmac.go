@@ -2,17 +2,26 @@ package state
 
 import (
 	"crypto/rand"
+	"fmt"
 	"io"
 	"sync/atomic"
+	"time"
 
 	"github.com/boltdb/bolt"
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/hashicorp/go-memdb"
 
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
 )
 
-var hmacKeyBucket = []byte("hmac_keys")
+var (
+	hmacKeyBucket = []byte("hmac_keys")
+
+	// hmacKeyCurrentKey is the key in the server config bucket of the ID
+	// of the HMAC key to sign new tokens with.
+	hmacKeyCurrentKey = []byte("hmac_key_current")
+)
 
 func init() {
 	dbBuckets = append(dbBuckets, hmacKeyBucket)
@@ -73,6 +82,7 @@ func (s *State) hmacKeyCreate(
 	var key pb.HMACKey
 	key.Id = id
 	key.Key = raw
+	key.CreatedAt = ptypes.TimestampNow()
 
 	// Persist our data
 	if err := dbPut(b, []byte(id), &key); err != nil {
@@ -83,6 +93,88 @@ func (s *State) hmacKeyCreate(
 	return &key, s.hmacKeyIndexSet(memTxn, &key)
 }
 
+// HMACKeyCurrent returns the ID of the HMAC key to sign new tokens with.
+// This is empty if the keys have never been rotated.
+func (s *State) HMACKeyCurrent() (string, error) {
+	var result string
+	err := s.db.View(func(dbTxn *bolt.Tx) error {
+		result = string(dbTxn.Bucket(serverConfigBucket).Get(hmacKeyCurrentKey))
+		return nil
+	})
+
+	return result, err
+}
+
+// HMACKeyRotate creates a new HMAC key with the given size and makes it
+// the current key. If retireAt is not zero, every other key that isn't
+// already retiring is only valid until then.
+func (s *State) HMACKeyRotate(size int, retireAt time.Time) (*pb.HMACKey, error) {
+	memTxn := s.inmem.Txn(true)
+	defer memTxn.Abort()
+
+	var result *pb.HMACKey
+	err := s.db.Update(func(dbTxn *bolt.Tx) error {
+		b := dbTxn.Bucket(hmacKeyBucket)
+
+		// Retire the existing keys.
+		var keys []*pb.HMACKey
+		err := b.ForEach(func(k, v []byte) error {
+			var key pb.HMACKey
+			if err := proto.Unmarshal(v, &key); err != nil {
+				return err
+			}
+
+			keys = append(keys, &key)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if !retireAt.IsZero() {
+			validUntil, err := ptypes.TimestampProto(retireAt)
+			if err != nil {
+				return err
+			}
+
+			for _, key := range keys {
+				if key.ValidUntil != nil {
+					continue
+				}
+
+				key.ValidUntil = validUntil
+				if err := dbPut(b, []byte(key.Id), key); err != nil {
+					return err
+				}
+				if err := s.hmacKeyIndexSet(memTxn, key); err != nil {
+					return err
+				}
+			}
+		}
+
+		// Use the next unused ID for the new key.
+		id := ""
+		for n := len(keys) + 1; ; n++ {
+			id = fmt.Sprintf("k%d", n)
+			if b.Get([]byte(id)) == nil {
+				break
+			}
+		}
+
+		result, err = s.hmacKeyCreate(dbTxn, memTxn, id, size)
+		if err != nil {
+			return err
+		}
+
+		return dbTxn.Bucket(serverConfigBucket).Put(hmacKeyCurrentKey, []byte(id))
+	})
+	if err == nil {
+		memTxn.Commit()
+	}
+
+	return result, err
+}
+
 // HMACKeyGet gets an HMAC key by ID. This will return a nil value if it
 // doesn't exist.
 func (s *State) HMACKeyGet(id string) (*pb.HMACKey, error) {
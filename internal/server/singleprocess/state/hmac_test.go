@@ -2,6 +2,7 @@ package state
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -55,4 +56,49 @@ func TestHMAC(t *testing.T) {
 			require.Equal(key2.Key, key.Key)
 		}
 	})
+	t.Run("Rotate", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+
+		// No current key until we rotate
+		current, err := s.HMACKeyCurrent()
+		require.NoError(err)
+		require.Empty(current)
+
+		_, err = s.HMACKeyCreateIfNotExist("k1", 32)
+		require.NoError(err)
+
+		// Rotate
+		retireAt := time.Now().Add(time.Hour)
+		key, err := s.HMACKeyRotate(32, retireAt)
+		require.NoError(err)
+		require.Equal("k2", key.Id)
+		require.NotNil(key.CreatedAt)
+		require.Nil(key.ValidUntil)
+
+		current, err = s.HMACKeyCurrent()
+		require.NoError(err)
+		require.Equal("k2", current)
+
+		// The old key is retiring
+		old, err := s.HMACKeyGet("k1")
+		require.NoError(err)
+		require.NotNil(old.ValidUntil)
+		require.Equal(retireAt.Unix(), old.ValidUntil.Seconds)
+
+		// Rotating again doesn't change when the old key retires
+		key, err = s.HMACKeyRotate(32, time.Now().Add(2*time.Hour))
+		require.NoError(err)
+		require.Equal("k3", key.Id)
+
+		old, err = s.HMACKeyGet("k1")
+		require.NoError(err)
+		require.Equal(retireAt.Unix(), old.ValidUntil.Seconds)
+
+		old, err = s.HMACKeyGet("k2")
+		require.NoError(err)
+		require.NotNil(old.ValidUntil)
+	})
 }
@@ -158,6 +158,23 @@ func (s *State) InstanceById(id string) (*Instance, error) {
 	return raw.(*Instance), nil
 }
 
+// InstanceCount returns the number of instances that are connected.
+func (s *State) InstanceCount() (int, error) {
+	txn := s.inmem.Txn(false)
+	defer txn.Abort()
+	iter, err := txn.Get(instanceTableName, instanceIdIndexName+"_prefix", "")
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		count++
+	}
+
+	return count, nil
+}
+
 func (s *State) InstancesByDeployment(id string, ws memdb.WatchSet) ([]*Instance, error) {
 	txn := s.inmem.Txn(false)
 	defer txn.Abort()
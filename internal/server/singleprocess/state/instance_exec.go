@@ -116,6 +116,38 @@ func (s *State) InstanceExecCreateByDeployment(did string, exec *InstanceExec) e
 	return nil
 }
 
+// InstanceExecCreateByInstance registers an exec session on the instance
+// with the given ID. If did is set, the instance must belong to that
+// deployment.
+func (s *State) InstanceExecCreateByInstance(did, id string, exec *InstanceExec) error {
+	txn := s.inmem.Txn(true)
+	defer txn.Abort()
+
+	raw, err := txn.First(instanceTableName, instanceIdIndexName, id)
+	if err != nil {
+		return status.Errorf(codes.Aborted, err.Error())
+	}
+	if raw == nil {
+		return status.Errorf(codes.NotFound,
+			"Instance %q not found. It may have exited.", id)
+	}
+
+	rec := raw.(*Instance)
+	if did != "" && rec.DeploymentId != did {
+		return status.Errorf(codes.NotFound,
+			"Instance %q is not an instance of deployment %q.", id, did)
+	}
+
+	exec.InstanceId = rec.Id
+	exec.Id = atomic.AddInt64(&instanceExecId, 1)
+	if err := txn.Insert(instanceExecTableName, exec); err != nil {
+		return status.Errorf(codes.Aborted, err.Error())
+	}
+	txn.Commit()
+
+	return nil
+}
+
 func (s *State) InstanceExecDelete(id int64) error {
 	txn := s.inmem.Txn(true)
 	defer txn.Abort()
@@ -83,3 +83,53 @@ func TestInstanceExecCreateByDeploymentId_valid(t *testing.T) {
 		require.NoError(s.InstanceExecDelete(rec.Id))
 	}
 }
+
+func TestInstanceExecCreateByInstance(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	// Create two instances of the same deployment
+	instance := testInstance(t, nil)
+	require.NoError(s.InstanceCreate(instance))
+	other := testInstance(t, &Instance{Id: "other"})
+	require.NoError(s.InstanceCreate(other))
+
+	// Load the first instance so that picking by deployment would choose
+	// the other one.
+	require.NoError(s.InstanceExecCreateByDeployment(instance.DeploymentId, &InstanceExec{}))
+
+	{
+		// We get the instance we asked for
+		rec := &InstanceExec{}
+		require.NoError(s.InstanceExecCreateByInstance(instance.DeploymentId, instance.Id, rec))
+		require.NotEmpty(rec.Id)
+		require.Equal(instance.Id, rec.InstanceId)
+
+		list, err := s.InstanceExecListByInstanceId(instance.Id, nil)
+		require.NoError(err)
+		require.Len(list, 2)
+	}
+
+	{
+		// The deployment is optional
+		rec := &InstanceExec{}
+		require.NoError(s.InstanceExecCreateByInstance("", other.Id, rec))
+		require.Equal(other.Id, rec.InstanceId)
+	}
+
+	{
+		// Unknown instance
+		err := s.InstanceExecCreateByInstance("", "nope", &InstanceExec{})
+		require.Error(err)
+		require.Equal(codes.NotFound, status.Code(err))
+	}
+
+	{
+		// Instance of a different deployment
+		err := s.InstanceExecCreateByInstance("nope", instance.Id, &InstanceExec{})
+		require.Error(err)
+		require.Equal(codes.NotFound, status.Code(err))
+	}
+}
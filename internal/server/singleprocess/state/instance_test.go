@@ -28,9 +28,18 @@ func TestInstance_crud(t *testing.T) {
 	require.NoError(err)
 	require.Equal(rec, found)
 
+	// It should be counted
+	count, err := s.InstanceCount()
+	require.NoError(err)
+	require.Equal(1, count)
+
 	// Delete that instance
 	require.NoError(s.InstanceDelete(rec.Id))
 
+	count, err = s.InstanceCount()
+	require.NoError(err)
+	require.Equal(0, count)
+
 	// Delete again should be fine
 	require.NoError(s.InstanceDelete(rec.Id))
 }
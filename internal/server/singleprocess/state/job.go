@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/boltdb/bolt"
@@ -24,6 +25,10 @@ var (
 
 	jobWaitingTimeout   = 2 * time.Minute
 	jobHeartbeatTimeout = 2 * time.Minute
+
+	// jobMaxAttempts is the number of times a job that targets any runner
+	// is started before it fails when the lease of its runner expires.
+	jobMaxAttempts uint32 = 3
 )
 
 const (
@@ -133,6 +138,12 @@ type jobIndex struct {
 	// on the current state. When the state changes, the timer should be cancelled.
 	StateTimer *time.Timer
 
+	// LeaseExpireTime is when the lease of the runner on a waiting or
+	// running job expires, which is when StateTimer fires. This is only
+	// kept in memory since every heartbeat renews it, and it is rebuilt
+	// along with the timer when the server starts.
+	LeaseExpireTime time.Time
+
 	// OutputBuffer stores the terminal output
 	OutputBuffer *logbuffer.Buffer
 }
@@ -170,6 +181,12 @@ func (s *State) JobCreate(jobpb *pb.Job) error {
 
 // JobList returns the list of jobs.
 func (s *State) JobList() ([]*pb.Job, error) {
+	return s.JobListWatch(nil)
+}
+
+// JobListWatch returns the list of jobs. If a non-nil WatchSet is given,
+// it is triggered when any job is created or changes state.
+func (s *State) JobListWatch(ws memdb.WatchSet) ([]*pb.Job, error) {
 	memTxn := s.inmem.Txn(false)
 	defer memTxn.Abort()
 
@@ -177,6 +194,7 @@ func (s *State) JobList() ([]*pb.Job, error) {
 	if err != nil {
 		return nil, err
 	}
+	ws.Add(iter.WatchCh())
 
 	var result []*pb.Job
 	for {
@@ -191,13 +209,89 @@ func (s *State) JobList() ([]*pb.Job, error) {
 			job, err = s.jobById(dbTxn, idx.Id)
 			return err
 		})
+		if err != nil {
+			return nil, err
+		}
 
+		idx.setLease(job)
 		result = append(result, job)
 	}
 
 	return result, nil
 }
 
+// JobCountByState returns the number of jobs in each state. This only
+// reads the in-memory index so it is cheap enough to call often.
+func (s *State) JobCountByState() (map[pb.Job_State]int, error) {
+	memTxn := s.inmem.Txn(false)
+	defer memTxn.Abort()
+
+	iter, err := memTxn.Get(jobTableName, jobIdIndexName+"_prefix", "")
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[pb.Job_State]int{}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		result[raw.(*jobIndex).State]++
+	}
+
+	return result, nil
+}
+
+// JobCountActive returns the number of jobs of the projects in the set
+// that are queued or running. The set must have lowercased names.
+func (s *State) JobCountActive(projects map[string]bool) (int, error) {
+	memTxn := s.inmem.Txn(false)
+	defer memTxn.Abort()
+
+	iter, err := memTxn.Get(jobTableName, jobIdIndexName+"_prefix", "")
+	if err != nil {
+		return 0, err
+	}
+
+	result := 0
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		idx := raw.(*jobIndex)
+		switch idx.State {
+		case pb.Job_QUEUED, pb.Job_WAITING, pb.Job_RUNNING:
+			if projects[strings.ToLower(idx.Application.GetProject())] {
+				result++
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// JobTimeoutsSet sets how long a runner has to ack a job assigned to it
+// and how long a running job waits for a heartbeat. Zero uses the
+// default. The timeouts apply the next time a job's timer is started or
+// reset, so jobs that are waiting for an ack keep their previous timeout.
+func (s *State) JobTimeoutsSet(ack, heartbeat time.Duration) {
+	s.jobTimeoutLock.Lock()
+	defer s.jobTimeoutLock.Unlock()
+	s.jobAckTimeout = ack
+	s.jobHeartbeatTimeout = heartbeat
+}
+
+// JobTimeoutsGet returns the timeouts set with JobTimeoutsSet, or the
+// defaults.
+func (s *State) JobTimeoutsGet() (ack, heartbeat time.Duration) {
+	s.jobTimeoutLock.RLock()
+	defer s.jobTimeoutLock.RUnlock()
+
+	ack, heartbeat = s.jobAckTimeout, s.jobHeartbeatTimeout
+	if ack == 0 {
+		ack = jobWaitingTimeout
+	}
+	if heartbeat == 0 {
+		heartbeat = jobHeartbeatTimeout
+	}
+
+	return ack, heartbeat
+}
+
 // JobById looks up a job by ID. The returned Job will be a deep copy
 // of the job so it is safe to read/write. If the job can't be found,
 // a nil result with no error is returned.
@@ -350,11 +444,16 @@ RETRY_ASSIGN:
 			return nil, err
 		}
 
-		// Update our state and update our on-disk job
+		// Update our state and update our on-disk job. The runner gets a
+		// lease on the job that it has to ack before it expires.
+		ackTimeout, _ := s.JobTimeoutsGet()
+		now := time.Now()
 		job.State = pb.Job_WAITING
+		job.LeaseExpireTime = now.Add(ackTimeout)
 		result, err := s.jobReadAndUpdate(job.Id, func(jobpb *pb.Job) error {
 			jobpb.State = job.State
-			jobpb.AssignTime, err = ptypes.TimestampProto(time.Now())
+			jobpb.AssignedRunner = &pb.Ref_RunnerId{Id: r.Id}
+			jobpb.AssignTime, err = ptypes.TimestampProto(now)
 			if err != nil {
 				// This should never happen since encoding a time now should be safe
 				panic("time encoding failed: " + err.Error())
@@ -367,8 +466,8 @@ RETRY_ASSIGN:
 		}
 
 		// Create our timer to requeue this if it isn't acked
-		job.StateTimer = time.AfterFunc(jobWaitingTimeout, func() {
-			s.log.Info("job ack timer expired", "job", job.Id, "timeout", jobWaitingTimeout)
+		job.StateTimer = time.AfterFunc(ackTimeout, func() {
+			s.log.Info("job ack timer expired", "job", job.Id, "timeout", ackTimeout)
 			s.JobAck(job.Id, false)
 		})
 
@@ -415,24 +514,35 @@ func (s *State) JobAck(id string, ack bool) (*Job, error) {
 			job.State.String())
 	}
 
+	_, heartbeatTimeout := s.JobTimeoutsGet()
 	result, err := s.jobReadAndUpdate(job.Id, func(jobpb *pb.Job) error {
 		if ack {
-			// Set to accepted
+			// Set to accepted. The lease of the runner is now renewed by
+			// heartbeats.
+			now := time.Now()
 			job.State = pb.Job_RUNNING
+			job.LeaseExpireTime = now.Add(heartbeatTimeout)
 			jobpb.State = job.State
-			jobpb.AckTime, err = ptypes.TimestampProto(time.Now())
+			jobpb.Attempts++
+			jobpb.AckTime, err = ptypes.TimestampProto(now)
 			if err != nil {
 				// This should never happen since encoding a time now should be safe
 				panic("time encoding failed: " + err.Error())
 			}
 
 			// We also initialize the output buffer here because we can
-			// expect output to begin streaming in.
-			job.OutputBuffer = logbuffer.New()
+			// expect output to begin streaming in. If this is another
+			// attempt, the output continues in the same buffer so that
+			// readers see the output of every attempt.
+			if job.OutputBuffer == nil {
+				job.OutputBuffer = logbuffer.New()
+			}
 		} else {
-			// Set to queued
+			// Set to queued and release the lease
 			job.State = pb.Job_QUEUED
+			job.LeaseExpireTime = time.Time{}
 			jobpb.State = job.State
+			jobpb.AssignedRunner = nil
 			jobpb.AssignTime = nil
 		}
 
@@ -448,18 +558,13 @@ func (s *State) JobAck(id string, ack bool) (*Job, error) {
 		job.StateTimer = nil
 	}
 
-	// Create a new timer that we'll use for our heartbeat. After this
-	// timer expires, the job will immediately move to an error state.
-	job.StateTimer = time.AfterFunc(jobHeartbeatTimeout, func() {
-		s.log.Info("canceling job due to heartbeat timeout", "job", job.Id)
-		// Force cancel
-		err := s.JobCancel(job.Id, true)
-		if err != nil {
-			s.log.Error("error canceling job due to heartbeat failure", "error", err, "job", job.Id)
-		}
-	})
-
-	s.log.Debug("heartbeat timer set", "job", job.Id, "timeout", jobHeartbeatTimeout)
+	// Create a new timer that we'll use for our heartbeat. If this timer
+	// expires, the lease of the runner expired. Queued jobs don't have a
+	// lease so they don't have a timer.
+	if ack {
+		job.StateTimer = s.jobLeaseTimer(job.Id, heartbeatTimeout)
+		s.log.Debug("heartbeat timer set", "job", job.Id, "timeout", heartbeatTimeout)
+	}
 
 	// Insert to update
 	if err := txn.Insert(jobTableName, job); err != nil {
@@ -676,12 +781,107 @@ func (s *State) jobHeartbeat(txn *memdb.Txn, id string) error {
 		return nil
 	}
 
-	// Reset the timer
-	job.StateTimer.Reset(jobHeartbeatTimeout)
+	// Reset the timer and renew the lease. Runners heartbeat often, so the
+	// lease is only renewed in memory rather than written to disk.
+	_, heartbeatTimeout := s.JobTimeoutsGet()
+	job.StateTimer.Reset(heartbeatTimeout)
+	job.LeaseExpireTime = time.Now().Add(heartbeatTimeout)
 
 	return nil
 }
 
+// jobLeaseTimer returns a timer that expires the lease of the runner on
+// the running job with the given ID after timeout.
+func (s *State) jobLeaseTimer(id string, timeout time.Duration) *time.Timer {
+	return time.AfterFunc(timeout, func() {
+		if err := s.jobLeaseExpire(id); err != nil {
+			s.log.Error("error expiring job lease", "error", err, "job", id)
+		}
+	})
+}
+
+// jobLeaseExpire handles the lease of the runner on a running job
+// expiring, such as when the runner stopped heartbeating because it died.
+// Jobs that target any runner are queued again so that another runner can
+// run them, up to jobMaxAttempts times. Other jobs are force canceled
+// since no other runner can run them.
+func (s *State) jobLeaseExpire(id string) error {
+	txn := s.inmem.Txn(true)
+	defer txn.Abort()
+
+	// Get the job
+	raw, err := txn.First(jobTableName, jobIdIndexName, id)
+	if err != nil {
+		return err
+	}
+	if raw == nil {
+		return status.Errorf(codes.NotFound, "job not found: %s", id)
+	}
+	job := raw.(*jobIndex)
+
+	// The lease only matters while the job is running.
+	if job.State != pb.Job_RUNNING {
+		return nil
+	}
+
+	var stored *pb.Job
+	err = s.db.View(func(dbTxn *bolt.Tx) error {
+		stored, err = s.jobById(dbTxn, id)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if !job.TargetAny || stored.CancelTime != nil || stored.Attempts >= jobMaxAttempts {
+		s.log.Info("canceling job due to heartbeat timeout",
+			"job", id, "attempts", stored.Attempts)
+		if err := s.jobCancel(txn, job, true); err != nil {
+			return err
+		}
+
+		txn.Commit()
+		return nil
+	}
+
+	s.log.Warn("job lease expired, queueing the job to run again",
+		"job", id,
+		"runner", stored.AssignedRunner.GetId(),
+		"attempts", stored.Attempts)
+
+	// Queue the job again. It keeps its queue time so that it is ahead of
+	// jobs that were queued after it.
+	job.State = pb.Job_QUEUED
+	job.LeaseExpireTime = time.Time{}
+	_, err = s.jobReadAndUpdate(id, func(jobpb *pb.Job) error {
+		jobpb.State = job.State
+		jobpb.AssignedRunner = nil
+		jobpb.AssignTime = nil
+		jobpb.AckTime = nil
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if job.StateTimer != nil {
+		job.StateTimer.Stop()
+		job.StateTimer = nil
+	}
+
+	if err := txn.Insert(jobTableName, job); err != nil {
+		return err
+	}
+
+	// Unblock the jobs waiting on this one, and this job itself.
+	if err := s.jobAssignedSet(txn, job, false); err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
 // JobExpire expires a job. This will cancel the job if it is still queued.
 func (s *State) JobExpire(id string) error {
 	txn := s.inmem.Txn(true)
@@ -711,6 +911,48 @@ func (s *State) JobExpire(id string) error {
 	return nil
 }
 
+// JobDelete deletes the records of completed jobs along with their
+// terminal output. Jobs that haven't completed can't be deleted.
+func (s *State) JobDelete(ids ...string) error {
+	txn := s.inmem.Txn(true)
+	defer txn.Abort()
+
+	err := s.db.Update(func(dbTxn *bolt.Tx) error {
+		for _, id := range ids {
+			raw, err := txn.First(jobTableName, jobIdIndexName, id)
+			if err != nil {
+				return err
+			}
+			if raw == nil {
+				return status.Errorf(codes.NotFound, "job not found: %s", id)
+			}
+			job := raw.(*jobIndex)
+
+			switch job.State {
+			case pb.Job_SUCCESS, pb.Job_ERROR:
+			default:
+				return status.Errorf(codes.FailedPrecondition,
+					"job %s can't be deleted until it completes", id)
+			}
+
+			if err := dbTxn.Bucket(jobBucket).Delete([]byte(id)); err != nil {
+				return err
+			}
+			if err := txn.Delete(jobTableName, job); err != nil {
+				return err
+			}
+			job.End()
+		}
+
+		return nil
+	})
+	if err == nil {
+		txn.Commit()
+	}
+
+	return err
+}
+
 // JobIsAssignable returns whether there is a registered runner that
 // meets the requirements to run this job.
 //
@@ -846,22 +1088,23 @@ func (s *State) jobIndexSet(txn *memdb.Txn, id []byte, jobpb *pb.Job) (*jobIndex
 		*ts.Field = t
 	}
 
+	ackTimeout, heartbeatTimeout := s.JobTimeoutsGet()
+
 	// If this job is assigned. Then we have to start a nacking timer.
 	// We reset the nack timer so it gives runners time to reconnect.
 	if rec.State == pb.Job_WAITING {
 		// Create our timer to requeue this if it isn't acked
-		rec.StateTimer = time.AfterFunc(jobWaitingTimeout, func() {
+		rec.StateTimer = time.AfterFunc(ackTimeout, func() {
 			s.JobAck(rec.Id, false)
 		})
+		rec.LeaseExpireTime = time.Now().Add(ackTimeout)
 	}
 
-	// If this job is running, we need to restart a heartbeat timeout.
+	// If this job is running, we need to restart the lease of its runner.
 	// This should only happen on reinit. This is tested.
 	if rec.State == pb.Job_RUNNING {
-		rec.StateTimer = time.AfterFunc(jobHeartbeatTimeout, func() {
-			// Force cancel
-			s.JobCancel(rec.Id, true)
-		})
+		rec.StateTimer = s.jobLeaseTimer(rec.Id, heartbeatTimeout)
+		rec.LeaseExpireTime = time.Now().Add(heartbeatTimeout)
 	}
 
 	// If we have an expiry, we need to set a timer to expire this job.
@@ -969,8 +1212,30 @@ func (s *State) jobCandidateById(memTxn *memdb.Txn, ws memdb.WatchSet, r *runner
 	return nil, nil
 }
 
-// jobCandidateAny returns the first candidate job that targets any runner.
+// jobCandidateAny returns the candidate job that targets any runner to
+// assign next. To be fair to every project, this is the oldest queued job
+// of the project with the fewest assigned jobs, so one project queueing
+// many jobs doesn't delay the jobs of other projects until its jobs run.
 func (s *State) jobCandidateAny(memTxn *memdb.Txn, ws memdb.WatchSet, r *runnerRecord) (*jobIndex, error) {
+	// active are the numbers of assigned jobs by lowercased project name.
+	// We only count them for the projects that have queued jobs, once per
+	// project.
+	active := map[string]int{}
+	countActive := func(project string) (int, error) {
+		project = strings.ToLower(project)
+		if count, ok := active[project]; ok {
+			return count, nil
+		}
+
+		count, err := s.jobCountAssigned(memTxn, project)
+		if err != nil {
+			return 0, err
+		}
+
+		active[project] = count
+		return count, nil
+	}
+
 	iter, err := memTxn.LowerBound(
 		jobTableName,
 		jobQueueTimeIndexName,
@@ -981,6 +1246,8 @@ func (s *State) jobCandidateAny(memTxn *memdb.Txn, ws memdb.WatchSet, r *runnerR
 		return nil, err
 	}
 
+	var result *jobIndex
+	var resultActive int
 	for {
 		raw := iter.Next()
 		if raw == nil {
@@ -992,6 +1259,16 @@ func (s *State) jobCandidateAny(memTxn *memdb.Txn, ws memdb.WatchSet, r *runnerR
 			continue
 		}
 
+		// Jobs are in queue order, so a later job is only better if its
+		// project has fewer assigned jobs.
+		count, err := countActive(job.Application.GetProject())
+		if err != nil {
+			return nil, err
+		}
+		if result != nil && count >= resultActive {
+			continue
+		}
+
 		// If this job is blocked, it is not a candidate.
 		if blocked, err := s.jobIsBlocked(memTxn, job, ws); err != nil {
 			return nil, err
@@ -999,20 +1276,44 @@ func (s *State) jobCandidateAny(memTxn *memdb.Txn, ws memdb.WatchSet, r *runnerR
 			continue
 		}
 
-		return job, nil
+		result, resultActive = job, count
+
+		// No job can be better than the oldest job of a project without
+		// assigned jobs.
+		if count == 0 {
+			break
+		}
 	}
 
-	return nil, nil
+	return result, nil
 }
 
 // Job returns the Job for an index.
 func (idx *jobIndex) Job(jobpb *pb.Job) *Job {
+	idx.setLease(jobpb)
 	return &Job{
 		Job:          jobpb,
 		OutputBuffer: idx.OutputBuffer,
 	}
 }
 
+// setLease sets the lease expiry time of jobpb, which is only stored in
+// the index. Jobs that aren't assigned to a runner have no lease.
+func (idx *jobIndex) setLease(jobpb *pb.Job) {
+	if jobpb == nil {
+		return
+	}
+
+	jobpb.LeaseExpireTime = nil
+	if idx.LeaseExpireTime.IsZero() ||
+		(idx.State != pb.Job_WAITING && idx.State != pb.Job_RUNNING) {
+		return
+	}
+
+	// This can only fail for times outside of the range of a Timestamp.
+	jobpb.LeaseExpireTime, _ = ptypes.TimestampProto(idx.LeaseExpireTime)
+}
+
 // End notes this job is complete and performs any cleanup on the index.
 func (idx *jobIndex) End() {
 	if idx.StateTimer != nil {
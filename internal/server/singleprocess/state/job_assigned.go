@@ -22,11 +22,16 @@ var blockOps = map[reflect.Type]struct{}{
 
 func init() {
 	schemas = append(schemas, jobAssignedSchema)
+	schemas = append(schemas, jobAssignedProjectSchema)
 }
 
 const (
 	jobAssignedTableName   = "jobs-assigned"
 	jobAssignedIdIndexName = "id"
+
+	jobAssignedProjectTableName        = "jobs-assigned-project"
+	jobAssignedProjectIdIndexName      = "id"
+	jobAssignedProjectProjectIndexName = "project"
 )
 
 func jobAssignedSchema() *memdb.TableSchema {
@@ -66,6 +71,40 @@ type jobAssignedIndex struct {
 	Workspace   string
 }
 
+func jobAssignedProjectSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: jobAssignedProjectTableName,
+		Indexes: map[string]*memdb.IndexSchema{
+			jobAssignedProjectIdIndexName: {
+				Name:         jobAssignedProjectIdIndexName,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "Id",
+				},
+			},
+
+			jobAssignedProjectProjectIndexName: {
+				Name:         jobAssignedProjectProjectIndexName,
+				AllowMissing: true,
+				Unique:       false,
+				Indexer: &memdb.StringFieldIndex{
+					Field:     "Project",
+					Lowercase: true,
+				},
+			},
+		},
+	}
+}
+
+// jobAssignedProjectIndex is a job of any operation type that is assigned
+// to a runner, so that the assigned jobs of a project can be counted
+// without looking at every job.
+type jobAssignedProjectIndex struct {
+	Id      string
+	Project string
+}
+
 // jobIsBlocked will return true if the given job is currently blocked because
 // a job with the same project/app/workspace is executing.
 //
@@ -99,6 +138,21 @@ func (s *State) jobIsBlocked(memTxn *memdb.Txn, idx *jobIndex, ws memdb.WatchSet
 
 // jobAssignedSet records the given job as assigned.
 func (s *State) jobAssignedSet(memTxn *memdb.Txn, idx *jobIndex, assigned bool) error {
+	if assigned {
+		if err := memTxn.Insert(jobAssignedProjectTableName, &jobAssignedProjectIndex{
+			Id:      idx.Id,
+			Project: idx.Application.GetProject(),
+		}); err != nil {
+			return err
+		}
+	} else {
+		if _, err := memTxn.DeleteAll(
+			jobAssignedProjectTableName, jobAssignedProjectIdIndexName, idx.Id,
+		); err != nil {
+			return err
+		}
+	}
+
 	// If this job represents a parallelizable operation type, then do nothing.
 	if _, ok := blockOps[idx.OpType]; !ok {
 		return nil
@@ -116,3 +170,23 @@ func (s *State) jobAssignedSet(memTxn *memdb.Txn, idx *jobIndex, assigned bool)
 
 	return memTxn.Delete(jobAssignedTableName, rec)
 }
+
+// jobCountAssigned returns the number of jobs of the project that are
+// assigned to runners, which are waiting or running.
+func (s *State) jobCountAssigned(memTxn *memdb.Txn, project string) (int, error) {
+	iter, err := memTxn.Get(
+		jobAssignedProjectTableName,
+		jobAssignedProjectProjectIndexName,
+		project,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	result := 0
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		result++
+	}
+
+	return result, nil
+}
@@ -6,6 +6,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/boltdb/bolt"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -426,6 +428,58 @@ func TestJobAssign(t *testing.T) {
 		require.NotNil(job)
 		require.Equal("B", job.Id)
 	})
+
+	t.Run("fair across projects", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+
+		// Project one queues a few jobs before project two
+		for _, id := range []string{"A1", "A2", "A3"} {
+			require.NoError(s.JobCreate(serverptypes.TestJobNew(t, &pb.Job{
+				Id:          id,
+				Application: &pb.Ref_Application{Project: "one", Application: "app"},
+			})))
+		}
+		require.NoError(s.JobCreate(serverptypes.TestJobNew(t, &pb.Job{
+			Id:          "B1",
+			Application: &pb.Ref_Application{Project: "two", Application: "app"},
+		})))
+
+		// The first job of project one is the oldest
+		job, err := s.JobAssignForRunner(context.Background(), &pb.Runner{Id: "R_A"})
+		require.NoError(err)
+		require.Equal("A1", job.Id)
+		require.Equal("R_A", job.AssignedRunner.Id)
+		require.NotNil(job.LeaseExpireTime)
+
+		// Project two has no assigned jobs so its job is next
+		job, err = s.JobAssignForRunner(context.Background(), &pb.Runner{Id: "R_B"})
+		require.NoError(err)
+		require.Equal("B1", job.Id)
+
+		// Then project one again
+		job, err = s.JobAssignForRunner(context.Background(), &pb.Runner{Id: "R_C"})
+		require.NoError(err)
+		require.Equal("A2", job.Id)
+
+		// Completed jobs no longer count, so project one has no assigned
+		// jobs and its job is older than project two's
+		for _, id := range []string{"A1", "A2"} {
+			_, err = s.JobAck(id, true)
+			require.NoError(err)
+			require.NoError(s.JobComplete(id, nil, nil))
+		}
+		require.NoError(s.JobCreate(serverptypes.TestJobNew(t, &pb.Job{
+			Id:          "B2",
+			Application: &pb.Ref_Application{Project: "two", Application: "app"},
+		})))
+
+		job, err = s.JobAssignForRunner(context.Background(), &pb.Runner{Id: "R_D"})
+		require.NoError(err)
+		require.Equal("A3", job.Id)
+	})
 }
 
 func TestJobAck(t *testing.T) {
@@ -523,6 +577,32 @@ func TestJobAck(t *testing.T) {
 		_, err = s.JobAck(job.Id, true)
 		require.Error(err)
 	})
+
+	t.Run("timeout set on the state", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+		s.JobTimeoutsSet(5*time.Millisecond, 0)
+
+		// Create a build
+		require.NoError(s.JobCreate(serverptypes.TestJobNew(t, &pb.Job{
+			Id: "A",
+		})))
+
+		// Assign it, we should get this build
+		job, err := s.JobAssignForRunner(context.Background(), &pb.Runner{Id: "R_A"})
+		require.NoError(err)
+		require.NotNil(job)
+
+		// Sleep too long
+		time.Sleep(100 * time.Millisecond)
+
+		// Verify it is queued
+		job, err = s.JobById(job.Id, nil)
+		require.NoError(err)
+		require.Equal(pb.Job_QUEUED, job.Job.State)
+	})
 }
 
 func TestJobComplete(t *testing.T) {
@@ -597,6 +677,82 @@ func TestJobComplete(t *testing.T) {
 	})
 }
 
+func TestJobDelete(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	// Create two jobs
+	require.NoError(s.JobCreate(serverptypes.TestJobNew(t, &pb.Job{
+		Id: "A",
+	})))
+	require.NoError(s.JobCreate(serverptypes.TestJobNew(t, &pb.Job{
+		Id: "B",
+	})))
+
+	// Run and complete one
+	job, err := s.JobAssignForRunner(context.Background(), &pb.Runner{Id: "R_A"})
+	require.NoError(err)
+	require.Equal("A", job.Id)
+	_, err = s.JobAck(job.Id, true)
+	require.NoError(err)
+	require.NoError(s.JobComplete(job.Id, &pb.Job_Result{}, nil))
+
+	// Queued jobs can't be deleted
+	err = s.JobDelete("B")
+	require.Error(err)
+	require.Equal(codes.FailedPrecondition, status.Code(err))
+
+	// Completed jobs can
+	require.NoError(s.JobDelete("A"))
+	job, err = s.JobById("A", nil)
+	require.NoError(err)
+	require.Nil(job)
+
+	jobs, err := s.JobList()
+	require.NoError(err)
+	require.Len(jobs, 1)
+	require.Equal("B", jobs[0].Id)
+
+	// Unknown jobs are an error
+	err = s.JobDelete("A")
+	require.Error(err)
+	require.Equal(codes.NotFound, status.Code(err))
+}
+
+func TestJobCountByState(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	// Create two jobs
+	require.NoError(s.JobCreate(serverptypes.TestJobNew(t, &pb.Job{
+		Id: "A",
+	})))
+	require.NoError(s.JobCreate(serverptypes.TestJobNew(t, &pb.Job{
+		Id: "B",
+	})))
+
+	counts, err := s.JobCountByState()
+	require.NoError(err)
+	require.Equal(map[pb.Job_State]int{pb.Job_QUEUED: 2}, counts)
+
+	// Assign and ack one
+	job, err := s.JobAssignForRunner(context.Background(), &pb.Runner{Id: "R_A"})
+	require.NoError(err)
+	_, err = s.JobAck(job.Id, true)
+	require.NoError(err)
+
+	counts, err = s.JobCountByState()
+	require.NoError(err)
+	require.Equal(map[pb.Job_State]int{
+		pb.Job_QUEUED:  1,
+		pb.Job_RUNNING: 1,
+	}, counts)
+}
+
 func TestJobIsAssignable(t *testing.T) {
 	t.Run("no runners", func(t *testing.T) {
 		require := require.New(t)
@@ -885,6 +1041,11 @@ func TestJobHeartbeat(t *testing.T) {
 		defer func() { jobHeartbeatTimeout = old }()
 		jobHeartbeatTimeout = 5 * time.Millisecond
 
+		// Fail the job when the lease expires rather than queueing it again
+		oldAttempts := jobMaxAttempts
+		defer func() { jobMaxAttempts = oldAttempts }()
+		jobMaxAttempts = 1
+
 		// Create a build
 		require.NoError(s.JobCreate(serverptypes.TestJobNew(t, &pb.Job{
 			Id: "A",
@@ -981,6 +1142,11 @@ func TestJobHeartbeat(t *testing.T) {
 		defer func() { jobHeartbeatTimeout = old }()
 		jobHeartbeatTimeout = 250 * time.Millisecond
 
+		// Fail the job when the lease expires rather than queueing it again
+		oldAttempts := jobMaxAttempts
+		defer func() { jobMaxAttempts = oldAttempts }()
+		jobMaxAttempts = 1
+
 		s := TestState(t)
 		defer s.Close()
 
@@ -1052,6 +1218,11 @@ func TestJobHeartbeat(t *testing.T) {
 		defer func() { jobHeartbeatTimeout = old }()
 		jobHeartbeatTimeout = 250 * time.Millisecond
 
+		// Fail the job when the lease expires rather than queueing it again
+		oldAttempts := jobMaxAttempts
+		defer func() { jobMaxAttempts = oldAttempts }()
+		jobMaxAttempts = 1
+
 		s := TestState(t)
 		defer s.Close()
 
@@ -1113,3 +1284,177 @@ func TestJobHeartbeat(t *testing.T) {
 		}, 2*time.Second, 10*time.Millisecond)
 	})
 }
+
+func TestJobLease(t *testing.T) {
+	t.Run("expired lease queues the job again", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+		s.JobTimeoutsSet(0, 50*time.Millisecond)
+
+		// Create a build
+		require.NoError(s.JobCreate(serverptypes.TestJobNew(t, &pb.Job{
+			Id: "A",
+		})))
+
+		// Assign and ack it
+		job, err := s.JobAssignForRunner(context.Background(), &pb.Runner{Id: "R_A"})
+		require.NoError(err)
+		job, err = s.JobAck(job.Id, true)
+		require.NoError(err)
+		require.Equal(uint32(1), job.Attempts)
+		buf := job.OutputBuffer
+		require.NotNil(buf)
+
+		// The runner stops heartbeating, so the job is queued again
+		require.Eventually(func() bool {
+			job, err = s.JobById("A", nil)
+			require.NoError(err)
+			return job.State == pb.Job_QUEUED
+		}, 2*time.Second, 10*time.Millisecond)
+		require.Nil(job.AssignedRunner)
+		require.Nil(job.LeaseExpireTime)
+
+		// Another runner gets it and the output continues in the same buffer
+		job, err = s.JobAssignForRunner(context.Background(), &pb.Runner{Id: "R_B"})
+		require.NoError(err)
+		require.Equal("A", job.Id)
+		require.Equal("R_B", job.AssignedRunner.Id)
+		job, err = s.JobAck(job.Id, true)
+		require.NoError(err)
+		require.Equal(uint32(2), job.Attempts)
+		require.True(buf == job.OutputBuffer)
+
+		require.NoError(s.JobComplete(job.Id, nil, nil))
+	})
+
+	t.Run("fails after the max attempts", func(t *testing.T) {
+		require := require.New(t)
+
+		old := jobMaxAttempts
+		defer func() { jobMaxAttempts = old }()
+		jobMaxAttempts = 2
+
+		s := TestState(t)
+		defer s.Close()
+		s.JobTimeoutsSet(0, 50*time.Millisecond)
+
+		require.NoError(s.JobCreate(serverptypes.TestJobNew(t, &pb.Job{
+			Id: "A",
+		})))
+
+		for i := 0; i < 2; i++ {
+			job, err := s.JobAssignForRunner(context.Background(), &pb.Runner{Id: "R_A"})
+			require.NoError(err)
+			_, err = s.JobAck(job.Id, true)
+			require.NoError(err)
+
+			require.Eventually(func() bool {
+				job, err = s.JobById("A", nil)
+				require.NoError(err)
+				return job.State != pb.Job_RUNNING
+			}, 2*time.Second, 10*time.Millisecond)
+		}
+
+		job, err := s.JobById("A", nil)
+		require.NoError(err)
+		require.Equal(pb.Job_ERROR, job.State)
+		require.Equal(uint32(2), job.Attempts)
+	})
+
+	t.Run("jobs that target a runner fail", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+		s.JobTimeoutsSet(0, 50*time.Millisecond)
+
+		require.NoError(s.JobCreate(serverptypes.TestJobNew(t, &pb.Job{
+			Id: "A",
+			TargetRunner: &pb.Ref_Runner{
+				Target: &pb.Ref_Runner_Id{
+					Id: &pb.Ref_RunnerId{
+						Id: "R_A",
+					},
+				},
+			},
+		})))
+
+		job, err := s.JobAssignForRunner(context.Background(), &pb.Runner{Id: "R_A"})
+		require.NoError(err)
+		_, err = s.JobAck(job.Id, true)
+		require.NoError(err)
+
+		require.Eventually(func() bool {
+			job, err = s.JobById("A", nil)
+			require.NoError(err)
+			return job.State == pb.Job_ERROR
+		}, 2*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("heartbeats renew the lease", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+
+		require.NoError(s.JobCreate(serverptypes.TestJobNew(t, &pb.Job{
+			Id: "A",
+		})))
+
+		job, err := s.JobAssignForRunner(context.Background(), &pb.Runner{Id: "R_A"})
+		require.NoError(err)
+		job, err = s.JobAck(job.Id, true)
+		require.NoError(err)
+		first, err := ptypes.Timestamp(job.LeaseExpireTime)
+		require.NoError(err)
+
+		time.Sleep(10 * time.Millisecond)
+		require.NoError(s.JobHeartbeat(job.Id))
+
+		job, err = s.JobById("A", nil)
+		require.NoError(err)
+		renewed, err := ptypes.Timestamp(job.LeaseExpireTime)
+		require.NoError(err)
+		require.True(renewed.After(first))
+
+		// Heartbeats don't write the lease to disk
+		require.NoError(s.db.View(func(dbTxn *bolt.Tx) error {
+			stored, err := s.jobById(dbTxn, job.Id)
+			require.NoError(err)
+			require.Nil(stored.LeaseExpireTime)
+			return nil
+		}))
+
+		require.NoError(s.JobComplete(job.Id, nil, nil))
+	})
+}
+
+func TestJobListWatch(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	// Create a job
+	require.NoError(s.JobCreate(serverptypes.TestJobNew(t, &pb.Job{
+		Id: "A",
+	})))
+
+	ws := memdb.NewWatchSet()
+	jobs, err := s.JobListWatch(ws)
+	require.NoError(err)
+	require.Len(jobs, 1)
+
+	// Should not be triggered
+	require.True(ws.Watch(time.After(10 * time.Millisecond)))
+
+	// Assign it, which changes the state
+	job, err := s.JobAssignForRunner(context.Background(), &pb.Runner{Id: "R_A"})
+	require.NoError(err)
+	require.Equal("A", job.Id)
+
+	// Should be triggered
+	require.False(ws.Watch(time.After(100 * time.Millisecond)))
+}
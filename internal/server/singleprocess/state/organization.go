@@ -0,0 +1,166 @@
+package state
+
+import (
+	"strings"
+
+	"github.com/boltdb/bolt"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+var organizationBucket = []byte("organization")
+
+func init() {
+	dbBuckets = append(dbBuckets, organizationBucket)
+}
+
+func organizationKey(name string) []byte {
+	return []byte(strings.ToLower(name))
+}
+
+// OrganizationPut creates or replaces an organization. This fails with
+// FailedPrecondition if a project of the organization is in another one.
+func (s *State) OrganizationPut(org *pb.Organization) error {
+	if org.Name == "" {
+		return status.Errorf(codes.InvalidArgument, "organization name is required")
+	}
+
+	return s.db.Update(func(dbTxn *bolt.Tx) error {
+		b := dbTxn.Bucket(organizationBucket)
+		key := organizationKey(org.Name)
+
+		projects := map[string]bool{}
+		for _, p := range org.Projects {
+			projects[strings.ToLower(p.Project)] = true
+		}
+
+		err := b.ForEach(func(k, v []byte) error {
+			if string(k) == string(key) {
+				return nil
+			}
+
+			var other pb.Organization
+			if err := proto.Unmarshal(v, &other); err != nil {
+				return err
+			}
+
+			for _, p := range other.Projects {
+				if projects[strings.ToLower(p.Project)] {
+					return status.Errorf(codes.FailedPrecondition,
+						"project %q is already in organization %q", p.Project, other.Name)
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		return dbPut(b, key, org)
+	})
+}
+
+// OrganizationGet gets an organization by name.
+func (s *State) OrganizationGet(name string) (*pb.Organization, error) {
+	var result pb.Organization
+	err := s.db.View(func(dbTxn *bolt.Tx) error {
+		return dbGet(dbTxn.Bucket(organizationBucket), organizationKey(name), &result)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// OrganizationDelete deletes an organization. Deleting an organization
+// that doesn't exist is not an error.
+func (s *State) OrganizationDelete(name string) error {
+	return s.db.Update(func(dbTxn *bolt.Tx) error {
+		return dbTxn.Bucket(organizationBucket).Delete(organizationKey(name))
+	})
+}
+
+// OrganizationList returns every organization sorted by name.
+func (s *State) OrganizationList() ([]*pb.Organization, error) {
+	var result []*pb.Organization
+	err := s.db.View(func(dbTxn *bolt.Tx) error {
+		return dbTxn.Bucket(organizationBucket).ForEach(func(k, v []byte) error {
+			var org pb.Organization
+			if err := proto.Unmarshal(v, &org); err != nil {
+				return err
+			}
+
+			result = append(result, &org)
+			return nil
+		})
+	})
+
+	return result, err
+}
+
+// OrganizationForProject returns the organization of a project. This
+// returns nil if the project isn't in an organization.
+func (s *State) OrganizationForProject(ref *pb.Ref_Project) (*pb.Organization, error) {
+	orgs, err := s.OrganizationList()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, org := range orgs {
+		for _, p := range org.Projects {
+			if strings.EqualFold(p.Project, ref.Project) {
+				return org, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// OrganizationAddProject adds a project to an organization. Adding a
+// project that is already in the organization is not an error.
+func (s *State) OrganizationAddProject(name string, ref *pb.Ref_Project) error {
+	return s.db.Update(func(dbTxn *bolt.Tx) error {
+		b := dbTxn.Bucket(organizationBucket)
+
+		var org pb.Organization
+		if err := dbGet(b, organizationKey(name), &org); err != nil {
+			return err
+		}
+
+		for _, p := range org.Projects {
+			if strings.EqualFold(p.Project, ref.Project) {
+				return nil
+			}
+		}
+
+		org.Projects = append(org.Projects, &pb.Ref_Project{Project: ref.Project})
+		return dbPut(b, organizationKey(name), &org)
+	})
+}
+
+// OrganizationStorageSize returns the bytes used by the builds, artifacts,
+// deployments, and releases of the projects of an organization.
+func (s *State) OrganizationStorageSize(org *pb.Organization) (uint64, error) {
+	projects := map[string]bool{}
+	for _, p := range org.Projects {
+		projects[strings.ToLower(p.Project)] = true
+	}
+
+	var result uint64
+	for _, op := range []*appOperation{buildOp, artifactOp, deploymentOp, releaseOp} {
+		size, err := op.Size(s, projects)
+		if err != nil {
+			return 0, err
+		}
+
+		result += size
+	}
+
+	return result, nil
+}
@@ -0,0 +1,116 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	serverptypes "github.com/hashicorp/waypoint/internal/server/ptypes"
+)
+
+func TestOrganization(t *testing.T) {
+	t.Run("CRUD", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+
+		// Not found
+		_, err := s.OrganizationGet("foo")
+		require.Error(err)
+		require.Equal(codes.NotFound, status.Code(err))
+
+		require.NoError(s.OrganizationPut(&pb.Organization{
+			Name:     "Foo",
+			Projects: []*pb.Ref_Project{{Project: "a"}},
+		}))
+		require.NoError(s.OrganizationPut(&pb.Organization{Name: "bar"}))
+
+		// Names are case insensitive
+		org, err := s.OrganizationGet("foo")
+		require.NoError(err)
+		require.Equal("Foo", org.Name)
+
+		// Projects can't be in two organizations
+		err = s.OrganizationPut(&pb.Organization{
+			Name:     "bar",
+			Projects: []*pb.Ref_Project{{Project: "A"}},
+		})
+		require.Error(err)
+		require.Equal(codes.FailedPrecondition, status.Code(err))
+
+		// Replacing an organization doesn't conflict with itself
+		require.NoError(s.OrganizationPut(&pb.Organization{
+			Name:     "foo",
+			Projects: []*pb.Ref_Project{{Project: "a"}, {Project: "b"}},
+		}))
+
+		org, err = s.OrganizationForProject(&pb.Ref_Project{Project: "B"})
+		require.NoError(err)
+		require.NotNil(org)
+		require.Equal("foo", org.Name)
+
+		org, err = s.OrganizationForProject(&pb.Ref_Project{Project: "c"})
+		require.NoError(err)
+		require.Nil(org)
+
+		// Adding a project
+		require.NoError(s.OrganizationAddProject("bar", &pb.Ref_Project{Project: "c"}))
+		require.NoError(s.OrganizationAddProject("bar", &pb.Ref_Project{Project: "C"}))
+		org, err = s.OrganizationGet("bar")
+		require.NoError(err)
+		require.Len(org.Projects, 1)
+
+		list, err := s.OrganizationList()
+		require.NoError(err)
+		require.Len(list, 2)
+		require.Equal("bar", list[0].Name)
+
+		require.NoError(s.OrganizationDelete("FOO"))
+		list, err = s.OrganizationList()
+		require.NoError(err)
+		require.Len(list, 1)
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+
+		org := &pb.Organization{
+			Name:     "foo",
+			Projects: []*pb.Ref_Project{{Project: "P_TEST"}},
+		}
+		require.NoError(s.OrganizationPut(org))
+
+		size, err := s.OrganizationStorageSize(org)
+		require.NoError(err)
+		require.Equal(uint64(0), size)
+
+		require.NoError(s.BuildPut(false, serverptypes.TestValidBuild(t, &pb.Build{
+			Id: "A",
+		})))
+		size, err = s.OrganizationStorageSize(org)
+		require.NoError(err)
+		require.True(size > 0)
+
+		// Other projects aren't counted
+		size, err = s.OrganizationStorageSize(&pb.Organization{Name: "bar"})
+		require.NoError(err)
+		require.Equal(uint64(0), size)
+
+		require.NoError(s.JobCreate(serverptypes.TestJobNew(t, &pb.Job{
+			Id: "A",
+		})))
+		count, err := s.JobCountActive(map[string]bool{"p_test": true})
+		require.NoError(err)
+		require.Equal(1, count)
+		count, err = s.JobCountActive(map[string]bool{"other": true})
+		require.NoError(err)
+		require.Equal(0, count)
+	})
+}
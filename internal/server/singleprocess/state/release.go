@@ -18,6 +18,11 @@ func (s *State) ReleasePut(update bool, b *pb.Release) error {
 	return releaseOp.Put(s, update, b)
 }
 
+// ReleaseDelete deletes release records by ID. IDs that don't exist are ignored.
+func (s *State) ReleaseDelete(ids ...string) error {
+	return releaseOp.Delete(s, ids...)
+}
+
 // ReleaseGet gets a release by ref.
 func (s *State) ReleaseGet(ref *pb.Ref_Operation) (*pb.Release, error) {
 	result, err := releaseOp.Get(s, ref)
@@ -0,0 +1,91 @@
+package state
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/boltdb/bolt"
+	"github.com/golang/protobuf/proto"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+var retentionPolicyBucket = []byte("retention_policy")
+
+func init() {
+	dbBuckets = append(dbBuckets, retentionPolicyBucket)
+}
+
+// retentionPolicyKey returns the key of the policy of a project. The
+// default policy has an empty key.
+func retentionPolicyKey(ref *pb.Ref_Project) []byte {
+	return []byte(strings.ToLower(ref.GetProject()))
+}
+
+// RetentionPolicyPut sets the retention policy of a project, or the
+// default policy if the project is unset.
+func (s *State) RetentionPolicyPut(policy *pb.RetentionPolicy) error {
+	return s.db.Update(func(dbTxn *bolt.Tx) error {
+		return dbPut(dbTxn.Bucket(retentionPolicyBucket), retentionPolicyKey(policy.Project), policy)
+	})
+}
+
+// RetentionPolicyDelete deletes the retention policy of a project, or the
+// default policy if ref is nil. Deleting a policy that doesn't exist is
+// not an error.
+func (s *State) RetentionPolicyDelete(ref *pb.Ref_Project) error {
+	return s.db.Update(func(dbTxn *bolt.Tx) error {
+		return dbTxn.Bucket(retentionPolicyBucket).Delete(retentionPolicyKey(ref))
+	})
+}
+
+// RetentionPolicyGet returns the retention policy that applies to a
+// project: its own policy, or else the default policy. This returns nil
+// if neither is set.
+func (s *State) RetentionPolicyGet(ref *pb.Ref_Project) (*pb.RetentionPolicy, error) {
+	var result *pb.RetentionPolicy
+	err := s.db.View(func(dbTxn *bolt.Tx) error {
+		b := dbTxn.Bucket(retentionPolicyBucket)
+
+		data := b.Get(retentionPolicyKey(ref))
+		if data == nil {
+			data = b.Get(retentionPolicyKey(nil))
+		}
+		if data == nil {
+			return nil
+		}
+
+		result = &pb.RetentionPolicy{}
+		return proto.Unmarshal(data, result)
+	})
+
+	return result, err
+}
+
+// RetentionPolicyList returns every retention policy. The default policy
+// is first if it is set, followed by the policies of projects by name.
+func (s *State) RetentionPolicyList() ([]*pb.RetentionPolicy, error) {
+	var result []*pb.RetentionPolicy
+	err := s.db.View(func(dbTxn *bolt.Tx) error {
+		return dbTxn.Bucket(retentionPolicyBucket).ForEach(func(k, v []byte) error {
+			var policy pb.RetentionPolicy
+			if err := proto.Unmarshal(v, &policy); err != nil {
+				return err
+			}
+
+			result = append(result, &policy)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Bolt iterates in key order already, but the keys are lowercased so
+	// we sort by the names as they were given.
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Project.GetProject() < result[j].Project.GetProject()
+	})
+
+	return result, nil
+}
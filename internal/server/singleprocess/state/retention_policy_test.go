@@ -0,0 +1,65 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func TestRetentionPolicy(t *testing.T) {
+	t.Run("Get falls back to the default", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+
+		project := &pb.Ref_Project{Project: "foo"}
+
+		// Nothing is set
+		policy, err := s.RetentionPolicyGet(project)
+		require.NoError(err)
+		require.Nil(policy)
+
+		// The default applies
+		require.NoError(s.RetentionPolicyPut(&pb.RetentionPolicy{KeepLast: 5}))
+		policy, err = s.RetentionPolicyGet(project)
+		require.NoError(err)
+		require.Equal(uint32(5), policy.KeepLast)
+
+		// The project's own policy applies, matched case insensitively
+		require.NoError(s.RetentionPolicyPut(&pb.RetentionPolicy{
+			Project:  &pb.Ref_Project{Project: "Foo"},
+			KeepLast: 2,
+		}))
+		policy, err = s.RetentionPolicyGet(project)
+		require.NoError(err)
+		require.Equal(uint32(2), policy.KeepLast)
+
+		// Deleting it reverts to the default
+		require.NoError(s.RetentionPolicyDelete(project))
+		policy, err = s.RetentionPolicyGet(project)
+		require.NoError(err)
+		require.Equal(uint32(5), policy.KeepLast)
+	})
+
+	t.Run("List puts the default first", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+
+		require.NoError(s.RetentionPolicyPut(&pb.RetentionPolicy{
+			Project:  &pb.Ref_Project{Project: "foo"},
+			KeepLast: 2,
+		}))
+		require.NoError(s.RetentionPolicyPut(&pb.RetentionPolicy{MaxAgeDays: 30}))
+
+		list, err := s.RetentionPolicyList()
+		require.NoError(err)
+		require.Len(list, 2)
+		require.Nil(list[0].Project)
+		require.Equal("foo", list[1].Project.Project)
+	})
+}
@@ -81,6 +81,26 @@ func (s *State) RunnerById(id string) (*pb.Runner, error) {
 	return raw.(*runnerRecord).Runner, nil
 }
 
+// RunnerList returns all the registered runners. If a non-nil WatchSet is
+// given, it is triggered when a runner is registered or removed.
+func (s *State) RunnerList(ws memdb.WatchSet) ([]*pb.Runner, error) {
+	txn := s.inmem.Txn(false)
+	defer txn.Abort()
+
+	iter, err := txn.Get(runnerTableName, runnerIdIndexName+"_prefix", "")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+
+	var result []*pb.Runner
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		result = append(result, raw.(*runnerRecord).Runner)
+	}
+
+	return result, nil
+}
+
 // runnerEmpty returns true if there are no runners registered.
 func (s *State) runnerEmpty(memTxn *memdb.Txn) (bool, error) {
 	iter, err := memTxn.LowerBound(runnerTableName, runnerIdIndexName, "")
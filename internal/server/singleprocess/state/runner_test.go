@@ -2,7 +2,9 @@ package state
 
 import (
 	"testing"
+	"time"
 
+	"github.com/hashicorp/go-memdb"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -38,6 +40,33 @@ func TestRunner_crud(t *testing.T) {
 	require.NoError(s.RunnerDelete(rec.Id))
 }
 
+func TestRunnerList(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	// Empty
+	ws := memdb.NewWatchSet()
+	list, err := s.RunnerList(ws)
+	require.NoError(err)
+	require.Empty(list)
+
+	// Should not be triggered
+	require.True(ws.Watch(time.After(10 * time.Millisecond)))
+
+	// Create a runner
+	require.NoError(s.RunnerCreate(&pb.Runner{Id: "A"}))
+
+	// Should be triggered
+	require.False(ws.Watch(time.After(100 * time.Millisecond)))
+
+	list, err = s.RunnerList(nil)
+	require.NoError(err)
+	require.Len(list, 1)
+	require.Equal("A", list[0].Id)
+}
+
 func TestRunnerById_notFound(t *testing.T) {
 	require := require.New(t)
 
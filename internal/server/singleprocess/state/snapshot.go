@@ -10,6 +10,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -21,6 +22,7 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/hashicorp/waypoint/internal/pkg/protowriter"
+	"github.com/hashicorp/waypoint/internal/pkg/snapshotsum"
 	"github.com/hashicorp/waypoint/internal/protocolversion"
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
 )
@@ -42,8 +44,9 @@ func (s *State) CreateSnapshot(w io.Writer) error {
 
 	// Write our header
 	if err := dw.WriteMsg(&pb.Snapshot_Header{
-		Version: protocolversion.Current(),
-		Format:  pb.Snapshot_Header_BOLT,
+		Version:       protocolversion.Current(),
+		Format:        pb.Snapshot_Header_BOLT,
+		SchemaVersion: snapshotSchemaCurrent,
 	}); err != nil {
 		return err
 	}
@@ -59,6 +62,7 @@ func (s *State) CreateSnapshot(w io.Writer) error {
 				if err := dw.WriteMsg(&pb.Snapshot_BoltChunk{
 					Bucket: string(name),
 					Items:  chunkItems,
+					Sha256: snapshotsum.Chunk(string(name), chunkItems, nil),
 				}); err != nil {
 					return err
 				}
@@ -107,13 +111,117 @@ func (s *State) CreateSnapshot(w io.Writer) error {
 	})
 }
 
+// RestoreOption is an option that can be passed to StageRestoreSnapshot
+// to modify how the snapshot is restored.
+type RestoreOption func(*restoreConfig)
+
+type restoreConfig struct {
+	skipTypes map[string]bool
+	dryRun    bool
+	applyRate int
+	scope     *restoreScope
+
+	// rename are the new names of projects to rename, keyed by their
+	// lowercased name in the snapshot.
+	rename map[string]string
+}
+
+// validate checks that the restore options are valid.
+func (c *restoreConfig) validate() error {
+	if err := ValidateRecordTypes(c.skipTypeNames()); err != nil {
+		return err
+	}
+
+	return c.validateRename()
+}
+
+// skipTypeNames returns the sorted record types to skip.
+func (c *restoreConfig) skipTypeNames() []string {
+	var result []string
+	for t := range c.skipTypes {
+		result = append(result, t)
+	}
+
+	sort.Strings(result)
+	return result
+}
+
+// WithRestoreSkipRecordTypes excludes all records of the given types from
+// the restore. The types must be valid according to ValidateRecordTypes.
+func WithRestoreSkipRecordTypes(types ...string) RestoreOption {
+	return func(c *restoreConfig) {
+		if c.skipTypes == nil {
+			c.skipTypes = map[string]bool{}
+		}
+
+		for _, t := range types {
+			c.skipTypes[t] = true
+		}
+	}
+}
+
+// WithRestoreDryRun validates the restore by applying it to a scratch
+// database rather than staging it. The scratch database is removed once
+// the restore has been applied, and the live database is never modified.
+func WithRestoreDryRun() RestoreOption {
+	return func(c *restoreConfig) {
+		c.dryRun = true
+	}
+}
+
+// WithRestoreApplyRate limits the number of records processed per second
+// while staging the restore. A rate of zero is unlimited.
+func WithRestoreApplyRate(perSecond int) RestoreOption {
+	return func(c *restoreConfig) {
+		c.applyRate = perSecond
+	}
+}
+
+// RecordTypes returns the sorted list of record types that are stored
+// in the database. A record type is the name of a bucket in a snapshot.
+func RecordTypes() []string {
+	result := make([]string, len(dbBuckets))
+	for i, b := range dbBuckets {
+		result[i] = string(b)
+	}
+
+	sort.Strings(result)
+	return result
+}
+
+// ValidateRecordTypes returns an InvalidArgument error if any of the given
+// types are not known record types.
+func ValidateRecordTypes(types []string) error {
+	known := RecordTypes()
+	for _, t := range types {
+		idx := sort.SearchStrings(known, t)
+		if idx >= len(known) || known[idx] != t {
+			return status.Errorf(codes.InvalidArgument,
+				"unknown record type %q, valid record types are: %s",
+				t, strings.Join(known, ", "))
+		}
+	}
+
+	return nil
+}
+
 // StageRestoreSnapshot stages a database restore for the next server restart.
 // This will create a temporary file alongside the data file so we must have
 // write access to the directory containing the database.
-func (s *State) StageRestoreSnapshot(r io.Reader) error {
+func (s *State) StageRestoreSnapshot(r io.Reader, opts ...RestoreOption) error {
 	log := s.log.Named("restore")
 	log.Warn("beginning to stage snapshot restore")
 
+	var cfg restoreConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	skipTypes := cfg.skipTypeNames()
+
 	ri := newRestoreInfo(log, s.db)
 	if err := ri.Lock(); err != nil {
 		return err
@@ -141,23 +249,107 @@ func (s *State) StageRestoreSnapshot(r io.Reader) error {
 		return err
 	}
 
-	// We do want to check that this appears valid because if we can't open
-	// the file then the server will never start again until it is cleaned up.
+	// We verify the entire snapshot, including its checksums, before it is
+	// staged. If it is truncated or corrupted, the restore would fail at
+	// startup after the existing data has already been replaced by this
+	// restore, and the server would never start again until it is cleaned up.
 	log.Info("validating restore file")
-	sr, closer, err := snapshotReader(ri.StageTempPath, nil)
+	header, err := verifySnapshot(ri.StageTempPath)
 	if err != nil {
 		log.Error("error while validating restore file", "err", err)
-		return fmt.Errorf("error validating restore data: %s", err)
+		return status.Errorf(codes.InvalidArgument,
+			"error validating restore data: %s", err)
+	}
+	if header.ParentId != "" {
+		log.Error("refusing to restore incremental snapshot", "parent", header.ParentId)
+		return status.Errorf(codes.InvalidArgument,
+			"snapshot is incremental and must be applied to its parent snapshot "+
+				"(ID %s) before it can be restored", header.ParentId)
+	}
+
+	// Refuse data this server can't read, such as from a newer server, and
+	// migrate older data to the current schema. We do this before anything
+	// else reads the records so that they're always in the current schema.
+	schema := snapshotSchema(header)
+	if err := CheckSnapshotSchema(schema, header.Version.GetVersion()); err != nil {
+		log.Error("refusing to restore snapshot with incompatible schema",
+			"schema_version", schema, "created_by", header.Version.GetVersion())
+		return err
+	}
+	if schema < snapshotSchemaCurrent {
+		log.Info("migrating restore file",
+			"from", schema, "to", snapshotSchemaCurrent)
+		if err := migrateSnapshot(ri.StageTempPath, fi.Mode(), schema); err != nil {
+			log.Error("error while migrating restore file", "err", err)
+			return fmt.Errorf("error migrating restore data: %s", err)
+		}
 	}
 
-	var header pb.Snapshot_Header
-	err = sr.ReadMsg(&header)
-	if cerr := closer(); cerr != nil {
-		err = cerr
+	// If we're skipping any record types, rewrite the snapshot without
+	// them. We do this now rather than at startup so that any problems
+	// with the snapshot are reported to the user doing the restore. This
+	// is also where we pace the processing of records if a rate is set.
+	if len(cfg.skipTypes) > 0 || cfg.applyRate > 0 {
+		log.Info("rewriting restore file",
+			"skip_types", skipTypes, "rate", cfg.applyRate)
+		pacer := newRestorePacer(cfg.applyRate)
+		skipped := map[string]int{}
+		filterPath := ri.StageTempPath + ".filter"
+		err := rewriteSnapshot(filterPath, ri.StageTempPath, fi.Mode(), nil, func(chunk *pb.Snapshot_BoltChunk) {
+			if cfg.skipTypes[chunk.Bucket] {
+				skipped[chunk.Bucket] += len(chunk.Items)
+				chunk.Items = nil
+			}
+
+			pacer.Wait(len(chunk.Items))
+		})
+		if err != nil {
+			os.Remove(filterPath)
+			log.Error("error while rewriting restore file", "err", err)
+			return fmt.Errorf("error validating restore data: %s", err)
+		}
+
+		log.Info("removed skipped record types", "skipped", skipped)
+		if err := atomic.ReplaceFile(filterPath, ri.StageTempPath); err != nil {
+			log.Error("error replacing file", "err", err)
+			return err
+		}
 	}
-	if err != nil {
-		log.Error("error while validating restore file", "err", err)
-		return fmt.Errorf("error validating restore data: %s", err)
+
+	// If we're renaming projects, rewrite the snapshot with the new names.
+	// We do this before limiting the scope since the scope refers to the
+	// new names.
+	if len(cfg.rename) > 0 {
+		log.Info("renaming projects in restore file", "rename", cfg.rename)
+		if err := s.renameSnapshot(ri.StageTempPath, fi.Mode(), cfg.rename); err != nil {
+			log.Error("error while renaming projects", "err", err)
+			return err
+		}
+	}
+
+	// If the restore is limited to some projects or applications, rewrite
+	// the snapshot to contain the current data for everything else.
+	if cfg.scope != nil {
+		log.Info("limiting restore to selected projects and applications")
+		cfg.scope.skipTypes = cfg.skipTypes
+		scopePath := ri.StageTempPath + ".scope"
+		if err := s.scopeSnapshot(log, scopePath, ri.StageTempPath, fi.Mode(), cfg.scope); err != nil {
+			os.Remove(scopePath)
+			log.Error("error while limiting restore scope", "err", err)
+			return fmt.Errorf("error limiting restore data: %s", err)
+		}
+
+		if err := atomic.ReplaceFile(scopePath, ri.StageTempPath); err != nil {
+			log.Error("error replacing file", "err", err)
+			return err
+		}
+	}
+
+	// For a dry run we apply the restore to a scratch database to validate
+	// it end-to-end and then clean everything up without staging it.
+	if cfg.dryRun {
+		defer os.Remove(ri.StageTempPath)
+		return restoreScratch(log, ri)
 	}
 
 	// Replace our file
@@ -223,12 +415,114 @@ func finalizeRestore(log hclog.Logger, db *bolt.DB) (*bolt.DB, error) {
 		return db, fmt.Errorf("invalid snapshot format (got code: %d)", header.Format)
 	}
 
-	// Open a temporary file to write our raw bolt data
+	// Write our raw bolt data to a temporary file
 	log.Info("reading bolt information and writing it into a new bolt db", "path", ri.StageTempPath)
-	tempDb, err := bolt.Open(ri.StageTempPath, 0600, &bolt.Options{Timeout: 2 * time.Second})
+	if err := restoreBolt(log, sr, checksum, ri.StageTempPath); err != nil {
+		return db, err
+	}
+
+	// The audit log is never restored, we keep our own.
+	log.Info("copying audit log into the restored database")
+	if err := keepAuditEvents(log, db, ri.StageTempPath); err != nil {
+		log.Error("error copying audit log", "err", err)
+		return db, err
+	}
+
+	// Revoked tokens stay revoked.
+	log.Info("copying token revocations into the restored database")
+	if err := keepTokenRevocations(log, db, ri.StageTempPath); err != nil {
+		log.Error("error copying token revocations", "err", err)
+		return db, err
+	}
+
+	// Close our DB, we will reopen with the new one
+	if err := db.Close(); err != nil {
+		log.Error("failed to close db for restore", "err", err)
+		return db, err
+	}
+
+	// Get our file info
+	var mode os.FileMode = 0666
+	if fi, err := os.Stat(ri.DBPath); err == nil {
+		mode = fi.Mode()
+	} else if !os.IsNotExist(err) {
+		return db, err
+	}
+
+	// Replace our file
+	log.Info("atomically replacing db file", "src", ri.StagePath, "dest", ri.DBPath)
+	if err := atomic.ReplaceFile(ri.StageTempPath, ri.DBPath); err != nil {
+		log.Error("error replacing file", "err", err)
+		return db, err
+	}
+
+	// Delete our restore data
+	log.Info("finished reading restore data, removing now")
+	if err := os.Remove(ri.StagePath); err != nil {
+		log.Error("error removing restore data", "err", err)
+		return db, err
+	}
+
+	// Reopen the DB
+	log.Info("reopening database", "path", ri.DBPath)
+	db, err = bolt.Open(ri.DBPath, mode, &bolt.Options{
+		Timeout: 2 * time.Second,
+	})
 	if err != nil {
+		log.Error("error reopening db", "err", err)
 		return db, err
 	}
+
+	log.Warn("database restore successful")
+	return db, nil
+}
+
+// restoreScratch applies the snapshot at the temporary stage path to a
+// scratch bolt database alongside the real one, and then removes the
+// scratch database. This validates a restore without staging it.
+func restoreScratch(log hclog.Logger, ri *restoreInfo) error {
+	scratchPath := ri.StagePath + ".scratch"
+	log.Info("dry run, applying snapshot to a scratch database", "path", scratchPath)
+
+	// Remove any leftovers from a previous run since bolt would open them
+	// rather than starting with an empty database.
+	if err := os.Remove(scratchPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	defer os.Remove(scratchPath)
+
+	checksum := sha256.New()
+	sr, closer, err := snapshotReader(ri.StageTempPath, checksum)
+	if err != nil {
+		return err
+	}
+	defer closer()
+
+	var header pb.Snapshot_Header
+	if err := sr.ReadMsg(&header); err != nil {
+		return fmt.Errorf("error reading restore header data: %s", err)
+	}
+	if header.Format != pb.Snapshot_Header_BOLT {
+		return fmt.Errorf("invalid snapshot format (got code: %d)", header.Format)
+	}
+
+	if err := restoreBolt(log, sr, checksum, scratchPath); err != nil {
+		log.Error("dry run restore failed", "err", err)
+		return fmt.Errorf("error applying restore data: %s", err)
+	}
+
+	log.Warn("dry run restore successful, scratch database removed")
+	return nil
+}
+
+// restoreBolt reads the bolt chunks from sr and writes them into a new
+// bolt database at path, then verifies the snapshot checksum. sr must be
+// positioned just after the header and h must be the hash sr writes to.
+func restoreBolt(log hclog.Logger, sr protowriter.Reader, h hash.Hash, path string) error {
+	tempDb, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 2 * time.Second})
+	if err != nil {
+		return err
+	}
 	for {
 		var chunk pb.Snapshot_BoltChunk
 		err := sr.ReadMsg(&chunk)
@@ -237,7 +531,11 @@ func finalizeRestore(log hclog.Logger, db *bolt.DB) (*bolt.DB, error) {
 		}
 		if err != nil {
 			tempDb.Close()
-			return db, err
+			return err
+		}
+		if err := verifyChunk(&chunk); err != nil {
+			tempDb.Close()
+			return err
 		}
 
 		if len(chunk.Bucket) > 0 && len(chunk.Items) > 0 {
@@ -255,26 +553,37 @@ func finalizeRestore(log hclog.Logger, db *bolt.DB) (*bolt.DB, error) {
 				return nil
 			}); err != nil {
 				tempDb.Close()
-				return db, err
+				return err
 			}
 		}
 
 		if chunk.Final {
-			tempDb.Close()
 			break
 		}
 	}
+	if err := tempDb.Close(); err != nil {
+		return err
+	}
+
+	// Read the trailer and validate our checksum
+	return verifySnapshotTrailer(log, sr, h)
+}
 
+// verifySnapshotTrailer reads the trailer from sr and verifies that the
+// checksum matches h. This must be called immediately after reading the
+// final bolt chunk, since the checksum is for all the data up to but not
+// including the trailer.
+func verifySnapshotTrailer(log hclog.Logger, sr protowriter.Reader, h hash.Hash) error {
 	// Determine our checksum. It is very important to do this here before
 	// we read the trailer because the checksum is up to but not including
 	// the trailer.
-	finalChecksum := hex.EncodeToString(checksum.Sum(nil))
+	finalChecksum := hex.EncodeToString(h.Sum(nil))
 
 	// Read the trailer
 	var trailer pb.Snapshot_Trailer
 	if err := sr.ReadMsg(&trailer); err != nil {
 		log.Error("error while parsing restore trailer", "err", err)
-		return db, fmt.Errorf("error reading restore trailer data: %s", err)
+		return fmt.Errorf("error reading restore trailer data: %s", err)
 	}
 
 	// Validate the checksum
@@ -285,54 +594,189 @@ func finalizeRestore(log hclog.Logger, db *bolt.DB) (*bolt.DB, error) {
 				"expected", v.Sha256,
 				"actual", finalChecksum,
 			)
-			return db, fmt.Errorf("checksum mismatch, expected %s got %s", v.Sha256, finalChecksum)
+			return fmt.Errorf("checksum mismatch, expected %s got %s", v.Sha256, finalChecksum)
 		}
 
 	default:
 		log.Error("unknown checksum type", "type", fmt.Sprintf("%T", trailer.Checksum))
-		return db, fmt.Errorf("error reading restore trailer data: unknown checksum type")
+		return fmt.Errorf("error reading restore trailer data: unknown checksum type")
 	}
 
-	// Close our DB, we will reopen with the new one
-	if err := db.Close(); err != nil {
-		log.Error("failed to close db for restore", "err", err)
-		return db, err
+	return nil
+}
+
+// verifySnapshot reads the entire snapshot at path and verifies the
+// checksum of every chunk and of the snapshot as a whole. The header is
+// returned if the snapshot is valid.
+func verifySnapshot(path string) (*pb.Snapshot_Header, error) {
+	checksum := sha256.New()
+	sr, closer, err := snapshotReader(path, checksum)
+	if err != nil {
+		return nil, err
 	}
+	defer closer()
 
-	// Get our file info
-	var mode os.FileMode = 0666
-	if fi, err := os.Stat(ri.DBPath); err == nil {
-		mode = fi.Mode()
-	} else if !os.IsNotExist(err) {
-		return db, err
+	var header pb.Snapshot_Header
+	if err := sr.ReadMsg(&header); err != nil {
+		return nil, fmt.Errorf("error reading header: %s", err)
 	}
 
-	// Replace our file
-	log.Info("atomically replacing db file", "src", ri.StagePath, "dest", ri.DBPath)
-	if err := atomic.ReplaceFile(ri.StageTempPath, ri.DBPath); err != nil {
-		log.Error("error replacing file", "err", err)
-		return db, err
+	for {
+		var chunk pb.Snapshot_BoltChunk
+		if err := sr.ReadMsg(&chunk); err != nil {
+			// Note that EOF is also an error here since we expect a final
+			// chunk. This is how we detect truncated snapshots.
+			return nil, fmt.Errorf("error reading data: %s", err)
+		}
+		if err := verifyChunk(&chunk); err != nil {
+			return nil, err
+		}
+
+		if chunk.Final {
+			break
+		}
 	}
 
-	// Delete our restore data
-	log.Info("finished reading restore data, removing now")
-	if err := os.Remove(ri.StagePath); err != nil {
-		log.Error("error removing restore data", "err", err)
-		return db, err
+	if err := verifySnapshotTrailer(hclog.NewNullLogger(), sr, checksum); err != nil {
+		return nil, err
 	}
 
-	// Reopen the DB
-	log.Info("reopening database", "path", ri.DBPath)
-	db, err = bolt.Open(ri.DBPath, mode, &bolt.Options{
-		Timeout: 2 * time.Second,
-	})
+	return &header, nil
+}
+
+// verifyChunk verifies the checksum of a single chunk, if it has one.
+func verifyChunk(chunk *pb.Snapshot_BoltChunk) error {
+	return snapshotsum.Verify(chunk.Sha256, chunk.Bucket, chunk.Items, chunk.DeletedKeys)
+}
+
+// rewriteSnapshot reads the snapshot at src and writes a new snapshot to
+// dst, calling fn with each bolt chunk so that it may be modified in place.
+// Chunks that have no items after fn is called are not written. If
+// headerFn is non-nil, it is called with the header so that it may be
+// modified too. The checksum of src is verified and dst is written with a
+// new checksum.
+func rewriteSnapshot(
+	dst, src string,
+	mode os.FileMode,
+	headerFn func(*pb.Snapshot_Header),
+	fn func(*pb.Snapshot_BoltChunk),
+) error {
+	checksum := sha256.New()
+	sr, closer, err := snapshotReader(src, checksum)
 	if err != nil {
-		log.Error("error reopening db", "err", err)
-		return db, err
+		return err
 	}
+	defer closer()
 
-	log.Warn("database restore successful")
-	return db, nil
+	f, err := os.OpenFile(dst, os.O_RDWR|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Same format as CreateSnapshot: the checksum is of the raw proto bytes.
+	newChecksum := sha256.New()
+	bw := bufio.NewWriter(f)
+	gzw := gzip.NewWriter(bw)
+	dw := protowriter.NewDelimitedWriter(io.MultiWriter(gzw, newChecksum))
+
+	var header pb.Snapshot_Header
+	if err := sr.ReadMsg(&header); err != nil {
+		return fmt.Errorf("error reading restore header data: %s", err)
+	}
+	if header.Format != pb.Snapshot_Header_BOLT {
+		return fmt.Errorf("invalid snapshot format (got code: %d)", header.Format)
+	}
+	if headerFn != nil {
+		headerFn(&header)
+	}
+	if err := dw.WriteMsg(&header); err != nil {
+		return err
+	}
+
+	for {
+		var chunk pb.Snapshot_BoltChunk
+		if err := sr.ReadMsg(&chunk); err != nil {
+			// Note that EOF is also an error here since we expect a final chunk.
+			return err
+		}
+
+		if chunk.Final {
+			if err := dw.WriteMsg(&chunk); err != nil {
+				return err
+			}
+
+			break
+		}
+
+		if err := verifyChunk(&chunk); err != nil {
+			return err
+		}
+
+		fn(&chunk)
+		if len(chunk.Items) == 0 {
+			continue
+		}
+
+		chunk.Sha256 = snapshotsum.Chunk(chunk.Bucket, chunk.Items, chunk.DeletedKeys)
+
+		if err := dw.WriteMsg(&chunk); err != nil {
+			return err
+		}
+	}
+
+	if err := verifySnapshotTrailer(hclog.NewNullLogger(), sr, checksum); err != nil {
+		return err
+	}
+
+	if err := dw.WriteMsg(&pb.Snapshot_Trailer{
+		Checksum: &pb.Snapshot_Trailer_Sha256{
+			Sha256: hex.EncodeToString(newChecksum.Sum(nil)),
+		},
+	}); err != nil {
+		return err
+	}
+
+	if err := gzw.Close(); err != nil {
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+// restorePacer limits the rate at which records are processed. A nil
+// pacer does not limit the rate.
+type restorePacer struct {
+	rate  int
+	start time.Time
+	n     int
+}
+
+// newRestorePacer returns a pacer for the given records per second. This
+// returns nil if rate is zero or less.
+func newRestorePacer(rate int) *restorePacer {
+	if rate <= 0 {
+		return nil
+	}
+
+	return &restorePacer{rate: rate, start: time.Now()}
+}
+
+// Wait records that n more records are being processed and blocks until
+// processing them would not exceed the rate.
+func (p *restorePacer) Wait(n int) {
+	if p == nil {
+		return
+	}
+
+	p.n += n
+	want := time.Duration(p.n) * time.Second / time.Duration(p.rate)
+	if d := want - time.Since(p.start); d > 0 {
+		time.Sleep(d)
+	}
 }
 
 // snapshotReader opens the delimited reader for a snapshot.
@@ -0,0 +1,136 @@
+package state
+
+import (
+	"github.com/golang/protobuf/proto"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// RestorePreview applies restore options to individual records the same
+// way that StageRestoreSnapshot applies them to a snapshot. This lets a
+// client compare a snapshot with a snapshot of the current data to show
+// what a restore would change before it is sent.
+type RestorePreview struct {
+	cfg restoreConfig
+
+	// state is only used for its record keys, which don't depend on the
+	// data in the state.
+	state *State
+}
+
+// NewRestorePreview returns a RestorePreview for the given options. The
+// skipped record types aren't validated since the server restoring the
+// snapshot may know of record types that the caller doesn't; the server
+// validates them when the restore is opened. Options that don't change
+// which records are restored, such as WithRestoreApplyRate, have no effect.
+func NewRestorePreview(opts ...RestoreOption) (*RestorePreview, error) {
+	var cfg restoreConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if err := cfg.validateRename(); err != nil {
+		return nil, err
+	}
+	if cfg.scope != nil {
+		cfg.scope.skipTypes = cfg.skipTypes
+	}
+
+	return &RestorePreview{cfg: cfg, state: &State{}}, nil
+}
+
+// Restores returns the key and value that the snapshot record with the
+// given record type and key is restored with, or false if the record is
+// not restored. Project records must be passed to Project afterwards.
+func (p *RestorePreview) Restores(bucket, k string, v []byte) (string, []byte, bool, error) {
+	// The audit log is never restored, see keepAuditEvents.
+	if p.cfg.skipTypes[bucket] || bucket == string(auditEventBucket) {
+		return "", nil, false, nil
+	}
+
+	if len(p.cfg.rename) > 0 && renameRecordValue(bucket) != nil {
+		r, err := p.state.renameRecord(bucket, k, v, p.cfg.rename)
+		if err != nil {
+			return "", nil, false, err
+		}
+
+		k, v = r.Key, r.Value
+	}
+
+	if p.cfg.scope != nil && bucket != string(projectBucket) {
+		ok, err := p.cfg.scope.contains(bucket, v)
+		if err != nil || !ok {
+			return "", nil, false, err
+		}
+	}
+
+	return k, v, true, nil
+}
+
+// Keeps returns true if the current record with the given record type is
+// kept as it is, even if the snapshot has a record with the same key.
+// Current records that aren't kept are removed unless the snapshot has
+// them. This must not be called for project records; see Project.
+func (p *RestorePreview) Keeps(bucket string, v []byte) (bool, error) {
+	switch bucket {
+	case string(auditEventBucket):
+		return true, nil
+
+	case string(tokenBucket):
+		// Revoked tokens stay revoked, see keepTokenRevocations.
+		var info pb.TokenInfo
+		if err := proto.Unmarshal(v, &info); err != nil {
+			return false, err
+		}
+		if info.RevokedAt != nil {
+			return true, nil
+		}
+	}
+
+	if p.cfg.scope == nil {
+		return false, nil
+	}
+
+	ok, err := p.cfg.scope.contains(bucket, v)
+	return !ok, err
+}
+
+// Project returns the project record after the restore given the current
+// record and the snapshot record returned by Restores, either of which may
+// be nil. A nil result means the project doesn't exist after the restore.
+func (p *RestorePreview) Project(current, incoming []byte) ([]byte, error) {
+	if p.cfg.scope == nil {
+		return incoming, nil
+	}
+
+	decode := func(v []byte) (*pb.Project, error) {
+		if v == nil {
+			return nil, nil
+		}
+
+		var result pb.Project
+		return &result, proto.Unmarshal(v, &result)
+	}
+
+	currentP, err := decode(current)
+	if err != nil {
+		return nil, err
+	}
+	incomingP, err := decode(incoming)
+	if err != nil {
+		return nil, err
+	}
+
+	switch result := p.cfg.scope.mergeProject(currentP, incomingP); result {
+	case nil:
+		return nil, nil
+
+	case currentP:
+		return current, nil
+
+	case incomingP:
+		return incoming, nil
+
+	default:
+		return proto.Marshal(result)
+	}
+}
@@ -0,0 +1,317 @@
+package state
+
+import (
+	"os"
+	"strings"
+
+	"github.com/boltdb/bolt"
+	"github.com/golang/protobuf/proto"
+	"github.com/natefinch/atomic"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// WithRestoreRenameProjects renames projects in the snapshot as they are
+// restored. The names are keyed by the name of the project in the snapshot.
+// The restore is limited to the renamed projects, as with
+// WithRestoreProjects using the new names, so that they are restored
+// alongside the existing data. The new names must not already exist.
+func WithRestoreRenameProjects(names map[string]string) RestoreOption {
+	return func(c *restoreConfig) {
+		if c.rename == nil {
+			c.rename = map[string]string{}
+		}
+		if c.scope == nil {
+			c.scope = newRestoreScope()
+		}
+
+		for from, to := range names {
+			c.rename[strings.ToLower(from)] = to
+			c.scope.projects[strings.ToLower(to)] = true
+		}
+	}
+}
+
+// validateRename checks that the projects to rename are valid and don't
+// conflict with the rest of the restore options.
+func (c *restoreConfig) validateRename() error {
+	if len(c.rename) == 0 {
+		return nil
+	}
+
+	targets := map[string]string{}
+	for from, to := range c.rename {
+		if from == "" || to == "" {
+			return status.Errorf(codes.InvalidArgument,
+				"project names to rename must not be empty")
+		}
+
+		key := strings.ToLower(to)
+		if other, ok := targets[key]; ok {
+			return status.Errorf(codes.InvalidArgument,
+				"projects %q and %q can't both be renamed to %q", other, from, to)
+		}
+		targets[key] = from
+	}
+
+	// Restoring a project by its name in the snapshot would delete it from
+	// the server since the snapshot no longer has a project by that name.
+	for from := range c.rename {
+		if _, ok := targets[from]; ok {
+			return status.Errorf(codes.InvalidArgument,
+				"project %q can't be renamed since another project is renamed to it", from)
+		}
+		if c.scope.projects[from] || len(c.scope.apps[from]) > 0 {
+			return status.Errorf(codes.InvalidArgument,
+				"project %q is renamed, it can't also be restored by its name in the snapshot", from)
+		}
+	}
+
+	return nil
+}
+
+// renameSnapshot rewrites the snapshot at path with the projects renamed.
+// The names to rename are keyed by the lowercased name in the snapshot.
+// The new names must not already be used by the snapshot or by the current
+// data. Records keep their IDs, so the records of renamed projects must not
+// have the same IDs as the current records, which is the case if the data
+// was restored to this server before.
+func (s *State) renameSnapshot(path string, mode os.FileMode, rename map[string]string) error {
+	renamePath := path + ".rename"
+	err := s.db.View(func(dbTxn *bolt.Tx) error {
+		b := dbTxn.Bucket(projectBucket)
+		for _, to := range rename {
+			if b.Get(s.projectIdByRef(&pb.Ref_Project{Project: to})) != nil {
+				return status.Errorf(codes.AlreadyExists,
+					"project %q already exists on the server, choose a different name", to)
+			}
+		}
+
+		return s.renameSnapshotTxn(dbTxn, renamePath, path, mode, rename)
+	})
+	if err != nil {
+		os.Remove(renamePath)
+		return err
+	}
+
+	return atomic.ReplaceFile(renamePath, path)
+}
+
+func (s *State) renameSnapshotTxn(
+	dbTxn *bolt.Tx,
+	dst, src string,
+	mode os.FileMode,
+	rename map[string]string,
+) error {
+	// names are the names of the projects in the snapshot after renaming,
+	// keyed by their ID, so that we can detect collisions. found are the
+	// projects we renamed.
+	names := map[string]string{}
+	found := map[string]bool{}
+
+	var renameErr error
+	err := rewriteSnapshot(dst, src, mode, nil, func(chunk *pb.Snapshot_BoltChunk) {
+		if renameErr != nil || renameRecordValue(chunk.Bucket) == nil {
+			// No records of this type refer to projects.
+			return
+		}
+
+		items := map[string][]byte{}
+		for k, v := range chunk.Items {
+			r, err := s.renameRecord(chunk.Bucket, k, v, rename)
+			if err != nil {
+				renameErr = err
+				return
+			}
+
+			switch msg := r.Msg.(type) {
+			case *pb.Project:
+				if r.From != "" {
+					found[r.From] = true
+				}
+
+				if other, ok := names[r.Key]; ok {
+					renameErr = status.Errorf(codes.InvalidArgument,
+						"renaming would give projects %q and %q in the snapshot the same name",
+						other, msg.Name)
+					return
+				}
+				names[r.Key] = msg.Name
+
+			case *pb.ConfigVar:
+				// Config is keyed by its scope, so a renamed project's
+				// config never has the key of a current record.
+
+			default:
+				if b := dbTxn.Bucket([]byte(chunk.Bucket)); r.Changed && b != nil && b.Get([]byte(r.Key)) != nil {
+					renameErr = status.Errorf(codes.AlreadyExists,
+						"%s record %q of a renamed project already exists on the server, "+
+							"which happens if the snapshot was restored to this server before",
+						chunk.Bucket, r.Key)
+					return
+				}
+			}
+
+			items[r.Key] = r.Value
+		}
+
+		chunk.Items = items
+	})
+	if err == nil {
+		err = renameErr
+	}
+	if err != nil {
+		return err
+	}
+
+	for from := range rename {
+		if !found[from] {
+			return status.Errorf(codes.InvalidArgument,
+				"project %q to rename was not found in the snapshot", from)
+		}
+	}
+
+	return nil
+}
+
+// renamedRecord is a snapshot record after renaming the projects it
+// refers to.
+type renamedRecord struct {
+	Key   string
+	Value []byte
+	Msg   proto.Message
+
+	// Changed is true if the record refers to a renamed project.
+	Changed bool
+
+	// From is the lowercased name in the snapshot of a renamed project
+	// record. It is empty for all other records.
+	From string
+}
+
+// renameRecord renames the projects that the record with key k refers to.
+// The record type must be one that renameRecordValue returns a value for.
+// Records that are keyed by their project's name get a new key.
+func (s *State) renameRecord(
+	bucket, k string,
+	v []byte,
+	rename map[string]string,
+) (*renamedRecord, error) {
+	msg := renameRecordValue(bucket)
+	if err := proto.Unmarshal(v, msg); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"error decoding %s record: %s", bucket, err)
+	}
+
+	r := &renamedRecord{Key: k, Value: v, Msg: msg}
+	r.Changed = renameProjectRefs(proto.MessageReflect(msg), rename)
+	switch msg := msg.(type) {
+	case *pb.Project:
+		from := strings.ToLower(msg.Name)
+		if to, ok := rename[from]; ok {
+			msg.Name = to
+			r.From = from
+			r.Changed = true
+		}
+
+		r.Key = string(s.projectId(msg))
+
+	case *pb.ConfigVar:
+		r.Key = string(s.configVarId(msg))
+	}
+
+	if r.Changed {
+		data, err := proto.Marshal(msg)
+		if err != nil {
+			return nil, err
+		}
+
+		r.Value = data
+	}
+
+	return r, nil
+}
+
+// renameRecordValue returns a new value to decode a record of the given
+// record type into, or nil if records of the type don't refer to projects.
+func renameRecordValue(bucket string) proto.Message {
+	switch bucket {
+	case string(projectBucket):
+		return &pb.Project{}
+
+	case string(configBucket):
+		return &pb.ConfigVar{}
+
+	case string(jobBucket):
+		return &pb.Job{}
+	}
+
+	for _, op := range restoreScopeOps {
+		if bucket == string(op.Bucket) {
+			return op.newStruct()
+		}
+	}
+
+	return nil
+}
+
+var (
+	refProjectName     = (&pb.Ref_Project{}).ProtoReflect().Descriptor().FullName()
+	refApplicationName = (&pb.Ref_Application{}).ProtoReflect().Descriptor().FullName()
+)
+
+// renameProjectRefs renames the projects in every project and application
+// reference within m and returns true if any were renamed. The names to
+// rename are keyed by lowercased name. Values within Any fields are not
+// renamed.
+func renameProjectRefs(m protoreflect.Message, rename map[string]string) bool {
+	var changed bool
+	if name := m.Descriptor().FullName(); name == refProjectName || name == refApplicationName {
+		fd := m.Descriptor().Fields().ByName("project")
+		if to, ok := rename[strings.ToLower(m.Get(fd).String())]; ok {
+			m.Set(fd, protoreflect.ValueOfString(to))
+			changed = true
+		}
+	}
+
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsList():
+			if fd.Message() == nil {
+				return true
+			}
+
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				if renameProjectRefs(list.Get(i).Message(), rename) {
+					changed = true
+				}
+			}
+
+		case fd.IsMap():
+			if fd.MapValue().Message() == nil {
+				return true
+			}
+
+			v.Map().Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+				if renameProjectRefs(v.Message(), rename) {
+					changed = true
+				}
+
+				return true
+			})
+
+		case fd.Message() != nil:
+			if renameProjectRefs(v.Message(), rename) {
+				changed = true
+			}
+		}
+
+		return true
+	})
+
+	return changed
+}
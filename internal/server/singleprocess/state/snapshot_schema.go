@@ -0,0 +1,109 @@
+package state
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/natefinch/atomic"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// The schema version of a snapshot is the version of the layout of the
+// records within it. This is separate from the protocol version since the
+// records may change without the API changing.
+//
+// When the records change in a way an older server can't read, increment
+// snapshotSchemaCurrent and add a migration from the previous version to
+// snapshotMigrations. Only increase snapshotSchemaMin if it is no longer
+// possible to migrate data from the older versions.
+const (
+	snapshotSchemaCurrent uint32 = 1
+	snapshotSchemaMin     uint32 = 1
+)
+
+// snapshotMigrations are the migrations of snapshot records, keyed by the
+// schema version they migrate from. Each migration modifies the chunk in
+// place so that its records are in the next schema version. There must be
+// a migration for every version from snapshotSchemaMin up to but not
+// including snapshotSchemaCurrent.
+var snapshotMigrations = map[uint32]func(*pb.Snapshot_BoltChunk) error{}
+
+// CheckSnapshotSchema returns an error if this server can't restore a
+// snapshot with the given schema version. A schema version of zero is a
+// snapshot created before schema versions were recorded. The version is
+// the version of Waypoint that created the snapshot, if known, and is only
+// used to make the error more helpful.
+func CheckSnapshotSchema(schema uint32, version string) error {
+	if schema == 0 {
+		schema = 1
+	}
+
+	from := "a different version of Waypoint"
+	if version != "" {
+		from = "Waypoint " + version
+	}
+
+	if schema > snapshotSchemaCurrent {
+		return status.Errorf(codes.FailedPrecondition,
+			"the snapshot was created by %s with data schema version %d, which is "+
+				"newer than this server supports (up to version %d). Upgrade the "+
+				"server to the same version or later before restoring this snapshot.",
+			from, schema, snapshotSchemaCurrent)
+	}
+	if schema < snapshotSchemaMin {
+		return status.Errorf(codes.FailedPrecondition,
+			"the snapshot was created by %s with data schema version %d, which is "+
+				"older than this server supports (version %d or later). Restore it "+
+				"to an older server and take a new snapshot to upgrade the data.",
+			from, schema, snapshotSchemaMin)
+	}
+
+	return nil
+}
+
+// snapshotSchema returns the schema version of the snapshot with header.
+func snapshotSchema(header *pb.Snapshot_Header) uint32 {
+	if header.SchemaVersion == 0 {
+		// Snapshots were created with the first schema before the schema
+		// version was recorded.
+		return 1
+	}
+
+	return header.SchemaVersion
+}
+
+// migrateSnapshot rewrites the snapshot at path with schema version from so
+// that its records are in the current schema version.
+func migrateSnapshot(path string, mode os.FileMode, from uint32) error {
+	var migrateErr error
+	migratePath := path + ".migrate"
+	err := rewriteSnapshot(migratePath, path, mode, func(header *pb.Snapshot_Header) {
+		header.SchemaVersion = snapshotSchemaCurrent
+	}, func(chunk *pb.Snapshot_BoltChunk) {
+		for v := from; v < snapshotSchemaCurrent && migrateErr == nil; v++ {
+			migrate, ok := snapshotMigrations[v]
+			if !ok {
+				migrateErr = fmt.Errorf("no migration from schema version %d", v)
+				break
+			}
+
+			if err := migrate(chunk); err != nil {
+				migrateErr = fmt.Errorf(
+					"error migrating %q records from schema version %d: %s",
+					chunk.Bucket, v, err)
+			}
+		}
+	})
+	if err == nil {
+		err = migrateErr
+	}
+	if err != nil {
+		os.Remove(migratePath)
+		return err
+	}
+
+	return atomic.ReplaceFile(migratePath, path)
+}
@@ -0,0 +1,412 @@
+package state
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/boltdb/bolt"
+	"github.com/golang/protobuf/proto"
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/waypoint/internal/pkg/protowriter"
+	"github.com/hashicorp/waypoint/internal/pkg/snapshotsum"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// WithRestoreProjects limits the restore to the records belonging to the
+// given projects. All other records on the server are kept as they are.
+func WithRestoreProjects(projects ...string) RestoreOption {
+	return func(c *restoreConfig) {
+		if c.scope == nil {
+			c.scope = newRestoreScope()
+		}
+
+		for _, p := range projects {
+			c.scope.projects[strings.ToLower(p)] = true
+		}
+	}
+}
+
+// WithRestoreApps limits the restore to the records belonging to the
+// given applications. All other records on the server are kept as they are.
+// This can be combined with WithRestoreProjects.
+func WithRestoreApps(refs ...*pb.Ref_Application) RestoreOption {
+	return func(c *restoreConfig) {
+		if c.scope == nil {
+			c.scope = newRestoreScope()
+		}
+
+		for _, ref := range refs {
+			p := strings.ToLower(ref.Project)
+			if c.scope.apps[p] == nil {
+				c.scope.apps[p] = map[string]bool{}
+			}
+
+			c.scope.apps[p][strings.ToLower(ref.Application)] = true
+		}
+	}
+}
+
+// restoreScope is the set of projects and applications that a partial
+// restore is limited to. Records outside of the scope are taken from the
+// current database rather than the snapshot.
+type restoreScope struct {
+	// projects are the lowercased names of projects whose records are all
+	// restored.
+	projects map[string]bool
+
+	// apps are the lowercased names of applications whose records are
+	// restored, keyed by lowercased project name.
+	apps map[string]map[string]bool
+
+	// skipTypes are record types that are never restored, so the current
+	// records of these types are always kept.
+	skipTypes map[string]bool
+}
+
+func newRestoreScope() *restoreScope {
+	return &restoreScope{
+		projects: map[string]bool{},
+		apps:     map[string]map[string]bool{},
+	}
+}
+
+// restoreScopeOps are the operations whose records are scoped by their
+// application.
+var restoreScopeOps = []*appOperation{artifactOp, buildOp, deploymentOp, releaseOp}
+
+// containsApp returns true if records for the application are restored.
+func (s *restoreScope) containsApp(ref *pb.Ref_Application) bool {
+	if ref == nil {
+		return false
+	}
+
+	p := strings.ToLower(ref.Project)
+	return s.projects[p] || s.apps[p][strings.ToLower(ref.Application)]
+}
+
+// contains returns true if the record with the given record type and value
+// should be restored from the snapshot. Records that don't belong to any
+// project, such as server configuration, are never restored. This must
+// not be called for project records; see mergeProject.
+func (s *restoreScope) contains(bucket string, v []byte) (bool, error) {
+	if s.skipTypes[bucket] {
+		return false, nil
+	}
+
+	var msg proto.Message
+	var app func() *pb.Ref_Application
+	switch bucket {
+	case string(configBucket):
+		var config pb.ConfigVar
+		msg = &config
+		app = func() *pb.Ref_Application {
+			switch scope := config.Scope.(type) {
+			case *pb.ConfigVar_Application:
+				return scope.Application
+
+			case *pb.ConfigVar_Project:
+				// Project-scoped config is only restored with the whole
+				// project. We use an empty app name since no app can match.
+				return &pb.Ref_Application{Project: scope.Project.Project}
+			}
+
+			return nil
+		}
+
+	case string(jobBucket):
+		var job pb.Job
+		msg = &job
+		app = func() *pb.Ref_Application { return job.Application }
+
+	default:
+		for _, op := range restoreScopeOps {
+			if bucket == string(op.Bucket) {
+				op, value := op, op.newStruct()
+				msg = value
+				app = func() *pb.Ref_Application {
+					ref, _ := op.valueField(value, "Application").(*pb.Ref_Application)
+					return ref
+				}
+
+				break
+			}
+		}
+	}
+	if msg == nil {
+		return false, nil
+	}
+
+	if err := proto.Unmarshal(v, msg); err != nil {
+		return false, fmt.Errorf("error decoding %s record: %s", bucket, err)
+	}
+
+	ref := app()
+	if ref != nil && ref.Application == "" {
+		return s.projects[strings.ToLower(ref.Project)], nil
+	}
+
+	return s.containsApp(ref), nil
+}
+
+// mergeProject returns the project record to restore given the current
+// record and the record in the snapshot, either of which may be nil. If
+// the whole project is restored, this is the snapshot record. Otherwise
+// the applications in scope are replaced in the current record. A nil
+// result means the project should not exist after the restore.
+func (s *restoreScope) mergeProject(current, incoming *pb.Project) *pb.Project {
+	var name string
+	switch {
+	case current != nil:
+		name = current.Name
+	case incoming != nil:
+		name = incoming.Name
+	default:
+		return nil
+	}
+
+	p := strings.ToLower(name)
+	if s.projects[p] {
+		return incoming
+	}
+	if len(s.apps[p]) == 0 {
+		return current
+	}
+
+	var result *pb.Project
+	if current != nil {
+		result = proto.Clone(current).(*pb.Project)
+	} else {
+		result = proto.Clone(incoming).(*pb.Project)
+	}
+
+	apps := result.Applications[:0]
+	for _, app := range result.Applications {
+		if !s.apps[p][strings.ToLower(app.Name)] {
+			apps = append(apps, app)
+		}
+	}
+	if incoming != nil {
+		for _, app := range incoming.Applications {
+			if s.apps[p][strings.ToLower(app.Name)] {
+				apps = append(apps, app)
+			}
+		}
+	}
+	result.Applications = apps
+
+	return result
+}
+
+// scopeSnapshot writes a snapshot to dst that contains the records in the
+// snapshot at src that are within scope, and the records in the current
+// database for everything else. Restoring dst then only changes the
+// records within scope. The checksum of src is verified.
+func (s *State) scopeSnapshot(
+	log hclog.Logger,
+	dst, src string,
+	mode os.FileMode,
+	scope *restoreScope,
+) error {
+	checksum := sha256.New()
+	sr, closer, err := snapshotReader(src, checksum)
+	if err != nil {
+		return err
+	}
+	defer closer()
+
+	f, err := os.OpenFile(dst, os.O_RDWR|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Same format as CreateSnapshot: the checksum is of the raw proto bytes.
+	newChecksum := sha256.New()
+	bw := bufio.NewWriter(f)
+	gzw := gzip.NewWriter(bw)
+	dw := protowriter.NewDelimitedWriter(io.MultiWriter(gzw, newChecksum))
+	writeChunk := func(bucket string, items map[string][]byte) error {
+		if len(items) == 0 {
+			return nil
+		}
+
+		return dw.WriteMsg(&pb.Snapshot_BoltChunk{
+			Bucket: bucket,
+			Items:  items,
+			Sha256: snapshotsum.Chunk(bucket, items, nil),
+		})
+	}
+
+	var header pb.Snapshot_Header
+	if err := sr.ReadMsg(&header); err != nil {
+		return fmt.Errorf("error reading restore header data: %s", err)
+	}
+	if header.Format != pb.Snapshot_Header_BOLT {
+		return fmt.Errorf("invalid snapshot format (got code: %d)", header.Format)
+	}
+	if err := dw.WriteMsg(&header); err != nil {
+		return err
+	}
+
+	// Copy the records within scope from the snapshot. We hold on to the
+	// project records since they need to be merged with the current ones.
+	incomingProjects := map[string]*pb.Project{}
+	restored := map[string]int{}
+	for {
+		var chunk pb.Snapshot_BoltChunk
+		if err := sr.ReadMsg(&chunk); err != nil {
+			// Note that EOF is also an error here since we expect a final chunk.
+			return err
+		}
+		if err := verifyChunk(&chunk); err != nil {
+			return err
+		}
+
+		if chunk.Final {
+			break
+		}
+
+		if chunk.Bucket == string(projectBucket) {
+			for k, v := range chunk.Items {
+				var p pb.Project
+				if err := proto.Unmarshal(v, &p); err != nil {
+					return fmt.Errorf("error decoding project record: %s", err)
+				}
+
+				incomingProjects[k] = &p
+			}
+
+			continue
+		}
+
+		items := map[string][]byte{}
+		for k, v := range chunk.Items {
+			ok, err := scope.contains(chunk.Bucket, v)
+			if err != nil {
+				return err
+			}
+			if ok {
+				items[k] = v
+			}
+		}
+
+		restored[chunk.Bucket] += len(items)
+		if err := writeChunk(chunk.Bucket, items); err != nil {
+			return err
+		}
+	}
+
+	if err := verifySnapshotTrailer(log, sr, checksum); err != nil {
+		return err
+	}
+
+	// Copy everything outside of the scope from the current data.
+	if err := s.db.View(func(dbTxn *bolt.Tx) error {
+		return dbTxn.ForEach(func(name []byte, b *bolt.Bucket) error {
+			bucket := string(name)
+			const chunkLenMax = 1024 * 1024 // 1 MB
+			chunkLen := 0
+			items := map[string][]byte{}
+
+			err := b.ForEach(func(k, v []byte) error {
+				if bucket == string(projectBucket) {
+					// Projects are merged below.
+					return nil
+				}
+
+				ok, err := scope.contains(bucket, v)
+				if err != nil || ok {
+					return err
+				}
+
+				if len(v)+chunkLen > chunkLenMax {
+					if err := writeChunk(bucket, items); err != nil {
+						return err
+					}
+
+					items = map[string][]byte{}
+					chunkLen = 0
+				}
+
+				// Bolt values are only valid for the life of the transaction
+				// but we write them before it ends so we don't need to copy.
+				chunkLen += len(v)
+				items[string(k)] = v
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			return writeChunk(bucket, items)
+		})
+	}); err != nil {
+		return err
+	}
+
+	// Merge the project records.
+	projects := map[string][]byte{}
+	merge := func(k string, current *pb.Project) error {
+		p := scope.mergeProject(current, incomingProjects[k])
+		delete(incomingProjects, k)
+		if p == nil {
+			return nil
+		}
+
+		v, err := proto.Marshal(p)
+		if err != nil {
+			return err
+		}
+
+		projects[k] = v
+		return nil
+	}
+	if err := s.db.View(func(dbTxn *bolt.Tx) error {
+		return dbTxn.Bucket(projectBucket).ForEach(func(k, v []byte) error {
+			var p pb.Project
+			if err := proto.Unmarshal(v, &p); err != nil {
+				return fmt.Errorf("error decoding project record: %s", err)
+			}
+
+			return merge(string(k), &p)
+		})
+	}); err != nil {
+		return err
+	}
+	for k := range incomingProjects {
+		if err := merge(k, nil); err != nil {
+			return err
+		}
+	}
+	if err := writeChunk(string(projectBucket), projects); err != nil {
+		return err
+	}
+
+	log.Info("limited restore to scope", "restored", restored)
+	if err := dw.WriteMsg(&pb.Snapshot_BoltChunk{Final: true}); err != nil {
+		return err
+	}
+	if err := dw.WriteMsg(&pb.Snapshot_Trailer{
+		Checksum: &pb.Snapshot_Trailer_Sha256{
+			Sha256: hex.EncodeToString(newChecksum.Sum(nil)),
+		},
+	}); err != nil {
+		return err
+	}
+
+	if err := gzw.Close(); err != nil {
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
@@ -1,14 +1,25 @@
 package state
 
 import (
+	"bufio"
 	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/hashicorp/waypoint/internal/pkg/protowriter"
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
 	serverptypes "github.com/hashicorp/waypoint/internal/server/ptypes"
 )
@@ -104,3 +115,564 @@ func TestSnapshotRestore_corrupt(t *testing.T) {
 	s, err := TestStateRestart(t, s)
 	require.NoError(err)
 }
+
+func TestSnapshotRestore_skipRecordTypes(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	// Create some data
+	err := s.ProjectPut(serverptypes.TestProject(t, &pb.Project{
+		Name: "A",
+	}))
+	require.NoError(err)
+	require.NoError(s.ConfigSet(&pb.ConfigVar{
+		Scope: &pb.ConfigVar_Project{
+			Project: &pb.Ref_Project{
+				Project: "A",
+			},
+		},
+
+		Name:  "foo",
+		Value: &pb.ConfigVar_Static{Static: "bar"},
+	}))
+
+	// Snapshot
+	var buf bytes.Buffer
+	require.NoError(s.CreateSnapshot(&buf))
+
+	// Unknown types are rejected
+	{
+		err := s.StageRestoreSnapshot(bytes.NewReader(buf.Bytes()),
+			WithRestoreSkipRecordTypes("nope"))
+		require.Error(err)
+		require.Equal(codes.InvalidArgument, status.Code(err))
+	}
+
+	// Restore without projects
+	require.NoError(s.StageRestoreSnapshot(bytes.NewReader(buf.Bytes()),
+		WithRestoreSkipRecordTypes(string(projectBucket))))
+
+	// Reboot!
+	s, err = TestStateRestart(t, s)
+	require.NoError(err)
+
+	// Should not find the project
+	{
+		_, err := s.ProjectGet(&pb.Ref_Project{
+			Project: "A",
+		})
+		require.Error(err)
+		require.Equal(codes.NotFound, status.Code(err))
+	}
+
+	// Should find the config
+	{
+		vs, err := s.ConfigGet(&pb.ConfigGetRequest{
+			Scope: &pb.ConfigGetRequest_Project{
+				Project: &pb.Ref_Project{Project: "A"},
+			},
+		})
+		require.NoError(err)
+		require.Len(vs, 1)
+	}
+}
+
+func TestSnapshotRestore_dryRun(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	// Create some data
+	err := s.ProjectPut(serverptypes.TestProject(t, &pb.Project{
+		Name: "A",
+	}))
+	require.NoError(err)
+
+	// Snapshot
+	var buf bytes.Buffer
+	require.NoError(s.CreateSnapshot(&buf))
+
+	// Create more data that isn't in the snapshot
+	err = s.ProjectPut(serverptypes.TestProject(t, &pb.Project{
+		Name: "B",
+	}))
+	require.NoError(err)
+
+	// Dry run with garbage data fails
+	require.Error(s.StageRestoreSnapshot(strings.NewReader(
+		"I am probably not a valid BoltDB file."), WithRestoreDryRun()))
+
+	// Dry run
+	require.NoError(s.StageRestoreSnapshot(
+		bytes.NewReader(buf.Bytes()), WithRestoreDryRun()))
+
+	// Nothing should be left behind
+	ri := newRestoreInfo(s.log, s.db)
+	for _, path := range []string{ri.StagePath, ri.StageTempPath, ri.StagePath + ".scratch"} {
+		_, err := os.Stat(path)
+		require.True(os.IsNotExist(err), path)
+	}
+
+	// Reboot!
+	s, err = TestStateRestart(t, s)
+	require.NoError(err)
+
+	// Should find both records since nothing was restored
+	for _, name := range []string{"A", "B"} {
+		resp, err := s.ProjectGet(&pb.Ref_Project{
+			Project: name,
+		})
+		require.NoError(err)
+		require.NotNil(resp)
+	}
+}
+
+func TestRestorePacer(t *testing.T) {
+	require := require.New(t)
+
+	// nil pacers don't limit
+	var p *restorePacer
+	require.Nil(newRestorePacer(0))
+	p.Wait(1000)
+
+	// 100 records at 1000 per second should take at least 100ms
+	p = newRestorePacer(1000)
+	start := time.Now()
+	p.Wait(50)
+	p.Wait(50)
+	require.True(time.Since(start) >= 100*time.Millisecond)
+}
+
+func TestSnapshotRestore_incremental(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	// Write a minimal incremental snapshot
+	var buf bytes.Buffer
+	checksum := sha256.New()
+	gzw := gzip.NewWriter(&buf)
+	dw := protowriter.NewDelimitedWriter(io.MultiWriter(gzw, checksum))
+	require.NoError(dw.WriteMsg(&pb.Snapshot_Header{
+		Format:   pb.Snapshot_Header_BOLT,
+		ParentId: "abc",
+	}))
+	require.NoError(dw.WriteMsg(&pb.Snapshot_BoltChunk{Final: true}))
+	require.NoError(dw.WriteMsg(&pb.Snapshot_Trailer{
+		Checksum: &pb.Snapshot_Trailer_Sha256{
+			Sha256: hex.EncodeToString(checksum.Sum(nil)),
+		},
+	}))
+	require.NoError(gzw.Close())
+
+	err := s.StageRestoreSnapshot(bytes.NewReader(buf.Bytes()))
+	require.Error(err)
+	require.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func TestSnapshotRestore_schema(t *testing.T) {
+	// writeSnapshot writes a snapshot with no records and the schema version.
+	writeSnapshot := func(t *testing.T, schema uint32) []byte {
+		var buf bytes.Buffer
+		checksum := sha256.New()
+		gzw := gzip.NewWriter(&buf)
+		dw := protowriter.NewDelimitedWriter(io.MultiWriter(gzw, checksum))
+		require.NoError(t, dw.WriteMsg(&pb.Snapshot_Header{
+			Version:       &pb.VersionInfo{Version: "v1.2.3"},
+			Format:        pb.Snapshot_Header_BOLT,
+			SchemaVersion: schema,
+		}))
+		require.NoError(t, dw.WriteMsg(&pb.Snapshot_BoltChunk{Final: true}))
+		require.NoError(t, dw.WriteMsg(&pb.Snapshot_Trailer{
+			Checksum: &pb.Snapshot_Trailer_Sha256{
+				Sha256: hex.EncodeToString(checksum.Sum(nil)),
+			},
+		}))
+		require.NoError(t, gzw.Close())
+		return buf.Bytes()
+	}
+
+	t.Run("newer schema", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+
+		err := s.StageRestoreSnapshot(bytes.NewReader(writeSnapshot(t, snapshotSchemaCurrent+1)))
+		require.Error(err)
+		require.Equal(codes.FailedPrecondition, status.Code(err))
+		require.Contains(err.Error(), "v1.2.3")
+	})
+
+	t.Run("no schema", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+
+		// Snapshots created before the schema version was recorded use
+		// the first schema.
+		require.NoError(s.StageRestoreSnapshot(bytes.NewReader(writeSnapshot(t, 0))))
+	})
+
+	t.Run("created", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+
+		var buf bytes.Buffer
+		require.NoError(s.CreateSnapshot(&buf))
+
+		gzr, err := gzip.NewReader(&buf)
+		require.NoError(err)
+		var header pb.Snapshot_Header
+		require.NoError(protowriter.NewDelimitedReader(bufio.NewReader(gzr), 4096).ReadMsg(&header))
+		require.Equal(snapshotSchemaCurrent, header.SchemaVersion)
+	})
+}
+
+func TestMigrateSnapshot(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	require.NoError(s.ProjectPut(serverptypes.TestProject(t, &pb.Project{
+		Name: "A",
+	})))
+
+	td, err := ioutil.TempDir("", "waypoint-migrate")
+	require.NoError(err)
+	defer os.RemoveAll(td)
+
+	path := filepath.Join(td, "snapshot")
+	f, err := os.Create(path)
+	require.NoError(err)
+	require.NoError(s.CreateSnapshot(f))
+	require.NoError(f.Close())
+
+	// Register a migration from a made up schema version that renames
+	// every project.
+	const from = snapshotSchemaCurrent - 1
+	snapshotMigrations[from] = func(chunk *pb.Snapshot_BoltChunk) error {
+		if chunk.Bucket != "project" {
+			return nil
+		}
+
+		for k, v := range chunk.Items {
+			var p pb.Project
+			if err := proto.Unmarshal(v, &p); err != nil {
+				return err
+			}
+
+			p.Name += "-migrated"
+			data, err := proto.Marshal(&p)
+			if err != nil {
+				return err
+			}
+			chunk.Items[k] = data
+		}
+
+		return nil
+	}
+	defer delete(snapshotMigrations, from)
+
+	require.NoError(migrateSnapshot(path, 0600, from))
+
+	header, err := verifySnapshot(path)
+	require.NoError(err)
+	require.Equal(snapshotSchemaCurrent, header.SchemaVersion)
+
+	// Restore the migrated data to check the migration was applied
+	f, err = os.Open(path)
+	require.NoError(err)
+	defer f.Close()
+	require.NoError(s.StageRestoreSnapshot(f))
+
+	s, err = TestStateRestart(t, s)
+	require.NoError(err)
+	defer s.Close()
+
+	p, err := s.ProjectGet(&pb.Ref_Project{Project: "A"})
+	require.NoError(err)
+	require.Equal("A-migrated", p.Name)
+}
+
+func TestSnapshotRestore_truncated(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	require.NoError(s.ProjectPut(serverptypes.TestProject(t, &pb.Project{
+		Name: "A",
+	})))
+
+	var buf bytes.Buffer
+	require.NoError(s.CreateSnapshot(&buf))
+
+	// Truncate the raw snapshot data but keep the compression valid so
+	// that only our checks can detect the problem.
+	gzr, err := gzip.NewReader(&buf)
+	require.NoError(err)
+	raw, err := ioutil.ReadAll(gzr)
+	require.NoError(err)
+
+	var truncated bytes.Buffer
+	gzw := gzip.NewWriter(&truncated)
+	_, err = gzw.Write(raw[:len(raw)/2])
+	require.NoError(err)
+	require.NoError(gzw.Close())
+
+	err = s.StageRestoreSnapshot(&truncated)
+	require.Error(err)
+	require.Equal(codes.InvalidArgument, status.Code(err))
+
+	// Nothing should be restored on restart.
+	s, err = TestStateRestart(t, s)
+	require.NoError(err)
+	_, err = s.ProjectGet(&pb.Ref_Project{Project: "A"})
+	require.NoError(err)
+}
+
+func TestSnapshotRestore_chunkChecksum(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	// Write a snapshot with a valid trailer but a bad chunk checksum.
+	var buf bytes.Buffer
+	checksum := sha256.New()
+	gzw := gzip.NewWriter(&buf)
+	dw := protowriter.NewDelimitedWriter(io.MultiWriter(gzw, checksum))
+	require.NoError(dw.WriteMsg(&pb.Snapshot_Header{
+		Format: pb.Snapshot_Header_BOLT,
+	}))
+	require.NoError(dw.WriteMsg(&pb.Snapshot_BoltChunk{
+		Bucket: "project",
+		Items:  map[string][]byte{"a": []byte("data")},
+		Sha256: "0000",
+	}))
+	require.NoError(dw.WriteMsg(&pb.Snapshot_BoltChunk{Final: true}))
+	require.NoError(dw.WriteMsg(&pb.Snapshot_Trailer{
+		Checksum: &pb.Snapshot_Trailer_Sha256{
+			Sha256: hex.EncodeToString(checksum.Sum(nil)),
+		},
+	}))
+	require.NoError(gzw.Close())
+
+	err := s.StageRestoreSnapshot(bytes.NewReader(buf.Bytes()))
+	require.Error(err)
+	require.Contains(err.Error(), "chunk checksum mismatch")
+}
+
+func TestSnapshotRestore_scope(t *testing.T) {
+	build := func(t *testing.T, s *State, id, project, app string) {
+		require.NoError(t, s.BuildPut(false, serverptypes.TestValidBuild(t, &pb.Build{
+			Id: id,
+			Application: &pb.Ref_Application{
+				Project:     project,
+				Application: app,
+			},
+		})))
+	}
+
+	// setup creates a state with some builds, snapshots it, and then
+	// creates more builds that aren't in the snapshot.
+	setup := func(t *testing.T) (*State, []byte) {
+		s := TestState(t)
+		build(t, s, "A1", "A", "web")
+		build(t, s, "B1", "B", "web")
+
+		var buf bytes.Buffer
+		require.NoError(t, s.CreateSnapshot(&buf))
+
+		build(t, s, "A2", "A", "web")
+		build(t, s, "A3", "A", "api")
+		build(t, s, "B2", "B", "web")
+		return s, buf.Bytes()
+	}
+
+	exists := func(t *testing.T, s *State, id string) bool {
+		_, err := s.BuildGet(appOpById(id))
+		if status.Code(err) == codes.NotFound {
+			return false
+		}
+
+		require.NoError(t, err)
+		return true
+	}
+
+	t.Run("project", func(t *testing.T) {
+		require := require.New(t)
+
+		s, snapshot := setup(t)
+		defer s.Close()
+
+		require.NoError(s.StageRestoreSnapshot(bytes.NewReader(snapshot),
+			WithRestoreProjects("a")))
+		s, err := TestStateRestart(t, s)
+		require.NoError(err)
+
+		// Project A is restored, including removing the app created
+		// after the snapshot.
+		require.True(exists(t, s, "A1"))
+		require.False(exists(t, s, "A2"))
+		require.False(exists(t, s, "A3"))
+		p, err := s.ProjectGet(&pb.Ref_Project{Project: "A"})
+		require.NoError(err)
+		require.Len(p.Applications, 1)
+
+		// Project B is untouched.
+		require.True(exists(t, s, "B1"))
+		require.True(exists(t, s, "B2"))
+	})
+
+	t.Run("app", func(t *testing.T) {
+		require := require.New(t)
+
+		s, snapshot := setup(t)
+		defer s.Close()
+
+		require.NoError(s.StageRestoreSnapshot(bytes.NewReader(snapshot),
+			WithRestoreApps(&pb.Ref_Application{Project: "A", Application: "web"})))
+		s, err := TestStateRestart(t, s)
+		require.NoError(err)
+
+		// Only the web app is restored.
+		require.True(exists(t, s, "A1"))
+		require.False(exists(t, s, "A2"))
+		require.True(exists(t, s, "A3"))
+		p, err := s.ProjectGet(&pb.Ref_Project{Project: "A"})
+		require.NoError(err)
+		require.Len(p.Applications, 2)
+
+		require.True(exists(t, s, "B1"))
+		require.True(exists(t, s, "B2"))
+	})
+}
+
+func TestSnapshotRestore_renameProject(t *testing.T) {
+	// setup creates a state with a project that has a build and config,
+	// and returns a snapshot of it.
+	setup := func(t *testing.T, project, buildId string) (*State, []byte) {
+		s := TestState(t)
+		require.NoError(t, s.BuildPut(false, serverptypes.TestValidBuild(t, &pb.Build{
+			Id: buildId,
+			Application: &pb.Ref_Application{
+				Project:     project,
+				Application: "web",
+			},
+		})))
+		require.NoError(t, s.ConfigSet(&pb.ConfigVar{
+			Scope: &pb.ConfigVar_Project{
+				Project: &pb.Ref_Project{Project: project},
+			},
+			Name:  "foo",
+			Value: &pb.ConfigVar_Static{Static: buildId},
+		}))
+
+		var buf bytes.Buffer
+		require.NoError(t, s.CreateSnapshot(&buf))
+		return s, buf.Bytes()
+	}
+
+	t.Run("restored alongside", func(t *testing.T) {
+		require := require.New(t)
+
+		src, snapshot := setup(t, "A", "A1")
+		defer src.Close()
+		s, _ := setup(t, "A", "X1")
+		defer s.Close()
+
+		require.NoError(s.StageRestoreSnapshot(bytes.NewReader(snapshot),
+			WithRestoreRenameProjects(map[string]string{"A": "B"})))
+		s, err := TestStateRestart(t, s)
+		require.NoError(err)
+
+		// Project A is untouched
+		p, err := s.ProjectGet(&pb.Ref_Project{Project: "A"})
+		require.NoError(err)
+		require.Len(p.Applications, 1)
+		b, err := s.BuildGet(appOpById("X1"))
+		require.NoError(err)
+		require.Equal("A", b.Application.Project)
+
+		// Project B has the data from the snapshot
+		p, err = s.ProjectGet(&pb.Ref_Project{Project: "B"})
+		require.NoError(err)
+		require.Equal("B", p.Name)
+		require.Len(p.Applications, 1)
+		require.Equal("B", p.Applications[0].Project.Project)
+		b, err = s.BuildGet(appOpById("A1"))
+		require.NoError(err)
+		require.Equal("B", b.Application.Project)
+
+		vs, err := s.ConfigGet(&pb.ConfigGetRequest{
+			Scope: &pb.ConfigGetRequest_Project{
+				Project: &pb.Ref_Project{Project: "B"},
+			},
+		})
+		require.NoError(err)
+		require.Len(vs, 1)
+		require.Equal("A1", vs[0].Value.(*pb.ConfigVar_Static).Static)
+	})
+
+	t.Run("name exists", func(t *testing.T) {
+		require := require.New(t)
+
+		src, snapshot := setup(t, "A", "A1")
+		defer src.Close()
+		s, _ := setup(t, "B", "B1")
+		defer s.Close()
+
+		err := s.StageRestoreSnapshot(bytes.NewReader(snapshot),
+			WithRestoreRenameProjects(map[string]string{"A": "B"}))
+		require.Error(err)
+		require.Equal(codes.AlreadyExists, status.Code(err))
+	})
+
+	t.Run("record exists", func(t *testing.T) {
+		require := require.New(t)
+
+		// Restoring to the same server keeps the build IDs, which collide
+		s, snapshot := setup(t, "A", "A1")
+		defer s.Close()
+
+		err := s.StageRestoreSnapshot(bytes.NewReader(snapshot),
+			WithRestoreRenameProjects(map[string]string{"A": "B"}))
+		require.Error(err)
+		require.Equal(codes.AlreadyExists, status.Code(err))
+	})
+
+	t.Run("not in snapshot", func(t *testing.T) {
+		require := require.New(t)
+
+		s, snapshot := setup(t, "A", "A1")
+		defer s.Close()
+
+		err := s.StageRestoreSnapshot(bytes.NewReader(snapshot),
+			WithRestoreRenameProjects(map[string]string{"nope": "B"}))
+		require.Error(err)
+		require.Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("also scoped by old name", func(t *testing.T) {
+		require := require.New(t)
+
+		s, snapshot := setup(t, "A", "A1")
+		defer s.Close()
+
+		err := s.StageRestoreSnapshot(bytes.NewReader(snapshot),
+			WithRestoreProjects("A"),
+			WithRestoreRenameProjects(map[string]string{"A": "B"}))
+		require.Error(err)
+		require.Equal(codes.InvalidArgument, status.Code(err))
+	})
+}
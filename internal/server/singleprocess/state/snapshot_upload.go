@@ -0,0 +1,280 @@
+package state
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/gofrs/flock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// restoreUploadPrefix and restoreUploadSuffix surround the ID in the
+	// file name of the data of a resumable restore. The lock file of the
+	// upload has restoreUploadLockSuffix appended to that.
+	restoreUploadPrefix     = "waypoint-restore-upload-"
+	restoreUploadSuffix     = ".partial"
+	restoreUploadLockSuffix = ".lock"
+)
+
+// restoreUploadIdRe is the format of the ID of a resumable restore upload.
+// This is restrictive since the ID is part of a file name.
+var restoreUploadIdRe = regexp.MustCompile(`^[A-Za-z0-9_-]{1,128}$`)
+
+// RestoreUpload is the data of a resumable restore that has been received
+// so far. The data is stored alongside the database so that if the upload
+// is interrupted, it can be resumed rather than starting over.
+//
+// Only one RestoreUpload can be open for an ID at a time. Close must be
+// called when done with it.
+type RestoreUpload struct {
+	f  *os.File
+	fl *flock.Flock
+}
+
+// OpenRestoreUpload opens the stored data for the resumable restore with the
+// given ID so that more data can be written to it starting at offset. Any
+// data already stored past offset is discarded. It is an error if offset is
+// greater than the amount of data stored. An offset of zero always starts
+// a new upload.
+func (s *State) OpenRestoreUpload(id string, offset int64) (*RestoreUpload, error) {
+	path, err := s.restoreUploadPath(id)
+	if err != nil {
+		return nil, err
+	}
+
+	fl := flock.New(path + restoreUploadLockSuffix)
+	locked, err := fl.TryLock()
+	if err != nil {
+		return nil, err
+	}
+	if !locked {
+		return nil, status.Errorf(codes.Aborted,
+			"restore %q is already being uploaded", id)
+	}
+
+	u, err := openRestoreUpload(path, offset)
+	if err != nil {
+		fl.Unlock()
+		return nil, err
+	}
+	u.fl = fl
+
+	return u, nil
+}
+
+func openRestoreUpload(path string, offset int64) (*RestoreUpload, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if offset > fi.Size() {
+		f.Close()
+		return nil, status.Errorf(codes.OutOfRange,
+			"offset %d is past the %d bytes received for the restore", offset, fi.Size())
+	}
+
+	// Discard anything after the offset since it is about to be resent.
+	if err := f.Truncate(offset); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &RestoreUpload{f: f}, nil
+}
+
+// Write appends data to the upload.
+func (u *RestoreUpload) Write(p []byte) (int, error) {
+	return u.f.Write(p)
+}
+
+// Reader returns a reader for all of the data in the upload. This must not
+// be used at the same time as Write.
+func (u *RestoreUpload) Reader() (io.Reader, error) {
+	if err := u.f.Sync(); err != nil {
+		return nil, err
+	}
+
+	if _, err := u.f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	return u.f, nil
+}
+
+// Close closes the upload, keeping the data so that it can be resumed.
+func (u *RestoreUpload) Close() error {
+	defer u.fl.Unlock()
+
+	if err := u.f.Sync(); err != nil {
+		u.f.Close()
+		return err
+	}
+
+	return u.f.Close()
+}
+
+// Delete closes the upload and deletes its data. This should be called
+// once the restore has completed.
+func (u *RestoreUpload) Delete() error {
+	defer u.fl.Unlock()
+
+	u.f.Close()
+	return removeRestoreUpload(u.f.Name())
+}
+
+// ExpireRestoreUploads deletes the data of resumable restores that haven't
+// been written to for maxAge as of now. These are restores that the client
+// abandoned rather than resumed. Uploads that are open are never deleted.
+// This returns the number of uploads deleted.
+func (s *State) ExpireRestoreUploads(maxAge time.Duration, now time.Time) (int, error) {
+	dir := filepath.Dir(s.db.Path())
+	paths, err := filepath.Glob(filepath.Join(dir, restoreUploadPrefix+"*"+restoreUploadSuffix))
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, path := range paths {
+		expired, err := restoreUploadExpired(path, maxAge, now)
+		if err != nil {
+			return count, err
+		}
+		if !expired {
+			continue
+		}
+
+		fl := flock.New(path + restoreUploadLockSuffix)
+		locked, err := fl.TryLock()
+		if err != nil {
+			return count, err
+		}
+		if !locked {
+			// The restore is being uploaded right now.
+			continue
+		}
+
+		err = removeRestoreUpload(path)
+		fl.Unlock()
+		if err != nil {
+			return count, err
+		}
+
+		count++
+	}
+
+	// Lock files are left behind without their data by older versions, and
+	// if the server exits while opening an upload.
+	locks, err := filepath.Glob(filepath.Join(dir,
+		restoreUploadPrefix+"*"+restoreUploadSuffix+restoreUploadLockSuffix))
+	if err != nil {
+		return count, err
+	}
+	for _, lock := range locks {
+		path := lock[:len(lock)-len(restoreUploadLockSuffix)]
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			continue
+		}
+
+		expired, err := restoreUploadExpired(lock, maxAge, now)
+		if err != nil {
+			return count, err
+		}
+		if !expired {
+			continue
+		}
+
+		fl := flock.New(lock)
+		locked, err := fl.TryLock()
+		if err != nil {
+			return count, err
+		}
+		if locked {
+			err = os.Remove(lock)
+			fl.Unlock()
+			if err != nil && !os.IsNotExist(err) {
+				return count, err
+			}
+		}
+	}
+
+	return count, nil
+}
+
+// restoreUploadExpired returns true if the file at path hasn't been
+// modified for maxAge as of now. A file that no longer exists isn't
+// expired since there is nothing to delete.
+func restoreUploadExpired(path string, maxAge time.Duration, now time.Time) (bool, error) {
+	fi, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return now.Sub(fi.ModTime()) >= maxAge, nil
+}
+
+// removeRestoreUpload deletes the data of the upload at path and its lock
+// file. The lock must be held.
+func removeRestoreUpload(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	err := os.Remove(path + restoreUploadLockSuffix)
+	if os.IsNotExist(err) {
+		err = nil
+	}
+
+	return err
+}
+
+// RestoreUploadOffset returns the number of bytes stored for the resumable
+// restore with the given ID and when data was last stored for it. This
+// returns a NotFound error if there is no upload with the ID, which is the
+// case once the restore has completed.
+func (s *State) RestoreUploadOffset(id string) (int64, time.Time, error) {
+	path, err := s.restoreUploadPath(id)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	fi, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return 0, time.Time{}, status.Errorf(codes.NotFound,
+			"no data was found for restore %q", id)
+	}
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	return fi.Size(), fi.ModTime(), nil
+}
+
+// restoreUploadPath returns the path to store the data of the resumable
+// restore with the given ID.
+func (s *State) restoreUploadPath(id string) (string, error) {
+	if !restoreUploadIdRe.MatchString(id) {
+		return "", status.Errorf(codes.InvalidArgument,
+			"resume ID %q may only contain letters, digits, '-' and '_'", id)
+	}
+
+	dir := filepath.Dir(s.db.Path())
+	return filepath.Join(dir, restoreUploadPrefix+id+restoreUploadSuffix), nil
+}
@@ -0,0 +1,171 @@
+package state
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	serverptypes "github.com/hashicorp/waypoint/internal/server/ptypes"
+)
+
+func TestRestoreUpload(t *testing.T) {
+	t.Run("resume", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+
+		// Create some data and snapshot it
+		require.NoError(s.ProjectPut(serverptypes.TestProject(t, &pb.Project{
+			Name: "A",
+		})))
+		var buf bytes.Buffer
+		require.NoError(s.CreateSnapshot(&buf))
+		data := buf.Bytes()
+		half := len(data) / 2
+
+		// No upload yet
+		_, _, err := s.RestoreUploadOffset("test")
+		require.Error(err)
+		require.Equal(codes.NotFound, status.Code(err))
+
+		// Upload the first half plus some garbage that is "lost"
+		u, err := s.OpenRestoreUpload("test", 0)
+		require.NoError(err)
+		_, err = u.Write(data[:half])
+		require.NoError(err)
+		_, err = u.Write([]byte("garbage"))
+		require.NoError(err)
+		require.NoError(u.Close())
+
+		offset, _, err := s.RestoreUploadOffset("test")
+		require.NoError(err)
+		require.Equal(int64(half+len("garbage")), offset)
+
+		// Can't resume past what we have
+		_, err = s.OpenRestoreUpload("test", offset+1)
+		require.Error(err)
+		require.Equal(codes.OutOfRange, status.Code(err))
+
+		// Resume from the end of the first half
+		u, err = s.OpenRestoreUpload("test", int64(half))
+		require.NoError(err)
+		_, err = u.Write(data[half:])
+		require.NoError(err)
+
+		r, err := u.Reader()
+		require.NoError(err)
+		actual, err := ioutil.ReadAll(r)
+		require.NoError(err)
+		require.Equal(data, actual)
+
+		// Deleting removes the data and the lock file
+		require.NoError(u.Delete())
+		_, _, err = s.RestoreUploadOffset("test")
+		require.Error(err)
+		require.Equal(codes.NotFound, status.Code(err))
+		path, err := s.restoreUploadPath("test")
+		require.NoError(err)
+		_, err = os.Stat(path + restoreUploadLockSuffix)
+		require.True(os.IsNotExist(err))
+	})
+
+	t.Run("expire", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+
+		now := time.Now()
+		upload := func(id string, age time.Duration) *RestoreUpload {
+			u, err := s.OpenRestoreUpload(id, 0)
+			require.NoError(err)
+			_, err = u.Write([]byte("data"))
+			require.NoError(err)
+
+			path, err := s.restoreUploadPath(id)
+			require.NoError(err)
+			require.NoError(os.Chtimes(path, now.Add(-age), now.Add(-age)))
+			return u
+		}
+
+		// An abandoned upload, a recent one, and one still being uploaded
+		require.NoError(upload("old", 2*time.Hour).Close())
+		require.NoError(upload("new", time.Minute).Close())
+		busy := upload("busy", 2*time.Hour)
+		defer busy.Delete()
+
+		count, err := s.ExpireRestoreUploads(time.Hour, now)
+		require.NoError(err)
+		require.Equal(1, count)
+
+		_, _, err = s.RestoreUploadOffset("old")
+		require.Error(err)
+		require.Equal(codes.NotFound, status.Code(err))
+		path, err := s.restoreUploadPath("old")
+		require.NoError(err)
+		_, err = os.Stat(path + restoreUploadLockSuffix)
+		require.True(os.IsNotExist(err))
+
+		for _, id := range []string{"new", "busy"} {
+			offset, _, err := s.RestoreUploadOffset(id)
+			require.NoError(err)
+			require.Equal(int64(len("data")), offset)
+		}
+
+		// The time data was last stored is reported
+		_, updated, err := s.RestoreUploadOffset("new")
+		require.NoError(err)
+		require.WithinDuration(now.Add(-time.Minute), updated, time.Second)
+
+		// A lock file left without its data is removed once it expires
+		path, err = s.restoreUploadPath("orphan")
+		require.NoError(err)
+		lock := path + restoreUploadLockSuffix
+		require.NoError(ioutil.WriteFile(lock, nil, 0600))
+		count, err = s.ExpireRestoreUploads(time.Hour, now)
+		require.NoError(err)
+		require.Equal(0, count)
+		_, err = os.Stat(lock)
+		require.NoError(err)
+
+		require.NoError(os.Chtimes(lock, now.Add(-2*time.Hour), now.Add(-2*time.Hour)))
+		_, err = s.ExpireRestoreUploads(time.Hour, now)
+		require.NoError(err)
+		_, err = os.Stat(lock)
+		require.True(os.IsNotExist(err))
+	})
+
+	t.Run("locked", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+
+		u, err := s.OpenRestoreUpload("test", 0)
+		require.NoError(err)
+		defer u.Delete()
+
+		_, err = s.OpenRestoreUpload("test", 0)
+		require.Error(err)
+		require.Equal(codes.Aborted, status.Code(err))
+	})
+
+	t.Run("invalid ID", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+
+		_, err := s.OpenRestoreUpload("../nope", 0)
+		require.Error(err)
+		require.Equal(codes.InvalidArgument, status.Code(err))
+	})
+}
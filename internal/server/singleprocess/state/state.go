@@ -5,6 +5,8 @@ package state
 import (
 	"fmt"
 	"reflect"
+	"sync"
+	"time"
 
 	"github.com/boltdb/bolt"
 	"github.com/hashicorp/go-hclog"
@@ -50,6 +52,12 @@ type State struct {
 	// initialized during New and set to nil at the end of New.
 	indexers map[uintptr]struct{}
 
+	// jobAckTimeout and jobHeartbeatTimeout override the default job
+	// timeouts if they're set. jobTimeoutLock must be held to use them.
+	jobAckTimeout       time.Duration
+	jobHeartbeatTimeout time.Duration
+	jobTimeoutLock      sync.RWMutex
+
 	// Where to log to
 	log hclog.Logger
 }
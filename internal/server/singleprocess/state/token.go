@@ -0,0 +1,166 @@
+package state
+
+import (
+	"sort"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/golang/protobuf/proto"
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+var tokenBucket = []byte("tokens")
+
+func init() {
+	dbBuckets = append(dbBuckets, tokenBucket)
+}
+
+// TokenPut creates or updates the record of a token.
+func (s *State) TokenPut(info *pb.TokenInfo) error {
+	if info.Id == "" {
+		return status.Errorf(codes.InvalidArgument, "token ID is required")
+	}
+
+	return s.db.Update(func(dbTxn *bolt.Tx) error {
+		return dbPut(dbTxn.Bucket(tokenBucket), []byte(info.Id), info)
+	})
+}
+
+// TokenGet gets the record of a token by ID. This will return a nil value
+// if it doesn't exist.
+func (s *State) TokenGet(id string) (*pb.TokenInfo, error) {
+	var result *pb.TokenInfo
+	err := s.db.View(func(dbTxn *bolt.Tx) error {
+		data := dbTxn.Bucket(tokenBucket).Get([]byte(id))
+		if data == nil {
+			return nil
+		}
+
+		result = &pb.TokenInfo{}
+		return proto.Unmarshal(data, result)
+	})
+
+	return result, err
+}
+
+// TokenList returns the records of every token, oldest first.
+func (s *State) TokenList() ([]*pb.TokenInfo, error) {
+	var result []*pb.TokenInfo
+	err := s.db.View(func(dbTxn *bolt.Tx) error {
+		return dbTxn.Bucket(tokenBucket).ForEach(func(k, v []byte) error {
+			var info pb.TokenInfo
+			if err := proto.Unmarshal(v, &info); err != nil {
+				return err
+			}
+
+			result = append(result, &info)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortTokens(result)
+	return result, nil
+}
+
+// TokenPrune deletes the records of tokens that expired before the given
+// time. The tokens would be rejected anyway, so this only removes them
+// from the list.
+func (s *State) TokenPrune(before time.Time) (int, error) {
+	count := 0
+	err := s.db.Update(func(dbTxn *bolt.Tx) error {
+		b := dbTxn.Bucket(tokenBucket)
+
+		var expired [][]byte
+		err := b.ForEach(func(k, v []byte) error {
+			var info pb.TokenInfo
+			if err := proto.Unmarshal(v, &info); err != nil {
+				return err
+			}
+
+			if vu := info.ValidUntil; vu != nil && time.Unix(vu.Seconds, int64(vu.Nanos)).Before(before) {
+				expired = append(expired, k)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, k := range expired {
+			if err := b.Delete(k); err != nil {
+				return err
+			}
+		}
+
+		count = len(expired)
+		return nil
+	})
+
+	return count, err
+}
+
+// sortTokens sorts tokens by when they were created. Tokens revoked
+// without a record have no creation time and sort first.
+func sortTokens(tokens []*pb.TokenInfo) {
+	created := func(i int) time.Time {
+		if ts := tokens[i].CreatedAt; ts != nil {
+			return time.Unix(ts.Seconds, int64(ts.Nanos))
+		}
+
+		return time.Time{}
+	}
+
+	sort.SliceStable(tokens, func(i, j int) bool {
+		return created(i).Before(created(j))
+	})
+}
+
+// keepTokenRevocations copies the records of revoked tokens from db into
+// the bolt database at path, which is being restored. A restore would
+// otherwise bring back tokens that were revoked after the snapshot.
+func keepTokenRevocations(log hclog.Logger, db *bolt.DB, path string) error {
+	restoreDb, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 2 * time.Second})
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	err = db.View(func(dbTxn *bolt.Tx) error {
+		src := dbTxn.Bucket(tokenBucket)
+
+		return restoreDb.Update(func(restoreTxn *bolt.Tx) error {
+			dst, err := restoreTxn.CreateBucketIfNotExists(tokenBucket)
+			if err != nil {
+				return err
+			}
+
+			return src.ForEach(func(k, v []byte) error {
+				var info pb.TokenInfo
+				if err := proto.Unmarshal(v, &info); err != nil {
+					return err
+				}
+				if info.RevokedAt == nil {
+					return nil
+				}
+
+				count++
+				return dst.Put(k, v)
+			})
+		})
+	})
+	if err != nil {
+		restoreDb.Close()
+		return err
+	}
+
+	log.Info("kept existing token revocations", "count", count)
+	return restoreDb.Close()
+}
@@ -0,0 +1,101 @@
+package state
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func TestToken(t *testing.T) {
+	t.Run("Put, Get, and List", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+
+		// Get returns nil if it doesn't exist
+		info, err := s.TokenGet("a")
+		require.NoError(err)
+		require.Nil(info)
+
+		// Put out of order
+		now := time.Now()
+		newer, err := ptypes.TimestampProto(now)
+		require.NoError(err)
+		older, err := ptypes.TimestampProto(now.Add(-time.Hour))
+		require.NoError(err)
+		require.NoError(s.TokenPut(&pb.TokenInfo{Id: "a", User: "alice", CreatedAt: newer}))
+		require.NoError(s.TokenPut(&pb.TokenInfo{Id: "b", User: "bob", CreatedAt: older}))
+
+		info, err = s.TokenGet("a")
+		require.NoError(err)
+		require.NotNil(info)
+		require.Equal("alice", info.User)
+
+		// Oldest first
+		list, err := s.TokenList()
+		require.NoError(err)
+		require.Len(list, 2)
+		require.Equal("b", list[0].Id)
+		require.Equal("a", list[1].Id)
+
+		// IDs are required
+		err = s.TokenPut(&pb.TokenInfo{User: "mallory"})
+		require.Error(err)
+		require.Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("Prune", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+
+		expired, err := ptypes.TimestampProto(time.Now().Add(-time.Minute))
+		require.NoError(err)
+		require.NoError(s.TokenPut(&pb.TokenInfo{Id: "a", ValidUntil: expired}))
+		require.NoError(s.TokenPut(&pb.TokenInfo{Id: "b"}))
+
+		count, err := s.TokenPrune(time.Now())
+		require.NoError(err)
+		require.Equal(1, count)
+
+		list, err := s.TokenList()
+		require.NoError(err)
+		require.Len(list, 1)
+		require.Equal("b", list[0].Id)
+	})
+
+	t.Run("revocations are kept on restore", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+
+		// Snapshot with a token
+		require.NoError(s.TokenPut(&pb.TokenInfo{Id: "a"}))
+		var buf bytes.Buffer
+		require.NoError(s.CreateSnapshot(&buf))
+
+		// Revoke it after the snapshot
+		require.NoError(s.TokenPut(&pb.TokenInfo{Id: "a", RevokedAt: ptypes.TimestampNow()}))
+
+		// Restore
+		require.NoError(s.StageRestoreSnapshot(bytes.NewReader(buf.Bytes())))
+		s, err := TestStateRestart(t, s)
+		require.NoError(err)
+
+		// It should still be revoked
+		info, err := s.TokenGet("a")
+		require.NoError(err)
+		require.NotNil(info)
+		require.NotNil(info.RevokedAt)
+	})
+}
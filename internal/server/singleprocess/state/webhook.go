@@ -0,0 +1,69 @@
+package state
+
+import (
+	"strings"
+
+	"github.com/boltdb/bolt"
+	"github.com/golang/protobuf/proto"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+var webhookBucket = []byte("webhook")
+
+func init() {
+	dbBuckets = append(dbBuckets, webhookBucket)
+}
+
+// WebhookPut creates or updates a webhook. If update is true, the webhook
+// must already exist.
+func (s *State) WebhookPut(update bool, hook *pb.Webhook) error {
+	return s.db.Update(func(dbTxn *bolt.Tx) error {
+		return dbUpsert(dbTxn.Bucket(webhookBucket), update, []byte(hook.Id), hook)
+	})
+}
+
+// WebhookGet gets a webhook by ID.
+func (s *State) WebhookGet(id string) (*pb.Webhook, error) {
+	var result pb.Webhook
+	err := s.db.View(func(dbTxn *bolt.Tx) error {
+		return dbGet(dbTxn.Bucket(webhookBucket), []byte(id), &result)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// WebhookList returns the webhooks of a project. If ref is nil, the
+// webhooks of every project are returned.
+func (s *State) WebhookList(ref *pb.Ref_Project) ([]*pb.Webhook, error) {
+	var result []*pb.Webhook
+	err := s.db.View(func(dbTxn *bolt.Tx) error {
+		return dbTxn.Bucket(webhookBucket).ForEach(func(k, v []byte) error {
+			var hook pb.Webhook
+			if err := proto.Unmarshal(v, &hook); err != nil {
+				return err
+			}
+
+			// Project names are case insensitive.
+			if ref != nil && !strings.EqualFold(hook.Project.GetProject(), ref.Project) {
+				return nil
+			}
+
+			result = append(result, &hook)
+			return nil
+		})
+	})
+
+	return result, err
+}
+
+// WebhookDelete deletes a webhook by ID. Deleting a webhook that doesn't
+// exist is not an error.
+func (s *State) WebhookDelete(id string) error {
+	return s.db.Update(func(dbTxn *bolt.Tx) error {
+		return dbTxn.Bucket(webhookBucket).Delete([]byte(id))
+	})
+}
@@ -0,0 +1,71 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func TestWebhook(t *testing.T) {
+	t.Run("Put, Get, List, and Delete", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+
+		// Get returns NotFound if it doesn't exist
+		_, err := s.WebhookGet("a")
+		require.Error(err)
+		require.Equal(codes.NotFound, status.Code(err))
+
+		require.NoError(s.WebhookPut(false, &pb.Webhook{
+			Id:      "a",
+			Project: &pb.Ref_Project{Project: "foo"},
+			Url:     "https://example.com/a",
+		}))
+		require.NoError(s.WebhookPut(false, &pb.Webhook{
+			Id:      "b",
+			Project: &pb.Ref_Project{Project: "bar"},
+			Url:     "https://example.com/b",
+		}))
+
+		hook, err := s.WebhookGet("a")
+		require.NoError(err)
+		require.Equal("https://example.com/a", hook.Url)
+
+		// List all
+		list, err := s.WebhookList(nil)
+		require.NoError(err)
+		require.Len(list, 2)
+
+		// List by project, which is case insensitive
+		list, err = s.WebhookList(&pb.Ref_Project{Project: "FOO"})
+		require.NoError(err)
+		require.Len(list, 1)
+		require.Equal("a", list[0].Id)
+
+		// Delete
+		require.NoError(s.WebhookDelete("a"))
+		_, err = s.WebhookGet("a")
+		require.Error(err)
+		require.Equal(codes.NotFound, status.Code(err))
+		list, err = s.WebhookList(nil)
+		require.NoError(err)
+		require.Len(list, 1)
+	})
+
+	t.Run("Update requires an existing webhook", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+
+		err := s.WebhookPut(true, &pb.Webhook{Id: "a", Url: "https://example.com"})
+		require.Error(err)
+		require.Equal(codes.NotFound, status.Code(err))
+	})
+}
@@ -28,6 +28,27 @@ func (s ArtifactStartDesc) Less(i, j int) bool {
 	return t2.Before(t1)
 }
 
+// ArtifactCompleteDesc sorts artifacts by complete time descending (most recent first).
+// For the opposite, use sort.Reverse.
+type ArtifactCompleteDesc []*pb.PushedArtifact
+
+func (s ArtifactCompleteDesc) Len() int      { return len(s) }
+func (s ArtifactCompleteDesc) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s ArtifactCompleteDesc) Less(i, j int) bool {
+	t1, err := ptypes.Timestamp(s[i].Status.CompleteTime)
+	if err != nil {
+		return false
+	}
+
+	t2, err := ptypes.Timestamp(s[j].Status.CompleteTime)
+	if err != nil {
+		return false
+	}
+
+	return t2.Before(t1)
+}
+
 var (
 	_ sort.Interface = (ArtifactStartDesc)(nil)
+	_ sort.Interface = (ArtifactCompleteDesc)(nil)
 )
@@ -28,6 +28,27 @@ func (s BuildStartDesc) Less(i, j int) bool {
 	return t2.Before(t1)
 }
 
+// BuildCompleteDesc sorts builds by complete time descending (most recent first).
+// For the opposite, use sort.Reverse.
+type BuildCompleteDesc []*pb.Build
+
+func (s BuildCompleteDesc) Len() int      { return len(s) }
+func (s BuildCompleteDesc) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s BuildCompleteDesc) Less(i, j int) bool {
+	t1, err := ptypes.Timestamp(s[i].Status.CompleteTime)
+	if err != nil {
+		return false
+	}
+
+	t2, err := ptypes.Timestamp(s[j].Status.CompleteTime)
+	if err != nil {
+		return false
+	}
+
+	return t2.Before(t1)
+}
+
 var (
 	_ sort.Interface = (BuildStartDesc)(nil)
+	_ sort.Interface = (BuildCompleteDesc)(nil)
 )
@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"fmt"
 	"os"
+	"strconv"
 	"time"
 
 	"google.golang.org/grpc"
@@ -53,11 +54,20 @@ func Connect(ctx context.Context, opts ...ConnectOption) (*grpc.ClientConn, erro
 		grpc.WithStreamInterceptor(protocolversion.StreamClientInterceptor(protocolversion.Current())),
 	}
 
+	if len(cfg.UnaryInterceptors) > 0 {
+		grpcOpts = append(grpcOpts, grpc.WithChainUnaryInterceptor(cfg.UnaryInterceptors...))
+	}
+
 	if !cfg.Tls {
 		grpcOpts = append(grpcOpts, grpc.WithInsecure())
-	} else if cfg.TlsSkipVerify {
+	} else if cfg.TlsSkipVerify || cfg.ClientCert != nil {
+		tlsCfg := &tls.Config{InsecureSkipVerify: cfg.TlsSkipVerify}
+		if cfg.ClientCert != nil {
+			tlsCfg.Certificates = []tls.Certificate{*cfg.ClientCert}
+		}
+
 		grpcOpts = append(grpcOpts, grpc.WithTransportCredentials(
-			credentials.NewTLS(&tls.Config{InsecureSkipVerify: true}),
+			credentials.NewTLS(tlsCfg),
 		))
 	}
 
@@ -97,6 +107,9 @@ func ContextConfig(opts ...ConnectOption) (*clicontext.Config, error) {
 			TlsSkipVerify: cfg.TlsSkipVerify,
 			RequireAuth:   cfg.Token != "",
 			AuthToken:     cfg.Token,
+
+			TlsClientCertFile: cfg.ClientCertFile,
+			TlsClientKeyFile:  cfg.ClientKeyFile,
 		},
 	}, nil
 }
@@ -109,23 +122,79 @@ type connectConfig struct {
 	Token         string
 	Optional      bool // See Optional func
 	Timeout       time.Duration
+
+	// ClientCert is presented to the server for mutual TLS if set. The
+	// file paths are kept so that they can be stored in a context.
+	ClientCert     *tls.Certificate
+	ClientCertFile string
+	ClientKeyFile  string
+
+	// UnaryInterceptors are called for every unary request after the
+	// protocol version interceptor.
+	UnaryInterceptors []grpc.UnaryClientInterceptor
 }
 
 // FromEnv sources the connection information from the environment
-// using standard environment variables.
+// using standard environment variables. An exported context in
+// WAYPOINT_CONTEXT_CONFIG is loaded first and the individual
+// WAYPOINT_SERVER_* variables override it.
 func FromEnv() ConnectOption {
 	return func(c *connectConfig) error {
+		if v := os.Getenv(EnvContextConfig); v != "" {
+			cfg, err := clicontext.Import([]byte(v))
+			if err != nil {
+				return fmt.Errorf("error loading %s: %s", EnvContextConfig, err)
+			}
+
+			opt := FromContextConfig(cfg)
+			if err := opt(c); err != nil {
+				return err
+			}
+		}
+
 		if v := os.Getenv(EnvServerAddr); v != "" {
+			tls, err := envBool(EnvServerTls)
+			if err != nil {
+				return err
+			}
+
+			tlsSkipVerify, err := envBool(EnvServerTlsSkipVerify)
+			if err != nil {
+				return err
+			}
+
 			c.Addr = v
-			c.Tls = os.Getenv(EnvServerTls) != ""
-			c.TlsSkipVerify = os.Getenv(EnvServerTlsSkipVerify) != ""
-			c.Auth = os.Getenv(EnvServerToken) != ""
+			c.Tls = tls
+			c.TlsSkipVerify = tlsSkipVerify
+			c.Token = os.Getenv(EnvServerToken)
+			c.Auth = c.Token != ""
 		}
 
 		return nil
 	}
 }
 
+// EnvSet returns true if the connection information is set in the
+// environment. In this case, no stored context is required to connect.
+func EnvSet() bool {
+	return os.Getenv(EnvServerAddr) != "" || os.Getenv(EnvContextConfig) != ""
+}
+
+// envBool parses the boolean environment variable k. Unset is false.
+func envBool(k string) (bool, error) {
+	v := os.Getenv(k)
+	if v == "" {
+		return false, nil
+	}
+
+	result, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("%s must be a boolean such as 1 or 0, got %q", k, v)
+	}
+
+	return result, nil
+}
+
 // FromContextConfig loads a specific context config.
 func FromContextConfig(cfg *clicontext.Config) ConnectOption {
 	return func(c *connectConfig) error {
@@ -137,8 +206,40 @@ func FromContextConfig(cfg *clicontext.Config) ConnectOption {
 				c.Auth = true
 				c.Token = cfg.Server.AuthToken
 			}
+
+			if cfg.Server.TlsClientCertFile != "" || cfg.Server.TlsClientKeyFile != "" {
+				opt := WithClientCertificate(
+					cfg.Server.TlsClientCertFile, cfg.Server.TlsClientKeyFile)
+				if err := opt(c); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+}
+
+// WithClientCertificate configures the connection to present the given
+// certificate to the server for mutual TLS. Both the certificate and key
+// must be specified, and the key must match the certificate.
+func WithClientCertificate(certFile, keyFile string) ConnectOption {
+	return func(c *connectConfig) error {
+		if certFile == "" || keyFile == "" {
+			return fmt.Errorf(
+				"both a client certificate and key must be specified for mutual TLS")
+		}
+
+		// LoadX509KeyPair verifies that the private key matches the
+		// public key in the certificate.
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return fmt.Errorf("error loading client certificate: %s", err)
 		}
 
+		c.ClientCert = &cert
+		c.ClientCertFile = certFile
+		c.ClientKeyFile = keyFile
 		return nil
 	}
 }
@@ -201,6 +302,15 @@ func Optional() ConnectOption {
 	}
 }
 
+// WithUnaryInterceptor adds an interceptor that is called for every unary
+// request on the connection.
+func WithUnaryInterceptor(i grpc.UnaryClientInterceptor) ConnectOption {
+	return func(c *connectConfig) error {
+		c.UnaryInterceptors = append(c.UnaryInterceptors, i)
+		return nil
+	}
+}
+
 // Timeout specifies a connection timeout. This defaults to 5 seconds.
 func Timeout(t time.Duration) ConnectOption {
 	return func(c *connectConfig) error {
@@ -225,6 +335,10 @@ const (
 
 	// EnvContext specifies a named context to load.
 	EnvContext = "WAYPOINT_CONTEXT"
+
+	// EnvContextConfig is a context exported with "waypoint context export".
+	// This configures the connection without any context stored on disk.
+	EnvContextConfig = "WAYPOINT_CONTEXT_CONFIG"
 )
 
 // This is a weird type that only exists to satisify the interface required by
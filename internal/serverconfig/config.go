@@ -6,25 +6,31 @@ import (
 
 // Client configures a client to connect to a server.
 type Client struct {
-	Address string `hcl:"address,attr"`
+	Address string `hcl:"address,attr" json:"address"`
 
 	// Tls, if true, will connect to the server with TLS. If TlsSkipVerify
 	// is true, the certificate presented by the server will not be validated.
-	Tls           bool `hcl:"tls,optional"`
-	TlsSkipVerify bool `hcl:"tls_skip_verify,optional"`
+	Tls           bool `hcl:"tls,optional" json:"tls,omitempty"`
+	TlsSkipVerify bool `hcl:"tls_skip_verify,optional" json:"tls_skip_verify,omitempty"`
+
+	// TlsClientCertFile and TlsClientKeyFile are paths to a certificate
+	// and matching private key to present to the server for mutual TLS.
+	// Both must be set if either is set.
+	TlsClientCertFile string `hcl:"tls_client_cert_file,optional" json:"tls_client_cert_file,omitempty"`
+	TlsClientKeyFile  string `hcl:"tls_client_key_file,optional" json:"tls_client_key_file,omitempty"`
 
 	// AddressInternal is a temporary config to work with local deployments
 	// on platforms such as Docker for Mac. We need to discuss a more
 	// long term approach to this.
-	AddressInternal string `hcl:"address_internal,optional"`
+	AddressInternal string `hcl:"address_internal,optional" json:"address_internal,omitempty"`
 
 	// Indicates that we need to present a token to connect to this server.
-	RequireAuth bool `hcl:"require_auth,optional"`
+	RequireAuth bool `hcl:"require_auth,optional" json:"require_auth,omitempty"`
 
 	// AuthToken is the token to use to authenticate to the server.
 	// Note this will be stored plaintext on disk. You can also use the
 	// WAYPOINT_SERVER_TOKEN env var.
-	AuthToken string `hcl:"auth_token,optional"`
+	AuthToken string `hcl:"auth_token,optional" json:"auth_token,omitempty"`
 }
 
 // Env returns a slice of environment variables in key=value settings
@@ -54,11 +60,220 @@ type Config struct {
 	// HTTP is the listening configuration for the HTTP service for grpc-web.
 	HTTP Listener `hcl:"http,block"`
 
+	// MetricsAddr is the address to serve Prometheus metrics on over
+	// plain HTTP. If this is empty, metrics are disabled.
+	MetricsAddr string `hcl:"metrics_address,optional"`
+
 	// URL configures a server to use a URL service.
 	URL *URL `hcl:"url,block"`
 
 	// CEBConfig configures the entrypoint binary for deployments
 	CEBConfig *CEBConfig `hcl:"entrypoint_config,block"`
+
+	// Snapshot configures automatic snapshots of the server data.
+	Snapshot *Snapshot `hcl:"snapshot,block"`
+
+	// GC configures automatic garbage collection of old builds, artifacts,
+	// deployments, and releases.
+	GC *GC `hcl:"gc,block"`
+
+	// Audit configures where audit events are sent in addition to being
+	// stored by the server.
+	Audit *Audit `hcl:"audit,block"`
+
+	// OIDC configures logging in with an OpenID Connect provider. If this
+	// is nil, OIDC login is disabled.
+	OIDC *OIDC `hcl:"oidc,block"`
+
+	// RateLimit limits the gRPC requests of each client. If this is nil,
+	// requests aren't limited.
+	RateLimit *RateLimit `hcl:"rate_limit,block"`
+
+	// ACME configures requesting the TLS certificate of the listeners
+	// from an ACME CA such as Let's Encrypt. Listeners with certificate
+	// files use those instead.
+	ACME *ACME `hcl:"acme,block"`
+
+	// Runtime is the configuration that can be changed while the server
+	// is running. If this is nil, the settings the server had before it
+	// was restarted are kept.
+	Runtime *Runtime `hcl:"runtime,block"`
+}
+
+// ACME is the configuration for requesting TLS certificates from an ACME
+// CA. Certificates are renewed automatically before they expire. ACME is
+// only used if Domains is set.
+type ACME struct {
+	// Domains are the domains to request certificates for. Connections
+	// for other names are rejected.
+	Domains []string `hcl:"domains,optional"`
+
+	// Email is the contact email of the ACME account.
+	Email string `hcl:"email,optional"`
+
+	// CacheDir is the directory to store certificates and the account
+	// key in so they're reused across restarts. This defaults to "acme"
+	// in the directory of the database.
+	CacheDir string `hcl:"cache_dir,optional"`
+
+	// DirectoryURL is the directory URL of the CA. This defaults to Let's
+	// Encrypt.
+	DirectoryURL string `hcl:"directory_url,optional"`
+
+	// HTTPAddr is the address to answer HTTP-01 challenges on, which
+	// must be reachable on port 80. If this is empty, TLS-ALPN-01
+	// challenges are answered by the listeners, so one must be reachable
+	// on port 443.
+	HTTPAddr string `hcl:"http_challenge_address,optional"`
+}
+
+// RateLimit is the configuration for limiting the gRPC requests of each
+// client. Requests over the limits fail with a ResourceExhausted error.
+// Zero values aren't limited.
+type RateLimit struct {
+	// TokenRequestsPerSecond and TokenBurst limit the requests of each
+	// token. The burst defaults to a second's worth of requests.
+	TokenRequestsPerSecond float64 `hcl:"token_requests_per_second,optional"`
+	TokenBurst             int     `hcl:"token_burst,optional"`
+
+	// IPRequestsPerSecond and IPBurst limit the requests of each IP
+	// address, whatever token they're sent with. The burst defaults to a
+	// second's worth of requests.
+	IPRequestsPerSecond float64 `hcl:"ip_requests_per_second,optional"`
+	IPBurst             int     `hcl:"ip_burst,optional"`
+
+	// MaxStreams is how many streams, such as log streams and runners,
+	// each token can have open at once. Requests without a token are
+	// limited by IP address instead.
+	MaxStreams int `hcl:"max_streams,optional"`
+}
+
+// OIDC is the configuration for logging in with an OpenID Connect
+// provider. Users that log in receive a token for the user in UserClaim
+// with the access of the first Access block that matches them.
+type OIDC struct {
+	// DiscoveryURL is the issuer URL of the provider. The provider
+	// configuration is discovered from DiscoveryURL
+	// + "/.well-known/openid-configuration".
+	DiscoveryURL string `hcl:"discovery_url,attr"`
+
+	// ClientID and ClientSecret are the credentials of the client
+	// registered with the provider for Waypoint.
+	ClientID     string `hcl:"client_id,attr"`
+	ClientSecret string `hcl:"client_secret,optional"`
+
+	// Scopes are the scopes to request in addition to "openid".
+	Scopes []string `hcl:"scopes,optional"`
+
+	// AllowedRedirectURIs are the URIs the provider may redirect to after
+	// login. These must also be registered with the provider.
+	AllowedRedirectURIs []string `hcl:"allowed_redirect_uris,attr"`
+
+	// UserClaim is the claim of the ID token to use as the user. This
+	// defaults to "sub".
+	UserClaim string `hcl:"user_claim,optional"`
+
+	// GroupsClaim is the claim of the ID token that lists the groups of the
+	// user, which are matched against the groups of the Access blocks.
+	GroupsClaim string `hcl:"groups_claim,optional"`
+
+	// TokenTTL is how long the tokens of users that log in are valid for,
+	// as a duration string such as "8h". This defaults to 8 hours.
+	TokenTTL string `hcl:"token_ttl,optional"`
+
+	// Access is the access to give users that log in. The first block
+	// that matches the user is used. Users that match no block can't
+	// log in.
+	Access []*OIDCAccess `hcl:"access,block"`
+}
+
+// OIDCAccess is the access given to users of an OIDC provider.
+type OIDCAccess struct {
+	// Groups are the groups this matches. A user matches if they're in
+	// any of these groups. If this is empty, every user matches.
+	Groups []string `hcl:"groups,optional"`
+
+	// Role is the role of the token, one of "admin", "operator", or
+	// "read-only".
+	Role string `hcl:"role,attr"`
+
+	// Projects and Workspaces, if set, limit the token to these projects
+	// and workspaces.
+	Projects   []string `hcl:"projects,optional"`
+	Workspaces []string `hcl:"workspaces,optional"`
+}
+
+// Audit is the configuration for sending audit events elsewhere. The
+// server always stores audit events itself.
+type Audit struct {
+	// File is the path of a file to append audit events to as JSON, one
+	// event per line.
+	File string `hcl:"file,optional"`
+
+	// Syslog, if true, sends audit events to the local syslog daemon.
+	// This isn't supported on Windows.
+	Syslog bool `hcl:"syslog,optional"`
+}
+
+// Snapshot is the configuration for automatic snapshots. Snapshots are
+// only taken if Interval is set.
+type Snapshot struct {
+	// Interval is how often to take a snapshot, as a duration string
+	// such as "6h".
+	Interval string `hcl:"interval,optional"`
+
+	// Retain is the number of snapshots to keep. Older snapshots are
+	// deleted after each new snapshot is taken. Zero keeps all snapshots.
+	Retain int `hcl:"retain,optional"`
+
+	// MaxAge is how long to keep snapshots, as a duration string such
+	// as "168h". Snapshots older than this are deleted after each new
+	// snapshot is taken, in addition to the Retain limit. Empty keeps
+	// snapshots regardless of age.
+	MaxAge string `hcl:"max_age,optional"`
+
+	// Path is the directory to write snapshots to.
+	Path string `hcl:"path,optional"`
+
+	// S3 configures writing snapshots to an S3 bucket instead of Path.
+	// Snapshots are only written to the bucket if its Bucket is set.
+	S3 *SnapshotS3 `hcl:"s3,block"`
+}
+
+// SnapshotS3 is the configuration for storing snapshots in an S3 bucket,
+// or in any object storage with an S3 compatible API such as GCS. The
+// server authenticates with the standard AWS credential chain, such as
+// the AWS_ACCESS_KEY_ID environment variable or an instance role.
+type SnapshotS3 struct {
+	// Bucket is the name of the bucket to write snapshots to.
+	Bucket string `hcl:"bucket,optional"`
+
+	// Prefix is prepended to the name of each snapshot to get its key,
+	// such as "waypoint/". Only snapshots under Prefix are listed and
+	// deleted, so other objects in the bucket are never touched.
+	Prefix string `hcl:"prefix,optional"`
+
+	// Region is the region of the bucket. If this is empty, the region is
+	// taken from the AWS configuration or looked up from the bucket.
+	Region string `hcl:"region,optional"`
+
+	// Endpoint is the URL of the object storage API, for storage other
+	// than AWS S3. For GCS, this is "https://storage.googleapis.com" with
+	// HMAC keys as the credentials.
+	Endpoint string `hcl:"endpoint,optional"`
+
+	// ForcePathStyle puts the bucket in the path of requests rather than
+	// the host name. Some S3 compatible storage, such as MinIO, needs this.
+	ForcePathStyle bool `hcl:"force_path_style,optional"`
+}
+
+// GC is the configuration for automatic garbage collection. What is
+// deleted is set by the retention policies stored by the server, so
+// nothing is deleted until a policy is set.
+type GC struct {
+	// Interval is how often to collect garbage, as a duration string
+	// such as "1h". Garbage is only collected on request if this is empty.
+	Interval string `hcl:"interval,optional"`
 }
 
 // CEBConfig is specific configuration for the entrypoint binaries
@@ -70,7 +285,7 @@ type CEBConfig struct {
 }
 
 type Listener struct {
-	Addr        string `hcl:"address,attr"`
+	Addr        string `hcl:"address,attr" json:"address"`
 	TLSDisable  bool   `hcl:"tls_disable,optional"`
 	TLSCertFile string `hcl:"tls_cert_file,optional"`
 	TLSKeyFile  string `hcl:"tls_key_file,optional"`
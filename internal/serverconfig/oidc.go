@@ -0,0 +1,29 @@
+package serverconfig
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2/hclsimple"
+)
+
+// LoadOIDC loads the OIDC configuration from the file at path. The file
+// has the attributes and blocks of OIDC at the top level and must have a
+// ".hcl" or ".json" extension.
+func LoadOIDC(path string) (*OIDC, error) {
+	var result OIDC
+	if err := hclsimple.DecodeFile(path, nil, &result); err != nil {
+		return nil, err
+	}
+
+	for i, a := range result.Access {
+		switch a.Role {
+		case "admin", "operator", "read-only":
+		default:
+			return nil, fmt.Errorf(
+				"access block %d: role must be one of \"admin\", \"operator\", or \"read-only\", got %q",
+				i+1, a.Role)
+		}
+	}
+
+	return &result, nil
+}
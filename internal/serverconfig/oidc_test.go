@@ -0,0 +1,48 @@
+package serverconfig
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadOIDC(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		require := require.New(t)
+
+		cfg, err := LoadOIDC(filepath.Join("testdata", "oidc.hcl"))
+		require.NoError(err)
+		require.Equal("https://idp.example.com", cfg.DiscoveryURL)
+		require.Equal("email", cfg.UserClaim)
+		require.Len(cfg.Access, 2)
+		require.Equal([]string{"platform"}, cfg.Access[0].Groups)
+		require.Equal("read-only", cfg.Access[1].Role)
+		require.Equal([]string{"marketing"}, cfg.Access[1].Projects)
+	})
+
+	t.Run("invalid role", func(t *testing.T) {
+		require := require.New(t)
+
+		td, err := ioutil.TempDir("", "waypoint-test")
+		require.NoError(err)
+		defer os.RemoveAll(td)
+
+		path := filepath.Join(td, "oidc.hcl")
+		require.NoError(ioutil.WriteFile(path, []byte(`
+discovery_url         = "https://idp.example.com"
+client_id             = "waypoint"
+allowed_redirect_uris = []
+
+access {
+  role = "owner"
+}
+`), 0600))
+
+		_, err = LoadOIDC(path)
+		require.Error(err)
+		require.Contains(err.Error(), "owner")
+	})
+}
@@ -0,0 +1,67 @@
+package serverconfig
+
+import (
+	"github.com/hashicorp/hcl/v2/hclsimple"
+)
+
+// Runtime is the configuration that can be changed while the server is
+// running. The server reads it from the file given with "-config" when it
+// starts and again when it receives SIGHUP. Settings that aren't set keep
+// their previous values. Durations are strings such as "30s".
+type Runtime struct {
+	// LogLevel is the level of the server logs: trace, debug, info, warn,
+	// or error.
+	LogLevel string `hcl:"log_level,optional"`
+
+	// LogLevels are the levels of the logs of server subsystems, such as
+	// "state" or "grpc", by subsystem. An empty level makes a subsystem
+	// use LogLevel.
+	LogLevels map[string]string `hcl:"log_levels,optional"`
+
+	// GCInterval is how often to collect garbage. "0" disables scheduled
+	// garbage collection. This overrides GC.Interval.
+	GCInterval string `hcl:"gc_interval,optional"`
+
+	// Webhook configures how webhook payloads are sent.
+	Webhook *RuntimeWebhook `hcl:"webhook,block"`
+
+	// Runner configures how long the server waits for runners.
+	Runner *RuntimeRunner `hcl:"runner,block"`
+}
+
+// RuntimeWebhook configures how webhook payloads are sent.
+type RuntimeWebhook struct {
+	// Attempts is how many times a payload is sent before giving up.
+	Attempts int `hcl:"attempts,optional"`
+
+	// Timeout is how long to wait for a webhook to respond.
+	Timeout string `hcl:"timeout,optional"`
+
+	// RetryWait is how long to wait after the first failed attempt. The
+	// wait grows with each attempt.
+	RetryWait string `hcl:"retry_wait,optional"`
+}
+
+// RuntimeRunner configures how long the server waits for runners.
+type RuntimeRunner struct {
+	// AckTimeout is how long a runner has to accept a job assigned to it
+	// before the job is queued again.
+	AckTimeout string `hcl:"ack_timeout,optional"`
+
+	// HeartbeatTimeout is how long a running job waits for a heartbeat
+	// from its runner before the job is failed.
+	HeartbeatTimeout string `hcl:"heartbeat_timeout,optional"`
+}
+
+// LoadRuntime loads the runtime configuration from the file at path. The
+// file has the attributes and blocks of Runtime at the top level and must
+// have a ".hcl" or ".json" extension. The values are validated when the
+// server applies them.
+func LoadRuntime(path string) (*Runtime, error) {
+	var result Runtime
+	if err := hclsimple.DecodeFile(path, nil, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,24 @@
+package serverconfig
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadRuntime(t *testing.T) {
+	require := require.New(t)
+
+	cfg, err := LoadRuntime(filepath.Join("testdata", "runtime.hcl"))
+	require.NoError(err)
+	require.Equal("debug", cfg.LogLevel)
+	require.Equal("30m", cfg.GCInterval)
+	require.Equal(map[string]string{"state": "trace"}, cfg.LogLevels)
+	require.NotNil(cfg.Webhook)
+	require.Equal(5, cfg.Webhook.Attempts)
+	require.Equal("", cfg.Webhook.Timeout)
+	require.Equal("10s", cfg.Webhook.RetryWait)
+	require.NotNil(cfg.Runner)
+	require.Equal("5m", cfg.Runner.HeartbeatTimeout)
+}
@@ -0,0 +1,17 @@
+discovery_url         = "https://idp.example.com"
+client_id             = "waypoint"
+client_secret         = "secret"
+scopes                = ["email", "groups"]
+allowed_redirect_uris = ["http://localhost:9711/oidc/callback"]
+user_claim            = "email"
+groups_claim          = "groups"
+
+access {
+  groups = ["platform"]
+  role   = "admin"
+}
+
+access {
+  role     = "read-only"
+  projects = ["marketing"]
+}
@@ -0,0 +1,15 @@
+log_level   = "debug"
+gc_interval = "30m"
+
+log_levels = {
+  state = "trace"
+}
+
+webhook {
+  attempts   = 5
+  retry_wait = "10s"
+}
+
+runner {
+  heartbeat_timeout = "5m"
+}
@@ -459,7 +459,16 @@ func newStatefulSet(c k8sConfig) (*appsv1.StatefulSet, error) {
 							LivenessProbe: &apiv1.Probe{
 								Handler: apiv1.Handler{
 									HTTPGet: &apiv1.HTTPGetAction{
-										Path:   "/",
+										Path:   "/healthz",
+										Port:   intstr.FromString("http"),
+										Scheme: "HTTPS",
+									},
+								},
+							},
+							ReadinessProbe: &apiv1.Probe{
+								Handler: apiv1.Handler{
+									HTTPGet: &apiv1.HTTPGetAction{
+										Path:   "/readyz",
 										Port:   intstr.FromString("http"),
 										Scheme: "HTTPS",
 									},
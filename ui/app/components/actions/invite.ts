@@ -5,7 +5,7 @@ import { later } from '@ember/runloop';
 
 import { inject as service } from '@ember/service';
 import ApiService from 'waypoint/services/api';
-import { InviteTokenRequest } from 'waypoint-pb';
+import { InviteTokenRequest, Token } from 'waypoint-pb';
 import SessionService from 'waypoint/services/session';
 
 export default class ActionsInvite extends Component {
@@ -38,6 +38,7 @@ export default class ActionsInvite extends Component {
   async createToken() {
     const req = new InviteTokenRequest();
     req.setDuration('12h');
+    req.setRole(Token.Role.ADMIN);
     const resp = await this.api.client.generateInviteToken(req, this.api.WithMeta());
     this.token = resp.getToken();
   }
@@ -0,0 +1,31 @@
+import Route from '@ember/routing/route';
+import { inject as service } from '@ember/service';
+import SessionService from 'waypoint/services/session';
+
+interface OIDCModel {
+  error: string | null;
+}
+
+export default class AuthOIDC extends Route {
+  @service session!: SessionService;
+
+  // The server redirects here after an OIDC login with the token or error
+  // in the URL fragment, so that it isn't sent to any server.
+  async model(): Promise<OIDCModel> {
+    let params = new URLSearchParams(window.location.hash.substring(1));
+    let token = params.get('token');
+    if (!token) {
+      return { error: params.get('error') || 'No token was returned by the server' };
+    }
+
+    await this.session.setToken(token);
+    return { error: null };
+  }
+
+  afterModel(model: OIDCModel) {
+    if (!model.error) {
+      // For now, we just support the default workspace
+      return this.transitionTo('workspace', 'default');
+    }
+  }
+}
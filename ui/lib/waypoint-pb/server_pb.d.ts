@@ -5065,6 +5065,13 @@ export namespace Token {
     }
   }
 
+
+  export enum Role { 
+    ROLE_UNSPECIFIED = 0,
+    ADMIN = 3,
+    OPERATOR = 1,
+    READ_ONLY = 2,
+  }
 }
 
 export class HMACKey extends jspb.Message {
@@ -5100,6 +5107,9 @@ export class InviteTokenRequest extends jspb.Message {
   hasEntrypoint(): boolean;
   clearEntrypoint(): InviteTokenRequest;
 
+  getRole(): Token.Role;
+  setRole(value: Token.Role): InviteTokenRequest;
+
   serializeBinary(): Uint8Array;
   toObject(includeInstance?: boolean): InviteTokenRequest.AsObject;
   static toObject(includeInstance: boolean, msg: InviteTokenRequest): InviteTokenRequest.AsObject;
@@ -5112,6 +5122,7 @@ export namespace InviteTokenRequest {
   export type AsObject = {
     duration: string,
     entrypoint?: Token.Entrypoint.AsObject,
+    role: Token.Role,
   }
 }
 
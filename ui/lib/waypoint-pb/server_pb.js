@@ -41176,6 +41176,17 @@ proto.hashicorp.waypoint.Token.serializeBinaryToWriter = function(message, write
 };
 
 
+/**
+ * @enum {number}
+ */
+proto.hashicorp.waypoint.Token.Role = {
+  ROLE_UNSPECIFIED: 0,
+  ADMIN: 3,
+  OPERATOR: 1,
+  READ_ONLY: 2
+};
+
+
 
 
 
@@ -41693,7 +41704,8 @@ proto.hashicorp.waypoint.InviteTokenRequest.prototype.toObject = function(opt_in
 proto.hashicorp.waypoint.InviteTokenRequest.toObject = function(includeInstance, msg) {
   var f, obj = {
     duration: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    entrypoint: (f = msg.getEntrypoint()) && proto.hashicorp.waypoint.Token.Entrypoint.toObject(includeInstance, f)
+    entrypoint: (f = msg.getEntrypoint()) && proto.hashicorp.waypoint.Token.Entrypoint.toObject(includeInstance, f),
+    role: jspb.Message.getFieldWithDefault(msg, 3, 0)
   };
 
   if (includeInstance) {
@@ -41739,6 +41751,10 @@ proto.hashicorp.waypoint.InviteTokenRequest.deserializeBinaryFromReader = functi
       reader.readMessage(value,proto.hashicorp.waypoint.Token.Entrypoint.deserializeBinaryFromReader);
       msg.setEntrypoint(value);
       break;
+    case 3:
+      var value = /** @type {!proto.hashicorp.waypoint.Token.Role} */ (reader.readEnum());
+      msg.setRole(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -41783,6 +41799,13 @@ proto.hashicorp.waypoint.InviteTokenRequest.serializeBinaryToWriter = function(m
       proto.hashicorp.waypoint.Token.Entrypoint.serializeBinaryToWriter
     );
   }
+  f = message.getRole();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      3,
+      f
+    );
+  }
 };
 
 
@@ -41841,6 +41864,24 @@ proto.hashicorp.waypoint.InviteTokenRequest.prototype.hasEntrypoint = function()
 };
 
 
+/**
+ * optional Token.Role role = 3;
+ * @return {!proto.hashicorp.waypoint.Token.Role}
+ */
+proto.hashicorp.waypoint.InviteTokenRequest.prototype.getRole = function() {
+  return /** @type {!proto.hashicorp.waypoint.Token.Role} */ (jspb.Message.getFieldWithDefault(this, 3, 0));
+};
+
+
+/**
+ * @param {!proto.hashicorp.waypoint.Token.Role} value
+ * @return {!proto.hashicorp.waypoint.InviteTokenRequest} returns this
+ */
+proto.hashicorp.waypoint.InviteTokenRequest.prototype.setRole = function(value) {
+  return jspb.Message.setProto3EnumField(this, 3, value);
+};
+
+
 
 
 
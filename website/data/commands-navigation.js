@@ -2,18 +2,26 @@ export default [
   'build',
   'deploy',
   'destroy',
+  'diff',
   'exec',
+  'fmt',
   'init',
   'install',
+  'login',
   'logs',
   'release',
+  'status',
+  'top',
   'ui',
   'up',
+  'validate',
   '---------',
   'artifact-build',
   'artifact-list-builds',
   'artifact-list',
   'artifact-push',
+  'audit',
+  'completion',
   'config-get',
   'config-set',
   'config-source-get',
@@ -22,6 +30,8 @@ export default [
   'context-clear',
   'context-create',
   'context-delete',
+  'context-export',
+  'context-import',
   'context-list',
   'context-rename',
   'context-use',
@@ -32,16 +42,38 @@ export default [
   'hostname-delete',
   'hostname-list',
   'hostname-register',
+  'org-delete',
+  'org-inspect',
+  'org-list',
+  'org-set',
   'plugin',
+  'plugin-scaffold',
+  'project-list',
+  'release-list',
   'runner-agent',
   'server-bootstrap',
   'server-config-set',
   'server-install',
+  'server-gc',
+  'server-gc-policy',
+  'server-log-level',
+  'server-prune',
   'server-restore',
   'server-run',
   'server-snapshot',
+  'server-snapshot-delete',
+  'server-snapshot-inspect',
+  'server-snapshot-list',
+  'server-snapshot-restore-state',
+  'server-snapshot-verify',
   'token-exchange',
   'token-invite',
+  'token-list',
   'token-new',
+  'token-revoke',
+  'token-rotate-key',
   'version',
+  'webhook-create',
+  'webhook-delete',
+  'webhook-list',
 ]
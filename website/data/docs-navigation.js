@@ -39,7 +39,7 @@ export default [
     content: [
       {
         category: 'variables',
-        content: ['artifact', 'deploy', 'entrypoint', 'path'],
+        content: ['artifact', 'build', 'deploy', 'entrypoint', 'path'],
       },
       {
         category: 'functions',
@@ -65,6 +65,7 @@ export default [
     category: 'server',
     content: [
       'auth',
+      'http-api',
       {
         category: 'run',
         content: ['maintenance', 'production', 'security'],
@@ -74,6 +75,7 @@ export default [
   'url',
   'logs',
   'exec',
+  'webhooks',
   {
     category: 'app-config',
     content: ['dynamic'],
@@ -1,11 +1,15 @@
 export default [
   'aws-ec2',
   'aws-ecs',
+  'aws-lambda',
+  'aws-secretsmanager',
   'aws-ssm',
   'azure-container-instance',
   'docker',
   'exec',
   'google-cloud-run',
+  'helm',
+  'kaniko',
   'kubernetes',
   'netlify',
   'nomad',